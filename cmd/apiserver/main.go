@@ -1,246 +1,81 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
-	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
-	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apiserver"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/componentconfig"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/logs"
 	"github.com/gin-gonic/gin"
 )
 
-const DefaultNamespace = "default"
-
-type APIServer struct {
-	store store.Store
-}
-
-func NewAPIServer(s store.Store) *APIServer {
-	return &APIServer{store: s}
-}
-
-func (s *APIServer) Serve(port string) {
-	router := gin.Default() // Use Gin router
-
-	// Pod routes
-	// /api/v1/namespaces/{namespace}/pods
-	podsGroup := router.Group("/api/v1/namespaces/:namespace/pods")
-	{
-		podsGroup.POST("", s.createPodHandlerGin)
-		podsGroup.GET("", s.listPodsHandlerGin)
-		podsGroup.GET("/:podname", s.getPodHandlerGin)
-		podsGroup.PUT("/:podname", s.updatePodHandlerGin) // Added route for updating a pod
-		podsGroup.DELETE("/:podname", s.deletePodHandlerGin)
-	}
-
-	// Node routes
-	// /api/v1/nodes
-	nodesGroup := router.Group("/api/v1/nodes")
-	{
-		nodesGroup.POST("", s.createNodeHandlerGin)
-		nodesGroup.GET("", s.listNodesHandlerGin)
-		nodesGroup.GET("/:nodename", s.getNodeHandlerGin)
-		nodesGroup.PUT("/:nodename", s.updateNodeHandlerGin) // Add PUT route for updating a node
-		// DELETE for a node could be added here: nodesGroup.DELETE("/:nodename", s.deleteNodeHandlerGin)
-	}
-
-	log.Printf("API Server starting on port %s using Gin", port)
-	// if err := http.ListenAndServe(":"+port, mux); err != nil { // Old http way
-	if err := router.Run(":" + port); err != nil { // Gin way
-		log.Fatalf("Failed to start Gin server: %v", err)
-	}
-}
-
-// Gin handler for creating a pod
-func (s *APIServer) createPodHandlerGin(c *gin.Context) {
-	namespace := c.Param("namespace")
-	var pod api.Pod
-	if err := c.ShouldBindJSON(&pod); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
-		return
-	}
-
-	if pod.Name == "" {
-		c.JSON(400, gin.H{"error": "Pod name must be provided"})
-		return
-	}
-	pod.Namespace = namespace // Ensure namespace from URL is used
-	if pod.Namespace == "" {
-		pod.Namespace = DefaultNamespace
-	}
-	pod.Phase = api.PodPending // Set initial phase
-	pod.NodeName = ""          // Not scheduled yet
-
-	if err := s.store.CreatePod(&pod); err != nil {
-		log.Printf("Error creating pod %s/%s in store: %v", pod.Namespace, pod.Name, err) // Log the actual error
-		if strings.Contains(err.Error(), "already exists") {
-			c.JSON(409, gin.H{"error": "Failed to create pod: " + err.Error()}) // 409 Conflict
-		} else {
-			c.JSON(500, gin.H{"error": "Failed to create pod: " + err.Error()}) // 500 for other errors
-		}
-		return
-	}
-	log.Printf("Created pod %s/%s", pod.Namespace, pod.Name)
-	c.JSON(201, pod)
-}
-
-// Gin handler for getting a specific pod
-func (s *APIServer) getPodHandlerGin(c *gin.Context) {
-	namespace := c.Param("namespace")
-	podName := c.Param("podname")
-	pod, err := s.store.GetPod(namespace, podName)
+func main() {
+	port := flag.String("port", "8080", "Port to serve the API on")
+	podCIDR := flag.String("pod-cidr", "10.244.0.0/16", "CIDR to assign pod IPs from (empty disables pod IP allocation)")
+	journalFile := flag.String("journal-file", "", "File to journal every write to and restore the cluster from on startup (empty keeps the cluster in memory only)")
+	kubeletToken := flag.String("kubelet-token", "", "Bearer token to send kubelets when proxying pod logs, exec, and node proxy requests to them")
+	slowRequestThreshold := flag.Duration("slow-request-threshold", apiserver.DefaultSlowRequestThreshold, "Log requests that take longer than this (0 disables)")
+	requestTimeout := flag.Duration("request-timeout", apiserver.DefaultRequestTimeout, "Fail requests other than watches and streams that take longer than this with 504 (0 disables)")
+	storeShards := flag.Int("store-shards", 1, "Number of shards, each with its own lock, to split each resource's objects into in the store (1 guards the whole store with one lock)")
+	maxRequestsInFlight := flag.Int("max-requests-inflight", apiserver.DefaultMaxRequestsInFlight, "Requests to run at once, shared between priority levels (node heartbeats, other requests, and lists); more wait their turn or get 429 (0 disables)")
+	corsAllowedOrigins := flag.String("cors-allowed-origins", "", "Comma-separated origins whose browser scripts may call the API, e.g. http://localhost:8081 (* allows any; empty disables CORS)")
+	eventTTL := flag.Duration("event-ttl", apiserver.DefaultEventTTL, "How long to keep an event after it last happened (0 keeps events forever)")
+	maxEventsPerNamespace := flag.Int("max-events-per-namespace", apiserver.DefaultMaxEventsPerNamespace, "Events to keep in each namespace; recording more deletes the least recently seen (0 is unlimited)")
+	requireNodeApproval := flag.Bool("require-node-approval", false, "Hold newly registered nodes NotReady and unschedulable until approved with kubectl-lite certificate approve")
+	var chaosConfig chaos.Config
+	chaosConfig.AddAPIServerFlags(flag.CommandLine)
+	logs.AddFlags(flag.CommandLine)
+	cfg := componentconfig.AddFlags(flag.CommandLine, "apiserver")
+	flag.Parse()
+	if err := cfg.Load(); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if err := chaosConfig.Validate(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if *eventTTL < 0 || *maxEventsPerNamespace < 0 {
+		log.Fatalf("-event-ttl and -max-events-per-namespace must not be negative")
+	}
+
+	podIPs, err := apiserver.NewPodIPAllocator(*podCIDR)
 	if err != nil {
-		c.JSON(404, gin.H{"error": "Pod not found: " + err.Error()})
-		return
+		log.Fatalf("Failed to set up pod IP allocation: %v", err)
 	}
-	c.JSON(200, pod)
-}
 
-// Gin handler for listing pods in a namespace
-func (s *APIServer) listPodsHandlerGin(c *gin.Context) {
-	namespace := c.Param("namespace")
-	pods, err := s.store.ListPods(namespace)
+	gin.SetMode(gin.ReleaseMode) // Or gin.DebugMode for development
+	dataStore, err := apiserver.OpenStore(*journalFile, *storeShards)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to list pods: " + err.Error()})
-		return
-	}
-	c.JSON(200, pods)
-}
-
-// Gin handler for deleting a specific pod
-func (s *APIServer) deletePodHandlerGin(c *gin.Context) {
-	namespace := c.Param("namespace")
-	podName := c.Param("podname")
-	if err := s.store.DeletePod(namespace, podName); err != nil {
-		log.Printf("Error deleting pod %s/%s from store: %v", namespace, podName, err) // Log the actual error
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(404, gin.H{"error": "Failed to delete pod: " + err.Error()}) // 404 Not Found
-		} else {
-			c.JSON(500, gin.H{"error": "Failed to delete pod: " + err.Error()}) // 500 for other errors
+		log.Fatalf("Failed to set up store: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := apiserver.NewAPIServer(dataStore, podIPs)
+	server.Chaos = chaos.New(chaosConfig)
+	server.SlowRequestThreshold = *slowRequestThreshold
+	server.RequestTimeout = *requestTimeout
+	server.MaxRequestsInFlight = *maxRequestsInFlight
+	server.KubeletToken = *kubeletToken
+	server.RequireNodeApproval = *requireNodeApproval
+	server.EventTTL, server.MaxEventsPerNamespace = *eventTTL, *maxEventsPerNamespace
+	for _, origin := range strings.Split(*corsAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			server.CORSAllowedOrigins = append(server.CORSAllowedOrigins, origin)
 		}
-		return
 	}
-	log.Printf("Deleted pod %s/%s", namespace, podName)
-	c.JSON(200, gin.H{"message": fmt.Sprintf("Pod %s/%s deleted", namespace, podName)})
-}
-
-// Gin handler for updating a specific pod
-func (s *APIServer) updatePodHandlerGin(c *gin.Context) {
-	namespace := c.Param("namespace")
-	podName := c.Param("podname")
-
-	var pod api.Pod
-	if err := c.ShouldBindJSON(&pod); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
-		return
+	go cfg.Watch(ctx, []string{"v", "slow-request-threshold"}, func() error {
+		server.SetSlowRequestThreshold(*slowRequestThreshold)
+		return nil
+	})
+	if err := server.Run(ctx, ":"+*port); err != nil {
+		log.Fatalf("API server failed: %v", err)
 	}
-
-	if pod.Name != podName {
-		c.JSON(400, gin.H{"error": fmt.Sprintf("Pod name in body (%s) does not match name in URL (%s)", pod.Name, podName)})
-		return
-	}
-	if pod.Namespace != namespace {
-		c.JSON(400, gin.H{"error": fmt.Sprintf("Pod namespace in body (%s) does not match namespace in URL (%s)", pod.Namespace, namespace)})
-		return
-	}
-
-	// Ensure the pod exists before updating (optional, store might handle this)
-	_, err := s.store.GetPod(namespace, podName)
-	if err != nil {
-		c.JSON(404, gin.H{"error": fmt.Sprintf("Pod %s/%s not found for update: %s", namespace, podName, err.Error())})
-		return
-	}
-
-	if err := s.store.UpdatePod(&pod); err != nil {
-		log.Printf("Failed to update pod in store: %v", err)
-		c.JSON(500, gin.H{"error": "Failed to update pod: " + err.Error()})
-		return
-	}
-
-	c.JSON(200, pod)
-}
-
-// Gin handler for creating a node
-func (s *APIServer) createNodeHandlerGin(c *gin.Context) {
-	var node api.Node
-	if err := c.ShouldBindJSON(&node); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
-		return
-	}
-
-	if node.Name == "" {
-		c.JSON(400, gin.H{"error": "Node name must be provided"})
-		return
-	}
-	if node.Status == "" {
-		node.Status = api.NodeReady // Default to Ready
-	}
-
-	if err := s.store.CreateNode(&node); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create node: " + err.Error()})
-		return
-	}
-	log.Printf("Registered node %s", node.Name)
-	c.JSON(201, node)
-}
-
-// Gin handler for getting a specific node
-func (s *APIServer) getNodeHandlerGin(c *gin.Context) {
-	nodeName := c.Param("nodename")
-	node, err := s.store.GetNode(nodeName)
-	if err != nil {
-		c.JSON(404, gin.H{"error": "Node not found: " + err.Error()})
-		return
-	}
-	c.JSON(200, node)
-}
-
-// Gin handler for listing all nodes
-func (s *APIServer) listNodesHandlerGin(c *gin.Context) {
-	nodes, err := s.store.ListNodes()
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to list nodes: " + err.Error()})
-		return
-	}
-	c.JSON(200, nodes)
-}
-
-// Gin handler for updating a specific node
-func (s *APIServer) updateNodeHandlerGin(c *gin.Context) {
-	nodeName := c.Param("nodename")
-	var updatedNode api.Node
-
-	if err := c.ShouldBindJSON(&updatedNode); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
-		return
-	}
-
-	// Ensure the name from the path is used and matches the body if provided.
-	if updatedNode.Name != "" && updatedNode.Name != nodeName {
-		c.JSON(400, gin.H{"error": fmt.Sprintf("Node name in body (%s) does not match path (%s)", updatedNode.Name, nodeName)})
-		return
-	}
-	updatedNode.Name = nodeName // Use name from path
-
-	// Check if node exists before updating - GetNode also serves this purpose
-	_, err := s.store.GetNode(nodeName)
-	if err != nil {
-		c.JSON(404, gin.H{"error": "Node not found for update: " + err.Error()}) // StatusNotFound
-		return
-	}
-
-	if err := s.store.UpdateNode(&updatedNode); err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update node: " + err.Error()})
-		return
-	}
-	log.Printf("Updated node %s", updatedNode.Name)
-	c.JSON(200, updatedNode)
-}
-
-func main() {
-	gin.SetMode(gin.ReleaseMode) // Or gin.DebugMode for development
-	dataStore := store.NewInMemoryStore()
-	server := NewAPIServer(dataStore)
-	server.Serve("8080") // Serve on port 8080
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/componentconfig"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controllermanager"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/logs"
+)
+
+func main() {
+	apiServerURL := flag.String("apiserver", "http://localhost:8080", "URL of the API server")
+	syncInterval := flag.Duration("sync-interval", 2*time.Second, "How often controllers poll the API server for changes")
+	workers := flag.Int("workers", 2, "Number of workers per controller")
+	leaderElect := flag.Bool("leader-elect", false, "Run each controller only while holding its lease, so that several controller managers can run with one active")
+	timeScale := flag.String("time-scale", "1x", "Run intervals and back-offs this many times faster than real time, e.g. 10x")
+	controllers := flag.String("controllers", "*", "Comma-separated controllers to run: * for all, NAME to add one, -NAME to leave one out (known: "+strings.Join(controllermanager.KnownControllers, ", ")+")")
+	var chaosConfig chaos.Config
+	chaosConfig.AddControllerFlags(flag.CommandLine)
+	logs.AddFlags(flag.CommandLine)
+	cfg := componentconfig.AddFlags(flag.CommandLine, "controller-manager")
+	flag.Parse()
+	if err := cfg.Load(); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if err := chaosConfig.Validate(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	enabled := strings.Split(*controllers, ",")
+	if err := controllermanager.ValidateControllers(enabled); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	scale, err := clock.ParseScale(*timeScale)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	log.Printf("Controller manager starting. Connecting to API server at %s", *apiServerURL)
+
+	client, err := api.NewClient(*apiServerURL, api.WithResponseCache())
+	if err != nil {
+		log.Fatalf("Failed to create API client: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go cfg.Watch(ctx, []string{"v"}, nil)
+	controllermanager.Run(ctx, client, controllermanager.Options{
+		SyncInterval: *syncInterval,
+		Workers:      *workers,
+		Clock:        clock.ForScale(scale),
+		Chaos:        chaos.New(chaosConfig),
+		LeaderElect:  *leaderElect,
+		Controllers:  enabled,
+	})
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+)
+
+//go:embed static
+var static embed.FS
+
+// coalesceDelay is how long a stream waits after a change before sending a snapshot,
+// so that the many changes of one relist go out together.
+const coalesceDelay = 100 * time.Millisecond
+
+// phaseChange is one step of a pod's timeline.
+type phaseChange struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+type podView struct {
+	Namespace string        `json:"namespace"`
+	Name      string        `json:"name"`
+	Image     string        `json:"image"`
+	Node      string        `json:"node,omitempty"`
+	IP        string        `json:"ip,omitempty"`
+	Status    string        `json:"status"`
+	Timeline  []phaseChange `json:"timeline"`
+}
+
+type nodeView struct {
+	Name          string `json:"name"`
+	Address       string `json:"address"`
+	Status        string `json:"status"`
+	Unschedulable bool   `json:"unschedulable,omitempty"`
+	Pods          int    `json:"pods"` // Pods bound to the node that aren't Deleted
+}
+
+// snapshot is what the UI renders: the whole cluster as last seen.
+type snapshot struct {
+	Nodes []nodeView `json:"nodes"`
+	Pods  []podView  `json:"pods"`
+}
+
+// dashboard keeps informers on the cluster's nodes and pods and streams snapshots to
+// the browser.
+type dashboard struct {
+	client api.Interface
+	pods   *controller.PollingInformer
+	nodes  *controller.PollingInformer
+
+	mu      sync.Mutex
+	changed chan struct{} // Closed and replaced whenever anything changes
+}
+
+func newDashboard(client api.Interface, interval time.Duration) *dashboard {
+	d := &dashboard{
+		client:  client,
+		pods:    controller.NewPodInformer(client, api.NamespaceAll, interval),
+		nodes:   controller.NewNodeInformer(client, interval),
+		changed: make(chan struct{}),
+	}
+	for _, informer := range []*controller.PollingInformer{d.pods, d.nodes} {
+		informer.AddEventHandler(controller.EventHandler{
+			OnAdd:    func(interface{}) { d.notify() },
+			OnUpdate: func(_, _ interface{}) { d.notify() },
+			OnDelete: func(interface{}) { d.notify() },
+		})
+	}
+	return d
+}
+
+// Run keeps the informers up to date until ctx is cancelled.
+func (d *dashboard) Run(ctx context.Context) {
+	go d.nodes.Run(ctx)
+	d.pods.Run(ctx)
+}
+
+// podStatus summarizes a pod for the UI: Terminating while it is being deleted and
+// its phase otherwise.
+func podStatus(pod *api.Pod) string {
+	if api.IsPodTerminating(pod) {
+		return "Terminating"
+	}
+	return string(pod.Phase)
+}
+
+// podTimeline returns each phase the pod went through, as the API server recorded
+// it, ending Terminating while the pod is being deleted.
+func podTimeline(pod *api.Pod) []phaseChange {
+	timeline := make([]phaseChange, 0, len(pod.PhaseTransitions)+1)
+	for _, t := range pod.PhaseTransitions {
+		timeline = append(timeline, phaseChange{Status: string(t.Phase), Time: t.Time})
+	}
+	if api.IsPodTerminating(pod) {
+		timeline = append(timeline, phaseChange{Status: "Terminating", Time: *pod.DeletionTimestamp})
+	}
+	return timeline
+}
+
+func (d *dashboard) notify() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
+
+// snapshot returns the current state of the cluster and a channel that is closed the
+// next time it changes.
+func (d *dashboard) snapshot() (snapshot, <-chan struct{}) {
+	// Take the channel first so that a change made while the snapshot is assembled
+	// still wakes the caller.
+	d.mu.Lock()
+	changed := d.changed
+	d.mu.Unlock()
+
+	var s snapshot
+	podsOnNode := make(map[string]int)
+	for _, obj := range d.pods.List() {
+		pod := obj.(*api.Pod)
+		if pod.NodeName != "" && pod.Phase != api.PodDeleted {
+			podsOnNode[pod.NodeName]++
+		}
+		s.Pods = append(s.Pods, podView{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Image:     pod.Image,
+			Node:      pod.NodeName,
+			IP:        pod.PodIP,
+			Status:    podStatus(pod),
+			Timeline:  podTimeline(pod),
+		})
+	}
+
+	for _, obj := range d.nodes.List() {
+		node := obj.(*api.Node)
+		s.Nodes = append(s.Nodes, nodeView{
+			Name:          node.Name,
+			Address:       node.Address,
+			Status:        string(node.Status),
+			Unschedulable: node.Unschedulable,
+			Pods:          podsOnNode[node.Name],
+		})
+	}
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Name < s.Nodes[j].Name })
+	sort.Slice(s.Pods, func(i, j int) bool {
+		if s.Pods[i].Namespace != s.Pods[j].Namespace {
+			return s.Pods[i].Namespace < s.Pods[j].Namespace
+		}
+		return s.Pods[i].Name < s.Pods[j].Name
+	})
+	return s, changed
+}
+
+// Handler returns the dashboard's HTTP handler: the UI at /, a stream of snapshots at
+// /api/stream, and pod creation and deletion under /api/pods.
+func (d *dashboard) Handler() http.Handler {
+	mux := http.NewServeMux()
+	ui, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // The directory is embedded at build time
+	}
+	mux.Handle("GET /", http.FileServerFS(ui))
+	mux.HandleFunc("GET /api/snapshot", d.getSnapshot)
+	mux.HandleFunc("GET /api/stream", d.stream)
+	mux.HandleFunc("POST /api/pods", d.createPod)
+	mux.HandleFunc("DELETE /api/pods/{namespace}/{name}", d.deletePod)
+	return mux
+}
+
+func (d *dashboard) getSnapshot(w http.ResponseWriter, r *http.Request) {
+	s, _ := d.snapshot()
+	writeJSON(w, http.StatusOK, s)
+}
+
+// stream sends a snapshot as a server-sent event now and after every change, until
+// the client goes away.
+func (d *dashboard) stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming not supported"})
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	for {
+		s, changed := d.snapshot()
+		data, err := json.Marshal(s)
+		if err != nil {
+			log.Printf("Error encoding snapshot: %v", err)
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(coalesceDelay):
+		}
+	}
+}
+
+type createPodRequest struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Image     string `json:"image"`
+}
+
+func (d *dashboard) createPod(w http.ResponseWriter, r *http.Request) {
+	var req createPodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
+		return
+	}
+	if req.Name == "" || req.Image == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and image are required"})
+		return
+	}
+	pod, err := d.client.CreatePod(req.Namespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: req.Name}, Image: req.Image})
+	if err != nil {
+		writeJSON(w, statusFor(err), map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, pod)
+}
+
+func (d *dashboard) deletePod(w http.ResponseWriter, r *http.Request) {
+	if err := d.client.DeletePod(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeJSON(w, statusFor(err), map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// statusFor picks the status to answer with when a request to the API server failed.
+func statusFor(err error) int {
+	var statusErr *api.StatusError
+	switch {
+	case api.IsNotFound(err):
+		return http.StatusNotFound
+	case api.IsAlreadyExists(err):
+		return http.StatusConflict
+	case errors.As(err, &statusErr) && statusErr.Code == http.StatusUnprocessableEntity:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
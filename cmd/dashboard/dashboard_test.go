@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+// waitForSnapshot polls the dashboard until cond holds for its snapshot.
+func waitForSnapshot(t *testing.T, d *dashboard, cond func(snapshot) bool) snapshot {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		s, _ := d.snapshot()
+		if cond(s) {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the snapshot, last: %+v", s)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDashboardTracksPodTimeline(t *testing.T) {
+	client := fake.NewClient(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady})
+	d := newDashboard(client, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+
+	srv := httptest.NewServer(d.Handler())
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/api/pods", "application/json", strings.NewReader(`{"name":"web","image":"nginx:1.25"}`))
+	if err != nil {
+		t.Fatalf("creating pod: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 creating a pod, got %d", resp.StatusCode)
+	}
+	waitForSnapshot(t, d, func(s snapshot) bool { return len(s.Pods) == 1 })
+
+	pod, err := client.GetPod("default", "web")
+	if err != nil {
+		t.Fatalf("GetPod: %v", err)
+	}
+	pod.NodeName, pod.Phase = "node1", api.PodScheduled
+	client.UpdatePod(pod)
+	waitForSnapshot(t, d, func(s snapshot) bool { return s.Pods[0].Status == "Scheduled" })
+	pod.Phase = api.PodRunning
+	client.UpdatePod(pod)
+	s := waitForSnapshot(t, d, func(s snapshot) bool { return s.Pods[0].Status == "Running" })
+
+	var statuses []string
+	for _, change := range s.Pods[0].Timeline {
+		statuses = append(statuses, change.Status)
+	}
+	if got := strings.Join(statuses, ","); got != "Pending,Scheduled,Running" {
+		t.Errorf("expected the timeline Pending,Scheduled,Running, got %s", got)
+	}
+	if len(s.Nodes) != 1 || s.Nodes[0].Pods != 1 {
+		t.Errorf("expected node1 to count one pod, got %+v", s.Nodes)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/api/pods/default/web", nil)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204 deleting the pod, got %v, %v", resp, err)
+	}
+	req, _ = http.NewRequest(http.MethodDelete, srv.URL+"/api/pods/default/missing", nil)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 deleting a missing pod, got %v, %v", resp, err)
+	}
+}
+
+func TestDashboardStreamsSnapshots(t *testing.T) {
+	client := fake.NewClient(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady})
+	d := newDashboard(client, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+	waitForSnapshot(t, d, func(s snapshot) bool { return len(s.Nodes) == 1 })
+
+	srv := httptest.NewServer(d.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/api/stream")
+	if err != nil {
+		t.Fatalf("opening stream: %v", err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewScanner(resp.Body)
+
+	// next returns the data of the next snapshot event.
+	next := func() snapshot {
+		t.Helper()
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				var s snapshot
+				if err := json.Unmarshal([]byte(data), &s); err != nil {
+					t.Fatalf("decoding snapshot: %v", err)
+				}
+				return s
+			}
+		}
+		t.Fatalf("stream ended: %v", events.Err())
+		return snapshot{}
+	}
+	if s := next(); len(s.Nodes) != 1 || len(s.Pods) != 0 {
+		t.Fatalf("expected a first snapshot with one node and no pods, got %+v", s)
+	}
+	if _, err := client.CreatePod("default", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx:1.25"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if s := next(); len(s.Pods) != 1 || s.Pods[0].Name != "web" {
+		t.Errorf("expected the next snapshot to show the new pod, got %+v", s)
+	}
+}
@@ -0,0 +1,46 @@
+// Command dashboard serves a web UI showing a k8s-lite-go cluster's nodes and pods as
+// they change, with each pod's phase timeline, and lets you create and delete pods.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func main() {
+	apiServerURL := flag.String("apiserver", "http://localhost:8080", "URL of the API server")
+	port := flag.String("port", "8081", "Port to serve the dashboard on")
+	pollInterval := flag.Duration("poll-interval", 500*time.Millisecond, "How long to wait before listing nodes and pods again after a watch of them fails")
+	flag.Parse()
+
+	client, err := api.NewClient(*apiServerURL, api.WithResponseCache())
+	if err != nil {
+		log.Fatalf("Failed to create API client: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	d := newDashboard(client, *pollInterval)
+	go d.Run(ctx)
+
+	srv := &http.Server{Addr: ":" + *port, Handler: d.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	log.Printf("Dashboard for %s at http://localhost:%s", *apiServerURL, *port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Dashboard failed: %v", err)
+	}
+}
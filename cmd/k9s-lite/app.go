@@ -0,0 +1,492 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// eventNamespace is where events about nodes live.
+const eventNamespace = "default"
+
+type viewKind int
+
+const (
+	podsView viewKind = iota
+	nodesView
+	detailView
+)
+
+// detail is a scrollable page about one object: its description, or its events.
+type detail struct {
+	kind      string // "Pod" or "Node"
+	namespace string
+	name      string
+	events    bool // Only the object's events, instead of its full description
+	lines     []string
+	scroll    int
+}
+
+// pendingDelete is a deletion waiting for the user to confirm it.
+type pendingDelete struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// app is the state of the TUI. Key presses and refreshes change it, and render draws
+// it; neither touches the terminal, so both can be tested directly.
+type app struct {
+	client       api.Interface
+	namespace    string // api.NamespaceAll shows pods in every namespace
+	ownNamespace string // The namespace "0" switches back to from all of them
+
+	view    viewKind
+	list    viewKind // The list view a detail view returns to
+	pods    []api.Pod
+	nodes   []api.Node
+	cursor  int
+	detail  detail
+	confirm *pendingDelete
+	message string // Shown on the status line until the next key press
+
+	width, height int
+	now           func() time.Time
+}
+
+func newApp(client api.Interface, namespace string) *app {
+	own := namespace
+	if own == api.NamespaceAll {
+		own = "default"
+	}
+	return &app{client: client, namespace: namespace, ownNamespace: own, width: 80, height: 24, now: time.Now}
+}
+
+// refresh relists pods and nodes, and reloads the open detail page.
+func (a *app) refresh() {
+	pods, err := a.client.ListPods(a.namespace, "")
+	if err != nil {
+		a.message = "Error listing pods: " + err.Error()
+		return
+	}
+	nodes, err := a.client.ListNodes("")
+	if err != nil {
+		a.message = "Error listing nodes: " + err.Error()
+		return
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	a.pods, a.nodes = pods, nodes
+	a.cursor = clamp(a.cursor, 0, a.rows()-1)
+	if a.view == detailView {
+		a.loadDetail()
+	}
+}
+
+// rows returns the number of rows in the current list view.
+func (a *app) rows() int {
+	if a.list == nodesView {
+		return len(a.nodes)
+	}
+	return len(a.pods)
+}
+
+// handleKey acts on a key press and reports whether the user asked to quit.
+func (a *app) handleKey(k key) (quit bool) {
+	if k == keyCtrlC {
+		return true
+	}
+	a.message = ""
+	if a.confirm != nil {
+		pending := a.confirm
+		a.confirm = nil
+		if k == "y" || k == "Y" {
+			a.delete(pending)
+			a.refresh()
+		}
+		return false
+	}
+
+	if a.view == detailView {
+		switch k {
+		case keyEsc, "q":
+			a.view = a.list
+		case keyUp, "k":
+			a.detail.scroll--
+		case keyDown, "j":
+			a.detail.scroll++
+		case keyPageUp:
+			a.detail.scroll -= a.pageSize()
+		case keyPageDown:
+			a.detail.scroll += a.pageSize()
+		case keyHome, "g":
+			a.detail.scroll = 0
+		case keyEnd, "G":
+			a.detail.scroll = len(a.detail.lines)
+		}
+		a.detail.scroll = clamp(a.detail.scroll, 0, len(a.detail.lines)-a.pageSize())
+		return false
+	}
+
+	switch k {
+	case "q":
+		return true
+	case keyUp, "k":
+		a.cursor--
+	case keyDown, "j":
+		a.cursor++
+	case keyPageUp:
+		a.cursor -= a.pageSize()
+	case keyPageDown:
+		a.cursor += a.pageSize()
+	case keyHome, "g":
+		a.cursor = 0
+	case keyEnd, "G":
+		a.cursor = a.rows() - 1
+	case "p":
+		a.view, a.list, a.cursor = podsView, podsView, 0
+	case "n":
+		a.view, a.list, a.cursor = nodesView, nodesView, 0
+	case "0":
+		if a.namespace == api.NamespaceAll {
+			a.namespace = a.ownNamespace
+		} else {
+			a.namespace = api.NamespaceAll
+		}
+		a.cursor = 0
+		a.refresh()
+	case "r":
+		a.refresh()
+	case "d", keyEnter:
+		a.openDetail(false)
+	case "l", "e":
+		a.openDetail(true)
+	case "x", keyCtrlD:
+		if ns, name, kind, ok := a.selected(); ok {
+			a.confirm = &pendingDelete{kind: kind, namespace: ns, name: name}
+		}
+	}
+	a.cursor = clamp(a.cursor, 0, a.rows()-1)
+	return false
+}
+
+// selected returns the object under the cursor, if the list isn't empty.
+func (a *app) selected() (namespace, name, kind string, ok bool) {
+	switch {
+	case a.list == nodesView && a.cursor < len(a.nodes):
+		return "", a.nodes[a.cursor].Name, "Node", true
+	case a.list == podsView && a.cursor < len(a.pods):
+		pod := a.pods[a.cursor]
+		return pod.Namespace, pod.Name, "Pod", true
+	}
+	return "", "", "", false
+}
+
+func (a *app) openDetail(events bool) {
+	namespace, name, kind, ok := a.selected()
+	if !ok {
+		return
+	}
+	a.detail = detail{kind: kind, namespace: namespace, name: name, events: events}
+	a.view = detailView
+	a.loadDetail()
+}
+
+func (a *app) delete(p *pendingDelete) {
+	var err error
+	if p.kind == "Node" {
+		err = a.client.DeleteNode(p.name)
+	} else {
+		err = a.client.DeletePod(p.namespace, p.name)
+	}
+	if err != nil {
+		a.message = fmt.Sprintf("Error deleting %s %s: %v", strings.ToLower(p.kind), p.name, err)
+		return
+	}
+	a.message = fmt.Sprintf("Deleted %s %s", strings.ToLower(p.kind), p.name)
+}
+
+// loadDetail fetches the object shown by the detail page and its events.
+func (a *app) loadDetail() {
+	d := &a.detail
+	var fields [][2]string
+	switch d.kind {
+	case "Pod":
+		pod, err := a.client.GetPod(d.namespace, d.name)
+		if err != nil {
+			d.lines = []string{"Error: " + err.Error()}
+			return
+		}
+		fields = [][2]string{
+			{"Name", pod.Name},
+			{"Namespace", pod.Namespace},
+			{"Labels", formatMap(pod.Labels)},
+			{"Image", pod.Image},
+			{"Node", orNone(pod.NodeName)},
+			{"Status", podStatus(pod)},
+			{"Conditions", formatConditions(pod.Conditions)},
+			{"Pod IP", orNone(pod.PodIP)},
+			{"Created", formatTime(pod.CreationTimestamp, a.now())},
+		}
+	case "Node":
+		node, err := a.client.GetNode(d.name)
+		if err != nil {
+			d.lines = []string{"Error: " + err.Error()}
+			return
+		}
+		var pods []string
+		for _, pod := range a.pods {
+			if pod.NodeName == node.Name && pod.Phase != api.PodDeleted {
+				pods = append(pods, pod.Namespace+"/"+pod.Name)
+			}
+		}
+		fields = [][2]string{
+			{"Name", node.Name},
+			{"Address", node.Address},
+			{"Status", string(node.Status)},
+			{"Unschedulable", fmt.Sprintf("%t", node.Unschedulable)},
+			{"Pods", orNone(strings.Join(pods, ", "))},
+			{"Created", formatTime(node.CreationTimestamp, a.now())},
+		}
+	}
+
+	var lines []string
+	if !d.events {
+		for _, f := range fields {
+			lines = append(lines, fmt.Sprintf("%-14s %s", f[0]+":", f[1]))
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, a.eventLines()...)
+	d.lines = lines
+}
+
+// eventLines lists the events about the detail page's object, oldest first.
+func (a *app) eventLines() []string {
+	d := a.detail
+	namespace := d.namespace
+	if d.kind == "Node" {
+		namespace = eventNamespace
+	}
+	events, err := a.client.ListEvents(namespace)
+	if err != nil {
+		return []string{"Events: error: " + err.Error()}
+	}
+	var matching []api.Event
+	for _, e := range events {
+		if e.InvolvedObject.Kind == d.kind && e.InvolvedObject.Name == d.name {
+			matching = append(matching, e)
+		}
+	}
+	if len(matching) == 0 {
+		return []string{"Events: <none>"}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].LastTimestamp.Before(matching[j].LastTimestamp) })
+	rows := [][]string{{"LAST SEEN", "TYPE", "REASON", "FROM", "MESSAGE"}}
+	for _, e := range matching {
+		rows = append(rows, []string{age(e.LastTimestamp, a.now()), string(e.Type), e.Reason, e.Source.Component, e.Message})
+	}
+	return append([]string{"Events:"}, table(rows)...)
+}
+
+// pageSize is how many rows of content fit below the header lines.
+func (a *app) pageSize() int {
+	return max(a.height-4, 1)
+}
+
+// render draws the screen as exactly a.height lines of at most a.width characters.
+func (a *app) render() []string {
+	title := "Pods"
+	scope := "ns: " + a.namespace
+	if a.namespace == api.NamespaceAll {
+		scope = "ns: all"
+	}
+	hints := "<d> describe  <l> events  <x> delete  <n> nodes  <0> all namespaces  <q> quit"
+	switch a.view {
+	case nodesView:
+		title, scope = "Nodes", ""
+		hints = "<d> describe  <l> events  <x> delete  <p> pods  <q> quit"
+	case detailView:
+		title = strings.ToLower(a.detail.kind) + " " + a.detail.name
+		if a.detail.namespace != "" {
+			title = strings.ToLower(a.detail.kind) + " " + a.detail.namespace + "/" + a.detail.name
+		}
+		if a.detail.events {
+			title = "Events of " + title + " (simulated pods have no logs)"
+		} else {
+			title = "Describe " + title
+		}
+		scope = ""
+		hints = "<up/down> scroll  <esc> back  <ctrl-c> quit"
+	}
+
+	lines := []string{
+		reverse(pad(fmt.Sprintf(" k9s-lite  %s  %s  %s", a.client.GetBaseURL(), title, scope), a.width)),
+		truncate(" "+hints, a.width),
+	}
+	body := a.pageSize() + 1 // Table header or blank line, plus a page of rows
+	if a.view == detailView {
+		visible := a.detail.lines[min(a.detail.scroll, len(a.detail.lines)):]
+		lines = append(lines, "")
+		for i := 0; i < body-1 && i < len(visible); i++ {
+			lines = append(lines, truncate(visible[i], a.width))
+		}
+	} else {
+		rows := a.tableRows()
+		lines = append(lines, truncate(rows[0], a.width))
+		// Scroll the table so that the cursor row is on screen.
+		first := max(0, a.cursor-(body-2))
+		for i := first; i < len(rows)-1 && i-first < body-1; i++ {
+			row := truncate(rows[i+1], a.width)
+			if i == a.cursor {
+				row = reverse(pad(row, a.width))
+			}
+			lines = append(lines, row)
+		}
+		if len(rows) == 1 {
+			lines = append(lines, " No resources found.")
+		}
+	}
+	for len(lines) < a.height-1 {
+		lines = append(lines, "")
+	}
+
+	status := a.message
+	if a.confirm != nil {
+		status = fmt.Sprintf("Delete %s %s? (y/n)", strings.ToLower(a.confirm.kind), a.confirm.name)
+	}
+	return append(lines[:a.height-1], truncate(" "+status, a.width))
+}
+
+// tableRows formats the current list as aligned rows, the first being the header.
+func (a *app) tableRows() []string {
+	now := a.now()
+	var rows [][]string
+	if a.list == nodesView {
+		podCounts := make(map[string]int)
+		for _, pod := range a.pods {
+			if pod.NodeName != "" && pod.Phase != api.PodDeleted {
+				podCounts[pod.NodeName]++
+			}
+		}
+		rows = append(rows, []string{"NAME", "STATUS", "ADDRESS", "PODS", "AGE"})
+		for _, node := range a.nodes {
+			status := string(node.Status)
+			if node.Unschedulable {
+				status += ",SchedulingDisabled"
+			}
+			rows = append(rows, []string{node.Name, status, node.Address, fmt.Sprint(podCounts[node.Name]), age(node.CreationTimestamp, now)})
+		}
+	} else {
+		rows = append(rows, []string{"NAMESPACE", "NAME", "STATUS", "NODE", "IP", "AGE"})
+		for _, pod := range a.pods {
+			rows = append(rows, []string{pod.Namespace, pod.Name, podStatus(&pod), orNone(pod.NodeName), orNone(pod.PodIP), age(pod.CreationTimestamp, now)})
+		}
+	}
+	return table(rows)
+}
+
+// table aligns rows into columns.
+func table(rows [][]string) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, " "+strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// podStatus is Terminating while a pod is being deleted and its phase otherwise.
+func podStatus(pod *api.Pod) string {
+	if api.IsPodTerminating(pod) {
+		return "Terminating"
+	}
+	return string(pod.Phase)
+}
+
+func formatConditions(conditions []api.PodCondition) string {
+	var parts []string
+	for _, cond := range conditions {
+		part := string(cond.Type) + "=" + string(cond.Status)
+		if cond.Reason != "" {
+			part += " (" + cond.Reason + ")"
+		}
+		parts = append(parts, part)
+	}
+	return orNone(strings.Join(parts, ", "))
+}
+
+func formatMap(m map[string]string) string {
+	parts := make([]string, 0, len(m))
+	for k, v := range m {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return orNone(strings.Join(parts, ","))
+}
+
+func formatTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return t.Format(time.RFC3339) + " (" + age(t, now) + " ago)"
+}
+
+// age formats how long ago t was the way kubectl does: 45s, 3m, 2h, 4d.
+func age(t, now time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	d := max(now.Sub(t), 0)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// truncate cuts s to at most width characters.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:max(width, 0)])
+}
+
+// pad fills s with spaces to width characters, so that highlighting spans the line.
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[0m"
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []key
+	}{
+		{"q", []key{"q"}},
+		{"\x1b[A\x1b[B", []key{keyUp, keyDown}},
+		{"\x1bOA", []key{keyUp}},
+		{"\x1b[5~j\r", []key{keyPageUp, "j", keyEnter}},
+		{"\x1b", []key{keyEsc}},
+		{"\x1b[C\x03", []key{keyCtrlC}}, // Right arrow is ignored
+		{"\x1b[", nil},                  // An incomplete sequence waits for more input
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func newTestApp() (*app, *fake.Client) {
+	created := time.Now() // The fake client stamps objects with the current time
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1", CreationTimestamp: created}, Status: api.NodeReady},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "api", Namespace: "default", CreationTimestamp: created}, Image: "api:1", NodeName: "node1", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", CreationTimestamp: created}, Image: "nginx:1.25", Phase: api.PodPending},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "team-a", CreationTimestamp: created}, Image: "postgres:16", Phase: api.PodPending},
+	)
+	a := newApp(client, "default")
+	a.now = func() time.Time { return created.Add(90 * time.Second) }
+	a.refresh()
+	return a, client
+}
+
+// screen joins the rendered lines, without highlighting, for matching.
+func screen(a *app) string {
+	return strings.NewReplacer("\x1b[7m", "", "\x1b[0m", "").Replace(strings.Join(a.render(), "\n"))
+}
+
+func TestAppListsAndNavigates(t *testing.T) {
+	a, _ := newTestApp()
+	lines := a.render()
+	if len(lines) != a.height {
+		t.Fatalf("expected %d lines, got %d", a.height, len(lines))
+	}
+	if !strings.Contains(lines[3], "\x1b[7m") || !strings.Contains(lines[3], "api") {
+		t.Errorf("expected the first pod to be highlighted, got %q", lines[3])
+	}
+	if s := screen(a); !strings.Contains(s, "web") || strings.Contains(s, "db ") || !strings.Contains(s, "1m") {
+		t.Errorf("expected the default namespace's pods with their age, got:\n%s", s)
+	}
+
+	a.handleKey(keyDown)
+	a.handleKey(keyDown) // Stays on the last row
+	if _, name, _, _ := a.selected(); name != "web" {
+		t.Errorf("expected the cursor on web, got %s", name)
+	}
+	a.handleKey("0")
+	if len(a.pods) != 3 || !strings.Contains(screen(a), "team-a") {
+		t.Errorf("expected 0 to show every namespace, got %d pods", len(a.pods))
+	}
+
+	a.handleKey("n")
+	if s := screen(a); !strings.Contains(s, "node1") || !strings.Contains(s, "Ready") {
+		t.Errorf("expected the node list, got:\n%s", s)
+	}
+	if a.handleKey("q") != true {
+		t.Error("expected q to quit from a list")
+	}
+}
+
+func TestAppDescribesAndDeletes(t *testing.T) {
+	a, client := newTestApp()
+	client.CreateEvent("default", &api.Event{
+		ObjectMeta:     api.ObjectMeta{Name: "api.1"},
+		InvolvedObject: api.ObjectReference{Kind: "Pod", Namespace: "default", Name: "api"},
+		Type:           api.EventTypeNormal, Reason: "Scheduled", Message: "Assigned default/api to node1",
+		Source: api.EventSource{Component: "scheduler"}, LastTimestamp: a.now(),
+	})
+
+	a.handleKey("d")
+	s := screen(a)
+	for _, want := range []string{"Describe pod default/api", "Image:", "api:1", "Scheduled", "Assigned default/api to node1"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected the description to contain %q, got:\n%s", want, s)
+		}
+	}
+	if a.handleKey("q") || a.view != podsView {
+		t.Fatal("expected q to go back to the pod list from a description")
+	}
+
+	a.handleKey("x")
+	if !strings.Contains(screen(a), "Delete pod api? (y/n)") {
+		t.Fatalf("expected a confirmation prompt, got:\n%s", screen(a))
+	}
+	a.handleKey("n")
+	if pod, err := client.GetPod("default", "api"); err != nil || pod.DeletionTimestamp != nil {
+		t.Fatalf("expected n to cancel the deletion, got %+v, %v", pod, err)
+	}
+	a.handleKey("x")
+	a.handleKey("y")
+	if pod, err := client.GetPod("default", "api"); err == nil && pod.DeletionTimestamp == nil {
+		t.Errorf("expected y to delete the pod, got %+v", pod)
+	}
+	if !strings.Contains(screen(a), "Deleted pod api") {
+		t.Errorf("expected a confirmation message, got:\n%s", screen(a))
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+// key is a key press: the character itself for printable keys, or a name such as
+// "up" or "ctrl-c".
+type key string
+
+const (
+	keyUp       key = "up"
+	keyDown     key = "down"
+	keyPageUp   key = "pgup"
+	keyPageDown key = "pgdn"
+	keyHome     key = "home"
+	keyEnd      key = "end"
+	keyEnter    key = "enter"
+	keyEsc      key = "esc"
+	keyCtrlC    key = "ctrl-c"
+	keyCtrlD    key = "ctrl-d"
+)
+
+// escapeSequences maps the input sequences of special keys, after the leading ESC,
+// to their keys. Terminals send either the CSI ("[") or SS3 ("O") form.
+var escapeSequences = map[string]key{
+	"[A": keyUp, "OA": keyUp,
+	"[B": keyDown, "OB": keyDown,
+	"[5~": keyPageUp, "[6~": keyPageDown,
+	"[H": keyHome, "OH": keyHome, "[1~": keyHome,
+	"[F": keyEnd, "OF": keyEnd, "[4~": keyEnd,
+}
+
+// parseKeys splits a read from the terminal into key presses. Escape sequences it
+// doesn't know, such as the left and right arrows, are dropped.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == 0x1b:
+			if i+1 == len(b) || (b[i+1] != '[' && b[i+1] != 'O') {
+				keys = append(keys, keyEsc)
+				continue
+			}
+			// A sequence runs to its first letter or "~" after the introducer.
+			end := i + 2
+			for end < len(b) && !isFinalByte(b[end]) {
+				end++
+			}
+			if end == len(b) {
+				return keys
+			}
+			if k, ok := escapeSequences[string(b[i+1:end+1])]; ok {
+				keys = append(keys, k)
+			}
+			i = end
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+		case c == 0x03:
+			keys = append(keys, keyCtrlC)
+		case c == 0x04:
+			keys = append(keys, keyCtrlD)
+		case c >= 0x20 && c < 0x7f:
+			keys = append(keys, key(c))
+		}
+	}
+	return keys
+}
+
+func isFinalByte(c byte) bool {
+	return c == '~' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
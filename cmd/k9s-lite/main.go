@@ -0,0 +1,132 @@
+// Command k9s-lite is a terminal UI for browsing a k8s-lite-go cluster: it lists pods
+// and nodes as they change and describes, shows the events of, or deletes the one
+// under the cursor.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubeconfig"
+)
+
+func main() {
+	apiServerURL := flag.String("apiserver", "", "URL of the API server (overrides the kubeconfig context; default http://localhost:8080)")
+	kubeconfigPath := flag.String("kubeconfig", kubeconfig.DefaultPath(), "Path to the kubeconfig-lite file")
+	contextName := flag.String("context", "", "Kubeconfig context to use (default the current context)")
+	namespace := flag.String("namespace", "default", "Namespace to show pods of; \"all\" shows every namespace")
+	refresh := flag.Duration("refresh", time.Second, "How often to relist pods and nodes")
+	flag.Parse()
+
+	client, err := newClient(*apiServerURL, *kubeconfigPath, *contextName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *namespace == "all" {
+		*namespace = api.NamespaceAll
+	}
+	if err := run(newApp(client, *namespace), *refresh); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newClient creates the API client the way kubectl-lite does: an explicit URL wins,
+// then the kubeconfig context, then the default local API server.
+func newClient(apiServerURL, kubeconfigPath, contextName string) (api.Interface, error) {
+	cfg, err := kubeconfig.Load(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	var opts []api.ClientOption
+	server := "http://localhost:8080"
+	if contextName != "" || cfg.CurrentContext != "" {
+		resolved, err := cfg.Resolve(contextName)
+		if err != nil {
+			return nil, err
+		}
+		server = resolved.Server
+		opts = append(opts, api.WithBearerToken(resolved.Token))
+	}
+	if apiServerURL != "" {
+		server = apiServerURL
+	}
+	return api.NewClient(server, opts...)
+}
+
+// run takes over the terminal and runs the UI until the user quits.
+func run(a *app, refresh time.Duration) error {
+	term, err := openTerminal(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("k9s-lite must run in a terminal: %w", err)
+	}
+	defer term.restore()
+	// Switch to the alternate screen and hide the cursor; undo both on the way out.
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer stopResize(resized)
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	resize := func() {
+		if w, h, err := term.size(); err == nil && w > 0 && h > 0 {
+			a.width, a.height = w, h
+		}
+	}
+	resize()
+	a.refresh()
+	for {
+		draw(a.render())
+		select {
+		case input, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range parseKeys(input) {
+				if a.handleKey(k) {
+					return nil
+				}
+			}
+		case <-ticker.C:
+			a.refresh()
+		case <-resized:
+			resize()
+		}
+	}
+}
+
+// draw repaints the screen with lines. The terminal is in raw mode, so each line ends
+// with an explicit carriage return.
+func draw(lines []string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K") // Clear the rest of the line
+	}
+	b.WriteString("\x1b[J") // and of the screen
+	os.Stdout.WriteString(b.String())
+}
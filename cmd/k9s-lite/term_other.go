@@ -0,0 +1,20 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+type terminal struct{}
+
+func openTerminal(*os.File) (*terminal, error) {
+	return nil, errors.New("k9s-lite needs a Unix terminal")
+}
+
+func (*terminal) size() (int, int, error) { return 0, 0, errors.New("unsupported") }
+func (*terminal) restore() error          { return nil }
+
+func notifyResize(chan<- os.Signal) {}
+func stopResize(chan<- os.Signal)   {}
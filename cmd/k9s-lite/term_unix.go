@@ -0,0 +1,56 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal puts a terminal into raw mode, so that keys arrive one at a time without
+// echo, and restores it afterwards.
+type terminal struct {
+	fd    int
+	saved *unix.Termios
+}
+
+func openTerminal(f *os.File) (*terminal, error) {
+	fd := int(f.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	raw := *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return &terminal{fd: fd, saved: saved}, nil
+}
+
+// size returns the terminal's width and height in characters.
+func (t *terminal) size() (width, height int, err error) {
+	ws, err := unix.IoctlGetWinsize(t.fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+func (t *terminal) restore() error {
+	return unix.IoctlSetTermios(t.fd, ioctlSetTermios, t.saved)
+}
+
+// notifyResize sends on ch whenever the terminal is resized.
+func notifyResize(ch chan<- os.Signal) { signal.Notify(ch, syscall.SIGWINCH) }
+
+func stopResize(ch chan<- os.Signal) { signal.Stop(ch) }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// restartedAtAnnotation is the pod template annotation rollout restart sets, as
+// kubectl does, to roll a deployment's pods without changing anything else.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+func newAnnotateCommand(o *globalOptions) *cobra.Command {
+	var overwrite bool
+	cmd := &cobra.Command{
+		Use:   "annotate (pod|node|namespace|deployment|service) NAME KEY=VALUE ... KEY- ...",
+		Short: "Add, update, or remove annotations of an object",
+		Long: `Set annotations, non-identifying metadata, on an object: KEY=VALUE sets KEY and
+KEY- removes it. An annotation that is already set is only changed with --overwrite.`,
+		Example: `  kubectl-lite annotate pod web description='serves the front page'
+  kubectl-lite annotate pod web description='serves the API' --overwrite
+  kubectl-lite annotate deployment web description-`,
+		Args:              cobra.MinimumNArgs(3),
+		ValidArgsFunction: o.completeResourceArgs([]string{"pods", "nodes", "namespaces", "deployments", "services"}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := lookupResource(args[0])
+			if err != nil {
+				return err
+			}
+			kind, name := r.Kind, args[1]
+			if kind != "Pod" && kind != "Node" && kind != "Namespace" && kind != "Deployment" && kind != "Service" {
+				return fmt.Errorf("annotate is not supported for %s", r.Name)
+			}
+			rest, err := o.restClient("annotate")
+			if err != nil {
+				return err
+			}
+			live, err := getObject(rest, kind, o.Namespace(), name)
+			if err != nil {
+				return err
+			}
+			annotations, err := annotationChanges(live.(interface{ GetObjectMeta() *api.ObjectMeta }).GetObjectMeta().Annotations, args[2:], overwrite)
+			if err != nil {
+				return err
+			}
+			patch, _ := json.Marshal(map[string]interface{}{"annotations": annotations})
+			if err := rest.Patch(kind, o.Namespace(), name, api.MergePatchType, patch, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s/%s annotated\n", strings.ToLower(kind), name)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Allow changing annotations that are already set")
+	return cmd
+}
+
+// annotationChanges turns KEY=VALUE and KEY- arguments into the annotations of a merge
+// patch, where a nil value removes the key. Setting a key that existing already has
+// to another value is an error unless overwrite is set.
+func annotationChanges(existing map[string]string, args []string, overwrite bool) (map[string]interface{}, error) {
+	changes := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			changes[key] = nil
+			continue
+		}
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid annotation %q: expected KEY=VALUE or KEY-", arg)
+		}
+		if old, set := existing[key]; set && old != value && !overwrite {
+			return nil, fmt.Errorf("annotation %s is already set to %q; use --overwrite to change it", key, old)
+		}
+		changes[key] = value
+	}
+	return changes, nil
+}
+
+func newRolloutCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Manage the rollout of a deployment",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restart deployment NAME ...",
+		Short: "Replace a deployment's pods with new ones, a few at a time",
+		Long: `Roll a deployment's pods without changing what they run: the restart sets the
+` + restartedAtAnnotation + ` annotation of the pod template to the current
+time, and the deployment controller replaces the pods as for any template change.`,
+		Example:           `  kubectl-lite rollout restart deployment web`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: o.completeResourceArgs([]string{"deployments"}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := lookupResource(args[0])
+			if err != nil {
+				return err
+			}
+			if r.Kind != "Deployment" {
+				return fmt.Errorf("rollout restart is not supported for %s", r.Name)
+			}
+			rest, err := o.restClient("rollout restart")
+			if err != nil {
+				return err
+			}
+			for _, name := range args[1:] {
+				patch, _ := json.Marshal(map[string]interface{}{
+					"template": map[string]interface{}{
+						"annotations": map[string]string{restartedAtAnnotation: time.Now().UTC().Format(time.RFC3339)},
+					},
+				})
+				if err := rest.Patch(r.Kind, o.Namespace(), name, api.MergePatchType, patch, nil); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "deployment/%s restarted\n", name)
+			}
+			return nil
+		},
+	})
+	return cmd
+}
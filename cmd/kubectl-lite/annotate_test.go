@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotationChanges(t *testing.T) {
+	existing := map[string]string{"owner": "web-team", "note": "x"}
+	got, err := annotationChanges(existing, []string{"owner=web-team", "description=a=b", "note-"}, false)
+	if err != nil {
+		t.Fatalf("annotationChanges: %v", err)
+	}
+	want := map[string]interface{}{"owner": "web-team", "description": "a=b", "note": nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := annotationChanges(existing, []string{"owner=api-team"}, false); err == nil {
+		t.Errorf("expected changing a set annotation without --overwrite to fail")
+	}
+	if _, err := annotationChanges(existing, []string{"owner=api-team"}, true); err != nil {
+		t.Errorf("expected --overwrite to allow the change, got %v", err)
+	}
+	if _, err := annotationChanges(existing, []string{"=x"}, false); err == nil {
+		t.Errorf("expected an annotation without a key to fail")
+	}
+}
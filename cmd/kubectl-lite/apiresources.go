@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func newAPIResourcesCommand(o *globalOptions) *cobra.Command {
+	var output, apiGroup string
+	var namespaced bool
+	cmd := &cobra.Command{
+		Use:   "api-resources",
+		Short: "List the resource types the API server serves",
+		Example: `  kubectl-lite api-resources
+  kubectl-lite api-resources --namespaced=false
+  kubectl-lite api-resources --api-group=apps -o wide`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rest, err := o.restClient("api-resources")
+			if err != nil {
+				return err
+			}
+			lists, err := rest.ServerResources()
+			if err != nil {
+				return err
+			}
+			var filter func(group string, r api.APIResource) bool
+			if cmd.Flags().Changed("api-group") || cmd.Flags().Changed("namespaced") {
+				filter = func(group string, r api.APIResource) bool {
+					return (!cmd.Flags().Changed("api-group") || group == apiGroup) &&
+						(!cmd.Flags().Changed("namespaced") || r.Namespaced == namespaced)
+				}
+			}
+			return printAPIResources(cmd.OutOrStdout(), lists, filter, output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: wide (adds the verbs) or name")
+	cmd.Flags().StringVar(&apiGroup, "api-group", "", "Only list resources in this API group; \"\" is the core group")
+	cmd.Flags().BoolVar(&namespaced, "namespaced", true, "Only list namespaced (true) or cluster-scoped (false) resources")
+	return cmd
+}
+
+func newAPIVersionsCommand(o *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "api-versions",
+		Short: "List the API group versions the API server serves, as group/version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rest, err := o.restClient("api-versions")
+			if err != nil {
+				return err
+			}
+			groupVersions, err := rest.ServerGroupVersions()
+			if err != nil {
+				return err
+			}
+			sort.Strings(groupVersions)
+			for _, gv := range groupVersions {
+				fmt.Fprintln(cmd.OutOrStdout(), gv)
+			}
+			return nil
+		},
+	}
+}
+
+// restClient returns the API client as an *api.Client, for commands that use more of
+// the API than api.Interface covers. command names the caller in the error.
+func (o *globalOptions) restClient(command string) (*api.Client, error) {
+	client, err := o.Client()
+	if err != nil {
+		return nil, err
+	}
+	rest, ok := client.(*api.Client)
+	if !ok {
+		return nil, fmt.Errorf("%s needs a client that talks to an API server", command)
+	}
+	return rest, nil
+}
+
+// printAPIResources prints the resources in lists that filter (if non-nil) keeps, core
+// group first and then by group and name, like kubectl api-resources.
+func printAPIResources(w io.Writer, lists []api.APIResourceList, filter func(group string, r api.APIResource) bool, output string) error {
+	type row struct {
+		group, groupVersion string
+		api.APIResource
+	}
+	var rows []row
+	for _, list := range lists {
+		group, _, named := strings.Cut(list.GroupVersion, "/")
+		if !named {
+			group = ""
+		}
+		for _, r := range list.Resources {
+			if filter == nil || filter(group, r) {
+				rows = append(rows, row{group, list.GroupVersion, r})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].group != rows[j].group {
+			return rows[i].group < rows[j].group
+		}
+		return rows[i].Name < rows[j].Name
+	})
+
+	switch output {
+	case "name":
+		for _, r := range rows {
+			name := r.Name
+			if r.group != "" {
+				name += "." + r.group
+			}
+			fmt.Fprintln(w, name)
+		}
+		return nil
+	case "", "wide":
+	default:
+		return fmt.Errorf("unknown output format %q (supported: wide, name)", output)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	header := "NAME\tSHORTNAMES\tAPIVERSION\tNAMESPACED\tKIND"
+	if output == "wide" {
+		header += "\tVERBS"
+	}
+	fmt.Fprintln(tw, header)
+	for _, r := range rows {
+		line := fmt.Sprintf("%s\t%s\t%s\t%t\t%s", r.Name, strings.Join(r.ShortNames, ","), r.groupVersion, r.Namespaced, r.Kind)
+		if output == "wide" {
+			line += "\t" + strings.Join(r.Verbs, ",")
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/jsonpath"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCommand(o *globalOptions) *cobra.Command {
+	var manifests manifestFlags
+	var fieldManager string
+	var force, serverSide bool
+	cmd := &cobra.Command{
+		Use:   "apply (-f FILENAME | -k DIRECTORY)",
+		Short: "Create or update objects from a manifest with server-side apply",
+		Long: `Apply each object in a manifest server-side: objects that don't exist are created,
+and the fields of existing ones are set to the values in the manifest.
+
+The API server records which manager owns each field. Fields this manager applied
+before but that are no longer in the manifest are removed, and fields other managers
+own, such as a pod's nodeName set by the scheduler, are left alone. Changing a field
+another manager owns fails with a conflict unless --force-conflicts is set, in which
+case this manager takes it over.
+
+With --server-side=false the merge is worked out here instead, as kubectl's
+client-side apply does: each object records the manifest it was last applied from in
+its ` + lastAppliedAnnotation + ` annotation,
+and the next apply patches the fields that changed since and removes those that were
+dropped from the manifest, leaving fields set by others alone.
+
+-f may name a directory, in which case every .yaml, .yml, and .json file in it is
+applied, and with -R those in its subdirectories too. Objects are applied namespaces
+first, then nodes, services, deployments, and pods, whatever order the files list
+them in, and a summary follows when there is more than one.
+
+-k builds the kustomization in a directory instead: the manifests its
+kustomization.yaml lists under resources, changed by its namePrefix, namespace, and
+commonLabels.`,
+		Example: `  kubectl-lite apply -f web.yaml
+  kubectl-lite apply -f manifests/ -R
+  kubectl-lite apply -k overlays/prod
+  kubectl-lite apply -f web.yaml --force-conflicts
+  kubectl-lite apply -f web.yaml --server-side=false`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !serverSide {
+				return runClientSideApply(cmd.OutOrStdout(), o, manifests)
+			}
+			return runApply(cmd.OutOrStdout(), o, manifests, api.ApplyOptions{FieldManager: fieldManager, Force: force})
+		},
+	}
+	manifests.addFlags(cmd, "apply")
+	cmd.Flags().StringVar(&fieldManager, "field-manager", "kubectl-lite", "Name of the manager that owns the applied fields")
+	cmd.Flags().BoolVar(&force, "force-conflicts", false, "Take over fields other managers own instead of failing")
+	cmd.Flags().BoolVar(&serverSide, "server-side", true, "Merge on the API server by field ownership; false merges against the last-applied-configuration annotation instead")
+	return cmd
+}
+
+// manifestFlags are the flags naming the manifests apply, diff, and delete read:
+// a file or directory with -f, or a kustomization with -k.
+type manifestFlags struct {
+	filename      string
+	recursive     bool
+	kustomization string
+}
+
+// addFlags adds the flags to cmd, whose help describes its action as verb.
+func (f *manifestFlags) addFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().StringVarP(&f.filename, "filename", "f", "", "Manifest file or directory to "+verb+", or - for standard input")
+	cmd.Flags().BoolVarP(&f.recursive, "recursive", "R", false, "Also "+verb+" the manifests in the subdirectories of a directory given with -f")
+	cmd.Flags().StringVarP(&f.kustomization, "kustomize", "k", "", "Directory holding a "+manifest.KustomizationFile+" to build and "+verb)
+	_ = cmd.MarkFlagFilename("filename", "yaml", "yml", "json")
+	_ = cmd.MarkFlagDirname("kustomize")
+}
+
+// set reports whether any manifests were named.
+func (f *manifestFlags) set() bool {
+	return f.filename != "" || f.kustomization != ""
+}
+
+// decode reads the objects in the manifests and sorts them into the order they are
+// created in.
+func (f *manifestFlags) decode() ([]manifest.Object, error) {
+	var objects []manifest.Object
+	var err error
+	switch {
+	case f.filename != "" && f.kustomization != "":
+		return nil, fmt.Errorf("-f and -k cannot be combined")
+	case f.kustomization != "":
+		objects, err = manifest.Kustomize(f.kustomization)
+	case f.filename != "":
+		objects, err = manifest.DecodePath(f.filename, f.recursive)
+	default:
+		return nil, fmt.Errorf("-f or -k is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest.SortForApply(objects)
+	return objects, nil
+}
+
+// printApplySummary prints how many objects an apply created, configured, and left
+// unchanged, by counts of each status. A single object's line says it all already.
+func printApplySummary(w io.Writer, counts map[string]int) {
+	if counts["created"]+counts["configured"]+counts["unchanged"] < 2 {
+		return
+	}
+	fmt.Fprintf(w, "%d created, %d configured, %d unchanged\n", counts["created"], counts["configured"], counts["unchanged"])
+}
+
+// runApply applies every object in the manifests and prints whether each was created,
+// configured, or unchanged.
+func runApply(w io.Writer, o *globalOptions, manifests manifestFlags, opts api.ApplyOptions) error {
+	objects, err := manifests.decode()
+	if err != nil {
+		return err
+	}
+	return applyObjects(w, o, objects, opts)
+}
+
+// applyObjects applies objects server-side, in order, and prints whether each was
+// created, configured, or unchanged.
+func applyObjects(w io.Writer, o *globalOptions, objects []manifest.Object, opts api.ApplyOptions) error {
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+	rest, ok := client.(*api.Client)
+	if !ok {
+		return fmt.Errorf("apply needs a client that supports server-side apply")
+	}
+
+	counts := make(map[string]int)
+	for _, obj := range objects {
+		namespace, name := obj.Name()
+		if name == "" {
+			return fmt.Errorf("%s in manifest has no name", obj.Kind)
+		}
+		if manifest.Namespaced(obj.Kind) && namespace == "" {
+			namespace = o.Namespace()
+		}
+		config, err := json.Marshal(obj.Fields)
+		if err != nil {
+			return err
+		}
+
+		// The server leaves an object it has nothing to change in untouched, so an
+		// unchanged resourceVersion tells "unchanged" from "configured".
+		var before string
+		if live, err := getObject(rest, obj.Kind, namespace, name); err == nil {
+			before = resourceVersionOf(live)
+		}
+		var result map[string]interface{}
+		created, err := rest.Apply(obj.Kind, namespace, name, config, opts, &result)
+		if err != nil {
+			return err
+		}
+		status := "configured"
+		switch {
+		case created:
+			status = "created"
+		case result["resourceVersion"] == before:
+			status = "unchanged"
+		}
+		counts[status]++
+		fmt.Fprintf(w, "%s/%s %s\n", strings.ToLower(obj.Kind), name, status)
+	}
+	printApplySummary(w, counts)
+	return nil
+}
+
+// lastAppliedAnnotation holds, as JSON, the manifest an object was last applied from by
+// a client-side apply.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// runClientSideApply applies every object in the manifests with a
+// three-way merge: from the configuration last applied to the object, the manifest,
+// and the live object it works out a merge patch that sets the fields the manifest
+// changed and removes those it no longer has.
+func runClientSideApply(w io.Writer, o *globalOptions, manifests manifestFlags) error {
+	objects, err := manifests.decode()
+	if err != nil {
+		return err
+	}
+	rest, err := o.restClient("client-side apply")
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	for _, obj := range objects {
+		namespace, name := obj.Name()
+		if name == "" {
+			return fmt.Errorf("%s in manifest has no name", obj.Kind)
+		}
+		if manifest.Namespaced(obj.Kind) {
+			if namespace == "" {
+				namespace = o.Namespace()
+			}
+			obj.Fields["namespace"] = namespace
+		}
+		config, err := json.Marshal(obj.Fields)
+		if err != nil {
+			return err
+		}
+		// Compare the manifest as JSON numbers and strings, as the live object comes.
+		var modified map[string]interface{}
+		if err := json.Unmarshal(config, &modified); err != nil {
+			return err
+		}
+		setAnnotation(modified, lastAppliedAnnotation, string(config))
+
+		live, err := getObject(rest, obj.Kind, namespace, name)
+		if err != nil {
+			if !api.IsNotFound(err) {
+				return err
+			}
+			typed, err := fromGeneric(obj.Kind, modified)
+			if err != nil {
+				return err
+			}
+			if _, err := createObject(rest, namespace, typed); err != nil {
+				return err
+			}
+			counts["created"]++
+			fmt.Fprintf(w, "%s/%s created\n", strings.ToLower(obj.Kind), name)
+			continue
+		}
+
+		generic, err := jsonpath.ToGeneric(live)
+		if err != nil {
+			return err
+		}
+		current := generic.(map[string]interface{})
+		// An object never applied client-side has no last configuration, so nothing is
+		// removed from it this time.
+		var original map[string]interface{}
+		if last := live.(interface{ GetObjectMeta() *api.ObjectMeta }).GetObjectMeta().Annotations[lastAppliedAnnotation]; last != "" {
+			if err := json.Unmarshal([]byte(last), &original); err != nil {
+				return fmt.Errorf("%s %s has an invalid %s annotation: %w", obj.Kind, name, lastAppliedAnnotation, err)
+			}
+			setAnnotation(original, lastAppliedAnnotation, last)
+		}
+
+		status := "unchanged"
+		if patch := threeWayMergePatch(original, modified, current); len(patch) > 0 {
+			data, err := json.Marshal(patch)
+			if err != nil {
+				return err
+			}
+			var result map[string]interface{}
+			if err := rest.Patch(obj.Kind, namespace, name, api.MergePatchType, data, &result); err != nil {
+				return err
+			}
+			if result["resourceVersion"] != resourceVersionOf(live) {
+				status = "configured"
+			}
+		}
+		counts[status]++
+		fmt.Fprintf(w, "%s/%s %s\n", strings.ToLower(obj.Kind), name, status)
+	}
+	printApplySummary(w, counts)
+	return nil
+}
+
+// setAnnotation sets annotation key of the generic object obj to value.
+func setAnnotation(obj map[string]interface{}, key, value string) {
+	annotations, _ := obj["annotations"].(map[string]interface{})
+	if annotations == nil {
+		annotations = make(map[string]interface{})
+		obj["annotations"] = annotations
+	}
+	annotations[key] = value
+}
+
+// threeWayMergePatch returns the JSON merge patch that takes current to modified:
+// fields of modified that differ from current are set, and fields of original, the
+// configuration applied before, that modified no longer has are removed. Fields in
+// neither were set by someone else and are left alone. Nested objects are compared
+// field by field; anything else, lists included, is replaced whole.
+func threeWayMergePatch(original, modified, current map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for k := range original {
+		if _, ok := modified[k]; !ok {
+			if _, ok := current[k]; ok {
+				patch[k] = nil
+			}
+		}
+	}
+	for k, v := range modified {
+		modifiedMap, modifiedIsMap := v.(map[string]interface{})
+		currentMap, currentIsMap := current[k].(map[string]interface{})
+		if modifiedIsMap && currentIsMap {
+			originalMap, _ := original[k].(map[string]interface{})
+			if sub := threeWayMergePatch(originalMap, modifiedMap, currentMap); len(sub) > 0 {
+				patch[k] = sub
+			}
+			continue
+		}
+		if !reflect.DeepEqual(v, current[k]) {
+			patch[k] = v
+		}
+	}
+	return patch
+}
+
+func resourceVersionOf(obj interface{}) string {
+	if m, ok := obj.(interface{ GetObjectMeta() *api.ObjectMeta }); ok {
+		return m.GetObjectMeta().ResourceVersion
+	}
+	return ""
+}
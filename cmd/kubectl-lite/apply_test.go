@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThreeWayMergePatch(t *testing.T) {
+	original := map[string]interface{}{
+		"name":   "web",
+		"image":  "nginx:1.25",
+		"labels": map[string]interface{}{"app": "web", "tier": "frontend"},
+		"ports":  []interface{}{float64(80)},
+	}
+	modified := map[string]interface{}{
+		"name":   "web",
+		"image":  "nginx:1.26",
+		"labels": map[string]interface{}{"app": "web"},
+	}
+	current := map[string]interface{}{
+		"name":     "web",
+		"image":    "nginx:1.25",
+		"labels":   map[string]interface{}{"app": "web", "tier": "frontend", "owner": "ops"},
+		"ports":    []interface{}{float64(80)},
+		"nodeName": "node1",
+	}
+	want := map[string]interface{}{
+		"image":  "nginx:1.26",
+		"labels": map[string]interface{}{"tier": nil},
+		"ports":  nil,
+	}
+	if got := threeWayMergePatch(original, modified, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Without a last-applied configuration, nothing is removed.
+	if got := threeWayMergePatch(nil, modified, current); !reflect.DeepEqual(got, map[string]interface{}{"image": "nginx:1.26"}) {
+		t.Errorf("got %v, want only the image changed", got)
+	}
+	if got := threeWayMergePatch(modified, modified, modified); len(got) != 0 {
+		t.Errorf("expected no patch when nothing changed, got %v", got)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+	"github.com/spf13/cobra"
+)
+
+func newAttachCommand(o *globalOptions) *cobra.Command {
+	var stdin bool
+	cmd := &cobra.Command{
+		Use:   "attach POD [-i]",
+		Short: "Attach to the process running in a pod",
+		Long: `Attach to the process running in a pod and print its output as it is written,
+until the pod stops. Unlike logs, earlier output isn't shown.
+
+With -i, each line of this program's input is input to the process, and attach
+returns once the input ends. Pods are simulated, so the process just echoes its
+input to its output, where logs shows it too.`,
+		Example: `  kubectl-lite attach web
+  echo hello | kubectl-lite attach -i web`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completePodArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			sc, err := streamClient(client, "attach")
+			if err != nil {
+				return err
+			}
+			opts := streaming.Options{Stdout: cmd.OutOrStdout()}
+			if stdin {
+				opts.Stdin = cmd.InOrStdin()
+			}
+			return sc.AttachPod(o.Namespace(), args[0], opts)
+		},
+	}
+	cmd.Flags().BoolVarP(&stdin, "stdin", "i", false, "Pass standard input to the process")
+	return cmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func newCertificateCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificate",
+		Short: "Approve nodes waiting to join the cluster",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "approve node/NAME...",
+		Short: "Approve nodes pending approval, making them Ready and schedulable",
+		Long: `Approve nodes pending approval, making them Ready and schedulable.
+
+An API server started with --require-node-approval holds the nodes that register
+with it NotReady and unschedulable, as if waiting for their kubelet's certificate
+request to be approved, until they are approved here.`,
+		Example: "  kubectl-lite certificate approve node/node1",
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var names []string
+			for _, name := range o.resourceNames("nodes") {
+				names = append(names, "node/"+name)
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			for _, arg := range args {
+				if err := approveNode(cmd.OutOrStdout(), client, arg); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	return cmd
+}
+
+// approveNode approves the node named by arg, node/NAME or just NAME.
+func approveNode(w io.Writer, client api.Interface, arg string) error {
+	name := arg
+	if kind, rest, ok := strings.Cut(arg, "/"); ok {
+		if kind != "node" && kind != "nodes" && kind != "no" {
+			return fmt.Errorf("only nodes can be approved, got %q", arg)
+		}
+		name = rest
+	}
+	node, err := client.GetNode(name)
+	if err != nil {
+		return err
+	}
+	if !node.PendingApproval {
+		fmt.Fprintf(w, "node/%s already approved\n", name)
+		return nil
+	}
+	if _, err := client.ApproveNode(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "node/%s approved\n", name)
+	return nil
+}
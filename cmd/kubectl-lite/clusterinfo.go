@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// metricsClient is implemented by clients that can fetch the API server's /metrics.
+type metricsClient interface {
+	Metrics() (string, error)
+}
+
+func newClusterInfoCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster-info",
+		Short: "Show the API server's address and the health of the cluster's components",
+		Long: `Show the API server's address and the health of every component that reports
+heartbeats to it: the scheduler, the controller manager, and each kubelet. A
+component is Unhealthy once it misses three heartbeats in a row.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			return printClusterInfo(cmd.OutOrStdout(), client)
+		},
+	}
+
+	var outputDir string
+	dumpCmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Dump the API server's metrics and the cluster's state for debugging",
+		Long: `Dump the API server's request metrics (including write conflicts, write latency,
+and slow requests) along with every component's health, every node, and the pods, deployments, and events of
+every namespace. Useful when diagnosing controllers that keep overwriting each other.
+
+Prints everything to stdout, or writes one file per section under --output-directory.`,
+		Example: `  kubectl-lite cluster-info dump
+  kubectl-lite cluster-info dump --output-directory /tmp/cluster-state`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			return dumpClusterInfo(cmd.OutOrStdout(), client, outputDir)
+		},
+	}
+	dumpCmd.Flags().StringVar(&outputDir, "output-directory", "", "Write the dump to files in this directory instead of stdout")
+
+	cmd.AddCommand(dumpCmd)
+	return cmd
+}
+
+// printClusterInfo writes the API server's address and a table of component health.
+func printClusterInfo(w io.Writer, client api.Interface) error {
+	statuses, err := client.ListComponentStatuses()
+	if err != nil {
+		return fmt.Errorf("API server at %s is not reachable: %w", client.GetBaseURL(), err)
+	}
+	fmt.Fprintf(w, "API server is running at %s\n\n", client.GetBaseURL())
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No components have reported yet.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tSTATUS\tLAST HEARTBEAT\tMESSAGE")
+	for _, status := range statuses {
+		health := "Healthy"
+		if !status.Healthy {
+			health = "Unhealthy"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s ago\t%s\n", status.Name, health, translateTimestampSince(status.LastHeartbeatTime), status.Message)
+	}
+	return tw.Flush()
+}
+
+// dumpClusterInfo collects the metrics and state of the cluster behind client. With
+// an empty dir each section is printed to w under a header; otherwise each goes to
+// its own file, metrics.txt, componentstatuses.json, nodes.json, and
+// <namespace>/<resource>.json, and w only says where the dump went.
+func dumpClusterInfo(w io.Writer, client api.Interface, dir string) error {
+	write := func(name string, data []byte) error {
+		if dir == "" {
+			fmt.Fprintf(w, "==== %s ====\n%s\n", name, data)
+			return nil
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o644)
+	}
+	writeJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return write(name, data)
+	}
+
+	if mc, ok := client.(metricsClient); ok {
+		metrics, err := mc.Metrics()
+		if err != nil {
+			return err
+		}
+		if err := write("metrics.txt", []byte(metrics)); err != nil {
+			return err
+		}
+	}
+
+	statuses, err := client.ListComponentStatuses()
+	if err != nil {
+		return err
+	}
+	if err := writeJSON("componentstatuses.json", statuses); err != nil {
+		return err
+	}
+	nodes, err := client.ListNodes("")
+	if err != nil {
+		return err
+	}
+	if err := writeJSON("nodes.json", nodes); err != nil {
+		return err
+	}
+	namespaces, err := client.ListNamespaces()
+	if err != nil {
+		return err
+	}
+	for _, ns := range namespaces {
+		pods, err := client.ListPods(ns.Name, "")
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(ns.Name, "pods.json"), pods); err != nil {
+			return err
+		}
+		deployments, err := client.ListDeployments(ns.Name)
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(ns.Name, "deployments.json"), deployments); err != nil {
+			return err
+		}
+		events, err := client.ListEvents(ns.Name)
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(ns.Name, "events.json"), events); err != nil {
+			return err
+		}
+	}
+
+	if dir != "" {
+		fmt.Fprintf(w, "Cluster info dumped to %s\n", dir)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+// fakeMetricsClient adds a canned /metrics response to the fake client.
+type fakeMetricsClient struct {
+	*fake.Client
+	metrics string
+}
+
+func (c fakeMetricsClient) Metrics() (string, error) { return c.metrics, nil }
+
+func TestPrintClusterInfo(t *testing.T) {
+	client := fake.NewClient()
+	if err := client.ReportComponentStatus(&api.ComponentStatus{Name: "scheduler", HeartbeatPeriodSeconds: 10}); err != nil {
+		t.Fatalf("ReportComponentStatus: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := printClusterInfo(&out, client); err != nil {
+		t.Fatalf("printClusterInfo: %v", err)
+	}
+	for _, want := range []string{"API server is running at fake://", "scheduler", "Healthy"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestDumpClusterInfo(t *testing.T) {
+	client := fakeMetricsClient{
+		Client: fake.NewClient(
+			&api.Namespace{ObjectMeta: api.ObjectMeta{Name: DefaultNamespace}},
+			&api.Node{ObjectMeta: api.ObjectMeta{Name: "n1"}, Status: api.NodeReady},
+			&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx"},
+		),
+		metrics: `apiserver_write_conflicts_total{verb="update",resource="pods"} 3` + "\n",
+	}
+
+	var out bytes.Buffer
+	if err := dumpClusterInfo(&out, client, ""); err != nil {
+		t.Fatalf("dumpClusterInfo: %v", err)
+	}
+	for _, want := range []string{"==== metrics.txt ====", "apiserver_write_conflicts_total", "==== nodes.json ====", `"n1"`, "==== default/pods.json ====", `"web"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dump missing %q:\n%s", want, out.String())
+		}
+	}
+
+	dir := t.TempDir()
+	out.Reset()
+	if err := dumpClusterInfo(&out, client, dir); err != nil {
+		t.Fatalf("dumpClusterInfo to %s: %v", dir, err)
+	}
+	for _, name := range []string{"metrics.txt", "componentstatuses.json", "nodes.json", "default/pods.json", "default/deployments.json", "default/events.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s in the dump: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubeconfig"
+	"github.com/spf13/cobra"
+)
+
+// Shell completion scripts come from cobra's built-in "completion" command. The
+// functions here supply the dynamic candidates: resource names are looked up on the
+// API server, contexts and namespaces in the kubeconfig.
+
+// completeResourceArgs completes "<type> <name>" positional arguments, offering
+// resourceTypes first and then the names of existing objects of the chosen type.
+func (o *globalOptions) completeResourceArgs(resourceTypes []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return resourceTypes, cobra.ShellCompDirectiveNoFileComp
+		case 1:
+			return o.resourceNames(args[0]), cobra.ShellCompDirectiveNoFileComp
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+}
+
+// resourceNames lists the names of existing objects of resourceType, which may be any
+// name lookupResource accepts. Errors are swallowed: a completion that can't reach
+// the API server should offer nothing rather than print noise into the user's prompt.
+func (o *globalOptions) resourceNames(resourceType string) []string {
+	resource, err := lookupResource(resourceType)
+	if err != nil {
+		return nil
+	}
+	client, err := o.Client()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	switch resource.Name {
+	case "pods":
+		pods, err := client.ListPods(o.Namespace(), "")
+		if err != nil {
+			return nil
+		}
+		for _, p := range pods {
+			names = append(names, p.Name)
+		}
+	case "nodes":
+		nodes, err := client.ListNodes("")
+		if err != nil {
+			return nil
+		}
+		for _, n := range nodes {
+			names = append(names, n.Name)
+		}
+	case "deployments":
+		deployments, err := client.ListDeployments(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, d := range deployments {
+			names = append(names, d.Name)
+		}
+	case "services":
+		services, err := client.ListServices(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, svc := range services {
+			names = append(names, svc.Name)
+		}
+	case "poddisruptionbudgets":
+		pdbs, err := client.ListPodDisruptionBudgets(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, pdb := range pdbs {
+			names = append(names, pdb.Name)
+		}
+	case "networkpolicies":
+		policies, err := client.ListNetworkPolicies(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, policy := range policies {
+			names = append(names, policy.Name)
+		}
+	case "persistentvolumes":
+		pvs, err := client.ListPersistentVolumes()
+		if err != nil {
+			return nil
+		}
+		for _, pv := range pvs {
+			names = append(names, pv.Name)
+		}
+	case "persistentvolumeclaims":
+		pvcs, err := client.ListPersistentVolumeClaims(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, pvc := range pvcs {
+			names = append(names, pvc.Name)
+		}
+	case "leases":
+		leases, err := client.ListLeases(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, lease := range leases {
+			names = append(names, lease.Name)
+		}
+	case "configmaps":
+		configMaps, err := client.ListConfigMaps(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, cm := range configMaps {
+			names = append(names, cm.Name)
+		}
+	case "secrets":
+		secrets, err := client.ListSecrets(o.Namespace())
+		if err != nil {
+			return nil
+		}
+		for _, secret := range secrets {
+			names = append(names, secret.Name)
+		}
+	case "namespaces":
+		namespaces, err := client.ListNamespaces()
+		if err != nil {
+			return nil
+		}
+		for _, ns := range namespaces {
+			names = append(names, ns.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeNamespaces offers the namespaces that exist on the API server, plus any
+// named in kubeconfig contexts so completion still helps when the server is down.
+func (o *globalOptions) completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	seen := map[string]bool{DefaultNamespace: true}
+	for _, name := range o.resourceNames("namespaces") {
+		seen[name] = true
+	}
+	if cfg, err := kubeconfig.Load(o.kubeconfigPath); err == nil {
+		for _, c := range cfg.Contexts {
+			if c.Context.Namespace != "" {
+				seen[c.Context.Namespace] = true
+			}
+		}
+	}
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (o *globalOptions) completeContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := kubeconfig.Load(o.kubeconfigPath)
+	if err != nil || len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, c := range cfg.Contexts {
+		names = append(names, c.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
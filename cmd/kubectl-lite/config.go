@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubeconfig"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// newConfigCommand manages the kubeconfig-lite file. None of its subcommands talk
+// to the API server.
+func newConfigCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Modify kubeconfig-lite files",
+	}
+
+	// edit loads the kubeconfig, applies fn, and saves it back.
+	edit := func(fn func(cfg *kubeconfig.Config) error) error {
+		cfg, err := kubeconfig.Load(o.kubeconfigPath)
+		if err != nil {
+			return err
+		}
+		if err := fn(cfg); err != nil {
+			return err
+		}
+		return kubeconfig.Save(o.kubeconfigPath, cfg)
+	}
+
+	var raw bool
+	viewCmd := &cobra.Command{
+		Use:   "view",
+		Short: "Display the kubeconfig, with tokens redacted unless --raw is given",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := kubeconfig.Load(o.kubeconfigPath)
+			if err != nil {
+				return err
+			}
+			out := *cfg
+			if !raw {
+				out.Users = make([]kubeconfig.NamedUser, len(cfg.Users))
+				for i, u := range cfg.Users {
+					out.Users[i] = u
+					if u.User.Token != "" {
+						out.Users[i].User.Token = "REDACTED"
+					}
+				}
+			}
+			data, err := yaml.Marshal(&out)
+			if err != nil {
+				return fmt.Errorf("encoding kubeconfig: %w", err)
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+	viewCmd.Flags().BoolVar(&raw, "raw", false, "Show credentials instead of redacting them")
+
+	currentContextCmd := &cobra.Command{
+		Use:   "current-context",
+		Short: "Display the current context",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := kubeconfig.Load(o.kubeconfigPath)
+			if err != nil {
+				return err
+			}
+			if cfg.CurrentContext == "" {
+				return fmt.Errorf("current-context is not set")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), cfg.CurrentContext)
+			return nil
+		},
+	}
+
+	getContextsCmd := &cobra.Command{
+		Use:   "get-contexts",
+		Short: "List the contexts in the kubeconfig",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := kubeconfig.Load(o.kubeconfigPath)
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 3, ' ', 0)
+			fmt.Fprintln(tw, "CURRENT\tNAME\tCLUSTER\tUSER\tNAMESPACE")
+			for _, c := range cfg.Contexts {
+				current := ""
+				if c.Name == cfg.CurrentContext {
+					current = "*"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", current, c.Name, c.Context.Cluster, c.Context.User, c.Context.Namespace)
+			}
+			return tw.Flush()
+		},
+	}
+
+	useContextCmd := &cobra.Command{
+		Use:               "use-context NAME",
+		Short:             "Set the current context",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeContexts,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := edit(func(cfg *kubeconfig.Config) error { return cfg.UseContext(args[0]) }); err != nil {
+				return err
+			}
+			fmt.Printf("Switched to context %q.\n", args[0])
+			return nil
+		},
+	}
+
+	var server string
+	setClusterCmd := &cobra.Command{
+		Use:   "set-cluster NAME --server=<url>",
+		Short: "Add or update a cluster entry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if server == "" {
+				return fmt.Errorf("--server is required")
+			}
+			if err := edit(func(cfg *kubeconfig.Config) error {
+				cfg.SetCluster(args[0], kubeconfig.Cluster{Server: server})
+				return nil
+			}); err != nil {
+				return err
+			}
+			fmt.Printf("Cluster %q set.\n", args[0])
+			return nil
+		},
+	}
+	setClusterCmd.Flags().StringVar(&server, "server", "", "URL of the API server")
+
+	var token string
+	setCredentialsCmd := &cobra.Command{
+		Use:   "set-credentials NAME --token=<token>",
+		Short: "Add or update a user entry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := edit(func(cfg *kubeconfig.Config) error {
+				cfg.SetUser(args[0], kubeconfig.User{Token: token})
+				return nil
+			}); err != nil {
+				return err
+			}
+			fmt.Printf("User %q set.\n", args[0])
+			return nil
+		},
+	}
+	setCredentialsCmd.Flags().StringVar(&token, "token", "", "Bearer token for the API server")
+
+	var ctxCluster, ctxUser string
+	setContextCmd := &cobra.Command{
+		Use:               "set-context NAME [--cluster=<cluster>] [--user=<user>] [--namespace=<ns>]",
+		Short:             "Add or update a context entry; unset flags keep their current values",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeContexts,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := edit(func(cfg *kubeconfig.Config) error {
+				ctx := kubeconfig.Context{}
+				if existing := cfg.GetContext(args[0]); existing != nil {
+					ctx = *existing
+				}
+				if cmd.Flags().Changed("cluster") {
+					ctx.Cluster = ctxCluster
+				}
+				if cmd.Flags().Changed("user") {
+					ctx.User = ctxUser
+				}
+				// --namespace is the persistent global flag; read it from the shared options.
+				if cmd.Flags().Changed("namespace") {
+					ctx.Namespace = o.namespace
+				}
+				if ctx.Cluster == "" {
+					return fmt.Errorf("--cluster is required")
+				}
+				cfg.SetContext(args[0], ctx)
+				return nil
+			}); err != nil {
+				return err
+			}
+			fmt.Printf("Context %q set.\n", args[0])
+			return nil
+		},
+	}
+	setContextCmd.Flags().StringVar(&ctxCluster, "cluster", "", "Cluster for the context")
+	setContextCmd.Flags().StringVar(&ctxUser, "user", "", "User for the context")
+
+	cmd.AddCommand(viewCmd, currentContextCmd, getContextsCmd, useContextCmd, setClusterCmd, setCredentialsCmd, setContextCmd)
+	return cmd
+}
@@ -0,0 +1,581 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+	"github.com/spf13/cobra"
+)
+
+func newCreateCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a resource",
+	}
+	cmd.AddCommand(
+		newCreatePodCommand(o),
+		newCreateDeploymentCommand(o),
+		newCreateServiceCommand(o),
+		newCreateNamespaceCommand(o),
+		newCreatePodDisruptionBudgetCommand(o),
+		newCreateNetworkPolicyCommand(o),
+		newCreateConfigMapCommand(o),
+		newCreateSecretCommand(o),
+		newCreateAPIServiceCommand(o),
+	)
+	return cmd
+}
+
+func newCreatePodCommand(o *globalOptions) *cobra.Command {
+	var name, image, pullPolicy, workingDir, restart string
+	var command, tty, privileged bool
+	var runAsUser int64
+	var pullSecrets []string
+	cmd := &cobra.Command{
+		Use:   "pod [NAME] --image=<image> [--command] [-- ARGS...]",
+		Short: "Create a pod running a single image",
+		Long: `Create a pod running a single image. Arguments after -- are passed to the image's
+entrypoint, or with --command replace it: the first is the command to run.`,
+		Example: `  kubectl-lite create pod mypod --image nginx
+
+  # Run a command in the pod, printing its output to the pod's logs
+  kubectl-lite create pod hello --image busybox --command -- echo hello`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if n := cmd.ArgsLenAtDash(); n > 1 || (n < 0 && len(args) > 1) {
+				return fmt.Errorf("expected at most one pod name before --, got %v", args)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var processArgs []string
+			if n := cmd.ArgsLenAtDash(); n >= 0 {
+				args, processArgs = args[:n], args[n:]
+			}
+			if len(args) == 1 {
+				name = args[0]
+			}
+			if name == "" || image == "" {
+				return fmt.Errorf("a pod name and --image are required for creating a pod")
+			}
+			if command && len(processArgs) == 0 {
+				return fmt.Errorf("--command requires the command to run after --")
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			namespace := o.Namespace()
+			pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace}, Image: image, ImagePullPolicy: api.PullPolicy(pullPolicy), WorkingDir: workingDir, TTY: tty, RestartPolicy: api.RestartPolicy(restart)}
+			if command {
+				pod.Command = processArgs
+			} else {
+				pod.Args = processArgs
+			}
+			if privileged || cmd.Flags().Changed("run-as-user") {
+				pod.SecurityContext = &api.SecurityContext{Privileged: privileged}
+				if cmd.Flags().Changed("run-as-user") {
+					pod.SecurityContext.RunAsUser = &runAsUser
+				}
+			}
+			for _, secret := range pullSecrets {
+				pod.ImagePullSecrets = append(pod.ImagePullSecrets, api.LocalObjectReference{Name: secret})
+			}
+			createdPod, err := client.CreatePod(namespace, pod)
+			if err != nil {
+				return fmt.Errorf("creating pod: %w", err)
+			}
+			fmt.Printf("Pod %s/%s created\n", createdPod.Namespace, createdPod.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Name of the pod (alternative to the NAME argument)")
+	cmd.Flags().StringVar(&image, "image", "", "Image for the pod")
+	cmd.Flags().StringVar(&pullPolicy, "image-pull-policy", "", "Always, IfNotPresent, or Never (default Always for :latest images, IfNotPresent otherwise)")
+	cmd.Flags().BoolVar(&command, "command", false, "Run the arguments after -- as the pod's command instead of passing them to the image's entrypoint")
+	cmd.Flags().StringVar(&restart, "restart", "", "Always, OnFailure, or Never: whether to run the container again after it exits (default Always)")
+	cmd.Flags().StringVar(&workingDir, "working-dir", "", "Absolute path the pod's process starts in (default /)")
+	cmd.Flags().BoolVarP(&tty, "tty", "t", false, "Give the pod's process a terminal")
+	cmd.Flags().BoolVar(&privileged, "privileged", false, "Run the pod's container privileged")
+	cmd.Flags().Int64Var(&runAsUser, "run-as-user", 0, "UID to run the pod's process as (default the image's user, root)")
+	cmd.Flags().StringArrayVar(&pullSecrets, "image-pull-secret", nil, "Name of a docker-registry secret to pull the image with (repeatable)")
+	return cmd
+}
+
+func newCreateDeploymentCommand(o *globalOptions) *cobra.Command {
+	var image string
+	var replicas int
+	cmd := &cobra.Command{
+		Use:     "deployment NAME --image=<image> [--replicas=N]",
+		Aliases: []string{"deploy"},
+		Short:   "Create a deployment running a single image",
+		Example: "  kubectl-lite create deployment web --image=nginx --replicas=3",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if image == "" {
+				return fmt.Errorf("--image is required for creating a deployment")
+			}
+			if replicas < 0 {
+				return fmt.Errorf("--replicas must not be negative")
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			namespace := o.Namespace()
+			created, err := client.CreateDeployment(namespace, generateDeployment(args[0], image, replicas))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deployment %s/%s created\n", created.Namespace, created.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&image, "image", "", "Image for the deployment's pods")
+	cmd.Flags().IntVar(&replicas, "replicas", 1, "Number of replicas to run")
+	return cmd
+}
+
+// generateDeployment builds a deployment whose pods are labeled app=<name>, the same
+// convention kubectl uses, so a service generated for the same name selects them.
+func generateDeployment(name, image string, replicas int) *api.Deployment {
+	labels := map[string]string{"app": name}
+	return &api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Replicas:   replicas,
+		Selector:   map[string]string{"app": name},
+		Template:   api.PodTemplate{Labels: labels, Image: image},
+	}
+}
+
+func newCreateServiceCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "service",
+		Aliases: []string{"svc"},
+		Short:   "Create a service of the given type",
+	}
+	cmd.AddCommand(newCreateServiceClusterIPCommand(o))
+	return cmd
+}
+
+func newCreateServiceClusterIPCommand(o *globalOptions) *cobra.Command {
+	var tcp []string
+	cmd := &cobra.Command{
+		Use:     "clusterip NAME --tcp=<port>:<targetPort> [--tcp=...]",
+		Short:   "Create a ClusterIP service selecting pods labeled app=NAME",
+		Example: "  kubectl-lite create service clusterip web --tcp=80:8080",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ports, err := parseTCPPorts(tcp)
+			if err != nil {
+				return err
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			namespace := o.Namespace()
+			svc := &api.Service{
+				ObjectMeta: api.ObjectMeta{Name: args[0]},
+				Type:       api.ServiceTypeClusterIP,
+				Selector:   map[string]string{"app": args[0]},
+				Ports:      ports,
+			}
+			created, err := client.CreateService(namespace, svc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Service %s/%s created\n", created.Namespace, created.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&tcp, "tcp", nil, "Port pairs <port>:<targetPort>; the target port defaults to the port")
+	return cmd
+}
+
+// parseTCPPorts turns --tcp values such as "80:8080" or "443" into service ports.
+func parseTCPPorts(specs []string) ([]api.ServicePort, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("at least one --tcp=<port>:<targetPort> is required")
+	}
+	var ports []api.ServicePort
+	for _, spec := range specs {
+		portStr, targetStr, hasTarget := strings.Cut(spec, ":")
+		if !hasTarget {
+			targetStr = portStr
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q in --tcp=%s", portStr, spec)
+		}
+		target, err := strconv.Atoi(targetStr)
+		if err != nil || target < 1 || target > 65535 {
+			return nil, fmt.Errorf("invalid target port %q in --tcp=%s", targetStr, spec)
+		}
+		ports = append(ports, api.ServicePort{
+			Name:       fmt.Sprintf("%d-%d", port, target),
+			Protocol:   "TCP",
+			Port:       port,
+			TargetPort: target,
+		})
+	}
+	return ports, nil
+}
+
+func newCreateNamespaceCommand(o *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:     "namespace NAME",
+		Aliases: []string{"ns"},
+		Short:   "Create a namespace",
+		Example: "  kubectl-lite create namespace staging",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			created, err := client.CreateNamespace(&api.Namespace{ObjectMeta: api.ObjectMeta{Name: args[0]}})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Namespace %s created\n", created.Name)
+			return nil
+		},
+	}
+}
+
+func newCreatePodDisruptionBudgetCommand(o *globalOptions) *cobra.Command {
+	var selector string
+	var minAvailable, maxUnavailable int
+	cmd := &cobra.Command{
+		Use:     "poddisruptionbudget NAME --selector=<labels> (--min-available=N | --max-unavailable=N)",
+		Aliases: []string{"pdb"},
+		Short:   "Create a pod disruption budget limiting voluntary evictions of the selected pods",
+		Example: "  kubectl-lite create pdb web --selector=app=web --min-available=2",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pdb, err := generatePodDisruptionBudget(args[0], selector,
+				flagInt(cmd, "min-available", minAvailable), flagInt(cmd, "max-unavailable", maxUnavailable))
+			if err != nil {
+				return err
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			created, err := client.CreatePodDisruptionBudget(o.Namespace(), pdb)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("PodDisruptionBudget %s/%s created\n", created.Namespace, created.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&selector, "selector", "", "Label selector of the pods the budget covers, e.g. app=web")
+	cmd.Flags().IntVar(&minAvailable, "min-available", 0, "Number of selected pods that must stay Running")
+	cmd.Flags().IntVar(&maxUnavailable, "max-unavailable", 0, "Number of selected pods that may be unavailable at once")
+	return cmd
+}
+
+// flagInt returns a pointer to value if the named flag was set, or nil.
+func flagInt(cmd *cobra.Command, name string, value int) *int {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	return &value
+}
+
+// generatePodDisruptionBudget builds a budget from the create pdb flags. Exactly one of
+// minAvailable and maxUnavailable must be non-nil.
+func generatePodDisruptionBudget(name, selector string, minAvailable, maxUnavailable *int) (*api.PodDisruptionBudget, error) {
+	if selector == "" {
+		return nil, fmt.Errorf("--selector is required for creating a pod disruption budget")
+	}
+	set, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		return nil, err
+	}
+	if (minAvailable == nil) == (maxUnavailable == nil) {
+		return nil, fmt.Errorf("exactly one of --min-available and --max-unavailable is required")
+	}
+	return &api.PodDisruptionBudget{
+		ObjectMeta:     api.ObjectMeta{Name: name},
+		Selector:       set,
+		MinAvailable:   minAvailable,
+		MaxUnavailable: maxUnavailable,
+	}, nil
+}
+
+func newCreateNetworkPolicyCommand(o *globalOptions) *cobra.Command {
+	var podSelector string
+	var allowFrom, allowFromNamespaces []string
+	var ports []int
+	cmd := &cobra.Command{
+		Use:     "networkpolicy NAME [--pod-selector=<labels>] [--allow-from=<labels>]... [--allow-from-namespace=<labels>]... [--port=N]...",
+		Aliases: []string{"netpol"},
+		Short:   "Create a network policy restricting the connections the selected pods accept",
+		Long: `Create a network policy restricting the connections the selected pods accept to
+those from the pods matching --allow-from in the policy's namespace, or from any pod
+in the namespaces matching --allow-from-namespace, on the given ports. Without
+--allow-from or --allow-from-namespace, connections from anywhere are allowed on the
+ports; with none of the three flags, the selected pods accept no connections at all.`,
+		Example: `  # Only the web pods may connect to the database, and only on port 5432
+  kubectl-lite create networkpolicy db --pod-selector=app=db --allow-from=app=web --port=5432
+
+  # Refuse every connection to the pods in the namespace
+  kubectl-lite create networkpolicy deny-all`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := generateNetworkPolicy(args[0], podSelector, allowFrom, allowFromNamespaces, ports)
+			if err != nil {
+				return err
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			created, err := client.CreateNetworkPolicy(o.Namespace(), policy)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("NetworkPolicy %s/%s created\n", created.Namespace, created.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&podSelector, "pod-selector", "", "Label selector of the pods the policy applies to; empty selects every pod in the namespace")
+	cmd.Flags().StringArrayVar(&allowFrom, "allow-from", nil, "Label selector of pods in the namespace to accept connections from (repeatable)")
+	cmd.Flags().StringArrayVar(&allowFromNamespaces, "allow-from-namespace", nil, "Label selector of namespaces whose pods to accept connections from (repeatable)")
+	cmd.Flags().IntSliceVar(&ports, "port", nil, "Port to accept connections on (repeatable); by default, every port")
+	return cmd
+}
+
+// generateNetworkPolicy builds a policy from the create networkpolicy flags: a single
+// ingress rule allowing the peers on the ports, or none if neither is given.
+func generateNetworkPolicy(name, podSelector string, allowFrom, allowFromNamespaces []string, ports []int) (*api.NetworkPolicy, error) {
+	selector, err := labels.ConvertSelectorToLabelsMap(podSelector)
+	if err != nil {
+		return nil, err
+	}
+	policy := &api.NetworkPolicy{ObjectMeta: api.ObjectMeta{Name: name}, PodSelector: selector}
+	if len(allowFrom) == 0 && len(allowFromNamespaces) == 0 && len(ports) == 0 {
+		return policy, nil
+	}
+	rule := api.NetworkPolicyIngressRule{Ports: ports}
+	for _, s := range allowFrom {
+		set, err := labels.ConvertSelectorToLabelsMap(s)
+		if err != nil {
+			return nil, err
+		}
+		rule.From = append(rule.From, api.NetworkPolicyPeer{PodSelector: set})
+	}
+	for _, s := range allowFromNamespaces {
+		set, err := labels.ConvertSelectorToLabelsMap(s)
+		if err != nil {
+			return nil, err
+		}
+		rule.From = append(rule.From, api.NetworkPolicyPeer{NamespaceSelector: set})
+	}
+	policy.Ingress = []api.NetworkPolicyIngressRule{rule}
+	return policy, nil
+}
+
+func newCreateConfigMapCommand(o *globalOptions) *cobra.Command {
+	var literals []string
+	cmd := &cobra.Command{
+		Use:     "configmap NAME [--from-literal=key=value]...",
+		Aliases: []string{"cm"},
+		Short:   "Create a config map from literal values",
+		Example: "  kubectl-lite create configmap settings --from-literal=mode=fast --from-literal=level=3",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configMap, err := generateConfigMap(args[0], literals)
+			if err != nil {
+				return err
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			created, err := client.CreateConfigMap(o.Namespace(), configMap)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("ConfigMap %s/%s created\n", created.Namespace, created.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&literals, "from-literal", nil, "A key and value to store, as key=value (repeatable)")
+	return cmd
+}
+
+// generateConfigMap builds a config map holding the key=value pairs of literals.
+func generateConfigMap(name string, literals []string) (*api.ConfigMap, error) {
+	data, err := parseLiterals(literals)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: name}, Data: data}, nil
+}
+
+// parseLiterals returns the key=value pairs of --from-literal flags, or nil for none.
+func parseLiterals(literals []string) (map[string]string, error) {
+	var data map[string]string
+	for _, literal := range literals {
+		key, value, ok := strings.Cut(literal, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --from-literal %q: expected key=value", literal)
+		}
+		if _, dup := data[key]; dup {
+			return nil, fmt.Errorf("invalid --from-literal %q: key %s given twice", literal, key)
+		}
+		if data == nil {
+			data = make(map[string]string)
+		}
+		data[key] = value
+	}
+	return data, nil
+}
+
+func newCreateAPIServiceCommand(o *globalOptions) *cobra.Command {
+	var serverURL string
+	cmd := &cobra.Command{
+		Use:   "apiservice GROUP/VERSION --url=<url>",
+		Short: "Register an aggregated API server for an API group version",
+		Long: `Register the server at --url as the aggregated API server of GROUP/VERSION: the
+API server lists the group in discovery and passes every request under
+/apis/GROUP/VERSION on to it. The API service is named VERSION.GROUP.`,
+		Example: "  kubectl-lite create apiservice metrics.lite/v1 --url=http://localhost:8443",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			group, version, ok := strings.Cut(args[0], "/")
+			if !ok {
+				return fmt.Errorf("invalid group version %q: expected GROUP/VERSION", args[0])
+			}
+			rest, err := o.restClient("create apiservice")
+			if err != nil {
+				return err
+			}
+			created, err := rest.APIServices().Create(&api.APIService{
+				ObjectMeta: api.ObjectMeta{Name: version + "." + group},
+				Group:      group,
+				Version:    version,
+				URL:        serverURL,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("APIService %s created\n", created.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "url", "", "Where the aggregated API server listens, e.g. http://localhost:8443")
+	_ = cmd.MarkFlagRequired("url")
+	return cmd
+}
+
+func newCreateSecretCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Create a secret",
+	}
+	cmd.AddCommand(newCreateGenericSecretCommand(o), newCreateDockerRegistrySecretCommand(o))
+	return cmd
+}
+
+func newCreateGenericSecretCommand(o *globalOptions) *cobra.Command {
+	var literals []string
+	cmd := &cobra.Command{
+		Use:     "generic NAME [--from-literal=key=value]...",
+		Short:   "Create an Opaque secret from literal values",
+		Example: "  kubectl-lite create secret generic db-password --from-literal=password=s3cret",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secret, err := generateGenericSecret(args[0], literals)
+			if err != nil {
+				return err
+			}
+			return createSecret(o, secret)
+		},
+	}
+	cmd.Flags().StringArrayVar(&literals, "from-literal", nil, "A key and value to store, as key=value (repeatable)")
+	return cmd
+}
+
+func newCreateDockerRegistrySecretCommand(o *globalOptions) *cobra.Command {
+	var server, username, password string
+	cmd := &cobra.Command{
+		Use:   "docker-registry NAME --docker-server=HOST --docker-username=USER --docker-password=PASSWORD",
+		Short: "Create a secret holding credentials for an image registry",
+		Long: `Create a secret holding credentials for an image registry. Pods name it in their
+imagePullSecrets to pull images from that registry.`,
+		Example: `  kubectl-lite create secret docker-registry regcred --docker-server=registry.local:5000 --docker-username=alice --docker-password=s3cret
+  kubectl-lite create pod app --image registry.local:5000/team/app:v1 --image-pull-secret regcred`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secret, err := generateDockerRegistrySecret(args[0], server, username, password)
+			if err != nil {
+				return err
+			}
+			return createSecret(o, secret)
+		},
+	}
+	cmd.Flags().StringVar(&server, "docker-server", "https://index.docker.io/v1/", "Registry the credentials are for")
+	cmd.Flags().StringVar(&username, "docker-username", "", "Username to log in to the registry with")
+	cmd.Flags().StringVar(&password, "docker-password", "", "Password to log in to the registry with")
+	return cmd
+}
+
+// generateGenericSecret builds an Opaque secret holding the key=value pairs of literals.
+func generateGenericSecret(name string, literals []string) (*api.Secret, error) {
+	data, err := parseLiterals(literals)
+	if err != nil {
+		return nil, err
+	}
+	secret := &api.Secret{ObjectMeta: api.ObjectMeta{Name: name}, Type: api.SecretTypeOpaque}
+	for key, value := range data {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[key] = []byte(value)
+	}
+	return secret, nil
+}
+
+// generateDockerRegistrySecret builds a SecretTypeDockerConfigJSON secret holding the
+// credentials for server.
+func generateDockerRegistrySecret(name, server, username, password string) (*api.Secret, error) {
+	if server == "" || username == "" || password == "" {
+		return nil, fmt.Errorf("--docker-server, --docker-username, and --docker-password are required")
+	}
+	config := api.DockerConfigJSON{Auths: map[string]api.DockerConfigEntry{server: {
+		Username: username,
+		Password: password,
+		Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}}}
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Type:       api.SecretTypeDockerConfigJSON,
+		Data:       map[string][]byte{api.DockerConfigJSONKey: raw},
+	}, nil
+}
+
+func createSecret(o *globalOptions, secret *api.Secret) error {
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+	created, err := client.CreateSecret(o.Namespace(), secret)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Secret %s/%s created\n", created.Namespace, created.Name)
+	return nil
+}
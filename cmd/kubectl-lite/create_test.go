@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestParseTCPPorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []api.ServicePort
+		wantErr bool
+	}{
+		{
+			name:  "port and target",
+			specs: []string{"80:8080"},
+			want:  []api.ServicePort{{Name: "80-8080", Protocol: "TCP", Port: 80, TargetPort: 8080}},
+		},
+		{
+			name:  "target defaults to port",
+			specs: []string{"443", "80:8080"},
+			want: []api.ServicePort{
+				{Name: "443-443", Protocol: "TCP", Port: 443, TargetPort: 443},
+				{Name: "80-8080", Protocol: "TCP", Port: 80, TargetPort: 8080},
+			},
+		},
+		{name: "no ports", specs: nil, wantErr: true},
+		{name: "not a number", specs: []string{"http:80"}, wantErr: true},
+		{name: "out of range", specs: []string{"80:70000"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTCPPorts(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTCPPorts(%v) error = %v, wantErr %v", tt.specs, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTCPPorts(%v) = %+v, want %+v", tt.specs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateNetworkPolicy(t *testing.T) {
+	policy, err := generateNetworkPolicy("db", "app=db", []string{"app=web"}, []string{"team=ops"}, []int{5432})
+	if err != nil {
+		t.Fatalf("generateNetworkPolicy: %v", err)
+	}
+	want := []api.NetworkPolicyIngressRule{{
+		From: []api.NetworkPolicyPeer{
+			{PodSelector: map[string]string{"app": "web"}},
+			{NamespaceSelector: map[string]string{"team": "ops"}},
+		},
+		Ports: []int{5432},
+	}}
+	if !reflect.DeepEqual(policy.PodSelector, map[string]string{"app": "db"}) || !reflect.DeepEqual(policy.Ingress, want) {
+		t.Errorf("generateNetworkPolicy() = %+v, want pod selector app=db and ingress %+v", policy, want)
+	}
+
+	denyAll, err := generateNetworkPolicy("deny-all", "", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("generateNetworkPolicy: %v", err)
+	}
+	if len(denyAll.PodSelector) != 0 || len(denyAll.Ingress) != 0 {
+		t.Errorf("expected a policy selecting every pod with no ingress rules, got %+v", denyAll)
+	}
+
+	if _, err := generateNetworkPolicy("bad", "app!=db", nil, nil, nil); err == nil {
+		t.Error("expected an error for a selector that isn't key=value terms")
+	}
+}
+
+func TestGenerateConfigMap(t *testing.T) {
+	configMap, err := generateConfigMap("settings", []string{"mode=fast", "query=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("generateConfigMap: %v", err)
+	}
+	want := map[string]string{"mode": "fast", "query": "a=b", "empty": ""}
+	if configMap.Name != "settings" || !reflect.DeepEqual(configMap.Data, want) {
+		t.Errorf("generateConfigMap() = %+v, want data %v", configMap, want)
+	}
+	for _, literals := range [][]string{{"mode"}, {"=fast"}, {"mode=fast", "mode=slow"}} {
+		if _, err := generateConfigMap("settings", literals); err == nil {
+			t.Errorf("expected an error for --from-literal %q", literals)
+		}
+	}
+}
+
+func TestGenerateDockerRegistrySecret(t *testing.T) {
+	secret, err := generateDockerRegistrySecret("regcred", "registry.local:5000", "alice", "s3cret")
+	if err != nil {
+		t.Fatalf("generateDockerRegistrySecret: %v", err)
+	}
+	if secret.Type != api.SecretTypeDockerConfigJSON {
+		t.Errorf("expected type %s, got %s", api.SecretTypeDockerConfigJSON, secret.Type)
+	}
+	var config api.DockerConfigJSON
+	if err := json.Unmarshal(secret.Data[api.DockerConfigJSONKey], &config); err != nil {
+		t.Fatalf("decoding %s: %v", api.DockerConfigJSONKey, err)
+	}
+	want := api.DockerConfigEntry{Username: "alice", Password: "s3cret", Auth: "YWxpY2U6czNjcmV0"}
+	if got := config.Auths["registry.local:5000"]; got != want {
+		t.Errorf("expected the registry's credentials %+v, got %+v", want, got)
+	}
+	if _, err := generateDockerRegistrySecret("regcred", "registry.local:5000", "alice", ""); err == nil {
+		t.Error("expected an error without a password")
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+func newDeleteCommand(o *globalOptions) *cobra.Command {
+	var labelSelector, fieldSelector, cascade string
+	var all bool
+	var manifests manifestFlags
+
+	cmd := &cobra.Command{
+		Use:   "delete ((pod|node|deployment|service|namespace|pdb|netpol|pv|pvc|apiservice) (NAME | -l SELECTOR | --field-selector SELECTOR | --all) | -f FILENAME | -k DIRECTORY)",
+		Short: "Delete a resource",
+		Example: `  kubectl-lite delete pod web
+  kubectl-lite delete pods -l app=web
+  kubectl-lite delete pods --field-selector status.phase=Failed
+  kubectl-lite delete pods --all
+  kubectl-lite delete deployment web --cascade=foreground
+  kubectl-lite delete -f manifests/ -R
+  kubectl-lite delete -k overlays/prod`,
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: o.completeResourceArgs([]string{"pod", "node", "deployment", "service", "namespace", "pdb", "netpol", "pv", "pvc", "cm", "apiservice"}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if manifests.set() {
+				if len(args) > 0 || labelSelector != "" || fieldSelector != "" || all {
+					return fmt.Errorf("-f and -k cannot be combined with a resource type, -l, --field-selector, or --all")
+				}
+			} else if len(args) == 0 {
+				return fmt.Errorf("specify a resource type, or the manifests to delete with -f or -k")
+			}
+			policy, err := cascadePolicy(cascade)
+			if err != nil {
+				return err
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			if rest, ok := client.(*api.Client); ok {
+				client = rest.WithPropagationPolicy(policy)
+			}
+			if manifests.set() {
+				return runDeleteManifests(cmd.OutOrStdout(), client, o, manifests)
+			}
+
+			resource, err := lookupResource(args[0])
+			if err != nil {
+				return err
+			}
+			resourceType := resource.Name
+			bySelector := labelSelector != "" || fieldSelector != "" || all
+			if len(args) == 2 && bySelector {
+				return fmt.Errorf("a name cannot be combined with -l, --field-selector, or --all")
+			}
+			if len(args) == 1 && !bySelector {
+				return fmt.Errorf("specify a %s name, or select objects with -l, --field-selector, or --all", resourceType)
+			}
+			namespace := o.Namespace()
+
+			if bySelector {
+				if resourceType != "pods" {
+					return fmt.Errorf("deleting by selector is only supported for pods")
+				}
+				deleted, err := client.DeleteCollection(namespace, labelSelector, fieldSelector)
+				if err != nil {
+					return err
+				}
+				for _, pod := range deleted {
+					fmt.Printf("Pod %s/%s deleted\n", pod.Namespace, pod.Name)
+				}
+				if len(deleted) == 0 {
+					fmt.Printf("No pods in namespace %s matched\n", namespace)
+				}
+				return nil
+			}
+
+			resourceName := args[1]
+
+			switch resourceType {
+			case "pods":
+				if err := client.DeletePod(namespace, resourceName); err != nil {
+					return fmt.Errorf("deleting pod %s/%s: %w", namespace, resourceName, err)
+				}
+				fmt.Printf("Pod %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "nodes":
+				if err := client.DeleteNode(resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("Node %s deleted\n", resourceName)
+				return nil
+			case "deployments":
+				if err := client.DeleteDeployment(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("Deployment %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "services":
+				if err := client.DeleteService(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("Service %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "poddisruptionbudgets":
+				if err := client.DeletePodDisruptionBudget(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("PodDisruptionBudget %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "networkpolicies":
+				if err := client.DeleteNetworkPolicy(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("NetworkPolicy %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "configmaps":
+				if err := client.DeleteConfigMap(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("ConfigMap %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "secrets":
+				if err := client.DeleteSecret(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("Secret %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "persistentvolumes":
+				if err := client.DeletePersistentVolume(resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("PersistentVolume %s deleted\n", resourceName)
+				return nil
+			case "persistentvolumeclaims":
+				if err := client.DeletePersistentVolumeClaim(namespace, resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("PersistentVolumeClaim %s/%s deleted\n", namespace, resourceName)
+				return nil
+			case "apiservices":
+				rest, err := o.restClient("delete apiservice")
+				if err != nil {
+					return err
+				}
+				if err := rest.APIServices().Delete(resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("APIService %s deleted\n", resourceName)
+				return nil
+			case "namespaces":
+				if err := client.DeleteNamespace(resourceName); err != nil {
+					return err
+				}
+				fmt.Printf("Namespace %s deleted\n", resourceName)
+				return nil
+			default:
+				return fmt.Errorf("delete is not supported for %s", resourceType)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Delete pods matching this label selector, e.g. app=web,tier!=db")
+	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "Delete pods matching this field selector, e.g. status.phase=Failed")
+	cmd.Flags().BoolVar(&all, "all", false, "Delete all pods in the namespace")
+	manifests.addFlags(cmd, "delete")
+	cmd.Flags().StringVar(&cascade, "cascade", "background", `What happens to the object's dependents: "background" deletes them after the object, "foreground" before it, and "orphan" leaves them`)
+	return cmd
+}
+
+// runDeleteManifests deletes the objects in the manifests in the reverse of the order
+// apply creates them in: pods before their deployments and services, and namespaces
+// last.
+func runDeleteManifests(w io.Writer, client api.Interface, o *globalOptions, manifests manifestFlags) error {
+	objects, err := manifests.decode()
+	if err != nil {
+		return err
+	}
+	for i := len(objects) - 1; i >= 0; i-- {
+		obj := objects[i]
+		namespace, name := obj.Name()
+		if name == "" {
+			return fmt.Errorf("%s in manifest has no name", obj.Kind)
+		}
+		if !manifest.Namespaced(obj.Kind) {
+			if err := deleteObject(client, obj.Kind, "", name); err != nil {
+				return fmt.Errorf("deleting %s %s: %w", obj.Kind, name, err)
+			}
+			fmt.Fprintf(w, "%s %s deleted\n", obj.Kind, name)
+			continue
+		}
+		if namespace == "" {
+			namespace = o.Namespace()
+		}
+		if err := deleteObject(client, obj.Kind, namespace, name); err != nil {
+			return fmt.Errorf("deleting %s %s/%s: %w", obj.Kind, namespace, name, err)
+		}
+		fmt.Fprintf(w, "%s %s/%s deleted\n", obj.Kind, namespace, name)
+	}
+	return nil
+}
+
+func deleteObject(client api.Interface, kind, namespace, name string) error {
+	switch kind {
+	case "Pod":
+		return client.DeletePod(namespace, name)
+	case "Node":
+		return client.DeleteNode(name)
+	case "Namespace":
+		return client.DeleteNamespace(name)
+	case "Deployment":
+		return client.DeleteDeployment(namespace, name)
+	case "Service":
+		return client.DeleteService(namespace, name)
+	}
+	return fmt.Errorf("unsupported kind %q", kind)
+}
+
+// cascadePolicy maps a --cascade value to the server's deletion propagation policy.
+func cascadePolicy(cascade string) (api.DeletionPropagation, error) {
+	switch cascade {
+	case "background":
+		return api.DeletePropagationBackground, nil
+	case "foreground":
+		return api.DeletePropagationForeground, nil
+	case "orphan":
+		return api.DeletePropagationOrphan, nil
+	default:
+		return "", fmt.Errorf(`invalid --cascade %q: must be "background", "foreground", or "orphan"`, cascade)
+	}
+}
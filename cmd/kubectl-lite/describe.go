@@ -0,0 +1,484 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func newDescribeCommand(o *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe (pod|node|deployment|service|namespace|pdb|netpol|pv|pvc) NAME",
+		Short: "Show details of a resource, including its recent events",
+		Example: `  kubectl-lite describe pod web
+  kubectl-lite describe node node1`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: o.completeResourceArgs([]string{"pod", "node", "deployment", "service", "namespace", "pdb", "netpol", "pv", "pvc"}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			return describe(cmd.OutOrStdout(), client, o.Namespace(), args[0], args[1])
+		},
+	}
+}
+
+// describe prints the named object's fields followed by the events about it.
+func describe(w io.Writer, client api.Interface, namespace, resourceType, name string) error {
+	ref, err := parseObjectRef(resourceType+"/"+name, namespace)
+	if err != nil {
+		return err
+	}
+
+	var fields [][2]string
+	var meta api.ObjectMeta
+	var sections func(w io.Writer) error // Printed after the fields, for kinds that have more to show
+	switch ref.Kind {
+	case "Pod":
+		pod, err := client.GetPod(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = pod.ObjectMeta
+		fields = [][2]string{
+			{"Name", pod.Name},
+			{"Namespace", pod.Namespace},
+			{"Labels", formatLabels(pod.Labels)},
+			{"Image", pod.Image},
+			{"Image Pull Policy", orNone(string(pod.ImagePullPolicy))},
+		}
+		if len(pod.ImagePullSecrets) > 0 {
+			names := make([]string, len(pod.ImagePullSecrets))
+			for i, ref := range pod.ImagePullSecrets {
+				names[i] = ref.Name
+			}
+			fields = append(fields, [2]string{"Image Pull Secrets", strings.Join(names, ", ")})
+		}
+		if len(pod.Command) > 0 {
+			fields = append(fields, [2]string{"Command", strings.Join(pod.Command, " ")})
+		}
+		if len(pod.Args) > 0 {
+			fields = append(fields, [2]string{"Args", strings.Join(pod.Args, " ")})
+		}
+		if pod.WorkingDir != "" {
+			fields = append(fields, [2]string{"Working Dir", pod.WorkingDir})
+		}
+		if pod.TTY {
+			fields = append(fields, [2]string{"TTY", "true"})
+		}
+		if sc := pod.SecurityContext; sc != nil {
+			if sc.RunAsUser != nil {
+				fields = append(fields, [2]string{"Run As User", fmt.Sprint(*sc.RunAsUser)})
+			}
+			if sc.Privileged {
+				fields = append(fields, [2]string{"Privileged", "true"})
+			}
+			if sc.ReadOnlyRootFilesystem {
+				fields = append(fields, [2]string{"Read-Only Root Filesystem", "true"})
+			}
+		}
+		fields = append(fields, [][2]string{
+			{"Node", orNone(pod.NodeName)},
+			{"Phase", string(pod.Phase)},
+			{"Timeline", formatPhaseTransitions(pod.PhaseTransitions)},
+			{"Conditions", formatPodConditions(pod.Conditions)},
+			{"Host IP", orNone(pod.HostIP)},
+			{"Pod IP", orNone(pod.PodIP)},
+			{"Volumes", formatVolumes(pod)},
+			{"Requests", formatResourceList(pod.Resources.Requests)},
+			{"Limits", formatResourceList(pod.Resources.Limits)},
+			{"QoS Class", orNone(string(pod.QOSClass))},
+		}...)
+		if len(pod.Overhead) > 0 {
+			fields = append(fields, [2]string{"Overhead", formatResourceList(pod.Overhead)})
+		}
+		if pod.DeletionTimestamp != nil {
+			fields = append(fields, [2]string{"Terminating Since", pod.DeletionTimestamp.Format("2006-01-02T15:04:05Z07:00")})
+		}
+	case "Node":
+		node, err := client.GetNode(name)
+		if err != nil {
+			return err
+		}
+		meta = node.ObjectMeta
+		fields = [][2]string{
+			{"Name", node.Name},
+			{"Address", node.Address},
+			{"Status", string(node.Status)},
+			{"Conditions", formatNodeConditions(node.Conditions)},
+			{"Unschedulable", fmt.Sprintf("%t", node.Unschedulable)},
+		}
+		if node.PendingApproval {
+			fields = append(fields, [2]string{"Pending Approval", "true (approve with kubectl-lite certificate approve node/" + node.Name + ")"})
+		}
+		sections = func(w io.Writer) error { return describeNodeResources(w, client, node) }
+	case "Deployment":
+		d, err := client.GetDeployment(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = d.ObjectMeta
+		fields = [][2]string{
+			{"Name", d.Name},
+			{"Namespace", d.Namespace},
+			{"Selector", formatLabels(d.Selector)},
+			{"Replicas", fmt.Sprintf("%d desired", d.Replicas)},
+			{"Pod Template Labels", formatLabels(d.Template.Labels)},
+			{"Pod Template Image", d.Template.Image},
+		}
+		if len(d.Template.Annotations) > 0 {
+			fields = append(fields, [2]string{"Pod Template Annotations", formatLabels(d.Template.Annotations)})
+		}
+	case "Service":
+		svc, err := client.GetService(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = svc.ObjectMeta
+		var ports []string
+		for _, p := range svc.Ports {
+			ports = append(ports, fmt.Sprintf("%d->%d/%s", p.Port, p.TargetPort, p.Protocol))
+		}
+		fields = [][2]string{
+			{"Name", svc.Name},
+			{"Namespace", svc.Namespace},
+			{"Type", string(svc.Type)},
+			{"Selector", formatLabels(svc.Selector)},
+			{"Ports", orNone(strings.Join(ports, ", "))},
+		}
+	case "PodDisruptionBudget":
+		pdb, err := client.GetPodDisruptionBudget(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = pdb.ObjectMeta
+		fields = [][2]string{
+			{"Name", pdb.Name},
+			{"Namespace", pdb.Namespace},
+			{"Selector", formatLabels(pdb.Selector)},
+		}
+		if pdb.MinAvailable != nil {
+			fields = append(fields, [2]string{"Min available", fmt.Sprintf("%d", *pdb.MinAvailable)})
+		}
+		if pdb.MaxUnavailable != nil {
+			fields = append(fields, [2]string{"Max unavailable", fmt.Sprintf("%d", *pdb.MaxUnavailable)})
+		}
+		fields = append(fields,
+			[2]string{"Allowed disruptions", fmt.Sprintf("%d", pdb.Status.DisruptionsAllowed)},
+			[2]string{"Pods", fmt.Sprintf("%d healthy, %d desired, %d expected", pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy, pdb.Status.ExpectedPods)},
+		)
+	case "NetworkPolicy":
+		policy, err := client.GetNetworkPolicy(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = policy.ObjectMeta
+		fields = [][2]string{
+			{"Name", policy.Name},
+			{"Namespace", policy.Namespace},
+			{"Pod Selector", formatPodSelector(policy.PodSelector)},
+			{"Allowing ingress", formatIngressRules(policy.Ingress)},
+		}
+	case "ConfigMap":
+		configMap, err := client.GetConfigMap(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = configMap.ObjectMeta
+		fields = [][2]string{
+			{"Name", configMap.Name},
+			{"Namespace", configMap.Namespace},
+			{"Labels", formatLabels(configMap.Labels)},
+		}
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fields = append(fields, [2]string{"Data " + key, configMap.Data[key]})
+		}
+	case "Secret":
+		secret, err := client.GetSecret(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = secret.ObjectMeta
+		fields = [][2]string{
+			{"Name", secret.Name},
+			{"Namespace", secret.Namespace},
+			{"Labels", formatLabels(secret.Labels)},
+			{"Type", string(secret.Type)},
+		}
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		// Like kubectl, only the sizes of the values, which are secret.
+		for _, key := range keys {
+			fields = append(fields, [2]string{"Data " + key, fmt.Sprintf("%d bytes", len(secret.Data[key]))})
+		}
+	case "PersistentVolume":
+		pv, err := client.GetPersistentVolume(name)
+		if err != nil {
+			return err
+		}
+		meta = pv.ObjectMeta
+		claim := "<none>"
+		if pv.ClaimRef != nil {
+			claim = pv.ClaimRef.Namespace + "/" + pv.ClaimRef.Name
+		}
+		source := "<none>"
+		if pv.HostPath != nil {
+			source = "HostPath " + pv.HostPath.Path
+		}
+		fields = [][2]string{
+			{"Name", pv.Name},
+			{"Capacity", pv.Capacity},
+			{"Access Modes", formatAccessModes(pv.AccessModes)},
+			{"Reclaim Policy", string(pv.ReclaimPolicy)},
+			{"Status", string(pv.Phase)},
+			{"Claim", claim},
+			{"Source", source},
+		}
+	case "PersistentVolumeClaim":
+		pvc, err := client.GetPersistentVolumeClaim(namespace, name)
+		if err != nil {
+			return err
+		}
+		meta = pvc.ObjectMeta
+		fields = [][2]string{
+			{"Name", pvc.Name},
+			{"Namespace", pvc.Namespace},
+			{"Status", string(pvc.Phase)},
+			{"Volume", orNone(pvc.VolumeName)},
+			{"Requested", pvc.Storage},
+			{"Capacity", orNone(pvc.Capacity)},
+			{"Access Modes", formatAccessModes(pvc.AccessModes)},
+		}
+	case "Namespace":
+		ns, err := client.GetNamespace(name)
+		if err != nil {
+			return err
+		}
+		meta = ns.ObjectMeta
+		fields = [][2]string{
+			{"Name", ns.Name},
+			{"Status", string(ns.Phase)},
+		}
+	default:
+		return fmt.Errorf("describe is not supported for %s", resourceType)
+	}
+
+	fields = append(fields, [2]string{"Created", fmt.Sprintf("%s (%s ago)", meta.CreationTimestamp.Format(time.RFC3339), translateTimestampSince(meta.CreationTimestamp))})
+	if len(meta.Annotations) > 0 {
+		fields = append(fields, [2]string{"Annotations", formatLabels(meta.Annotations)})
+	}
+	for _, owner := range meta.OwnerReferences {
+		if owner.Controller {
+			fields = append(fields, [2]string{"Controlled By", owner.Kind + "/" + owner.Name})
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", f[0], f[1])
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if sections != nil {
+		if err := sections(w); err != nil {
+			return err
+		}
+	}
+
+	events, err := eventsFor(client, namespace, &ref)
+	if err != nil {
+		// The object itself was found; don't fail the whole describe over its events.
+		fmt.Fprintf(w, "Events:\t<unavailable: %v>\n", err)
+		return nil
+	}
+	return printEventTable(w, events)
+}
+
+// formatLabels renders labels as sorted k=v pairs, or <none>.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// formatPodConditions renders conditions as "Type=Status (Reason)", comma separated.
+func formatPodConditions(conditions []api.PodCondition) string {
+	if len(conditions) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		part := string(cond.Type) + "=" + string(cond.Status)
+		if cond.Reason != "" {
+			part += " (" + cond.Reason + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatPhaseTransitions renders a pod's phase history as a timeline of each phase
+// and how long the pod spent in it, ending with the current phase: "Pending 1.2s ->
+// Scheduled 300ms -> Running".
+func formatPhaseTransitions(transitions []api.PodPhaseTransition) string {
+	if len(transitions) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, len(transitions))
+	for i, t := range transitions {
+		parts[i] = string(t.Phase)
+		if i+1 < len(transitions) {
+			parts[i] += " " + formatPhaseDuration(transitions[i+1].Time.Sub(t.Time))
+		}
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// formatPhaseDuration renders how long a pod spent in a phase: to the millisecond
+// under a second, since control loops often take less, to a tenth of a second under
+// a minute, and in the short form of ages beyond.
+func formatPhaseDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return shortDuration(d)
+}
+
+// formatNodeConditions renders conditions as formatPodConditions does.
+func formatNodeConditions(conditions []api.NodeCondition) string {
+	pods := make([]api.PodCondition, len(conditions))
+	for i, cond := range conditions {
+		pods[i] = api.PodCondition{Type: api.PodConditionType(cond.Type), Status: cond.Status, Reason: cond.Reason}
+	}
+	return formatPodConditions(pods)
+}
+
+// formatVolumes renders each volume as "name (source) at mountPath", comma separated.
+func formatVolumes(pod *api.Pod) string {
+	if len(pod.Volumes) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(pod.Volumes))
+	for _, vol := range pod.Volumes {
+		part := vol.Name
+		switch {
+		case vol.EmptyDir != nil:
+			part += " (emptyDir)"
+		case vol.HostPath != nil:
+			part += " (hostPath " + vol.HostPath.Path + ")"
+		case vol.PersistentVolumeClaim != nil:
+			part += " (persistentVolumeClaim " + vol.PersistentVolumeClaim.ClaimName + ")"
+		}
+		for _, m := range pod.VolumeMounts {
+			if m.Name != vol.Name {
+				continue
+			}
+			part += " at " + m.MountPath
+			if m.ReadOnly {
+				part += " (ro)"
+			}
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatResourceList renders resource quantities as "name=quantity", comma separated.
+func formatResourceList(list api.ResourceList) string {
+	pairs := make([]string, 0, len(list))
+	for name, quantity := range list {
+		pairs = append(pairs, string(name)+"="+quantity)
+	}
+	sort.Strings(pairs)
+	return orNone(strings.Join(pairs, ", "))
+}
+
+func formatAccessModes(modes []api.PersistentVolumeAccessMode) string {
+	parts := make([]string, 0, len(modes))
+	for _, m := range modes {
+		parts = append(parts, string(m))
+	}
+	return orNone(strings.Join(parts, ", "))
+}
+
+// formatPodSelector renders a network policy's pod selector, which selects every pod
+// in the namespace when empty.
+func formatPodSelector(selector map[string]string) string {
+	if len(selector) == 0 {
+		return "<none> (all pods in the namespace)"
+	}
+	return formatLabels(selector)
+}
+
+// formatIngressRules renders a network policy's rules as "from PEERS on PORTS",
+// separated by "; ".
+func formatIngressRules(rules []api.NetworkPolicyIngressRule) string {
+	if len(rules) == 0 {
+		return "<none> (the selected pods accept no connections)"
+	}
+	parts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		from := "anywhere"
+		if len(rule.From) > 0 {
+			peers := make([]string, 0, len(rule.From))
+			for _, peer := range rule.From {
+				pods := "all pods"
+				if len(peer.PodSelector) > 0 {
+					pods = "pods " + formatLabels(peer.PodSelector)
+				}
+				switch {
+				case peer.NamespaceSelector == nil:
+					peers = append(peers, pods)
+				case len(peer.NamespaceSelector) == 0:
+					peers = append(peers, pods+" in all namespaces")
+				default:
+					peers = append(peers, pods+" in namespaces "+formatLabels(peer.NamespaceSelector))
+				}
+			}
+			from = strings.Join(peers, ", ")
+		}
+		on := "any port"
+		if len(rule.Ports) > 0 {
+			ports := make([]string, 0, len(rule.Ports))
+			for _, port := range rule.Ports {
+				ports = append(ports, strconv.Itoa(port))
+			}
+			on = "ports " + strings.Join(ports, ", ")
+		}
+		parts = append(parts, "from "+from+" on "+on)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
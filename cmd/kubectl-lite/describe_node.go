@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// allocatedResources are the resources describe node sums the pods' requests and
+// limits of.
+var allocatedResources = []api.ResourceName{api.ResourceCPU, api.ResourceMemory}
+
+// describeNodeResources prints the node's capacity and allocatable, the pods running
+// on it with what each requests and is limited to, and the totals, each as a share
+// of what the node has allocatable, as kubectl describe node does. The pods come from
+// a list by spec.nodeName, so what is shown is the API server's view, not the
+// scheduler's cache.
+func describeNodeResources(w io.Writer, client api.Interface, node *api.Node) error {
+	printResourceList(w, "Capacity", node.Capacity)
+	printResourceList(w, "Allocatable", node.Allocatable)
+
+	pods, err := client.ListPodsMatching(api.NamespaceAll, "", "spec.nodeName="+node.Name)
+	if err != nil {
+		fmt.Fprintf(w, "Non-terminated Pods:\t<unavailable: %v>\n", err)
+		return nil
+	}
+	running := pods[:0]
+	for _, pod := range pods {
+		if !api.IsPodGone(&pod) && pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed {
+			running = append(running, pod)
+		}
+	}
+	sort.Slice(running, func(i, j int) bool {
+		if running[i].Namespace != running[j].Namespace {
+			return running[i].Namespace < running[j].Namespace
+		}
+		return running[i].Name < running[j].Name
+	})
+
+	allocatable := make(map[api.ResourceName]int64)
+	for name, quantity := range node.Allocatable {
+		if v, err := api.ParseResourceQuantity(name, quantity); err == nil {
+			allocatable[name] = v
+		}
+	}
+	share := func(name api.ResourceName, v int64) string {
+		s := api.FormatResourceQuantity(name, v)
+		if total, ok := allocatable[name]; ok && total > 0 {
+			s += fmt.Sprintf(" (%d%%)", v*100/total)
+		}
+		return s
+	}
+
+	fmt.Fprintf(w, "Non-terminated Pods:\t(%d in total)\n", len(running))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  Namespace\tName\tCPU Requests\tCPU Limits\tMemory Requests\tMemory Limits\tAge")
+	requested, limited := make(map[api.ResourceName]int64), make(map[api.ResourceName]int64)
+	for i := range running {
+		pod := &running[i]
+		requests, limits := api.PodRequests(pod), podLimits(pod)
+		row := []interface{}{pod.Namespace, pod.Name}
+		for _, name := range allocatedResources {
+			requested[name] += requests[name]
+			limited[name] += limits[name]
+			row = append(row, share(name, requests[name]), share(name, limits[name]))
+		}
+		row = append(row, translateTimestampSince(pod.CreationTimestamp))
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\n", row...)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "Allocated resources:")
+	fmt.Fprintln(w, "  (Total limits may be over 100 percent, i.e., overcommitted.)")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  Resource\tRequests\tLimits")
+	fmt.Fprintln(tw, "  --------\t--------\t------")
+	for _, name := range allocatedResources {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, share(name, requested[name]), share(name, limited[name]))
+	}
+	fmt.Fprintf(tw, "  %s\t%s\n", api.ResourcePods, share(api.ResourcePods, int64(len(running))))
+	return tw.Flush()
+}
+
+// printResourceList prints list under title, a resource to a line, or <none>.
+func printResourceList(w io.Writer, title string, list api.ResourceList) {
+	if len(list) == 0 {
+		fmt.Fprintf(w, "%s:\t<none>\n", title)
+		return
+	}
+	fmt.Fprintf(w, "%s:\n", title)
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, name := range []api.ResourceName{api.ResourceCPU, api.ResourceMemory, api.ResourcePods} {
+		if quantity, ok := list[name]; ok {
+			fmt.Fprintf(tw, "  %s:\t%s\n", name, quantity)
+		}
+	}
+	tw.Flush()
+}
+
+// podLimits returns the most of each resource pod may use, with its overhead, for the
+// resources it has limits on.
+func podLimits(pod *api.Pod) map[api.ResourceName]int64 {
+	limits := make(map[api.ResourceName]int64)
+	for name, quantity := range pod.Resources.Limits {
+		if v, err := api.ParseResourceQuantity(name, quantity); err == nil {
+			limits[name] = v
+		}
+	}
+	for name, quantity := range pod.Overhead {
+		if v, err := api.ParseResourceQuantity(name, quantity); err == nil && limits[name] > 0 {
+			limits[name] += v
+		}
+	}
+	return limits
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+func TestDescribeNodeAllocatedResources(t *testing.T) {
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady,
+			Capacity:    api.ResourceList{api.ResourceCPU: "2", api.ResourceMemory: "1Gi", api.ResourcePods: "110"},
+			Allocatable: api.ResourceList{api.ResourceCPU: "2", api.ResourceMemory: "1Gi", api.ResourcePods: "110"}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, NodeName: "node1", Phase: api.PodRunning,
+			Resources: api.ResourceRequirements{
+				Requests: api.ResourceList{api.ResourceCPU: "400m", api.ResourceMemory: "256Mi"},
+				Limits:   api.ResourceList{api.ResourceCPU: "1"},
+			},
+			Overhead: api.ResourceList{api.ResourceCPU: "100m"}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "team"}, NodeName: "node1", Phase: api.PodRunning,
+			Resources: api.ResourceRequirements{Requests: api.ResourceList{api.ResourceMemory: "256Mi"}}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "done", Namespace: "default"}, NodeName: "node1", Phase: api.PodSucceeded,
+			Resources: api.ResourceRequirements{Requests: api.ResourceList{api.ResourceCPU: "1"}}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "elsewhere", Namespace: "default"}, NodeName: "node2", Phase: api.PodRunning},
+	)
+	var out bytes.Buffer
+	if err := describe(&out, client, "default", "node", "node1"); err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Capacity:\n  cpu:    2\n  memory: 1Gi\n  pods:   110\n",
+		"Non-terminated Pods:\t(2 in total)",
+		// Requests and limits include the overhead.
+		"  default    web   500m (25%)    1100m (55%)  256Mi (25%)      0 (0%)",
+		"  team       db    0 (0%)        0 (0%)       256Mi (25%)      0 (0%)",
+		"  cpu       500m (25%)   1100m (55%)\n",
+		"  memory    512Mi (50%)  0 (0%)\n",
+		"  pods      2 (1%)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "done") || strings.Contains(got, "elsewhere") {
+		t.Errorf("expected only the node's running pods, got:\n%s", got)
+	}
+}
+
+func TestDescribePodTimeline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transitions := []api.PodPhaseTransition{
+		{Phase: api.PodPending, Time: start},
+		{Phase: api.PodScheduled, Time: start.Add(1500 * time.Millisecond)},
+		{Phase: api.PodRunning, Time: start.Add(1800 * time.Millisecond)},
+	}
+	if got := formatPhaseTransitions(transitions); got != "Pending 1.5s -> Scheduled 300ms -> Running" {
+		t.Errorf("timeline = %q", got)
+	}
+
+	// The store records the phase a pod is created in.
+	client := fake.NewClient(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "nginx", Phase: api.PodPending})
+	var out bytes.Buffer
+	if err := describe(&out, client, "default", "pod", "web"); err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if !regexp.MustCompile(`(?m)^Timeline:\s+Pending$`).MatchString(out.String()) {
+		t.Errorf("expected a timeline in the output, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/jsonpath"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/manifest"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+func newDiffCommand(o *globalOptions) *cobra.Command {
+	var manifests manifestFlags
+	cmd := &cobra.Command{
+		Use:   "diff (-f FILENAME | -k DIRECTORY)",
+		Short: "Show the changes applying a manifest would make",
+		Long: `Diff the live objects against the objects a manifest would produce.
+
+Each object in the manifest is sent to the API server as a dry-run create or
+update, so the server's defaulting and validation are reflected in the output.
+Fields set in the manifest replace the live values; fields left out are kept.
+
+Exits with status 0 if there are no differences, 1 if there are, and above 1 on error.`,
+		Example: "  kubectl-lite diff -f web.yaml",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changed, err := runDiff(cmd.OutOrStdout(), o, manifests)
+			if err != nil {
+				return &exitCodeError{code: 2, err: err}
+			}
+			if changed {
+				cmd.SilenceErrors = true
+				return &exitCodeError{code: 1}
+			}
+			return nil
+		},
+	}
+	manifests.addFlags(cmd, "diff")
+	return cmd
+}
+
+// exitCodeError makes kubectl-lite exit with code instead of the usual 1. err, if
+// set, is reported as usual.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// runDiff prints the diff for every object in the manifests and reports
+// whether any object would change.
+func runDiff(w io.Writer, o *globalOptions, manifests manifestFlags) (bool, error) {
+	objects, err := manifests.decode()
+	if err != nil {
+		return false, err
+	}
+	client, err := o.Client()
+	if err != nil {
+		return false, err
+	}
+	rest, ok := client.(*api.Client)
+	if !ok {
+		return false, fmt.Errorf("diff needs a client that supports server-side dry run")
+	}
+
+	changed := false
+	for _, obj := range objects {
+		namespace, name := obj.Name()
+		header := fmt.Sprintf("%s/%s", obj.Kind, name)
+		if manifest.Namespaced(obj.Kind) {
+			if namespace == "" {
+				namespace = o.Namespace()
+			}
+			header = fmt.Sprintf("%s/%s/%s", obj.Kind, namespace, name)
+		}
+		live, desired, err := dryRunApply(rest, rest.DryRun(), obj, namespace)
+		if err != nil {
+			return false, err
+		}
+		d, err := diffObjects(header, live, desired)
+		if err != nil {
+			return false, err
+		}
+		if d != "" {
+			changed = true
+			fmt.Fprint(w, d)
+		}
+	}
+	return changed, nil
+}
+
+// dryRunApply returns the live object (nil if it doesn't exist) and the object the
+// server would store if the manifest object were applied in namespace.
+func dryRunApply(client, dryRun api.Interface, obj manifest.Object, namespace string) (live, desired interface{}, err error) {
+	_, name := obj.Name()
+	if manifest.Namespaced(obj.Kind) {
+		obj.Fields["namespace"] = namespace
+	}
+	if name == "" {
+		return nil, nil, fmt.Errorf("%s in manifest has no name", obj.Kind)
+	}
+
+	live, err = getObject(client, obj.Kind, namespace, name)
+	if err != nil {
+		if !api.IsNotFound(err) {
+			return nil, nil, err
+		}
+		live = nil
+	}
+
+	var target map[string]interface{}
+	if live == nil {
+		target = obj.Fields
+	} else {
+		liveFields, err := jsonpath.ToGeneric(live)
+		if err != nil {
+			return nil, nil, err
+		}
+		target = mergeObject(liveFields.(map[string]interface{}), obj.Fields)
+	}
+	typed, err := fromGeneric(obj.Kind, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if live == nil {
+		desired, err = createObject(dryRun, namespace, typed)
+	} else {
+		desired, err = updateObject(dryRun, typed)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("dry run of %s %s: %w", obj.Kind, name, err)
+	}
+	return live, desired, nil
+}
+
+// mergeObject overlays patch onto base following JSON merge patch rules: nested objects
+// are merged, null removes a field, and anything else replaces the base value.
+func mergeObject(base, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		patchMap, patchIsMap := v.(map[string]interface{})
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		if patchIsMap && baseIsMap {
+			merged[k] = mergeObject(baseMap, patchMap)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+func fromGeneric(kind string, fields map[string]interface{}) (interface{}, error) {
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	switch kind {
+	case "Pod":
+		obj = &api.Pod{}
+	case "Node":
+		obj = &api.Node{}
+	case "Namespace":
+		obj = &api.Namespace{}
+	case "Deployment":
+		obj = &api.Deployment{}
+	case "Service":
+		obj = &api.Service{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", kind, err)
+	}
+	return obj, nil
+}
+
+func getObject(client api.Interface, kind, namespace, name string) (interface{}, error) {
+	switch kind {
+	case "Pod":
+		return client.GetPod(namespace, name)
+	case "Node":
+		return client.GetNode(name)
+	case "Namespace":
+		return client.GetNamespace(name)
+	case "Deployment":
+		return client.GetDeployment(namespace, name)
+	case "Service":
+		return client.GetService(namespace, name)
+	}
+	return nil, fmt.Errorf("unsupported kind %q", kind)
+}
+
+func createObject(client api.Interface, namespace string, obj interface{}) (interface{}, error) {
+	switch o := obj.(type) {
+	case *api.Pod:
+		return client.CreatePod(namespace, o)
+	case *api.Node:
+		return client.CreateNode(o)
+	case *api.Namespace:
+		return client.CreateNamespace(o)
+	case *api.Deployment:
+		return client.CreateDeployment(namespace, o)
+	case *api.Service:
+		return client.CreateService(namespace, o)
+	}
+	return nil, fmt.Errorf("unsupported object type %T", obj)
+}
+
+// updateObject sends obj as an update and returns it as refreshed by the server.
+func updateObject(client api.Interface, obj interface{}) (interface{}, error) {
+	var err error
+	switch o := obj.(type) {
+	case *api.Pod:
+		err = client.UpdatePod(o)
+	case *api.Node:
+		err = client.UpdateNode(o)
+	case *api.Namespace:
+		err = client.UpdateNamespace(o)
+	case *api.Deployment:
+		err = client.UpdateDeployment(o)
+	case *api.Service:
+		err = client.UpdateService(o)
+	default:
+		return nil, fmt.Errorf("unsupported object type %T", obj)
+	}
+	return obj, err
+}
+
+// diffObjects renders live and desired as YAML and returns their unified diff, or ""
+// if they are identical. A nil live object diffs as empty.
+func diffObjects(name string, live, desired interface{}) (string, error) {
+	var liveYAML string
+	if live != nil {
+		var err error
+		if liveYAML, err = toYAML(live); err != nil {
+			return "", err
+		}
+	}
+	desiredYAML, err := toYAML(desired)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff("live/"+name, "merged/"+name, splitLines(liveYAML), splitLines(desiredYAML)), nil
+}
+
+// serverManagedFields are set by the server on every write. They are left out of diffs
+// because a manifest can't change them, and a dry-run create doesn't fill them in.
+var serverManagedFields = []string{"uid", "creationTimestamp", "resourceVersion", "managedFields"}
+
+func toYAML(obj interface{}) (string, error) {
+	generic, err := jsonpath.ToGeneric(obj)
+	if err != nil {
+		return "", err
+	}
+	if fields, ok := generic.(map[string]interface{}); ok {
+		for _, f := range serverManagedFields {
+			delete(fields, f)
+		}
+	}
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return "", fmt.Errorf("rendering YAML: %w", err)
+	}
+	return out.String(), nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// unifiedDiff returns a diff -u style comparison of a and b, or "" if they are equal.
+func unifiedDiff(fromName, toName string, a, b []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if strings.TrimSuffix(a[i], "\n") == strings.TrimSuffix(b[j], "\n") {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type edit struct {
+		op   byte // ' ', '-', or '+'
+		line string
+		ai   int // Index of the line in a (for ' ' and '-')
+		bi   int // Index of the line in b (for ' ' and '+')
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && strings.TrimSuffix(a[i], "\n") == strings.TrimSuffix(b[j], "\n"):
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	for start := 0; start < len(edits); {
+		// Find the next change, then extend the hunk while changes are close together.
+		first := start
+		for first < len(edits) && edits[first].op == ' ' {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		hunkStart := max(first-diffContext, start)
+		end := first
+		for k := first; k < len(edits); k++ {
+			if edits[k].op != ' ' {
+				end = k
+			} else if k-end > 2*diffContext {
+				break
+			}
+		}
+		hunkEnd := min(end+diffContext+1, len(edits))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		var aCount, bCount int
+		for _, e := range edits[hunkStart:hunkEnd] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		aStart, bStart := edits[hunkStart].ai+1, edits[hunkStart].bi+1
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, e := range edits[hunkStart:hunkEnd] {
+			out.WriteByte(e.op)
+			out.WriteString(strings.TrimSuffix(e.line, "\n"))
+			out.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return out.String()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeObject(t *testing.T) {
+	base := map[string]interface{}{
+		"name":     "web",
+		"image":    "nginx:1.0",
+		"phase":    "Running",
+		"labels":   map[string]interface{}{"app": "web", "tier": "frontend"},
+		"nodeName": "node1",
+	}
+	patch := map[string]interface{}{
+		"image":    "nginx:1.1",
+		"labels":   map[string]interface{}{"tier": "backend"},
+		"nodeName": nil,
+	}
+	want := map[string]interface{}{
+		"name":   "web",
+		"image":  "nginx:1.1",
+		"phase":  "Running",
+		"labels": map[string]interface{}{"app": "web", "tier": "backend"},
+	}
+	if got := mergeObject(base, patch); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeObject() = %v, want %v", got, want)
+	}
+	if base["image"] != "nginx:1.0" {
+		t.Errorf("mergeObject modified its base argument")
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    []string{"a\n", "b\n"},
+			b:    []string{"a\n", "b\n"},
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    []string{"image: nginx:1.0\n", "name: web\n"},
+			b:    []string{"image: nginx:1.1\n", "name: web\n"},
+			want: "--- live\n+++ merged\n@@ -1,2 +1,2 @@\n-image: nginx:1.0\n+image: nginx:1.1\n name: web\n",
+		},
+		{
+			name: "new object",
+			a:    nil,
+			b:    []string{"name: web\n"},
+			want: "--- live\n+++ merged\n@@ -0,0 +1,1 @@\n+name: web\n",
+		},
+		{
+			name: "distant changes get separate hunks",
+			a:    []string{"1\n", "2\n", "3\n", "4\n", "5\n", "6\n", "7\n", "8\n", "9\n", "10\n"},
+			b:    []string{"one\n", "2\n", "3\n", "4\n", "5\n", "6\n", "7\n", "8\n", "9\n", "ten\n"},
+			want: "--- live\n+++ merged\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("live", "merged", tt.a, tt.b); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
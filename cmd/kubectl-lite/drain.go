@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func newCordonCommand(o *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:               "cordon NODE",
+		Short:             "Mark a node as unschedulable",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeNodeArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			return setUnschedulable(cmd.OutOrStdout(), client, args[0], true)
+		},
+	}
+}
+
+func newUncordonCommand(o *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:               "uncordon NODE",
+		Short:             "Mark a node as schedulable again",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeNodeArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			return setUnschedulable(cmd.OutOrStdout(), client, args[0], false)
+		},
+	}
+}
+
+func newDrainCommand(o *globalOptions) *cobra.Command {
+	var timeout, interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "drain NODE",
+		Short: "Cordon a node and evict its pods, respecting pod disruption budgets",
+		Long: `Cordon a node and evict its pods, respecting pod disruption budgets.
+
+Evictions a budget refuses are retried every --interval until they succeed or
+--timeout expires.`,
+		Example:           "  kubectl-lite drain n1 --timeout=2m",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeNodeArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			return drainNode(cmd.OutOrStdout(), client, args[0], timeout, interval)
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "How long to keep retrying blocked evictions; 0 means forever")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "How long to wait between eviction attempts")
+	return cmd
+}
+
+func (o *globalOptions) completeNodeArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return o.resourceNames("nodes"), cobra.ShellCompDirectiveNoFileComp
+}
+
+// setUnschedulable cordons or uncordons a node.
+func setUnschedulable(w io.Writer, client api.Interface, nodeName string, unschedulable bool) error {
+	node, err := client.GetNode(nodeName)
+	if err != nil {
+		return err
+	}
+	verb := "cordoned"
+	if !unschedulable {
+		verb = "uncordoned"
+	}
+	if node.Unschedulable == unschedulable {
+		fmt.Fprintf(w, "Node %s already %s\n", nodeName, verb)
+		return nil
+	}
+	node.Unschedulable = unschedulable
+	if err := client.UpdateNode(node); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Node %s %s\n", nodeName, verb)
+	return nil
+}
+
+// drainNode cordons nodeName and evicts every pod bound to it that is still running or
+// waiting to run. Evictions refused by a disruption budget are retried every interval
+// until timeout (forever if timeout is 0).
+func drainNode(w io.Writer, client api.Interface, nodeName string, timeout, interval time.Duration) error {
+	if err := setUnschedulable(w, client, nodeName, true); err != nil {
+		return err
+	}
+
+	pods, err := client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		return fmt.Errorf("listing pods on node %s: %w", nodeName, err)
+	}
+	var remaining []api.Pod
+	for _, pod := range pods {
+		if pod.NodeName != nodeName || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Phase == api.PodSucceeded || pod.Phase == api.PodFailed {
+			continue
+		}
+		remaining = append(remaining, pod)
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	blocked := map[string]bool{} // Pods whose refusal was already reported
+	for {
+		var retry []api.Pod
+		for _, pod := range remaining {
+			err := client.EvictPod(pod.Namespace, pod.Name)
+			switch {
+			case err == nil:
+				fmt.Fprintf(w, "Pod %s/%s evicted\n", pod.Namespace, pod.Name)
+			case api.IsTooManyRequests(err):
+				key := pod.Namespace + "/" + pod.Name
+				if !blocked[key] {
+					var statusErr *api.StatusError
+					if errors.As(err, &statusErr) {
+						err = errors.New(statusErr.Message)
+					}
+					fmt.Fprintf(w, "Error when evicting pod %s (will retry after %s): %v\n", key, interval, err)
+					blocked[key] = true
+				}
+				retry = append(retry, pod)
+			case api.IsNotFound(err):
+				// Deleted by someone else since we listed it.
+			default:
+				return err
+			}
+		}
+		if len(retry) == 0 {
+			fmt.Fprintf(w, "Node %s drained\n", nodeName)
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("drain did not complete within %s: %d pod(s) still blocked by disruption budgets", timeout, len(retry))
+		}
+		remaining = retry
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+func TestDrainNode(t *testing.T) {
+	one := 1
+	webLabels := map[string]string{"app": "web"}
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "n1"}, Status: api.NodeReady},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web-1", Labels: webLabels}, NodeName: "n1", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web-2", Labels: webLabels}, NodeName: "n1", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "batch"}, NodeName: "n1", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "done"}, NodeName: "n1", Phase: api.PodSucceeded},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "elsewhere"}, NodeName: "n2", Phase: api.PodRunning},
+		&api.PodDisruptionBudget{ObjectMeta: api.ObjectMeta{Name: "web"}, Selector: webLabels, MinAvailable: &one},
+	)
+
+	var out bytes.Buffer
+	err := drainNode(&out, client, "n1", 30*time.Millisecond, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "1 pod(s) still blocked") {
+		t.Fatalf("expected the drain to time out on one blocked pod, got %v\n%s", err, out.String())
+	}
+
+	node, _ := client.GetNode("n1")
+	if !node.Unschedulable {
+		t.Errorf("expected node n1 to be cordoned")
+	}
+	terminating := map[string]bool{}
+	pods, _ := client.ListPods(DefaultNamespace, "")
+	for _, pod := range pods {
+		terminating[pod.Name] = pod.DeletionTimestamp != nil
+	}
+	if !terminating["batch"] || terminating["done"] || terminating["elsewhere"] {
+		t.Errorf("unexpected evictions: %v", terminating)
+	}
+	if terminating["web-1"] == terminating["web-2"] {
+		t.Errorf("expected exactly one web pod to be evicted under minAvailable=1, got %v", terminating)
+	}
+
+	// Once the budget is gone the remaining pod can be evicted.
+	if err := client.DeletePodDisruptionBudget(DefaultNamespace, "web"); err != nil {
+		t.Fatalf("DeletePodDisruptionBudget: %v", err)
+	}
+	out.Reset()
+	if err := drainNode(&out, client, "n1", time.Second, 10*time.Millisecond); err != nil {
+		t.Fatalf("second drain: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Node n1 drained") {
+		t.Errorf("expected the drain to finish, got:\n%s", out.String())
+	}
+}
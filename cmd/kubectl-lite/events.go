@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// eventNamespace is where events about cluster-scoped objects such as nodes live.
+const eventNamespace = DefaultNamespace
+
+// lookupResource resolves a resource type named on the command line by any of the
+// names api.Resources gives it (plural, singular, short name, or kind), so that
+// "po", "pod", and "pods" all mean the same thing, as they do for kubectl.
+func lookupResource(name string) (api.ResourceType, error) {
+	r, ok := api.LookupResource(name)
+	if !ok {
+		return api.ResourceType{}, fmt.Errorf("unknown resource type %q", name)
+	}
+	return r, nil
+}
+
+// parseObjectRef parses a "--for" value such as "pod/web" into an object reference
+// in namespace. Cluster-scoped kinds ignore namespace.
+func parseObjectRef(s, namespace string) (api.ObjectReference, error) {
+	resource, name, ok := strings.Cut(s, "/")
+	if !ok || name == "" {
+		return api.ObjectReference{}, fmt.Errorf("expected <resource>/<name>, got %q", s)
+	}
+	r, err := lookupResource(resource)
+	if err != nil {
+		return api.ObjectReference{}, err
+	}
+	ref := api.ObjectReference{Kind: r.Kind, Name: name}
+	if r.Namespaced {
+		ref.Namespace = namespace
+	}
+	return ref, nil
+}
+
+// eventsFor lists the events in namespace, or only those about ref if it is non-nil,
+// oldest first.
+func eventsFor(client api.Interface, namespace string, ref *api.ObjectReference) ([]api.Event, error) {
+	if ref != nil && ref.Namespace == "" {
+		namespace = eventNamespace
+	}
+	events, err := client.ListEvents(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ref != nil {
+		var matched []api.Event
+		for _, e := range events {
+			if e.InvolvedObject == *ref {
+				matched = append(matched, e)
+			}
+		}
+		events = matched
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(events[j].LastTimestamp)
+	})
+	return events, nil
+}
+
+// printEventTable writes events as the timeline shown at the end of describe output.
+func printEventTable(w io.Writer, events []api.Event) error {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "Events:\t<none>")
+		return nil
+	}
+	fmt.Fprintln(w, "Events:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TYPE\tREASON\tAGE\tFROM\tMESSAGE")
+	for _, e := range events {
+		age := translateTimestampSince(e.LastTimestamp)
+		if e.Count > 1 {
+			age = fmt.Sprintf("%s (x%d over %s)", age, e.Count, translateTimestampSince(e.FirstTimestamp))
+		}
+		from := e.Source.Component
+		if e.Source.Host != "" {
+			from += ", " + e.Source.Host
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", e.Type, e.Reason, age, from, e.Message)
+	}
+	return tw.Flush()
+}
+
+// translateTimestampSince renders the time elapsed since t in the short form kubectl
+// uses for ages: 45s, 12m, 3h, 5d.
+func translateTimestampSince(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return shortDuration(time.Since(t))
+}
+
+// shortDuration renders d in the short form of ages: 45s, 12m, 3h, 5d.
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
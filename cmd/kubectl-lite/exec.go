@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+	"github.com/spf13/cobra"
+)
+
+func newExecCommand(o *globalOptions) *cobra.Command {
+	var stdin, tty bool
+	cmd := &cobra.Command{
+		Use:   "exec POD [-i] [-t] -- COMMAND [ARGS...]",
+		Short: "Run a command in a pod",
+		Long: `Run a command in a running pod, on the kubelet running it, streaming its output
+as it runs. kubectl-lite exits with the command's exit code. -i sends the command
+this program's input, and -t gives it a terminal the size of this one, which also
+puts its error output on standard output.
+
+Pods are simulated, so only a few commands exist: echo, hostname, env, true,
+false, ls and cat, which see the pod's volume mounts (cat with no file prints
+its input), stty size, and nc [-z] [-v] HOST PORT, which connects to a service or
+pod IP subject to network policies.`,
+		Example: `  kubectl-lite exec web -- hostname
+  kubectl-lite exec web -- ls /data
+  kubectl-lite exec web -- nc -zv db 5432
+  echo hello | kubectl-lite exec -i web -- cat
+  kubectl-lite exec -t web -- stty size`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: o.completePodArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			var in io.Reader
+			if stdin {
+				in = cmd.InOrStdin()
+			}
+			code, err := execInPod(in, cmd.OutOrStdout(), cmd.ErrOrStderr(), tty, client, o.Namespace(), args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				cmd.SilenceErrors = true
+				return &exitCodeError{code: code}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&stdin, "stdin", "i", false, "Pass standard input to the command")
+	cmd.Flags().BoolVarP(&tty, "tty", "t", false, "Give the command a terminal the size of this one")
+	return cmd
+}
+
+// execInPod runs command in pod name in namespace with stdin, if not nil, as its
+// input, streams its output to stdout and stderr, and returns its exit code. With tty
+// the command gets a terminal that follows the size of this one.
+func execInPod(stdin io.Reader, stdout, stderr io.Writer, tty bool, client api.Interface, namespace, name string, command []string) (int, error) {
+	sc, err := streamClient(client, "exec")
+	if err != nil {
+		return 0, err
+	}
+	opts := streaming.Options{Stdin: stdin, Stdout: stdout, Stderr: stderr}
+	if tty {
+		sizes, stop := watchTerminalSize(os.Stdout)
+		defer stop()
+		opts.Resize = sizes
+	}
+	return sc.ExecPodStream(namespace, name, command, tty, opts)
+}
+
+// watchTerminalSize returns a channel delivering the size of the terminal f is, now
+// and whenever it changes, and a function to stop watching it.
+func watchTerminalSize(f *os.File) (<-chan streaming.TerminalSize, func()) {
+	sizes := make(chan streaming.TerminalSize, 1)
+	if size, ok := terminalSize(f); ok {
+		sizes <- size
+	}
+	signals := make(chan os.Signal, 1)
+	notifyResize(signals)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if size, ok := terminalSize(f); ok {
+					select {
+					case sizes <- size:
+					default: // The last size hasn't been sent yet
+					}
+				}
+			}
+		}
+	}()
+	return sizes, func() {
+		stopResize(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/fields"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/jsonpath"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+	"github.com/spf13/cobra"
+)
+
+func newGetCommand(o *globalOptions) *cobra.Command {
+	var watch bool
+	var output string
+	var forObject string
+	var labelSelector, fieldSelector, sortBy string
+	var chunkSize int
+
+	cmd := &cobra.Command{
+		Use:   "get (pods|nodes|deployments|services|namespaces|poddisruptionbudgets|networkpolicies|persistentvolumes|persistentvolumeclaims|leases|configmaps|secrets|apiservices|events) [NAME]",
+		Short: "Display one or many resources",
+		Example: `  kubectl-lite get pods
+  kubectl-lite get pods -o wide
+  kubectl-lite get pod web -o jsonpath='{.phase}'
+  kubectl-lite get pods -o custom-columns=NAME:.name,NODE:.nodeName
+  kubectl-lite get pods -l app=web,pod-template-hash=5f4c786b9d
+  kubectl-lite get pods --field-selector phase=Running,nodeName=worker-1
+  kubectl-lite get pods --sort-by=.metadata.creationTimestamp
+  kubectl-lite get nodes -w
+  kubectl-lite get pods --chunk-size=100
+  kubectl-lite get events --for pod/web
+  kubectl-lite get leases -n kube-node-lease
+  kubectl-lite get configmap cluster-info -n kube-public
+  kubectl-lite get cs`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: o.completeResourceArgs([]string{"pods", "nodes", "deployments", "services", "namespaces", "poddisruptionbudgets", "networkpolicies", "persistentvolumes", "persistentvolumeclaims", "leases", "configmaps", "secrets", "apiservices", "componentstatuses", "events"}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resource, err := lookupResource(args[0])
+			if err != nil {
+				return err
+			}
+			var resourceName string
+			if len(args) > 1 {
+				resourceName = args[1]
+			}
+
+			o.chunkSize = chunkSize
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			namespace := o.Namespace()
+			labelSel, err := labels.Parse(labelSelector)
+			if err != nil {
+				return fmt.Errorf("invalid --selector: %w", err)
+			}
+			fieldSel, err := fields.Parse(fieldSelector)
+			if err != nil {
+				return fmt.Errorf("invalid --field-selector: %w", err)
+			}
+			if (!labelSel.Empty() || !fieldSel.Empty() || sortBy != "") && (resourceName != "" || watch) {
+				return fmt.Errorf("-l, --field-selector, and --sort-by only apply when listing, not to a single object or -w")
+			}
+			// printList prints a list, after filtering it by the selectors unless the
+			// server already has, and sorting it by --sort-by.
+			printList := func(list interface{}, selected bool) error {
+				var err error
+				if !selected {
+					if list, err = filterObjects(list, labelSel, fieldSel); err != nil {
+						return err
+					}
+				}
+				if sortBy != "" {
+					if list, err = sortObjects(list, sortBy); err != nil {
+						return err
+					}
+				}
+				return printOutput(cmd.OutOrStdout(), list, output, true)
+			}
+
+			switch resource.Name {
+			case "pods":
+				if watch {
+					ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+					defer stop()
+					watchPods(ctx, cmd.OutOrStdout(), client, namespace, resourceName)
+					return nil
+				}
+				if resourceName == "" { // List all pods in namespace
+					pods, err := client.ListPodsMatching(namespace, labelSelector, fieldSelector)
+					if err != nil {
+						return fmt.Errorf("getting pods: %w", err)
+					}
+					return printList(pods, true)
+				}
+				pod, err := client.GetPod(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), pod, output, false)
+			case "nodes":
+				if watch {
+					ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+					defer stop()
+					watchNodes(ctx, cmd.OutOrStdout(), client, resourceName)
+					return nil
+				}
+				if resourceName == "" { // List all nodes
+					nodes, err := client.ListNodesMatching(labelSelector, fieldSelector)
+					if err != nil {
+						return fmt.Errorf("getting nodes: %w", err)
+					}
+					return printList(nodes, true)
+				}
+				node, err := client.GetNode(resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), node, output, false)
+			case "deployments":
+				if resourceName == "" {
+					deployments, err := client.ListDeployments(namespace)
+					if err != nil {
+						return fmt.Errorf("getting deployments: %w", err)
+					}
+					return printList(deployments, false)
+				}
+				d, err := client.GetDeployment(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), d, output, false)
+			case "services":
+				if resourceName == "" {
+					services, err := client.ListServices(namespace)
+					if err != nil {
+						return fmt.Errorf("getting services: %w", err)
+					}
+					return printList(services, false)
+				}
+				svc, err := client.GetService(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), svc, output, false)
+			case "namespaces":
+				if resourceName == "" {
+					namespaces, err := client.ListNamespaces()
+					if err != nil {
+						return fmt.Errorf("getting namespaces: %w", err)
+					}
+					return printList(namespaces, false)
+				}
+				ns, err := client.GetNamespace(resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), ns, output, false)
+			case "poddisruptionbudgets":
+				if resourceName == "" {
+					pdbs, err := client.ListPodDisruptionBudgets(namespace)
+					if err != nil {
+						return fmt.Errorf("getting poddisruptionbudgets: %w", err)
+					}
+					return printList(pdbs, false)
+				}
+				pdb, err := client.GetPodDisruptionBudget(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), pdb, output, false)
+			case "networkpolicies":
+				if resourceName == "" {
+					policies, err := client.ListNetworkPolicies(namespace)
+					if err != nil {
+						return fmt.Errorf("getting networkpolicies: %w", err)
+					}
+					return printList(policies, false)
+				}
+				policy, err := client.GetNetworkPolicy(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), policy, output, false)
+			case "persistentvolumes":
+				if resourceName == "" {
+					pvs, err := client.ListPersistentVolumes()
+					if err != nil {
+						return fmt.Errorf("getting persistentvolumes: %w", err)
+					}
+					return printList(pvs, false)
+				}
+				pv, err := client.GetPersistentVolume(resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), pv, output, false)
+			case "persistentvolumeclaims":
+				if resourceName == "" {
+					pvcs, err := client.ListPersistentVolumeClaims(namespace)
+					if err != nil {
+						return fmt.Errorf("getting persistentvolumeclaims: %w", err)
+					}
+					return printList(pvcs, false)
+				}
+				pvc, err := client.GetPersistentVolumeClaim(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), pvc, output, false)
+			case "leases":
+				if resourceName == "" {
+					leases, err := client.ListLeases(namespace)
+					if err != nil {
+						return fmt.Errorf("getting leases: %w", err)
+					}
+					return printList(leases, false)
+				}
+				lease, err := client.GetLease(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), lease, output, false)
+			case "configmaps":
+				if resourceName == "" {
+					configMaps, err := client.ListConfigMaps(namespace)
+					if err != nil {
+						return fmt.Errorf("getting configmaps: %w", err)
+					}
+					return printList(configMaps, false)
+				}
+				configMap, err := client.GetConfigMap(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), configMap, output, false)
+			case "secrets":
+				if resourceName == "" {
+					secrets, err := client.ListSecrets(namespace)
+					if err != nil {
+						return fmt.Errorf("getting secrets: %w", err)
+					}
+					return printList(secrets, false)
+				}
+				secret, err := client.GetSecret(namespace, resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), secret, output, false)
+			case "apiservices":
+				rest, err := o.restClient("get apiservices")
+				if err != nil {
+					return err
+				}
+				if resourceName == "" {
+					apiServices, err := rest.APIServices().List(api.ListOptions{})
+					if err != nil {
+						return fmt.Errorf("getting apiservices: %w", err)
+					}
+					return printList(apiServices, false)
+				}
+				apiService, err := rest.APIServices().Get(resourceName)
+				if err != nil {
+					return err
+				}
+				return printOutput(cmd.OutOrStdout(), apiService, output, false)
+			case "componentstatuses":
+				statuses, err := client.ListComponentStatuses()
+				if err != nil {
+					return fmt.Errorf("getting componentstatuses: %w", err)
+				}
+				if resourceName == "" {
+					return printList(statuses, false)
+				}
+				for i := range statuses {
+					if statuses[i].Name == resourceName {
+						return printOutput(cmd.OutOrStdout(), &statuses[i], output, false)
+					}
+				}
+				return fmt.Errorf("componentstatus %q not found", resourceName)
+			case "events":
+				if resourceName != "" {
+					return fmt.Errorf("events are selected with --for <resource>/<name>, not by name")
+				}
+				var ref *api.ObjectReference
+				if forObject != "" {
+					r, err := parseObjectRef(forObject, namespace)
+					if err != nil {
+						return fmt.Errorf("invalid --for: %w", err)
+					}
+					ref = &r
+				}
+				events, err := eventsFor(client, namespace, ref)
+				if err != nil {
+					return fmt.Errorf("getting events: %w", err)
+				}
+				return printList(events, false)
+			default:
+				return fmt.Errorf("get is not supported for %s", resource.Name)
+			}
+		},
+	}
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "After listing, watch for changes")
+	cmd.Flags().StringVar(&forObject, "for", "", "With 'get events', only show events about this object, e.g. pod/web")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Only list objects matching this label selector, e.g. app=web,tier!=db")
+	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "Only list objects matching this field selector, e.g. phase=Running,nodeName=worker-1; objects other than pods and nodes only support name and namespace")
+	cmd.Flags().StringVar(&sortBy, "sort-by", "", "Sort lists by the value at this JSONPath, e.g. .metadata.creationTimestamp")
+	cmd.Flags().IntVar(&chunkSize, "chunk-size", 500, "List large result sets in pages of this many objects; 0 fetches each list in one request")
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format: json, wide (pods only), jsonpath=<template>, or custom-columns=<HDR>:<path>,...")
+	return cmd
+}
+
+// filterObjects returns the objects in list, a slice of API objects, that match
+// labelSel and fieldSel. Fields can only select by name and namespace here.
+func filterObjects(list interface{}, labelSel labels.Selector, fieldSel fields.Selector) (interface{}, error) {
+	if labelSel.Empty() && fieldSel.Empty() {
+		return list, nil
+	}
+	if err := fieldSel.Validate(fields.ObjectMetaFields(&api.ObjectMeta{})); err != nil {
+		return nil, err
+	}
+	items := reflect.ValueOf(list)
+	matched := reflect.MakeSlice(items.Type(), 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		obj, ok := items.Index(i).Addr().Interface().(interface{ GetObjectMeta() *api.ObjectMeta })
+		if !ok {
+			return nil, fmt.Errorf("cannot select %s by label or field", items.Index(i).Type())
+		}
+		meta := obj.GetObjectMeta()
+		if labelSel.Matches(meta.Labels) && fieldSel.Matches(fields.ObjectMetaFields(meta)) {
+			matched = reflect.Append(matched, items.Index(i))
+		}
+	}
+	return matched.Interface(), nil
+}
+
+// sortObjects returns list, a slice, sorted by the value each item has at path, a
+// JSONPath such as .metadata.creationTimestamp. Objects in this API are flat, so a
+// path under .metadata, .spec, or .status that selects nothing is tried again without
+// that prefix. Items without a value sort first.
+func sortObjects(list interface{}, path string) (interface{}, error) {
+	if _, err := jsonpath.Evaluate(path, nil); err != nil {
+		return nil, fmt.Errorf("invalid --sort-by: %w", err)
+	}
+	items := reflect.ValueOf(list)
+	keys := make([]interface{}, items.Len())
+	for i := range keys {
+		item := items.Index(i).Interface()
+		values, err := jsonpath.Evaluate(path, item)
+		if err != nil {
+			return nil, err
+		}
+		if flat, ok := flatPath(path); ok && len(values) == 0 {
+			if values, err = jsonpath.Evaluate(flat, item); err != nil {
+				return nil, err
+			}
+		}
+		if len(values) > 0 {
+			keys[i] = values[0]
+		}
+	}
+
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return lessValue(keys[order[a]], keys[order[b]]) })
+	sorted := reflect.MakeSlice(items.Type(), len(order), len(order))
+	for i, j := range order {
+		sorted.Index(i).Set(items.Index(j))
+	}
+	return sorted.Interface(), nil
+}
+
+// flatPath strips a leading .metadata, .spec, or .status from a Kubernetes-style
+// path, such as .metadata.creationTimestamp, leaving the flat .creationTimestamp.
+func flatPath(path string) (string, bool) {
+	path = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(path), "{"), "}")
+	for _, prefix := range []string{".metadata.", ".spec.", ".status."} {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			return "." + rest, true
+		}
+	}
+	return "", false
+}
+
+// lessValue orders values selected by a --sort-by path: missing values first, then
+// numbers, timestamps, and other strings by value.
+func lessValue(a, b interface{}) bool {
+	switch {
+	case a == nil:
+		return b != nil
+	case b == nil:
+		return false
+	}
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			return a < b
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			return !a && b
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			ta, errA := time.Parse(time.RFC3339Nano, a)
+			tb, errB := time.Parse(time.RFC3339Nano, b)
+			if errA == nil && errB == nil {
+				return ta.Before(tb)
+			}
+			return a < b
+		}
+	}
+	return jsonpath.FormatValue(a) < jsonpath.FormatValue(b)
+}
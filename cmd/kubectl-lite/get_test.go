@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/fields"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+)
+
+func TestSortObjects(t *testing.T) {
+	now := time.Now()
+	pods := []api.Pod{
+		// As strings, "...:00.55Z" sorts before "...:00.5Z".
+		{ObjectMeta: api.ObjectMeta{Name: "b", CreationTimestamp: now.Truncate(time.Second).Add(550 * time.Millisecond)}, NodeName: "node2"},
+		{ObjectMeta: api.ObjectMeta{Name: "a", CreationTimestamp: now.Truncate(time.Second).Add(500 * time.Millisecond)}},
+		{ObjectMeta: api.ObjectMeta{Name: "c", CreationTimestamp: now.Truncate(time.Second).Add(2 * time.Second)}, NodeName: "node1"},
+	}
+	names := func(list interface{}) []string {
+		var got []string
+		for _, pod := range list.([]api.Pod) {
+			got = append(got, pod.Name)
+		}
+		return got
+	}
+
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{".metadata.creationTimestamp", "[a b c]"},
+		{"{.creationTimestamp}", "[a b c]"},
+		{".spec.nodeName", "[a c b]"}, // Pods without a node first
+		{".name", "[a b c]"},
+	} {
+		sorted, err := sortObjects(pods, tt.path)
+		if err != nil {
+			t.Fatalf("sortObjects(%s): %v", tt.path, err)
+		}
+		if got := names(sorted); fmt.Sprint(got) != tt.want {
+			t.Errorf("sortObjects(%s) = %v, want %s", tt.path, got, tt.want)
+		}
+	}
+	if names(pods)[0] != "b" {
+		t.Errorf("expected sortObjects to leave its argument alone, got %v", names(pods))
+	}
+	if _, err := sortObjects(pods, ".items[x"); err == nil {
+		t.Error("expected an invalid path to be rejected")
+	}
+}
+
+func TestFilterObjects(t *testing.T) {
+	deployments := []api.Deployment{
+		{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+		{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default", Labels: map[string]string{"app": "db"}}},
+	}
+	labelSel, _ := labels.Parse("app=web")
+	fieldSel, _ := fields.Parse("metadata.name!=api")
+	got, err := filterObjects(deployments, labelSel, fieldSel)
+	if err != nil {
+		t.Fatalf("filterObjects: %v", err)
+	}
+	if matched := got.([]api.Deployment); len(matched) != 1 || matched[0].Name != "web" {
+		t.Errorf("expected only web to match, got %+v", matched)
+	}
+
+	phase, _ := fields.Parse("phase=Running")
+	if _, err := filterObjects(deployments, nil, phase); err == nil {
+		t.Error("expected a field other than name or namespace to be rejected")
+	}
+}
+
+func TestPrintWide(t *testing.T) {
+	created := time.Now().Add(-3 * time.Minute)
+	pods := []api.Pod{
+		{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", CreationTimestamp: created}, NodeName: "node1", Phase: api.PodRunning,
+			PodIP: "10.244.0.5", HostIP: "192.168.1.10", RestartCount: 2,
+			Conditions: []api.PodCondition{{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: api.PodReasonCrashLoopBackOff}}},
+		{ObjectMeta: api.ObjectMeta{Name: "queued", Namespace: "default", CreationTimestamp: created}, Phase: api.PodPending},
+	}
+	var out strings.Builder
+	if err := printOutput(&out, pods, "wide", true); err != nil {
+		t.Fatalf("printOutput: %v", err)
+	}
+	want := "NAMESPACE   NAME     STATUS             RESTARTS   AGE   NODE     POD IP       HOST IP\n" +
+		"default     web      CrashLoopBackOff   2          3m    node1    10.244.0.5   192.168.1.10\n" +
+		"default     queued   Pending            0          3m    <none>   <none>       <none>\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	if err := printOutput(&out, []api.Node{}, "wide", true); err == nil {
+		t.Error("expected -o wide to be refused for nodes")
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+	"github.com/spf13/cobra"
+)
+
+// podStreamClient is implemented by clients that can reach pods through the API
+// server and their kubelets.
+type podStreamClient interface {
+	GetPodLogsWithOptions(namespace, name string, opts api.PodLogOptions) (string, error)
+	ExecPodStream(namespace, name string, command []string, tty bool, opts streaming.Options) (int, error)
+	AttachPod(namespace, name string, opts streaming.Options) error
+	PortForward(namespace, name string, port int, conn io.ReadWriter) error
+}
+
+// streamClient returns client as a podStreamClient, or an error naming what needs one.
+func streamClient(client api.Interface, what string) (podStreamClient, error) {
+	sc, ok := client.(podStreamClient)
+	if !ok {
+		return nil, fmt.Errorf("%s is not supported by this client", what)
+	}
+	return sc, nil
+}
+
+func newLogsCommand(o *globalOptions) *cobra.Command {
+	var (
+		tail      int
+		since     time.Duration
+		previous  bool
+		container string
+	)
+	cmd := &cobra.Command{
+		Use:   "logs POD",
+		Short: "Print the output of a pod",
+		Long: `Print the output of a pod, fetched from the kubelet running it through the
+API server. The pod must be running. Only the output of its container's current run
+is printed, or with --previous that of the run before it last restarted, such as
+why a crash looping container exited. A pod has one container, named after the pod.`,
+		Example: `  kubectl-lite logs web
+  kubectl-lite logs web --tail 20 --since 5m
+  kubectl-lite logs web -c web --previous`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completePodArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if since < 0 {
+				return fmt.Errorf("--since must not be negative, got %v", since)
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			opts := api.PodLogOptions{Previous: previous, Container: container}
+			if tail > 0 {
+				opts.TailLines = tail
+			}
+			if since > 0 {
+				// Rounded up, so that --since never leaves out a line it covers.
+				opts.SinceSeconds = int64((since + time.Second - 1) / time.Second)
+			}
+			return printLogs(cmd.OutOrStdout(), client, o.Namespace(), args[0], opts)
+		},
+	}
+	cmd.Flags().IntVar(&tail, "tail", -1, "Print only the last lines of output, this many (-1 prints all)")
+	cmd.Flags().DurationVar(&since, "since", 0, "Print only the output of this last stretch of time, e.g. 5m (0 prints all)")
+	cmd.Flags().BoolVarP(&previous, "previous", "p", false, "Print the output of the container's previous run, before it restarted")
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container to print the output of; a pod's only container is named after the pod")
+	return cmd
+}
+
+func (o *globalOptions) completePodArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return o.resourceNames("pods"), cobra.ShellCompDirectiveNoFileComp
+}
+
+// printLogs writes the output of pod name in namespace that opts selects to w.
+func printLogs(w io.Writer, client api.Interface, namespace, name string, opts api.PodLogOptions) error {
+	sc, err := streamClient(client, "logs")
+	if err != nil {
+		return err
+	}
+	logs, err := sc.GetPodLogsWithOptions(namespace, name, opts)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, logs)
+	return err
+}
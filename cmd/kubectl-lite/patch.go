@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// patchTypes maps the values of --type to the patch types the server understands.
+var patchTypes = map[string]api.PatchType{
+	"merge": api.MergePatchType,
+	"json":  api.JSONPatchType,
+}
+
+func newPatchCommand(o *globalOptions) *cobra.Command {
+	var patchData, patchType string
+	cmd := &cobra.Command{
+		Use:   "patch (pod|node|namespace|deployment|service) NAME -p PATCH [--type merge|json]",
+		Short: "Update fields of an object with a merge patch or JSON patch",
+		Long: `Update fields of an object in place, without editing or applying the whole object.
+
+A merge patch (the default) is a partial object: its fields replace the object's,
+maps are merged, and null removes a field. A JSON patch is a list of operations
+(add, remove, replace, move, copy, test) on JSON pointer paths.`,
+		Example: `  kubectl-lite patch pod web -p '{"labels":{"tier":"backend"}}'
+  kubectl-lite patch pod web -p '{"labels":{"canary":null}}'
+  kubectl-lite patch deployment web -p '{"replicas":5}'
+  kubectl-lite patch deployment web --type json -p '[{"op":"replace","path":"/template/image","value":"nginx:1.26"}]'`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: o.completeResourceArgs([]string{"pods", "nodes", "namespaces", "deployments", "services"}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := lookupResource(args[0])
+			if err != nil {
+				return err
+			}
+			kind := r.Kind
+			if kind != "Pod" && kind != "Node" && kind != "Namespace" && kind != "Deployment" && kind != "Service" {
+				return fmt.Errorf("patch is not supported for %s", r.Name)
+			}
+			name := args[1]
+			pt, ok := patchTypes[patchType]
+			if !ok {
+				return fmt.Errorf("--type must be merge or json, got %q", patchType)
+			}
+			if patchData == "" {
+				return fmt.Errorf("-p is required")
+			}
+
+			rest, err := o.restClient("patch")
+			if err != nil {
+				return err
+			}
+			namespace := o.Namespace()
+			// The server leaves an object a patch doesn't change untouched, so an
+			// unchanged resourceVersion tells "patched (no change)" from "patched".
+			var before string
+			if live, err := getObject(rest, kind, namespace, name); err == nil {
+				before = resourceVersionOf(live)
+			}
+			var result map[string]interface{}
+			if err := rest.Patch(kind, namespace, name, pt, []byte(patchData), &result); err != nil {
+				return err
+			}
+			status := "patched"
+			if result["resourceVersion"] == before {
+				status = "patched (no change)"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s/%s %s\n", strings.ToLower(kind), name, status)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&patchData, "patch", "p", "", "The patch, as JSON")
+	cmd.Flags().StringVar(&patchType, "type", "merge", "Patch type: merge or json")
+	return cmd
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is prepended to a command name to find its plugin executable:
+// "kubectl-lite foo bar" runs kubectl-lite-foo-bar or, failing that, kubectl-lite-foo.
+const pluginPrefix = "kubectl-lite-"
+
+// runPlugin executes a plugin for args if the first non-flag argument is not a
+// built-in command. It reports whether a plugin ran and its exit code.
+func runPlugin(root *cobra.Command, args []string) (bool, int) {
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			break // Plugin names are made of leading positional words only.
+		}
+		words = append(words, a)
+	}
+	if len(words) == 0 {
+		return false, 0
+	}
+	if cmd, _, err := root.Find(args); err == nil && cmd != root {
+		return false, 0
+	}
+	if words[0] == "help" || strings.HasPrefix(words[0], "__") {
+		return false, 0
+	}
+
+	// Prefer the most specific plugin name.
+	for i := len(words); i > 0; i-- {
+		path, err := exec.LookPath(pluginPrefix + strings.Join(words[:i], "-"))
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[i:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		cmd.Env = os.Environ()
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return true, exitErr.ExitCode()
+			}
+			fmt.Fprintf(os.Stderr, "Error running plugin %s: %v\n", path, err)
+			return true, 1
+		}
+		return true, 0
+	}
+	return false, 0
+}
+
+// discoverPlugins returns the full paths of kubectl-lite plugins on PATH, keeping
+// only the first occurrence of each name.
+func discoverPlugins() []string {
+	seen := map[string]bool{}
+	var plugins []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !strings.HasPrefix(name, pluginPrefix) || seen[name] {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || info.Mode()&0o111 == 0 {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(plugins)
+	return plugins
+}
+
+func newPluginCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Work with kubectl-lite plugins",
+		Long: `Plugins are executables named kubectl-lite-<name> found on PATH.
+"kubectl-lite <name> [args]" runs them with the remaining arguments.`,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List plugin executables found on PATH",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins := discoverPlugins()
+			if len(plugins) == 0 {
+				return fmt.Errorf("no plugins found on PATH")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "The following kubectl-lite plugins are available:")
+			for _, p := range plugins {
+				fmt.Fprintln(cmd.OutOrStdout(), "  "+p)
+			}
+			return nil
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// portMapping is a local port forwarded to a port of a pod. A Local of 0 picks a
+// free port.
+type portMapping struct {
+	Local, Remote int
+}
+
+func newPortForwardCommand(o *globalOptions) *cobra.Command {
+	var address string
+	cmd := &cobra.Command{
+		Use:   "port-forward POD [LOCAL_PORT:]REMOTE_PORT...",
+		Short: "Forward local ports to a pod",
+		Long: `Listen on local ports and forward each connection to a port of a pod, through
+the API server and the kubelet running the pod, until interrupted. A mapping of
+just REMOTE_PORT listens on the same port locally, and :REMOTE_PORT on any free
+port.
+
+Pods are simulated, so every port of a pod speaks HTTP and answers each request
+with a line naming the pod.`,
+		Example: `  kubectl-lite port-forward web 8080:80
+  curl localhost:8080`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: o.completePodArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mappings, err := parsePortMappings(args[1:])
+			if err != nil {
+				return err
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			sc, err := streamClient(client, "port-forward")
+			if err != nil {
+				return err
+			}
+			return portForward(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), sc, o.Namespace(), args[0], address, mappings)
+		},
+	}
+	cmd.Flags().StringVar(&address, "address", "127.0.0.1", "Address to listen on")
+	return cmd
+}
+
+// parsePortMappings parses [LOCAL_PORT:]REMOTE_PORT arguments.
+func parsePortMappings(args []string) ([]portMapping, error) {
+	mappings := make([]portMapping, 0, len(args))
+	for _, arg := range args {
+		local, remote, mapped := strings.Cut(arg, ":")
+		if !mapped {
+			remote = arg
+		}
+		var m portMapping
+		var err error
+		if m.Remote, err = strconv.Atoi(remote); err != nil || m.Remote < 1 || m.Remote > 65535 {
+			return nil, fmt.Errorf("invalid port mapping %q: the pod's port must be from 1 to 65535", arg)
+		}
+		switch {
+		case !mapped:
+			m.Local = m.Remote
+		case local != "":
+			if m.Local, err = strconv.Atoi(local); err != nil || m.Local < 1 || m.Local > 65535 {
+				return nil, fmt.Errorf("invalid port mapping %q: the local port must be from 1 to 65535", arg)
+			}
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, nil
+}
+
+// portForward listens on address at each mapping's local port and forwards the
+// connections it accepts to the mapping's port of pod name, until ctx is done.
+func portForward(ctx context.Context, out, errOut io.Writer, sc podStreamClient, namespace, name, address string, mappings []portMapping) error {
+	for _, m := range mappings {
+		ln, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(m.Local)))
+		if err != nil {
+			return err
+		}
+		defer ln.Close()
+		fmt.Fprintf(out, "Forwarding from %s -> %d\n", ln.Addr(), m.Remote)
+		go func(ln net.Listener, remote int) {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					fmt.Fprintf(out, "Handling connection for %d\n", remote)
+					if err := sc.PortForward(namespace, name, remote, conn); err != nil {
+						fmt.Fprintf(errOut, "error: %v\n", err)
+					}
+				}()
+			}
+		}(ln, m.Remote)
+	}
+	<-ctx.Done()
+	return nil
+}
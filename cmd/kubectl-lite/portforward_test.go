@@ -0,0 +1,22 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePortMappings(t *testing.T) {
+	mappings, err := parsePortMappings([]string{"8080:80", "443", ":5432"})
+	if err != nil {
+		t.Fatalf("parsePortMappings: %v", err)
+	}
+	want := []portMapping{{Local: 8080, Remote: 80}, {Local: 443, Remote: 443}, {Local: 0, Remote: 5432}}
+	if !reflect.DeepEqual(mappings, want) {
+		t.Errorf("expected %v, got %v", want, mappings)
+	}
+	for _, arg := range []string{"http", "8080:", "0", "70000:80", "x:80"} {
+		if _, err := parsePortMappings([]string{arg}); err == nil {
+			t.Errorf("expected an error for %q", arg)
+		}
+	}
+}
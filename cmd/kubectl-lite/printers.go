@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/jsonpath"
+)
+
+// printOutput writes data in the format selected by the -o flag:
+//
+//	json (default)                    indented JSON
+//	wide                              a table of pods with their node, IPs, and restarts
+//	jsonpath=<template>               a JSONPath template, e.g. jsonpath='{.phase}'
+//	custom-columns=<HDR>:<path>,...   a table with one column per path
+//
+// Lists are exposed to templates as {"items": [...]} like kubectl, so
+// '{.items[*].name}' selects every name.
+func printOutput(w io.Writer, data interface{}, output string, isList bool) error {
+	switch {
+	case output == "" || output == "json":
+		return prettyPrint(w, data)
+	case output == "wide":
+		return printWide(w, data)
+	case strings.HasPrefix(output, "jsonpath="):
+		tmpl, err := jsonpath.Parse(strings.TrimPrefix(output, "jsonpath="))
+		if err != nil {
+			return fmt.Errorf("parsing jsonpath template: %w", err)
+		}
+		if isList {
+			data = map[string]interface{}{"items": data}
+		}
+		if err := tmpl.Execute(w, data); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		return nil
+	case strings.HasPrefix(output, "custom-columns="):
+		columns, err := parseCustomColumns(strings.TrimPrefix(output, "custom-columns="))
+		if err != nil {
+			return err
+		}
+		items, err := toItems(data, isList)
+		if err != nil {
+			return err
+		}
+		return printCustomColumns(w, columns, items)
+	default:
+		return fmt.Errorf("unknown output format %q (supported: json, wide, jsonpath=..., custom-columns=...)", output)
+	}
+}
+
+// printWide prints pods as a table of the fields most often looked up: each pod's
+// status, restarts, age, node, and IPs. Other objects have no wide form.
+func printWide(w io.Writer, data interface{}) error {
+	var pods []api.Pod
+	switch data := data.(type) {
+	case []api.Pod:
+		pods = data
+	case *api.Pod:
+		pods = []api.Pod{*data}
+	default:
+		return fmt.Errorf("-o wide is only supported for pods")
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tSTATUS\tRESTARTS\tAGE\tNODE\tPOD IP\tHOST IP")
+	for i := range pods {
+		pod := &pods[i]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", pod.Namespace, pod.Name, podStatus(pod), pod.RestartCount,
+			translateTimestampSince(pod.CreationTimestamp), orNone(pod.NodeName), orNone(pod.PodIP), orNone(pod.HostIP))
+	}
+	return tw.Flush()
+}
+
+// customColumn is a single HEADER:path pair from a custom-columns spec.
+type customColumn struct {
+	header string
+	path   string
+}
+
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	var columns []customColumn
+	for _, part := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || header == "" || path == "" {
+			return nil, fmt.Errorf("invalid custom-columns entry %q, expected HEADER:.path", part)
+		}
+		if _, err := jsonpath.Parse("{" + strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}") + "}"); err != nil {
+			return nil, fmt.Errorf("invalid path for column %s: %w", header, err)
+		}
+		columns = append(columns, customColumn{header: header, path: path})
+	}
+	return columns, nil
+}
+
+// toItems returns the individual objects in data as generic JSON values.
+func toItems(data interface{}, isList bool) ([]interface{}, error) {
+	generic, err := jsonpath.ToGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+	if !isList {
+		return []interface{}{generic}, nil
+	}
+	items, _ := generic.([]interface{})
+	return items, nil
+}
+
+func printCustomColumns(w io.Writer, columns []customColumn, items []interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, item := range items {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			values, err := jsonpath.Evaluate(col.path, item)
+			if err != nil {
+				return err
+			}
+			cells[i] = formatCell(values)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatCell joins the values selected for one table cell, using <none> when the
+// path matched nothing.
+func formatCell(values []interface{}) string {
+	var parts []string
+	for _, v := range values {
+		if s := jsonpath.FormatValue(v); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return "<none>"
+	}
+	return strings.Join(parts, ",")
+}
+
+func prettyPrint(w io.Writer, data interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return fmt.Errorf("pretty printing JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// newRegisterCommand registers nodes by hand. Kubelets normally register themselves.
+func newRegisterCommand(o *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register a node with the cluster",
+	}
+
+	var name, address string
+	nodeCmd := &cobra.Command{
+		Use:     "node [NAME] --address=<addr>",
+		Short:   "Register a node",
+		Example: "  kubectl-lite register node node1 --address 10.0.0.1",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				name = args[0]
+			}
+			if name == "" || address == "" {
+				return fmt.Errorf("a node name and --address are required for registering a node")
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			node := &api.Node{ObjectMeta: api.ObjectMeta{Name: name}, Address: address, Status: api.NodeReady}
+			createdNode, err := client.CreateNode(node)
+			if err != nil {
+				return fmt.Errorf("registering node: %w", err)
+			}
+			fmt.Printf("Node %s registered with address %s\n", createdNode.Name, createdNode.Address)
+			return nil
+		},
+	}
+	nodeCmd.Flags().StringVar(&name, "name", "", "Name of the node (alternative to the NAME argument)")
+	nodeCmd.Flags().StringVar(&address, "address", "", "Address of the node (e.g. IP)")
+
+	cmd.AddCommand(nodeCmd)
+	return cmd
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubeconfig"
+	"github.com/spf13/cobra"
+)
+
+// globalOptions holds the persistent flags shared by every command and lazily
+// builds the API client from them.
+type globalOptions struct {
+	apiServerURL   string
+	kubeconfigPath string
+	contextName    string
+	namespace      string
+	chunkSize      int // Set by get --chunk-size
+
+	client           api.Interface
+	contextNamespace string
+}
+
+func newRootCommand() *cobra.Command {
+	o := &globalOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "kubectl-lite",
+		Short: "kubectl-lite controls a k8s-lite-go cluster",
+		Long: `kubectl-lite controls a k8s-lite-go cluster.
+
+Commands not built in are looked up as kubectl-lite-<command> executables on PATH.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.apiServerURL, "apiserver", "", "URL of the API server (overrides the kubeconfig context; default "+DefaultAPIServerURL+")")
+	flags.StringVar(&o.kubeconfigPath, "kubeconfig", kubeconfig.DefaultPath(), "Path to the kubeconfig-lite file")
+	flags.StringVar(&o.contextName, "context", "", "Kubeconfig context to use (default: current-context)")
+	flags.StringVarP(&o.namespace, "namespace", "n", "", "Namespace for namespaced resources (default: the context's namespace, or \""+DefaultNamespace+"\")")
+
+	_ = cmd.RegisterFlagCompletionFunc("namespace", o.completeNamespaces)
+	_ = cmd.RegisterFlagCompletionFunc("context", o.completeContexts)
+
+	cmd.AddCommand(
+		newCreateCommand(o),
+		newGetCommand(o),
+		newDeleteCommand(o),
+		newDescribeCommand(o),
+		newDiffCommand(o),
+		newApplyCommand(o),
+		newTemplateCommand(o),
+		newPatchCommand(o),
+		newAnnotateCommand(o),
+		newRolloutCommand(o),
+		newAPIResourcesCommand(o),
+		newAPIVersionsCommand(o),
+		newCordonCommand(o),
+		newUncordonCommand(o),
+		newDrainCommand(o),
+		newCertificateCommand(o),
+		newScheduleCommand(o),
+		newLogsCommand(o),
+		newExecCommand(o),
+		newAttachCommand(o),
+		newPortForwardCommand(o),
+		newRegisterCommand(o),
+		newClusterInfoCommand(o),
+		newConfigCommand(o),
+		newPluginCommand(),
+	)
+	return cmd
+}
+
+// Client returns the API client, creating it on first use. An explicit --apiserver
+// wins; otherwise the kubeconfig context supplies the server and token. With no
+// kubeconfig at all, the client talks to DefaultAPIServerURL.
+func (o *globalOptions) Client() (api.Interface, error) {
+	if o.client != nil {
+		return o.client, nil
+	}
+
+	cfg, err := kubeconfig.Load(o.kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved *kubeconfig.Resolved
+	if o.contextName != "" || cfg.CurrentContext != "" {
+		resolved, err = cfg.Resolve(o.contextName)
+		if err != nil {
+			return nil, err
+		}
+		o.contextNamespace = resolved.Namespace
+	}
+
+	opts := []api.ClientOption{api.WithChunkSize(o.chunkSize), api.WithWarningHandler(func(message string) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", message)
+	})}
+	var client *api.Client
+	switch {
+	case o.apiServerURL != "":
+		if resolved != nil {
+			opts = append(opts, api.WithBearerToken(resolved.Token))
+		}
+		client, err = api.NewClient(o.apiServerURL, opts...)
+	case resolved != nil:
+		client, err = api.NewClient(resolved.Server, append(opts, api.WithBearerToken(resolved.Token))...)
+	default:
+		client, err = api.NewClient(DefaultAPIServerURL, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	o.client = client
+	return client, nil
+}
+
+// Namespace returns the namespace to operate in: --namespace if given, otherwise the
+// kubeconfig context's namespace, otherwise DefaultNamespace.
+func (o *globalOptions) Namespace() string {
+	if o.namespace != "" {
+		return o.namespace
+	}
+	if o.contextNamespace == "" {
+		if cfg, err := kubeconfig.Load(o.kubeconfigPath); err == nil {
+			if resolved, err := cfg.Resolve(o.contextName); err == nil {
+				o.contextNamespace = resolved.Namespace
+			}
+		}
+	}
+	if o.contextNamespace != "" {
+		return o.contextNamespace
+	}
+	return DefaultNamespace
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/manifest"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/scheduler"
+	"github.com/spf13/cobra"
+)
+
+func newScheduleCommand(o *globalOptions) *cobra.Command {
+	var filename, output string
+	var explain bool
+	cmd := &cobra.Command{
+		Use:   "schedule --explain (-f FILENAME | POD)",
+		Short: "Explain where the scheduler would place a pod, and why",
+		Long: `Run the scheduler's filters and scores for a pod against the cluster's current
+nodes and pods, without binding it, and show for every node the filters it failed
+or the scores it got. The pod is an existing one, or each pod in a manifest, which
+need not have been created.
+
+The scheduler binds a pod to one of the best scoring nodes, picking among ties
+round-robin.`,
+		Example: `  kubectl-lite schedule --explain web
+  kubectl-lite schedule --explain -f pod.yaml
+  kubectl-lite schedule --explain web -o json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: o.completePodArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !explain {
+				return fmt.Errorf("only --explain is supported; the scheduler binds pods itself")
+			}
+			if (filename == "") == (len(args) == 0) {
+				return fmt.Errorf("specify either a pod name or -f")
+			}
+			client, err := o.Client()
+			if err != nil {
+				return err
+			}
+			pods, err := podsToExplain(client, o.Namespace(), filename, args)
+			if err != nil {
+				return err
+			}
+			for _, pod := range pods {
+				explanation, err := scheduler.Explain(client, pod)
+				if err != nil {
+					return err
+				}
+				if output != "" {
+					if err := printOutput(cmd.OutOrStdout(), explanation, output, false); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := printExplanation(cmd.OutOrStdout(), explanation); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&explain, "explain", false, "Show how every node fared instead of binding the pod")
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "Manifest of the pods to explain, or - for standard input")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: json, jsonpath=..., or custom-columns=... (default: a table)")
+	_ = cmd.MarkFlagFilename("filename", "yaml", "yml", "json")
+	return cmd
+}
+
+// podsToExplain returns the pods in the manifest at filename, or the pod named in args.
+func podsToExplain(client api.Interface, namespace, filename string, args []string) ([]*api.Pod, error) {
+	if filename == "" {
+		pod, err := client.GetPod(namespace, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return []*api.Pod{pod}, nil
+	}
+	objects, err := manifest.DecodeFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var pods []*api.Pod
+	for _, obj := range objects {
+		pod, ok := obj.Object.(*api.Pod)
+		if !ok {
+			continue
+		}
+		if pod.Namespace == "" {
+			pod.Namespace = namespace
+		}
+		pods = append(pods, pod)
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods in %s", filename)
+	}
+	return pods, nil
+}
+
+// printExplanation writes the outcome for the pod, then a row per node with the filters
+// it failed or its scores.
+func printExplanation(w io.Writer, e *scheduler.Explanation) error {
+	if len(e.BestNodes) == 0 {
+		fmt.Fprintf(w, "Pod %s is unschedulable: %s\n", e.Pod, e.Message)
+	} else {
+		fmt.Fprintf(w, "Pod %s fits best on: %s\n", e.Pod, strings.Join(e.BestNodes, ", "))
+	}
+	if len(e.Nodes) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tFILTERS\tSCORES\tTOTAL")
+	for _, node := range e.Nodes {
+		if len(node.Failures) > 0 {
+			failures := make([]string, 0, len(node.Failures))
+			for _, f := range node.Failures {
+				failures = append(failures, f.Plugin+": "+f.Reason)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\n", node.Node, strings.Join(failures, "; "))
+			continue
+		}
+		plugins := make([]string, 0, len(node.Scores))
+		for plugin := range node.Scores {
+			plugins = append(plugins, plugin)
+		}
+		sort.Strings(plugins)
+		scores := make([]string, 0, len(plugins))
+		for _, plugin := range plugins {
+			scores = append(scores, fmt.Sprintf("%s=%d", plugin, node.Scores[plugin]))
+		}
+		fmt.Fprintf(tw, "%s\tpassed\t%s\t%d\n", node.Node, strings.Join(scores, ", "), node.Score)
+	}
+	return tw.Flush()
+}
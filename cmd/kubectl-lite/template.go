@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chart"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+func newTemplateCommand(o *globalOptions) *cobra.Command {
+	var valuesFiles, setValues []string
+	var apply bool
+	var fieldManager string
+	cmd := &cobra.Command{
+		Use:   "template RELEASE CHART",
+		Short: "Render a chart of templated manifests, and optionally apply it",
+		Long: `Render the chart in the directory CHART, a small take on a Helm chart: every
+.yaml, .yml, and .json file in its ` + chart.TemplatesDir + ` directory is a Go template, rendered with
+the values in its ` + chart.ValuesFile + ` as .Values, RELEASE as .Release.Name, and the
+namespace as .Release.Namespace.
+
+Values files given with -f, and then single values given with --set, are merged over
+the chart's own, later ones winning. Templates can use Helm's default, required,
+quote, upper, lower, toYaml, indent, and nindent functions.
+
+The rendered manifests are printed, or with --apply applied server-side as apply -f
+would.`,
+		Example: `  kubectl-lite template web ./charts/web
+  kubectl-lite template web ./charts/web -f prod-values.yaml --set replicas=3
+  kubectl-lite template web ./charts/web --set image.tag=1.27 --apply`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values := chart.Values{}
+			for _, path := range valuesFiles {
+				file, err := chart.ReadValues(path)
+				if err != nil {
+					return err
+				}
+				values.Merge(file)
+			}
+			for _, assignment := range setValues {
+				if err := values.Set(assignment); err != nil {
+					return err
+				}
+			}
+			rendered, err := chart.Render(args[1], chart.Release{Name: args[0], Namespace: o.Namespace()}, values)
+			if err != nil {
+				return err
+			}
+			if !apply {
+				_, err := cmd.OutOrStdout().Write(rendered)
+				return err
+			}
+			objects, err := manifest.Decode(bytes.NewReader(rendered))
+			if err != nil {
+				return fmt.Errorf("rendered chart: %w", err)
+			}
+			manifest.SortForApply(objects)
+			return applyObjects(cmd.OutOrStdout(), o, objects, api.ApplyOptions{FieldManager: fieldManager})
+		},
+	}
+	cmd.Flags().StringArrayVarP(&valuesFiles, "values", "f", nil, "Values file to merge over the chart's; may be repeated")
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Value to set, as KEY=VALUE with dots for nested keys, e.g. image.tag=1.27; may be repeated")
+	cmd.Flags().BoolVar(&apply, "apply", false, "Apply the rendered manifests instead of printing them")
+	cmd.Flags().StringVar(&fieldManager, "field-manager", "kubectl-lite", "Name of the manager that owns the applied fields, with --apply")
+	_ = cmd.MarkFlagFilename("values", "yaml", "yml")
+	return cmd
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"os"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+)
+
+func terminalSize(*os.File) (streaming.TerminalSize, bool) { return streaming.TerminalSize{}, false }
+
+func notifyResize(chan<- os.Signal) {}
+func stopResize(chan<- os.Signal)   {}
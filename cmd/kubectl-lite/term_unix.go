@@ -0,0 +1,26 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+	"golang.org/x/sys/unix"
+)
+
+// terminalSize returns the size of the terminal f is, and false if it isn't one.
+func terminalSize(f *os.File) (streaming.TerminalSize, bool) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return streaming.TerminalSize{}, false
+	}
+	return streaming.TerminalSize{Width: ws.Col, Height: ws.Row}, true
+}
+
+// notifyResize sends on ch whenever the terminal is resized.
+func notifyResize(ch chan<- os.Signal) { signal.Notify(ch, syscall.SIGWINCH) }
+
+func stopResize(ch chan<- os.Signal) { signal.Stop(ch) }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+)
+
+// watchPollInterval is how often the watch fallback relists objects from the API server.
+const watchPollInterval = 500 * time.Millisecond
+
+// watchPods prints to out a line every time a pod in namespace is added, changes, or is
+// removed. If name is non-empty, only that pod is reported. It blocks until ctx is done.
+func watchPods(ctx context.Context, out io.Writer, client api.Interface, namespace, name string) {
+	informer := controller.NewPodInformer(client, namespace, watchPollInterval)
+	printEvent := func(event string, obj interface{}) {
+		pod := obj.(*api.Pod)
+		if name != "" && pod.Name != name {
+			return
+		}
+		fmt.Fprintf(out, "%-20s %-9s %-30s %-12s %s\n",
+			time.Now().Format(time.RFC3339), event, pod.Namespace+"/"+pod.Name, podStatus(pod), pod.NodeName)
+	}
+	informer.AddEventHandler(controller.EventHandler{
+		OnAdd: func(obj interface{}) { printEvent("ADDED", obj) },
+		OnUpdate: func(oldObj, newObj interface{}) {
+			oldPod, newPod := oldObj.(*api.Pod), newObj.(*api.Pod)
+			if podStatus(oldPod) != podStatus(newPod) || oldPod.NodeName != newPod.NodeName {
+				printEvent("MODIFIED", newObj)
+			}
+		},
+		OnDelete: func(obj interface{}) { printEvent("DELETED", obj) },
+	})
+
+	fmt.Fprintf(out, "%-20s %-9s %-30s %-12s %s\n", "TIME", "EVENT", "POD", "STATUS", "NODE")
+	informer.Run(ctx)
+}
+
+// podStatus summarizes a pod for display: Terminating while it is being deleted, why
+// its container is waiting (e.g. ImagePullBackOff or CrashLoopBackOff) while it waits
+// to start or restart, and its phase otherwise.
+func podStatus(pod *api.Pod) string {
+	if api.IsPodTerminating(pod) {
+		return "Terminating"
+	}
+	if cond := api.GetPodCondition(pod, api.PodReadyCondition); cond != nil && cond.Status == api.ConditionFalse {
+		switch {
+		case pod.Phase == api.PodScheduled:
+			switch cond.Reason {
+			case api.PodReasonErrImagePull, api.PodReasonImagePullBackOff, api.PodReasonErrImageNeverPull, api.PodReasonInvalidImageName, api.PodReasonContainerCreating:
+				return cond.Reason
+			}
+		case pod.Phase == api.PodRunning && cond.Reason == api.PodReasonCrashLoopBackOff:
+			return cond.Reason
+		}
+	}
+	return string(pod.Phase)
+}
+
+// watchNodes prints to out a line every time a node is added, changes status or address,
+// or is removed. If name is non-empty, only that node is reported. It blocks until ctx
+// is done.
+func watchNodes(ctx context.Context, out io.Writer, client api.Interface, name string) {
+	informer := controller.NewNodeInformer(client, watchPollInterval)
+	printEvent := func(event string, obj interface{}) {
+		node := obj.(*api.Node)
+		if name != "" && node.Name != name {
+			return
+		}
+		fmt.Fprintf(out, "%-20s %-9s %-30s %-10s %s\n",
+			time.Now().Format(time.RFC3339), event, node.Name, node.Status, node.Address)
+	}
+	informer.AddEventHandler(controller.EventHandler{
+		OnAdd: func(obj interface{}) { printEvent("ADDED", obj) },
+		OnUpdate: func(oldObj, newObj interface{}) {
+			// The kubelet's heartbeat rewrites the Ready condition's timestamps every few
+			// seconds; only what is printed changing is worth a line.
+			oldNode, newNode := oldObj.(*api.Node), newObj.(*api.Node)
+			if oldNode.Status != newNode.Status || oldNode.Address != newNode.Address {
+				printEvent("MODIFIED", newObj)
+			}
+		},
+		OnDelete: func(obj interface{}) { printEvent("DELETED", obj) },
+	})
+
+	fmt.Fprintf(out, "%-20s %-9s %-30s %-10s %s\n", "TIME", "EVENT", "NODE", "STATUS", "ADDRESS")
+	informer.Run(ctx)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+// syncBuffer is a bytes.Buffer that a watch can write to while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// eventLines returns the event and status columns of each line printed by a watch,
+// skipping the header.
+func eventLines(out string) []string {
+	var events []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n")[1:] {
+		fields := strings.Fields(line)
+		events = append(events, fields[1]+" "+fields[3])
+	}
+	return events
+}
+
+// runWatch runs watch in the background and returns its output and a func that stops
+// it, waiting for it to return.
+func runWatch(watch func(ctx context.Context, out *syncBuffer)) (*syncBuffer, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watch(ctx, out)
+	}()
+	return out, func() {
+		cancel()
+		<-done
+	}
+}
+
+// waitForLines waits until out has n event lines.
+func waitForLines(t *testing.T, out *syncBuffer, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for strings.Count(out.String(), "\n")-1 < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d event lines, got:\n%s", n, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchPods(t *testing.T) {
+	client := fake.NewClient(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Phase: api.PodPending})
+	out, stop := runWatch(func(ctx context.Context, out *syncBuffer) {
+		watchPods(ctx, out, client, DefaultNamespace, "")
+	})
+	defer stop()
+	waitForLines(t, out, 1)
+
+	pod, _ := client.GetPod(DefaultNamespace, "web")
+	pod.Phase, pod.NodeName = api.PodScheduled, "n1"
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	waitForLines(t, out, 2)
+	// A change that isn't printed isn't reported.
+	pod, _ = client.GetPod(DefaultNamespace, "web")
+	pod.Labels = map[string]string{"app": "web"}
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	time.Sleep(2 * watchPollInterval) // The fake can't watch, so let a relist see it
+	pod.Phase = api.PodRunning
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	waitForLines(t, out, 3)
+	stop()
+
+	if got, want := eventLines(out.String()), []string{"ADDED Pending", "MODIFIED Scheduled", "MODIFIED Running"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected events %v, got:\n%s", want, out.String())
+	}
+}
+
+func TestWatchNodesIgnoresHeartbeats(t *testing.T) {
+	client := fake.NewClient(&api.Node{
+		ObjectMeta: api.ObjectMeta{Name: "n1"},
+		Status:     api.NodeReady,
+		Conditions: []api.NodeCondition{{Type: api.NodeReadyCondition, Status: api.ConditionTrue, LastHeartbeatTime: time.Now()}},
+	})
+	out, stop := runWatch(func(ctx context.Context, out *syncBuffer) {
+		watchNodes(ctx, out, client, "")
+	})
+	defer stop()
+	waitForLines(t, out, 1)
+
+	node, _ := client.GetNode("n1")
+	node.Conditions[0].LastHeartbeatTime = time.Now().Add(time.Second)
+	if err := client.UpdateNode(node); err != nil {
+		t.Fatalf("UpdateNode: %v", err)
+	}
+	time.Sleep(2 * watchPollInterval) // The fake can't watch, so let a relist see it
+	node.Status, node.Conditions[0].Status = api.NodeNotReady, api.ConditionFalse
+	if err := client.UpdateNode(node); err != nil {
+		t.Fatalf("UpdateNode: %v", err)
+	}
+	waitForLines(t, out, 2)
+	stop()
+
+	if got, want := eventLines(out.String()), []string{"ADDED Ready", "MODIFIED NotReady"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected events %v, got:\n%s", want, out.String())
+	}
+}
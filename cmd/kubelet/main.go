@@ -1,180 +1,200 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/componentconfig"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubelet"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/logs"
 )
 
-const DefaultNamespace = "default"
-
-// Kubelet represents a node agent.
-type Kubelet struct {
-	NodeName    string
-	NodeAddress string // Mock address for this Kubelet/Node
-	APIClient   *api.Client
-	// knownPods map[string]api.PodPhase // To track pods it's "running"
-}
+func main() {
+	nodeName := flag.String("name", "", "Name of this node (kubelet), or the name prefix with -virtual-nodes")
+	nodeAddress := flag.String("address", "localhost:10250", "Address of this node (e.g. IP or hostname); the kubelet serves its API on its port")
+	serve := flag.Bool("serve", true, "Serve the kubelet API, which the API server proxies pod logs and exec to, on the port of -address")
+	token := flag.String("token", "", "Bearer token the kubelet API requires (empty accepts any request)")
+	apiServerURL := flag.String("apiserver", "http://localhost:8080", "URL of the API server")
+	syncInterval := flag.Duration("sync-interval", 10*time.Second, "Pod synchronization interval")
+	rootDir := flag.String("root-dir", "", "Directory for pod volumes (default <tmp>/k8s-lite-kubelet/<name>)")
+	pullDelay := flag.Duration("image-pull-delay", 0, "How long each simulated image pull takes")
+	pullFailureRate := flag.Float64("image-pull-failure-rate", 0, "Fraction of simulated image pulls that fail, from 0 to 1")
+	pullBackOff := flag.Duration("image-pull-backoff", 10*time.Second, "Delay before retrying a failed image pull; doubles with each failure, up to 5m")
+	preloadedImages := flag.String("preloaded-images", "", "Comma-separated images already present on the node")
+	privateRegistries := flag.String("private-registries", "", "Comma-separated HOST=USER:PASSWORD registries whose simulated pulls need those credentials, from a pod's imagePullSecrets")
+	memoryCapacity := flag.String("memory-capacity", "", "Memory of the node, e.g. 4Gi; the scheduler places pods whose memory requests fit in it less -eviction-hard, and pods are evicted, BestEffort first and Guaranteed last, when their simulated use leaves less than -eviction-hard available (empty is unlimited and never evicts)")
+	cpuCapacity := flag.String("cpu-capacity", "", "CPU of the node, e.g. 4 or 3500m; the scheduler places pods whose CPU requests fit in it (empty is unlimited)")
+	evictionHard := flag.String("eviction-hard", "memory.available<100Mi", "Memory to keep available on the node, as memory.available<QUANTITY")
+	containerLogMaxSize := flag.String("container-log-max-size", "10Mi", "Size a container's log file grows to before it is rotated, e.g. 10Mi")
+	containerLogMaxFiles := flag.Int("container-log-max-files", kubelet.DefaultContainerLogMaxFiles, "Number of log files kept of each run of a container, the current one included; the previous run's are kept too, for logs --previous")
+	virtualNodes := flag.Int("virtual-nodes", 0, "Simulate this many nodes, named <name>-1 to <name>-N, from this one process (0 runs the single node <name>)")
+	timeScale := flag.String("time-scale", "1x", "Run intervals and back-offs this many times faster than real time, e.g. 10x")
+	var chaosConfig chaos.Config
+	chaosConfig.AddKubeletFlags(flag.CommandLine)
+	logs.AddFlags(flag.CommandLine)
+	cfg := componentconfig.AddFlags(flag.CommandLine, "kubelet")
+	flag.Parse()
+	if err := cfg.Load(); err != nil {
+		log.Fatalf("%v", err)
+	}
 
-func NewKubelet(nodeName, nodeAddress, apiServerURL string) (*Kubelet, error) {
-	client, err := api.NewClient(apiServerURL)
+	if *nodeName == "" {
+		log.Fatalf("Node name must be specified using -name flag")
+	}
+	if *virtualNodes < 0 {
+		log.Fatalf("-virtual-nodes must not be negative, got %d", *virtualNodes)
+	}
+	if *pullFailureRate < 0 || *pullFailureRate > 1 {
+		log.Fatalf("-image-pull-failure-rate must be between 0 and 1, got %v", *pullFailureRate)
+	}
+	err := chaosConfig.Validate()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create API client: %w", err)
-	}
-	return &Kubelet{
-		NodeName:    nodeName,
-		NodeAddress: nodeAddress,
-		APIClient:   client,
-		// knownPods:  make(map[string]api.PodPhase),
-	}, nil
-}
+		log.Fatalf("%v", err)
+	}
 
-// registerNode registers this Kubelet's node with the API server.
-func (k *Kubelet) registerNode() error {
-	node := &api.Node{
-		Name:    k.NodeName,
-		Address: k.NodeAddress,
-		Status:  api.NodeReady, // Assume ready on startup
-	}
-	createdNode, err := k.APIClient.CreateNode(node)
-	if err != nil {
-		// It might already exist if Kubelet restarted, try to update (get and then put if needed)
-		// For simplicity, we'll just log an error. A real Kubelet would handle this more gracefully.
-		log.Printf("Failed to register node %s, attempting to update: %v", k.NodeName, err)
-		// Attempt to update if creation failed (e.g. node already exists)
-		if errUpdate := k.APIClient.UpdateNode(node); errUpdate != nil {
-			return fmt.Errorf("failed to register or update node %s: %w (update error: %v)", k.NodeName, err, errUpdate)
+	var capacity int64
+	if *memoryCapacity != "" {
+		if capacity, err = api.ParseResourceQuantity(api.ResourceMemory, *memoryCapacity); err != nil {
+			log.Fatalf("Invalid -memory-capacity: %v", err)
 		}
-		log.Printf("Node %s updated successfully after initial registration failure.", k.NodeName)
-		return nil
 	}
-	log.Printf("Node %s registered successfully with address %s and status %s", createdNode.Name, createdNode.Address, createdNode.Status)
-	return nil
-}
-
-// syncPods is the main loop for the Kubelet to manage pods on its node.
-func (k *Kubelet) syncPods() {
-	log.Printf("[%s] Syncing pods...", k.NodeName)
-
-	// 1. Get all pods in the default namespace
-	pods, err := k.APIClient.ListPods(DefaultNamespace, "") // Get all pods, any phase
+	var cpu int64
+	if *cpuCapacity != "" {
+		if cpu, err = api.ParseResourceQuantity(api.ResourceCPU, *cpuCapacity); err != nil {
+			log.Fatalf("Invalid -cpu-capacity: %v", err)
+		}
+	}
+	logMaxSize, err := api.ParseResourceQuantity(api.ResourceMemory, *containerLogMaxSize)
+	if err != nil || logMaxSize <= 0 {
+		log.Fatalf("Invalid -container-log-max-size %q: must be a positive size such as 10Mi", *containerLogMaxSize)
+	}
+	if *containerLogMaxFiles < 1 {
+		log.Fatalf("-container-log-max-files must be at least 1, got %d", *containerLogMaxFiles)
+	}
+	threshold, err := parseEvictionHard(*evictionHard)
 	if err != nil {
-		log.Printf("[%s] Error fetching pods: %v", k.NodeName, err)
-		return
+		log.Fatalf("Invalid -eviction-hard: %v", err)
+	}
+	registries, err := parsePrivateRegistries(*privateRegistries)
+	if err != nil {
+		log.Fatalf("Invalid -private-registries: %v", err)
 	}
 
-	for _, pod := range pods {
-		// Check if the pod is scheduled to this node
-		if pod.NodeName == k.NodeName {
+	scale, err := clock.ParseScale(*timeScale)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
 
-			// **NEW SECTION: Handle terminating pods first**
-			if pod.DeletionTimestamp != nil {
-				// If the pod is marked for deletion, process its termination.
-				if pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed && pod.Phase != api.PodDeleted { // Also check against PodDeleted
-					log.Printf("[%s] Detected terminating pod %s. Simulating cleanup and marking as Deleted.", k.NodeName, pod.Name)
-					updatedPod := pod                 // Make a copy
-					updatedPod.Phase = api.PodDeleted // CHANGE THIS LINE
-					// updatedPod.Phase = api.PodSucceeded (OLD LINE)
+	log.Printf("Kubelet for node '%s' starting. Node address: %s. API Server: %s", *nodeName, *nodeAddress, *apiServerURL)
 
-					if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
-						log.Printf("[%s] Error updating pod %s to Deleted after termination: %v", k.NodeName, pod.Name, err)
-					} else {
-						log.Printf("[%s] Pod %s marked as Deleted after termination processing.", k.NodeName, pod.Name)
-					}
-				} else {
-					// Pod is terminating but already in a final state (Succeeded, Failed, or Deleted).
-					log.Printf("[%s] Pod %s is terminating and already in state %s. No Kubelet action needed.", k.NodeName, pod.Name, pod.Phase)
-				}
-				continue
+	pulls := kubelet.ImagePullOptions{Delay: *pullDelay, FailureRate: *pullFailureRate, BackOff: *pullBackOff, PrivateRegistries: registries}
+	for _, image := range strings.Split(*preloadedImages, ",") {
+		if image = strings.TrimSpace(image); image != "" {
+			pulls.Preloaded = append(pulls.Preloaded, image)
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	clk := clock.ForScale(scale)
+	injector := chaos.New(chaosConfig)
+	// reloadConfig puts changes to the settings that can be reloaded into kubelets until
+	// ctx is cancelled.
+	reloadConfig := func(kubelets ...*kubelet.Kubelet) {
+		cfg.Watch(ctx, []string{"v", "sync-interval", "image-pull-delay", "image-pull-failure-rate", "image-pull-backoff"}, func() error {
+			if *pullFailureRate < 0 || *pullFailureRate > 1 {
+				return fmt.Errorf("image-pull-failure-rate must be between 0 and 1, got %v", *pullFailureRate)
 			}
-			// **END OF NEW SECTION**
-
-			// Original switch statement, now effectively for non-terminating pods
-			switch pod.Phase {
-			case api.PodScheduled:
-				log.Printf("[%s] Found scheduled pod %s. 'Starting' it...", k.NodeName, pod.Name)
-				updatedPod := pod
-				updatedPod.Phase = api.PodRunning
-				if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
-					log.Printf("[%s] Error updating pod %s to Running: %v", k.NodeName, pod.Name, err)
-				} else {
-					log.Printf("[%s] Pod %s with image '%s' is now 'Running'.", k.NodeName, pod.Name, pod.Image)
-				}
-			case api.PodRunning:
-				// log.Printf("[%s] Pod %s is already running.", k.NodeName, pod.Name)
-				// Potentially check health here
-				break
-
-			case api.PodTerminating:
-				log.Printf("[%s] Pod %s found in Terminating phase. Processing termination.", k.NodeName, pod.Name)
-				if pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed && pod.Phase != api.PodDeleted { // Also check against PodDeleted
-					updatedPod := pod
-					updatedPod.Phase = api.PodDeleted // CHANGE THIS
-					if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
-						log.Printf("[%s] Error updating pod %s from Terminating to Deleted: %v", k.NodeName, pod.Name, err)
-					} else {
-						log.Printf("[%s] Pod %s (in Terminating phase) marked as Deleted.", k.NodeName, pod.Name)
-					}
-				}
-
-			case api.PodDeleting: // This was an older phase name you had.
-				log.Printf("[%s] Detected pod %s in PodDeleting phase. Handling as terminating.", k.NodeName, pod.Name)
-				// Similar logic to PodTerminating or rely on DeletionTimestamp check
-				if pod.DeletionTimestamp == nil { // If timestamp wasn't set, but phase is Deleting
-					log.Printf("[%s] Warning: Pod %s in PodDeleting phase but DeletionTimestamp is nil. This should be synchronized.", k.NodeName, pod.Name)
-				}
-				// The DeletionTimestamp check at the top should handle most cases.
-				// If we reach here and it's not Succeeded/Failed, update it.
-				if pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed {
-					updatedPod := pod
-					updatedPod.Phase = api.PodSucceeded
-					if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
-						log.Printf("[%s] Error updating pod %s from PodDeleting to Succeeded: %v", k.NodeName, pod.Name, err)
-					} else {
-						log.Printf("[%s] Pod %s (in PodDeleting phase) marked as Succeeded.", k.NodeName, pod.Name)
-					}
-				}
-
-			default:
-				// Do nothing for other phases like Pending (handled by scheduler), Succeeded, Failed (final states)
-				if pod.Phase != api.PodPending && pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed {
-					log.Printf("[%s] Pod %s found in unhandled phase: %s", k.NodeName, pod.Name, pod.Phase)
-				}
+			for _, k := range kubelets {
+				k.SetSyncInterval(*syncInterval)
+				k.SetImagePullOptions(kubelet.ImagePullOptions{Delay: *pullDelay, FailureRate: *pullFailureRate, BackOff: *pullBackOff})
 			}
-		}
+			return nil
+		})
 	}
-	// TODO: Implement logic to detect and "stop" pods that were running on this node but are no longer in the API server's list
-}
 
-func main() {
-	nodeName := flag.String("name", "", "Name of this node (kubelet)")
-	nodeAddress := flag.String("address", "localhost:10250", "Address of this node (e.g. IP or hostname, port is informational for mock)")
-	apiServerURL := flag.String("apiserver", "http://localhost:8080", "URL of the API server")
-	syncInterval := flag.Duration("sync-interval", 10*time.Second, "Pod synchronization interval")
-	flag.Parse()
-
-	if *nodeName == "" {
-		log.Fatalf("Node name must be specified using -name flag")
+	if *virtualNodes == 0 {
+		client, err := api.NewClient(*apiServerURL, api.WithResponseCache())
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+		k := kubelet.NewKubelet(client, *nodeName, *nodeAddress, *rootDir, *syncInterval, pulls, clk)
+		k.Chaos = injector
+		k.Serve = *serve
+		k.ServerToken = *token
+		k.MemoryCapacity, k.EvictionThreshold = capacity, threshold
+		k.CPUCapacity = cpu
+		k.ContainerLogMaxSize, k.ContainerLogMaxFiles = logMaxSize, *containerLogMaxFiles
+		go reloadConfig(k)
+		if err := k.Run(ctx); err != nil {
+			log.Fatalf("%v. Ensure API server is running.", err)
+		}
+		return
 	}
 
-	log.Printf("Kubelet for node '%s' starting. Node address: %s. API Server: %s", *nodeName, *nodeAddress, *apiServerURL)
-
-	k, err := NewKubelet(*nodeName, *nodeAddress, *apiServerURL)
+	// The virtual nodes share one client; give it enough connections for all of them.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *virtualNodes
+	client, err := api.NewClient(*apiServerURL, api.WithTransport(transport), api.WithResponseCache())
 	if err != nil {
-		log.Fatalf("Failed to create Kubelet: %v", err)
+		log.Fatalf("Failed to create API client: %v", err)
 	}
-
-	if err := k.registerNode(); err != nil {
-		log.Fatalf("Failed to register node with API server: %v. Ensure API server is running.", err)
+	var kubelets []*kubelet.Kubelet
+	for _, node := range kubelet.VirtualNodes(*nodeName, *nodeAddress, *virtualNodes) {
+		dir := ""
+		if *rootDir != "" {
+			dir = filepath.Join(*rootDir, node.Name)
+		}
+		k := kubelet.NewKubelet(client, node.Name, node.Address, dir, *syncInterval, pulls, clk)
+		k.Chaos = injector
+		k.Serve = *serve
+		k.ServerToken = *token
+		k.MemoryCapacity, k.EvictionThreshold = capacity, threshold
+		k.CPUCapacity = cpu
+		k.ContainerLogMaxSize, k.ContainerLogMaxFiles = logMaxSize, *containerLogMaxFiles
+		kubelets = append(kubelets, k)
+	}
+	go reloadConfig(kubelets...)
+	log.Printf("Simulating %d virtual nodes, %s to %s", len(kubelets), kubelets[0].NodeName, kubelets[len(kubelets)-1].NodeName)
+	if err := kubelet.RunAll(ctx, kubelets...); err != nil {
+		log.Fatalf("%v. Ensure API server is running.", err)
 	}
+}
 
-	log.Printf("Kubelet for node '%s' registered. Starting pod sync loop with interval %v.", *nodeName, *syncInterval)
+// parseEvictionHard returns the memory a -eviction-hard setting such as
+// "memory.available<100Mi" keeps available.
+func parseEvictionHard(s string) (int64, error) {
+	quantity, ok := strings.CutPrefix(strings.TrimSpace(s), "memory.available<")
+	if !ok {
+		return 0, fmt.Errorf("%q is not of the form memory.available<QUANTITY", s)
+	}
+	return api.ParseResourceQuantity(api.ResourceMemory, quantity)
+}
 
-	for {
-		k.syncPods()
-		time.Sleep(*syncInterval)
+// parsePrivateRegistries parses -private-registries, a comma-separated list of
+// HOST=USER:PASSWORD, into the credentials each host accepts.
+func parsePrivateRegistries(s string) (map[string]string, error) {
+	registries := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		host, credentials, ok := strings.Cut(entry, "=")
+		if !ok || host == "" || !strings.Contains(credentials, ":") {
+			return nil, fmt.Errorf("%q is not of the form HOST=USER:PASSWORD", entry)
+		}
+		registries[host] = credentials
 	}
+	return registries, nil
 }
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apiserver"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubelet"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/scheduler"
+)
+
+// benchNamespace is where the benchmark pods go; the scheduler and kubelets only look
+// at the default namespace.
+const benchNamespace = "default"
+
+type benchOptions struct {
+	apiServer         string
+	pods              int
+	nodes             int
+	concurrency       int
+	batchSize         int
+	image             string
+	schedulerInterval time.Duration
+	kubeletSync       time.Duration
+	pollInterval      time.Duration
+	timeout           time.Duration
+	verbose           bool
+	storeShards       int
+}
+
+// podTimes records when a benchmark pod was created and when it was first seen bound
+// to a node and Running.
+type podTimes struct {
+	created   time.Time
+	failed    bool // The create request failed
+	scheduled time.Time
+	running   time.Time
+}
+
+// runBench starts the simulated nodes (and, without --apiserver, an API server and
+// scheduler), creates the pods, and waits for them to run.
+func runBench(ctx context.Context, o benchOptions) (*report, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// Every component shares one client whose requests are timed. The default transport
+	// keeps only two idle connections per host, far too few for this many clients.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = o.nodes + o.concurrency + 2
+	latencies := newLatencyRecorder(transport)
+
+	apiServerURL := o.apiServer
+	if apiServerURL == "" {
+		url, err := startControlPlane(ctx, &wg, o, latencies)
+		if err != nil {
+			return nil, err
+		}
+		apiServerURL = url
+	}
+	client, err := api.NewClient(apiServerURL, api.WithTransport(latencies))
+	if err != nil {
+		return nil, err
+	}
+	// Progress is checked with a separate client so the polling doesn't show up in the
+	// latency report.
+	observer, err := api.NewClient(apiServerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	rootDir, err := os.MkdirTemp("", "kubelite-bench-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(rootDir)
+	runID := strconv.FormatInt(time.Now().Unix(), 36)
+	nodeNames := make(map[string]bool, o.nodes)
+	for i := 1; i <= o.nodes; i++ {
+		name := fmt.Sprintf("bench-%s-node%d", runID, i)
+		nodeNames[name] = true
+		k := kubelet.NewKubelet(client, name, fmt.Sprintf("%s:10250", name), filepath.Join(rootDir, name),
+			o.kubeletSync, kubelet.ImagePullOptions{}, clock.RealClock{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k.Run(ctx)
+		}()
+	}
+	if err := waitForNodes(ctx, observer, nodeNames, o.pollInterval); err != nil {
+		return nil, err
+	}
+	// Only the pods' traffic counts, not the nodes registering.
+	latencies.reset()
+
+	r := &report{
+		inProcess: o.apiServer == "",
+		nodes:     o.nodes,
+		pods:      make(map[string]*podTimes, o.pods),
+		interval:  o.pollInterval,
+	}
+	for i := 1; i <= o.pods; i++ {
+		r.pods[fmt.Sprintf("bench-%s-%d", runID, i)] = &podTimes{}
+	}
+
+	var mu sync.Mutex // guards r.pods entries while the pods are created and observed
+	start := time.Now()
+	observed := make(chan struct{})
+	go func() {
+		defer close(observed)
+		observe(ctx, observer, r, &mu, o.pollInterval, start.Add(o.timeout))
+	}()
+	createPods(client, r, &mu, o)
+	r.createDuration = time.Since(start)
+	<-observed
+	r.finish(start, latencies.snapshot())
+
+	if o.apiServer != "" {
+		// Leave the cluster as we found it; an in-process one goes away on its own.
+		cleanUp(ctx, client, observer, r, nodeNames, o)
+	}
+	return r, ctx.Err()
+}
+
+// startControlPlane starts an in-process API server on a loopback port and a
+// scheduler, and returns the API server's URL.
+func startControlPlane(ctx context.Context, wg *sync.WaitGroup, o benchOptions, rt http.RoundTripper) (string, error) {
+	dataStore, err := apiserver.OpenStore("", o.storeShards)
+	if err != nil {
+		return "", err
+	}
+	podIPs, err := apiserver.NewPodIPAllocator("10.0.0.0/8")
+	if err != nil {
+		return "", err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	url := "http://" + ln.Addr().String()
+	client, err := api.NewClient(url, api.WithTransport(rt))
+	if err != nil {
+		ln.Close()
+		return "", err
+	}
+
+	server := apiserver.NewAPIServer(dataStore, podIPs)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		server.Serve(ctx, ln)
+	}()
+	go func() {
+		defer wg.Done()
+		recorder := record.NewRecorder(client, api.EventSource{Component: "scheduler"})
+		scheduler.NewScheduler(client, recorder, o.schedulerInterval, clock.RealClock{}).Run(ctx)
+	}()
+	return url, nil
+}
+
+// waitForNodes waits until every node in names is registered and Ready.
+func waitForNodes(ctx context.Context, client api.Interface, names map[string]bool, interval time.Duration) error {
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		nodes, err := client.ListNodes(api.NodeReady)
+		if err == nil {
+			ready := 0
+			for _, node := range nodes {
+				if names[node.Name] {
+					ready++
+				}
+			}
+			if ready == len(names) {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %d simulated nodes to become Ready (last error: %v)", len(names), err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// createPods creates the benchmark pods, o.concurrency requests at a time and
+// o.batchSize pods per request.
+func createPods(client api.Interface, r *report, mu *sync.Mutex, o benchOptions) {
+	batches := make(chan []string)
+	var wg sync.WaitGroup
+	for w := 0; w < o.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for names := range batches {
+				pods := make([]api.Pod, len(names))
+				mu.Lock()
+				for i, name := range names {
+					r.pods[name].created = time.Now()
+					pods[i] = api.Pod{
+						ObjectMeta: api.ObjectMeta{Name: name, Labels: map[string]string{"app": "kubelite-bench"}},
+						Image:      o.image,
+					}
+				}
+				mu.Unlock()
+				errs := createBatch(client, pods)
+				mu.Lock()
+				for i, err := range errs {
+					if err != nil {
+						r.pods[names[i]].failed = true
+						r.createErrors = append(r.createErrors, err)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	var batch []string
+	for name := range r.pods {
+		batch = append(batch, name)
+		if len(batch) == o.batchSize {
+			batches <- batch
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches <- batch
+	}
+	close(batches)
+	wg.Wait()
+}
+
+// createBatch creates pods, one request per pod if there is only one, and returns the
+// error creating each.
+func createBatch(client api.Interface, pods []api.Pod) []error {
+	errs := make([]error, len(pods))
+	if len(pods) == 1 {
+		_, errs[0] = client.CreatePod(benchNamespace, &pods[0])
+		return errs
+	}
+	results, err := client.CreatePods(benchNamespace, pods)
+	for i := range pods {
+		switch {
+		case err != nil:
+			errs[i] = err
+		case i >= len(results):
+			errs[i] = fmt.Errorf("no result for pod %s", pods[i].Name)
+		default:
+			errs[i] = results[i].Err()
+		}
+	}
+	return errs
+}
+
+// observe lists the pods every interval, recording when each was first seen bound to
+// a node and Running, until every created pod runs or the deadline passes.
+func observe(ctx context.Context, client api.Interface, r *report, mu *sync.Mutex, interval time.Duration, deadline time.Time) {
+	for {
+		pods, err := client.ListPods(benchNamespace, "")
+		now := time.Now()
+		if err == nil {
+			mu.Lock()
+			for _, pod := range pods {
+				t := r.pods[pod.Name]
+				if t == nil {
+					continue
+				}
+				if t.scheduled.IsZero() && pod.NodeName != "" {
+					t.scheduled = now
+				}
+				if t.running.IsZero() && pod.Phase == api.PodRunning {
+					t.running = now
+				}
+			}
+			done := true
+			for _, t := range r.pods {
+				if !t.failed && t.running.IsZero() {
+					done = false
+					break
+				}
+			}
+			mu.Unlock()
+			if done {
+				return
+			}
+		}
+		if now.After(deadline) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// cleanUp deletes the benchmark pods, waits for the simulated nodes to finish
+// deleting them, and then removes the nodes.
+func cleanUp(ctx context.Context, client, observer api.Interface, r *report, nodeNames map[string]bool, o benchOptions) {
+	deletePods(client, r, o.concurrency)
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) && ctx.Err() == nil {
+		pods, err := observer.ListPods(benchNamespace, "")
+		if err == nil {
+			remaining := 0
+			for _, pod := range pods {
+				if r.pods[pod.Name] != nil && pod.Phase != api.PodDeleted {
+					remaining++
+				}
+			}
+			if remaining == 0 {
+				break
+			}
+		}
+		time.Sleep(o.pollInterval)
+	}
+	for name := range nodeNames {
+		client.DeleteNode(name)
+	}
+}
+
+// deletePods deletes the benchmark pods, concurrency at a time.
+func deletePods(client api.Interface, r *report, concurrency int) {
+	names := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				client.DeletePod(benchNamespace, name)
+			}
+		}()
+	}
+	for name, t := range r.pods {
+		if !t.failed {
+			names <- name
+		}
+	}
+	close(names)
+	wg.Wait()
+}
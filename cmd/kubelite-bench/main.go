@@ -0,0 +1,60 @@
+// Command kubelite-bench load-tests a k8s-lite-go cluster: it creates pods for many
+// simulated nodes and reports how fast they were scheduled and started and how the
+// API server's latency held up.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	o := benchOptions{}
+	flag.StringVar(&o.apiServer, "apiserver", "", "URL of an API server (with a scheduler) to benchmark; empty starts an in-process API server and scheduler")
+	flag.IntVar(&o.pods, "pods", 100, "Number of pods to create")
+	flag.IntVar(&o.nodes, "nodes", 10, "Number of simulated nodes to run in this process")
+	flag.IntVar(&o.concurrency, "concurrency", 10, "Number of create requests sent in parallel")
+	flag.IntVar(&o.batchSize, "batch-size", 1, "Number of pods created per request; above 1 they are created in batches")
+	flag.StringVar(&o.image, "image", "registry.k8s.io/pause:3.9", "Image of the benchmark pods")
+	flag.DurationVar(&o.schedulerInterval, "scheduler-interval", 100*time.Millisecond, "Scheduling interval of the in-process scheduler")
+	flag.DurationVar(&o.kubeletSync, "kubelet-sync-interval", 100*time.Millisecond, "Pod synchronization interval of each simulated node")
+	flag.DurationVar(&o.pollInterval, "poll-interval", 50*time.Millisecond, "How often to check pod progress; bounds the precision of the pod timings")
+	flag.DurationVar(&o.timeout, "timeout", 5*time.Minute, "Give up if not every pod is Running after this long")
+	flag.IntVar(&o.storeShards, "store-shards", 1, "Number of shards the in-process API server's store splits each resource into, each with its own lock")
+	flag.BoolVar(&o.verbose, "v", false, "Show the logs of the in-process components")
+	flag.Parse()
+
+	if o.pods < 1 || o.nodes < 1 || o.concurrency < 1 || o.batchSize < 1 {
+		log.Fatalf("-pods, -nodes, -concurrency, and -batch-size must be at least 1")
+	}
+	if !o.verbose {
+		// The components log every sync; the report is what matters here.
+		log.SetOutput(io.Discard)
+		gin.DefaultWriter = io.Discard
+	}
+	gin.SetMode(gin.ReleaseMode)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	r, err := runBench(ctx, o)
+	if r != nil {
+		r.print(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if r.running < o.pods {
+		fmt.Fprintf(os.Stderr, "Error: only %d of %d pods were Running after %v\n", r.running, o.pods, o.timeout)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// latencyRecorder is an http.RoundTripper that times every request it sends, grouped
+// by what the request does, such as "create pods" or "list nodes".
+type latencyRecorder struct {
+	next http.RoundTripper
+
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int // Requests that failed or got a 5xx
+}
+
+func newLatencyRecorder(next http.RoundTripper) *latencyRecorder {
+	l := &latencyRecorder{next: next}
+	l.reset()
+	return l
+}
+
+func (l *latencyRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := l.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	op := operation(req.Method, req.URL.Path)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.latencies[op] = append(l.latencies[op], elapsed)
+	if err != nil || resp.StatusCode >= 500 {
+		l.errors[op]++
+	}
+	return resp, err
+}
+
+// reset forgets everything recorded so far.
+func (l *latencyRecorder) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.latencies = make(map[string][]time.Duration)
+	l.errors = make(map[string]int)
+}
+
+// snapshot summarizes the recorded requests, one row per operation.
+func (l *latencyRecorder) snapshot() []latencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make([]latencyStats, 0, len(l.latencies))
+	for op, latencies := range l.latencies {
+		stats = append(stats, latencyStats{operation: op, errors: l.errors[op], durations: summarize(latencies)})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].operation < stats[j].operation })
+	return stats
+}
+
+// operation names what an API request does from its method and path, e.g.
+// "GET /api/v1/namespaces/default/pods" is "list pods" and
+// "POST /api/v1/namespaces/default/pods/web/eviction" is "create pods/eviction".
+func operation(method, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return method + " " + path
+	}
+	// Namespaced resources live under namespaces/<namespace>/.
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		return method + " " + path
+	}
+	resource := segments[0]
+	named := len(segments) > 1
+	if len(segments) > 2 {
+		resource += "/" + strings.Join(segments[2:], "/")
+	}
+
+	verb := strings.ToLower(method)
+	switch method {
+	case http.MethodGet:
+		verb = "get"
+		if !named {
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodDelete:
+		verb = "delete"
+		if !named {
+			verb = "deletecollection"
+		}
+	}
+	return verb + " " + resource
+}
+
+// durationStats summarizes a set of durations.
+type durationStats struct {
+	count              int
+	p50, p90, p99, max time.Duration
+}
+
+// summarize returns the count, percentiles, and maximum of durations.
+func summarize(durations []time.Duration) durationStats {
+	if len(durations) == 0 {
+		return durationStats{}
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return durationStats{
+		count: len(sorted),
+		p50:   percentile(sorted, 0.50),
+		p90:   percentile(sorted, 0.90),
+		p99:   percentile(sorted, 0.99),
+		max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted, which must not be
+// empty.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+type latencyStats struct {
+	operation string
+	errors    int
+	durations durationStats
+}
+
+// report is the outcome of a benchmark run.
+type report struct {
+	inProcess bool
+	nodes     int
+	interval  time.Duration // How often pod progress was checked
+	pods      map[string]*podTimes
+
+	createDuration time.Duration
+	createErrors   []error
+
+	created, scheduled, running int
+	scheduleSpan, runSpan       time.Duration // From the first create to the last pod bound or Running
+	toSchedule, toRun           durationStats
+	api                         []latencyStats
+}
+
+// finish computes the report's statistics from the recorded pod times. start is when
+// the first pod was created.
+func (r *report) finish(start time.Time, api []latencyStats) {
+	var toSchedule, toRun []time.Duration
+	for _, t := range r.pods {
+		if t.failed {
+			continue
+		}
+		r.created++
+		if !t.scheduled.IsZero() {
+			r.scheduled++
+			toSchedule = append(toSchedule, t.scheduled.Sub(t.created))
+			if span := t.scheduled.Sub(start); span > r.scheduleSpan {
+				r.scheduleSpan = span
+			}
+		}
+		if !t.running.IsZero() {
+			r.running++
+			toRun = append(toRun, t.running.Sub(t.created))
+			if span := t.running.Sub(start); span > r.runSpan {
+				r.runSpan = span
+			}
+		}
+	}
+	r.toSchedule = summarize(toSchedule)
+	r.toRun = summarize(toRun)
+	r.api = api
+}
+
+// rate formats n pods over d as pods per second.
+func rate(n int, d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f pods/s", float64(n)/d.Seconds())
+}
+
+func (r *report) print(out io.Writer) {
+	cluster := "external API server"
+	if r.inProcess {
+		cluster = "in-process API server and scheduler"
+	}
+	fmt.Fprintf(out, "Cluster:     %s, %d simulated nodes\n", cluster, r.nodes)
+	fmt.Fprintf(out, "Created:     %d/%d pods in %v (%s)\n", r.created, len(r.pods), r.createDuration.Round(time.Millisecond), rate(r.created, r.createDuration))
+	fmt.Fprintf(out, "Scheduled:   %d pods in %v (%s)\n", r.scheduled, r.scheduleSpan.Round(time.Millisecond), rate(r.scheduled, r.scheduleSpan))
+	fmt.Fprintf(out, "Running:     %d pods in %v (%s)\n", r.running, r.runSpan.Round(time.Millisecond), rate(r.running, r.runSpan))
+	if len(r.createErrors) > 0 {
+		fmt.Fprintf(out, "Create errors: %d, first: %v\n", len(r.createErrors), r.createErrors[0])
+	}
+
+	fmt.Fprintf(out, "\nPod timings (checked every %v):\n", r.interval)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "  PHASE\tCOUNT\tP50\tP90\tP99\tMAX")
+	printStats(w, "create to scheduled", r.toSchedule, "")
+	printStats(w, "create to running", r.toRun, "")
+	w.Flush()
+
+	fmt.Fprintln(out, "\nAPI latency:")
+	w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "  REQUEST\tCOUNT\tP50\tP90\tP99\tMAX\tERRORS")
+	for _, s := range r.api {
+		printStats(w, s.operation, s.durations, fmt.Sprintf("\t%d", s.errors))
+	}
+	w.Flush()
+}
+
+func printStats(w io.Writer, name string, s durationStats, extra string) {
+	round := func(d time.Duration) time.Duration {
+		switch {
+		case d < time.Millisecond:
+			return d.Round(time.Microsecond)
+		case d < time.Second:
+			return d.Round(100 * time.Microsecond)
+		default:
+			return d.Round(time.Millisecond)
+		}
+	}
+	fmt.Fprintf(w, "  %s\t%d\t%v\t%v\t%v\t%v%s\n", name, s.count, round(s.p50), round(s.p90), round(s.p99), round(s.max), extra)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/api/v1/namespaces/default/pods", "list pods"},
+		{"GET", "/api/v1/namespaces/default/pods/web", "get pods"},
+		{"POST", "/api/v1/namespaces/default/pods", "create pods"},
+		{"PUT", "/api/v1/namespaces/default/pods/web", "update pods"},
+		{"DELETE", "/api/v1/namespaces/default/pods", "deletecollection pods"},
+		{"POST", "/api/v1/namespaces/default/pods/web/eviction", "create pods/eviction"},
+		{"GET", "/api/v1/nodes", "list nodes"},
+		{"PUT", "/api/v1/nodes/node1", "update nodes"},
+		{"GET", "/api/v1/namespaces", "list namespaces"},
+		{"DELETE", "/api/v1/namespaces/team-a", "delete namespaces"},
+		{"GET", "/apis/apps/v1/namespaces/default/deployments", "list deployments"},
+		{"GET", "/healthz", "GET /healthz"},
+	}
+	for _, tt := range tests {
+		if got := operation(tt.method, tt.path); got != tt.want {
+			t.Errorf("operation(%s, %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	got := summarize(durations)
+	want := durationStats{count: 100, p50: 50 * time.Millisecond, p90: 90 * time.Millisecond, p99: 99 * time.Millisecond, max: 100 * time.Millisecond}
+	if got != want {
+		t.Errorf("summarize = %+v, want %+v", got, want)
+	}
+	if got := summarize([]time.Duration{time.Second}); got.p50 != time.Second || got.p99 != time.Second {
+		t.Errorf("expected every percentile of one duration to be it, got %+v", got)
+	}
+	if got := summarize(nil); got != (durationStats{}) {
+		t.Errorf("expected zero stats for no durations, got %+v", got)
+	}
+}
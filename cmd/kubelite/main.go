@@ -0,0 +1,20 @@
+// Command kubelite runs a whole k8s-lite-go cluster in a single process.
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:          "kubelite",
+		Short:        "kubelite runs a k8s-lite-go cluster in a single process",
+		SilenceUsage: true,
+	}
+	root.AddCommand(newUpCommand())
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
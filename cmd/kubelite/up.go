@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apiserver"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/autoscaler"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controllermanager"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubelet"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/scheduler"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+// upOptions configures the cluster started by "kubelite up". The defaults match those
+// of the standalone binaries.
+type upOptions struct {
+	nodes              int
+	port               string
+	podCIDR            string
+	journalFile        string
+	storeShards        int
+	rootDir            string
+	schedulerInterval  time.Duration
+	kubeletSync        time.Duration
+	controllerSync     time.Duration
+	controllerWorkers  int
+	kubeletAddressBase int
+	timeScale          string
+	slowRequests       time.Duration
+	requestTimeout     time.Duration
+	maxInFlight        int
+	corsOrigins        []string
+	nodeApproval       bool
+	privateRegistries  map[string]string
+	chaos              chaos.Config
+	autoscaler         autoscaler.Options
+	controllers        []string
+}
+
+func newUpCommand() *cobra.Command {
+	o := &upOptions{}
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Start an API server, scheduler, controller manager, and simulated nodes",
+		Long: `Start an API server, scheduler, controller manager, and --nodes simulated kubelets
+in this process, and run them until interrupted.
+
+Nodes are named node1, node2, and so on. Point kubectl-lite at the API server with
+--apiserver http://localhost:<port>.
+
+With --autoscale-max-nodes, a cluster autoscaler adds up to that many more nodes,
+named autoscaled-1, autoscaled-2, and so on, while pods are unschedulable, and
+removes them once they have run no pods for --autoscale-scale-down-delay.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.nodes < 0 {
+				return fmt.Errorf("--nodes must not be negative, got %d", o.nodes)
+			}
+			if o.autoscaler.MaxNodes < 0 {
+				return fmt.Errorf("--autoscale-max-nodes must not be negative, got %d", o.autoscaler.MaxNodes)
+			}
+			for host, credentials := range o.privateRegistries {
+				if !strings.Contains(credentials, ":") {
+					return fmt.Errorf("--private-registry %s must be given USER:PASSWORD, got %q", host, credentials)
+				}
+			}
+			if err := controllermanager.ValidateControllers(o.controllers); err != nil {
+				return err
+			}
+			if err := o.chaos.Validate(); err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runUp(ctx, o)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&o.nodes, "nodes", 1, "Number of simulated nodes")
+	flags.StringVar(&o.port, "port", "8080", "Port to serve the API on")
+	flags.StringVar(&o.podCIDR, "pod-cidr", "10.244.0.0/16", "CIDR to assign pod IPs from (empty disables pod IP allocation)")
+	flags.StringVar(&o.journalFile, "journal-file", "", "File to journal every write to and restore the cluster from on startup (empty keeps the cluster in memory only)")
+	flags.IntVar(&o.storeShards, "store-shards", 1, "Number of shards, each with its own lock, to split each resource's objects into in the API server's store (1 guards the whole store with one lock)")
+	flags.StringVar(&o.rootDir, "root-dir", filepath.Join(os.TempDir(), "k8s-lite-kubelet"), "Directory for pod volumes; each node gets a subdirectory named after it")
+	flags.DurationVar(&o.schedulerInterval, "scheduler-interval", 5*time.Second, "Scheduling interval")
+	flags.DurationVar(&o.kubeletSync, "kubelet-sync-interval", 10*time.Second, "Pod synchronization interval of each kubelet")
+	flags.DurationVar(&o.controllerSync, "controller-sync-interval", 2*time.Second, "How often controllers poll the API server for changes")
+	flags.IntVar(&o.controllerWorkers, "workers", 2, "Number of workers per controller")
+	flags.StringSliceVar(&o.controllers, "controllers", []string{"*"}, "Controllers to run: * for all, NAME to add one, -NAME to leave one out (known: "+strings.Join(controllermanager.KnownControllers, ", ")+")")
+	flags.StringVar(&o.timeScale, "time-scale", "1x", "Run intervals and back-offs this many times faster than real time, e.g. 10x")
+	flags.DurationVar(&o.slowRequests, "slow-request-threshold", apiserver.DefaultSlowRequestThreshold, "Log API requests that take longer than this (0 disables)")
+	flags.DurationVar(&o.requestTimeout, "request-timeout", apiserver.DefaultRequestTimeout, "Fail API requests other than watches and streams that take longer than this with 504 (0 disables)")
+	flags.IntVar(&o.maxInFlight, "max-requests-inflight", apiserver.DefaultMaxRequestsInFlight, "API requests to run at once, shared between priority levels (node heartbeats, other requests, and lists); more wait their turn or get 429 (0 disables)")
+	flags.StringSliceVar(&o.corsOrigins, "cors-allowed-origins", nil, "Origins whose browser scripts may call the API, e.g. http://localhost:8081 (* allows any)")
+	flags.BoolVar(&o.nodeApproval, "require-node-approval", false, "Hold newly registered nodes, including those of this command, NotReady and unschedulable until approved with kubectl-lite certificate approve")
+	flags.StringToStringVar(&o.privateRegistries, "private-registry", nil, "HOST=USER:PASSWORD of a registry whose simulated pulls need those credentials, from a pod's imagePullSecrets (repeatable)")
+	flags.IntVar(&o.kubeletAddressBase, "kubelet-port", 10250, "Port the first node's kubelet serves its API on; node N gets this plus N-1 (0 disables the kubelet API)")
+	flags.IntVar(&o.autoscaler.MaxNodes, "autoscale-max-nodes", 0, "Most nodes the cluster autoscaler adds for unschedulable pods, on top of --nodes (0 disables the autoscaler)")
+	flags.DurationVar(&o.autoscaler.ScaleDownDelay, "autoscale-scale-down-delay", 10*time.Minute, "How long a node the autoscaler added must run no pods before it is removed")
+	flags.DurationVar(&o.autoscaler.Interval, "autoscale-interval", 10*time.Second, "How often the cluster autoscaler checks for unschedulable pods and idle nodes")
+
+	chaosFlags := flag.NewFlagSet("chaos", flag.ContinueOnError)
+	o.chaos.AddAPIServerFlags(chaosFlags)
+	o.chaos.AddKubeletFlags(chaosFlags)
+	o.chaos.AddControllerFlags(chaosFlags)
+	flags.AddGoFlagSet(chaosFlags)
+	return cmd
+}
+
+// runUp starts every component in its own goroutine and waits until ctx is cancelled
+// or one of them fails, then stops the rest.
+func runUp(ctx context.Context, o *upOptions) error {
+	gin.SetMode(gin.ReleaseMode)
+	scale, err := clock.ParseScale(o.timeScale)
+	if err != nil {
+		return err
+	}
+	// Every component shares one clock so that they all agree on the time.
+	clk := clock.ForScale(scale)
+	podIPs, err := apiserver.NewPodIPAllocator(o.podCIDR)
+	if err != nil {
+		return fmt.Errorf("setting up pod IP allocation: %w", err)
+	}
+	dataStore, err := apiserver.OpenStore(o.journalFile, o.storeShards)
+	if err != nil {
+		return err
+	}
+	// Listen before starting anything else so the other components never race the
+	// API server's startup.
+	ln, err := net.Listen("tcp", ":"+o.port)
+	if err != nil {
+		return err
+	}
+	apiServerURL := fmt.Sprintf("http://localhost:%d", ln.Addr().(*net.TCPAddr).Port)
+	// The components talk to the API server over loopback like they would from their
+	// own processes; sharing one client between them is safe. They poll, so relists
+	// of unchanged pods and nodes are answered from the client's cache.
+	client, err := api.NewClient(apiServerURL, api.WithResponseCache())
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	run := func(name string, fn func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx); err != nil {
+				log.Printf("%s failed: %v", name, err)
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", name, err)
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+
+	// Nil unless a --chaos-* flag is set, in which case every component shares it.
+	injector := chaos.New(o.chaos)
+
+	// The kubelets only take proxied requests from this API server.
+	kubeletToken, err := randomToken()
+	if err != nil {
+		return err
+	}
+	server := apiserver.NewAPIServer(dataStore, podIPs)
+	server.Chaos = injector
+	server.SlowRequestThreshold = o.slowRequests
+	server.RequestTimeout = o.requestTimeout
+	server.MaxRequestsInFlight = o.maxInFlight
+	server.KubeletToken = kubeletToken
+	server.CORSAllowedOrigins = o.corsOrigins
+	server.RequireNodeApproval = o.nodeApproval
+	run("apiserver", func(ctx context.Context) error {
+		return server.Serve(ctx, ln)
+	})
+
+	run("scheduler", func(ctx context.Context) error {
+		recorder := record.NewRecorder(client, api.EventSource{Component: "scheduler"})
+		scheduler.NewScheduler(client, recorder, o.schedulerInterval, clk).Run(ctx)
+		return nil
+	})
+	run("controller-manager", func(ctx context.Context) error {
+		controllermanager.Run(ctx, client, controllermanager.Options{
+			SyncInterval: o.controllerSync,
+			Workers:      o.controllerWorkers,
+			Clock:        clk,
+			Chaos:        injector,
+			Controllers:  o.controllers,
+		})
+		return nil
+	})
+
+	// Every kubelet gets the next port after the previous one's, including those the
+	// autoscaler adds later.
+	nextKubeletPort := o.kubeletAddressBase
+	newKubelet := func(nodeName string) *kubelet.Kubelet {
+		k := kubelet.NewKubelet(client, nodeName, fmt.Sprintf("localhost:%d", nextKubeletPort),
+			filepath.Join(o.rootDir, nodeName), o.kubeletSync, kubelet.ImagePullOptions{BackOff: 10 * time.Second, PrivateRegistries: o.privateRegistries}, clk)
+		nextKubeletPort++
+		k.Chaos = injector
+		k.Serve = o.kubeletAddressBase != 0
+		k.ServerToken = kubeletToken
+		return k
+	}
+	for i := 1; i <= o.nodes; i++ {
+		nodeName := fmt.Sprintf("node%d", i)
+		run("kubelet "+nodeName, newKubelet(nodeName).Run)
+	}
+	if o.autoscaler.MaxNodes > 0 {
+		// The pool calls newKubelet with its lock held, one node at a time.
+		pool := kubelet.NewNodePool(ctx, newKubelet)
+		run("cluster-autoscaler", func(ctx context.Context) error {
+			recorder := record.NewRecorder(client, api.EventSource{Component: "cluster-autoscaler"})
+			autoscaler.New(client, pool, recorder, clk, o.autoscaler).Run(ctx)
+			return nil
+		})
+	}
+
+	log.Printf("Cluster up: API server at %s with %d node(s), time running at %vx. Press Ctrl-C to stop.", apiServerURL, o.nodes, scale)
+	if injector != nil {
+		log.Printf("Chaos mode on: %+v", o.chaos)
+	}
+	<-ctx.Done()
+	log.Println("Shutting down cluster")
+	wg.Wait()
+	return firstErr
+}
+
+// randomToken returns a random bearer token.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// responseCache keeps the last body and ETag the server sent for each URL the client
+// has fetched, so that a repeated GET can be answered from it when the server says
+// nothing changed.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// WithResponseCache makes the client remember the ETag and body of every successful
+// GET and send If-None-Match when repeating it. When the server answers 304 Not
+// Modified the cached body is returned as if it had been sent again, so a component
+// relisting pods every few seconds costs the server only a hash of the list unless
+// something changed; the server lists objects in a fixed order, so an unchanged list
+// always has the same ETag. Callers still get freshly decoded objects every time.
+func WithResponseCache() ClientOption {
+	return func(c *Client) { c.cache = &responseCache{entries: make(map[string]cachedResponse)} }
+}
+
+// get returns the cached response for key, if there is one.
+func (rc *responseCache) get(key string) (cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	return entry, ok
+}
+
+func (rc *responseCache) set(key string, entry cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[key] = entry
+}
+
+func (rc *responseCache) remove(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.entries, key)
+}
+
+// doCached sends the GET req through send, answering it from the cache when the
+// server says the cached response is still current.
+func (rc *responseCache) doCached(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	key := req.URL.String()
+	cached, ok := rc.get(key)
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	resp, err := send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Body = io.NopCloser(bytes.NewReader(cached.body))
+		resp.ContentLength = int64(len(cached.body))
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		rc.set(key, cachedResponse{etag: resp.Header.Get("ETag"), body: body})
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	default:
+		// The object may be gone; don't keep offering the server a stale ETag.
+		rc.remove(key)
+	}
+	return resp, nil
+}
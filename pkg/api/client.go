@@ -2,29 +2,191 @@ package api
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubeconfig"
 )
 
 // Client is a client for the k8s-lite-go API server.
 type Client struct {
-	baseURL    *url.URL
-	httpClient *http.Client
+	baseURL     *url.URL
+	httpClient  *http.Client
+	bearerToken string
+	dryRun      bool
+	propagation DeletionPropagation
+	cache       *responseCache // Set by WithResponseCache
+	chunkSize   int            // Set by WithChunkSize
+	warnings    func(string)   // Set by WithWarningHandler
+	middleware  []Middleware   // Set by WithMiddleware
+}
+
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// WithBearerToken makes the client send "Authorization: Bearer <token>" on every request.
+func WithBearerToken(token string) ClientOption {
+	return func(c *Client) { c.bearerToken = token }
+}
+
+// WithTransport makes the client send its requests through rt instead of
+// http.DefaultTransport, e.g. to tune connection pooling. To observe or change requests
+// on their way, use WithMiddleware.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) { c.httpClient.Transport = rt }
+}
+
+// WithChunkSize makes the client list objects n at a time, following the server's
+// continue tokens until it has the whole list, so no single response has to carry a
+// large result set. n <= 0 fetches each list in one request.
+func WithChunkSize(n int) ClientOption {
+	return func(c *Client) { c.chunkSize = n }
+}
+
+// WithWarningHandler makes the client call handle with each warning the server sends
+// in a Warning header, such as that a pod would violate its namespace's pod security
+// level. Without it, warnings are dropped.
+func WithWarningHandler(handle func(message string)) ClientOption {
+	return func(c *Client) { c.warnings = handle }
 }
 
 // NewClient creates a new API client.
-func NewClient(baseURLStr string) (*Client, error) {
+func NewClient(baseURLStr string, opts ...ClientOption) (*Client, error) {
 	baseURL, err := url.Parse(baseURLStr)
 	if err != nil {
 		return nil, fmt.Errorf("parsing base URL: %w", err)
 	}
-	return &Client{
+	c := &Client{
 		baseURL:    baseURL,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.middleware) > 0 {
+		c.httpClient.Transport = chain(c.httpClient.Transport, c.middleware)
+	}
+	return c, nil
+}
+
+// NewClientFromKubeconfig creates a client for the given context (or the current
+// context if empty) in the kubeconfig-lite file at path, with opts applied after the
+// context's credentials.
+func NewClientFromKubeconfig(path, contextName string, opts ...ClientOption) (*Client, error) {
+	cfg, err := kubeconfig.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := cfg.Resolve(contextName)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(resolved.Server, append([]ClientOption{WithBearerToken(resolved.Token)}, opts...)...)
+}
+
+// DryRun returns a copy of the client whose create, update, and delete requests are
+// sent with ?dryRun=true: the server validates them and returns the would-be result
+// without persisting anything.
+func (c *Client) DryRun() *Client {
+	dry := *c
+	dry.dryRun = true
+	return &dry
+}
+
+// WithPropagationPolicy returns a copy of the client whose delete requests carry
+// ?propagationPolicy=policy, deciding what happens to the deleted object's dependents.
+// The server honors it for deployments, services, and poddisruptionbudgets.
+func (c *Client) WithPropagationPolicy(policy DeletionPropagation) *Client {
+	out := *c
+	out.propagation = policy
+	return &out
+}
+
+// do sends req, adding the headers every request carries.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.dryRun && req.Method != http.MethodGet {
+		q := req.URL.Query()
+		q.Set("dryRun", "true")
+		req.URL.RawQuery = q.Encode()
+	}
+	if c.propagation != "" && req.Method == http.MethodDelete {
+		q := req.URL.Query()
+		q.Set("propagationPolicy", string(c.propagation))
+		req.URL.RawQuery = q.Encode()
+	}
+	if c.cache != nil && req.Method == http.MethodGet {
+		return c.cache.doCached(req, c.send)
+	}
+	return c.send(req)
+}
+
+// userAgent identifies the program using the client, such as "kubectl-lite/k8s-lite-go".
+var userAgent = filepath.Base(os.Args[0]) + "/k8s-lite-go"
+
+// send sends req with the client's User-Agent, decompressing the response if the
+// server gzipped it.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	// Ask for gzip ourselves, rather than rely on http.Transport doing it, so that
+	// compression works whatever transport WithTransport installs.
+	req.Header.Set("Accept-Encoding", "gzip")
+	if req.Header.Get("User-Agent") == "" {
+		// The API server names the field manager of a write after the product here.
+		req.Header.Set("User-Agent", userAgent)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err == nil && c.warnings != nil {
+		for _, warning := range resp.Header.Values("Warning") {
+			c.warnings(warningText(warning))
+		}
+	}
+	if err != nil || resp.Header.Get("Content-Encoding") != "gzip" {
+		return resp, err
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("decompressing response: %w", err)
+	}
+	resp.Body = &gzipBody{Reader: gz, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return resp, nil
+}
+
+// warningText returns the text of a Warning header value, `299 - "text"`, or the
+// whole value if it isn't in that form.
+func warningText(value string) string {
+	parts := strings.SplitN(value, " ", 3)
+	if len(parts) == 3 {
+		if text, err := strconv.Unquote(parts[2]); err == nil {
+			return text
+		}
+	}
+	return value
+}
+
+// gzipBody decompresses a response body, closing the original when done.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
 }
 
 func (c *Client) buildURL(pathSegments ...string) string {
@@ -58,15 +220,14 @@ func (c *Client) CreateNode(node *Node) (*Node, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		// TODO: Read body for more detailed error message from server
-		return nil, fmt.Errorf("server returned non-Created status for create node: %d", resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var createdNode Node
@@ -76,7 +237,8 @@ func (c *Client) CreateNode(node *Node) (*Node, error) {
 	return &createdNode, nil
 }
 
-// UpdateNode sends a PUT request to update a node.
+// UpdateNode sends a PUT request to update a node. On success node is refreshed
+// from the server's response.
 func (c *Client) UpdateNode(node *Node) error {
 	if node.Name == "" {
 		return fmt.Errorf("node name must be specified for update")
@@ -94,95 +256,44 @@ func (c *Client) UpdateNode(node *Node) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		// TODO: Read body for more detailed error message from server
-		return fmt.Errorf("server returned non-OK status for update node: %d", resp.StatusCode)
+		return statusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(node); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
 	return nil
 }
 
-// ListPods fetches pods, optionally filtering by phase.
-// For now, it gets all pods for the namespace and filters client-side if phase is specified.
-// A more efficient API would support server-side filtering by phase.
+// ListPods fetches pods, optionally filtering by phase. A namespace of NamespaceAll
+// lists pods in every namespace. It is ListPodsWithOptions with a status.phase field
+// selector.
 func (c *Client) ListPods(namespace string, phase PodPhase) ([]Pod, error) {
-	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "pods")
-	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+	var opts ListOptions
+	if phase != "" {
+		opts.FieldSelector = "status.phase=" + string(phase)
 	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned non-OK status: %d", resp.StatusCode)
-	}
-
-	var allPods []Pod
-	if err := json.NewDecoder(resp.Body).Decode(&allPods); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	if phase == "" { // No phase filter, return all
-		return allPods, nil
-	}
-
-	var filteredPods []Pod
-	for _, pod := range allPods {
-		if pod.Phase == phase {
-			filteredPods = append(filteredPods, pod)
-		}
-	}
-	return filteredPods, nil
+	return c.ListPodsWithOptions(namespace, opts)
 }
 
-// ListNodes fetches nodes, optionally filtering by status.
-// Similar to ListPods, filters client-side for simplicity.
+// ListNodes fetches nodes, optionally filtering by status. It is ListNodesWithOptions
+// with a status field selector.
 func (c *Client) ListNodes(status NodeStatus) ([]Node, error) {
-	urlStr := c.buildURL("api", "v1", "nodes")
-	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+	var opts ListOptions
+	if status != "" {
+		opts.FieldSelector = "status=" + string(status)
 	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned non-OK status: %d", resp.StatusCode)
-	}
-
-	var allNodes []Node
-	if err := json.NewDecoder(resp.Body).Decode(&allNodes); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	if status == "" { // No status filter, return all
-		return allNodes, nil
-	}
-
-	var filteredNodes []Node
-	for _, node := range allNodes {
-		if node.Status == status {
-			filteredNodes = append(filteredNodes, node)
-		}
-	}
-	return filteredNodes, nil
+	return c.ListNodesWithOptions(opts)
 }
 
-// UpdatePod sends a PUT request to update a pod.
+// UpdatePod sends a PUT request to update a pod. On success pod is refreshed
+// from the server's response.
 func (c *Client) UpdatePod(pod *Pod) error {
 	urlStr := c.buildURL("api", "v1", "namespaces", pod.Namespace, "pods", pod.Name)
 
@@ -197,17 +308,18 @@ func (c *Client) UpdatePod(pod *Pod) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		// TODO: Read body for more detailed error message from server
-		return fmt.Errorf("server returned non-OK status for update: %d", resp.StatusCode)
+		return statusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(pod); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
-	// Optionally decode the response body if the updated pod is returned
 	return nil
 }
 
@@ -219,17 +331,15 @@ func (c *Client) GetNode(name string) (*Node, error) {
 		return nil, fmt.Errorf("creating request for get node: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request for get node: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("node %s not found", name) // Specific error for not found
-	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned non-OK status for get node: %d", resp.StatusCode)
+		// Wrapped with %w, so that callers can tell a 404 with IsNotFound.
+		return nil, fmt.Errorf("getting node %s: %w", name, statusError(resp))
 	}
 
 	var node Node
@@ -257,15 +367,14 @@ func (c *Client) CreatePod(namespace string, pod *Pod) (*Pod, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		// TODO: Read body for more detailed error message from server
-		return nil, fmt.Errorf("server returned non-Created status for create pod: %d", resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var createdPod Pod
@@ -286,17 +395,15 @@ func (c *Client) GetPod(namespace, name string) (*Pod, error) {
 		return nil, fmt.Errorf("creating request for get pod: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request for get pod: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("pod %s/%s not found", namespace, name)
-	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned non-OK status for get pod: %d", resp.StatusCode)
+		// Wrapped with %w, so that callers can tell a 404 with IsNotFound.
+		return nil, fmt.Errorf("getting pod %s/%s: %w", namespace, name, statusError(resp))
 	}
 
 	var pod Pod
@@ -318,15 +425,14 @@ func (c *Client) DeletePod(namespace, name string) error {
 		return fmt.Errorf("creating request for delete pod: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("executing request for delete pod: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent { // Some APIs return 204 for delete
-		// TODO: Read body for more detailed error message from server
-		return fmt.Errorf("server returned non-OK status for delete pod: %d", resp.StatusCode)
+		return fmt.Errorf("deleting pod %s/%s: %w", namespace, name, statusError(resp))
 	}
 	return nil
 }
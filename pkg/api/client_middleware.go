@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// Middleware wraps the http.RoundTripper a client sends its requests through with
+// another, which may look at or change each request before passing it on to next,
+// and look at or replace the response. Middleware is how callers layer logging,
+// metrics, credentials, or retries onto a client without forking it:
+//
+//	client, err := api.NewClient(url, api.WithMiddleware(
+//		api.ObserveResponses(func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+//			log.Printf("%s %s took %v", req.Method, req.URL.Path, elapsed)
+//		}),
+//	))
+//
+// As with any http.RoundTripper, a middleware must not modify the request it is given;
+// it clones it first, as InterceptRequests does.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an http.RoundTripper that is a function, for writing middleware.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware makes the client send its requests through mw, in order: the first
+// middleware sees each request first and its response last. They wrap the transport
+// WithTransport sets, or http.DefaultTransport, whatever the order of the options, and
+// apply to every request the client sends, including watches and streams. They see
+// responses as the server sent them: still gzipped, and a list the response cache
+// revalidates as 304 Not Modified.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) { c.middleware = append(c.middleware, mw...) }
+}
+
+// InterceptRequests returns middleware that calls intercept with a copy of each
+// request before sending it, so it may set headers such as a freshly minted token.
+func InterceptRequests(intercept func(req *http.Request)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			intercept(req)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// ObserveResponses returns middleware that calls observe with each request, its
+// response or error, and how long it took, once the response headers arrive. It is
+// for logging and metrics; observe must not read or close the response's body.
+func ObserveResponses(observe func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			observe(req, resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// chain wraps rt, or http.DefaultTransport if rt is nil, in mw, the first outermost.
+func chain(rt http.RoundTripper, mw []Middleware) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		rt = mw[i](rt)
+	}
+	return rt
+}
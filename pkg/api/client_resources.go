@@ -0,0 +1,1035 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// doJSON sends in (if non-nil) as the JSON body of a request and decodes the response
+// into out (if non-nil). Any status other than one of okStatuses is returned as an error
+// carrying the server's error message.
+func (c *Client) doJSON(method, urlStr string, in, out interface{}, okStatuses ...int) error {
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshalling request body: %w", err)
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, urlStr, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	for _, code := range okStatuses {
+		if resp.StatusCode == code {
+			if out == nil {
+				return nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("decoding response: %w", err)
+			}
+			return nil
+		}
+	}
+	return statusError(resp)
+}
+
+// ContinueHeader carries the token for the next page of a list the server cut short
+// because the request set ?limit=. The next page is requested with ?continue=<token>.
+const ContinueHeader = "X-Continue"
+
+// listJSON decodes the whole JSON list at urlStr into out, as list does without
+// options.
+func (c *Client) listJSON(urlStr string, out interface{}) error {
+	_, err := c.list(urlStr, ListOptions{}, out)
+	return err
+}
+
+// Err returns the result's failure as a *StatusError, or nil if the pod was created.
+func (r PodBatchResult) Err() error {
+	if r.Code == http.StatusCreated {
+		return nil
+	}
+	return &StatusError{Code: r.Code, Message: r.Error}
+}
+
+// RequestIDHeader carries the ID the API server gives each request. It is echoed in
+// every response and in the server's log lines for the request.
+const RequestIDHeader = "X-Request-ID"
+
+// StatusError is returned for a response with an unexpected status code. Message is
+// the server's {"error": "..."} message, or the raw body if there wasn't one.
+// RequestID finds the server's log lines for the request.
+type StatusError struct {
+	Code      int
+	Message   string
+	RequestID string
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("server returned status %d", e.Code)
+	if e.Message != "" {
+		msg = fmt.Sprintf("server returned %d: %s", e.Code, e.Message)
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// IsTooManyRequests reports whether err is a 429 response, which the eviction endpoint
+// uses to say a disruption budget currently forbids the request.
+func IsTooManyRequests(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusTooManyRequests
+}
+
+// IsConflict reports whether err is a 409 response, which an update of a lease gets
+// when the lease changed since it was read.
+func IsConflict(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusConflict
+}
+
+// IsNotFound reports whether err is a 404 response, for an object that doesn't exist.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+// IsAlreadyExists reports whether err is the 409 response to creating an object that
+// already exists. A 409 is also the answer to a write that conflicts with another, so
+// it is meant for the errors of creates.
+func IsAlreadyExists(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusConflict
+}
+
+// statusError builds an error from a non-success response, preferring the server's
+// {"error": "..."} message over the bare status code.
+func statusError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	requestID := resp.Header.Get(RequestIDHeader)
+	if json.Unmarshal(raw, &body) == nil && body.Error != "" {
+		return &StatusError{Code: resp.StatusCode, Message: body.Error, RequestID: requestID}
+	}
+	return &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(raw)), RequestID: requestID}
+}
+
+func defaultedNamespace(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+// DeleteNode sends a DELETE request to remove a node.
+func (c *Client) DeleteNode(name string) error {
+	if err := c.doJSON(http.MethodDelete, c.buildURL("api", "v1", "nodes", name), nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting node %s: %w", name, err)
+	}
+	return nil
+}
+
+// ApproveNode approves a node that registered while the server requires node
+// approval, making it Ready and schedulable.
+func (c *Client) ApproveNode(name string) (*Node, error) {
+	var node Node
+	if err := c.doJSON(http.MethodPost, c.buildURL("api", "v1", "nodes", name, "approval"), nil, &node, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("approving node %s: %w", name, err)
+	}
+	return &node, nil
+}
+
+// ListPodsMatching lists the pods in namespace, or in every namespace for
+// NamespaceAll, that match labelSelector and fieldSelector (either may be empty). The
+// server does the filtering.
+func (c *Client) ListPodsMatching(namespace, labelSelector, fieldSelector string) ([]Pod, error) {
+	return c.ListPodsWithOptions(namespace, ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+}
+
+// ListPodsWithOptions lists the pods in namespace, or in every namespace for
+// NamespaceAll, that opts selects. The server does the filtering.
+func (c *Client) ListPodsWithOptions(namespace string, opts ListOptions) ([]Pod, error) {
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "pods")
+	if namespace == NamespaceAll {
+		urlStr = c.buildURL("api", "v1", "pods")
+	}
+	var pods []Pod
+	if _, err := c.list(urlStr, opts, &pods); err != nil {
+		return nil, fmt.Errorf("listing pods in %s: %w", namespace, err)
+	}
+	return pods, nil
+}
+
+// ListNodesMatching lists the nodes that match labelSelector and fieldSelector (either
+// may be empty). The server does the filtering.
+func (c *Client) ListNodesMatching(labelSelector, fieldSelector string) ([]Node, error) {
+	return c.ListNodesWithOptions(ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+}
+
+// ListNodesWithOptions lists the nodes that opts selects. The server does the
+// filtering.
+func (c *Client) ListNodesWithOptions(opts ListOptions) ([]Node, error) {
+	var nodes []Node
+	if _, err := c.list(c.buildURL("api", "v1", "nodes"), opts, &nodes); err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	return nodes, nil
+}
+
+// DeleteCollection deletes every pod in namespace matching labelSelector and
+// fieldSelector (either may be empty) in a single request, and returns the pods that
+// were marked for deletion.
+func (c *Client) DeleteCollection(namespace, labelSelector, fieldSelector string) ([]Pod, error) {
+	return c.DeleteCollectionWithOptions(namespace, ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+}
+
+// DeleteCollectionWithOptions deletes every pod in namespace that opts selects in a
+// single request, and returns the pods that were marked for deletion. Paging options
+// are ignored: the whole selection is deleted.
+func (c *Client) DeleteCollectionWithOptions(namespace string, opts ListOptions) ([]Pod, error) {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "pods") + ListOptions{LabelSelector: opts.LabelSelector, FieldSelector: opts.FieldSelector}.query()
+	var deleted []Pod
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, &deleted, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("deleting pods in %s: %w", namespace, err)
+	}
+	return deleted, nil
+}
+
+// CreatePods creates pods in namespace in a single request. The pods are created one
+// by one on the server, so some may fail while others succeed; the returned results
+// are in the order of pods, and the error is only for the request as a whole.
+func (c *Client) CreatePods(namespace string, pods []Pod) ([]PodBatchResult, error) {
+	namespace = defaultedNamespace(namespace)
+	var results []PodBatchResult
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "pods:batch")
+	if err := c.doJSON(http.MethodPost, urlStr, pods, &results, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("creating %d pods in %s: %w", len(pods), namespace, err)
+	}
+	return results, nil
+}
+
+// PatchType is the Content-Type of a PATCH, which says how the server applies it.
+type PatchType string
+
+const (
+	// ApplyPatchContentType is the Content-Type of a server-side apply.
+	ApplyPatchContentType PatchType = "application/apply-patch+yaml"
+	// MergePatchType is a JSON merge patch (RFC 7386): a partial object whose fields
+	// replace the live object's, where null removes a field.
+	MergePatchType PatchType = "application/merge-patch+json"
+	// JSONPatchType is a JSON patch (RFC 6902): a list of operations such as
+	// {"op": "replace", "path": "/image", "value": "nginx:1.26"}.
+	JSONPatchType PatchType = "application/json-patch+json"
+)
+
+// ApplyOptions are the options of a server-side apply.
+type ApplyOptions struct {
+	// FieldManager names who is applying; it owns the fields in the configuration.
+	FieldManager string
+	// Force takes over fields other managers own instead of failing with a conflict.
+	Force bool
+}
+
+// Apply applies config, the YAML or JSON configuration of a Pod, Node, Namespace,
+// Deployment, or Service, server-side: the object is created, or its fields owned by
+// opts.FieldManager are updated to match config. namespace is ignored for cluster-scoped
+// kinds. The resulting object is decoded into out (if non-nil), and created says
+// whether the object is new. Fields another manager owns with a different value are
+// a 409 conflict unless opts.Force is set.
+func (c *Client) Apply(kind, namespace, name string, config []byte, opts ApplyOptions, out interface{}) (created bool, err error) {
+	urlStr, ok := c.patchURL(kind, namespace, name)
+	if !ok {
+		return false, fmt.Errorf("apply is not supported for kind %q", kind)
+	}
+	q := url.Values{"fieldManager": {opts.FieldManager}}
+	if opts.Force {
+		q.Set("force", "true")
+	}
+	urlStr += "?" + q.Encode()
+
+	req, err := http.NewRequest(http.MethodPatch, urlStr, bytes.NewReader(config))
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", string(ApplyPatchContentType))
+	resp, err := c.do(req)
+	if err != nil {
+		return false, fmt.Errorf("applying %s %s: %w", strings.ToLower(kind), name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return false, fmt.Errorf("applying %s %s: %w", strings.ToLower(kind), name, statusError(resp))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return false, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return resp.StatusCode == http.StatusCreated, nil
+}
+
+// Patch patches the Pod, Node, Namespace, Deployment, or Service named name with data,
+// a patch of type patchType, and decodes the result into out (if non-nil). namespace
+// is ignored for cluster-scoped kinds. A patch that changes nothing leaves the object,
+// and its resourceVersion, as they were.
+func (c *Client) Patch(kind, namespace, name string, patchType PatchType, data []byte, out interface{}) error {
+	urlStr, ok := c.patchURL(kind, namespace, name)
+	if !ok {
+		return fmt.Errorf("patch is not supported for kind %q", kind)
+	}
+	req, err := http.NewRequest(http.MethodPatch, urlStr, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", string(patchType))
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("patching %s %s: %w", strings.ToLower(kind), name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("patching %s %s: %w", strings.ToLower(kind), name, statusError(resp))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return nil
+}
+
+// patchURL returns the URL of an object of a kind that supports PATCH.
+func (c *Client) patchURL(kind, namespace, name string) (string, bool) {
+	namespace = defaultedNamespace(namespace)
+	switch kind {
+	case "Pod":
+		return c.buildURL("api", "v1", "namespaces", namespace, "pods", name), true
+	case "Node":
+		return c.buildURL("api", "v1", "nodes", name), true
+	case "Namespace":
+		return c.buildURL("api", "v1", "namespaces", name), true
+	case "Deployment":
+		return c.buildURL("apis", "apps", "v1", "namespaces", namespace, "deployments", name), true
+	case "Service":
+		return c.buildURL("api", "v1", "namespaces", namespace, "services", name), true
+	}
+	return "", false
+}
+
+// ServerGroupVersions returns the group versions the API server serves, such as "v1"
+// and "apps/v1", with the core group's first.
+func (c *Client) ServerGroupVersions() ([]string, error) {
+	var versions APIVersions
+	if err := c.doJSON(http.MethodGet, c.buildURL("api"), nil, &versions, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("discovering API versions: %w", err)
+	}
+	var groups APIGroupList
+	if err := c.doJSON(http.MethodGet, c.buildURL("apis"), nil, &groups, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("discovering API groups: %w", err)
+	}
+	groupVersions := versions.Versions
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			groupVersions = append(groupVersions, version.GroupVersion)
+		}
+	}
+	return groupVersions, nil
+}
+
+// ServerResources returns the resources of every group version the API server serves.
+func (c *Client) ServerResources() ([]APIResourceList, error) {
+	groupVersions, err := c.ServerGroupVersions()
+	if err != nil {
+		return nil, err
+	}
+	lists := make([]APIResourceList, 0, len(groupVersions))
+	for _, gv := range groupVersions {
+		urlStr := c.buildURL("api", gv)
+		if group, version, named := strings.Cut(gv, "/"); named {
+			urlStr = c.buildURL("apis", group, version)
+		}
+		var list APIResourceList
+		if err := c.doJSON(http.MethodGet, urlStr, nil, &list, http.StatusOK); err != nil {
+			return nil, fmt.Errorf("discovering the resources of %s: %w", gv, err)
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+// Metrics fetches the API server's request metrics in the Prometheus text format.
+func (c *Client) Metrics() (string, error) {
+	req, err := http.NewRequest(http.MethodGet, c.buildURL("metrics"), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching metrics: %w", statusError(resp))
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading metrics: %w", err)
+	}
+	return string(raw), nil
+}
+
+// GetPodLogs fetches the output of a pod from the kubelet running it, through the API
+// server.
+func (c *Client) GetPodLogs(namespace, name string) (string, error) {
+	return c.GetPodLogsWithOptions(namespace, name, PodLogOptions{})
+}
+
+// GetPodLogsWithOptions fetches the part of a pod's output that opts selects from the
+// kubelet running it, through the API server.
+func (c *Client) GetPodLogsWithOptions(namespace, name string, opts PodLogOptions) (string, error) {
+	namespace = defaultedNamespace(namespace)
+	query := url.Values{}
+	if opts.TailLines > 0 {
+		query.Set("tailLines", strconv.Itoa(opts.TailLines))
+	}
+	if opts.SinceSeconds > 0 {
+		query.Set("sinceSeconds", strconv.FormatInt(opts.SinceSeconds, 10))
+	}
+	if opts.Previous {
+		query.Set("previous", "true")
+	}
+	if opts.Container != "" {
+		query.Set("container", opts.Container)
+	}
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "pods", name, "log")
+	if len(query) > 0 {
+		urlStr += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching logs of pod %s/%s: %w", namespace, name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching logs of pod %s/%s: %w", namespace, name, statusError(resp))
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading logs of pod %s/%s: %w", namespace, name, err)
+	}
+	return string(raw), nil
+}
+
+// ExecPod runs command in a pod, on the kubelet running it, through the API server. A
+// command that runs but fails is not an error; its exit code is in the result.
+func (c *Client) ExecPod(namespace, name string, command []string) (*ExecResult, error) {
+	namespace = defaultedNamespace(namespace)
+	u, err := url.Parse(c.buildURL("api", "v1", "namespaces", namespace, "pods", name, "exec"))
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"command": command}.Encode()
+	var result ExecResult
+	if err := c.doJSON(http.MethodPost, u.String(), nil, &result, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("executing in pod %s/%s: %w", namespace, name, err)
+	}
+	return &result, nil
+}
+
+// CreateLease sends a POST request to create a lease in a namespace.
+func (c *Client) CreateLease(namespace string, lease *Lease) (*Lease, error) {
+	namespace = defaultedNamespace(namespace)
+	var created Lease
+	urlStr := c.buildURL("apis", "coordination", "v1", "namespaces", namespace, "leases")
+	if err := c.doJSON(http.MethodPost, urlStr, lease, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating lease %s/%s: %w", namespace, lease.Name, err)
+	}
+	return &created, nil
+}
+
+// GetLease fetches a lease by name from a namespace.
+func (c *Client) GetLease(namespace, name string) (*Lease, error) {
+	namespace = defaultedNamespace(namespace)
+	var lease Lease
+	urlStr := c.buildURL("apis", "coordination", "v1", "namespaces", namespace, "leases", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &lease, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting lease %s/%s: %w", namespace, name, err)
+	}
+	return &lease, nil
+}
+
+// ListLeases fetches the leases in a namespace.
+func (c *Client) ListLeases(namespace string) ([]Lease, error) {
+	namespace = defaultedNamespace(namespace)
+	var leases []Lease
+	urlStr := c.buildURL("apis", "coordination", "v1", "namespaces", namespace, "leases")
+	if err := c.listJSON(urlStr, &leases); err != nil {
+		return nil, fmt.Errorf("listing leases in %s: %w", namespace, err)
+	}
+	return leases, nil
+}
+
+// UpdateLease sends a PUT request to update a lease and refreshes lease with the
+// stored copy. The update fails with a conflict (see IsConflict) unless lease carries
+// the current resourceVersion.
+func (c *Client) UpdateLease(lease *Lease) error {
+	urlStr := c.buildURL("apis", "coordination", "v1", "namespaces", lease.Namespace, "leases", lease.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, lease, lease, http.StatusOK); err != nil {
+		return fmt.Errorf("updating lease %s/%s: %w", lease.Namespace, lease.Name, err)
+	}
+	return nil
+}
+
+// DeleteLease sends a DELETE request to remove a lease.
+func (c *Client) DeleteLease(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("apis", "coordination", "v1", "namespaces", namespace, "leases", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting lease %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// ReportComponentStatus sends a PUT request recording a heartbeat from a component.
+func (c *Client) ReportComponentStatus(status *ComponentStatus) error {
+	if err := c.doJSON(http.MethodPut, c.buildURL("api", "v1", "componentstatuses", status.Name), status, nil, http.StatusOK); err != nil {
+		return fmt.Errorf("reporting component status %s: %w", status.Name, err)
+	}
+	return nil
+}
+
+// ListComponentStatuses fetches the health of every component that has reported.
+func (c *Client) ListComponentStatuses() ([]ComponentStatus, error) {
+	var statuses []ComponentStatus
+	if err := c.listJSON(c.buildURL("api", "v1", "componentstatuses"), &statuses); err != nil {
+		return nil, fmt.Errorf("listing component statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+// CreateNamespace sends a POST request to create a namespace.
+func (c *Client) CreateNamespace(ns *Namespace) (*Namespace, error) {
+	var created Namespace
+	if err := c.doJSON(http.MethodPost, c.buildURL("api", "v1", "namespaces"), ns, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating namespace %s: %w", ns.Name, err)
+	}
+	return &created, nil
+}
+
+// GetNamespace fetches a namespace by name.
+func (c *Client) GetNamespace(name string) (*Namespace, error) {
+	var ns Namespace
+	if err := c.doJSON(http.MethodGet, c.buildURL("api", "v1", "namespaces", name), nil, &ns, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting namespace %s: %w", name, err)
+	}
+	return &ns, nil
+}
+
+// ListNamespaces fetches all namespaces.
+func (c *Client) ListNamespaces() ([]Namespace, error) {
+	var namespaces []Namespace
+	if err := c.listJSON(c.buildURL("api", "v1", "namespaces"), &namespaces); err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+	return namespaces, nil
+}
+
+// UpdateNamespace sends a PUT request to replace a namespace. On success ns is
+// refreshed from the server's response.
+func (c *Client) UpdateNamespace(ns *Namespace) error {
+	if err := c.doJSON(http.MethodPut, c.buildURL("api", "v1", "namespaces", ns.Name), ns, ns, http.StatusOK); err != nil {
+		return fmt.Errorf("updating namespace %s: %w", ns.Name, err)
+	}
+	return nil
+}
+
+// DeleteNamespace sends a DELETE request to remove a namespace.
+func (c *Client) DeleteNamespace(name string) error {
+	if err := c.doJSON(http.MethodDelete, c.buildURL("api", "v1", "namespaces", name), nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// CreateDeployment sends a POST request to create a deployment in a namespace.
+func (c *Client) CreateDeployment(namespace string, d *Deployment) (*Deployment, error) {
+	namespace = defaultedNamespace(namespace)
+	var created Deployment
+	urlStr := c.buildURL("apis", "apps", "v1", "namespaces", namespace, "deployments")
+	if err := c.doJSON(http.MethodPost, urlStr, d, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating deployment %s/%s: %w", namespace, d.Name, err)
+	}
+	return &created, nil
+}
+
+// GetDeployment fetches a deployment by name from a namespace.
+func (c *Client) GetDeployment(namespace, name string) (*Deployment, error) {
+	namespace = defaultedNamespace(namespace)
+	var d Deployment
+	urlStr := c.buildURL("apis", "apps", "v1", "namespaces", namespace, "deployments", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &d, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting deployment %s/%s: %w", namespace, name, err)
+	}
+	return &d, nil
+}
+
+// ListDeployments fetches the deployments in a namespace. A namespace of NamespaceAll
+// lists deployments in every namespace.
+func (c *Client) ListDeployments(namespace string) ([]Deployment, error) {
+	var deployments []Deployment
+	urlStr := c.buildURL("apis", "apps", "v1", "namespaces", namespace, "deployments")
+	if namespace == NamespaceAll {
+		urlStr = c.buildURL("apis", "apps", "v1", "deployments")
+	}
+	if err := c.listJSON(urlStr, &deployments); err != nil {
+		return nil, fmt.Errorf("listing deployments in %s: %w", namespace, err)
+	}
+	return deployments, nil
+}
+
+// UpdateDeployment sends a PUT request to replace a deployment. On success d is
+// refreshed from the server's response.
+func (c *Client) UpdateDeployment(d *Deployment) error {
+	urlStr := c.buildURL("apis", "apps", "v1", "namespaces", d.Namespace, "deployments", d.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, d, d, http.StatusOK); err != nil {
+		return fmt.Errorf("updating deployment %s/%s: %w", d.Namespace, d.Name, err)
+	}
+	return nil
+}
+
+// DeleteDeployment sends a DELETE request to remove a deployment.
+func (c *Client) DeleteDeployment(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("apis", "apps", "v1", "namespaces", namespace, "deployments", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting deployment %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CreateService sends a POST request to create a service in a namespace.
+func (c *Client) CreateService(namespace string, svc *Service) (*Service, error) {
+	namespace = defaultedNamespace(namespace)
+	var created Service
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "services")
+	if err := c.doJSON(http.MethodPost, urlStr, svc, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating service %s/%s: %w", namespace, svc.Name, err)
+	}
+	return &created, nil
+}
+
+// GetService fetches a service by name from a namespace.
+func (c *Client) GetService(namespace, name string) (*Service, error) {
+	namespace = defaultedNamespace(namespace)
+	var svc Service
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "services", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &svc, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting service %s/%s: %w", namespace, name, err)
+	}
+	return &svc, nil
+}
+
+// ListServices fetches the services in a namespace.
+func (c *Client) ListServices(namespace string) ([]Service, error) {
+	namespace = defaultedNamespace(namespace)
+	var services []Service
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "services")
+	if err := c.listJSON(urlStr, &services); err != nil {
+		return nil, fmt.Errorf("listing services in %s: %w", namespace, err)
+	}
+	return services, nil
+}
+
+// UpdateService sends a PUT request to replace a service. On success svc is
+// refreshed from the server's response.
+func (c *Client) UpdateService(svc *Service) error {
+	urlStr := c.buildURL("api", "v1", "namespaces", svc.Namespace, "services", svc.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, svc, svc, http.StatusOK); err != nil {
+		return fmt.Errorf("updating service %s/%s: %w", svc.Namespace, svc.Name, err)
+	}
+	return nil
+}
+
+// DeleteService sends a DELETE request to remove a service.
+func (c *Client) DeleteService(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "services", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting service %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CreateEvent sends a POST request to record an event. The server folds repeats of an
+// existing event into it, in which case the returned event has Count > 1.
+func (c *Client) CreateEvent(namespace string, event *Event) (*Event, error) {
+	namespace = defaultedNamespace(namespace)
+	var created Event
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "events")
+	if err := c.doJSON(http.MethodPost, urlStr, event, &created, http.StatusCreated, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("creating event %s: %w", event.Reason, err)
+	}
+	return &created, nil
+}
+
+// ListEvents fetches the events in a namespace.
+func (c *Client) ListEvents(namespace string) ([]Event, error) {
+	namespace = defaultedNamespace(namespace)
+	var events []Event
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "events")
+	if err := c.listJSON(urlStr, &events); err != nil {
+		return nil, fmt.Errorf("listing events in %s: %w", namespace, err)
+	}
+	return events, nil
+}
+
+// CreatePodDisruptionBudget sends a POST request to create a pod disruption budget in
+// a namespace.
+func (c *Client) CreatePodDisruptionBudget(namespace string, pdb *PodDisruptionBudget) (*PodDisruptionBudget, error) {
+	namespace = defaultedNamespace(namespace)
+	var created PodDisruptionBudget
+	urlStr := c.buildURL("apis", "policy", "v1", "namespaces", namespace, "poddisruptionbudgets")
+	if err := c.doJSON(http.MethodPost, urlStr, pdb, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating poddisruptionbudget %s/%s: %w", namespace, pdb.Name, err)
+	}
+	return &created, nil
+}
+
+// GetPodDisruptionBudget fetches a pod disruption budget, with its current status, by
+// name from a namespace.
+func (c *Client) GetPodDisruptionBudget(namespace, name string) (*PodDisruptionBudget, error) {
+	namespace = defaultedNamespace(namespace)
+	var pdb PodDisruptionBudget
+	urlStr := c.buildURL("apis", "policy", "v1", "namespaces", namespace, "poddisruptionbudgets", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &pdb, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting poddisruptionbudget %s/%s: %w", namespace, name, err)
+	}
+	return &pdb, nil
+}
+
+// ListPodDisruptionBudgets fetches the pod disruption budgets in a namespace.
+func (c *Client) ListPodDisruptionBudgets(namespace string) ([]PodDisruptionBudget, error) {
+	namespace = defaultedNamespace(namespace)
+	var pdbs []PodDisruptionBudget
+	urlStr := c.buildURL("apis", "policy", "v1", "namespaces", namespace, "poddisruptionbudgets")
+	if err := c.listJSON(urlStr, &pdbs); err != nil {
+		return nil, fmt.Errorf("listing poddisruptionbudgets in %s: %w", namespace, err)
+	}
+	return pdbs, nil
+}
+
+// DeletePodDisruptionBudget sends a DELETE request to remove a pod disruption budget.
+func (c *Client) DeletePodDisruptionBudget(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("apis", "policy", "v1", "namespaces", namespace, "poddisruptionbudgets", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting poddisruptionbudget %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CreateNetworkPolicy sends a POST request to create a network policy in a namespace.
+func (c *Client) CreateNetworkPolicy(namespace string, policy *NetworkPolicy) (*NetworkPolicy, error) {
+	namespace = defaultedNamespace(namespace)
+	var created NetworkPolicy
+	urlStr := c.buildURL("apis", "networking", "v1", "namespaces", namespace, "networkpolicies")
+	if err := c.doJSON(http.MethodPost, urlStr, policy, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating networkpolicy %s/%s: %w", namespace, policy.Name, err)
+	}
+	return &created, nil
+}
+
+// GetNetworkPolicy fetches a network policy by name from a namespace.
+func (c *Client) GetNetworkPolicy(namespace, name string) (*NetworkPolicy, error) {
+	namespace = defaultedNamespace(namespace)
+	var policy NetworkPolicy
+	urlStr := c.buildURL("apis", "networking", "v1", "namespaces", namespace, "networkpolicies", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &policy, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting networkpolicy %s/%s: %w", namespace, name, err)
+	}
+	return &policy, nil
+}
+
+// ListNetworkPolicies fetches the network policies in a namespace.
+func (c *Client) ListNetworkPolicies(namespace string) ([]NetworkPolicy, error) {
+	namespace = defaultedNamespace(namespace)
+	var policies []NetworkPolicy
+	urlStr := c.buildURL("apis", "networking", "v1", "namespaces", namespace, "networkpolicies")
+	if err := c.listJSON(urlStr, &policies); err != nil {
+		return nil, fmt.Errorf("listing networkpolicies in %s: %w", namespace, err)
+	}
+	return policies, nil
+}
+
+// UpdateNetworkPolicy sends a PUT request to replace a network policy.
+func (c *Client) UpdateNetworkPolicy(policy *NetworkPolicy) error {
+	namespace := defaultedNamespace(policy.Namespace)
+	urlStr := c.buildURL("apis", "networking", "v1", "namespaces", namespace, "networkpolicies", policy.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, policy, policy, http.StatusOK); err != nil {
+		return fmt.Errorf("updating networkpolicy %s/%s: %w", namespace, policy.Name, err)
+	}
+	return nil
+}
+
+// DeleteNetworkPolicy sends a DELETE request to remove a network policy.
+func (c *Client) DeleteNetworkPolicy(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("apis", "networking", "v1", "namespaces", namespace, "networkpolicies", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting networkpolicy %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CreateConfigMap sends a POST request to create a config map in a namespace.
+func (c *Client) CreateConfigMap(namespace string, configMap *ConfigMap) (*ConfigMap, error) {
+	namespace = defaultedNamespace(namespace)
+	var created ConfigMap
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "configmaps")
+	if err := c.doJSON(http.MethodPost, urlStr, configMap, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating configmap %s/%s: %w", namespace, configMap.Name, err)
+	}
+	return &created, nil
+}
+
+// GetConfigMap fetches a config map by name from a namespace.
+func (c *Client) GetConfigMap(namespace, name string) (*ConfigMap, error) {
+	namespace = defaultedNamespace(namespace)
+	var configMap ConfigMap
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "configmaps", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &configMap, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting configmap %s/%s: %w", namespace, name, err)
+	}
+	return &configMap, nil
+}
+
+// ListConfigMaps fetches the config maps in a namespace.
+func (c *Client) ListConfigMaps(namespace string) ([]ConfigMap, error) {
+	namespace = defaultedNamespace(namespace)
+	var configMaps []ConfigMap
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "configmaps")
+	if err := c.listJSON(urlStr, &configMaps); err != nil {
+		return nil, fmt.Errorf("listing configmaps in %s: %w", namespace, err)
+	}
+	return configMaps, nil
+}
+
+// UpdateConfigMap sends a PUT request to replace a config map.
+func (c *Client) UpdateConfigMap(configMap *ConfigMap) error {
+	namespace := defaultedNamespace(configMap.Namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "configmaps", configMap.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, configMap, configMap, http.StatusOK); err != nil {
+		return fmt.Errorf("updating configmap %s/%s: %w", namespace, configMap.Name, err)
+	}
+	return nil
+}
+
+// DeleteConfigMap sends a DELETE request to remove a config map.
+func (c *Client) DeleteConfigMap(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "configmaps", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting configmap %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CreateSecret sends a POST request to create a secret in a namespace.
+func (c *Client) CreateSecret(namespace string, secret *Secret) (*Secret, error) {
+	namespace = defaultedNamespace(namespace)
+	var created Secret
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "secrets")
+	if err := c.doJSON(http.MethodPost, urlStr, secret, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating secret %s/%s: %w", namespace, secret.Name, err)
+	}
+	return &created, nil
+}
+
+// GetSecret fetches a secret by name from a namespace.
+func (c *Client) GetSecret(namespace, name string) (*Secret, error) {
+	namespace = defaultedNamespace(namespace)
+	var secret Secret
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "secrets", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &secret, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting secret %s/%s: %w", namespace, name, err)
+	}
+	return &secret, nil
+}
+
+// ListSecrets fetches the secrets in a namespace.
+func (c *Client) ListSecrets(namespace string) ([]Secret, error) {
+	namespace = defaultedNamespace(namespace)
+	var secrets []Secret
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "secrets")
+	if err := c.listJSON(urlStr, &secrets); err != nil {
+		return nil, fmt.Errorf("listing secrets in %s: %w", namespace, err)
+	}
+	return secrets, nil
+}
+
+// UpdateSecret sends a PUT request to replace a secret.
+func (c *Client) UpdateSecret(secret *Secret) error {
+	namespace := defaultedNamespace(secret.Namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "secrets", secret.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, secret, secret, http.StatusOK); err != nil {
+		return fmt.Errorf("updating secret %s/%s: %w", namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// DeleteSecret sends a DELETE request to remove a secret.
+func (c *Client) DeleteSecret(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "secrets", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting secret %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// EvictPod asks the server to delete a pod subject to its disruption budgets. When a
+// budget forbids the eviction the error satisfies IsTooManyRequests and the caller
+// should retry later.
+func (c *Client) EvictPod(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "pods", name, "eviction")
+	if err := c.doJSON(http.MethodPost, urlStr, map[string]string{"name": name, "namespace": namespace}, nil, http.StatusOK, http.StatusCreated); err != nil {
+		return fmt.Errorf("evicting pod %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// CreatePersistentVolume sends a POST request to create a persistent volume.
+func (c *Client) CreatePersistentVolume(pv *PersistentVolume) (*PersistentVolume, error) {
+	var created PersistentVolume
+	urlStr := c.buildURL("api", "v1", "persistentvolumes")
+	if err := c.doJSON(http.MethodPost, urlStr, pv, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating persistentvolume %s: %w", pv.Name, err)
+	}
+	return &created, nil
+}
+
+// GetPersistentVolume fetches a persistent volume by name.
+func (c *Client) GetPersistentVolume(name string) (*PersistentVolume, error) {
+	var pv PersistentVolume
+	urlStr := c.buildURL("api", "v1", "persistentvolumes", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &pv, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting persistentvolume %s: %w", name, err)
+	}
+	return &pv, nil
+}
+
+// ListPersistentVolumes fetches all persistent volumes.
+func (c *Client) ListPersistentVolumes() ([]PersistentVolume, error) {
+	var pvs []PersistentVolume
+	urlStr := c.buildURL("api", "v1", "persistentvolumes")
+	if err := c.listJSON(urlStr, &pvs); err != nil {
+		return nil, fmt.Errorf("listing persistentvolumes: %w", err)
+	}
+	return pvs, nil
+}
+
+// UpdatePersistentVolume sends a PUT request to replace a persistent volume. On success
+// pv is refreshed from the server's response.
+func (c *Client) UpdatePersistentVolume(pv *PersistentVolume) error {
+	urlStr := c.buildURL("api", "v1", "persistentvolumes", pv.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, pv, pv, http.StatusOK); err != nil {
+		return fmt.Errorf("updating persistentvolume %s: %w", pv.Name, err)
+	}
+	return nil
+}
+
+// DeletePersistentVolume sends a DELETE request to remove a persistent volume.
+func (c *Client) DeletePersistentVolume(name string) error {
+	urlStr := c.buildURL("api", "v1", "persistentvolumes", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting persistentvolume %s: %w", name, err)
+	}
+	return nil
+}
+
+// CreatePersistentVolumeClaim sends a POST request to create a persistent volume claim
+// in a namespace.
+func (c *Client) CreatePersistentVolumeClaim(namespace string, pvc *PersistentVolumeClaim) (*PersistentVolumeClaim, error) {
+	namespace = defaultedNamespace(namespace)
+	var created PersistentVolumeClaim
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "persistentvolumeclaims")
+	if err := c.doJSON(http.MethodPost, urlStr, pvc, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("creating persistentvolumeclaim %s/%s: %w", namespace, pvc.Name, err)
+	}
+	return &created, nil
+}
+
+// GetPersistentVolumeClaim fetches a persistent volume claim by name from a namespace.
+func (c *Client) GetPersistentVolumeClaim(namespace, name string) (*PersistentVolumeClaim, error) {
+	namespace = defaultedNamespace(namespace)
+	var pvc PersistentVolumeClaim
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "persistentvolumeclaims", name)
+	if err := c.doJSON(http.MethodGet, urlStr, nil, &pvc, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("getting persistentvolumeclaim %s/%s: %w", namespace, name, err)
+	}
+	return &pvc, nil
+}
+
+// ListPersistentVolumeClaims fetches the persistent volume claims in a namespace. A
+// namespace of NamespaceAll lists claims in every namespace.
+func (c *Client) ListPersistentVolumeClaims(namespace string) ([]PersistentVolumeClaim, error) {
+	var pvcs []PersistentVolumeClaim
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "persistentvolumeclaims")
+	if namespace == NamespaceAll {
+		urlStr = c.buildURL("api", "v1", "persistentvolumeclaims")
+	}
+	if err := c.listJSON(urlStr, &pvcs); err != nil {
+		return nil, fmt.Errorf("listing persistentvolumeclaims in %s: %w", namespace, err)
+	}
+	return pvcs, nil
+}
+
+// UpdatePersistentVolumeClaim sends a PUT request to replace a persistent volume claim.
+// On success pvc is refreshed from the server's response.
+func (c *Client) UpdatePersistentVolumeClaim(pvc *PersistentVolumeClaim) error {
+	urlStr := c.buildURL("api", "v1", "namespaces", pvc.Namespace, "persistentvolumeclaims", pvc.Name)
+	if err := c.doJSON(http.MethodPut, urlStr, pvc, pvc, http.StatusOK); err != nil {
+		return fmt.Errorf("updating persistentvolumeclaim %s/%s: %w", pvc.Namespace, pvc.Name, err)
+	}
+	return nil
+}
+
+// DeletePersistentVolumeClaim sends a DELETE request to remove a persistent volume claim.
+func (c *Client) DeletePersistentVolumeClaim(namespace, name string) error {
+	namespace = defaultedNamespace(namespace)
+	urlStr := c.buildURL("api", "v1", "namespaces", namespace, "persistentvolumeclaims", name)
+	if err := c.doJSON(http.MethodDelete, urlStr, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting persistentvolumeclaim %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+)
+
+// ExecPodStream runs command in a pod like ExecPod, but over a stream: its output is
+// written to opts.Stdout and opts.Stderr as it is produced, and opts.Stdin, if set, is
+// its input. With tty the command gets a terminal, sized by what opts.Resize sends,
+// and its error output goes to stdout. It returns the command's exit code.
+func (c *Client) ExecPodStream(namespace, name string, command []string, tty bool, opts streaming.Options) (int, error) {
+	query := url.Values{"command": command}
+	if opts.Stdin != nil {
+		query.Set("stdin", "true")
+	}
+	if tty {
+		query.Set("tty", "true")
+	}
+	status, err := c.streamPod(namespace, name, "exec", query, opts)
+	if err != nil {
+		return 0, fmt.Errorf("executing in pod %s/%s: %w", namespace, name, err)
+	}
+	return status.ExitCode, nil
+}
+
+// AttachPod streams the output of a running pod to opts.Stdout from now on, until the
+// pod stops or opts.Stdin, if set, ends. Each line of opts.Stdin is input to the pod's
+// process.
+func (c *Client) AttachPod(namespace, name string, opts streaming.Options) error {
+	query := url.Values{}
+	if opts.Stdin != nil {
+		query.Set("stdin", "true")
+	}
+	if _, err := c.streamPod(namespace, name, "attach", query, opts); err != nil {
+		return fmt.Errorf("attaching to pod %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// PortForward forwards conn to port of a pod, through the API server and the pod's
+// kubelet, until conn's input ends.
+func (c *Client) PortForward(namespace, name string, port int, conn io.ReadWriter) error {
+	query := url.Values{"port": {strconv.Itoa(port)}}
+	if _, err := c.streamPod(namespace, name, "portforward", query, streaming.Options{Stdin: conn, Stdout: conn}); err != nil {
+		return fmt.Errorf("forwarding to port %d of pod %s/%s: %w", port, namespace, name, err)
+	}
+	return nil
+}
+
+// streamPod runs a stream to a pod subresource with opts, returning its status. A
+// status carrying an error is returned as the error.
+func (c *Client) streamPod(namespace, name, subresource string, query url.Values, opts streaming.Options) (streaming.Status, error) {
+	namespace = defaultedNamespace(namespace)
+	req, err := streaming.NewRequest(c.buildURL("api", "v1", "namespaces", namespace, "pods", name, subresource) + "?" + query.Encode())
+	if err != nil {
+		return streaming.Status{}, fmt.Errorf("creating request: %w", err)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	// Not c.httpClient, whose timeout would cut the stream off: a stream lasts as
+	// long as what is at the other end.
+	resp, err := (&http.Client{Transport: c.httpClient.Transport}).Do(req)
+	if err != nil {
+		return streaming.Status{}, fmt.Errorf("executing request: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		return streaming.Status{}, statusError(resp)
+	}
+	conn, err := streaming.Open(resp)
+	if err != nil {
+		return streaming.Status{}, err
+	}
+	status, err := conn.Stream(opts)
+	if err == nil && status.Error != "" {
+		err = errors.New(status.Error)
+	}
+	return status, err
+}
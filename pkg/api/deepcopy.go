@@ -0,0 +1,325 @@
+package api
+
+// DeepCopy methods for the API types. DeepCopyInto copies the receiver into out,
+// sharing no maps, slices, or pointers with it; DeepCopy returns a new copy, or nil
+// for a nil receiver. Keep them in step with the types: a field added to a type that
+// holds a map, slice, or pointer needs copying here too, which TestDeepCopy checks.
+
+func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
+	*out = *in
+	out.Labels = copyStringMap(in.Labels)
+	out.Annotations = copyStringMap(in.Annotations)
+	out.OwnerReferences = copySlice(in.OwnerReferences)
+	out.DeletionTimestamp = copyPointer(in.DeletionTimestamp)
+	out.Finalizers = copySlice(in.Finalizers)
+	if in.ManagedFields != nil {
+		out.ManagedFields = make([]ManagedFieldsEntry, len(in.ManagedFields))
+		for i := range in.ManagedFields {
+			out.ManagedFields[i] = in.ManagedFields[i]
+			out.ManagedFields[i].Fields = copySlice(in.ManagedFields[i].Fields)
+		}
+	}
+}
+
+func (in *Pod) DeepCopyInto(out *Pod) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Command = copySlice(in.Command)
+	out.Args = copySlice(in.Args)
+	out.ImagePullSecrets = copySlice(in.ImagePullSecrets)
+	if in.SecurityContext != nil {
+		sc := *in.SecurityContext
+		sc.RunAsUser = copyPointer(in.SecurityContext.RunAsUser)
+		out.SecurityContext = &sc
+	}
+	if in.Volumes != nil {
+		out.Volumes = make([]Volume, len(in.Volumes))
+		for i := range in.Volumes {
+			in.Volumes[i].DeepCopyInto(&out.Volumes[i])
+		}
+	}
+	out.VolumeMounts = copySlice(in.VolumeMounts)
+	out.Resources.Requests = in.Resources.Requests.DeepCopy()
+	out.Resources.Limits = in.Resources.Limits.DeepCopy()
+	out.Overhead = in.Overhead.DeepCopy()
+	out.Conditions = copySlice(in.Conditions)
+	out.PhaseTransitions = copySlice(in.PhaseTransitions)
+}
+
+func (in *Pod) DeepCopy() *Pod {
+	if in == nil {
+		return nil
+	}
+	out := new(Pod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in ResourceList) DeepCopy() ResourceList {
+	if in == nil {
+		return nil
+	}
+	out := make(ResourceList, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func (in *Volume) DeepCopyInto(out *Volume) {
+	*out = *in
+	if in.EmptyDir != nil {
+		out.EmptyDir = &EmptyDirVolumeSource{}
+	}
+	out.HostPath = copyPointer(in.HostPath)
+	out.PersistentVolumeClaim = copyPointer(in.PersistentVolumeClaim)
+}
+
+func (in *Node) DeepCopyInto(out *Node) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Conditions = copySlice(in.Conditions)
+	out.Capacity = in.Capacity.DeepCopy()
+	out.Allocatable = in.Allocatable.DeepCopy()
+}
+
+func (in *Node) DeepCopy() *Node {
+	if in == nil {
+		return nil
+	}
+	out := new(Node)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Namespace) DeepCopyInto(out *Namespace) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+func (in *Namespace) DeepCopy() *Namespace {
+	if in == nil {
+		return nil
+	}
+	out := new(Namespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Deployment) DeepCopyInto(out *Deployment) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Selector = copyStringMap(in.Selector)
+	out.Template.Labels = copyStringMap(in.Template.Labels)
+	out.Template.Annotations = copyStringMap(in.Template.Annotations)
+}
+
+func (in *Deployment) DeepCopy() *Deployment {
+	if in == nil {
+		return nil
+	}
+	out := new(Deployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Service) DeepCopyInto(out *Service) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Selector = copyStringMap(in.Selector)
+	out.Ports = copySlice(in.Ports)
+}
+
+func (in *Service) DeepCopy() *Service {
+	if in == nil {
+		return nil
+	}
+	out := new(Service)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Event) DeepCopyInto(out *Event) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+func (in *Event) DeepCopy() *Event {
+	if in == nil {
+		return nil
+	}
+	out := new(Event)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *PodDisruptionBudget) DeepCopyInto(out *PodDisruptionBudget) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Selector = copyStringMap(in.Selector)
+	out.MinAvailable = copyPointer(in.MinAvailable)
+	out.MaxUnavailable = copyPointer(in.MaxUnavailable)
+}
+
+func (in *PodDisruptionBudget) DeepCopy() *PodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.PodSelector = copyStringMap(in.PodSelector)
+	if in.Ingress != nil {
+		out.Ingress = make([]NetworkPolicyIngressRule, len(in.Ingress))
+		for i, rule := range in.Ingress {
+			out.Ingress[i].Ports = copySlice(rule.Ports)
+			if rule.From != nil {
+				out.Ingress[i].From = make([]NetworkPolicyPeer, len(rule.From))
+				for j, peer := range rule.From {
+					out.Ingress[i].From[j] = NetworkPolicyPeer{
+						PodSelector:       copyStringMap(peer.PodSelector),
+						NamespaceSelector: copyStringMap(peer.NamespaceSelector),
+					}
+				}
+			}
+		}
+	}
+}
+
+func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *PersistentVolume) DeepCopyInto(out *PersistentVolume) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.AccessModes = copySlice(in.AccessModes)
+	out.HostPath = copyPointer(in.HostPath)
+	out.ClaimRef = copyPointer(in.ClaimRef)
+}
+
+func (in *PersistentVolume) DeepCopy() *PersistentVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *PersistentVolumeClaim) DeepCopyInto(out *PersistentVolumeClaim) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.AccessModes = copySlice(in.AccessModes)
+}
+
+func (in *PersistentVolumeClaim) DeepCopy() *PersistentVolumeClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Lease) DeepCopyInto(out *Lease) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.RenewTime = copyPointer(in.RenewTime)
+}
+
+func (in *Lease) DeepCopy() *Lease {
+	if in == nil {
+		return nil
+	}
+	out := new(Lease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *APIService) DeepCopyInto(out *APIService) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+func (in *APIService) DeepCopy() *APIService {
+	if in == nil {
+		return nil
+	}
+	out := new(APIService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *ConfigMap) DeepCopyInto(out *ConfigMap) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Data = copyStringMap(in.Data)
+}
+
+func (in *ConfigMap) DeepCopy() *ConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Secret) DeepCopyInto(out *Secret) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Data != nil {
+		out.Data = make(map[string][]byte, len(in.Data))
+		for k, v := range in.Data {
+			out.Data[k] = copySlice(v)
+		}
+	}
+}
+
+func (in *Secret) DeepCopy() *Secret {
+	if in == nil {
+		return nil
+	}
+	out := new(Secret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func copyStringMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+// copySlice copies a slice of values that hold no references themselves.
+func copySlice[T any](in []T) []T {
+	if in == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(in)), in...)
+}
+
+// copyPointer copies what in points to, which must hold no references itself.
+func copyPointer[T any](in *T) *T {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
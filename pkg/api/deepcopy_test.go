@@ -0,0 +1,95 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDeepCopy fills every field of each type, so a map, slice, or pointer field
+// that DeepCopyInto forgets to copy is caught as soon as it is added.
+func TestDeepCopy(t *testing.T) {
+	objects := []interface{}{
+		&Pod{}, &Node{}, &Namespace{}, &Deployment{}, &Service{}, &Event{},
+		&PodDisruptionBudget{}, &NetworkPolicy{}, &PersistentVolume{}, &PersistentVolumeClaim{}, &Lease{}, &ConfigMap{}, &Secret{}, &APIService{},
+	}
+	for _, obj := range objects {
+		in := reflect.ValueOf(obj)
+		fill(in.Elem())
+		out := in.MethodByName("DeepCopy").Call(nil)[0]
+		name := in.Elem().Type().Name()
+		if !reflect.DeepEqual(in.Interface(), out.Interface()) {
+			t.Errorf("%s: copy differs from the original", name)
+		}
+		if path := shared(in.Elem(), out.Elem(), name); path != "" {
+			t.Errorf("%s shares memory with the original", path)
+		}
+	}
+	var nilPod *Pod
+	if nilPod.DeepCopy() != nil {
+		t.Errorf("expected a nil pod to copy to nil")
+	}
+}
+
+// fill sets every field of v to a non-zero value, with one element in each map and slice.
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fill(key)
+		fill(elem)
+		v.SetMapIndex(key, elem)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(1)
+	}
+}
+
+// shared returns the path of the first map, slice, or pointer a and b have in common.
+func shared(a, b reflect.Value, path string) string {
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !a.Type().Field(i).IsExported() {
+				continue
+			}
+			if p := shared(a.Field(i), b.Field(i), path+"."+a.Type().Field(i).Name); p != "" {
+				return p
+			}
+		}
+	case reflect.Pointer, reflect.Map:
+		// All pointers to zero-sized values, such as an EmptyDir, may be equal.
+		zeroSized := a.Kind() == reflect.Pointer && a.Type().Elem().Size() == 0
+		if !a.IsNil() && !zeroSized && a.UnsafePointer() == b.UnsafePointer() {
+			return path
+		}
+		if a.Kind() == reflect.Pointer && !a.IsNil() {
+			return shared(a.Elem(), b.Elem(), path)
+		}
+	case reflect.Slice:
+		if a.Len() > 0 && a.UnsafePointer() == b.UnsafePointer() {
+			return path
+		}
+		for i := 0; i < a.Len(); i++ {
+			if p := shared(a.Index(i), b.Index(i), path); p != "" {
+				return p
+			}
+		}
+	}
+	return ""
+}
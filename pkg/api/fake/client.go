@@ -0,0 +1,545 @@
+// Package fake provides an in-memory implementation of the API client for unit tests.
+// It lets scheduler, kubelet, and controller logic be exercised without an HTTP server.
+//
+//	client := fake.NewClient(&api.Node{Name: "node1", Status: api.NodeReady})
+//	client.PrependReactor("update", "pods", func(action fake.Action) (bool, interface{}, error) {
+//		return true, nil, errors.New("injected failure")
+//	})
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/fields"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+)
+
+const defaultNamespace = "default"
+
+// ctx is the context the fake passes its store. The client's methods, like
+// api.Client's, take none, and the store never times out.
+var ctx = context.Background()
+
+// Action records a single call made against the fake client.
+type Action struct {
+	Verb      string      // "create", "get", "list", "update", "delete", or "deletecollection"
+	Resource  string      // e.g. "pods", "nodes", "deployments", "events"
+	Namespace string      // Empty for cluster-scoped resources
+	Name      string      // Empty for list calls
+	Object    interface{} // The object passed to create/update, if any
+}
+
+// ReactionFunc is invoked for a matching action before the tracker handles it.
+// If handled is true, ret and err are returned to the caller and the tracker is skipped.
+type ReactionFunc func(action Action) (handled bool, ret interface{}, err error)
+
+type reactor struct {
+	verb     string
+	resource string
+	fn       ReactionFunc
+}
+
+func (r reactor) matches(action Action) bool {
+	return (r.verb == "*" || r.verb == action.Verb) && (r.resource == "*" || r.resource == action.Resource)
+}
+
+var _ api.Interface = (*Client)(nil)
+
+// Client is a fake API client backed by an in-memory store.
+// Objects are copied on the way in and out, so tests can't mutate tracked state by accident.
+type Client struct {
+	mu       sync.Mutex
+	tracker  store.Store
+	reactors []reactor
+	actions  []Action
+	eventSeq int // Used to name events created without a name
+
+	components map[string]api.ComponentStatus // Reported component statuses, by name
+}
+
+// NewClient returns a fake client seeded with the given *api.Pod, *api.Node,
+// *api.Namespace, *api.Deployment, *api.Service, *api.PodDisruptionBudget,
+// *api.NetworkPolicy, *api.PersistentVolume, and *api.PersistentVolumeClaim objects.
+func NewClient(objects ...interface{}) *Client {
+	c := &Client{tracker: store.NewInMemoryStore()}
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *api.Pod:
+			pod := *o
+			if pod.Namespace == "" {
+				pod.Namespace = defaultNamespace
+			}
+			if err := c.tracker.CreatePod(ctx, &pod); err != nil {
+				panic(fmt.Sprintf("fake: seeding pod: %v", err))
+			}
+		case *api.Node:
+			node := *o
+			if err := c.tracker.CreateNode(ctx, &node); err != nil {
+				panic(fmt.Sprintf("fake: seeding node: %v", err))
+			}
+		case *api.Namespace:
+			ns := *o
+			if err := c.tracker.CreateNamespace(ctx, &ns); err != nil {
+				panic(fmt.Sprintf("fake: seeding namespace: %v", err))
+			}
+		case *api.Deployment:
+			d := *o
+			if d.Namespace == "" {
+				d.Namespace = defaultNamespace
+			}
+			if err := c.tracker.CreateDeployment(ctx, &d); err != nil {
+				panic(fmt.Sprintf("fake: seeding deployment: %v", err))
+			}
+		case *api.Service:
+			svc := *o
+			if svc.Namespace == "" {
+				svc.Namespace = defaultNamespace
+			}
+			if err := c.tracker.CreateService(ctx, &svc); err != nil {
+				panic(fmt.Sprintf("fake: seeding service: %v", err))
+			}
+		case *api.PodDisruptionBudget:
+			pdb := *o
+			if pdb.Namespace == "" {
+				pdb.Namespace = defaultNamespace
+			}
+			if err := c.tracker.CreatePodDisruptionBudget(ctx, &pdb); err != nil {
+				panic(fmt.Sprintf("fake: seeding poddisruptionbudget: %v", err))
+			}
+		case *api.NetworkPolicy:
+			policy := *o
+			if policy.Namespace == "" {
+				policy.Namespace = defaultNamespace
+			}
+			if err := c.tracker.CreateNetworkPolicy(ctx, &policy); err != nil {
+				panic(fmt.Sprintf("fake: seeding networkpolicy: %v", err))
+			}
+		case *api.PersistentVolume:
+			pv := *o
+			if pv.Phase == "" {
+				pv.Phase = api.VolumeAvailable
+			}
+			if err := c.tracker.CreatePersistentVolume(ctx, &pv); err != nil {
+				panic(fmt.Sprintf("fake: seeding persistentvolume: %v", err))
+			}
+		case *api.PersistentVolumeClaim:
+			pvc := *o
+			if pvc.Namespace == "" {
+				pvc.Namespace = defaultNamespace
+			}
+			if pvc.Phase == "" {
+				pvc.Phase = api.ClaimPending
+			}
+			if err := c.tracker.CreatePersistentVolumeClaim(ctx, &pvc); err != nil {
+				panic(fmt.Sprintf("fake: seeding persistentvolumeclaim: %v", err))
+			}
+		case *api.Secret:
+			secret := *o
+			if secret.Namespace == "" {
+				secret.Namespace = defaultNamespace
+			}
+			if err := c.tracker.CreateSecret(ctx, &secret); err != nil {
+				panic(fmt.Sprintf("fake: seeding secret: %v", err))
+			}
+		default:
+			panic(fmt.Sprintf("fake: unsupported object type %T", obj))
+		}
+	}
+	return c
+}
+
+// PrependReactor adds a reaction that runs before all existing reactions.
+// verb and resource may be "*" to match anything.
+func (c *Client) PrependReactor(verb, resource string, fn ReactionFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reactors = append([]reactor{{verb: verb, resource: resource, fn: fn}}, c.reactors...)
+}
+
+// AddReactor adds a reaction that runs after all existing reactions.
+func (c *Client) AddReactor(verb, resource string, fn ReactionFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reactors = append(c.reactors, reactor{verb: verb, resource: resource, fn: fn})
+}
+
+// Actions returns every action recorded so far, in order.
+func (c *Client) Actions() []Action {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Action(nil), c.actions...)
+}
+
+// ClearActions forgets all recorded actions.
+func (c *Client) ClearActions() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actions = nil
+}
+
+// invoke records action and runs the matching reactors. It returns handled=true if a
+// reactor produced the result.
+func (c *Client) invoke(action Action) (bool, interface{}, error) {
+	c.mu.Lock()
+	c.actions = append(c.actions, action)
+	reactors := append([]reactor(nil), c.reactors...)
+	c.mu.Unlock()
+
+	for _, r := range reactors {
+		if !r.matches(action) {
+			continue
+		}
+		if handled, ret, err := r.fn(action); handled {
+			return true, ret, err
+		}
+	}
+	return false, nil, nil
+}
+
+// statusError turns an error from the tracker into the *api.StatusError the API server
+// answers with, so that callers tell a missing or existing object apart with
+// api.IsNotFound and api.IsAlreadyExists as they would against the real client. Other
+// errors are returned as they are.
+func statusError(err error) error {
+	var code int
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, store.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, store.ErrAlreadyExists), errors.Is(err, store.ErrConflict):
+		code = http.StatusConflict
+	default:
+		return err
+	}
+	return &api.StatusError{Code: code, Message: err.Error()}
+}
+
+// GetBaseURL returns a placeholder URL identifying the fake.
+func (c *Client) GetBaseURL() string {
+	return "fake://"
+}
+
+// CreateNode registers a node, deriving its status from its conditions, and them from
+// its status if it has none, like the API server.
+func (c *Client) CreateNode(node *api.Node) (*api.Node, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "nodes", Name: node.Name, Object: node}); handled {
+		n, _ := ret.(*api.Node)
+		return n, err
+	}
+	if node.Name == "" {
+		return nil, fmt.Errorf("node name must be provided")
+	}
+	created := *node.DeepCopy()
+	api.DeriveNodeStatus(&created)
+	if err := c.tracker.CreateNode(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// UpdateNode replaces a tracked node and, like the real client, refreshes the argument
+// with the stored copy.
+func (c *Client) UpdateNode(node *api.Node) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "nodes", Name: node.Name, Object: node}); handled {
+		return err
+	}
+	if node.Name == "" {
+		return fmt.Errorf("node name must be specified for update")
+	}
+	updated := *node.DeepCopy()
+	api.DeriveNodeStatus(&updated)
+	if err := c.tracker.UpdateNode(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*node = updated
+	return nil
+}
+
+// GetNode returns a copy of the named node.
+func (c *Client) GetNode(name string) (*api.Node, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "nodes", Name: name}); handled {
+		n, _ := ret.(*api.Node)
+		return n, err
+	}
+	node, err := c.tracker.GetNode(ctx, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *node
+	return &out, nil
+}
+
+// ListNodes returns copies of all tracked nodes, optionally filtered by status.
+func (c *Client) ListNodes(status api.NodeStatus) ([]api.Node, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "nodes"}); handled {
+		n, _ := ret.([]api.Node)
+		return n, err
+	}
+	nodes, err := c.tracker.ListNodes(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Node
+	for _, node := range nodes {
+		if status == "" || node.Status == status {
+			result = append(result, *node)
+		}
+	}
+	return result, nil
+}
+
+// ListNodesMatching returns the tracked nodes that match labelSelector and
+// fieldSelector, as ListNodesWithOptions does.
+func (c *Client) ListNodesMatching(labelSelector, fieldSelector string) ([]api.Node, error) {
+	return c.ListNodesWithOptions(api.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+}
+
+// ListNodesWithOptions returns the tracked nodes that match opts' selectors, all in
+// one page. The recorded action's Object is opts.
+func (c *Client) ListNodesWithOptions(opts api.ListOptions) ([]api.Node, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "nodes", Object: opts}); handled {
+		n, _ := ret.([]api.Node)
+		return n, err
+	}
+	labelSel, fieldSel, err := parseSelectors(opts.LabelSelector, opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.tracker.ListNodes(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Node
+	for _, node := range nodes {
+		if labelSel.Matches(node.Labels) && fieldSel.Matches(fields.NodeFields(node)) {
+			result = append(result, *node)
+		}
+	}
+	return result, nil
+}
+
+// DeleteNode removes a tracked node.
+func (c *Client) DeleteNode(name string) error {
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "nodes", Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteNode(ctx, name))
+}
+
+// ApproveNode approves a node pending approval, making it Ready and schedulable.
+func (c *Client) ApproveNode(name string) (*api.Node, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "approve", Resource: "nodes", Name: name}); handled {
+		n, _ := ret.(*api.Node)
+		return n, err
+	}
+	node, err := c.tracker.GetNode(ctx, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	approved := *node.DeepCopy()
+	if approved.PendingApproval {
+		approved.PendingApproval = false
+		api.SetNodeCondition(&approved, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionTrue, Reason: "NodeApproved", Message: "The node was approved"})
+		api.DeriveNodeStatus(&approved)
+		approved.Unschedulable = false
+		if err := c.tracker.UpdateNode(ctx, &approved); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	return &approved, nil
+}
+
+// CreatePod creates a pod in namespace, starting it in the Pending phase like the API server.
+func (c *Client) CreatePod(namespace string, pod *api.Pod) (*api.Pod, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "pods", Namespace: namespace, Name: pod.Name, Object: pod}); handled {
+		p, _ := ret.(*api.Pod)
+		return p, err
+	}
+	if pod.Name == "" {
+		return nil, fmt.Errorf("pod name must be provided")
+	}
+	created := *pod
+	created.Namespace = namespace
+	created.Phase = api.PodPending
+	created.NodeName = ""
+	validation.SetDefaults_Pod(&created)
+	if err := c.tracker.CreatePod(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// CreatePods creates each pod in turn as CreatePod does, reporting failures in the
+// results rather than stopping at the first.
+func (c *Client) CreatePods(namespace string, pods []api.Pod) ([]api.PodBatchResult, error) {
+	results := make([]api.PodBatchResult, len(pods))
+	for i := range pods {
+		created, err := c.CreatePod(namespace, &pods[i])
+		switch {
+		case err == nil:
+			results[i] = api.PodBatchResult{Code: http.StatusCreated, Pod: created}
+		case api.IsAlreadyExists(err):
+			results[i] = api.PodBatchResult{Code: http.StatusConflict, Error: err.Error()}
+		default:
+			results[i] = api.PodBatchResult{Code: http.StatusUnprocessableEntity, Error: err.Error()}
+		}
+	}
+	return results, nil
+}
+
+// GetPod returns a copy of the named pod.
+func (c *Client) GetPod(namespace, name string) (*api.Pod, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "pods", Namespace: namespace, Name: name}); handled {
+		p, _ := ret.(*api.Pod)
+		return p, err
+	}
+	pod, err := c.tracker.GetPod(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *pod
+	return &out, nil
+}
+
+// ListPods returns copies of the pods in namespace, optionally filtered by phase.
+func (c *Client) ListPods(namespace string, phase api.PodPhase) ([]api.Pod, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "pods", Namespace: namespace}); handled {
+		p, _ := ret.([]api.Pod)
+		return p, err
+	}
+	pods, err := c.tracker.ListPods(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Pod
+	for _, pod := range pods {
+		if phase == "" || pod.Phase == phase {
+			result = append(result, *pod)
+		}
+	}
+	return result, nil
+}
+
+// ListPodsMatching returns the tracked pods in namespace that match labelSelector and
+// fieldSelector, as ListPodsWithOptions does.
+func (c *Client) ListPodsMatching(namespace, labelSelector, fieldSelector string) ([]api.Pod, error) {
+	return c.ListPodsWithOptions(namespace, api.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+}
+
+// ListPodsWithOptions returns the tracked pods in namespace that match opts' selectors,
+// all in one page. The recorded action's Object is opts.
+func (c *Client) ListPodsWithOptions(namespace string, opts api.ListOptions) ([]api.Pod, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "pods", Namespace: namespace, Object: opts}); handled {
+		p, _ := ret.([]api.Pod)
+		return p, err
+	}
+	labelSel, fieldSel, err := parseSelectors(opts.LabelSelector, opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.tracker.ListPods(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Pod
+	for _, pod := range pods {
+		if labelSel.Matches(pod.Labels) && fieldSel.Matches(fields.PodFields(pod)) {
+			result = append(result, *pod)
+		}
+	}
+	return result, nil
+}
+
+// parseSelectors parses a label selector and a field selector.
+func parseSelectors(labelSelector, fieldSelector string) (labels.Selector, fields.Selector, error) {
+	labelSel, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldSel, err := fields.Parse(fieldSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	return labelSel, fieldSel, nil
+}
+
+// UpdatePod replaces a tracked pod, subject to the same termination rules as the real
+// store, and refreshes the argument with the stored copy.
+func (c *Client) UpdatePod(pod *api.Pod) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "pods", Namespace: pod.Namespace, Name: pod.Name, Object: pod}); handled {
+		return err
+	}
+	updated := *pod
+	if existing, err := c.tracker.GetPod(ctx, pod.Namespace, pod.Name); err == nil {
+		api.ConvertDeprecatedPodPhase(&updated, existing)
+	}
+	if err := c.tracker.UpdatePod(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*pod = updated
+	return nil
+}
+
+// DeletePod marks a pod for deletion.
+func (c *Client) DeletePod(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "pods", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeletePod(ctx, namespace, name))
+}
+
+// DeleteCollection marks every matching pod in namespace for deletion, as
+// DeleteCollectionWithOptions does.
+func (c *Client) DeleteCollection(namespace, labelSelector, fieldSelector string) ([]api.Pod, error) {
+	return c.DeleteCollectionWithOptions(namespace, api.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+}
+
+// DeleteCollectionWithOptions marks every pod in namespace that matches opts'
+// selectors for deletion. The recorded action's Object is opts.
+func (c *Client) DeleteCollectionWithOptions(namespace string, opts api.ListOptions) ([]api.Pod, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "deletecollection", Resource: "pods", Namespace: namespace, Object: opts}); handled {
+		p, _ := ret.([]api.Pod)
+		return p, err
+	}
+	labelSel, fieldSel, err := parseSelectors(opts.LabelSelector, opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.tracker.ListPods(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var deleted []api.Pod
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !labelSel.Matches(pod.Labels) || !fieldSel.Matches(fields.PodFields(pod)) {
+			continue
+		}
+		if err := c.tracker.DeletePod(ctx, namespace, pod.Name); err != nil {
+			return deleted, statusError(err)
+		}
+		deleted = append(deleted, *pod)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestClientTracksObjects(t *testing.T) {
+	client := NewClient(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady})
+
+	created, err := client.CreatePod("", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx", Phase: api.PodRunning})
+	if err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if created.Namespace != "default" || created.Phase != api.PodPending {
+		t.Fatalf("expected defaulted pod, got namespace=%q phase=%q", created.Namespace, created.Phase)
+	}
+
+	// Mutating a returned object must not change tracked state.
+	created.Phase = api.PodFailed
+	pod, err := client.GetPod("default", "web")
+	if err != nil {
+		t.Fatalf("GetPod: %v", err)
+	}
+	if pod.Phase != api.PodPending {
+		t.Fatalf("tracked pod was mutated through returned pointer: phase=%q", pod.Phase)
+	}
+
+	pod.NodeName = "node1"
+	pod.Phase = api.PodScheduled
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	scheduled, err := client.ListPods("default", api.PodScheduled)
+	if err != nil || len(scheduled) != 1 {
+		t.Fatalf("expected one scheduled pod, got %d (err=%v)", len(scheduled), err)
+	}
+
+	if err := client.DeletePod("default", "web"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	pod, _ = client.GetPod("default", "web")
+	if !api.IsPodTerminating(pod) || pod.Phase != api.PodScheduled {
+		t.Fatalf("expected pod to be terminating in phase Scheduled, got phase=%q deletionTimestamp=%v", pod.Phase, pod.DeletionTimestamp)
+	}
+	if ready := api.GetPodCondition(pod, api.PodReadyCondition); ready == nil || ready.Status != api.ConditionFalse || ready.Reason != "Terminating" {
+		t.Fatalf("expected Ready=False (Terminating), got %+v", ready)
+	}
+
+	var verbs []string
+	for _, a := range client.Actions() {
+		verbs = append(verbs, a.Verb)
+	}
+	want := []string{"create", "get", "update", "list", "delete", "get"}
+	if len(verbs) != len(want) {
+		t.Fatalf("expected actions %v, got %v", want, verbs)
+	}
+	for i := range want {
+		if verbs[i] != want[i] {
+			t.Fatalf("expected actions %v, got %v", want, verbs)
+		}
+	}
+}
+
+func TestClientReactors(t *testing.T) {
+	client := NewClient()
+	injected := errors.New("apiserver unavailable")
+	client.PrependReactor("list", "nodes", func(Action) (bool, interface{}, error) {
+		return true, nil, injected
+	})
+
+	if _, err := client.ListNodes(""); !errors.Is(err, injected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if _, err := client.ListPods("default", ""); err != nil {
+		t.Fatalf("reactor should only match nodes, got %v", err)
+	}
+}
+
+func TestClientDeleteCollection(t *testing.T) {
+	client := NewClient(
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web-1", Labels: map[string]string{"app": "web"}}, Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web-2", Labels: map[string]string{"app": "web"}}, Phase: api.PodFailed},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "db-1", Labels: map[string]string{"app": "db"}}, Phase: api.PodFailed},
+	)
+
+	deleted, err := client.DeleteCollection("default", "app=web", "status.phase=Failed")
+	if err != nil {
+		t.Fatalf("DeleteCollection: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Name != "web-2" {
+		t.Fatalf("expected only web-2 to be deleted, got %+v", deleted)
+	}
+
+	for _, name := range []string{"web-1", "db-1"} {
+		pod, err := client.GetPod("default", name)
+		if err != nil {
+			t.Fatalf("GetPod(%s): %v", name, err)
+		}
+		if pod.DeletionTimestamp != nil {
+			t.Errorf("pod %s should not have been deleted", name)
+		}
+	}
+}
@@ -0,0 +1,965 @@
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/disruption"
+)
+
+// CreateNamespace creates a namespace in the Active phase.
+func (c *Client) CreateNamespace(ns *api.Namespace) (*api.Namespace, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "namespaces", Name: ns.Name, Object: ns}); handled {
+		n, _ := ret.(*api.Namespace)
+		return n, err
+	}
+	if ns.Name == "" {
+		return nil, fmt.Errorf("namespace name must be provided")
+	}
+	created := *ns
+	created.Phase = api.NamespaceActive
+	if err := c.tracker.CreateNamespace(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetNamespace returns a copy of the named namespace.
+func (c *Client) GetNamespace(name string) (*api.Namespace, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "namespaces", Name: name}); handled {
+		n, _ := ret.(*api.Namespace)
+		return n, err
+	}
+	ns, err := c.tracker.GetNamespace(ctx, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *ns
+	return &out, nil
+}
+
+// ListNamespaces returns copies of all tracked namespaces.
+func (c *Client) ListNamespaces() ([]api.Namespace, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "namespaces"}); handled {
+		n, _ := ret.([]api.Namespace)
+		return n, err
+	}
+	namespaces, err := c.tracker.ListNamespaces(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Namespace
+	for _, ns := range namespaces {
+		result = append(result, *ns)
+	}
+	return result, nil
+}
+
+// UpdateNamespace replaces a tracked namespace and, like the real client, refreshes
+// the argument with the stored copy.
+func (c *Client) UpdateNamespace(ns *api.Namespace) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "namespaces", Name: ns.Name, Object: ns}); handled {
+		return err
+	}
+	updated := *ns
+	if err := c.tracker.UpdateNamespace(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*ns = updated
+	return nil
+}
+
+// DeleteNamespace marks an active namespace Terminating, like the API server, and
+// removes a terminating one. Unlike the API server, only pods that aren't gone keep it
+// from being removed, with a 409 *api.StatusError.
+func (c *Client) DeleteNamespace(name string) error {
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "namespaces", Name: name}); handled {
+		return err
+	}
+	ns, err := c.tracker.GetNamespace(ctx, name)
+	if err != nil {
+		return statusError(err)
+	}
+	if ns.Phase != api.NamespaceTerminating {
+		now := time.Now()
+		ns.Phase = api.NamespaceTerminating
+		ns.DeletionTimestamp = &now
+		return statusError(c.tracker.UpdateNamespace(ctx, ns))
+	}
+	pods, err := c.tracker.ListPods(ctx, name)
+	if err != nil {
+		return statusError(err)
+	}
+	for _, pod := range pods {
+		if !api.IsPodGone(pod) {
+			return &api.StatusError{Code: http.StatusConflict, Message: fmt.Sprintf("namespace %s still holds pod/%s", name, pod.Name)}
+		}
+	}
+	return statusError(c.tracker.DeleteNamespace(ctx, name))
+}
+
+// CreateDeployment creates a deployment in namespace.
+func (c *Client) CreateDeployment(namespace string, d *api.Deployment) (*api.Deployment, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "deployments", Namespace: namespace, Name: d.Name, Object: d}); handled {
+		out, _ := ret.(*api.Deployment)
+		return out, err
+	}
+	if d.Name == "" {
+		return nil, fmt.Errorf("deployment name must be provided")
+	}
+	created := *d
+	created.Namespace = namespace
+	if err := c.tracker.CreateDeployment(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetDeployment returns a copy of the named deployment.
+func (c *Client) GetDeployment(namespace, name string) (*api.Deployment, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "deployments", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.Deployment)
+		return out, err
+	}
+	d, err := c.tracker.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *d
+	return &out, nil
+}
+
+// ListDeployments returns copies of the deployments in namespace.
+func (c *Client) ListDeployments(namespace string) ([]api.Deployment, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "deployments", Namespace: namespace}); handled {
+		out, _ := ret.([]api.Deployment)
+		return out, err
+	}
+	deployments, err := c.tracker.ListDeployments(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Deployment
+	for _, d := range deployments {
+		result = append(result, *d)
+	}
+	return result, nil
+}
+
+// UpdateDeployment replaces a tracked deployment and, like the real client, refreshes
+// the argument with the stored copy.
+func (c *Client) UpdateDeployment(d *api.Deployment) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "deployments", Namespace: d.Namespace, Name: d.Name, Object: d}); handled {
+		return err
+	}
+	updated := *d
+	if err := c.tracker.UpdateDeployment(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*d = updated
+	return nil
+}
+
+// DeleteDeployment removes a tracked deployment.
+func (c *Client) DeleteDeployment(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "deployments", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteDeployment(ctx, namespace, name))
+}
+
+// CreateService creates a service in namespace, defaulting its type to ClusterIP.
+func (c *Client) CreateService(namespace string, svc *api.Service) (*api.Service, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "services", Namespace: namespace, Name: svc.Name, Object: svc}); handled {
+		out, _ := ret.(*api.Service)
+		return out, err
+	}
+	if svc.Name == "" {
+		return nil, fmt.Errorf("service name must be provided")
+	}
+	created := *svc
+	created.Namespace = namespace
+	if created.Type == "" {
+		created.Type = api.ServiceTypeClusterIP
+	}
+	if err := c.tracker.CreateService(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetService returns a copy of the named service.
+func (c *Client) GetService(namespace, name string) (*api.Service, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "services", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.Service)
+		return out, err
+	}
+	svc, err := c.tracker.GetService(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *svc
+	return &out, nil
+}
+
+// ListServices returns copies of the services in namespace.
+func (c *Client) ListServices(namespace string) ([]api.Service, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "services", Namespace: namespace}); handled {
+		out, _ := ret.([]api.Service)
+		return out, err
+	}
+	services, err := c.tracker.ListServices(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Service
+	for _, svc := range services {
+		result = append(result, *svc)
+	}
+	return result, nil
+}
+
+// UpdateService replaces a tracked service and, like the real client, refreshes
+// the argument with the stored copy.
+func (c *Client) UpdateService(svc *api.Service) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "services", Namespace: svc.Namespace, Name: svc.Name, Object: svc}); handled {
+		return err
+	}
+	updated := *svc
+	if err := c.tracker.UpdateService(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*svc = updated
+	return nil
+}
+
+// DeleteService removes a tracked service.
+func (c *Client) DeleteService(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "services", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteService(ctx, namespace, name))
+}
+
+// CreateEvent records an event. Unlike the API server, the fake does not fold repeats.
+func (c *Client) CreateEvent(namespace string, event *api.Event) (*api.Event, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "events", Namespace: namespace, Name: event.Name, Object: event}); handled {
+		out, _ := ret.(*api.Event)
+		return out, err
+	}
+	created := *event
+	created.Namespace = namespace
+	if created.Name == "" {
+		c.mu.Lock()
+		c.eventSeq++
+		created.Name = fmt.Sprintf("%s.%d", event.InvolvedObject.Name, c.eventSeq)
+		c.mu.Unlock()
+	}
+	if created.Count == 0 {
+		created.Count = 1
+	}
+	if err := c.tracker.CreateEvent(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// ListEvents returns copies of the events in namespace.
+func (c *Client) ListEvents(namespace string) ([]api.Event, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "events", Namespace: namespace}); handled {
+		out, _ := ret.([]api.Event)
+		return out, err
+	}
+	events, err := c.tracker.ListEvents(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Event
+	for _, e := range events {
+		result = append(result, *e)
+	}
+	return result, nil
+}
+
+// CreatePodDisruptionBudget creates a pod disruption budget in namespace.
+func (c *Client) CreatePodDisruptionBudget(namespace string, pdb *api.PodDisruptionBudget) (*api.PodDisruptionBudget, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "poddisruptionbudgets", Namespace: namespace, Name: pdb.Name, Object: pdb}); handled {
+		out, _ := ret.(*api.PodDisruptionBudget)
+		return out, err
+	}
+	if pdb.Name == "" {
+		return nil, fmt.Errorf("poddisruptionbudget name must be provided")
+	}
+	created := *pdb
+	created.Namespace = namespace
+	if err := validation.Validate_PodDisruptionBudget(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreatePodDisruptionBudget(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	return c.withStatus(&created)
+}
+
+// GetPodDisruptionBudget returns a copy of the named pod disruption budget with its
+// status computed from the tracked pods.
+func (c *Client) GetPodDisruptionBudget(namespace, name string) (*api.PodDisruptionBudget, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "poddisruptionbudgets", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.PodDisruptionBudget)
+		return out, err
+	}
+	pdb, err := c.tracker.GetPodDisruptionBudget(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return c.withStatus(pdb)
+}
+
+// ListPodDisruptionBudgets returns copies of the pod disruption budgets in namespace.
+func (c *Client) ListPodDisruptionBudgets(namespace string) ([]api.PodDisruptionBudget, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "poddisruptionbudgets", Namespace: namespace}); handled {
+		out, _ := ret.([]api.PodDisruptionBudget)
+		return out, err
+	}
+	pdbs, err := c.tracker.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		out, err := c.withStatus(pdb)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *out)
+	}
+	return result, nil
+}
+
+// DeletePodDisruptionBudget removes a tracked pod disruption budget.
+func (c *Client) DeletePodDisruptionBudget(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "poddisruptionbudgets", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeletePodDisruptionBudget(ctx, namespace, name))
+}
+
+// CreateNetworkPolicy creates a network policy in namespace.
+func (c *Client) CreateNetworkPolicy(namespace string, policy *api.NetworkPolicy) (*api.NetworkPolicy, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "networkpolicies", Namespace: namespace, Name: policy.Name, Object: policy}); handled {
+		out, _ := ret.(*api.NetworkPolicy)
+		return out, err
+	}
+	created := *policy
+	created.Namespace = namespace
+	if err := validation.Validate_NetworkPolicy(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreateNetworkPolicy(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetNetworkPolicy returns a copy of the named network policy.
+func (c *Client) GetNetworkPolicy(namespace, name string) (*api.NetworkPolicy, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "networkpolicies", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.NetworkPolicy)
+		return out, err
+	}
+	policy, err := c.tracker.GetNetworkPolicy(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *policy
+	return &out, nil
+}
+
+// ListNetworkPolicies returns copies of the network policies in namespace.
+func (c *Client) ListNetworkPolicies(namespace string) ([]api.NetworkPolicy, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "networkpolicies", Namespace: namespace}); handled {
+		out, _ := ret.([]api.NetworkPolicy)
+		return out, err
+	}
+	policies, err := c.tracker.ListNetworkPolicies(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.NetworkPolicy
+	for _, policy := range policies {
+		result = append(result, *policy)
+	}
+	return result, nil
+}
+
+// UpdateNetworkPolicy replaces a tracked network policy and refreshes the argument with
+// the stored copy.
+func (c *Client) UpdateNetworkPolicy(policy *api.NetworkPolicy) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "networkpolicies", Namespace: policy.Namespace, Name: policy.Name, Object: policy}); handled {
+		return err
+	}
+	if err := validation.Validate_NetworkPolicy(policy).ToAggregate(); err != nil {
+		return err
+	}
+	updated := *policy
+	if err := c.tracker.UpdateNetworkPolicy(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*policy = updated
+	return nil
+}
+
+// DeleteNetworkPolicy removes a tracked network policy.
+func (c *Client) DeleteNetworkPolicy(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "networkpolicies", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteNetworkPolicy(ctx, namespace, name))
+}
+
+// CreateConfigMap creates a config map in namespace.
+func (c *Client) CreateConfigMap(namespace string, configMap *api.ConfigMap) (*api.ConfigMap, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "configmaps", Namespace: namespace, Name: configMap.Name, Object: configMap}); handled {
+		out, _ := ret.(*api.ConfigMap)
+		return out, err
+	}
+	created := *configMap
+	created.Namespace = namespace
+	if err := validation.Validate_ConfigMap(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreateConfigMap(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetConfigMap returns a copy of the named config map.
+func (c *Client) GetConfigMap(namespace, name string) (*api.ConfigMap, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "configmaps", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.ConfigMap)
+		return out, err
+	}
+	configMap, err := c.tracker.GetConfigMap(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *configMap
+	return &out, nil
+}
+
+// ListConfigMaps returns copies of the config maps in namespace.
+func (c *Client) ListConfigMaps(namespace string) ([]api.ConfigMap, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "configmaps", Namespace: namespace}); handled {
+		out, _ := ret.([]api.ConfigMap)
+		return out, err
+	}
+	configMaps, err := c.tracker.ListConfigMaps(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.ConfigMap
+	for _, configMap := range configMaps {
+		result = append(result, *configMap)
+	}
+	return result, nil
+}
+
+// UpdateConfigMap replaces a tracked config map and refreshes the argument with
+// the stored copy.
+func (c *Client) UpdateConfigMap(configMap *api.ConfigMap) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "configmaps", Namespace: configMap.Namespace, Name: configMap.Name, Object: configMap}); handled {
+		return err
+	}
+	if err := validation.Validate_ConfigMap(configMap).ToAggregate(); err != nil {
+		return err
+	}
+	updated := *configMap
+	if err := c.tracker.UpdateConfigMap(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*configMap = updated
+	return nil
+}
+
+// DeleteConfigMap removes a tracked config map.
+func (c *Client) DeleteConfigMap(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "configmaps", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteConfigMap(ctx, namespace, name))
+}
+
+// CreateSecret creates a secret in namespace.
+func (c *Client) CreateSecret(namespace string, secret *api.Secret) (*api.Secret, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "secrets", Namespace: namespace, Name: secret.Name, Object: secret}); handled {
+		out, _ := ret.(*api.Secret)
+		return out, err
+	}
+	created := *secret
+	created.Namespace = namespace
+	validation.SetDefaults_Secret(&created)
+	if err := validation.Validate_Secret(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreateSecret(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetSecret returns a copy of the named secret.
+func (c *Client) GetSecret(namespace, name string) (*api.Secret, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "secrets", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.Secret)
+		return out, err
+	}
+	secret, err := c.tracker.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *secret
+	return &out, nil
+}
+
+// ListSecrets returns copies of the secrets in namespace.
+func (c *Client) ListSecrets(namespace string) ([]api.Secret, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "secrets", Namespace: namespace}); handled {
+		out, _ := ret.([]api.Secret)
+		return out, err
+	}
+	secrets, err := c.tracker.ListSecrets(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Secret
+	for _, secret := range secrets {
+		result = append(result, *secret)
+	}
+	return result, nil
+}
+
+// UpdateSecret replaces a tracked secret and refreshes the argument with
+// the stored copy.
+func (c *Client) UpdateSecret(secret *api.Secret) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "secrets", Namespace: secret.Namespace, Name: secret.Name, Object: secret}); handled {
+		return err
+	}
+	if err := validation.Validate_Secret(secret).ToAggregate(); err != nil {
+		return err
+	}
+	updated := *secret
+	if err := c.tracker.UpdateSecret(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*secret = updated
+	return nil
+}
+
+// DeleteSecret removes a tracked secret.
+func (c *Client) DeleteSecret(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "secrets", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteSecret(ctx, namespace, name))
+}
+
+func (c *Client) withStatus(pdb *api.PodDisruptionBudget) (*api.PodDisruptionBudget, error) {
+	pods, err := c.tracker.ListPods(ctx, pdb.Namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *pdb
+	out.Status = disruption.Status(pdb, pods)
+	return &out, nil
+}
+
+// EvictPod marks a pod for deletion unless a disruption budget forbids it, in which
+// case it returns a 429 *api.StatusError like the API server. The recorded action has
+// Verb "create" and Resource "pods/eviction".
+func (c *Client) EvictPod(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "create", Resource: "pods/eviction", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	pod, err := c.tracker.GetPod(ctx, namespace, name)
+	if err != nil {
+		return statusError(err)
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+	pdbs, err := c.tracker.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		return statusError(err)
+	}
+	pods, err := c.tracker.ListPods(ctx, namespace)
+	if err != nil {
+		return statusError(err)
+	}
+	if err := disruption.CheckEviction(pod, pdbs, pods); err != nil {
+		if errors.Is(err, disruption.ErrBudgetViolated) {
+			return &api.StatusError{Code: http.StatusTooManyRequests, Message: err.Error()}
+		}
+		return err
+	}
+	return statusError(c.tracker.DeletePod(ctx, namespace, name))
+}
+
+// CreatePersistentVolume creates a persistent volume in the Available phase.
+func (c *Client) CreatePersistentVolume(pv *api.PersistentVolume) (*api.PersistentVolume, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "persistentvolumes", Name: pv.Name, Object: pv}); handled {
+		out, _ := ret.(*api.PersistentVolume)
+		return out, err
+	}
+	created := *pv
+	created.Phase = api.VolumeAvailable
+	validation.SetDefaults_PersistentVolume(&created)
+	if err := validation.Validate_PersistentVolume(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreatePersistentVolume(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetPersistentVolume returns a copy of the named persistent volume.
+func (c *Client) GetPersistentVolume(name string) (*api.PersistentVolume, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "persistentvolumes", Name: name}); handled {
+		out, _ := ret.(*api.PersistentVolume)
+		return out, err
+	}
+	pv, err := c.tracker.GetPersistentVolume(ctx, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *pv
+	return &out, nil
+}
+
+// ListPersistentVolumes returns copies of all persistent volumes.
+func (c *Client) ListPersistentVolumes() ([]api.PersistentVolume, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "persistentvolumes"}); handled {
+		out, _ := ret.([]api.PersistentVolume)
+		return out, err
+	}
+	pvs, err := c.tracker.ListPersistentVolumes(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.PersistentVolume
+	for _, pv := range pvs {
+		result = append(result, *pv)
+	}
+	return result, nil
+}
+
+// UpdatePersistentVolume replaces a tracked persistent volume and, like the real client,
+// refreshes the argument with the stored copy.
+func (c *Client) UpdatePersistentVolume(pv *api.PersistentVolume) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "persistentvolumes", Name: pv.Name, Object: pv}); handled {
+		return err
+	}
+	updated := *pv
+	if err := c.tracker.UpdatePersistentVolume(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*pv = updated
+	return nil
+}
+
+// DeletePersistentVolume removes a tracked persistent volume.
+func (c *Client) DeletePersistentVolume(name string) error {
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "persistentvolumes", Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeletePersistentVolume(ctx, name))
+}
+
+// CreatePersistentVolumeClaim creates a persistent volume claim in namespace in the
+// Pending phase.
+func (c *Client) CreatePersistentVolumeClaim(namespace string, pvc *api.PersistentVolumeClaim) (*api.PersistentVolumeClaim, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "persistentvolumeclaims", Namespace: namespace, Name: pvc.Name, Object: pvc}); handled {
+		out, _ := ret.(*api.PersistentVolumeClaim)
+		return out, err
+	}
+	created := *pvc
+	created.Namespace = namespace
+	created.Phase = api.ClaimPending
+	created.Capacity = ""
+	if err := validation.Validate_PersistentVolumeClaim(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreatePersistentVolumeClaim(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetPersistentVolumeClaim returns a copy of the named persistent volume claim.
+func (c *Client) GetPersistentVolumeClaim(namespace, name string) (*api.PersistentVolumeClaim, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "persistentvolumeclaims", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.PersistentVolumeClaim)
+		return out, err
+	}
+	pvc, err := c.tracker.GetPersistentVolumeClaim(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *pvc
+	return &out, nil
+}
+
+// ListPersistentVolumeClaims returns copies of the persistent volume claims in namespace,
+// or in every namespace for NamespaceAll.
+func (c *Client) ListPersistentVolumeClaims(namespace string) ([]api.PersistentVolumeClaim, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "persistentvolumeclaims", Namespace: namespace}); handled {
+		out, _ := ret.([]api.PersistentVolumeClaim)
+		return out, err
+	}
+	pvcs, err := c.tracker.ListPersistentVolumeClaims(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.PersistentVolumeClaim
+	for _, pvc := range pvcs {
+		result = append(result, *pvc)
+	}
+	return result, nil
+}
+
+// UpdatePersistentVolumeClaim replaces a tracked persistent volume claim and, like the
+// real client, refreshes the argument with the stored copy.
+func (c *Client) UpdatePersistentVolumeClaim(pvc *api.PersistentVolumeClaim) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "persistentvolumeclaims", Namespace: pvc.Namespace, Name: pvc.Name, Object: pvc}); handled {
+		return err
+	}
+	updated := *pvc
+	if err := c.tracker.UpdatePersistentVolumeClaim(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*pvc = updated
+	return nil
+}
+
+// DeletePersistentVolumeClaim removes a tracked persistent volume claim.
+func (c *Client) DeletePersistentVolumeClaim(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "persistentvolumeclaims", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeletePersistentVolumeClaim(ctx, namespace, name))
+}
+
+// CreateLease creates a lease in namespace.
+func (c *Client) CreateLease(namespace string, lease *api.Lease) (*api.Lease, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "create", Resource: "leases", Namespace: namespace, Name: lease.Name, Object: lease}); handled {
+		out, _ := ret.(*api.Lease)
+		return out, err
+	}
+	created := *lease
+	created.Namespace = namespace
+	validation.SetDefaults_Lease(&created)
+	if err := validation.Validate_Lease(&created).ToAggregate(); err != nil {
+		return nil, err
+	}
+	if err := c.tracker.CreateLease(ctx, &created); err != nil {
+		return nil, statusError(err)
+	}
+	out := created
+	return &out, nil
+}
+
+// GetLease returns a copy of the named lease.
+func (c *Client) GetLease(namespace, name string) (*api.Lease, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "get", Resource: "leases", Namespace: namespace, Name: name}); handled {
+		out, _ := ret.(*api.Lease)
+		return out, err
+	}
+	lease, err := c.tracker.GetLease(ctx, namespace, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := *lease
+	return &out, nil
+}
+
+// ListLeases returns copies of the leases in namespace.
+func (c *Client) ListLeases(namespace string) ([]api.Lease, error) {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "leases", Namespace: namespace}); handled {
+		out, _ := ret.([]api.Lease)
+		return out, err
+	}
+	leases, err := c.tracker.ListLeases(ctx, namespace)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var result []api.Lease
+	for _, lease := range leases {
+		result = append(result, *lease)
+	}
+	return result, nil
+}
+
+// UpdateLease replaces a tracked lease and refreshes the argument with the stored
+// copy. Like the API server, it answers a stale resourceVersion with a 409 Conflict.
+func (c *Client) UpdateLease(lease *api.Lease) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "leases", Namespace: lease.Namespace, Name: lease.Name, Object: lease}); handled {
+		return err
+	}
+	updated := *lease
+	if err := c.tracker.UpdateLease(ctx, &updated); err != nil {
+		return statusError(err)
+	}
+	*lease = updated
+	return nil
+}
+
+// DeleteLease removes a tracked lease.
+func (c *Client) DeleteLease(namespace, name string) error {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if handled, _, err := c.invoke(Action{Verb: "delete", Resource: "leases", Namespace: namespace, Name: name}); handled {
+		return err
+	}
+	return statusError(c.tracker.DeleteLease(ctx, namespace, name))
+}
+
+// ReportComponentStatus records a heartbeat. Every reported component is Healthy;
+// the fake doesn't age heartbeats.
+func (c *Client) ReportComponentStatus(status *api.ComponentStatus) error {
+	if handled, _, err := c.invoke(Action{Verb: "update", Resource: "componentstatuses", Name: status.Name, Object: status}); handled {
+		return err
+	}
+	if status.Name == "" {
+		return fmt.Errorf("component name must be provided")
+	}
+	reported := *status
+	reported.LastHeartbeatTime = time.Now()
+	reported.Healthy = true
+	reported.Message = ""
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.components == nil {
+		c.components = make(map[string]api.ComponentStatus)
+	}
+	c.components[reported.Name] = reported
+	return nil
+}
+
+// ListComponentStatuses returns every reported component status, sorted by name.
+func (c *Client) ListComponentStatuses() ([]api.ComponentStatus, error) {
+	if handled, ret, err := c.invoke(Action{Verb: "list", Resource: "componentstatuses"}); handled {
+		out, _ := ret.([]api.ComponentStatus)
+		return out, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []api.ComponentStatus
+	for _, status := range c.components {
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
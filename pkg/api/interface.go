@@ -0,0 +1,115 @@
+package api
+
+// Interface is the set of operations offered by the API server client.
+// Components depend on Interface rather than *Client so that fakes,
+// instrumentation wrappers, and caching decorators can be substituted.
+// New resources get a typed client (see ResourceClient) instead of methods here.
+type Interface interface {
+	// GetBaseURL returns the base URL of the API server.
+	GetBaseURL() string
+
+	// Node operations
+	CreateNode(node *Node) (*Node, error)
+	GetNode(name string) (*Node, error)
+	UpdateNode(node *Node) error
+	ListNodes(status NodeStatus) ([]Node, error)
+	ListNodesMatching(labelSelector, fieldSelector string) ([]Node, error)
+	ListNodesWithOptions(opts ListOptions) ([]Node, error)
+	DeleteNode(name string) error
+	ApproveNode(name string) (*Node, error)
+
+	// Pod operations. ListPods and ListPodsWithOptions accept NamespaceAll. ListPods,
+	// ListPodsMatching, and DeleteCollection are shorthands for the WithOptions
+	// methods, as are ListNodes and ListNodesMatching.
+	CreatePod(namespace string, pod *Pod) (*Pod, error)
+	CreatePods(namespace string, pods []Pod) ([]PodBatchResult, error)
+	GetPod(namespace, name string) (*Pod, error)
+	UpdatePod(pod *Pod) error
+	DeletePod(namespace, name string) error
+	ListPods(namespace string, phase PodPhase) ([]Pod, error)
+	ListPodsMatching(namespace, labelSelector, fieldSelector string) ([]Pod, error)
+	ListPodsWithOptions(namespace string, opts ListOptions) ([]Pod, error)
+	DeleteCollection(namespace, labelSelector, fieldSelector string) ([]Pod, error)
+	DeleteCollectionWithOptions(namespace string, opts ListOptions) ([]Pod, error)
+	EvictPod(namespace, name string) error
+
+	// Namespace operations
+	CreateNamespace(ns *Namespace) (*Namespace, error)
+	GetNamespace(name string) (*Namespace, error)
+	ListNamespaces() ([]Namespace, error)
+	UpdateNamespace(ns *Namespace) error
+	DeleteNamespace(name string) error
+
+	// Deployment operations
+	CreateDeployment(namespace string, d *Deployment) (*Deployment, error)
+	GetDeployment(namespace, name string) (*Deployment, error)
+	ListDeployments(namespace string) ([]Deployment, error)
+	UpdateDeployment(d *Deployment) error
+	DeleteDeployment(namespace, name string) error
+
+	// Service operations
+	CreateService(namespace string, svc *Service) (*Service, error)
+	GetService(namespace, name string) (*Service, error)
+	ListServices(namespace string) ([]Service, error)
+	UpdateService(svc *Service) error
+	DeleteService(namespace, name string) error
+
+	// PodDisruptionBudget operations
+	CreatePodDisruptionBudget(namespace string, pdb *PodDisruptionBudget) (*PodDisruptionBudget, error)
+	GetPodDisruptionBudget(namespace, name string) (*PodDisruptionBudget, error)
+	ListPodDisruptionBudgets(namespace string) ([]PodDisruptionBudget, error)
+	DeletePodDisruptionBudget(namespace, name string) error
+
+	// NetworkPolicy operations
+	CreateNetworkPolicy(namespace string, policy *NetworkPolicy) (*NetworkPolicy, error)
+	GetNetworkPolicy(namespace, name string) (*NetworkPolicy, error)
+	ListNetworkPolicies(namespace string) ([]NetworkPolicy, error)
+	UpdateNetworkPolicy(policy *NetworkPolicy) error
+	DeleteNetworkPolicy(namespace, name string) error
+
+	// PersistentVolume operations
+	CreatePersistentVolume(pv *PersistentVolume) (*PersistentVolume, error)
+	GetPersistentVolume(name string) (*PersistentVolume, error)
+	ListPersistentVolumes() ([]PersistentVolume, error)
+	UpdatePersistentVolume(pv *PersistentVolume) error
+	DeletePersistentVolume(name string) error
+
+	// PersistentVolumeClaim operations. ListPersistentVolumeClaims accepts NamespaceAll.
+	CreatePersistentVolumeClaim(namespace string, pvc *PersistentVolumeClaim) (*PersistentVolumeClaim, error)
+	GetPersistentVolumeClaim(namespace, name string) (*PersistentVolumeClaim, error)
+	ListPersistentVolumeClaims(namespace string) ([]PersistentVolumeClaim, error)
+	UpdatePersistentVolumeClaim(pvc *PersistentVolumeClaim) error
+	DeletePersistentVolumeClaim(namespace, name string) error
+
+	// Event operations
+	CreateEvent(namespace string, event *Event) (*Event, error)
+	ListEvents(namespace string) ([]Event, error)
+
+	// Lease operations. UpdateLease fails with a conflict (see IsConflict) unless the
+	// lease carries its current resourceVersion.
+	CreateLease(namespace string, lease *Lease) (*Lease, error)
+	GetLease(namespace, name string) (*Lease, error)
+	ListLeases(namespace string) ([]Lease, error)
+	UpdateLease(lease *Lease) error
+	DeleteLease(namespace, name string) error
+
+	// ConfigMap operations
+	CreateConfigMap(namespace string, configMap *ConfigMap) (*ConfigMap, error)
+	GetConfigMap(namespace, name string) (*ConfigMap, error)
+	ListConfigMaps(namespace string) ([]ConfigMap, error)
+	UpdateConfigMap(configMap *ConfigMap) error
+	DeleteConfigMap(namespace, name string) error
+
+	// Secret operations
+	CreateSecret(namespace string, secret *Secret) (*Secret, error)
+	GetSecret(namespace, name string) (*Secret, error)
+	ListSecrets(namespace string) ([]Secret, error)
+	UpdateSecret(secret *Secret) error
+	DeleteSecret(namespace, name string) error
+
+	// ComponentStatus operations. ReportComponentStatus records a heartbeat.
+	ReportComponentStatus(status *ComponentStatus) error
+	ListComponentStatuses() ([]ComponentStatus, error)
+}
+
+var _ Interface = (*Client)(nil)
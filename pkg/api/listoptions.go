@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions narrows a list, a watch, or a collection delete. The zero value selects
+// everything.
+type ListOptions struct {
+	LabelSelector string // e.g. "app=web,tier!=cache"
+	FieldSelector string // e.g. "status.phase=Running"; see fields.PodFields and NodeFields
+
+	// Limit, if positive, makes a list return one page of at most Limit objects, in
+	// namespace/name order, starting after Continue, instead of the whole list.
+	// ResourceClient.ListPage also returns the token for the next page.
+	Limit    int
+	Continue string
+
+	// ResourceVersion starts a watch with the changes after it instead of with the
+	// current state. Lists always return the current state.
+	ResourceVersion string
+}
+
+// query returns the query string, if any, that passes on the options' selectors and
+// paging. The resource version is left to watches, which resume from their own.
+func (o ListOptions) query() string {
+	query := url.Values{}
+	if o.LabelSelector != "" {
+		query.Set("labelSelector", o.LabelSelector)
+	}
+	if o.FieldSelector != "" {
+		query.Set("fieldSelector", o.FieldSelector)
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Continue != "" {
+		query.Set("continue", o.Continue)
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// list decodes the objects at the collection urlStr that opts selects into out, and
+// returns the continue token for the next page if opts.Limit cut the list short.
+// Without a limit, it returns the whole list, from opts.Continue on, fetched a chunk
+// at a time if the client was made WithChunkSize.
+func (c *Client) list(urlStr string, opts ListOptions, out interface{}) (next string, err error) {
+	if opts.Limit > 0 {
+		return c.listPage(urlStr+opts.query(), out)
+	}
+	if c.chunkSize <= 0 && opts.Continue == "" {
+		return "", c.doJSON(http.MethodGet, urlStr+opts.query(), nil, out, http.StatusOK)
+	}
+	opts.Limit = c.chunkSize
+	var items []json.RawMessage
+	for {
+		var page []json.RawMessage
+		if opts.Continue, err = c.listPage(urlStr+opts.query(), &page); err != nil {
+			return "", err
+		}
+		items = append(items, page...)
+		if opts.Continue == "" {
+			break
+		}
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("joining list pages: %w", err)
+	}
+	return "", json.Unmarshal(raw, out)
+}
+
+// listPage decodes one page of a list into out and returns the server's continue token
+// for the next ("" after the last page).
+func (c *Client) listPage(urlStr string, out interface{}) (next string, err error) {
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	return resp.Header.Get(ContinueHeader), nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import "time"
+
+// GetNodeCondition returns the node's condition of type t, or nil if it has none.
+func GetNodeCondition(node *Node, t NodeConditionType) *NodeCondition {
+	for i := range node.Conditions {
+		if node.Conditions[i].Type == t {
+			return &node.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetNodeCondition adds or replaces the node's condition of cond.Type, as
+// SetPodCondition does, and reports whether its status changed. A missing heartbeat
+// time is set to now.
+func SetNodeCondition(node *Node, cond NodeCondition) bool {
+	now := time.Now().UTC()
+	if cond.LastHeartbeatTime.IsZero() {
+		cond.LastHeartbeatTime = now
+	}
+	existing := GetNodeCondition(node, cond.Type)
+	if existing == nil {
+		if cond.LastTransitionTime.IsZero() {
+			cond.LastTransitionTime = now
+		}
+		node.Conditions = append(node.Conditions, cond)
+		return true
+	}
+	changed := existing.Status != cond.Status
+	if !changed {
+		cond.LastTransitionTime = existing.LastTransitionTime
+	} else if cond.LastTransitionTime.IsZero() {
+		cond.LastTransitionTime = now
+	}
+	*existing = cond
+	return changed
+}
+
+// IsNodeReady reports whether the node's Ready condition is True. A node without
+// conditions, as older clients register, is Ready if its Status says so.
+func IsNodeReady(node *Node) bool {
+	if cond := GetNodeCondition(node, NodeReadyCondition); cond != nil {
+		return cond.Status == ConditionTrue
+	}
+	return node.Status == NodeReady
+}
+
+// DeriveNodeStatus sets the node's Status from its Ready condition. A node that has
+// no Ready condition, because the client that wrote it only knows Status, gets one
+// that agrees with Status (Ready if it is empty); one with an unknown Status is left
+// as it is, for validation to reject.
+func DeriveNodeStatus(node *Node) {
+	if GetNodeCondition(node, NodeReadyCondition) == nil {
+		cond := NodeCondition{Type: NodeReadyCondition}
+		switch node.Status {
+		case "", NodeReady:
+			cond.Status, cond.Reason = ConditionTrue, "NodeStatusReady"
+		case NodeNotReady:
+			cond.Status, cond.Reason = ConditionFalse, "NodeStatusNotReady"
+		default:
+			return
+		}
+		SetNodeCondition(node, cond)
+	}
+	node.Status = NodeNotReady
+	if IsNodeReady(node) {
+		node.Status = NodeReady
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import "testing"
+
+func TestDeriveNodeStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		node  Node
+		ready ConditionStatus // The Ready condition afterwards; "" for none
+		want  NodeStatus
+	}{
+		{"no status", Node{}, ConditionTrue, NodeReady},
+		{"old client NotReady", Node{Status: NodeNotReady}, ConditionFalse, NodeNotReady},
+		{"unknown status", Node{Status: "Sleeping"}, "", "Sleeping"},
+		{"condition wins", Node{Status: NodeReady, Conditions: []NodeCondition{{Type: NodeReadyCondition, Status: ConditionUnknown}}}, ConditionUnknown, NodeNotReady},
+		{"pressure alone", Node{Conditions: []NodeCondition{{Type: NodeMemoryPressure, Status: ConditionTrue}}}, ConditionTrue, NodeReady},
+	}
+	for _, tt := range tests {
+		DeriveNodeStatus(&tt.node)
+		var ready ConditionStatus
+		if cond := GetNodeCondition(&tt.node, NodeReadyCondition); cond != nil {
+			ready = cond.Status
+		}
+		if tt.node.Status != tt.want || ready != tt.ready {
+			t.Errorf("%s: got status %q and Ready %q, want %q and %q", tt.name, tt.node.Status, ready, tt.want, tt.ready)
+		}
+	}
+}
+
+func TestSetNodeCondition(t *testing.T) {
+	node := &Node{}
+	if !SetNodeCondition(node, NodeCondition{Type: NodeDiskPressure, Status: ConditionFalse}) {
+		t.Error("expected adding a condition to report a change")
+	}
+	first := *GetNodeCondition(node, NodeDiskPressure)
+	if SetNodeCondition(node, NodeCondition{Type: NodeDiskPressure, Status: ConditionFalse, Reason: "Again"}) {
+		t.Error("expected reporting the same status not to be a change")
+	}
+	if got := GetNodeCondition(node, NodeDiskPressure); got.LastTransitionTime != first.LastTransitionTime || got.Reason != "Again" {
+		t.Errorf("expected the reason updated and the transition time kept, got %+v", got)
+	}
+	if !SetNodeCondition(node, NodeCondition{Type: NodeDiskPressure, Status: ConditionTrue}) || len(node.Conditions) != 1 {
+		t.Errorf("expected a status change to replace the condition, got %+v", node.Conditions)
+	}
+}
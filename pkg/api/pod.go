@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/resource"
+)
+
+// IsPodTerminating reports whether pod has been deleted but its kubelet has not yet
+// reclaimed it.
+func IsPodTerminating(pod *Pod) bool {
+	return pod.DeletionTimestamp != nil && pod.Phase != PodDeleted
+}
+
+// IsPodGone reports whether a pod no longer counts as existing. Pods stay in the store
+// after deletion, so one is gone once the kubelet has reclaimed it, or once it is
+// terminating with nothing left to finish it: it was never scheduled, or its
+// containers already exited.
+func IsPodGone(pod *Pod) bool {
+	if pod.Phase == PodDeleted {
+		return true
+	}
+	if pod.DeletionTimestamp == nil {
+		return false
+	}
+	return pod.NodeName == "" || pod.Phase == PodSucceeded || pod.Phase == PodFailed
+}
+
+// GetPodCondition returns the pod's condition of type t, or nil if it has none.
+func GetPodCondition(pod *Pod, t PodConditionType) *PodCondition {
+	for i := range pod.Conditions {
+		if pod.Conditions[i].Type == t {
+			return &pod.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetPodCondition adds or replaces the pod's condition of cond.Type. The transition
+// time is only moved when the status changes, so it records when the condition last
+// flipped rather than when it was last reported.
+func SetPodCondition(pod *Pod, cond PodCondition) {
+	existing := GetPodCondition(pod, cond.Type)
+	if existing == nil {
+		if cond.LastTransitionTime.IsZero() {
+			cond.LastTransitionTime = time.Now().UTC()
+		}
+		pod.Conditions = append(pod.Conditions, cond)
+		return
+	}
+	if existing.Status == cond.Status {
+		cond.LastTransitionTime = existing.LastTransitionTime
+	} else if cond.LastTransitionTime.IsZero() {
+		cond.LastTransitionTime = time.Now().UTC()
+	}
+	*existing = cond
+}
+
+// MaxPodPhaseTransitions is how many phase transitions a pod keeps in its history.
+const MaxPodPhaseTransitions = 10
+
+// RecordPodPhase adds pod's phase to its PhaseTransitions as entered at now, unless it
+// is the phase last recorded or empty, dropping the oldest transitions beyond
+// MaxPodPhaseTransitions.
+func RecordPodPhase(pod *Pod, now time.Time) {
+	if n := len(pod.PhaseTransitions); pod.Phase == "" || n > 0 && pod.PhaseTransitions[n-1].Phase == pod.Phase {
+		return
+	}
+	// The full slice expression makes append copy, leaving the slice pod had untouched.
+	n := len(pod.PhaseTransitions)
+	transitions := append(pod.PhaseTransitions[:n:n], PodPhaseTransition{Phase: pod.Phase, Time: now.UTC()})
+	if len(transitions) > MaxPodPhaseTransitions {
+		transitions = transitions[len(transitions)-MaxPodPhaseTransitions:]
+	}
+	pod.PhaseTransitions = transitions
+}
+
+// ConvertDeprecatedPodPhase rewrites an update of existing from a client that still
+// uses the deprecated Deleting or Terminating phases. Termination is recorded by the
+// DeletionTimestamp that DELETE sets, so those phases carry no information beyond it:
+// they are replaced with the stored phase, leaving the phase unchanged.
+func ConvertDeprecatedPodPhase(pod, existing *Pod) {
+	if pod.Phase == PodDeleting || pod.Phase == PodTerminating {
+		pod.Phase = existing.Phase
+	}
+}
+
+// ParseResourceQuantity returns the value of a quantity of resource name: thousandths
+// of a CPU for CPU, and bytes for memory.
+func ParseResourceQuantity(name ResourceName, quantity string) (int64, error) {
+	if name == ResourceCPU {
+		return resource.ParseMilliQuantity(quantity)
+	}
+	return resource.ParseQuantity(quantity)
+}
+
+// FormatResourceQuantity formats v, a value of resource name as ParseResourceQuantity
+// returns it.
+func FormatResourceQuantity(name ResourceName, v int64) string {
+	if name == ResourceCPU {
+		return resource.FormatMilliQuantity(v)
+	}
+	return resource.FormatQuantity(v)
+}
+
+// PodRequests returns what pod requests of each resource, as ParseResourceQuantity
+// values, with its overhead. A limit without a request counts as the request.
+// Quantities that don't parse, which validation rejects, count as nothing.
+func PodRequests(pod *Pod) map[ResourceName]int64 {
+	requests := make(map[ResourceName]int64)
+	add := func(list ResourceList, only func(ResourceName) bool) {
+		for name, quantity := range list {
+			if !only(name) {
+				continue
+			}
+			if v, err := ParseResourceQuantity(name, quantity); err == nil {
+				requests[name] += v
+			}
+		}
+	}
+	all := func(ResourceName) bool { return true }
+	add(pod.Resources.Requests, all)
+	add(pod.Resources.Limits, func(name ResourceName) bool {
+		_, requested := pod.Resources.Requests[name]
+		return !requested
+	})
+	add(pod.Overhead, all)
+	return requests
+}
+
+// UnmarshalJSON takes quantities written as numbers, as in "cpu: 1" in a manifest, as
+// well as strings.
+func (l *ResourceList) UnmarshalJSON(data []byte) error {
+	var raw map[ResourceName]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*l = nil
+		return nil
+	}
+	*l = make(ResourceList, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			(*l)[name] = v
+		case json.Number:
+			(*l)[name] = v.String()
+		default:
+			return fmt.Errorf("quantity of %s must be a string or a number, got %v", name, v)
+		}
+	}
+	return nil
+}
+
+// GetPodQOS returns the QoS class pod's requests and limits put it in. A pod is
+// Guaranteed if it has CPU and memory limits and requests no different from them, a
+// missing request being taken to equal its limit; BestEffort if it has no requests or
+// limits; and Burstable otherwise.
+func GetPodQOS(pod *Pod) PodQOSClass {
+	requests, limits := pod.Resources.Requests, pod.Resources.Limits
+	if len(requests) == 0 && len(limits) == 0 {
+		return PodQOSBestEffort
+	}
+	for _, name := range []ResourceName{ResourceCPU, ResourceMemory} {
+		limit, ok := limits[name]
+		if !ok {
+			return PodQOSBurstable
+		}
+		if request, ok := requests[name]; ok && !equalQuantities(name, request, limit) {
+			return PodQOSBurstable
+		}
+	}
+	return PodQOSGuaranteed
+}
+
+func equalQuantities(name ResourceName, a, b string) bool {
+	x, errX := ParseResourceQuantity(name, a)
+	y, errY := ParseResourceQuantity(name, b)
+	return errX == nil && errY == nil && x == y
+}
+
+// IsLeaseExpired reports whether lease is free to be taken at now: it has no holder
+// or its holder hasn't renewed it within DurationSeconds.
+func IsLeaseExpired(lease *Lease, now time.Time) bool {
+	if lease.HolderIdentity == "" || lease.RenewTime == nil {
+		return true
+	}
+	return now.After(lease.RenewTime.Add(time.Duration(lease.DurationSeconds) * time.Second))
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResourceListUnmarshalNumbers(t *testing.T) {
+	var r ResourceRequirements
+	if err := json.Unmarshal([]byte(`{"requests": {"cpu": 1, "memory": "128Mi"}, "limits": {"cpu": 1.5}}`), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := ResourceRequirements{
+		Requests: ResourceList{ResourceCPU: "1", ResourceMemory: "128Mi"},
+		Limits:   ResourceList{ResourceCPU: "1.5"},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("got %+v, want %+v", r, want)
+	}
+	if err := json.Unmarshal([]byte(`{"requests": {"cpu": true}}`), &r); err == nil {
+		t.Errorf("expected an error for a quantity that is neither a string nor a number")
+	}
+}
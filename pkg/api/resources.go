@@ -0,0 +1,67 @@
+package api
+
+import "strings"
+
+// ResourceType describes a resource the API serves: where it lives, its kind, and
+// the names it goes by. The API server's discovery and kubectl-lite's argument
+// parsing both read it from Resources, so the short names one advertises are the
+// ones the other accepts.
+type ResourceType struct {
+	GroupVersion string // "v1" for the core group, otherwise "<group>/<version>"
+	Name         string // The plural used in URLs, e.g. "deployments"
+	SingularName string
+	Kind         string
+	Namespaced   bool
+	ShortNames   []string
+}
+
+// Resources are the resource types the API serves. A new resource needs an entry
+// here to be discovered and named on the command line.
+var Resources = []ResourceType{
+	{"v1", "pods", "pod", "Pod", true, []string{"po"}},
+	{"v1", "nodes", "node", "Node", false, []string{"no"}},
+	{"v1", "namespaces", "namespace", "Namespace", false, []string{"ns"}},
+	{"v1", "services", "service", "Service", true, []string{"svc"}},
+	{"v1", "events", "event", "Event", true, []string{"ev"}},
+	{"v1", "persistentvolumes", "persistentvolume", "PersistentVolume", false, []string{"pv"}},
+	{"v1", "persistentvolumeclaims", "persistentvolumeclaim", "PersistentVolumeClaim", true, []string{"pvc"}},
+	{"v1", "configmaps", "configmap", "ConfigMap", true, []string{"cm"}},
+	{"v1", "secrets", "secret", "Secret", true, nil},
+	{"v1", "componentstatuses", "componentstatus", "ComponentStatus", false, []string{"cs"}},
+	{"apps/v1", "deployments", "deployment", "Deployment", true, []string{"deploy"}},
+	{"policy/v1", "poddisruptionbudgets", "poddisruptionbudget", "PodDisruptionBudget", true, []string{"pdb"}},
+	{"coordination/v1", "leases", "lease", "Lease", true, nil},
+	{"networking/v1", "networkpolicies", "networkpolicy", "NetworkPolicy", true, []string{"netpol"}},
+	{"apiregistration/v1", "apiservices", "apiservice", "APIService", false, nil},
+}
+
+// Group returns the API group of the resource type, "" for the core group.
+func (r ResourceType) Group() string {
+	group, _, named := strings.Cut(r.GroupVersion, "/")
+	if !named {
+		return ""
+	}
+	return group
+}
+
+// LookupResource finds the resource type name refers to, case-insensitively: its
+// plural, singular, a short name, or its kind, optionally qualified with its group
+// as in "deployments.apps".
+func LookupResource(name string) (ResourceType, bool) {
+	name = strings.ToLower(name)
+	for _, r := range Resources {
+		n := name
+		if group := r.Group(); group != "" {
+			n = strings.TrimSuffix(n, "."+group)
+		}
+		if n == r.Name || n == r.SingularName || n == strings.ToLower(r.Kind) {
+			return r, true
+		}
+		for _, short := range r.ShortNames {
+			if n == short {
+				return r, true
+			}
+		}
+	}
+	return ResourceType{}, false
+}
@@ -0,0 +1,40 @@
+package api
+
+import "testing"
+
+func TestLookupResource(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"pods", "pods"}, {"pod", "pods"}, {"po", "pods"}, {"Pod", "pods"},
+		{"no", "nodes"}, {"ns", "namespaces"}, {"svc", "services"}, {"ev", "events"},
+		{"deploy", "deployments"}, {"deployments.apps", "deployments"}, {"DEPLOYMENT", "deployments"},
+		{"pdb", "poddisruptionbudgets"}, {"netpol", "networkpolicies"}, {"cs", "componentstatuses"},
+		{"pv", "persistentvolumes"}, {"pvc", "persistentvolumeclaims"}, {"lease", "leases"}, {"cm", "configmaps"}, {"secret", "secrets"}, {"apiservice", "apiservices"},
+	}
+	for _, tt := range tests {
+		r, ok := LookupResource(tt.name)
+		if !ok || r.Name != tt.want {
+			t.Errorf("LookupResource(%q) = %q, %t; want %q", tt.name, r.Name, ok, tt.want)
+		}
+	}
+	for _, name := range []string{"pods.apps", "deploy.policy", "widgets", ""} {
+		if r, ok := LookupResource(name); ok {
+			t.Errorf("LookupResource(%q) = %q, want no match", name, r.Name)
+		}
+	}
+}
+
+// TestResourceNamesAreUnique keeps every name in Resources pointing at one resource,
+// so adding a short name can't quietly shadow another resource's.
+func TestResourceNamesAreUnique(t *testing.T) {
+	seen := map[string]string{}
+	for _, r := range Resources {
+		for _, name := range append([]string{r.Name, r.SingularName}, r.ShortNames...) {
+			if other, ok := seen[name]; ok {
+				t.Errorf("%q names both %s and %s", name, other, r.Name)
+			}
+			seen[name] = r.Name
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Annotations that control how the kubelet's simulated runtime runs a pod's
+// container, so that demos and tests can make it start slowly, finish, or crash.
+const (
+	// AnnotationStartupDelay is how long the container takes to start, as a
+	// duration such as "5s". The pod stays Scheduled, with its Ready condition
+	// False for ContainerCreating, until it has passed.
+	AnnotationStartupDelay = "k8s-lite.io/startup-delay"
+	// AnnotationRunDuration is how long each run of the container lasts before it
+	// exits with code 0. Without it the container runs until the pod is deleted.
+	AnnotationRunDuration = "k8s-lite.io/run-duration"
+	// AnnotationCrashProbability is the chance, from 0 to 1, that a run of the
+	// container crashes with exit code 1 instead: at the end of its run duration
+	// if it has one, and otherwise at each sync of the pod while it runs.
+	AnnotationCrashProbability = "k8s-lite.io/crash-probability"
+)
+
+// SimulatedBehavior is how the simulated runtime runs a pod's container, as its
+// annotations set it.
+type SimulatedBehavior struct {
+	StartupDelay     time.Duration
+	RunDuration      time.Duration // Zero to run until deleted
+	CrashProbability float64
+}
+
+// SimulationAnnotations lists the annotations GetSimulatedBehavior reads.
+var SimulationAnnotations = []string{AnnotationStartupDelay, AnnotationRunDuration, AnnotationCrashProbability}
+
+// GetSimulatedBehavior returns how pod's annotations ask for its container to be run.
+// A pod without them starts at once and runs until deleted.
+func GetSimulatedBehavior(pod *Pod) (SimulatedBehavior, error) {
+	var b SimulatedBehavior
+	for _, key := range SimulationAnnotations {
+		if value, ok := pod.Annotations[key]; ok {
+			if err := b.set(key, value); err != nil {
+				return SimulatedBehavior{}, fmt.Errorf("annotation %s %v, got %q", key, err, value)
+			}
+		}
+	}
+	return b, nil
+}
+
+// ValidateSimulationAnnotation checks the value of one of SimulationAnnotations.
+func ValidateSimulationAnnotation(key, value string) error {
+	var b SimulatedBehavior
+	return b.set(key, value)
+}
+
+func (b *SimulatedBehavior) set(key, value string) error {
+	if key == AnnotationCrashProbability {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v < 0 || v > 1 {
+			return errors.New("must be a number from 0 to 1")
+		}
+		b.CrashProbability = v
+		return nil
+	}
+	v, err := time.ParseDuration(value)
+	if err != nil || v < 0 {
+		return errors.New("must be a non-negative duration such as 30s")
+	}
+	if key == AnnotationStartupDelay {
+		b.StartupDelay = v
+	} else {
+		b.RunDuration = v
+	}
+	return nil
+}
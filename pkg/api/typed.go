@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Object is an API object: anything with ObjectMeta, which every kind but
+// ComponentStatus embeds.
+type Object interface {
+	GetObjectMeta() *ObjectMeta
+}
+
+// ResourceClient is the typed client of one resource, scoped to a namespace if the
+// resource is namespaced, as in client.Pods("default").Get("web") or
+// client.Nodes().List(api.ListOptions{}). Every resource has one, all alike, so that a new
+// resource needs no new client methods beyond its accessor below.
+//
+// A namespaced client for NamespaceAll lists and watches across namespaces, where the
+// server allows it, and creates, gets, updates, patches, and deletes in "default".
+type ResourceClient[T any] struct {
+	client    *Client
+	resource  ResourceType
+	namespace string
+}
+
+// Typed clients, one per resource.
+
+func (c *Client) Pods(namespace string) *ResourceClient[Pod] {
+	return resourceClient[Pod](c, "pods", namespace)
+}
+
+func (c *Client) Nodes() *ResourceClient[Node] {
+	return resourceClient[Node](c, "nodes", "")
+}
+
+func (c *Client) Namespaces() *ResourceClient[Namespace] {
+	return resourceClient[Namespace](c, "namespaces", "")
+}
+
+func (c *Client) Services(namespace string) *ResourceClient[Service] {
+	return resourceClient[Service](c, "services", namespace)
+}
+
+func (c *Client) Events(namespace string) *ResourceClient[Event] {
+	return resourceClient[Event](c, "events", namespace)
+}
+
+func (c *Client) PersistentVolumes() *ResourceClient[PersistentVolume] {
+	return resourceClient[PersistentVolume](c, "persistentvolumes", "")
+}
+
+func (c *Client) PersistentVolumeClaims(namespace string) *ResourceClient[PersistentVolumeClaim] {
+	return resourceClient[PersistentVolumeClaim](c, "persistentvolumeclaims", namespace)
+}
+
+func (c *Client) ConfigMaps(namespace string) *ResourceClient[ConfigMap] {
+	return resourceClient[ConfigMap](c, "configmaps", namespace)
+}
+
+func (c *Client) Secrets(namespace string) *ResourceClient[Secret] {
+	return resourceClient[Secret](c, "secrets", namespace)
+}
+
+func (c *Client) Deployments(namespace string) *ResourceClient[Deployment] {
+	return resourceClient[Deployment](c, "deployments", namespace)
+}
+
+func (c *Client) PodDisruptionBudgets(namespace string) *ResourceClient[PodDisruptionBudget] {
+	return resourceClient[PodDisruptionBudget](c, "poddisruptionbudgets", namespace)
+}
+
+func (c *Client) Leases(namespace string) *ResourceClient[Lease] {
+	return resourceClient[Lease](c, "leases", namespace)
+}
+
+func (c *Client) NetworkPolicies(namespace string) *ResourceClient[NetworkPolicy] {
+	return resourceClient[NetworkPolicy](c, "networkpolicies", namespace)
+}
+
+func (c *Client) APIServices() *ResourceClient[APIService] {
+	return resourceClient[APIService](c, "apiservices", "")
+}
+
+func resourceClient[T any](c *Client, name, namespace string) *ResourceClient[T] {
+	resource, ok := LookupResource(name)
+	if !ok {
+		panic(fmt.Sprintf("api: no resource %q in Resources", name))
+	}
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	return &ResourceClient[T]{client: c, resource: resource, namespace: namespace}
+}
+
+// Create creates obj and returns it as stored.
+func (r *ResourceClient[T]) Create(obj *T) (*T, error) {
+	var created T
+	if err := r.client.doJSON(http.MethodPost, r.url(false, ""), obj, &created, http.StatusCreated, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", r.resource.SingularName, err)
+	}
+	return &created, nil
+}
+
+// Get fetches the object named name.
+func (r *ResourceClient[T]) Get(name string) (*T, error) {
+	var obj T
+	if err := r.client.doJSON(http.MethodGet, r.url(false, name), nil, &obj, http.StatusOK); err != nil {
+		// Wrapped with %w, so that callers can tell a 404 with IsNotFound.
+		return nil, fmt.Errorf("getting %s %s: %w", r.resource.SingularName, r.describe(name), err)
+	}
+	return &obj, nil
+}
+
+// List lists the objects that opts selects: all of them, following the server's
+// continue tokens if the client was made WithChunkSize, or one page if opts.Limit is
+// set.
+func (r *ResourceClient[T]) List(opts ListOptions) ([]T, error) {
+	items, _, err := r.ListPage(opts)
+	return items, err
+}
+
+// ListPage is List that also returns, when opts.Limit cuts the list short, the token
+// to pass as opts.Continue for the next page ("" after the last page):
+//
+//	opts := api.ListOptions{Limit: 100}
+//	for {
+//		pods, next, err := client.Pods("default").ListPage(opts)
+//		...
+//		if opts.Continue = next; next == "" {
+//			break
+//		}
+//	}
+func (r *ResourceClient[T]) ListPage(opts ListOptions) (items []T, next string, err error) {
+	if next, err = r.client.list(r.url(true, ""), opts, &items); err != nil {
+		return nil, "", fmt.Errorf("listing %s: %w", r.resource.Name, err)
+	}
+	return items, next, nil
+}
+
+// Update replaces the object obj names with obj and returns it as stored. It fails
+// with a conflict (see IsConflict) if obj carries a stale resourceVersion for a
+// resource that checks it.
+func (r *ResourceClient[T]) Update(obj *T) (*T, error) {
+	name := objectName(obj)
+	var updated T
+	if err := r.client.doJSON(http.MethodPut, r.url(false, name), obj, &updated, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("updating %s %s: %w", r.resource.SingularName, r.describe(name), err)
+	}
+	return &updated, nil
+}
+
+// Patch applies data, a patch of patchType, to the object named name and returns the
+// result.
+func (r *ResourceClient[T]) Patch(name string, patchType PatchType, data []byte) (*T, error) {
+	var patched T
+	if err := r.client.Patch(r.resource.Kind, r.namespace, name, patchType, data, &patched); err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
+
+// Delete deletes the object named name, honoring the client's propagation policy.
+func (r *ResourceClient[T]) Delete(name string) error {
+	if err := r.client.doJSON(http.MethodDelete, r.url(false, name), nil, nil, http.StatusOK, http.StatusAccepted, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting %s %s: %w", r.resource.SingularName, r.describe(name), err)
+	}
+	return nil
+}
+
+// Watch calls fn with every change to the objects that opts selects, decoded, as
+// Client.WatchWithOptions describes; bookmarks are left out.
+func (r *ResourceClient[T]) Watch(ctx context.Context, opts ListOptions, fn func(eventType WatchEventType, obj *T) error) error {
+	return r.client.WatchWithOptions(ctx, r.resource.Name, r.namespace, opts, func(event WatchEvent) error {
+		if event.Type == WatchBookmark {
+			return nil
+		}
+		var obj T
+		if err := json.Unmarshal(event.Object, &obj); err != nil {
+			return fmt.Errorf("decoding %s in watch event: %w", r.resource.SingularName, err)
+		}
+		return fn(event.Type, &obj)
+	})
+}
+
+// url returns the URL of the object named name, or of the collection if name is
+// empty. A collection in NamespaceAll is addressed across namespaces if all is true,
+// as lists are, and in "default" otherwise.
+func (r *ResourceClient[T]) url(all bool, name string) string {
+	segments := []string{"api", "v1"}
+	if r.resource.Group() != "" {
+		segments = append([]string{"apis"}, strings.Split(r.resource.GroupVersion, "/")...)
+	}
+	if r.resource.Namespaced && !(all && r.namespace == NamespaceAll) {
+		segments = append(segments, "namespaces", defaultedNamespace(r.namespace))
+	}
+	segments = append(segments, r.resource.Name)
+	if name != "" {
+		segments = append(segments, name)
+	}
+	return r.client.buildURL(segments...)
+}
+
+// describe names an object of the client's resource for error messages.
+func (r *ResourceClient[T]) describe(name string) string {
+	if !r.resource.Namespaced {
+		return name
+	}
+	return defaultedNamespace(r.namespace) + "/" + name
+}
+
+// objectName returns the name of obj, an API object.
+func objectName(obj any) string {
+	if o, ok := obj.(Object); ok {
+		return o.GetObjectMeta().Name
+	}
+	return ""
+}
@@ -2,7 +2,81 @@ package api
 
 import "time"
 
-// NodeStatus represents the status of a node.
+// ObjectMeta is the metadata every API object carries. It is embedded without a JSON
+// tag so its fields appear at the top level of each object, e.g. {"name": ..., "uid": ...}.
+// UID, CreationTimestamp, and ResourceVersion are set by the server.
+type ObjectMeta struct {
+	Name              string               `json:"name"`
+	Namespace         string               `json:"namespace,omitempty"`         // Empty for cluster-scoped objects such as nodes
+	UID               string               `json:"uid,omitempty"`               // Unique across the lifetime of the cluster, unlike the name
+	CreationTimestamp time.Time            `json:"creationTimestamp"`           // When the server stored the object
+	Labels            map[string]string    `json:"labels,omitempty"`            // Key/value pairs used by selectors
+	Annotations       map[string]string    `json:"annotations,omitempty"`       // Arbitrary non-identifying metadata
+	ResourceVersion   string               `json:"resourceVersion,omitempty"`   // Changes on every write to the object
+	OwnerReferences   []OwnerReference     `json:"ownerReferences,omitempty"`   // Objects this one depends on, e.g. a pod's deployment
+	DeletionTimestamp *time.Time           `json:"deletionTimestamp,omitempty"` // Set once deletion has begun; the object goes away when it completes
+	Finalizers        []string             `json:"finalizers,omitempty"`        // Work that must finish before a deleted object is removed
+	ManagedFields     []ManagedFieldsEntry `json:"managedFields,omitempty"`     // Which manager last set which fields; maintained by the server
+}
+
+// GetObjectMeta returns the metadata itself, giving every object that embeds
+// ObjectMeta a common accessor for it.
+func (m *ObjectMeta) GetObjectMeta() *ObjectMeta { return m }
+
+// ManagedFieldsOperation is the kind of write that gave a manager its fields.
+// +enum
+type ManagedFieldsOperation string
+
+const (
+	// ManagedFieldsOperationApply is a server-side apply: the manager declared the
+	// fields it wants, and may conflict with other managers over them.
+	ManagedFieldsOperationApply ManagedFieldsOperation = "Apply"
+	// ManagedFieldsOperationUpdate is any other write, such as a PUT: the manager
+	// takes over the fields it changed without conflicts.
+	ManagedFieldsOperationUpdate ManagedFieldsOperation = "Update"
+)
+
+// ManagedFieldsEntry records the fields one manager owns through one kind of
+// operation. Fields are paths such as "image" or "labels.app"; a key containing a dot
+// is quoted, as in labels."app.kubernetes.io/name".
+type ManagedFieldsEntry struct {
+	Manager   string                 `json:"manager"`
+	Operation ManagedFieldsOperation `json:"operation"`
+	Time      time.Time              `json:"time"`
+	Fields    []string               `json:"fields"`
+}
+
+// DeletionPropagation controls what happens to an object's dependents when it is
+// deleted. It is passed as the propagationPolicy query parameter of DELETE requests.
+// +enum
+type DeletionPropagation string
+
+const (
+	// DeletePropagationBackground deletes the object immediately and lets the garbage
+	// collector delete its dependents afterwards. This is the default.
+	DeletePropagationBackground DeletionPropagation = "Background"
+	// DeletePropagationForeground keeps the object, marked for deletion, until the
+	// garbage collector has deleted all of its dependents.
+	DeletePropagationForeground DeletionPropagation = "Foreground"
+	// DeletePropagationOrphan removes the object's owner references from its dependents
+	// and deletes only the object itself.
+	DeletePropagationOrphan DeletionPropagation = "Orphan"
+)
+
+// FinalizerForegroundDeletion holds an object being deleted in the foreground until its
+// dependents are gone.
+const FinalizerForegroundDeletion = "foregroundDeletion"
+
+// OwnerReference points at an object that owns this one. Owned objects are garbage
+// once every owner is gone.
+type OwnerReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+	Controller bool   `json:"controller,omitempty"` // True for the owner that manages this object
+}
+
+// NodeStatus summarizes a node's Ready condition.
 // +enum
 type NodeStatus string
 
@@ -11,11 +85,55 @@ const (
 	NodeNotReady NodeStatus = "NotReady"
 )
 
+// NodeConditionType names an aspect of a node's health that a condition reports on.
+// +enum
+type NodeConditionType string
+
+const (
+	NodeReadyCondition NodeConditionType = "Ready"          // The kubelet is healthy and ready to run pods
+	NodeMemoryPressure NodeConditionType = "MemoryPressure" // The node's available memory is below the eviction threshold
+	NodeDiskPressure   NodeConditionType = "DiskPressure"   // The node's disk space is low
+	NodePIDPressure    NodeConditionType = "PIDPressure"    // The node is running low on process IDs
+)
+
+// NodePressureConditions are the conditions a kubelet reports True when its node runs
+// low on a resource. A node under pressure may still be Ready.
+var NodePressureConditions = []NodeConditionType{NodeMemoryPressure, NodeDiskPressure, NodePIDPressure}
+
+// NodeCondition reports one aspect of a node's health, and why it is so.
+type NodeCondition struct {
+	Type               NodeConditionType `json:"type"`
+	Status             ConditionStatus   `json:"status"`
+	Reason             string            `json:"reason,omitempty"`  // Short CamelCase cause, e.g. "KubeletReady"
+	Message            string            `json:"message,omitempty"` // Human-readable details
+	LastHeartbeatTime  time.Time         `json:"lastHeartbeatTime"` // When the condition was last reported
+	LastTransitionTime time.Time         `json:"lastTransitionTime"`
+}
+
 // Node represents a worker machine in the cluster.
 type Node struct {
-	Name    string     `json:"name"`
-	Address string     `json:"address"` // e.g., "localhost:8081"
-	Status  NodeStatus `json:"status"`
+	ObjectMeta
+	Address string `json:"address"` // e.g., "localhost:8081"
+	// Status summarizes the Ready condition: Ready if it is True, NotReady otherwise.
+	// The API server derives it from Conditions, so it can't be set on its own once
+	// the node has a Ready condition; it is kept for clients and field selectors that
+	// only care whether the node is Ready.
+	Status NodeStatus `json:"status"`
+	// Conditions are the node's health as its kubelet, or the node lifecycle
+	// controller when the kubelet stops reporting, last saw it.
+	Conditions []NodeCondition `json:"conditions,omitempty"`
+	// Capacity is the node's CPU, memory, and pods, as its kubelet reports them.
+	// Allocatable is what of it the scheduler may give to pods' requests: the
+	// capacity less what the node keeps for itself. A resource missing from
+	// Allocatable isn't limited.
+	Capacity    ResourceList `json:"capacity,omitempty"`
+	Allocatable ResourceList `json:"allocatable,omitempty"`
+	// Unschedulable keeps the scheduler from placing new pods on the node (see cordon/drain).
+	Unschedulable bool `json:"unschedulable,omitempty"`
+	// PendingApproval is set by the API server on nodes that registered while it
+	// requires node approval, until they are approved. Such a node is kept NotReady
+	// and unschedulable.
+	PendingApproval bool `json:"pendingApproval,omitempty"`
 }
 
 // PodPhase represents the phase of a pod.
@@ -23,24 +141,613 @@ type Node struct {
 type PodPhase string
 
 const (
-	PodPending     PodPhase = "Pending"   // The pod has been accepted by the system, but one or more of the container images has not been created. This includes time before being scheduled as well as time spent downloading images over the network.
-	PodScheduled   PodPhase = "Scheduled" // The pod has been scheduled to a node, but is not yet running.
-	PodRunning     PodPhase = "Running"   // The pod has been bound to a node, and all of the containers have been created. At least one container is still running, or is in the process of starting or restarting.
-	PodDeleted     PodPhase = "Deleted"   // The pod's resources have been reclaimed by the Kubelet. This is a final state.
-	PodSucceeded   PodPhase = "Succeeded" // All containers in the pod have terminated in success, and will not be restarted.
-	PodFailed      PodPhase = "Failed"    // All containers in the pod have terminated, and at least one container has terminated in failure. The container either exited with non-zero status or was terminated by the system.
-	PodDeleting    PodPhase = "Deleting"  // The pod is marked for deletion.
+	PodPending   PodPhase = "Pending"   // The pod has been accepted by the system, but one or more of the container images has not been created. This includes time before being scheduled as well as time spent downloading images over the network.
+	PodScheduled PodPhase = "Scheduled" // The pod has been scheduled to a node, but is not yet running.
+	PodRunning   PodPhase = "Running"   // The pod has been bound to a node, and all of the containers have been created. At least one container is still running, or is in the process of starting or restarting.
+	PodDeleted   PodPhase = "Deleted"   // The pod's resources have been reclaimed by the Kubelet. This is a final state.
+	PodSucceeded PodPhase = "Succeeded" // All containers in the pod have terminated in success, and will not be restarted.
+	PodFailed    PodPhase = "Failed"    // All containers in the pod have terminated, and at least one container has terminated in failure. The container either exited with non-zero status or was terminated by the system.
+
+	// Deprecated: a pod being deleted is one with a DeletionTimestamp, whatever its
+	// phase. The API server converts updates that still send this phase.
+	PodDeleting PodPhase = "Deleting"
+	// Deprecated: see PodDeleting.
 	PodTerminating PodPhase = "Terminating"
 )
 
+// PodConditionType names an aspect of a pod's state that a condition reports on.
+// +enum
+type PodConditionType string
+
+const (
+	PodScheduledCondition PodConditionType = "PodScheduled" // The pod has been bound to a node
+	PodReadyCondition     PodConditionType = "Ready"        // The pod is running and not being deleted
+)
+
+// ConditionStatus is the status of a condition: True, False, or Unknown.
+// +enum
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// PodCondition reports one aspect of a pod's state, and why it is so.
+type PodCondition struct {
+	Type               PodConditionType `json:"type"`
+	Status             ConditionStatus  `json:"status"`
+	Reason             string           `json:"reason,omitempty"`  // Short CamelCase cause, e.g. "Terminating"
+	Message            string           `json:"message,omitempty"` // Human-readable details
+	LastTransitionTime time.Time        `json:"lastTransitionTime"`
+}
+
+// NamespaceAll is passed to pod list calls to select pods in every namespace.
+const NamespaceAll = ""
+
 // Pod represents the smallest deployable units of computing that you can create and manage.
 type Pod struct {
-	Name              string     `json:"name"`
-	Namespace         string     `json:"namespace"`
-	Image             string     `json:"image"`                       // Image name (e.g., "nginx:latest")
-	NodeName          string     `json:"nodeName,omitempty"`          // Name of the node the pod is assigned to, omitempty because it's not set initially
-	Phase             PodPhase   `json:"phase"`                       // Current phase of the pod
-	HostIP            string     `json:"hostIP,omitempty"`            // IP address of the host to which the pod is assigned
-	PodIP             string     `json:"podIP,omitempty"`             // IP address of the pod
-	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty"` // Added for soft delete
+	ObjectMeta
+	Image           string     `json:"image"`                     // Image name (e.g., "nginx:latest")
+	ImagePullPolicy PullPolicy `json:"imagePullPolicy,omitempty"` // When the kubelet pulls Image
+	NodeName        string     `json:"nodeName,omitempty"`        // Name of the node the pod is assigned to, omitempty because it's not set initially
+	Phase           PodPhase   `json:"phase"`                     // Current phase of the pod
+	HostIP          string     `json:"hostIP,omitempty"`          // IP address of the host to which the pod is assigned
+	PodIP           string     `json:"podIP,omitempty"`           // IP address of the pod
+
+	// ImagePullSecrets name the SecretTypeDockerConfigJSON secrets, in the pod's
+	// namespace, whose credentials the kubelet pulls Image with.
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// The container's process: Command replaces the image's entrypoint and Args its
+	// arguments. The process starts in WorkingDir, "/" by default, with a terminal if
+	// TTY is set.
+	Command    []string `json:"command,omitempty"`
+	Args       []string `json:"args,omitempty"`
+	WorkingDir string   `json:"workingDir,omitempty"`
+	TTY        bool     `json:"tty,omitempty"`
+	// SecurityContext, if set, is the user and privileges the container runs with.
+	SecurityContext *SecurityContext `json:"securityContext,omitempty"`
+	// RestartPolicy says whether the kubelet runs the container again after it exits;
+	// RestartCount is how many times it has.
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+	RestartCount  int           `json:"restartCount,omitempty"`
+
+	Volumes      []Volume      `json:"volumes,omitempty"`      // Storage the kubelet prepares for the pod
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty"` // Where the pod's container sees its volumes
+
+	Resources ResourceRequirements `json:"resources"`          // CPU and memory the pod's container needs and may use
+	Overhead  ResourceList         `json:"overhead,omitempty"` // Resources the pod uses beyond its container's, such as its sandbox
+	QOSClass  PodQOSClass          `json:"qosClass,omitempty"` // Set by the server from Resources
+
+	Conditions []PodCondition `json:"conditions,omitempty"`
+	// PhaseTransitions is the history of the pod's phase, oldest first, as the API
+	// server recorded each change. Only the last MaxPodPhaseTransitions are kept.
+	PhaseTransitions []PodPhaseTransition `json:"phaseTransitions,omitempty"`
+}
+
+// PodPhaseTransition records when a pod entered a phase.
+type PodPhaseTransition struct {
+	Phase PodPhase  `json:"phase"`
+	Time  time.Time `json:"time"`
+}
+
+// SecurityContext holds the security settings of a pod's container.
+type SecurityContext struct {
+	// RunAsUser is the UID the container's process runs as. Unset, it runs as the
+	// image's user, which is root for every simulated image.
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// Privileged gives the container the privileges of root on its node.
+	Privileged bool `json:"privileged,omitempty"`
+	// ReadOnlyRootFilesystem keeps the container from writing anywhere but its volumes.
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+}
+
+// PodBatchResult is the outcome of creating one pod of a batch. Code is the status
+// creating the pod on its own would have been answered with: 201 with the created Pod,
+// or an error code and message.
+type PodBatchResult struct {
+	Code  int    `json:"code"`
+	Pod   *Pod   `json:"pod,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ExecResult is the outcome of running a command in a pod, as reported by the kubelet
+// running the pod.
+type ExecResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// PodLogOptions selects the part of a pod's output to fetch. The zero value fetches
+// everything the kubelet keeps of its container's current run.
+type PodLogOptions struct {
+	// TailLines, if positive, keeps only the last TailLines lines.
+	TailLines int
+	// SinceSeconds, if positive, keeps only the lines written in the last SinceSeconds
+	// seconds.
+	SinceSeconds int64
+	// Previous fetches the output of the container's run before it last restarted.
+	Previous bool
+	// Container, if set, names the container whose output to fetch. A pod has one
+	// container, named after the pod, as kubectl run names it.
+	Container string
+}
+
+// RestartPolicy says when the kubelet restarts a pod's container after it exits. A pod
+// whose container isn't restarted ends Succeeded if it exited with code 0, and Failed
+// otherwise.
+// +enum
+type RestartPolicy string
+
+const (
+	RestartPolicyAlways    RestartPolicy = "Always"    // Restart whatever the exit code
+	RestartPolicyOnFailure RestartPolicy = "OnFailure" // Restart only after a non-zero exit code
+	RestartPolicyNever     RestartPolicy = "Never"     // Never restart
+)
+
+// PullPolicy says when the kubelet pulls a pod's image.
+// +enum
+type PullPolicy string
+
+const (
+	PullAlways       PullPolicy = "Always"       // Pull every time the pod starts
+	PullIfNotPresent PullPolicy = "IfNotPresent" // Pull only if the node doesn't have the image
+	PullNever        PullPolicy = "Never"        // Never pull; the image must already be on the node
+)
+
+// PodReasonUnschedulable is the reason the scheduler gives on a pod's PodScheduled
+// condition while no node can run the pod; the message says why.
+const PodReasonUnschedulable = "Unschedulable"
+
+// PodReasonEvicted is the reason the kubelet gives on the Ready condition of a pod it
+// failed to reclaim memory for its node.
+const PodReasonEvicted = "Evicted"
+
+// Reasons the kubelet gives on a pod's Ready condition while it can't get the pod's image.
+const (
+	PodReasonErrImagePull      = "ErrImagePull"      // The last pull failed
+	PodReasonImagePullBackOff  = "ImagePullBackOff"  // Waiting to retry a failed pull
+	PodReasonErrImageNeverPull = "ErrImageNeverPull" // Policy Never and the image isn't on the node
+	PodReasonInvalidImageName  = "InvalidImageName"  // The image reference can't be parsed
+)
+
+// Reasons the kubelet gives on a pod's Ready condition while its container waits to run.
+const (
+	PodReasonContainerCreating = "ContainerCreating" // Starting, for the pod's startup delay
+	PodReasonCrashLoopBackOff  = "CrashLoopBackOff"  // Waiting to restart a container that exited
+)
+
+// ResourceName names a resource a pod requests.
+// +enum
+type ResourceName string
+
+const (
+	ResourceCPU    ResourceName = "cpu"    // In CPUs, e.g. "2" or "250m"
+	ResourceMemory ResourceName = "memory" // In bytes, e.g. "128Mi"
+	ResourcePods   ResourceName = "pods"   // How many pods a node runs; only in node capacity
+)
+
+// ResourceList maps resources to quantities, as resource.ParseQuantity reads them for
+// memory and resource.ParseMilliQuantity for CPU.
+type ResourceList map[ResourceName]string
+
+// ResourceRequirements are what a pod's container needs and may use. A request is
+// what the pod is guaranteed; a limit is the most it may use. A limit without a
+// request also sets the request.
+type ResourceRequirements struct {
+	Requests ResourceList `json:"requests,omitempty"`
+	Limits   ResourceList `json:"limits,omitempty"`
+}
+
+// PodQOSClass is the quality of service a pod gets, from its requests and limits. When
+// a node runs short of memory, the kubelet evicts BestEffort pods first and Guaranteed
+// pods last.
+// +enum
+type PodQOSClass string
+
+const (
+	PodQOSGuaranteed PodQOSClass = "Guaranteed" // Limits for CPU and memory, and requests equal to them
+	PodQOSBurstable  PodQOSClass = "Burstable"  // Some request or limit, but not Guaranteed
+	PodQOSBestEffort PodQOSClass = "BestEffort" // No requests or limits
+)
+
+// Volume is a named piece of storage available to a pod. Exactly one source is set.
+type Volume struct {
+	Name string `json:"name"`
+	VolumeSource
+}
+
+// VolumeSource says where a volume's storage comes from.
+type VolumeSource struct {
+	EmptyDir              *EmptyDirVolumeSource              `json:"emptyDir,omitempty"`
+	HostPath              *HostPathVolumeSource              `json:"hostPath,omitempty"`
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+}
+
+// EmptyDirVolumeSource is a scratch directory created empty for the pod on its node
+// and removed when the pod is deleted.
+type EmptyDirVolumeSource struct{}
+
+// HostPathType says what a hostPath volume must find at its path.
+// +enum
+type HostPathType string
+
+const (
+	HostPathUnset             HostPathType = ""                  // No check
+	HostPathDirectoryOrCreate HostPathType = "DirectoryOrCreate" // Created if missing
+	HostPathDirectory         HostPathType = "Directory"
+	HostPathFile              HostPathType = "File"
+)
+
+// HostPathVolumeSource exposes a file or directory on the pod's node. It is left in
+// place when the pod is deleted.
+type HostPathVolumeSource struct {
+	Path string       `json:"path"`
+	Type HostPathType `json:"type,omitempty"`
+}
+
+// PersistentVolumeClaimVolumeSource uses the PersistentVolume bound to a claim in the
+// pod's namespace. The kubelet won't start the pod until the claim is Bound.
+type PersistentVolumeClaimVolumeSource struct {
+	ClaimName string `json:"claimName"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+}
+
+// VolumeMount mounts one of the pod's volumes at MountPath.
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+}
+
+// NamespacePhase represents the lifecycle phase of a namespace.
+// +enum
+type NamespacePhase string
+
+const (
+	NamespaceActive      NamespacePhase = "Active"
+	NamespaceTerminating NamespacePhase = "Terminating"
+)
+
+// Namespace groups namespaced objects such as pods, deployments, and services.
+type Namespace struct {
+	ObjectMeta
+	Phase NamespacePhase `json:"phase,omitempty"`
+}
+
+// PodTemplate describes the pods a controller creates.
+type PodTemplate struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"` // Copied to each pod; changing them rolls the pods
+	Image       string            `json:"image"`
+}
+
+// PodTemplateHashLabel is the label the deployment controller puts on each pod it
+// creates, set to a hash of the deployment's pod template at the time. Pods whose hash
+// differs from the current template's are replaced during a rolling update.
+const PodTemplateHashLabel = "pod-template-hash"
+
+// Deployment declares a desired number of replicas of a pod template.
+type Deployment struct {
+	ObjectMeta
+	Replicas int               `json:"replicas"`
+	Selector map[string]string `json:"selector"` // Labels identifying the pods this deployment manages
+	Template PodTemplate       `json:"template"`
+}
+
+// ServiceType determines how a service is exposed.
+// +enum
+type ServiceType string
+
+const (
+	ServiceTypeClusterIP ServiceType = "ClusterIP"
+	ServiceTypeNodePort  ServiceType = "NodePort"
+)
+
+// ServicePort maps a port on the service to a port on the selected pods.
+type ServicePort struct {
+	Name       string `json:"name,omitempty"`
+	Protocol   string `json:"protocol"` // "TCP" or "UDP"
+	Port       int    `json:"port"`
+	TargetPort int    `json:"targetPort"`
+}
+
+// Service exposes the pods matching Selector behind a stable set of ports.
+type Service struct {
+	ObjectMeta
+	Type     ServiceType       `json:"type"`
+	Selector map[string]string `json:"selector,omitempty"`
+	Ports    []ServicePort     `json:"ports"`
+}
+
+// EventType distinguishes routine events from ones that may need attention.
+// +enum
+type EventType string
+
+const (
+	EventTypeNormal  EventType = "Normal"
+	EventTypeWarning EventType = "Warning"
+)
+
+// ObjectReference identifies another object, such as the one an event is about.
+type ObjectReference struct {
+	Kind      string `json:"kind"` // e.g. "Pod", "Node"
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"` // Set when a recreated object of the same name must not match
+}
+
+// EventSource names the component, and for node agents the host, that reported an event.
+type EventSource struct {
+	Component string `json:"component"`
+	Host      string `json:"host,omitempty"`
+}
+
+// Event records something that happened to an object, such as a pod being scheduled.
+// Repeats of the same event are folded into one object by bumping Count.
+type Event struct {
+	ObjectMeta
+	InvolvedObject ObjectReference `json:"involvedObject"`
+	Type           EventType       `json:"type"`
+	Reason         string          `json:"reason"` // Short CamelCase cause, e.g. "Scheduled"
+	Message        string          `json:"message"`
+	Source         EventSource     `json:"source"`
+	FirstTimestamp time.Time       `json:"firstTimestamp"`
+	LastTimestamp  time.Time       `json:"lastTimestamp"`
+	Count          int             `json:"count"`
+}
+
+// PodDisruptionBudget limits how many of the pods matching Selector may be voluntarily
+// disrupted (evicted) at once. Exactly one of MinAvailable and MaxUnavailable is set.
+type PodDisruptionBudget struct {
+	ObjectMeta
+	Selector       map[string]string `json:"selector"`
+	MinAvailable   *int              `json:"minAvailable,omitempty"`   // Matching pods that must stay Running
+	MaxUnavailable *int              `json:"maxUnavailable,omitempty"` // Matching pods that may be down at once
+
+	Status PodDisruptionBudgetStatus `json:"status"`
+}
+
+// PodDisruptionBudgetStatus is computed by the API server from the current pods.
+type PodDisruptionBudgetStatus struct {
+	ExpectedPods       int `json:"expectedPods"`       // Matching pods that are not being deleted
+	CurrentHealthy     int `json:"currentHealthy"`     // Matching pods that are Running
+	DesiredHealthy     int `json:"desiredHealthy"`     // Minimum healthy pods the budget allows
+	DisruptionsAllowed int `json:"disruptionsAllowed"` // Pods that may be evicted right now
+}
+
+// NetworkPolicy restricts the traffic the pods matching PodSelector, in the policy's
+// namespace, accept. A pod no policy selects accepts traffic from anywhere; a pod that
+// some policy selects accepts only the traffic one of the policies selecting it allows.
+type NetworkPolicy struct {
+	ObjectMeta
+	PodSelector map[string]string          `json:"podSelector"` // Empty selects every pod in the namespace
+	Ingress     []NetworkPolicyIngressRule `json:"ingress,omitempty"`
+}
+
+// NetworkPolicyIngressRule allows traffic from any of From to any of Ports. Empty From
+// allows traffic from anywhere, and empty Ports to any port.
+type NetworkPolicyIngressRule struct {
+	From  []NetworkPolicyPeer `json:"from,omitempty"`
+	Ports []int               `json:"ports,omitempty"`
+}
+
+// NetworkPolicyPeer selects the pods traffic is allowed from. At least one selector is
+// set; an empty one selects everything, while a nil one is unset. PodSelector alone
+// selects pods in the policy's namespace, NamespaceSelector alone every pod in the
+// matching namespaces, and both the matching pods in the matching namespaces.
+type NetworkPolicyPeer struct {
+	PodSelector       map[string]string `json:"podSelector"`
+	NamespaceSelector map[string]string `json:"namespaceSelector"`
+}
+
+// PersistentVolumeAccessMode says how many nodes may mount a volume, and how.
+// +enum
+type PersistentVolumeAccessMode string
+
+const (
+	ReadWriteOnce PersistentVolumeAccessMode = "ReadWriteOnce" // Read-write by a single node
+	ReadOnlyMany  PersistentVolumeAccessMode = "ReadOnlyMany"  // Read-only by many nodes
+	ReadWriteMany PersistentVolumeAccessMode = "ReadWriteMany" // Read-write by many nodes
+)
+
+// PersistentVolumeReclaimPolicy says what happens to a volume once its claim is deleted.
+// +enum
+type PersistentVolumeReclaimPolicy string
+
+const (
+	// PersistentVolumeReclaimRetain keeps the volume, Released, until an administrator
+	// clears its claimRef to make it Available again.
+	PersistentVolumeReclaimRetain PersistentVolumeReclaimPolicy = "Retain"
+	// PersistentVolumeReclaimDelete deletes the volume object. The data on the host is
+	// left alone.
+	PersistentVolumeReclaimDelete PersistentVolumeReclaimPolicy = "Delete"
+)
+
+// PersistentVolumePhase is where a volume is in its lifecycle.
+// +enum
+type PersistentVolumePhase string
+
+const (
+	VolumeAvailable PersistentVolumePhase = "Available" // Not bound to a claim
+	VolumeBound     PersistentVolumePhase = "Bound"     // Bound to ClaimRef
+	VolumeReleased  PersistentVolumePhase = "Released"  // Its claim was deleted; not yet reclaimed
+)
+
+// PersistentVolume is a piece of storage provisioned by an administrator, backed by a
+// directory on the nodes. Pods use it through a PersistentVolumeClaim bound to it.
+type PersistentVolume struct {
+	ObjectMeta
+	Capacity      string                        `json:"capacity"` // e.g. "10Gi"
+	AccessModes   []PersistentVolumeAccessMode  `json:"accessModes"`
+	ReclaimPolicy PersistentVolumeReclaimPolicy `json:"persistentVolumeReclaimPolicy,omitempty"`
+	HostPath      *HostPathVolumeSource         `json:"hostPath"`
+
+	// ClaimRef is the claim bound to this volume. Setting it before the claim exists
+	// reserves the volume for that claim.
+	ClaimRef *ObjectReference      `json:"claimRef,omitempty"`
+	Phase    PersistentVolumePhase `json:"phase,omitempty"`
+}
+
+// PersistentVolumeClaimPhase is where a claim is in its lifecycle.
+// +enum
+type PersistentVolumeClaimPhase string
+
+const (
+	ClaimPending PersistentVolumeClaimPhase = "Pending" // Waiting for a matching volume
+	ClaimBound   PersistentVolumeClaimPhase = "Bound"   // Bound to VolumeName
+	ClaimLost    PersistentVolumeClaimPhase = "Lost"    // Its bound volume was deleted
+)
+
+// PersistentVolumeClaim asks for storage of at least Storage bytes with all of
+// AccessModes. The volume binder binds it to a matching PersistentVolume.
+type PersistentVolumeClaim struct {
+	ObjectMeta
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes"`
+	Storage     string                       `json:"storage"` // Requested size, e.g. "1Gi"
+
+	// VolumeName is the bound volume. Setting it at creation asks for that volume.
+	VolumeName string                     `json:"volumeName,omitempty"`
+	Phase      PersistentVolumeClaimPhase `json:"phase,omitempty"`
+	Capacity   string                     `json:"capacity,omitempty"` // Of the bound volume
+}
+
+// ComponentStatus is the liveness of a control plane component or kubelet. Components
+// report themselves every HeartbeatPeriodSeconds; the API server stamps each report
+// and judges the component Healthy until it misses a few of them.
+type ComponentStatus struct {
+	Name                   string    `json:"name"`                   // e.g. "scheduler" or "kubelet-node1"
+	HeartbeatPeriodSeconds int       `json:"heartbeatPeriodSeconds"` // How often the component reports
+	LastHeartbeatTime      time.Time `json:"lastHeartbeatTime"`      // Set by the API server
+	Healthy                bool      `json:"healthy"`                // Computed by the API server
+	Message                string    `json:"message,omitempty"`      // Why the component is unhealthy
+}
+
+// ConfigMap holds configuration data, as string values by key, for pods and
+// components to read.
+type ConfigMap struct {
+	ObjectMeta
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// SecretType says what a secret holds, and so which keys its data must have.
+// +enum
+type SecretType string
+
+const (
+	SecretTypeOpaque SecretType = "Opaque" // Arbitrary data; the default
+	// SecretTypeDockerConfigJSON holds credentials for pulling images from registries:
+	// a DockerConfigJSON, as JSON, under DockerConfigJSONKey.
+	SecretTypeDockerConfigJSON SecretType = "kubernetes.io/dockerconfigjson"
+)
+
+// DockerConfigJSONKey is the key of a SecretTypeDockerConfigJSON secret's credentials.
+const DockerConfigJSONKey = ".dockerconfigjson"
+
+// Secret holds sensitive data, such as passwords and registry credentials, by key.
+// Its values are base64-encoded in JSON.
+type Secret struct {
+	ObjectMeta
+	Type SecretType        `json:"type,omitempty"`
+	Data map[string][]byte `json:"data,omitempty"`
+}
+
+// DockerConfigJSON is the content of a ~/.docker/config.json file, the format
+// SecretTypeDockerConfigJSON secrets keep registry credentials in.
+type DockerConfigJSON struct {
+	Auths map[string]DockerConfigEntry `json:"auths"` // By registry host, such as "registry.local:5000"
+}
+
+// DockerConfigEntry is the credentials for one registry: Username and Password, or
+// Auth, the base64 encoding of "username:password".
+type DockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// LocalObjectReference names an object in the namespace of the object referring to it.
+type LocalObjectReference struct {
+	Name string `json:"name"`
+}
+
+// NodeLeaseNamespace holds the Lease each kubelet renews as its node's heartbeat.
+const NodeLeaseNamespace = "kube-node-lease"
+
+// SystemNamespace holds the objects of the cluster's own components.
+const SystemNamespace = "kube-system"
+
+// PublicNamespace holds what every client of the cluster may read, such as the
+// ClusterInfoConfigMap.
+const PublicNamespace = "kube-public"
+
+// ClusterInfoConfigMap is the config map in PublicNamespace describing the cluster.
+// Its "server" key is the address the API server serves on.
+const ClusterInfoConfigMap = "cluster-info"
+
+// Lease is a lock held by one identity at a time until it expires
+// DurationSeconds after RenewTime. Kubelets renew one per node as a heartbeat, and
+// replicated controllers take turns leading through one. Updates must carry the
+// resourceVersion they read, so two identities can't both take the same lease.
+type Lease struct {
+	ObjectMeta
+	HolderIdentity  string     `json:"holderIdentity,omitempty"` // Empty when released
+	DurationSeconds int        `json:"durationSeconds"`
+	RenewTime       *time.Time `json:"renewTime,omitempty"`
+}
+
+// APIService registers an aggregated API server: an external server that serves an
+// API group version of its own, such as metrics.lite/v1. The API server lists the
+// group in discovery and proxies every request under /apis/<group>/<version> to it,
+// so clients reach it like any built-in group. Its name is "<version>.<group>".
+type APIService struct {
+	ObjectMeta
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	// URL is where the aggregated server listens, such as http://localhost:8443. A
+	// request for /apis/<group>/<version>/nodes goes to that path under it.
+	URL string `json:"url"`
+}
+
+// APIVersions lists the versions of the core API group, served at /api.
+type APIVersions struct {
+	Versions []string `json:"versions"` // e.g. "v1"
+}
+
+// APIGroupList lists the named API groups, served at /apis.
+type APIGroupList struct {
+	Groups []APIGroup `json:"groups"`
+}
+
+// APIGroup is a named API group, such as apps, and the versions it is served at.
+type APIGroup struct {
+	Name             string                     `json:"name"`
+	Versions         []GroupVersionForDiscovery `json:"versions"`
+	PreferredVersion GroupVersionForDiscovery   `json:"preferredVersion"`
+}
+
+// GroupVersionForDiscovery is one version of an API group.
+type GroupVersionForDiscovery struct {
+	GroupVersion string `json:"groupVersion"` // e.g. "apps/v1"
+	Version      string `json:"version"`      // e.g. "v1"
+}
+
+// APIResourceList lists the resources of one group version, served at /api/v1 for the
+// core group and /apis/<group>/<version> for the others.
+type APIResourceList struct {
+	GroupVersion string        `json:"groupVersion"`
+	Resources    []APIResource `json:"resources"`
+}
+
+// APIResource describes a resource the API server serves.
+type APIResource struct {
+	Name         string   `json:"name"`         // The plural used in URLs, e.g. "deployments"
+	SingularName string   `json:"singularName"` // e.g. "deployment"
+	Namespaced   bool     `json:"namespaced"`
+	Kind         string   `json:"kind"`
+	Verbs        []string `json:"verbs"` // e.g. "get", "list", "watch", sorted
+	ShortNames   []string `json:"shortNames,omitempty"`
 }
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/backoff"
+)
+
+// WatchEventType is the type of a WatchEvent.
+type WatchEventType string
+
+const (
+	WatchAdded    WatchEventType = "ADDED"
+	WatchModified WatchEventType = "MODIFIED"
+	WatchDeleted  WatchEventType = "DELETED"
+	// WatchBookmark carries only a resourceVersion: the watch has sent every change up
+	// to it. The server sends one after the initial state and then periodically, so a
+	// watch of objects that rarely change can still resume from a recent version.
+	WatchBookmark WatchEventType = "BOOKMARK"
+	// WatchError ends a watch; its object is {"code": ..., "error": "..."}.
+	WatchError WatchEventType = "ERROR"
+)
+
+// WatchEvent is one line of a watch response. Object is the added or modified object,
+// or a deleted one as it was, with the resourceVersion of its deletion.
+type WatchEvent struct {
+	Type   WatchEventType  `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// ResourceVersion returns the resourceVersion of the event's object.
+func (e WatchEvent) ResourceVersion() string {
+	var meta ObjectMeta
+	json.Unmarshal(e.Object, &meta)
+	return meta.ResourceVersion
+}
+
+// ErrWatchExpired is returned by Watch when the server no longer has the changes since
+// the resource version to resume from. The watcher has to start again without one,
+// from the current state.
+var ErrWatchExpired = errors.New("watch expired: the resource version is too old")
+
+// Watcher is implemented by clients that can stream changes, such as *Client.
+type Watcher interface {
+	Watch(ctx context.Context, resource, namespace, resourceVersion string, fn func(WatchEvent) error) error
+	WatchMatching(ctx context.Context, resource, namespace, resourceVersion, labelSelector, fieldSelector string, fn func(WatchEvent) error) error
+}
+
+// Watch waits watchRetryInterval before reconnecting a broken watch, doubling the wait
+// with each failed reconnect up to maxWatchRetryInterval.
+const (
+	watchRetryInterval    = time.Second
+	maxWatchRetryInterval = 30 * time.Second
+)
+
+// watchPaths holds the path of each resource Watch supports, before and after the
+// namespace; namespaced resources that can be watched across namespaces have allPath.
+var watchPaths = map[string]struct {
+	prefix, allPath []string
+	namespaced      bool
+}{
+	"pods":                   {[]string{"api", "v1"}, []string{"api", "v1", "pods"}, true},
+	"nodes":                  {[]string{"api", "v1"}, nil, false},
+	"namespaces":             {[]string{"api", "v1"}, nil, false},
+	"services":               {[]string{"api", "v1"}, nil, true},
+	"events":                 {[]string{"api", "v1"}, nil, true},
+	"persistentvolumes":      {[]string{"api", "v1"}, nil, false},
+	"persistentvolumeclaims": {[]string{"api", "v1"}, []string{"api", "v1", "persistentvolumeclaims"}, true},
+	"configmaps":             {[]string{"api", "v1"}, nil, true},
+	"secrets":                {[]string{"api", "v1"}, nil, true},
+	"deployments":            {[]string{"apis", "apps", "v1"}, []string{"apis", "apps", "v1", "deployments"}, true},
+	"poddisruptionbudgets":   {[]string{"apis", "policy", "v1"}, nil, true},
+	"networkpolicies":        {[]string{"apis", "networking", "v1"}, nil, true},
+	"leases":                 {[]string{"apis", "coordination", "v1"}, nil, true},
+	"apiservices":            {[]string{"apis", "apiregistration", "v1"}, nil, false},
+}
+
+// Watch calls fn with every change to the objects of resource, such as "pods", in
+// namespace, until ctx is cancelled, fn returns an error, or the watch fails; it returns
+// that error. A namespace of NamespaceAll watches pods, persistent volume claims, and
+// deployments in every namespace; it is ignored for cluster-scoped resources.
+//
+// With an empty resourceVersion, the watch starts with an ADDED event for every object
+// that exists, then a BOOKMARK; otherwise it starts with the changes after
+// resourceVersion. Watch reconnects when the connection breaks, resuming from the last
+// resource version it saw, so fn misses nothing and sees nothing twice. Only if the
+// connection breaks before the first BOOKMARK, with no resource version to resume from,
+// does Watch return the error. If the server no longer has the changes to resume from,
+// it returns ErrWatchExpired.
+func (c *Client) Watch(ctx context.Context, resource, namespace, resourceVersion string, fn func(WatchEvent) error) error {
+	return c.WatchMatching(ctx, resource, namespace, resourceVersion, "", "", fn)
+}
+
+// WatchMatching is Watch narrowed to the objects that match labelSelector and
+// fieldSelector (either may be empty), as filtered by the server. An object that comes
+// to match arrives as ADDED, and one that stops matching as DELETED.
+func (c *Client) WatchMatching(ctx context.Context, resource, namespace, resourceVersion, labelSelector, fieldSelector string, fn func(WatchEvent) error) error {
+	return c.WatchWithOptions(ctx, resource, namespace, ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector, ResourceVersion: resourceVersion}, fn)
+}
+
+// WatchWithOptions is Watch narrowed to the objects that opts selects, starting after
+// opts.ResourceVersion if set. Paging options are ignored.
+func (c *Client) WatchWithOptions(ctx context.Context, resource, namespace string, opts ListOptions, fn func(WatchEvent) error) error {
+	paths, ok := watchPaths[resource]
+	if !ok {
+		return fmt.Errorf("watching %s: unknown resource", resource)
+	}
+	var segments []string
+	switch {
+	case !paths.namespaced:
+		segments = append(append(segments, paths.prefix...), resource)
+	case namespace == NamespaceAll && paths.allPath != nil:
+		segments = paths.allPath
+	case namespace == NamespaceAll:
+		return fmt.Errorf("watching %s: cannot watch across namespaces", resource)
+	default:
+		segments = append(append(segments, paths.prefix...), "namespaces", namespace, resource)
+	}
+	urlStr := c.buildURL(segments...) + ListOptions{LabelSelector: opts.LabelSelector, FieldSelector: opts.FieldSelector}.query()
+	resourceVersion := opts.ResourceVersion
+
+	// The stream outlives the client's request timeout, and is never cached.
+	stream := *c
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	stream.httpClient = &httpClient
+	stream.cache = nil
+
+	retry := backoff.New(watchRetryInterval, maxWatchRetryInterval)
+	for {
+		synced, err := stream.watchOnce(ctx, urlStr, &resourceVersion, fn)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var connErr *watchConnectionError
+		if !errors.As(err, &connErr) || (!synced && resourceVersion == "") {
+			return err
+		}
+		if synced {
+			retry.Reset()
+		}
+		delay := retry.Next()
+		log.Printf("Watch of %s broke, resuming from resource version %s in %v: %v", resource, resourceVersion, delay.Round(time.Millisecond), err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// watchConnectionError is a failure of a watch's connection, which Watch recovers from
+// by reconnecting.
+type watchConnectionError struct{ err error }
+
+func (e *watchConnectionError) Error() string { return e.err.Error() }
+func (e *watchConnectionError) Unwrap() error { return e.err }
+
+// watchOnce makes one watch request from *resourceVersion and passes its events to fn,
+// advancing *resourceVersion as it goes. Until the connection's first BOOKMARK the
+// events are the initial state, in no particular order, so it only advances from there;
+// synced reports whether it got that far.
+func (c *Client) watchOnce(ctx context.Context, urlStr string, resourceVersion *string, fn func(WatchEvent) error) (synced bool, err error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return false, err
+	}
+	q := u.Query()
+	q.Set("watch", "true")
+	if *resourceVersion != "" {
+		q.Set("resourceVersion", *resourceVersion)
+	}
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return false, &watchConnectionError{err}
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		return false, fmt.Errorf("%w: %v", ErrWatchExpired, statusError(resp))
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return false, &watchConnectionError{statusError(resp)}
+	case resp.StatusCode != http.StatusOK:
+		return false, statusError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var event WatchEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return synced, &watchConnectionError{fmt.Errorf("decoding watch event: %w", err)}
+		}
+		if event.Type == WatchError {
+			var status struct {
+				Code  int    `json:"code"`
+				Error string `json:"error"`
+			}
+			json.Unmarshal(event.Object, &status)
+			if status.Code == http.StatusGone {
+				return synced, fmt.Errorf("%w: %s", ErrWatchExpired, status.Error)
+			}
+			return synced, &StatusError{Code: status.Code, Message: status.Error}
+		}
+		if err := fn(event); err != nil {
+			return synced, err
+		}
+		if event.Type == WatchBookmark {
+			synced = true
+		}
+		if synced {
+			*resourceVersion = event.ResourceVersion()
+		}
+	}
+	err = scanner.Err()
+	if err == nil {
+		err = errors.New("watch closed by the server")
+	}
+	return synced, &watchConnectionError{err}
+}
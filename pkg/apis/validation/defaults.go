@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// The SetDefaults_* functions fill in fields a client may leave empty. The API server
+// runs them before the matching Validate_* function, so validation sees the object as
+// it will be stored.
+
+// SetDefaults_Pod starts a pod without a phase in Pending, and gives a pod without an
+// image pull policy Always for a :latest (or untagged) image and IfNotPresent
+// otherwise. Its container is restarted Always unless it says otherwise. A limit
+// without a request sets the request too. The QoS class is always worked out afresh,
+// so whatever a client sends for it is ignored.
+func SetDefaults_Pod(pod *api.Pod) {
+	if pod.Phase == "" {
+		pod.Phase = api.PodPending
+	}
+	if pod.ImagePullPolicy == "" {
+		pod.ImagePullPolicy = api.PullIfNotPresent
+		if imageTag(pod.Image) == "latest" {
+			pod.ImagePullPolicy = api.PullAlways
+		}
+	}
+	if pod.RestartPolicy == "" {
+		pod.RestartPolicy = api.RestartPolicyAlways
+	}
+	for name, limit := range pod.Resources.Limits {
+		if _, ok := pod.Resources.Requests[name]; !ok {
+			if pod.Resources.Requests == nil {
+				pod.Resources.Requests = make(api.ResourceList)
+			}
+			pod.Resources.Requests[name] = limit
+		}
+	}
+	pod.QOSClass = api.GetPodQOS(pod)
+}
+
+// imageTag returns the tag of an image reference: "latest" if it has neither a tag nor
+// a digest, and "" if it is pinned by digest.
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return "latest"
+}
+
+// SetDefaults_Node derives the node's Status from its Ready condition, which a node
+// without one gets from its Status, Ready if it has none (see api.DeriveNodeStatus).
+func SetDefaults_Node(node *api.Node) {
+	api.DeriveNodeStatus(node)
+}
+
+// SetDefaults_Namespace marks a namespace without a phase Active.
+func SetDefaults_Namespace(ns *api.Namespace) {
+	if ns.Phase == "" {
+		ns.Phase = api.NamespaceActive
+	}
+}
+
+// SetDefaults_Deployment labels the pod template app=<name> if it has no labels, and
+// selects the template's labels if the deployment has no selector.
+func SetDefaults_Deployment(d *api.Deployment) {
+	if len(d.Template.Labels) == 0 {
+		d.Template.Labels = map[string]string{"app": d.Name}
+	}
+	if len(d.Selector) == 0 {
+		d.Selector = make(map[string]string, len(d.Template.Labels))
+		for k, v := range d.Template.Labels {
+			d.Selector[k] = v
+		}
+	}
+}
+
+// SetDefaults_Service defaults the service type to ClusterIP, port protocols to TCP,
+// and target ports to the service port.
+func SetDefaults_Service(svc *api.Service) {
+	if svc.Type == "" {
+		svc.Type = api.ServiceTypeClusterIP
+	}
+	for i := range svc.Ports {
+		p := &svc.Ports[i]
+		if p.Protocol == "" {
+			p.Protocol = "TCP"
+		}
+		if p.TargetPort == 0 {
+			p.TargetPort = p.Port
+		}
+	}
+}
+
+// SetDefaults_PersistentVolume defaults the reclaim policy to Retain and starts the
+// volume Available.
+func SetDefaults_PersistentVolume(pv *api.PersistentVolume) {
+	if pv.ReclaimPolicy == "" {
+		pv.ReclaimPolicy = api.PersistentVolumeReclaimRetain
+	}
+	if pv.Phase == "" {
+		pv.Phase = api.VolumeAvailable
+	}
+}
+
+// SetDefaults_PersistentVolumeClaim starts a claim without a phase Pending.
+func SetDefaults_PersistentVolumeClaim(pvc *api.PersistentVolumeClaim) {
+	if pvc.Phase == "" {
+		pvc.Phase = api.ClaimPending
+	}
+}
+
+// SetDefaults_Event defaults the event type to Normal.
+func SetDefaults_Event(event *api.Event) {
+	if event.Type == "" {
+		event.Type = api.EventTypeNormal
+	}
+}
+
+// DefaultLeaseDurationSeconds is how long a lease created without a duration lasts.
+const DefaultLeaseDurationSeconds = 40
+
+// SetDefaults_Lease gives a lease without a duration DefaultLeaseDurationSeconds.
+func SetDefaults_Lease(lease *api.Lease) {
+	if lease.DurationSeconds == 0 {
+		lease.DurationSeconds = DefaultLeaseDurationSeconds
+	}
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorType classifies a field error.
+type ErrorType string
+
+const (
+	ErrorTypeRequired     ErrorType = "FieldValueRequired"     // A required field is empty
+	ErrorTypeInvalid      ErrorType = "FieldValueInvalid"      // The value is malformed or out of range
+	ErrorTypeNotSupported ErrorType = "FieldValueNotSupported" // The value is not one of an enumerated set
+	ErrorTypeForbidden    ErrorType = "FieldValueForbidden"    // The field may not be set or changed this way
+	ErrorTypeNotFound     ErrorType = "FieldValueNotFound"     // The value refers to something that doesn't exist
+	ErrorTypeTooLong      ErrorType = "FieldValueTooLong"      // The value is larger than allowed
+)
+
+// Error is a problem with a single field. Field is the JSON path of the field, such as
+// "template.image" or "ports[0].port".
+type Error struct {
+	Type     ErrorType   `json:"reason"`
+	Field    string      `json:"field"`
+	BadValue interface{} `json:"-"`
+	Detail   string      `json:"message"`
+}
+
+// Error renders the error the way it appears in API responses, for example
+// `name: Invalid value: "Web": must be lowercase`.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Field)
+	b.WriteString(": ")
+	switch e.Type {
+	case ErrorTypeRequired:
+		b.WriteString("Required value")
+	case ErrorTypeInvalid:
+		fmt.Fprintf(&b, "Invalid value: %#v", e.BadValue)
+	case ErrorTypeNotSupported:
+		fmt.Fprintf(&b, "Unsupported value: %#v", e.BadValue)
+	case ErrorTypeForbidden:
+		b.WriteString("Forbidden")
+	case ErrorTypeNotFound:
+		fmt.Fprintf(&b, "Not found: %#v", e.BadValue)
+	case ErrorTypeTooLong:
+		b.WriteString("Too long")
+	}
+	if e.Detail != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Detail)
+	}
+	return b.String()
+}
+
+// Required returns an error for a required field that is empty.
+func Required(field, detail string) *Error {
+	return &Error{Type: ErrorTypeRequired, Field: field, Detail: detail}
+}
+
+// Invalid returns an error for a field whose value is malformed or out of range.
+func Invalid(field string, value interface{}, detail string) *Error {
+	return &Error{Type: ErrorTypeInvalid, Field: field, BadValue: value, Detail: detail}
+}
+
+// NotSupported returns an error for a value outside the enumerated valid values.
+func NotSupported(field string, value interface{}, valid []string) *Error {
+	quoted := make([]string, len(valid))
+	for i, v := range valid {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return &Error{Type: ErrorTypeNotSupported, Field: field, BadValue: value, Detail: "supported values: " + strings.Join(quoted, ", ")}
+}
+
+// Forbidden returns an error for a field that may not be set or changed.
+func Forbidden(field, detail string) *Error {
+	return &Error{Type: ErrorTypeForbidden, Field: field, Detail: detail}
+}
+
+// NotFound returns an error for a value that refers to something that doesn't exist.
+func NotFound(field string, value interface{}) *Error {
+	return &Error{Type: ErrorTypeNotFound, Field: field, BadValue: value}
+}
+
+// TooLong returns an error for a field whose value is larger than maxBytes.
+func TooLong(field string, maxBytes int) *Error {
+	return &Error{Type: ErrorTypeTooLong, Field: field, Detail: fmt.Sprintf("must have at most %d bytes", maxBytes)}
+}
+
+// ErrorList collects every problem found in an object, so a client can fix them all
+// in one go.
+type ErrorList []*Error
+
+// Error joins the errors in the list, bracketed when there is more than one.
+func (list ErrorList) Error() string {
+	if len(list) == 1 {
+		return list[0].Error()
+	}
+	msgs := make([]string, len(list))
+	for i, err := range list {
+		msgs[i] = err.Error()
+	}
+	return "[" + strings.Join(msgs, ", ") + "]"
+}
+
+// ToAggregate returns the list as an error, or nil if it is empty.
+func (list ErrorList) ToAggregate() error {
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
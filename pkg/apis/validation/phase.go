@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// podPhaseTransitions is the pod phase state machine: the phases an update may move a
+// pod to from each phase. Staying in the same phase is always allowed.
+//
+// A pod normally goes Pending, Scheduled, Running, then Succeeded or Failed, and is
+// Deleted once its kubelet reclaims it. A Scheduled pod goes back to Pending when its
+// node disappears before it starts. Deleted is final.
+var podPhaseTransitions = map[api.PodPhase][]api.PodPhase{
+	api.PodPending:   {api.PodScheduled, api.PodFailed},
+	api.PodScheduled: {api.PodRunning, api.PodPending, api.PodFailed},
+	api.PodRunning:   {api.PodSucceeded, api.PodFailed},
+	api.PodSucceeded: {api.PodDeleted},
+	api.PodFailed:    {api.PodDeleted},
+	api.PodDeleted:   nil,
+}
+
+// terminatingPodPhaseTransitions replaces podPhaseTransitions once a pod has a deletion
+// timestamp: a terminating pod may only finish, and may be Deleted from any phase.
+var terminatingPodPhaseTransitions = map[api.PodPhase][]api.PodPhase{
+	api.PodPending:   {api.PodFailed, api.PodDeleted},
+	api.PodScheduled: {api.PodFailed, api.PodDeleted},
+	api.PodRunning:   {api.PodSucceeded, api.PodFailed, api.PodDeleted},
+	api.PodSucceeded: {api.PodDeleted},
+	api.PodFailed:    {api.PodDeleted},
+	api.PodDeleted:   nil,
+}
+
+// ValidatePodPhaseTransition returns an error explaining why a pod may not move from
+// one phase to another, or nil if it may. terminating reports whether the pod has
+// been marked for deletion.
+func ValidatePodPhaseTransition(from, to api.PodPhase, terminating bool) error {
+	if from == to {
+		return nil
+	}
+	transitions := podPhaseTransitions
+	if terminating {
+		transitions = terminatingPodPhaseTransitions
+	}
+	next, known := transitions[from]
+	if !known {
+		// Pods stored before their phase was validated may have any phase; let them
+		// move on rather than wedging them.
+		return nil
+	}
+	for _, p := range next {
+		if p == to {
+			return nil
+		}
+	}
+	if len(next) == 0 {
+		return fmt.Errorf("illegal phase transition from %s to %s: %s is final", from, to, from)
+	}
+	names := make([]string, len(next))
+	for i, p := range next {
+		names[i] = string(p)
+	}
+	allowed := names[len(names)-1]
+	if len(names) > 1 {
+		allowed = strings.Join(names[:len(names)-1], ", ") + " or " + allowed
+	}
+	state := from
+	if terminating {
+		state = "terminating " + from
+	}
+	return fmt.Errorf("illegal phase transition from %s to %s: a %s pod may only move to %s", from, to, state, allowed)
+}
@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+var resourceNames = []string{string(api.ResourceCPU), string(api.ResourceMemory)}
+
+// nodeResourceNames are what a node's capacity and allocatable may name.
+var nodeResourceNames = []string{string(api.ResourceCPU), string(api.ResourceMemory), string(api.ResourcePods)}
+
+// ValidateResources checks a pod's requests, limits, and overhead: each names CPU or
+// memory with a valid quantity, and no request is more than its limit.
+func ValidateResources(resources api.ResourceRequirements, overhead api.ResourceList) ErrorList {
+	errs := validateResourceList("resources.requests", resources.Requests)
+	errs = append(errs, validateResourceList("resources.limits", resources.Limits)...)
+	errs = append(errs, validateResourceList("overhead", overhead)...)
+	for _, name := range sortedResourceNames(resources.Requests) {
+		limit, ok := resources.Limits[name]
+		if !ok {
+			continue
+		}
+		request, errR := api.ParseResourceQuantity(name, resources.Requests[name])
+		maximum, errL := api.ParseResourceQuantity(name, limit)
+		if errR == nil && errL == nil && request > maximum {
+			errs = append(errs, Invalid("resources.requests."+string(name), resources.Requests[name], "must be less than or equal to the "+string(name)+" limit"))
+		}
+	}
+	return errs
+}
+
+func validateResourceList(field string, list api.ResourceList) ErrorList {
+	return validateResourceListOf(field, list, resourceNames)
+}
+
+// validateResourceListOf checks that list names only resources in names, each with a
+// valid quantity.
+func validateResourceListOf(field string, list api.ResourceList, names []string) ErrorList {
+	var errs ErrorList
+	for _, name := range sortedResourceNames(list) {
+		if !oneOf(string(name), names) {
+			errs = append(errs, NotSupported(field, string(name), names))
+			continue
+		}
+		if _, err := api.ParseResourceQuantity(name, list[name]); err != nil {
+			errs = append(errs, Invalid(field+"."+string(name), list[name], err.Error()))
+		}
+	}
+	return errs
+}
+
+func sortedResourceNames(list api.ResourceList) []api.ResourceName {
+	names := make([]api.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// validatePodResourcesUnchanged forbids changing a pod's resources or overhead after it
+// has been created: its QoS class, and the kubelet's accounting of the node's memory,
+// are worked out from them.
+func validatePodResourcesUnchanged(pod, old *api.Pod) ErrorList {
+	var errs ErrorList
+	if !reflect.DeepEqual(pod.Resources, old.Resources) {
+		errs = append(errs, Forbidden("resources", "may not be changed after the pod is created"))
+	}
+	if !reflect.DeepEqual(pod.Overhead, old.Overhead) {
+		errs = append(errs, Forbidden("overhead", "may not be changed after the pod is created"))
+	}
+	return errs
+}
@@ -0,0 +1,674 @@
+// Package validation defaults and validates API objects before the API server stores
+// them. Each resource has a SetDefaults_<Kind> function that fills in optional fields
+// and a Validate_<Kind> function that returns every problem it finds as a field-level
+// ErrorList:
+//
+//	validation.SetDefaults_Deployment(d)
+//	if errs := validation.Validate_Deployment(d); len(errs) > 0 {
+//		// reject with 422 Unprocessable Entity, listing errs as causes
+//	}
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/podsecurity"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/resource"
+)
+
+const (
+	dns1123LabelMaxLength     = 63
+	dns1123SubdomainMaxLength = 253
+	qualifiedNameMaxLength    = 63
+	labelValueMaxLength       = 63
+)
+
+var (
+	dns1123LabelRegexp     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	dns1123SubdomainRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	qualifiedNameRegexp    = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+)
+
+// IsDNS1123Label checks that value can be used as a DNS label, e.g. a namespace name:
+// at most 63 lowercase alphanumeric characters or '-', starting and ending with an
+// alphanumeric character. It returns a description of each problem found.
+func IsDNS1123Label(value string) []string {
+	var msgs []string
+	if len(value) > dns1123LabelMaxLength {
+		msgs = append(msgs, fmt.Sprintf("must be no more than %d characters", dns1123LabelMaxLength))
+	}
+	if !dns1123LabelRegexp.MatchString(value) {
+		msgs = append(msgs, "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character")
+	}
+	return msgs
+}
+
+// IsDNS1123Subdomain checks that value can be used as a DNS subdomain, the format of
+// most object names: dot-separated DNS labels, at most 253 characters in total.
+func IsDNS1123Subdomain(value string) []string {
+	var msgs []string
+	if len(value) > dns1123SubdomainMaxLength {
+		msgs = append(msgs, fmt.Sprintf("must be no more than %d characters", dns1123SubdomainMaxLength))
+	}
+	if !dns1123SubdomainRegexp.MatchString(value) {
+		msgs = append(msgs, "a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character")
+	}
+	return msgs
+}
+
+// IsQualifiedName checks that value can be used as a label or annotation key: an
+// optional DNS subdomain prefix and '/', followed by a name of at most 63 alphanumeric
+// characters, '-', '_' or '.', starting and ending with an alphanumeric character.
+func IsQualifiedName(value string) []string {
+	var msgs []string
+	name := value
+	if i := strings.IndexByte(value, '/'); i >= 0 {
+		prefix := value[:i]
+		name = value[i+1:]
+		if prefix == "" {
+			msgs = append(msgs, "prefix part must be non-empty")
+		} else {
+			for _, msg := range IsDNS1123Subdomain(prefix) {
+				msgs = append(msgs, "prefix part "+msg)
+			}
+		}
+	}
+	switch {
+	case name == "":
+		msgs = append(msgs, "name part must be non-empty")
+	case len(name) > qualifiedNameMaxLength:
+		msgs = append(msgs, fmt.Sprintf("name part must be no more than %d characters", qualifiedNameMaxLength))
+	}
+	if name != "" && !qualifiedNameRegexp.MatchString(name) {
+		msgs = append(msgs, "name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character")
+	}
+	return msgs
+}
+
+// IsValidLabelValue checks that value can be used as a label value: empty, or at most
+// 63 alphanumeric characters, '-', '_' or '.', starting and ending with an
+// alphanumeric character.
+func IsValidLabelValue(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var msgs []string
+	if len(value) > labelValueMaxLength {
+		msgs = append(msgs, fmt.Sprintf("must be no more than %d characters", labelValueMaxLength))
+	}
+	if !qualifiedNameRegexp.MatchString(value) {
+		msgs = append(msgs, "a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character")
+	}
+	return msgs
+}
+
+// validateName reports a missing name or one that fails isValid.
+func validateName(field, name string, isValid func(string) []string) ErrorList {
+	if name == "" {
+		return ErrorList{Required(field, "")}
+	}
+	var errs ErrorList
+	for _, msg := range isValid(name) {
+		errs = append(errs, Invalid(field, name, msg))
+	}
+	return errs
+}
+
+// ValidateLabels checks the keys and values of a label map found at field.
+func ValidateLabels(field string, labels map[string]string) ErrorList {
+	var errs ErrorList
+	for _, k := range sortedKeys(labels) {
+		v := labels[k]
+		for _, msg := range IsQualifiedName(k) {
+			errs = append(errs, Invalid(field, k, msg))
+		}
+		for _, msg := range IsValidLabelValue(v) {
+			errs = append(errs, Invalid(fmt.Sprintf("%s[%s]", field, k), v, msg))
+		}
+	}
+	return errs
+}
+
+// TotalAnnotationSizeLimit is the most the keys and values of an object's annotations
+// may add up to, in bytes.
+const TotalAnnotationSizeLimit = 256 * 1024
+
+// ValidateAnnotations checks the keys of an annotation map found at field, and that
+// the keys and values together fit in TotalAnnotationSizeLimit. Unlike label values,
+// annotation values may hold anything.
+func ValidateAnnotations(field string, annotations map[string]string) ErrorList {
+	var errs ErrorList
+	size := 0
+	for _, k := range sortedKeys(annotations) {
+		for _, msg := range IsQualifiedName(strings.ToLower(k)) {
+			errs = append(errs, Invalid(field, k, msg))
+		}
+		size += len(k) + len(annotations[k])
+	}
+	if size > TotalAnnotationSizeLimit {
+		errs = append(errs, TooLong(field, TotalAnnotationSizeLimit))
+	}
+	return errs
+}
+
+// ValidateObjectMeta checks the metadata every object shares. The name must satisfy
+// isValidName; namespaced objects must also have a valid namespace.
+func ValidateObjectMeta(meta *api.ObjectMeta, namespaced bool, isValidName func(string) []string) ErrorList {
+	errs := validateName("name", meta.Name, isValidName)
+	if namespaced {
+		if meta.Namespace == "" {
+			errs = append(errs, Required("namespace", ""))
+		} else {
+			for _, msg := range IsDNS1123Label(meta.Namespace) {
+				errs = append(errs, Invalid("namespace", meta.Namespace, msg))
+			}
+		}
+	}
+	errs = append(errs, ValidateLabels("labels", meta.Labels)...)
+	errs = append(errs, ValidateAnnotations("annotations", meta.Annotations)...)
+	for i, ref := range meta.OwnerReferences {
+		field := fmt.Sprintf("ownerReferences[%d]", i)
+		if ref.Kind == "" {
+			errs = append(errs, Required(field+".kind", ""))
+		}
+		if ref.Name == "" {
+			errs = append(errs, Required(field+".name", ""))
+		}
+		if ref.UID == "" {
+			errs = append(errs, Required(field+".uid", ""))
+		}
+	}
+	return errs
+}
+
+// sortedKeys returns the keys of m in order, so errors are reported in a stable order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var podPhases = []string{
+	string(api.PodPending), string(api.PodScheduled), string(api.PodRunning),
+	string(api.PodSucceeded), string(api.PodFailed), string(api.PodDeleted),
+}
+
+// pullPolicies lists the valid image pull policies. An empty policy, which the API
+// server defaults, is accepted so that pods stored before the field existed validate.
+var pullPolicies = []string{"", string(api.PullAlways), string(api.PullIfNotPresent), string(api.PullNever)}
+
+// restartPolicies lists the valid restart policies, with the empty one for pods stored
+// before the field existed, as pullPolicies does.
+var restartPolicies = []string{"", string(api.RestartPolicyAlways), string(api.RestartPolicyOnFailure), string(api.RestartPolicyNever)}
+
+func oneOf(value string, valid []string) bool {
+	for _, v := range valid {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate_Pod checks a pod being created or updated.
+func Validate_Pod(pod *api.Pod) ErrorList {
+	errs := ValidateObjectMeta(&pod.ObjectMeta, true, IsDNS1123Subdomain)
+	if strings.TrimSpace(pod.Image) == "" {
+		errs = append(errs, Required("image", ""))
+	}
+	if !oneOf(string(pod.Phase), podPhases) {
+		errs = append(errs, NotSupported("phase", string(pod.Phase), podPhases))
+	}
+	if !oneOf(string(pod.ImagePullPolicy), pullPolicies) {
+		errs = append(errs, NotSupported("imagePullPolicy", string(pod.ImagePullPolicy), pullPolicies[1:]))
+	}
+	if !oneOf(string(pod.RestartPolicy), restartPolicies) {
+		errs = append(errs, NotSupported("restartPolicy", string(pod.RestartPolicy), restartPolicies[1:]))
+	}
+	if pod.RestartCount < 0 {
+		errs = append(errs, Invalid("restartCount", pod.RestartCount, "must be greater than or equal to 0"))
+	}
+	for _, key := range api.SimulationAnnotations {
+		if value, ok := pod.Annotations[key]; ok {
+			if err := api.ValidateSimulationAnnotation(key, value); err != nil {
+				errs = append(errs, Invalid(fmt.Sprintf("annotations[%s]", key), value, err.Error()))
+			}
+		}
+	}
+	if pod.NodeName != "" {
+		for _, msg := range IsDNS1123Subdomain(pod.NodeName) {
+			errs = append(errs, Invalid("nodeName", pod.NodeName, msg))
+		}
+	}
+	for i, ref := range pod.ImagePullSecrets {
+		if ref.Name == "" {
+			errs = append(errs, Required(fmt.Sprintf("imagePullSecrets[%d].name", i), ""))
+		}
+	}
+	errs = append(errs, validatePodProcess(pod)...)
+	errs = append(errs, ValidateVolumes(pod.Volumes, pod.VolumeMounts)...)
+	errs = append(errs, ValidateResources(pod.Resources, pod.Overhead)...)
+	return errs
+}
+
+// validatePodProcess checks the command, arguments, and working directory of a pod's
+// process.
+func validatePodProcess(pod *api.Pod) ErrorList {
+	var errs ErrorList
+	if len(pod.Command) > 0 && strings.TrimSpace(pod.Command[0]) == "" {
+		errs = append(errs, Required("command[0]", "the executable to run"))
+	}
+	if pod.WorkingDir != "" && !path.IsAbs(pod.WorkingDir) {
+		errs = append(errs, Invalid("workingDir", pod.WorkingDir, "must be an absolute path"))
+	}
+	if sc := pod.SecurityContext; sc != nil && sc.RunAsUser != nil && (*sc.RunAsUser < 0 || *sc.RunAsUser > math.MaxInt32) {
+		errs = append(errs, Invalid("securityContext.runAsUser", *sc.RunAsUser, fmt.Sprintf("must be between 0 and %d", math.MaxInt32)))
+	}
+	return errs
+}
+
+// validatePodProcessUnchanged forbids changing a pod's process after it has been
+// created: the kubelet starts it once.
+func validatePodProcessUnchanged(pod, old *api.Pod) ErrorList {
+	var errs ErrorList
+	if !slices.Equal(pod.Command, old.Command) || !slices.Equal(pod.Args, old.Args) || pod.WorkingDir != old.WorkingDir || pod.TTY != old.TTY {
+		errs = append(errs, Forbidden("command", "command, args, workingDir, and tty may not be changed after the pod is created"))
+	}
+	if pod.RestartPolicy != old.RestartPolicy {
+		errs = append(errs, Forbidden("restartPolicy", "may not be changed after the pod is created"))
+	}
+	if !reflect.DeepEqual(pod.SecurityContext, old.SecurityContext) {
+		errs = append(errs, Forbidden("securityContext", "may not be changed after the pod is created"))
+	}
+	return errs
+}
+
+// Validate_PodUpdate checks an update of old to pod: everything Validate_Pod checks,
+// plus that the phase change is allowed by the pod phase state machine and that the
+// pod's process, volumes, and resources are unchanged.
+func Validate_PodUpdate(pod, old *api.Pod) ErrorList {
+	errs := Validate_Pod(pod)
+	errs = append(errs, validatePodProcessUnchanged(pod, old)...)
+	errs = append(errs, validatePodVolumesUnchanged(pod, old)...)
+	errs = append(errs, validatePodResourcesUnchanged(pod, old)...)
+	if err := ValidatePodPhaseTransition(old.Phase, pod.Phase, old.DeletionTimestamp != nil); err != nil {
+		errs = append(errs, Forbidden("phase", err.Error()))
+	}
+	return errs
+}
+
+// Validate_Node checks a node being registered or updated.
+func Validate_Node(node *api.Node) ErrorList {
+	errs := ValidateObjectMeta(&node.ObjectMeta, false, IsDNS1123Subdomain)
+	statuses := []string{string(api.NodeReady), string(api.NodeNotReady)}
+	if !oneOf(string(node.Status), statuses) {
+		errs = append(errs, NotSupported("status", string(node.Status), statuses))
+	}
+	conditionStatuses := []string{string(api.ConditionTrue), string(api.ConditionFalse), string(api.ConditionUnknown)}
+	seen := make(map[api.NodeConditionType]bool)
+	for i, cond := range node.Conditions {
+		field := fmt.Sprintf("conditions[%d]", i)
+		switch {
+		case cond.Type == "":
+			errs = append(errs, Required(field+".type", "a condition must have a type"))
+		case seen[cond.Type]:
+			errs = append(errs, Invalid(field+".type", string(cond.Type), "duplicate condition type"))
+		}
+		seen[cond.Type] = true
+		if !oneOf(string(cond.Status), conditionStatuses) {
+			errs = append(errs, NotSupported(field+".status", string(cond.Status), conditionStatuses))
+		}
+	}
+	errs = append(errs, validateResourceListOf("capacity", node.Capacity, nodeResourceNames)...)
+	errs = append(errs, validateResourceListOf("allocatable", node.Allocatable, nodeResourceNames)...)
+	return errs
+}
+
+// Validate_Namespace checks a namespace. Namespace names are DNS labels, since they
+// appear as a single component in DNS names.
+func Validate_Namespace(ns *api.Namespace) ErrorList {
+	errs := ValidateObjectMeta(&ns.ObjectMeta, false, IsDNS1123Label)
+	for _, label := range podsecurity.Labels {
+		if value, ok := ns.Labels[label]; ok {
+			if _, err := podsecurity.ParseLevel(value); err != nil {
+				errs = append(errs, NotSupported(fmt.Sprintf("labels[%s]", label), value, podsecurity.Levels))
+			}
+		}
+	}
+	phases := []string{string(api.NamespaceActive), string(api.NamespaceTerminating)}
+	if !oneOf(string(ns.Phase), phases) {
+		errs = append(errs, NotSupported("phase", string(ns.Phase), phases))
+	}
+	return errs
+}
+
+// Validate_Deployment checks a deployment, including that its selector matches the
+// pods its template produces.
+func Validate_Deployment(d *api.Deployment) ErrorList {
+	errs := ValidateObjectMeta(&d.ObjectMeta, true, IsDNS1123Subdomain)
+	if d.Replicas < 0 {
+		errs = append(errs, Invalid("replicas", d.Replicas, "must be greater than or equal to 0"))
+	}
+	if strings.TrimSpace(d.Template.Image) == "" {
+		errs = append(errs, Required("template.image", ""))
+	}
+	errs = append(errs, ValidateLabels("template.labels", d.Template.Labels)...)
+	errs = append(errs, ValidateAnnotations("template.annotations", d.Template.Annotations)...)
+	errs = append(errs, ValidateLabels("selector", d.Selector)...)
+	if len(d.Selector) == 0 {
+		errs = append(errs, Required("selector", ""))
+	}
+	for _, k := range sortedKeys(d.Selector) {
+		if v := d.Selector[k]; d.Template.Labels[k] != v {
+			errs = append(errs, Invalid("template.labels", d.Template.Labels, fmt.Sprintf("selector %s=%s does not match template labels", k, v)))
+		}
+	}
+	return errs
+}
+
+// Validate_Service checks a service's type and ports.
+func Validate_Service(svc *api.Service) ErrorList {
+	errs := ValidateObjectMeta(&svc.ObjectMeta, true, IsDNS1123Label)
+	types := []string{string(api.ServiceTypeClusterIP), string(api.ServiceTypeNodePort)}
+	if !oneOf(string(svc.Type), types) {
+		errs = append(errs, NotSupported("type", string(svc.Type), types))
+	}
+	errs = append(errs, ValidateLabels("selector", svc.Selector)...)
+	if len(svc.Ports) == 0 {
+		errs = append(errs, Required("ports", "service must expose at least one port"))
+	}
+	protocols := []string{"TCP", "UDP"}
+	for i, p := range svc.Ports {
+		field := fmt.Sprintf("ports[%d]", i)
+		if p.Name != "" {
+			for _, msg := range IsDNS1123Label(p.Name) {
+				errs = append(errs, Invalid(field+".name", p.Name, msg))
+			}
+		}
+		if !oneOf(p.Protocol, protocols) {
+			errs = append(errs, NotSupported(field+".protocol", p.Protocol, protocols))
+		}
+		if p.Port < 1 || p.Port > 65535 {
+			errs = append(errs, Invalid(field+".port", p.Port, "must be between 1 and 65535, inclusive"))
+		}
+		if p.TargetPort < 1 || p.TargetPort > 65535 {
+			errs = append(errs, Invalid(field+".targetPort", p.TargetPort, "must be between 1 and 65535, inclusive"))
+		}
+	}
+	return errs
+}
+
+// Validate_PodDisruptionBudget checks that a budget selects something and sets exactly
+// one of minAvailable and maxUnavailable.
+func Validate_PodDisruptionBudget(pdb *api.PodDisruptionBudget) ErrorList {
+	errs := ValidateObjectMeta(&pdb.ObjectMeta, true, IsDNS1123Subdomain)
+	errs = append(errs, ValidateLabels("selector", pdb.Selector)...)
+	if len(pdb.Selector) == 0 {
+		errs = append(errs, Required("selector", "pod disruption budget selector must not be empty"))
+	}
+	if (pdb.MinAvailable == nil) == (pdb.MaxUnavailable == nil) {
+		errs = append(errs, Invalid("minAvailable", pdb.MinAvailable, "exactly one of minAvailable and maxUnavailable must be set"))
+	}
+	if pdb.MinAvailable != nil && *pdb.MinAvailable < 0 {
+		errs = append(errs, Invalid("minAvailable", *pdb.MinAvailable, "must be greater than or equal to 0"))
+	}
+	if pdb.MaxUnavailable != nil && *pdb.MaxUnavailable < 0 {
+		errs = append(errs, Invalid("maxUnavailable", *pdb.MaxUnavailable, "must be greater than or equal to 0"))
+	}
+	return errs
+}
+
+// Validate_NetworkPolicy checks a network policy's selectors and ports, and that each
+// peer it allows traffic from sets at least one selector.
+func Validate_NetworkPolicy(policy *api.NetworkPolicy) ErrorList {
+	errs := ValidateObjectMeta(&policy.ObjectMeta, true, IsDNS1123Subdomain)
+	errs = append(errs, ValidateLabels("podSelector", policy.PodSelector)...)
+	for i, rule := range policy.Ingress {
+		for j, peer := range rule.From {
+			field := fmt.Sprintf("ingress[%d].from[%d]", i, j)
+			if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+				errs = append(errs, Required(field, "must set podSelector, namespaceSelector, or both"))
+			}
+			errs = append(errs, ValidateLabels(field+".podSelector", peer.PodSelector)...)
+			errs = append(errs, ValidateLabels(field+".namespaceSelector", peer.NamespaceSelector)...)
+		}
+		for j, port := range rule.Ports {
+			if port < 1 || port > 65535 {
+				errs = append(errs, Invalid(fmt.Sprintf("ingress[%d].ports[%d]", i, j), port, "must be between 1 and 65535, inclusive"))
+			}
+		}
+	}
+	return errs
+}
+
+// Validate_Event checks an event. Its name may still be empty, since the API server
+// generates one for new events.
+func Validate_Event(event *api.Event) ErrorList {
+	var errs ErrorList
+	if event.Name != "" {
+		errs = append(errs, validateName("name", event.Name, IsDNS1123Subdomain)...)
+	}
+	if event.InvolvedObject.Kind == "" {
+		errs = append(errs, Required("involvedObject.kind", ""))
+	}
+	if event.InvolvedObject.Name == "" {
+		errs = append(errs, Required("involvedObject.name", ""))
+	}
+	if event.Reason == "" {
+		errs = append(errs, Required("reason", ""))
+	}
+	types := []string{string(api.EventTypeNormal), string(api.EventTypeWarning)}
+	if !oneOf(string(event.Type), types) {
+		errs = append(errs, NotSupported("type", string(event.Type), types))
+	}
+	return errs
+}
+
+var accessModes = []string{string(api.ReadWriteOnce), string(api.ReadOnlyMany), string(api.ReadWriteMany)}
+
+func validateAccessModes(modes []api.PersistentVolumeAccessMode) ErrorList {
+	var errs ErrorList
+	if len(modes) == 0 {
+		errs = append(errs, Required("accessModes", "at least one access mode is required"))
+	}
+	seen := make(map[api.PersistentVolumeAccessMode]bool, len(modes))
+	for i, mode := range modes {
+		field := fmt.Sprintf("accessModes[%d]", i)
+		if !oneOf(string(mode), accessModes) {
+			errs = append(errs, NotSupported(field, string(mode), accessModes))
+		} else if seen[mode] {
+			errs = append(errs, Invalid(field, string(mode), "duplicate access mode"))
+		}
+		seen[mode] = true
+	}
+	return errs
+}
+
+func validateStorageQuantity(field, value string) ErrorList {
+	if value == "" {
+		return ErrorList{Required(field, "")}
+	}
+	if n, err := resource.ParseQuantity(value); err != nil {
+		return ErrorList{Invalid(field, value, "must be a quantity such as 10Gi or 500M")}
+	} else if n == 0 {
+		return ErrorList{Invalid(field, value, "must be greater than 0")}
+	}
+	return nil
+}
+
+// Validate_PersistentVolume checks a persistent volume's capacity, access modes,
+// reclaim policy, and hostPath source.
+func Validate_PersistentVolume(pv *api.PersistentVolume) ErrorList {
+	errs := ValidateObjectMeta(&pv.ObjectMeta, false, IsDNS1123Subdomain)
+	errs = append(errs, validateStorageQuantity("capacity", pv.Capacity)...)
+	errs = append(errs, validateAccessModes(pv.AccessModes)...)
+	policies := []string{string(api.PersistentVolumeReclaimRetain), string(api.PersistentVolumeReclaimDelete)}
+	if !oneOf(string(pv.ReclaimPolicy), policies) {
+		errs = append(errs, NotSupported("persistentVolumeReclaimPolicy", string(pv.ReclaimPolicy), policies))
+	}
+	if pv.HostPath == nil {
+		errs = append(errs, Required("hostPath", "persistent volumes must be backed by a hostPath"))
+	} else {
+		errs = append(errs, validateVolumeSource("", &api.VolumeSource{HostPath: pv.HostPath})...)
+	}
+	if pv.ClaimRef != nil && (pv.ClaimRef.Namespace == "" || pv.ClaimRef.Name == "") {
+		errs = append(errs, Required("claimRef", "claimRef must name the claim's namespace and name"))
+	}
+	phases := []string{string(api.VolumeAvailable), string(api.VolumeBound), string(api.VolumeReleased)}
+	if !oneOf(string(pv.Phase), phases) {
+		errs = append(errs, NotSupported("phase", string(pv.Phase), phases))
+	}
+	return errs
+}
+
+// Validate_PersistentVolumeUpdate checks an update of old to pv: everything
+// Validate_PersistentVolume checks, plus that its storage is unchanged.
+func Validate_PersistentVolumeUpdate(pv, old *api.PersistentVolume) ErrorList {
+	errs := Validate_PersistentVolume(pv)
+	if pv.Capacity != old.Capacity || !reflect.DeepEqual(pv.HostPath, old.HostPath) || !reflect.DeepEqual(pv.AccessModes, old.AccessModes) {
+		errs = append(errs, Forbidden("capacity", "capacity, accessModes, and hostPath may not be changed"))
+	}
+	return errs
+}
+
+// Validate_PersistentVolumeClaim checks a claim's access modes and requested size.
+func Validate_PersistentVolumeClaim(pvc *api.PersistentVolumeClaim) ErrorList {
+	errs := ValidateObjectMeta(&pvc.ObjectMeta, true, IsDNS1123Subdomain)
+	errs = append(errs, validateAccessModes(pvc.AccessModes)...)
+	errs = append(errs, validateStorageQuantity("storage", pvc.Storage)...)
+	if pvc.VolumeName != "" {
+		errs = append(errs, validateName("volumeName", pvc.VolumeName, IsDNS1123Subdomain)...)
+	}
+	phases := []string{string(api.ClaimPending), string(api.ClaimBound), string(api.ClaimLost)}
+	if !oneOf(string(pvc.Phase), phases) {
+		errs = append(errs, NotSupported("phase", string(pvc.Phase), phases))
+	}
+	return errs
+}
+
+// Validate_PersistentVolumeClaimUpdate checks an update of old to pvc: everything
+// Validate_PersistentVolumeClaim checks, plus that the request is unchanged and that
+// a bound claim stays bound to the same volume.
+func Validate_PersistentVolumeClaimUpdate(pvc, old *api.PersistentVolumeClaim) ErrorList {
+	errs := Validate_PersistentVolumeClaim(pvc)
+	if pvc.Storage != old.Storage || !reflect.DeepEqual(pvc.AccessModes, old.AccessModes) {
+		errs = append(errs, Forbidden("storage", "storage and accessModes may not be changed"))
+	}
+	if old.VolumeName != "" && pvc.VolumeName != old.VolumeName {
+		errs = append(errs, Forbidden("volumeName", "may not be changed once set"))
+	}
+	return errs
+}
+
+// configMapKey matches the keys a config map's data may have.
+var configMapKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// Validate_ConfigMap checks a config map's name and the keys of its data.
+func Validate_ConfigMap(configMap *api.ConfigMap) ErrorList {
+	errs := ValidateObjectMeta(&configMap.ObjectMeta, true, IsDNS1123Subdomain)
+	for _, key := range sortedKeys(configMap.Data) {
+		if len(key) > 253 || !configMapKey.MatchString(key) {
+			errs = append(errs, Invalid("data", key, "a key must consist of alphanumeric characters, '-', '_' or '.', and be at most 253 characters"))
+		}
+	}
+	return errs
+}
+
+// SetDefaults_Secret makes a secret without a type Opaque.
+func SetDefaults_Secret(secret *api.Secret) {
+	if secret.Type == "" {
+		secret.Type = api.SecretTypeOpaque
+	}
+}
+
+var secretTypes = []string{string(api.SecretTypeOpaque), string(api.SecretTypeDockerConfigJSON)}
+
+// Validate_Secret checks a secret's name, the keys of its data, and that its data
+// is what its type says.
+func Validate_Secret(secret *api.Secret) ErrorList {
+	errs := ValidateObjectMeta(&secret.ObjectMeta, true, IsDNS1123Subdomain)
+	for _, key := range sortedKeys(secret.Data) {
+		if len(key) > 253 || !configMapKey.MatchString(key) {
+			errs = append(errs, Invalid("data", key, "a key must consist of alphanumeric characters, '-', '_' or '.', and be at most 253 characters"))
+		}
+	}
+	switch secret.Type {
+	case api.SecretTypeOpaque:
+	case api.SecretTypeDockerConfigJSON:
+		field := fmt.Sprintf("data[%s]", api.DockerConfigJSONKey)
+		raw, ok := secret.Data[api.DockerConfigJSONKey]
+		if !ok {
+			errs = append(errs, Required(field, "the registry credentials of a "+string(api.SecretTypeDockerConfigJSON)+" secret"))
+			break
+		}
+		var config api.DockerConfigJSON
+		if err := json.Unmarshal(raw, &config); err != nil {
+			errs = append(errs, Invalid(field, "<secret contents redacted>", "must be the JSON of a Docker config file: "+err.Error()))
+		}
+	default:
+		errs = append(errs, NotSupported("type", string(secret.Type), secretTypes))
+	}
+	return errs
+}
+
+// Validate_Lease checks a lease's name and duration, and that a held lease says when
+// it was renewed.
+func Validate_Lease(lease *api.Lease) ErrorList {
+	errs := ValidateObjectMeta(&lease.ObjectMeta, true, IsDNS1123Subdomain)
+	if lease.DurationSeconds <= 0 {
+		errs = append(errs, Invalid("durationSeconds", lease.DurationSeconds, "must be greater than 0"))
+	}
+	if lease.HolderIdentity != "" && lease.RenewTime == nil {
+		errs = append(errs, Required("renewTime", "a held lease must have a renewTime"))
+	}
+	return errs
+}
+
+// Validate_APIService checks that an API service names an API group version that no
+// built-in resource is served under, that its name is "<version>.<group>", and that
+// its URL is an absolute http or https URL.
+func Validate_APIService(apiService *api.APIService) ErrorList {
+	errs := ValidateObjectMeta(&apiService.ObjectMeta, false, IsDNS1123Subdomain)
+	if apiService.Group == "" {
+		errs = append(errs, Required("group", "the API group the aggregated server serves"))
+	} else {
+		for _, msg := range IsDNS1123Subdomain(apiService.Group) {
+			errs = append(errs, Invalid("group", apiService.Group, msg))
+		}
+		for _, r := range api.Resources {
+			if r.Group() == apiService.Group {
+				errs = append(errs, Forbidden("group", fmt.Sprintf("group %s is served by the API server itself", apiService.Group)))
+				break
+			}
+		}
+	}
+	if apiService.Version == "" {
+		errs = append(errs, Required("version", "the version of the API group the aggregated server serves"))
+	} else {
+		for _, msg := range IsDNS1123Label(apiService.Version) {
+			errs = append(errs, Invalid("version", apiService.Version, msg))
+		}
+	}
+	if want := apiService.Version + "." + apiService.Group; apiService.Name != "" && apiService.Group != "" && apiService.Version != "" && apiService.Name != want {
+		errs = append(errs, Invalid("name", apiService.Name, "must be "+want+", the version and group"))
+	}
+	if apiService.URL == "" {
+		errs = append(errs, Required("url", "where the aggregated server listens"))
+	} else if u, err := url.Parse(apiService.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, Invalid("url", apiService.URL, "must be an absolute http or https URL"))
+	}
+	return errs
+}
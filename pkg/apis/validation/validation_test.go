@@ -0,0 +1,535 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// fields returns the field path of each error, in order.
+func fields(errs ErrorList) []string {
+	var out []string
+	for _, err := range errs {
+		out = append(out, err.Field)
+	}
+	return out
+}
+
+func TestNameFormats(t *testing.T) {
+	tests := []struct {
+		value       string
+		label, subd bool
+	}{
+		{"web", true, true},
+		{"web-1", true, true},
+		{"web.example.com", false, true},
+		{"Web", false, false},
+		{"-web", false, false},
+		{"web_1", false, false},
+		{strings.Repeat("a", 64), false, true},
+		{strings.Repeat("a", 254), false, false},
+	}
+	for _, tt := range tests {
+		if got := len(IsDNS1123Label(tt.value)) == 0; got != tt.label {
+			t.Errorf("IsDNS1123Label(%q) valid = %v, want %v", tt.value, got, tt.label)
+		}
+		if got := len(IsDNS1123Subdomain(tt.value)) == 0; got != tt.subd {
+			t.Errorf("IsDNS1123Subdomain(%q) valid = %v, want %v", tt.value, got, tt.subd)
+		}
+	}
+}
+
+func TestLabelSyntax(t *testing.T) {
+	tests := []struct {
+		key, value string
+		valid      bool
+	}{
+		{"app", "web", true},
+		{"app.kubernetes.io/name", "Web_1", true},
+		{"tier", "", true},
+		{"", "web", false},
+		{"/app", "web", false},
+		{"Example.com/app", "web", false},
+		{"app/", "web", false},
+		{"a/b/c", "web", false},
+		{"app", "-web", false},
+		{"app", strings.Repeat("a", 64), false},
+	}
+	for _, tt := range tests {
+		errs := ValidateLabels("labels", map[string]string{tt.key: tt.value})
+		if got := len(errs) == 0; got != tt.valid {
+			t.Errorf("label %q=%q valid = %v, want %v (errors: %v)", tt.key, tt.value, got, tt.valid, errs)
+		}
+	}
+}
+
+func TestValidatePod(t *testing.T) {
+	negativeUID := int64(-1)
+	tests := []struct {
+		name string
+		pod  api.Pod
+		want []string
+	}{
+		{
+			name: "valid",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}}, Image: "nginx", Phase: api.PodPending},
+		},
+		{
+			name: "everything wrong",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Namespace: "Default", Labels: map[string]string{"bad key": "v"}}, Phase: "Sleeping", NodeName: "Node_1"},
+			want: []string{"name", "namespace", "labels", "image", "phase", "nodeName"},
+		},
+		{
+			name: "owner reference without uid",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", OwnerReferences: []api.OwnerReference{{Kind: "Deployment", Name: "web"}}}, Image: "nginx", Phase: api.PodPending},
+			want: []string{"ownerReferences[0].uid"},
+		},
+		{
+			name: "command and working directory",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "busybox", Phase: api.PodPending, Command: []string{"sh", "-c"}, Args: []string{"echo hi"}, WorkingDir: "/data", TTY: true},
+		},
+		{
+			name: "empty command and relative working directory",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "busybox", Phase: api.PodPending, Command: []string{" "}, WorkingDir: "data"},
+			want: []string{"command[0]", "workingDir"},
+		},
+		{
+			name: "running as root",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "busybox", Phase: api.PodPending, SecurityContext: &api.SecurityContext{RunAsUser: new(int64), Privileged: true}},
+		},
+		{
+			name: "negative runAsUser",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "busybox", Phase: api.PodPending, SecurityContext: &api.SecurityContext{RunAsUser: &negativeUID}},
+			want: []string{"securityContext.runAsUser"},
+		},
+		{
+			name: "simulated behavior",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "job", Namespace: "default", Annotations: map[string]string{api.AnnotationRunDuration: "30s", api.AnnotationCrashProbability: "0.5"}}, Image: "busybox", Phase: api.PodPending, RestartPolicy: api.RestartPolicyOnFailure},
+		},
+		{
+			name: "bad simulated behavior and restart policy",
+			pod:  api.Pod{ObjectMeta: api.ObjectMeta{Name: "job", Namespace: "default", Annotations: map[string]string{api.AnnotationStartupDelay: "soon", api.AnnotationCrashProbability: "2"}}, Image: "busybox", Phase: api.PodPending, RestartPolicy: "Sometimes"},
+			want: []string{"restartPolicy", "annotations[k8s-lite.io/startup-delay]", "annotations[k8s-lite.io/crash-probability]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(Validate_Pod(&tt.pod)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodImagePullPolicyDefaults(t *testing.T) {
+	tests := map[string]api.PullPolicy{
+		"nginx":                             api.PullAlways,
+		"nginx:latest":                      api.PullAlways,
+		"registry.local:5000/nginx":         api.PullAlways,
+		"nginx:1.25":                        api.PullIfNotPresent,
+		"registry.local:5000/nginx:1.25":    api.PullIfNotPresent,
+		"nginx@sha256:0123456789abcdef0123": api.PullIfNotPresent,
+	}
+	for image, want := range tests {
+		pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: image}
+		SetDefaults_Pod(&pod)
+		if pod.ImagePullPolicy != want {
+			t.Errorf("image %q: defaulted pull policy %q, want %q", image, pod.ImagePullPolicy, want)
+		}
+	}
+
+	pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "nginx", Phase: api.PodPending, ImagePullPolicy: "Sometimes"}
+	if got := fields(Validate_Pod(&pod)); !reflect.DeepEqual(got, []string{"imagePullPolicy"}) {
+		t.Errorf("error fields = %v, want [imagePullPolicy]", got)
+	}
+}
+
+func TestValidatePodUpdate(t *testing.T) {
+	deleted := time.Now()
+	tests := []struct {
+		from, to    api.PodPhase
+		terminating bool
+		allowed     bool
+	}{
+		{api.PodPending, api.PodScheduled, false, true},
+		{api.PodPending, api.PodRunning, false, false},
+		{api.PodScheduled, api.PodRunning, false, true},
+		{api.PodScheduled, api.PodPending, false, true},
+		{api.PodRunning, api.PodSucceeded, false, true},
+		{api.PodRunning, api.PodPending, false, false},
+		{api.PodRunning, api.PodDeleted, false, false},
+		{api.PodRunning, api.PodDeleted, true, true},
+		{api.PodScheduled, api.PodRunning, true, false},
+		{api.PodPending, api.PodDeleted, true, true},
+		{api.PodSucceeded, api.PodDeleted, false, true},
+		{api.PodFailed, api.PodRunning, false, false},
+		{api.PodDeleted, api.PodRunning, false, false},
+		{api.PodDeleted, api.PodDeleted, true, true},
+		{api.PodRunning, api.PodTerminating, true, false},
+	}
+	for _, tt := range tests {
+		old := api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "nginx", Phase: tt.from}
+		if tt.terminating {
+			old.DeletionTimestamp = &deleted
+		}
+		pod := old
+		pod.Phase = tt.to
+		errs := Validate_PodUpdate(&pod, &old)
+		if got := len(errs) == 0; got != tt.allowed {
+			t.Errorf("%s -> %s (terminating=%v) allowed = %v, want %v (errors: %v)", tt.from, tt.to, tt.terminating, got, tt.allowed, errs)
+		}
+	}
+}
+
+func TestValidatePodProcessUpdate(t *testing.T) {
+	old := api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "busybox", Phase: api.PodRunning, Command: []string{"echo"}, Args: []string{"hi"}}
+	pod := *old.DeepCopy()
+	pod.Labels = map[string]string{"app": "web"}
+	if errs := Validate_PodUpdate(&pod, &old); len(errs) != 0 {
+		t.Errorf("expected an update leaving the process alone to be allowed, got %v", errs)
+	}
+	pod.Args = []string{"bye"}
+	pod.SecurityContext = &api.SecurityContext{Privileged: true}
+	if got := fields(Validate_PodUpdate(&pod, &old)); !reflect.DeepEqual(got, []string{"command", "securityContext"}) {
+		t.Errorf("error fields = %v, want [command securityContext]", got)
+	}
+}
+
+func TestPodPhaseTransitionMessage(t *testing.T) {
+	tests := []struct {
+		from, to    api.PodPhase
+		terminating bool
+		want        string
+	}{
+		{api.PodRunning, api.PodPending, false, "illegal phase transition from Running to Pending: a Running pod may only move to Succeeded or Failed"},
+		{api.PodScheduled, api.PodRunning, true, "illegal phase transition from Scheduled to Running: a terminating Scheduled pod may only move to Failed or Deleted"},
+		{api.PodDeleted, api.PodRunning, false, "illegal phase transition from Deleted to Running: Deleted is final"},
+	}
+	for _, tt := range tests {
+		err := ValidatePodPhaseTransition(tt.from, tt.to, tt.terminating)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ValidatePodPhaseTransition(%s, %s) = %v, want %q", tt.from, tt.to, err, tt.want)
+		}
+	}
+}
+
+func TestValidateVolumes(t *testing.T) {
+	scratch := api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}
+	tests := []struct {
+		name    string
+		volumes []api.Volume
+		mounts  []api.VolumeMount
+		want    []string
+	}{
+		{
+			name: "valid",
+			volumes: []api.Volume{
+				{Name: "cache", VolumeSource: scratch},
+				{Name: "logs", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: "/var/log", Type: api.HostPathDirectory}}},
+				{Name: "data", VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+			},
+			mounts: []api.VolumeMount{{Name: "cache", MountPath: "/cache"}, {Name: "logs", MountPath: "/var/log", ReadOnly: true}, {Name: "data", MountPath: "/data"}},
+		},
+		{
+			name: "bad volumes",
+			volumes: []api.Volume{
+				{Name: "cache", VolumeSource: scratch},
+				{Name: "cache", VolumeSource: scratch},
+				{Name: "Data"},
+				{Name: "both", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}, HostPath: &api.HostPathVolumeSource{Path: "/data"}}},
+				{Name: "host", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: "data", Type: "Socket"}}},
+				{Name: "claim", VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{}}},
+			},
+			want: []string{"volumes[1].name", "volumes[2].name", "volumes[2]", "volumes[3]", "volumes[4].hostPath.path", "volumes[4].hostPath.type", "volumes[5].persistentVolumeClaim.claimName"},
+		},
+		{
+			name:    "bad mounts",
+			volumes: []api.Volume{{Name: "cache", VolumeSource: scratch}},
+			mounts:  []api.VolumeMount{{Name: "missing", MountPath: "/a"}, {Name: "cache", MountPath: "cache"}, {Name: "cache", MountPath: "/a/"}},
+			want:    []string{"volumeMounts[0].name", "volumeMounts[1].mountPath", "volumeMounts[2].mountPath"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(ValidateVolumes(tt.volumes, tt.mounts)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodQOSClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources api.ResourceRequirements
+		want      api.PodQOSClass
+	}{
+		{name: "none", want: api.PodQOSBestEffort},
+		{
+			name:      "limits only",
+			resources: api.ResourceRequirements{Limits: api.ResourceList{"cpu": "500m", "memory": "128Mi"}},
+			want:      api.PodQOSGuaranteed,
+		},
+		{
+			name: "requests equal to limits",
+			resources: api.ResourceRequirements{
+				Requests: api.ResourceList{"cpu": "0.5", "memory": "128Mi"},
+				Limits:   api.ResourceList{"cpu": "500m", "memory": "134217728"},
+			},
+			want: api.PodQOSGuaranteed,
+		},
+		{
+			name: "requests below limits",
+			resources: api.ResourceRequirements{
+				Requests: api.ResourceList{"cpu": "250m", "memory": "128Mi"},
+				Limits:   api.ResourceList{"cpu": "500m", "memory": "128Mi"},
+			},
+			want: api.PodQOSBurstable,
+		},
+		{
+			name:      "memory limit only",
+			resources: api.ResourceRequirements{Limits: api.ResourceList{"memory": "128Mi"}},
+			want:      api.PodQOSBurstable,
+		},
+		{
+			name:      "requests only",
+			resources: api.ResourceRequirements{Requests: api.ResourceList{"cpu": "1"}},
+			want:      api.PodQOSBurstable,
+		},
+	}
+	for _, tt := range tests {
+		pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "nginx", Resources: tt.resources, QOSClass: api.PodQOSGuaranteed}
+		SetDefaults_Pod(&pod)
+		if pod.QOSClass != tt.want {
+			t.Errorf("%s: QoS class %q, want %q", tt.name, pod.QOSClass, tt.want)
+		}
+		for name, limit := range tt.resources.Limits {
+			if pod.Resources.Requests[name] == "" {
+				t.Errorf("%s: expected the %s limit %s to set the request", tt.name, name, limit)
+			}
+		}
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	resources := api.ResourceRequirements{
+		Requests: api.ResourceList{"cpu": "2", "memory": "lots", "gpu": "1"},
+		Limits:   api.ResourceList{"cpu": "1500m", "memory": "1Gi"},
+	}
+	want := []string{"resources.requests", "resources.requests.memory", "overhead.memory", "resources.requests.cpu"}
+	if got := fields(ValidateResources(resources, api.ResourceList{"memory": "-1Mi"})); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v", got, want)
+	}
+
+	old := api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Image: "nginx", Phase: api.PodRunning}
+	old.Resources.Limits = api.ResourceList{"memory": "128Mi"}
+	SetDefaults_Pod(&old)
+	pod := *old.DeepCopy()
+	pod.Resources.Limits["memory"] = "256Mi"
+	SetDefaults_Pod(&pod)
+	if got := fields(Validate_PodUpdate(&pod, &old)); !reflect.DeepEqual(got, []string{"resources"}) {
+		t.Errorf("error fields = %v, want [resources]", got)
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"example.com/description": "anything at all: {\"json\": true}",
+		"bad key":                 "",
+	}
+	if got := fields(ValidateAnnotations("annotations", annotations)); !reflect.DeepEqual(got, []string{"annotations"}) {
+		t.Errorf("error fields = %v, want [annotations]", got)
+	}
+
+	large := map[string]string{"a": strings.Repeat("x", TotalAnnotationSizeLimit/2), "b": strings.Repeat("x", TotalAnnotationSizeLimit/2)}
+	errs := ValidateAnnotations("template.annotations", large)
+	if len(errs) != 1 || errs[0].Type != ErrorTypeTooLong || errs[0].Field != "template.annotations" {
+		t.Errorf("expected one TooLong error for annotations over the limit, got %v", errs)
+	}
+	delete(large, "b")
+	if errs := ValidateAnnotations("annotations", large); len(errs) != 0 {
+		t.Errorf("expected annotations within the limit to be valid, got %v", errs)
+	}
+}
+
+func TestDeploymentDefaultsAndValidation(t *testing.T) {
+	d := api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Replicas: 2, Template: api.PodTemplate{Image: "nginx"}}
+	SetDefaults_Deployment(&d)
+	if want := map[string]string{"app": "web"}; !reflect.DeepEqual(d.Selector, want) || !reflect.DeepEqual(d.Template.Labels, want) {
+		t.Fatalf("defaulted selector %v and template labels %v, want both %v", d.Selector, d.Template.Labels, want)
+	}
+	if errs := Validate_Deployment(&d); len(errs) != 0 {
+		t.Fatalf("defaulted deployment is invalid: %v", errs)
+	}
+
+	d.Replicas = -1
+	d.Template.Image = ""
+	d.Selector = map[string]string{"app": "api"}
+	want := []string{"replicas", "template.image", "template.labels"}
+	if got := fields(Validate_Deployment(&d)); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v", got, want)
+	}
+}
+
+func TestValidateService(t *testing.T) {
+	svc := api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"},
+		Ports:      []api.ServicePort{{Port: 80}, {Name: "Metrics", Port: 70000, Protocol: "SCTP"}},
+	}
+	SetDefaults_Service(&svc)
+	if svc.Type != api.ServiceTypeClusterIP || svc.Ports[0].Protocol != "TCP" || svc.Ports[0].TargetPort != 80 {
+		t.Fatalf("defaults not applied: %+v", svc)
+	}
+	want := []string{"ports[1].name", "ports[1].protocol", "ports[1].port", "ports[1].targetPort"}
+	if got := fields(Validate_Service(&svc)); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v", got, want)
+	}
+}
+
+func TestValidateLease(t *testing.T) {
+	lease := api.Lease{ObjectMeta: api.ObjectMeta{Name: "node1", Namespace: "kube-node-lease"}}
+	SetDefaults_Lease(&lease)
+	if lease.DurationSeconds != DefaultLeaseDurationSeconds {
+		t.Fatalf("expected the default duration, got %d", lease.DurationSeconds)
+	}
+	if errs := Validate_Lease(&lease); len(errs) != 0 {
+		t.Errorf("expected an unheld lease to be valid, got %v", errs)
+	}
+	lease.HolderIdentity = "node1"
+	lease.DurationSeconds = -1
+	want := []string{"durationSeconds", "renewTime"}
+	if got := fields(Validate_Lease(&lease)); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v", got, want)
+	}
+}
+
+func TestValidateSecret(t *testing.T) {
+	secret := api.Secret{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default"}, Data: map[string][]byte{"password": []byte("s3cret")}}
+	SetDefaults_Secret(&secret)
+	if secret.Type != api.SecretTypeOpaque {
+		t.Fatalf("expected the default type %s, got %s", api.SecretTypeOpaque, secret.Type)
+	}
+	if errs := Validate_Secret(&secret); len(errs) != 0 {
+		t.Errorf("expected an Opaque secret to be valid, got %v", errs)
+	}
+	tests := []struct {
+		name   string
+		secret api.Secret
+		want   []string
+	}{
+		{"registry credentials", api.Secret{Type: api.SecretTypeDockerConfigJSON, Data: map[string][]byte{api.DockerConfigJSONKey: []byte(`{"auths":{}}`)}}, nil},
+		{"missing credentials", api.Secret{Type: api.SecretTypeDockerConfigJSON}, []string{"data[.dockerconfigjson]"}},
+		{"credentials not JSON", api.Secret{Type: api.SecretTypeDockerConfigJSON, Data: map[string][]byte{api.DockerConfigJSONKey: []byte("alice:s3cret")}}, []string{"data[.dockerconfigjson]"}},
+		{"unknown type", api.Secret{Type: "kubernetes.io/tls"}, []string{"type"}},
+		{"bad key", api.Secret{Type: api.SecretTypeOpaque, Data: map[string][]byte{"no spaces": nil}}, []string{"data"}},
+	}
+	for _, tt := range tests {
+		tt.secret.ObjectMeta = api.ObjectMeta{Name: "db", Namespace: "default"}
+		if got := fields(Validate_Secret(&tt.secret)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: error fields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePersistentVolumes(t *testing.T) {
+	pv := api.PersistentVolume{
+		ObjectMeta:  api.ObjectMeta{Name: "pv-1"},
+		Capacity:    "10Gi",
+		AccessModes: []api.PersistentVolumeAccessMode{api.ReadWriteOnce},
+		HostPath:    &api.HostPathVolumeSource{Path: "/srv/pv-1"},
+	}
+	SetDefaults_PersistentVolume(&pv)
+	if pv.ReclaimPolicy != api.PersistentVolumeReclaimRetain || pv.Phase != api.VolumeAvailable {
+		t.Fatalf("defaults not applied: %+v", pv)
+	}
+	if errs := Validate_PersistentVolume(&pv); len(errs) != 0 {
+		t.Fatalf("valid volume rejected: %v", errs)
+	}
+
+	bad := pv
+	bad.Capacity = "10GB"
+	bad.AccessModes = []api.PersistentVolumeAccessMode{api.ReadWriteOnce, "ReadWriteSometimes", api.ReadWriteOnce}
+	bad.HostPath = nil
+	want := []string{"capacity", "accessModes[1]", "accessModes[2]", "hostPath"}
+	if got := fields(Validate_PersistentVolume(&bad)); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v", got, want)
+	}
+
+	resized := pv
+	resized.Capacity = "20Gi"
+	if got := fields(Validate_PersistentVolumeUpdate(&resized, &pv)); !reflect.DeepEqual(got, []string{"capacity"}) {
+		t.Errorf("resizing a volume: error fields = %v", got)
+	}
+	bound := pv
+	bound.ClaimRef = &api.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: "default", Name: "data"}
+	bound.Phase = api.VolumeBound
+	if errs := Validate_PersistentVolumeUpdate(&bound, &pv); len(errs) != 0 {
+		t.Errorf("binding a volume rejected: %v", errs)
+	}
+
+	pvc := api.PersistentVolumeClaim{
+		ObjectMeta:  api.ObjectMeta{Name: "data", Namespace: "default"},
+		AccessModes: []api.PersistentVolumeAccessMode{api.ReadWriteOnce},
+		Storage:     "1Gi",
+	}
+	SetDefaults_PersistentVolumeClaim(&pvc)
+	if errs := Validate_PersistentVolumeClaim(&pvc); len(errs) != 0 {
+		t.Fatalf("valid claim rejected: %v", errs)
+	}
+	boundClaim := pvc
+	boundClaim.VolumeName, boundClaim.Phase = "pv-1", api.ClaimBound
+	if errs := Validate_PersistentVolumeClaimUpdate(&boundClaim, &pvc); len(errs) != 0 {
+		t.Errorf("binding a claim rejected: %v", errs)
+	}
+	rebound := boundClaim
+	rebound.VolumeName = "pv-2"
+	rebound.Storage = "2Gi"
+	want = []string{"storage", "volumeName"}
+	if got := fields(Validate_PersistentVolumeClaimUpdate(&rebound, &boundClaim)); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v", got, want)
+	}
+}
+
+func TestErrorListMessage(t *testing.T) {
+	errs := ErrorList{
+		Required("image", ""),
+		Invalid("name", "Web", "must be lowercase"),
+		NotSupported("type", "LoadBalancer", []string{"ClusterIP", "NodePort"}),
+	}
+	want := `[image: Required value, name: Invalid value: "Web": must be lowercase, type: Unsupported value: "LoadBalancer": supported values: "ClusterIP", "NodePort"]`
+	if got := errs.Error(); got != want {
+		t.Errorf("Error() = %s\nwant      %s", got, want)
+	}
+	if ErrorList(nil).ToAggregate() != nil {
+		t.Errorf("ToAggregate of an empty list should be nil")
+	}
+}
+
+func TestValidateAPIService(t *testing.T) {
+	apiService := api.APIService{ObjectMeta: api.ObjectMeta{Name: "v1.metrics.lite"}, Group: "metrics.lite", Version: "v1", URL: "http://localhost:8443"}
+	if errs := Validate_APIService(&apiService); len(errs) != 0 {
+		t.Errorf("expected the API service to be valid, got %v", errs)
+	}
+	tests := []struct {
+		name   string
+		mutate func(*api.APIService)
+		want   []string
+	}{
+		{"name mismatch", func(s *api.APIService) { s.Name = "metrics" }, []string{"name"}},
+		{"built-in group", func(s *api.APIService) { s.Name, s.Group = "v1.apps", "apps" }, []string{"group"}},
+		{"missing fields", func(s *api.APIService) { s.Group, s.Version, s.URL = "", "", "" }, []string{"group", "version", "url"}},
+		{"relative url", func(s *api.APIService) { s.URL = "localhost:8443" }, []string{"url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := apiService
+			tt.mutate(&s)
+			if got := fields(Validate_APIService(&s)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
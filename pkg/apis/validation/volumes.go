@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+var hostPathTypes = []string{
+	string(api.HostPathUnset), string(api.HostPathDirectoryOrCreate),
+	string(api.HostPathDirectory), string(api.HostPathFile),
+}
+
+// ValidateVolumes checks a pod's volumes and the mounts that refer to them: volume names
+// are unique DNS labels with exactly one source, every mount names a volume, and mount
+// paths are absolute and distinct.
+func ValidateVolumes(volumes []api.Volume, mounts []api.VolumeMount) ErrorList {
+	var errs ErrorList
+	names := make(map[string]bool, len(volumes))
+	for i, vol := range volumes {
+		field := fmt.Sprintf("volumes[%d]", i)
+		if vol.Name == "" {
+			errs = append(errs, Required(field+".name", ""))
+		} else {
+			for _, msg := range IsDNS1123Label(vol.Name) {
+				errs = append(errs, Invalid(field+".name", vol.Name, msg))
+			}
+			if names[vol.Name] {
+				errs = append(errs, Invalid(field+".name", vol.Name, "duplicate volume name"))
+			}
+			names[vol.Name] = true
+		}
+		errs = append(errs, validateVolumeSource(field, &vol.VolumeSource)...)
+	}
+
+	mountPaths := make(map[string]bool, len(mounts))
+	for i, m := range mounts {
+		field := fmt.Sprintf("volumeMounts[%d]", i)
+		if m.Name == "" {
+			errs = append(errs, Required(field+".name", ""))
+		} else if !names[m.Name] {
+			errs = append(errs, NotFound(field+".name", m.Name))
+		}
+		switch {
+		case m.MountPath == "":
+			errs = append(errs, Required(field+".mountPath", ""))
+		case !path.IsAbs(m.MountPath):
+			errs = append(errs, Invalid(field+".mountPath", m.MountPath, "must be an absolute path"))
+		case mountPaths[path.Clean(m.MountPath)]:
+			errs = append(errs, Invalid(field+".mountPath", m.MountPath, "must be unique"))
+		}
+		mountPaths[path.Clean(m.MountPath)] = true
+	}
+	return errs
+}
+
+func validateVolumeSource(field string, src *api.VolumeSource) ErrorList {
+	var errs ErrorList
+	sources := 0
+	if src.EmptyDir != nil {
+		sources++
+	}
+	if src.HostPath != nil {
+		sources++
+		prefix := joinField(field, "hostPath")
+		switch p := src.HostPath.Path; {
+		case p == "":
+			errs = append(errs, Required(prefix+".path", ""))
+		case !path.IsAbs(p):
+			errs = append(errs, Invalid(prefix+".path", p, "must be an absolute path"))
+		case strings.Contains(p, ".."):
+			errs = append(errs, Invalid(prefix+".path", p, "must not contain '..'"))
+		}
+		if !oneOf(string(src.HostPath.Type), hostPathTypes) {
+			errs = append(errs, NotSupported(prefix+".type", string(src.HostPath.Type), hostPathTypes[1:]))
+		}
+	}
+	if src.PersistentVolumeClaim != nil {
+		sources++
+		errs = append(errs, validateName(joinField(field, "persistentVolumeClaim.claimName"), src.PersistentVolumeClaim.ClaimName, IsDNS1123Subdomain)...)
+	}
+	switch sources {
+	case 0:
+		errs = append(errs, Required(field, "must specify a volume source (emptyDir, hostPath, or persistentVolumeClaim)"))
+	case 1:
+	default:
+		errs = append(errs, Forbidden(field, "may not specify more than one volume source"))
+	}
+	return errs
+}
+
+// joinField appends child to the field path parent, which may be empty.
+func joinField(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
+
+// validatePodVolumesUnchanged forbids changing a pod's volumes or mounts after it has
+// been created: the kubelet only sets them up when it starts the pod.
+func validatePodVolumesUnchanged(pod, old *api.Pod) ErrorList {
+	var errs ErrorList
+	if !reflect.DeepEqual(pod.Volumes, old.Volumes) {
+		errs = append(errs, Forbidden("volumes", "may not be changed after the pod is created"))
+	}
+	if !reflect.DeepEqual(pod.VolumeMounts, old.VolumeMounts) {
+		errs = append(errs, Forbidden("volumeMounts", "may not be changed after the pod is created"))
+	}
+	return errs
+}
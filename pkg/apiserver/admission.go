@@ -0,0 +1,112 @@
+package apiserver
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/podsecurity"
+	"github.com/gin-gonic/gin"
+)
+
+// Admission plugins, as rejections are counted and reported by.
+const (
+	// admissionValidation rejects objects that fail validation, with 422.
+	admissionValidation = "Validation"
+	// admissionNamespaceLifecycle rejects new objects in a terminating namespace.
+	admissionNamespaceLifecycle = "NamespaceLifecycle"
+	// admissionPodSecurity rejects pods that violate their namespace's enforced Pod
+	// Security Standard.
+	admissionPodSecurity = "PodSecurity"
+)
+
+// rejectInvalid answers 422 Unprocessable Entity if errs is non-empty, listing each
+// field error as a cause, and reports whether it did. Handlers call it after defaulting
+// and validating an object, before anything is stored:
+//
+//	validation.SetDefaults_Pod(&pod)
+//	if s.rejectInvalid(c, "Pod", pod.Name, validation.Validate_Pod(&pod)) {
+//		return
+//	}
+func (s *APIServer) rejectInvalid(c *gin.Context, kind, name string, errs validation.ErrorList) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	c.JSON(422, s.rejected(c, admissionValidation, kind, name, invalidBody(kind, name, errs)))
+	return true
+}
+
+// rejected records that plugin rejected the kind object named name with body, the
+// error body it is answered with, and returns body. Each rejection is counted in
+// /metrics and, unless the request is a dry run, reported as a Warning event on the
+// object, in its namespace or, for a cluster-scoped object, the default namespace, so
+// that users can see why their writes bounce with kubectl-lite get events.
+func (s *APIServer) rejected(c *gin.Context, plugin, kind, name string, body gin.H) gin.H {
+	resource, _ := api.LookupResource(kind)
+	s.metrics.recordAdmissionRejection(plugin, resource.Name)
+	if isDryRun(c) {
+		return body
+	}
+	ref := api.ObjectReference{Kind: kind, Name: name}
+	namespace := DefaultNamespace
+	if resource.Namespaced {
+		ref.Namespace = c.Param("namespace")
+		if ref.Namespace == "" {
+			ref.Namespace = DefaultNamespace
+		}
+		namespace = ref.Namespace
+	}
+	event := api.Event{
+		ObjectMeta:     api.ObjectMeta{Namespace: namespace},
+		InvolvedObject: ref,
+		Type:           api.EventTypeWarning,
+		Reason:         "FailedAdmission",
+		Message:        fmt.Sprintf("%s: %v", plugin, body["error"]),
+		Source:         api.EventSource{Component: "apiserver"},
+	}
+	if _, err := s.recordEvent(c.Request.Context(), &event); err != nil {
+		log.Printf("Error recording the %s rejection of %s %s/%s: %v", plugin, kind, ref.Namespace, name, err)
+	}
+	return body
+}
+
+// invalidBody is the body of a 422 for an object that failed validation.
+func invalidBody(kind, name string, errs validation.ErrorList) gin.H {
+	return gin.H{
+		"error":  fmt.Sprintf("%s %q is invalid: %s", kind, name, errs.Error()),
+		"causes": errs,
+	}
+}
+
+// admitPodSecurity holds a new pod to the Pod Security Standards its namespace's labels
+// set. It returns the body of a 403 if the pod violates the enforced level; otherwise
+// it logs the pod if it violates the audited level and warns the client, in a Warning
+// header, if it violates the level warned about.
+func (s *APIServer) admitPodSecurity(c *gin.Context, pod *api.Pod) gin.H {
+	ctx := c.Request.Context()
+	ns, err := s.store.GetNamespace(ctx, pod.Namespace)
+	if err != nil {
+		return nil
+	}
+	policy, err := podsecurity.PolicyFor(ns.Labels)
+	if err != nil {
+		log.Printf("Namespace %s has invalid pod security labels, holding its pods to the restricted level instead: %v", ns.Name, err)
+	}
+	if violations := podsecurity.Check(policy.Enforce, pod); len(violations) > 0 {
+		return s.rejected(c, admissionPodSecurity, "Pod", pod.Name, gin.H{"error": fmt.Sprintf("pods %q is forbidden: violates %s", pod.Name, podsecurity.Describe(policy.Enforce, violations))})
+	}
+	if violations := podsecurity.Check(policy.Audit, pod); len(violations) > 0 {
+		log.Printf("Audit: pod %s/%s violates %s", pod.Namespace, pod.Name, podsecurity.Describe(policy.Audit, violations))
+	}
+	if violations := podsecurity.Check(policy.Warn, pod); len(violations) > 0 {
+		addWarning(c, fmt.Sprintf("would violate %s", podsecurity.Describe(policy.Warn, violations)))
+	}
+	return nil
+}
+
+// addWarning adds a Warning header to the response, which clients show their user.
+func addWarning(c *gin.Context, message string) {
+	c.Writer.Header().Add("Warning", fmt.Sprintf("299 - %s", strconv.Quote(message)))
+}
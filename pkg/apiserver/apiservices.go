@@ -0,0 +1,192 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *APIServer) apiServices() *store.Registry[*api.APIService] {
+	return store.RegistryFor[*api.APIService](s.store, store.APIServices)
+}
+
+// Gin handler for registering an aggregated API server
+func (s *APIServer) createAPIServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var apiService api.APIService
+	if err := c.ShouldBindJSON(&apiService); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	apiService.Namespace = ""
+	if s.rejectInvalid(c, "APIService", apiService.Name, validation.Validate_APIService(&apiService)) {
+		return
+	}
+
+	if isDryRun(c) {
+		if _, err := s.apiServices().Get(ctx, "", apiService.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create apiservice: apiservice %s already exists", apiService.Name)})
+			return
+		}
+		c.JSON(201, apiService)
+		return
+	}
+
+	if err := s.apiServices().Create(ctx, &apiService); err != nil {
+		log.Printf("Error creating apiservice %s in store: %v", apiService.Name, err)
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create apiservice: " + err.Error()})
+		return
+	}
+	log.Printf("Created apiservice %s, proxying /apis/%s/%s to %s", apiService.Name, apiService.Group, apiService.Version, apiService.URL)
+	c.JSON(201, apiService)
+}
+
+// Gin handler for getting a specific API service
+func (s *APIServer) getAPIServiceHandlerGin(c *gin.Context) {
+	apiService, err := s.apiServices().Get(c.Request.Context(), "", c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get apiservice: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(apiService), apiService)
+}
+
+// Gin handler for listing API services
+func (s *APIServer) listAPIServicesHandlerGin(c *gin.Context) {
+	apiServices, err := s.apiServices().List(c.Request.Context(), "")
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list apiservices: " + err.Error()})
+		return
+	}
+	respondWithList(c, apiServices)
+}
+
+// Gin handler for updating a specific API service, such as to move it to another URL
+func (s *APIServer) updateAPIServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	var apiService api.APIService
+	if err := c.ShouldBindJSON(&apiService); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if apiService.Name != name {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("APIService name in body (%s) does not match name in URL (%s)", apiService.Name, name)})
+		return
+	}
+	apiService.Namespace = ""
+	if s.rejectInvalid(c, "APIService", apiService.Name, validation.Validate_APIService(&apiService)) {
+		return
+	}
+
+	if isDryRun(c) {
+		if _, err := s.apiServices().Get(ctx, "", name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update apiservice: " + err.Error()})
+			return
+		}
+		c.JSON(200, apiService)
+		return
+	}
+
+	if err := s.apiServices().Update(ctx, &apiService); err != nil {
+		log.Printf("Failed to update apiservice in store: %v", err)
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update apiservice: " + err.Error()})
+		return
+	}
+	c.JSON(200, apiService)
+}
+
+// Gin handler for deleting a specific API service. Its group version is no longer
+// served once it is gone.
+func (s *APIServer) deleteAPIServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.apiServices().Get(ctx, "", name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete apiservice: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("APIService %s deleted (dry run)", name)})
+		return
+	}
+	if err := s.apiServices().Delete(ctx, "", name); err != nil {
+		log.Printf("Error deleting apiservice %s from store: %v", name, err)
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete apiservice: " + err.Error()})
+		return
+	}
+	log.Printf("Deleted apiservice %s", name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("APIService %s deleted", name)})
+}
+
+// aggregatedGroups returns the API groups aggregated servers serve, for discovery.
+func (s *APIServer) aggregatedGroups(ctx context.Context) ([]api.APIGroup, error) {
+	apiServices, err := s.apiServices().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	byGroup := make(map[string]*api.APIGroup)
+	var names []string
+	for _, apiService := range apiServices {
+		v := api.GroupVersionForDiscovery{GroupVersion: apiService.Group + "/" + apiService.Version, Version: apiService.Version}
+		group, ok := byGroup[apiService.Group]
+		if !ok {
+			group = &api.APIGroup{Name: apiService.Group, PreferredVersion: v}
+			byGroup[apiService.Group] = group
+			names = append(names, apiService.Group)
+		}
+		group.Versions = append(group.Versions, v)
+	}
+	sort.Strings(names)
+	groups := make([]api.APIGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, *byGroup[name])
+	}
+	return groups, nil
+}
+
+// Gin handler for requests no route matches. Those under /apis/<group>/<version> for
+// a group version an API service registered are passed on to its aggregated server,
+// at the same path and query; anything else is answered 404 as usual. The client's
+// credentials are not passed on.
+func (s *APIServer) aggregatedAPIHandlerGin(c *gin.Context) {
+	rest, ok := strings.CutPrefix(c.Request.URL.Path, "/apis/")
+	if !ok {
+		return
+	}
+	segments := strings.SplitN(rest, "/", 3)
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return
+	}
+	apiService, err := s.apiServices().Get(c.Request.Context(), "", segments[1]+"."+segments[0])
+	if err != nil {
+		return
+	}
+	target, err := url.Parse(apiService.URL)
+	if err != nil {
+		c.JSON(503, gin.H{"error": fmt.Sprintf("APIService %s has an invalid URL %q", apiService.Name, apiService.URL)})
+		return
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.Host = ""
+			r.Out.Header.Del("Authorization")
+			r.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			c.JSON(503, gin.H{"error": fmt.Sprintf("Failed to reach the aggregated API server of %s at %s: %v", apiService.Name, apiService.URL, err)})
+		},
+	}
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
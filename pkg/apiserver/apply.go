@@ -0,0 +1,292 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/fieldmanager"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// managedFieldsKey is the gin context key under which an apply leaves the managed
+// fields it computed, for the create or update handler it hands the write to.
+const managedFieldsKey = "managedFields"
+
+// applyResource is what server-side apply needs to know about a kind.
+type applyResource struct {
+	kind       string
+	nameParam  string // The route parameter holding the object's name
+	namespaced bool
+	newObject  func() metaObject
+	get        func(ctx context.Context, st store.Store, namespace, name string) (metaObject, error)
+	create     func(s *APIServer, c *gin.Context)
+	update     func(s *APIServer, c *gin.Context)
+}
+
+var (
+	applyPods = applyResource{
+		kind: "Pod", nameParam: "podname", namespaced: true,
+		newObject: func() metaObject { return &api.Pod{} },
+		get: func(ctx context.Context, st store.Store, namespace, name string) (metaObject, error) {
+			return orNil(st.GetPod(ctx, namespace, name))
+		},
+		create: (*APIServer).createPodHandlerGin,
+		update: (*APIServer).updatePodHandlerGin,
+	}
+	applyNodes = applyResource{
+		kind: "Node", nameParam: "nodename",
+		newObject: func() metaObject { return &api.Node{} },
+		get: func(ctx context.Context, st store.Store, _, name string) (metaObject, error) {
+			return orNil(st.GetNode(ctx, name))
+		},
+		create: (*APIServer).createNodeHandlerGin,
+		update: (*APIServer).updateNodeHandlerGin,
+	}
+	applyNamespaces = applyResource{
+		kind: "Namespace", nameParam: "namespace",
+		newObject: func() metaObject { return &api.Namespace{} },
+		get: func(ctx context.Context, st store.Store, _, name string) (metaObject, error) {
+			return orNil(st.GetNamespace(ctx, name))
+		},
+		create: (*APIServer).createNamespaceHandlerGin,
+		update: (*APIServer).updateNamespaceHandlerGin,
+	}
+	applyDeployments = applyResource{
+		kind: "Deployment", nameParam: "name", namespaced: true,
+		newObject: func() metaObject { return &api.Deployment{} },
+		get: func(ctx context.Context, st store.Store, namespace, name string) (metaObject, error) {
+			return orNil(st.GetDeployment(ctx, namespace, name))
+		},
+		create: (*APIServer).createDeploymentHandlerGin,
+		update: (*APIServer).updateDeploymentHandlerGin,
+	}
+	applyServices = applyResource{
+		kind: "Service", nameParam: "name", namespaced: true,
+		newObject: func() metaObject { return &api.Service{} },
+		get: func(ctx context.Context, st store.Store, namespace, name string) (metaObject, error) {
+			return orNil(st.GetService(ctx, namespace, name))
+		},
+		create: (*APIServer).createServiceHandlerGin,
+		update: (*APIServer).updateServiceHandlerGin,
+	}
+)
+
+// orNil keeps a nil object from a failed get out of a non-nil interface.
+func orNil[T metaObject](obj T, err error) (metaObject, error) {
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Gin handler for PATCH on an object: a server-side apply, a JSON merge patch, or a
+// JSON patch, as the Content-Type says.
+func (s *APIServer) patchHandlerGin(r applyResource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contentType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		switch patchType := api.PatchType(contentType); patchType {
+		case api.ApplyPatchContentType:
+			s.apply(c, r)
+		case api.MergePatchType, api.JSONPatchType:
+			s.patch(c, r, patchType)
+		default:
+			c.JSON(415, gin.H{"error": fmt.Sprintf("Unsupported patch Content-Type %q; use %s, %s, or %s", contentType, api.ApplyPatchContentType, api.MergePatchType, api.JSONPatchType)})
+		}
+	}
+}
+
+// apply handles a server-side apply of the object named in the URL. The body is the
+// complete configuration the fieldManager wants, in YAML or JSON. It is merged into
+// the live object as fieldmanager.Apply describes, and the result is handed to the
+// kind's create or update handler, so it is defaulted, validated, and stored exactly
+// as a POST or PUT would be.
+func (s *APIServer) apply(c *gin.Context, r applyResource) {
+	ctx := c.Request.Context()
+	manager := c.Query("fieldManager")
+	if manager == "" {
+		c.JSON(422, gin.H{"error": "fieldManager is required for apply"})
+		return
+	}
+	force := c.Query("force") == "true"
+
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid apply configuration: " + err.Error()})
+		return
+	}
+	delete(doc, "kind")
+	delete(doc, "apiVersion")
+	applied, err := toFields(doc) // Numbers as JSON has them, to compare with live objects
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid apply configuration: " + err.Error()})
+		return
+	}
+
+	name := c.Param(r.nameParam)
+	namespace := ""
+	if r.namespaced {
+		namespace = c.Param("namespace")
+	}
+	for field, want := range map[string]string{"name": name, "namespace": namespace} {
+		if got, ok := applied[field]; ok && got != want {
+			c.JSON(400, gin.H{"error": fmt.Sprintf("%s %s in body (%v) does not match URL (%s)", r.kind, field, got, want)})
+			return
+		}
+	}
+	applied["name"] = name
+	if r.namespaced {
+		applied["namespace"] = namespace
+	}
+	if err := decodeFields(applied, r.newObject(), true); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid apply configuration: " + err.Error()})
+		return
+	}
+
+	live, err := r.get(ctx, s.store, namespace, name)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to apply %s: %v", strings.ToLower(r.kind), err)})
+		return
+	}
+	var liveFields, before map[string]interface{}
+	var managed []api.ManagedFieldsEntry
+	if live != nil {
+		if liveFields, err = toFields(live); err == nil {
+			before, err = toFields(live)
+		}
+		if err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to apply %s: %v", strings.ToLower(r.kind), err)})
+			return
+		}
+		managed = live.GetObjectMeta().ManagedFields
+	}
+
+	merged, managed, err := fieldmanager.Apply(liveFields, managed, applied, manager, force, time.Now())
+	var conflictErr *fieldmanager.ConflictError
+	if errors.As(err, &conflictErr) {
+		c.JSON(409, gin.H{"error": conflictErr.Error(), "causes": conflictErr.Conflicts})
+		return
+	}
+	merged["name"] = name // Nobody owns the name, so Apply leaves it to us
+	if r.namespaced {
+		merged["namespace"] = namespace
+	}
+	if live != nil && unchangedByApply(before, merged, live.GetObjectMeta().ManagedFields, managed) {
+		c.JSON(200, live) // Don't bump the resourceVersion of an object nothing changed in
+		return
+	}
+
+	obj := r.newObject()
+	if err := decodeFields(merged, obj, false); err != nil {
+		c.JSON(422, gin.H{"error": fmt.Sprintf("%s %q is invalid: %v", r.kind, name, err)})
+		return
+	}
+	body, err := json.Marshal(obj)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to apply %s: %v", strings.ToLower(r.kind), err)})
+		return
+	}
+	c.Set(managedFieldsKey, managed)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if live == nil {
+		r.create(s, c)
+	} else {
+		r.update(s, c)
+	}
+}
+
+// unchangedByApply reports whether an apply left both the object and who owns which
+// of its fields as they were.
+func unchangedByApply(before, after map[string]interface{}, oldManaged, newManaged []api.ManagedFieldsEntry) bool {
+	delete(before, "managedFields")
+	delete(after, "managedFields")
+	if !jsonEqual(before, after) || len(oldManaged) != len(newManaged) {
+		return false
+	}
+	for i := range oldManaged {
+		o, n := oldManaged[i], newManaged[i]
+		if o.Manager != n.Manager || o.Operation != n.Operation || strings.Join(o.Fields, "\n") != strings.Join(n.Fields, "\n") {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ra, errA := json.Marshal(a)
+	rb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ra, rb)
+}
+
+// trackManagedFields sets the managed fields of obj, about to be written over old (nil
+// for a create): those computed by the apply that handed over the write, or else the
+// request's field manager takes over every field it changed.
+func (s *APIServer) trackManagedFields(c *gin.Context, old, obj metaObject) {
+	meta := obj.GetObjectMeta()
+	if managed, ok := c.Get(managedFieldsKey); ok {
+		meta.ManagedFields = managed.([]api.ManagedFieldsEntry)
+		return
+	}
+	var oldFields map[string]interface{}
+	var managed []api.ManagedFieldsEntry
+	if old != nil {
+		oldFields, _ = toFields(old)
+		managed = old.GetObjectMeta().ManagedFields
+	}
+	newFields, _ := toFields(obj)
+	meta.ManagedFields = fieldmanager.Update(oldFields, newFields, managed, fieldManagerFor(c), time.Now())
+}
+
+// fieldManagerFor names the manager of a write: the fieldManager query parameter, or
+// else the product in the client's User-Agent, such as "kubectl-lite".
+func fieldManagerFor(c *gin.Context) string {
+	if manager := c.Query("fieldManager"); manager != "" {
+		return manager
+	}
+	product := userAgentProduct(c)
+	if product == "" {
+		return "unknown"
+	}
+	return product
+}
+
+// toFields converts obj to its generic JSON form.
+func toFields(obj interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// decodeFields decodes generic fields into obj; strict rejects unknown fields.
+func decodeFields(fields map[string]interface{}, obj interface{}, strict bool) error {
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(obj)
+}
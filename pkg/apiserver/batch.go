@@ -0,0 +1,55 @@
+package apiserver
+
+import (
+	"fmt"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// maxBatchSize is the most objects a single batch request may create.
+const maxBatchSize = 500
+
+// routeKey is the gin context key under which a handler may name the route it served
+// more precisely than c.FullPath(), for metrics.
+const routeKey = "route"
+
+// Gin handler for POST /api/v1/namespaces/:namespace/<resource>:<action>. Gin takes a
+// ':' to start a path parameter, so these routes can't be registered directly and are
+// dispatched from here instead.
+func (s *APIServer) collectionActionHandlerGin(c *gin.Context) {
+	action := c.Param("collectionAction")
+	switch action {
+	case "pods:batch":
+		c.Set(routeKey, "/api/v1/namespaces/:namespace/"+action)
+		s.batchCreatePodsHandlerGin(c)
+	default:
+		c.JSON(404, gin.H{"error": fmt.Sprintf("No such resource or action: %s", action)})
+	}
+}
+
+// Gin handler for creating a batch of pods in one request. Each pod is created as a
+// POST of it alone would be, and the response holds one result per pod, in order, so
+// some may fail while the rest are created.
+func (s *APIServer) batchCreatePodsHandlerGin(c *gin.Context) {
+	var pods []api.Pod
+	if err := c.ShouldBindJSON(&pods); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if len(pods) > maxBatchSize {
+		c.JSON(413, gin.H{"error": fmt.Sprintf("A batch may create at most %d pods, got %d", maxBatchSize, len(pods))})
+		return
+	}
+
+	results := make([]api.PodBatchResult, len(pods))
+	for i := range pods {
+		code, body := s.createPod(c, &pods[i])
+		if body != nil {
+			results[i] = api.PodBatchResult{Code: code, Error: fmt.Sprint(body["error"])}
+			continue
+		}
+		results[i] = api.PodBatchResult{Code: code, Pod: &pods[i]}
+	}
+	c.JSON(200, results)
+}
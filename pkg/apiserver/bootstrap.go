@@ -0,0 +1,74 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+)
+
+// systemNamespaces are the namespaces every cluster has. The first three may not be
+// deleted.
+var systemNamespaces = []string{DefaultNamespace, api.SystemNamespace, api.PublicNamespace, api.NodeLeaseNamespace}
+
+// bootstrap creates what every cluster starts with, unless it already exists: the
+// system namespaces, then the cluster-info config map. It runs on every start, so a
+// store journaled by an older API server gains what it lacks, and nothing is created
+// twice.
+//
+// Built-in roles belong here too, once the API server authorizes requests, as do the
+// admin, edit, and view role bindings createNamespaceHandlerGin should then give every
+// new namespace. Until requests carry a user there is nothing for them to grant.
+func bootstrap(ctx context.Context, dataStore store.Store) error {
+	for _, name := range systemNamespaces {
+		if _, err := dataStore.GetNamespace(ctx, name); err == nil {
+			continue
+		}
+		if err := dataStore.CreateNamespace(ctx, &api.Namespace{ObjectMeta: api.ObjectMeta{Name: name}, Phase: api.NamespaceActive}); err != nil {
+			return fmt.Errorf("creating %s namespace: %w", name, err)
+		}
+		log.Printf("Bootstrap: created namespace %s", name)
+	}
+	if _, err := dataStore.GetConfigMap(ctx, api.PublicNamespace, api.ClusterInfoConfigMap); err != nil {
+		clusterInfo := &api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: api.ClusterInfoConfigMap, Namespace: api.PublicNamespace}}
+		if err := dataStore.CreateConfigMap(ctx, clusterInfo); err != nil {
+			return fmt.Errorf("creating %s config map: %w", api.ClusterInfoConfigMap, err)
+		}
+		log.Printf("Bootstrap: created config map %s/%s", api.PublicNamespace, api.ClusterInfoConfigMap)
+	}
+	return nil
+}
+
+// immortalNamespace reports whether the namespace name may not be deleted.
+func immortalNamespace(name string) bool {
+	return name == DefaultNamespace || name == api.SystemNamespace || name == api.PublicNamespace
+}
+
+// publishClusterInfo records addr, the address the server listens on, as the "server"
+// of the cluster-info config map. An unspecified host, as in ":8080", is published as
+// localhost.
+func (s *APIServer) publishClusterInfo(ctx context.Context, addr net.Addr) error {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	server := "http://" + net.JoinHostPort(host, port)
+	clusterInfo, err := s.store.GetConfigMap(ctx, api.PublicNamespace, api.ClusterInfoConfigMap)
+	if err != nil {
+		return err
+	}
+	if clusterInfo.Data["server"] == server {
+		return nil
+	}
+	if clusterInfo.Data == nil {
+		clusterInfo.Data = make(map[string]string)
+	}
+	clusterInfo.Data["server"] = server
+	return s.store.UpdateConfigMap(ctx, clusterInfo)
+}
@@ -0,0 +1,26 @@
+package apiserver
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/gin-gonic/gin"
+)
+
+// chaosMiddleware delays and fails API requests as inj decides, so clients have to
+// cope with a slow or flaky API server.
+func chaosMiddleware(inj *chaos.Injector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d := inj.APIDelay(); d > 0 {
+			time.Sleep(d)
+		}
+		if inj.FailAPIRequest() {
+			log.Printf("Chaos: failing %s %s", c.Request.Method, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "chaos: injected internal error"})
+			return
+		}
+		c.Next()
+	}
+}
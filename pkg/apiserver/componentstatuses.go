@@ -0,0 +1,67 @@
+package apiserver
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// missedHeartbeats is how many heartbeat periods a component may go without reporting
+// before it is considered unhealthy.
+const missedHeartbeats = 3
+
+// componentHealth fills in status.Healthy and status.Message as of now.
+func componentHealth(status *api.ComponentStatus, now time.Time) {
+	period := time.Duration(status.HeartbeatPeriodSeconds) * time.Second
+	if period < time.Second {
+		period = time.Second
+	}
+	silent := now.Sub(status.LastHeartbeatTime)
+	status.Healthy = silent <= missedHeartbeats*period
+	status.Message = ""
+	if !status.Healthy {
+		status.Message = fmt.Sprintf("no heartbeat for %v", silent.Round(time.Second))
+	}
+}
+
+// Gin handler for a component's heartbeat. The server stamps the time itself, so
+// components' clocks don't need to agree with it.
+func (s *APIServer) reportComponentStatusHandlerGin(c *gin.Context) {
+	var status api.ComponentStatus
+	if err := c.ShouldBindJSON(&status); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	status.Name = c.Param("name")
+	if status.HeartbeatPeriodSeconds < 0 {
+		c.JSON(422, gin.H{"error": "heartbeatPeriodSeconds must not be negative"})
+		return
+	}
+	if isDryRun(c) {
+		c.Status(200)
+		return
+	}
+	status.LastHeartbeatTime = time.Now()
+
+	s.componentsMu.Lock()
+	s.components[status.Name] = status
+	s.componentsMu.Unlock()
+	c.Status(200)
+}
+
+// Gin handler for listing the health of every component that has reported, by name.
+func (s *APIServer) listComponentStatusesHandlerGin(c *gin.Context) {
+	now := time.Now()
+	s.componentsMu.Lock()
+	statuses := make([]api.ComponentStatus, 0, len(s.components))
+	for _, status := range s.components {
+		componentHealth(&status, now)
+		statuses = append(statuses, status)
+	}
+	s.componentsMu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	c.JSON(200, statuses)
+}
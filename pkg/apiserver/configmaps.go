@@ -0,0 +1,146 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a config map
+func (s *APIServer) createConfigMapHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var configMap api.ConfigMap
+	if err := c.ShouldBindJSON(&configMap); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	configMap.Namespace = c.Param("namespace")
+	if configMap.Namespace == "" {
+		configMap.Namespace = DefaultNamespace
+	}
+	if s.rejectInvalid(c, "ConfigMap", configMap.Name, validation.Validate_ConfigMap(&configMap)) {
+		return
+	}
+	s.trackManagedFields(c, nil, &configMap)
+
+	if body := s.terminatingNamespace(c, "ConfigMap", configMap.Name, configMap.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetConfigMap(ctx, configMap.Namespace, configMap.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create configmap: configmap %s in namespace %s already exists", configMap.Name, configMap.Namespace)})
+			return
+		}
+		c.JSON(201, configMap)
+		return
+	}
+
+	if err := s.store.CreateConfigMap(ctx, &configMap); err != nil {
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create configmap: " + err.Error()})
+		} else {
+			log.Printf("Error creating configmap %s/%s in store: %v", configMap.Namespace, configMap.Name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create configmap: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created configmap %s/%s", configMap.Namespace, configMap.Name)
+	c.JSON(201, configMap)
+}
+
+// Gin handler for getting a specific config map
+func (s *APIServer) getConfigMapHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	configMap, err := s.store.GetConfigMap(ctx, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get configmap: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(configMap), configMap)
+}
+
+// Gin handler for listing config maps in a namespace
+func (s *APIServer) listConfigMapsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	configMaps, err := s.store.ListConfigMaps(ctx, c.Param("namespace"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list configmaps: " + err.Error()})
+		return
+	}
+	respondWithList(c, configMaps)
+}
+
+// Gin handler for updating a specific config map
+func (s *APIServer) updateConfigMapHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var configMap api.ConfigMap
+	if err := c.ShouldBindJSON(&configMap); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if configMap.Name != name || configMap.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("ConfigMap %s/%s in body does not match URL (%s/%s)", configMap.Namespace, configMap.Name, namespace, name)})
+		return
+	}
+	if s.rejectInvalid(c, "ConfigMap", configMap.Name, validation.Validate_ConfigMap(&configMap)) {
+		return
+	}
+	existing, err := s.store.GetConfigMap(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update configmap: " + err.Error()})
+		return
+	}
+	s.trackManagedFields(c, existing, &configMap)
+
+	if isDryRun(c) {
+		c.JSON(200, configMap)
+		return
+	}
+
+	if err := s.store.UpdateConfigMap(ctx, &configMap); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update configmap: " + err.Error()})
+		} else {
+			log.Printf("Failed to update configmap in store: %v", err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update configmap: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, configMap)
+}
+
+// Gin handler for deleting a specific config map
+func (s *APIServer) deleteConfigMapHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.store.GetConfigMap(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete configmap: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("ConfigMap %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	if err := s.store.DeleteConfigMap(ctx, namespace, name); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete configmap: " + err.Error()})
+		} else {
+			log.Printf("Error deleting configmap %s/%s from store: %v", namespace, name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete configmap: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted configmap %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("ConfigMap %s/%s deleted", namespace, name)})
+}
@@ -0,0 +1,176 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a deployment
+func (s *APIServer) createDeploymentHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	var d api.Deployment
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	d.Namespace = namespace
+	if d.Namespace == "" {
+		d.Namespace = DefaultNamespace
+	}
+	validation.SetDefaults_Deployment(&d)
+	if s.rejectInvalid(c, "Deployment", d.Name, validation.Validate_Deployment(&d)) {
+		return
+	}
+	s.trackManagedFields(c, nil, &d)
+
+	if body := s.terminatingNamespace(c, "Deployment", d.Name, d.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetDeployment(ctx, d.Namespace, d.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create deployment: deployment %s in namespace %s already exists", d.Name, d.Namespace)})
+			return
+		}
+		c.JSON(201, d)
+		return
+	}
+
+	if err := s.store.CreateDeployment(ctx, &d); err != nil {
+		log.Printf("Error creating deployment %s/%s in store: %v", d.Namespace, d.Name, err)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create deployment: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create deployment: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created deployment %s/%s", d.Namespace, d.Name)
+	c.JSON(201, d)
+}
+
+// Gin handler for getting a specific deployment
+func (s *APIServer) getDeploymentHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	d, err := s.store.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get deployment: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(d), d)
+}
+
+// Gin handler for listing deployments in a namespace
+func (s *APIServer) listDeploymentsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	deployments, err := s.store.ListDeployments(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list deployments: " + err.Error()})
+		return
+	}
+	respondWithList(c, deployments)
+}
+
+// Gin handler for updating a specific deployment
+func (s *APIServer) updateDeploymentHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var d api.Deployment
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if d.Name != name || d.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Deployment %s/%s in body does not match URL (%s/%s)", d.Namespace, d.Name, namespace, name)})
+		return
+	}
+	validation.SetDefaults_Deployment(&d)
+	if s.rejectInvalid(c, "Deployment", d.Name, validation.Validate_Deployment(&d)) {
+		return
+	}
+	existing, err := s.store.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update deployment: " + err.Error()})
+		return
+	}
+	s.trackManagedFields(c, existing, &d)
+
+	if isDryRun(c) {
+		c.JSON(200, d)
+		return
+	}
+
+	if err := s.store.UpdateDeployment(ctx, &d); err != nil {
+		log.Printf("Failed to update deployment in store: %v", err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update deployment: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update deployment: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, d)
+}
+
+// Gin handler for deleting a specific deployment
+func (s *APIServer) deleteDeploymentHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	policy, err := propagationPolicy(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetDeployment(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete deployment: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Deployment %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	deleteNow := true
+	err = s.store.Txn(ctx, func(tx store.StoreTxn) error {
+		if existing, err := tx.GetDeployment(ctx, namespace, name); err == nil {
+			deleteNow, err = propagateDeletion(ctx, tx, policy, existing.ObjectMeta, func(meta api.ObjectMeta) error {
+				updated := *existing
+				updated.ObjectMeta = meta
+				return tx.UpdateDeployment(ctx, &updated)
+			})
+			if err != nil || !deleteNow {
+				return err
+			}
+		}
+		return tx.DeleteDeployment(ctx, namespace, name)
+	})
+	if err != nil {
+		log.Printf("Error deleting deployment %s/%s from store: %v", namespace, name, err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete deployment: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete deployment: " + err.Error()})
+		}
+		return
+	}
+	if !deleteNow {
+		log.Printf("Marked deployment %s/%s for foreground deletion", namespace, name)
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Deployment %s/%s marked for deletion; waiting for its dependents", namespace, name)})
+		return
+	}
+	log.Printf("Deleted deployment %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Deployment %s/%s deleted", namespace, name)})
+}
@@ -0,0 +1,124 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// registerDiscovery serves api.Resources, with the verbs the routes registered on
+// router so far offer on them: /api lists the core group's versions, /apis the named
+// groups, and /api/v1 and /apis/<group>/<version> the resources of each group version.
+// A route for a resource missing from api.Resources is served but not discovered.
+// /apis also lists the groups that aggregated returns, those of aggregated API
+// servers, which serve their own group versions' resource lists.
+func registerDiscovery(router *gin.Engine, aggregated func(context.Context) ([]api.APIGroup, error)) {
+	verbs := routeVerbs(router.Routes())
+
+	lists := make(map[string]*api.APIResourceList)
+	var groupVersions []string
+	for _, r := range api.Resources {
+		list, ok := lists[r.GroupVersion]
+		if !ok {
+			list = &api.APIResourceList{GroupVersion: r.GroupVersion}
+			lists[r.GroupVersion] = list
+			groupVersions = append(groupVersions, r.GroupVersion)
+		}
+		resourceVerbs := make([]string, 0, len(verbs[r.GroupVersion+"/"+r.Name]))
+		for verb := range verbs[r.GroupVersion+"/"+r.Name] {
+			resourceVerbs = append(resourceVerbs, verb)
+		}
+		sort.Strings(resourceVerbs)
+		list.Resources = append(list.Resources, api.APIResource{
+			Name:         r.Name,
+			SingularName: r.SingularName,
+			Namespaced:   r.Namespaced,
+			Kind:         r.Kind,
+			Verbs:        resourceVerbs,
+			ShortNames:   r.ShortNames,
+		})
+	}
+
+	versions := api.APIVersions{}
+	groups := api.APIGroupList{Groups: []api.APIGroup{}}
+	for _, gv := range groupVersions {
+		list := lists[gv]
+		group, version, named := strings.Cut(gv, "/")
+		if !named {
+			versions.Versions = append(versions.Versions, gv)
+			router.GET("/api/"+gv, func(c *gin.Context) { c.JSON(http.StatusOK, list) })
+			continue
+		}
+		v := api.GroupVersionForDiscovery{GroupVersion: gv, Version: version}
+		groups.Groups = append(groups.Groups, api.APIGroup{Name: group, Versions: []api.GroupVersionForDiscovery{v}, PreferredVersion: v})
+		router.GET("/apis/"+gv, func(c *gin.Context) { c.JSON(http.StatusOK, list) })
+	}
+	router.GET("/api", func(c *gin.Context) { c.JSON(http.StatusOK, versions) })
+	router.GET("/apis", func(c *gin.Context) {
+		extra, err := aggregated(c.Request.Context())
+		if err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list aggregated API groups: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, api.APIGroupList{Groups: append(groups.Groups[:len(groups.Groups):len(groups.Groups)], extra...)})
+	})
+}
+
+// routeVerbs returns the verbs routes offer on each resource, keyed by
+// "<groupVersion>/<resource>". Subresources such as pods/log are left out.
+func routeVerbs(routes gin.RoutesInfo) map[string]map[string]bool {
+	verbs := make(map[string]map[string]bool)
+	for _, route := range routes {
+		segments := strings.Split(strings.Trim(route.Path, "/"), "/")
+		var groupVersion string
+		switch {
+		case segments[0] == "api" && len(segments) > 2:
+			groupVersion, segments = segments[1], segments[2:]
+		case segments[0] == "apis" && len(segments) > 3:
+			groupVersion, segments = segments[1]+"/"+segments[2], segments[3:]
+		default:
+			continue
+		}
+		if len(segments) > 2 && segments[0] == "namespaces" && segments[1] == ":namespace" {
+			segments = segments[2:]
+		}
+		if strings.HasPrefix(segments[0], ":") {
+			continue
+		}
+
+		var verb string
+		switch collection := len(segments) == 1; {
+		case len(segments) > 2:
+			continue
+		case collection && route.Method == http.MethodGet:
+			verb = "list"
+		case collection && route.Method == http.MethodPost:
+			verb = "create"
+		case collection && route.Method == http.MethodDelete:
+			verb = "deletecollection"
+		case route.Method == http.MethodGet:
+			verb = "get"
+		case route.Method == http.MethodPut:
+			verb = "update"
+		case route.Method == http.MethodPatch:
+			verb = "patch"
+		case route.Method == http.MethodDelete:
+			verb = "delete"
+		default:
+			continue
+		}
+		key := groupVersion + "/" + segments[0]
+		if verbs[key] == nil {
+			verbs[key] = make(map[string]bool)
+		}
+		verbs[key][verb] = true
+		if verb == "list" && strings.Contains(route.Handler, ".watchable[") {
+			verbs[key]["watch"] = true
+		}
+	}
+	return verbs
+}
@@ -0,0 +1,11 @@
+package apiserver
+
+import "github.com/gin-gonic/gin"
+
+// isDryRun reports whether the request carries ?dryRun=true (or kubectl's dryRun=All).
+// Dry-run requests go through the same defaulting and validation as real ones, and get
+// the same response, but nothing is written to the store.
+func isDryRun(c *gin.Context) bool {
+	v := c.Query("dryRun")
+	return v == "true" || v == "All"
+}
@@ -0,0 +1,25 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+)
+
+// storeErrorCode maps an error from the store to a status code: 404 for an object
+// that doesn't exist, 409 for one that already does or a write that conflicts with
+// the stored object, 504 for a store call cut off by the request's timeout, and 500
+// for anything else.
+func storeErrorCode(err error) int {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrAlreadyExists), errors.Is(err, store.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
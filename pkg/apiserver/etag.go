@@ -0,0 +1,57 @@
+package apiserver
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// metaObject is implemented by every API object, through its embedded ObjectMeta.
+type metaObject interface {
+	GetObjectMeta() *api.ObjectMeta
+}
+
+// objectETag is the ETag of a single object: its resourceVersion, which changes on
+// every write to it.
+func objectETag(obj metaObject) string {
+	return strconv.Quote(obj.GetObjectMeta().ResourceVersion)
+}
+
+// listETag is the ETag of a list of objects. It hashes every item's name and
+// resourceVersion, so it changes when any item is added, removed, or written. Items
+// are hashed in the order given, which respondWithList keeps stable.
+func listETag[T metaObject](items []T) string {
+	h := fnv.New64a()
+	for _, item := range items {
+		meta := item.GetObjectMeta()
+		fmt.Fprintf(h, "%s/%s/%s\n", meta.Namespace, meta.Name, meta.ResourceVersion)
+	}
+	return fmt.Sprintf(`"list-%x"`, h.Sum64())
+}
+
+// respondWithETag writes obj with the given ETag, or just 304 Not Modified if the
+// request's If-None-Match says the client already has it.
+func respondWithETag(c *gin.Context, etag string, obj interface{}) {
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, obj)
+}
+
+// etagMatches reports whether an If-None-Match header names etag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
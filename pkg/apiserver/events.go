@@ -0,0 +1,209 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultEventTTL is how long an event is kept after it last happened, and
+// DefaultMaxEventsPerNamespace how many events each namespace keeps at most.
+const (
+	DefaultEventTTL              = time.Hour
+	DefaultMaxEventsPerNamespace = 1000
+)
+
+// eventExpiryInterval is how often the server deletes expired events in every
+// namespace, besides those it deletes as it records new events.
+const eventExpiryInterval = time.Minute
+
+func (s *APIServer) events() *store.Registry[*api.Event] {
+	return store.RegistryFor[*api.Event](s.store, store.Events)
+}
+
+// sameEvent reports whether b is a repeat of a: the same thing happening to the same
+// object, as reported by the same component.
+func sameEvent(a, b *api.Event) bool {
+	return a.InvolvedObject == b.InvolvedObject &&
+		a.Type == b.Type &&
+		a.Reason == b.Reason &&
+		a.Message == b.Message &&
+		a.Source == b.Source
+}
+
+// Gin handler for creating an event. A repeat of an existing event bumps that event's
+// Count and LastTimestamp instead of creating a new object, and creating one may
+// delete others to keep within EventTTL and MaxEventsPerNamespace.
+func (s *APIServer) createEventHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	var event api.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	event.Namespace = namespace
+	if event.Namespace == "" {
+		event.Namespace = DefaultNamespace
+	}
+	validation.SetDefaults_Event(&event)
+	if s.rejectInvalid(c, "Event", event.Name, validation.Validate_Event(&event)) {
+		return
+	}
+	created, err := s.recordEvent(ctx, &event)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create event: " + err.Error()})
+		return
+	}
+	if !created {
+		c.JSON(200, event)
+		return
+	}
+	c.JSON(201, event)
+}
+
+// recordEvent stores event, or, if it repeats an existing event, bumps that event's
+// Count and LastTimestamp instead, leaving the stored event in event either way. It
+// reports whether it created a new event. Expired events in the namespace are deleted
+// first, so they are never bumped, and a new event that would take the namespace past
+// MaxEventsPerNamespace makes room by deleting the events least recently seen.
+func (s *APIServer) recordEvent(ctx context.Context, event *api.Event) (created bool, err error) {
+	now := time.Now()
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = now
+	}
+
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+
+	existing, err := s.store.ListEvents(ctx, event.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if existing, err = s.pruneEvents(ctx, existing, now, 0); err != nil {
+		return false, err
+	}
+	for _, e := range existing {
+		if !sameEvent(e, event) {
+			continue
+		}
+		updated := *e
+		updated.Count++
+		updated.LastTimestamp = event.LastTimestamp
+		if err := s.store.UpdateEvent(ctx, &updated); err != nil {
+			return false, err
+		}
+		*event = updated
+		return false, nil
+	}
+
+	if event.Name == "" {
+		prefix := event.InvolvedObject.Name
+		if prefix == "" {
+			prefix = strings.ToLower(event.InvolvedObject.Kind) // Rejected for having no name
+		}
+		event.Name = fmt.Sprintf("%s.%x", prefix, now.UnixNano())
+	}
+	if event.FirstTimestamp.IsZero() {
+		event.FirstTimestamp = event.LastTimestamp
+	}
+	event.Count = 1
+
+	if _, err := s.pruneEvents(ctx, existing, now, 1); err != nil {
+		return false, err
+	}
+	if err := s.store.CreateEvent(ctx, event); err != nil {
+		log.Printf("Error creating event %s/%s in store: %v", event.Namespace, event.Name, err)
+		return false, err
+	}
+	return true, nil
+}
+
+// pruneEvents deletes the events in events, all from one namespace, that were last
+// seen more than EventTTL before now, and then, to leave room for room more events
+// within MaxEventsPerNamespace, those seen least recently. It returns the events
+// left. eventsMu must be held.
+func (s *APIServer) pruneEvents(ctx context.Context, events []*api.Event, now time.Time, room int) ([]*api.Event, error) {
+	var kept, expired []*api.Event
+	for _, e := range events {
+		if s.EventTTL > 0 && now.Sub(e.LastTimestamp) > s.EventTTL {
+			expired = append(expired, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if excess := len(kept) + room - s.MaxEventsPerNamespace; s.MaxEventsPerNamespace > 0 && excess > 0 {
+		sort.SliceStable(kept, func(i, j int) bool { return kept[i].LastTimestamp.Before(kept[j].LastTimestamp) })
+		excess = min(excess, len(kept))
+		expired, kept = append(expired, kept[:excess]...), kept[excess:]
+	}
+	for _, e := range expired {
+		if err := s.events().Delete(ctx, e.Namespace, e.Name); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return kept, nil
+}
+
+// expireEvents deletes expired events in every namespace every eventExpiryInterval
+// until ctx is cancelled, so that events no one repeats or adds to don't linger.
+func (s *APIServer) expireEvents(ctx context.Context) {
+	if s.EventTTL <= 0 {
+		return
+	}
+	ticker := time.NewTicker(min(eventExpiryInterval, s.EventTTL))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.expireEventsOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Error expiring events: %v", err)
+		}
+	}
+}
+
+// expireEventsOnce deletes the events in every namespace last seen more than EventTTL
+// before now.
+func (s *APIServer) expireEventsOnce(ctx context.Context, now time.Time) error {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	events, err := s.store.ListEvents(ctx, api.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	byNamespace := make(map[string][]*api.Event)
+	for _, e := range events {
+		byNamespace[e.Namespace] = append(byNamespace[e.Namespace], e)
+	}
+	for _, events := range byNamespace {
+		if _, err := s.pruneEvents(ctx, events, now, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Gin handler for listing events in a namespace
+func (s *APIServer) listEventsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	events, err := s.store.ListEvents(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list events: " + err.Error()})
+		return
+	}
+	respondWithList(c, events)
+}
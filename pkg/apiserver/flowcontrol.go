@@ -0,0 +1,251 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxRequestsInFlight is how many requests the API server runs at once, across
+// all priority levels, unless configured otherwise.
+const DefaultMaxRequestsInFlight = 400
+
+// queuedPerSeat bounds how many requests may wait for a priority level, per request it
+// runs at once; any more are rejected with 429 at once.
+const queuedPerSeat = 4
+
+// maxQueueWait is how long a request waits for a seat before it is rejected with 429.
+const maxQueueWait = 5 * time.Second
+
+// Priority levels. Each gets its shares of the seats, the requests it may run at once,
+// so that a flood of requests at one level can't take the seats of another.
+const (
+	// priorityNodeHigh is for node status updates, leases, kubelets' pod status
+	// updates, and component heartbeats: what keeps nodes Ready and leaders elected.
+	priorityNodeHigh = "node-high"
+	// priorityWorkload is for every other get and write.
+	priorityWorkload = "workload"
+	// priorityBulk is for lists, which cost the most and can wait the longest.
+	priorityBulk = "bulk"
+)
+
+var priorityLevels = []struct {
+	name   string
+	shares int
+}{
+	{priorityNodeHigh, 40},
+	{priorityWorkload, 40},
+	{priorityBulk, 20},
+}
+
+// flowController queues requests by priority level and, within a level, by flow: the
+// client sending them, by User-Agent and address. When a level's seats are taken,
+// its waiting flows are served in turn, one request each, so a client sending many
+// requests can't crowd out one sending a few.
+type flowController struct {
+	mu     sync.Mutex
+	levels map[string]*priorityLevel
+}
+
+type priorityLevel struct {
+	seats     int // Requests the level runs at once
+	executing int
+	queued    int
+	rejected  uint64
+
+	queues map[string][]chan struct{} // Waiting requests by flow, oldest first; each is closed when given a seat
+	flows  []string                   // Flows with waiting requests, in the order they are served
+	next   int                        // Index in flows of the next flow to serve
+}
+
+// newFlowController divides maxInFlight seats between the priority levels by their
+// shares, giving each at least one.
+func newFlowController(maxInFlight int) *flowController {
+	total := 0
+	for _, l := range priorityLevels {
+		total += l.shares
+	}
+	fc := &flowController{levels: make(map[string]*priorityLevel)}
+	for _, l := range priorityLevels {
+		fc.levels[l.name] = &priorityLevel{seats: max(maxInFlight*l.shares/total, 1), queues: make(map[string][]chan struct{})}
+	}
+	return fc
+}
+
+// acquire takes a seat at level for a request of flow, waiting its turn if the level's
+// seats are taken, and returns the func that gives the seat back. It returns false if
+// the level's queue is full, or the request waited longer than maxQueueWait or ctx
+// ended first.
+func (fc *flowController) acquire(ctx context.Context, level, flow string) (release func(), ok bool) {
+	fc.mu.Lock()
+	l := fc.levels[level]
+	release = func() { fc.release(l) }
+	if l.executing < l.seats && l.queued == 0 {
+		l.executing++
+		fc.mu.Unlock()
+		return release, true
+	}
+	if l.queued >= l.seats*queuedPerSeat {
+		l.rejected++
+		fc.mu.Unlock()
+		return nil, false
+	}
+	ready := make(chan struct{})
+	if len(l.queues[flow]) == 0 {
+		l.flows = append(l.flows, flow)
+	}
+	l.queues[flow] = append(l.queues[flow], ready)
+	l.queued++
+	fc.mu.Unlock()
+
+	timer := time.NewTimer(maxQueueWait)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return release, true
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	select {
+	case <-ready:
+		// Given a seat just as it gave up: take it rather than leak it.
+		return release, true
+	default:
+	}
+	l.dequeue(flow, ready)
+	l.rejected++
+	return nil, false
+}
+
+// release gives back a seat at l, handing it to the next flow's oldest request.
+func (fc *flowController) release(l *priorityLevel) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	l.executing--
+	if len(l.flows) == 0 {
+		return
+	}
+	l.next %= len(l.flows)
+	flow := l.flows[l.next]
+	ready := l.queues[flow][0]
+	l.dequeue(flow, ready)
+	if len(l.queues[flow]) > 0 {
+		l.next++ // Otherwise dequeue dropped the flow, and the next one moved up
+	}
+	l.executing++
+	close(ready)
+}
+
+// dequeue removes a waiting request of flow, and the flow once it has none left. The
+// caller must hold the flow controller's lock.
+func (l *priorityLevel) dequeue(flow string, ready chan struct{}) {
+	queue := l.queues[flow]
+	for i, r := range queue {
+		if r == ready {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	l.queued--
+	if len(queue) > 0 {
+		l.queues[flow] = queue
+		return
+	}
+	delete(l.queues, flow)
+	for i, f := range l.flows {
+		if f == flow {
+			l.flows = append(l.flows[:i], l.flows[i+1:]...)
+			if i < l.next {
+				l.next--
+			}
+			break
+		}
+	}
+}
+
+// writeTo renders the seats, executing and queued requests, and rejections of each
+// priority level in the Prometheus text exposition format.
+func (fc *flowController) writeTo(w io.Writer) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	for _, m := range []struct {
+		name, help, typ string
+		value           func(l *priorityLevel) uint64
+	}{
+		{"apiserver_flowcontrol_nominal_limit_seats", "Requests each priority level may run at once.", "gauge", func(l *priorityLevel) uint64 { return uint64(l.seats) }},
+		{"apiserver_flowcontrol_current_executing_requests", "Requests running, by priority level.", "gauge", func(l *priorityLevel) uint64 { return uint64(l.executing) }},
+		{"apiserver_flowcontrol_current_inqueue_requests", "Requests waiting for a seat, by priority level.", "gauge", func(l *priorityLevel) uint64 { return uint64(l.queued) }},
+		{"apiserver_flowcontrol_rejected_requests_total", "Requests rejected with 429 because their priority level's queue was full or they waited too long.", "counter", func(l *priorityLevel) uint64 { return l.rejected }},
+	} {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.typ)
+		for _, level := range priorityLevels {
+			fmt.Fprintf(w, "%s{priority_level=%q} %d\n", m.name, level.name, m.value(fc.levels[level.name]))
+		}
+	}
+}
+
+// priorityLevelFor classifies a request. exempt is true for what flow control leaves
+// alone: watches and streams, which hold no seat for their short setup, and requests
+// outside the API, such as health checks and /metrics, which must answer even when
+// the API is overloaded.
+func priorityLevelFor(c *gin.Context) (level string, exempt bool) {
+	key, ok := requestKeyFor(c.Request.Method, c.FullPath())
+	if !ok || isLongRunning(c) {
+		return "", true
+	}
+	switch {
+	case key.verb == "list":
+		return priorityBulk, false
+	case key.resource == "nodes" || key.resource == "leases" || key.resource == "componentstatuses":
+		return priorityNodeHigh, false
+	case key.resource == "pods" && key.verb == "update" && userAgentProduct(c) == "kubelet":
+		return priorityNodeHigh, false
+	}
+	return priorityWorkload, false
+}
+
+// flowFor names the client a request came from, for fair queuing within its priority
+// level. Requests carry no authenticated user, so the User-Agent and address stand in
+// for one.
+func flowFor(c *gin.Context) string {
+	return c.Request.UserAgent() + "@" + c.ClientIP()
+}
+
+// userAgentProduct returns the product in the request's User-Agent, such as
+// "kubectl-lite" for "kubectl-lite/k8s-lite-go". It is all the API server knows of who
+// sent a request, for field managers and priority levels; Impersonate-User and
+// Impersonate-Group headers are ignored until there are users and roles to check
+// them against.
+func userAgentProduct(c *gin.Context) string {
+	product, _, _ := strings.Cut(c.Request.UserAgent(), "/")
+	return product
+}
+
+// flowControlMiddleware runs each request once fc gives it a seat at its priority
+// level, and answers it with 429 Too Many Requests if it can't get one.
+func flowControlMiddleware(fc *flowController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		level, exempt := priorityLevelFor(c)
+		if exempt {
+			c.Next()
+			return
+		}
+		release, ok := fc.acquire(c.Request.Context(), level, flowFor(c))
+		if !ok {
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("Too many requests at priority level %s, please try again later", level)})
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
@@ -0,0 +1,153 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a lease
+func (s *APIServer) createLeaseHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var lease api.Lease
+	if err := c.ShouldBindJSON(&lease); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	lease.Namespace = c.Param("namespace")
+	if lease.Namespace == "" {
+		lease.Namespace = DefaultNamespace
+	}
+	validation.SetDefaults_Lease(&lease)
+	if s.rejectInvalid(c, "Lease", lease.Name, validation.Validate_Lease(&lease)) {
+		return
+	}
+
+	if body := s.terminatingNamespace(c, "Lease", lease.Name, lease.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetLease(ctx, lease.Namespace, lease.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create lease: lease %s in namespace %s already exists", lease.Name, lease.Namespace)})
+			return
+		}
+		c.JSON(201, lease)
+		return
+	}
+
+	if err := s.store.CreateLease(ctx, &lease); err != nil {
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create lease: " + err.Error()})
+		} else {
+			log.Printf("Error creating lease %s/%s in store: %v", lease.Namespace, lease.Name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create lease: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(201, lease)
+}
+
+// Gin handler for getting a specific lease
+func (s *APIServer) getLeaseHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	lease, err := s.store.GetLease(ctx, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get lease: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(lease), lease)
+}
+
+// Gin handler for listing leases in a namespace
+func (s *APIServer) listLeasesHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	leases, err := s.store.ListLeases(ctx, c.Param("namespace"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list leases: " + err.Error()})
+		return
+	}
+	respondWithList(c, leases)
+}
+
+// Gin handler for updating a specific lease. The body must carry the resourceVersion
+// it was read at; a lease changed since then is a 409 Conflict, so of two identities
+// racing to take a lease only one succeeds.
+func (s *APIServer) updateLeaseHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var lease api.Lease
+	if err := c.ShouldBindJSON(&lease); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if lease.Name != name || lease.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Lease %s/%s in body does not match URL (%s/%s)", lease.Namespace, lease.Name, namespace, name)})
+		return
+	}
+	validation.SetDefaults_Lease(&lease)
+	if s.rejectInvalid(c, "Lease", lease.Name, validation.Validate_Lease(&lease)) {
+		return
+	}
+
+	if isDryRun(c) {
+		existing, err := s.store.GetLease(ctx, namespace, name)
+		if err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update lease: " + err.Error()})
+			return
+		}
+		if existing.ResourceVersion != lease.ResourceVersion {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to update lease: conflict: lease %s in namespace %s has resourceVersion %s", name, namespace, existing.ResourceVersion)})
+			return
+		}
+		c.JSON(200, lease)
+		return
+	}
+
+	if err := s.store.UpdateLease(ctx, &lease); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			c.JSON(404, gin.H{"error": "Failed to update lease: " + err.Error()})
+		case errors.Is(err, store.ErrConflict):
+			c.JSON(409, gin.H{"error": "Failed to update lease: " + err.Error()})
+		default:
+			log.Printf("Failed to update lease in store: %v", err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update lease: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, lease)
+}
+
+// Gin handler for deleting a specific lease
+func (s *APIServer) deleteLeaseHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.store.GetLease(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete lease: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Lease %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	if err := s.store.DeleteLease(ctx, namespace, name); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete lease: " + err.Error()})
+		} else {
+			log.Printf("Error deleting lease %s/%s from store: %v", namespace, name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete lease: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Lease %s/%s deleted", namespace, name)})
+}
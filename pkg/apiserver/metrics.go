@@ -0,0 +1,286 @@
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultSlowRequestThreshold is how long a request may take before the API server
+// logs it as slow, unless configured otherwise.
+const DefaultSlowRequestThreshold = time.Second
+
+// writeLatencyBuckets are the upper bounds, in seconds, of the write latency
+// histogram buckets.
+var writeLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// requestKey identifies the requests a counter or histogram is kept for.
+type requestKey struct {
+	verb     string
+	resource string
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket of writeLatencyBuckets, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(seconds float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(writeLatencyBuckets))
+	}
+	for i, bound := range writeLatencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// admissionKey identifies the admission rejections a counter is kept for.
+type admissionKey struct {
+	plugin   string
+	resource string
+}
+
+// requestMetrics counts the API server's requests, write conflicts, slow requests, and
+// admission rejections, and times its writes. It is rendered in the Prometheus text
+// format at /metrics.
+type requestMetrics struct {
+	mu           sync.Mutex
+	requests     map[requestKey]map[int]uint64 // By status code
+	conflicts    map[requestKey]uint64
+	slow         map[requestKey]uint64
+	writeLatency map[requestKey]*histogram
+	rejections   map[admissionKey]uint64
+}
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		requests:     make(map[requestKey]map[int]uint64),
+		conflicts:    make(map[requestKey]uint64),
+		slow:         make(map[requestKey]uint64),
+		writeLatency: make(map[requestKey]*histogram),
+		rejections:   make(map[admissionKey]uint64),
+	}
+}
+
+func (m *requestMetrics) recordAdmissionRejection(plugin, resource string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rejections[admissionKey{plugin, resource}]++
+}
+
+func (m *requestMetrics) record(key requestKey, code int, elapsed time.Duration, slow bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.requests[key] == nil {
+		m.requests[key] = make(map[int]uint64)
+	}
+	m.requests[key][code]++
+	if slow {
+		m.slow[key]++
+	}
+	if !isWriteVerb(key.verb) {
+		return
+	}
+	if code == http.StatusConflict {
+		m.conflicts[key]++
+	}
+	h := m.writeLatency[key]
+	if h == nil {
+		h = &histogram{}
+		m.writeLatency[key] = h
+	}
+	h.observe(elapsed.Seconds())
+}
+
+func isWriteVerb(verb string) bool {
+	switch verb {
+	case "create", "update", "patch", "delete", "deletecollection":
+		return true
+	}
+	return false
+}
+
+// writeTo renders the metrics in the Prometheus text exposition format.
+func (m *requestMetrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP apiserver_request_total Requests served, by verb, resource, and status code.")
+	fmt.Fprintln(w, "# TYPE apiserver_request_total counter")
+	for _, key := range sortedKeys(m.requests) {
+		codes := make([]int, 0, len(m.requests[key]))
+		for code := range m.requests[key] {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(w, "apiserver_request_total{%s,code=\"%d\"} %d\n", key.labels(), code, m.requests[key][code])
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP apiserver_write_conflicts_total Writes rejected with 409 Conflict, by verb and resource.")
+	fmt.Fprintln(w, "# TYPE apiserver_write_conflicts_total counter")
+	for _, key := range sortedKeys(m.conflicts) {
+		fmt.Fprintf(w, "apiserver_write_conflicts_total{%s} %d\n", key.labels(), m.conflicts[key])
+	}
+
+	fmt.Fprintln(w, "# HELP apiserver_slow_requests_total Requests that took longer than the slow request threshold.")
+	fmt.Fprintln(w, "# TYPE apiserver_slow_requests_total counter")
+	for _, key := range sortedKeys(m.slow) {
+		fmt.Fprintf(w, "apiserver_slow_requests_total{%s} %d\n", key.labels(), m.slow[key])
+	}
+
+	fmt.Fprintln(w, "# HELP apiserver_write_duration_seconds Latency of create, update, patch, and delete requests.")
+	fmt.Fprintln(w, "# TYPE apiserver_write_duration_seconds histogram")
+	for _, key := range sortedKeys(m.writeLatency) {
+		h := m.writeLatency[key]
+		var cumulative uint64
+		for i, bound := range writeLatencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "apiserver_write_duration_seconds_bucket{%s,le=\"%g\"} %d\n", key.labels(), bound, cumulative)
+		}
+		fmt.Fprintf(w, "apiserver_write_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", key.labels(), h.count)
+		fmt.Fprintf(w, "apiserver_write_duration_seconds_sum{%s} %g\n", key.labels(), h.sum)
+		fmt.Fprintf(w, "apiserver_write_duration_seconds_count{%s} %d\n", key.labels(), h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP apiserver_admission_rejections_total Writes rejected by admission, by plugin and resource.")
+	fmt.Fprintln(w, "# TYPE apiserver_admission_rejections_total counter")
+	keys := make([]admissionKey, 0, len(m.rejections))
+	for k := range m.rejections {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].plugin != keys[j].plugin {
+			return keys[i].plugin < keys[j].plugin
+		}
+		return keys[i].resource < keys[j].resource
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "apiserver_admission_rejections_total{plugin=%q,resource=%q} %d\n", k.plugin, k.resource, m.rejections[k])
+	}
+}
+
+func (k requestKey) labels() string {
+	return fmt.Sprintf("verb=%q,resource=%q", k.verb, k.resource)
+}
+
+func sortedKeys[V any](m map[requestKey]V) []requestKey {
+	keys := make([]requestKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].resource != keys[j].resource {
+			return keys[i].resource < keys[j].resource
+		}
+		return keys[i].verb < keys[j].verb
+	})
+	return keys
+}
+
+// requestKeyFor names the verb and resource of a request from the route it matched,
+// for example "update" and "pods" for PUT /api/v1/namespaces/:namespace/pods/:podname.
+// Subresources are named after their resource, as in "pods/eviction", and proxied
+// paths are left out, as in "nodes/proxy". ok is false for
+// requests that matched no API route.
+func requestKeyFor(method, route string) (key requestKey, ok bool) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:] // api, version
+	case len(segments) >= 4 && segments[0] == "apis":
+		segments = segments[3:] // apis, group, version
+	default:
+		return requestKey{}, false
+	}
+	if len(segments) > 2 && segments[0] == "namespaces" && segments[1] == ":namespace" {
+		segments = segments[2:]
+	}
+
+	var resource []string
+	named := false
+	for _, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			named = true
+			continue
+		}
+		resource = append(resource, s)
+	}
+	key.resource = strings.Join(resource, "/")
+
+	switch method {
+	case http.MethodGet:
+		key.verb = "list"
+		if named {
+			key.verb = "get"
+		}
+	case http.MethodPost:
+		key.verb = "create"
+	case http.MethodPut:
+		key.verb = "update"
+	case http.MethodPatch:
+		key.verb = "patch"
+	case http.MethodDelete:
+		key.verb = "deletecollection"
+		if named {
+			key.verb = "delete"
+		}
+	default:
+		key.verb = strings.ToLower(method)
+	}
+	return key, true
+}
+
+// metricsMiddleware records every API request in m and logs those that take longer
+// than slowThreshold; zero disables the logging.
+func metricsMiddleware(m *requestMetrics, slowThreshold *atomic.Int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if r := c.GetString(routeKey); r != "" {
+			route = r
+		}
+		key, ok := requestKeyFor(c.Request.Method, route)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+		watch := key.verb == "list" && isWatch(c)
+		if watch {
+			key.verb = "watch"
+		}
+		// A watch or stream lasts as long as the client keeps it open, so it's never slow.
+		stream := c.GetHeader("Upgrade") != ""
+		threshold := time.Duration(slowThreshold.Load())
+		slow := threshold > 0 && elapsed > threshold && !watch && !stream
+		if slow {
+			log.Printf("Slow request: %s %s took %v (status %d, request ID %s)", c.Request.Method, c.Request.URL.Path, elapsed, c.Writer.Status(), requestID(c))
+		}
+		m.record(key, c.Writer.Status(), elapsed, slow)
+	}
+}
+
+func (s *APIServer) metricsHandlerGin(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(200)
+	s.metrics.writeTo(c.Writer)
+	if s.flowControl != nil {
+		s.flowControl.writeTo(c.Writer)
+	}
+}
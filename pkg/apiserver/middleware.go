@@ -0,0 +1,211 @@
+package apiserver
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/logs"
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDKey is the gin context key the request's ID is stored under.
+const requestIDKey = "requestID"
+
+// maxRequestIDLength bounds the client-supplied request IDs the server will reuse.
+const maxRequestIDLength = 64
+
+// requestID returns the ID requestIDMiddleware gave the request, or "" outside it.
+func requestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// requestIDMiddleware gives every request an ID, echoed in the X-Request-ID response
+// header and included in the server's log lines for the request. A client may pick
+// the ID itself by sending the header, to correlate its own logs with the server's.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(api.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(api.RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID reports whether a client-supplied ID is safe to log and echo back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// loggerMiddleware is gin's request logger with the request ID on the end of each line.
+// Requests are only logged at verbosity 2 and above.
+func loggerMiddleware() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{Skip: func(*gin.Context) bool { return !logs.V(2) }, Formatter: func(p gin.LogFormatterParams) string {
+		id, _ := p.Keys[requestIDKey].(string)
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | %s\n%s",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			p.StatusCode,
+			p.Latency,
+			p.ClientIP,
+			p.Method,
+			p.Path,
+			id,
+			p.ErrorMessage,
+		)
+	}})
+}
+
+// recoveryMiddleware turns a panicking handler into a 500 with a JSON error, rather
+// than a dropped connection, and logs the panic and its stack under the request ID.
+func recoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err) // The client went away; let net/http handle it
+				}
+				id := requestID(c)
+				log.Printf("Panic serving %s %s (request ID %s): %v\n%s", c.Request.Method, c.Request.URL.Path, id, err, debug.Stack())
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error":     fmt.Sprintf("Internal server error: %v", err),
+					"requestID": id,
+				})
+			}
+		}()
+		c.Next()
+	}
+}
+
+// corsAllowedHeaders and corsExposedHeaders are the request headers a browser may send
+// cross-origin, and the response headers its scripts may read.
+const (
+	corsAllowedHeaders = "Authorization, Content-Type, If-None-Match, " + api.RequestIDHeader
+	corsExposedHeaders = "ETag, " + api.RequestIDHeader + ", " + api.ContinueHeader
+)
+
+// corsMiddleware lets scripts on the given origins call the API from a browser. An
+// origin of "*" allows every origin, and is answered with a literal "*". Requests from
+// other origins are served as before, without CORS headers, so the browser keeps
+// their responses from the page. Preflight OPTIONS requests from an allowed origin are
+// answered here. Credentials are never allowed: clients send a bearer token in the
+// Authorization header, which scripts set themselves, rather than cookies.
+func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || !(allowed["*"] || allowed[origin]) {
+			c.Next()
+			return
+		}
+		if allowed[origin] {
+			c.Writer.Header().Add("Vary", "Origin")
+			c.Header("Access-Control-Allow-Origin", origin)
+		} else {
+			c.Header("Access-Control-Allow-Origin", "*")
+		}
+		c.Header("Access-Control-Expose-Headers", corsExposedHeaders)
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			c.Header("Access-Control-Allow-Headers", corsAllowedHeaders)
+			c.Header("Access-Control-Max-Age", "600")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// minGzipSize is the smallest response worth compressing.
+const minGzipSize = 1024
+
+var gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+
+// gzipMiddleware compresses responses for clients that accept gzip. Lists of pods
+// polled every few seconds shrink several times over.
+func gzipMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+			c.Next()
+			return
+		}
+		w := &gzipWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			c.Writer = w.ResponseWriter
+			if w.gz != nil {
+				w.gz.Close()
+				gzipWriters.Put(w.gz)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// gzipWriter compresses what is written to it, unless the first write is smaller
+// than minGzipSize; the JSON responses are written in one go, so that first write is
+// usually the whole body.
+type gzipWriter struct {
+	gin.ResponseWriter
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		if len(b) >= minGzipSize {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Del("Content-Length")
+			w.gz = gzipWriters.Get().(*gzip.Writer)
+			w.gz.Reset(w.ResponseWriter)
+		}
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.gz.Write(b)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,287 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a namespace
+func (s *APIServer) createNamespaceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var ns api.Namespace
+	if err := c.ShouldBindJSON(&ns); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	ns.Phase = api.NamespaceActive
+	if s.rejectInvalid(c, "Namespace", ns.Name, validation.Validate_Namespace(&ns)) {
+		return
+	}
+	s.trackManagedFields(c, nil, &ns)
+
+	if isDryRun(c) {
+		if _, err := s.store.GetNamespace(ctx, ns.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create namespace: namespace %s already exists", ns.Name)})
+			return
+		}
+		c.JSON(201, ns)
+		return
+	}
+
+	if err := s.store.CreateNamespace(ctx, &ns); err != nil {
+		log.Printf("Error creating namespace %s in store: %v", ns.Name, err)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create namespace: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create namespace: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created namespace %s", ns.Name)
+	c.JSON(201, ns)
+}
+
+// Gin handler for getting a specific namespace
+func (s *APIServer) getNamespaceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("namespace")
+	ns, err := s.store.GetNamespace(ctx, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get namespace: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(ns), ns)
+}
+
+// Gin handler for listing all namespaces
+func (s *APIServer) listNamespacesHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespaces, err := s.store.ListNamespaces(ctx)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list namespaces: " + err.Error()})
+		return
+	}
+	respondWithList(c, namespaces)
+}
+
+// errNamespaceNotEmpty is returned by the final delete of a namespace that still
+// holds objects.
+var errNamespaceNotEmpty = errors.New("namespace is not empty")
+
+// Gin handler for deleting a specific namespace. Deleting an active namespace only
+// marks it Terminating: nothing more can be created in it, and the namespace
+// controller deletes what it holds. Deleting a terminating namespace, as the controller
+// does once it has, removes it, along with its events and the pods that are gone; that
+// fails with a 409 while anything else is left in it.
+func (s *APIServer) deleteNamespaceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("namespace")
+	if immortalNamespace(name) {
+		c.JSON(403, gin.H{"error": fmt.Sprintf("Failed to delete namespace: namespace %s may not be deleted", name)})
+		return
+	}
+	removed := false
+	err := s.store.Txn(ctx, func(tx store.StoreTxn) error {
+		ns, err := tx.GetNamespace(ctx, name)
+		if err != nil {
+			return err
+		}
+		if ns.Phase != api.NamespaceTerminating {
+			if isDryRun(c) {
+				return nil
+			}
+			now := time.Now()
+			ns.Phase = api.NamespaceTerminating
+			ns.DeletionTimestamp = &now
+			return tx.UpdateNamespace(ctx, ns)
+		}
+		remaining, err := namespaceContents(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+		if len(remaining) > 0 {
+			return fmt.Errorf("%w: %s still holds %s", errNamespaceNotEmpty, name, strings.Join(remaining, ", "))
+		}
+		removed = true
+		if isDryRun(c) {
+			return nil
+		}
+		return purgeNamespace(ctx, tx, name)
+	})
+	if err != nil {
+		log.Printf("Error deleting namespace %s: %v", name, err)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			c.JSON(404, gin.H{"error": "Failed to delete namespace: " + err.Error()})
+		case errors.Is(err, errNamespaceNotEmpty):
+			c.JSON(409, gin.H{"error": "Failed to delete namespace: " + err.Error()})
+		default:
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete namespace: " + err.Error()})
+		}
+		return
+	}
+	suffix := ""
+	if isDryRun(c) {
+		suffix = " (dry run)"
+	}
+	if !removed {
+		log.Printf("Namespace %s is terminating%s", name, suffix)
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Namespace %s is terminating%s", name, suffix)})
+		return
+	}
+	log.Printf("Deleted namespace %s%s", name, suffix)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Namespace %s deleted%s", name, suffix)})
+}
+
+// namespaceContents names the objects left in namespace that keep it from being
+// removed, such as "pod/web".
+func namespaceContents(ctx context.Context, tx store.StoreTxn, namespace string) ([]string, error) {
+	var remaining []string
+	add := func(kind string, metas []*api.ObjectMeta) {
+		for _, meta := range metas {
+			remaining = append(remaining, kind+"/"+meta.Name)
+		}
+	}
+	pods, err := tx.ListPods(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if !api.IsPodGone(pod) {
+			remaining = append(remaining, "pod/"+pod.Name)
+		}
+	}
+	deployments, err := tx.ListDeployments(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("deployment", metasOf(deployments))
+	services, err := tx.ListServices(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("service", metasOf(services))
+	pdbs, err := tx.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("poddisruptionbudget", metasOf(pdbs))
+	policies, err := tx.ListNetworkPolicies(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("networkpolicy", metasOf(policies))
+	pvcs, err := tx.ListPersistentVolumeClaims(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("persistentvolumeclaim", metasOf(pvcs))
+	leases, err := tx.ListLeases(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("lease", metasOf(leases))
+	configMaps, err := tx.ListConfigMaps(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("configmap", metasOf(configMaps))
+	secrets, err := tx.ListSecrets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	add("secret", metasOf(secrets))
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+func metasOf[T interface{ GetObjectMeta() *api.ObjectMeta }](objs []T) []*api.ObjectMeta {
+	metas := make([]*api.ObjectMeta, len(objs))
+	for i, obj := range objs {
+		metas[i] = obj.GetObjectMeta()
+	}
+	return metas
+}
+
+// purgeNamespace removes an empty namespace, with its events and gone pods.
+func purgeNamespace(ctx context.Context, tx store.StoreTxn, namespace string) error {
+	pods, err := tx.ListPods(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if err := store.RegistryFor[*api.Pod](tx, store.Pods).Delete(ctx, namespace, pod.Name); err != nil {
+			return err
+		}
+	}
+	events, err := tx.ListEvents(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := store.RegistryFor[*api.Event](tx, store.Events).Delete(ctx, namespace, event.Name); err != nil {
+			return err
+		}
+	}
+	return tx.DeleteNamespace(ctx, namespace)
+}
+
+// terminatingNamespace returns the error body for creating the kind object named name
+// in namespace if the namespace is being deleted, and nil otherwise. Creating objects
+// in a namespace that doesn't exist is allowed, as it always has been.
+func (s *APIServer) terminatingNamespace(c *gin.Context, kind, name, namespace string) gin.H {
+	ns, err := s.store.GetNamespace(c.Request.Context(), namespace)
+	if err != nil || ns.Phase != api.NamespaceTerminating {
+		return nil
+	}
+	return s.rejected(c, admissionNamespaceLifecycle, kind, name, gin.H{"error": fmt.Sprintf("Failed to create object: namespace %s is being terminated", namespace)})
+}
+
+// Gin handler for updating a specific namespace. The phase is owned by the server and
+// cannot be changed through an update.
+func (s *APIServer) updateNamespaceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("namespace")
+	var ns api.Namespace
+	if err := c.ShouldBindJSON(&ns); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if ns.Name != "" && ns.Name != name {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Namespace name in body (%s) does not match path (%s)", ns.Name, name)})
+		return
+	}
+	ns.Name = name
+
+	existing, err := s.store.GetNamespace(ctx, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update namespace: " + err.Error()})
+		return
+	}
+	ns.Phase = existing.Phase
+	if s.rejectInvalid(c, "Namespace", ns.Name, validation.Validate_Namespace(&ns)) {
+		return
+	}
+	s.trackManagedFields(c, existing, &ns)
+
+	if isDryRun(c) {
+		c.JSON(200, ns)
+		return
+	}
+	if err := s.store.UpdateNamespace(ctx, &ns); err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update namespace: " + err.Error()})
+		return
+	}
+	c.JSON(200, ns)
+}
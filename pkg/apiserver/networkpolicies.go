@@ -0,0 +1,146 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a network policy
+func (s *APIServer) createNetworkPolicyHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var policy api.NetworkPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	policy.Namespace = c.Param("namespace")
+	if policy.Namespace == "" {
+		policy.Namespace = DefaultNamespace
+	}
+	if s.rejectInvalid(c, "NetworkPolicy", policy.Name, validation.Validate_NetworkPolicy(&policy)) {
+		return
+	}
+	s.trackManagedFields(c, nil, &policy)
+
+	if body := s.terminatingNamespace(c, "NetworkPolicy", policy.Name, policy.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetNetworkPolicy(ctx, policy.Namespace, policy.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create networkpolicy: networkpolicy %s in namespace %s already exists", policy.Name, policy.Namespace)})
+			return
+		}
+		c.JSON(201, policy)
+		return
+	}
+
+	if err := s.store.CreateNetworkPolicy(ctx, &policy); err != nil {
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create networkpolicy: " + err.Error()})
+		} else {
+			log.Printf("Error creating networkpolicy %s/%s in store: %v", policy.Namespace, policy.Name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create networkpolicy: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created networkpolicy %s/%s", policy.Namespace, policy.Name)
+	c.JSON(201, policy)
+}
+
+// Gin handler for getting a specific network policy
+func (s *APIServer) getNetworkPolicyHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	policy, err := s.store.GetNetworkPolicy(ctx, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get networkpolicy: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(policy), policy)
+}
+
+// Gin handler for listing network policies in a namespace
+func (s *APIServer) listNetworkPoliciesHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	policies, err := s.store.ListNetworkPolicies(ctx, c.Param("namespace"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list networkpolicies: " + err.Error()})
+		return
+	}
+	respondWithList(c, policies)
+}
+
+// Gin handler for updating a specific network policy
+func (s *APIServer) updateNetworkPolicyHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var policy api.NetworkPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if policy.Name != name || policy.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("NetworkPolicy %s/%s in body does not match URL (%s/%s)", policy.Namespace, policy.Name, namespace, name)})
+		return
+	}
+	if s.rejectInvalid(c, "NetworkPolicy", policy.Name, validation.Validate_NetworkPolicy(&policy)) {
+		return
+	}
+	existing, err := s.store.GetNetworkPolicy(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update networkpolicy: " + err.Error()})
+		return
+	}
+	s.trackManagedFields(c, existing, &policy)
+
+	if isDryRun(c) {
+		c.JSON(200, policy)
+		return
+	}
+
+	if err := s.store.UpdateNetworkPolicy(ctx, &policy); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update networkpolicy: " + err.Error()})
+		} else {
+			log.Printf("Failed to update networkpolicy in store: %v", err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update networkpolicy: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, policy)
+}
+
+// Gin handler for deleting a specific network policy
+func (s *APIServer) deleteNetworkPolicyHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.store.GetNetworkPolicy(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete networkpolicy: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("NetworkPolicy %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	if err := s.store.DeleteNetworkPolicy(ctx, namespace, name); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete networkpolicy: " + err.Error()})
+		} else {
+			log.Printf("Error deleting networkpolicy %s/%s from store: %v", namespace, name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete networkpolicy: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted networkpolicy %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("NetworkPolicy %s/%s deleted", namespace, name)})
+}
@@ -0,0 +1,59 @@
+package apiserver
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// holdForApproval keeps a node that is pending approval out of the cluster, whatever
+// its kubelet or anyone else writes: it stays NotReady and unschedulable until
+// approved. existing is the stored node being replaced, or nil for a new one, which
+// starts pending approval if the server requires it. This models the TLS bootstrap of
+// Kubernetes, where a new node can't join before its certificate request is approved.
+func (s *APIServer) holdForApproval(node, existing *api.Node) {
+	if existing == nil {
+		node.PendingApproval = s.RequireNodeApproval
+	} else {
+		node.PendingApproval = existing.PendingApproval
+	}
+	if node.PendingApproval {
+		api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionFalse, Reason: "NodePendingApproval", Message: "The node is waiting to be approved"})
+		api.DeriveNodeStatus(node)
+		node.Unschedulable = true
+	}
+}
+
+// Gin handler for approving a node pending approval, which makes it Ready and
+// schedulable. Approving a node that isn't pending approval changes nothing.
+func (s *APIServer) approveNodeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	nodeName := c.Param("nodename")
+	node, err := s.store.GetNode(ctx, nodeName)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get node: " + err.Error()})
+		return
+	}
+	if !node.PendingApproval {
+		c.JSON(200, node)
+		return
+	}
+	approved := *node.DeepCopy()
+	approved.PendingApproval = false
+	api.SetNodeCondition(&approved, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionTrue, Reason: "NodeApproved", Message: "The node was approved"})
+	api.DeriveNodeStatus(&approved)
+	approved.Unschedulable = false
+
+	if isDryRun(c) {
+		c.JSON(200, approved)
+		return
+	}
+	if err := s.store.UpdateNode(ctx, &approved); err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to approve node %s: %v", nodeName, err)})
+		return
+	}
+	log.Printf("Approved node %s", nodeName)
+	c.JSON(200, approved)
+}
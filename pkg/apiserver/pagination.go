@@ -0,0 +1,80 @@
+package apiserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// respondWithList writes a list of objects, honouring the limit and continue query
+// parameters. Items are written in namespace/name order, whatever order the store
+// holds them in, so that the same list always gets the same ETag. Without a limit
+// the whole list is written; with one, at most limit items are, and the continue
+// token for the next page goes in the api.ContinueHeader response header.
+func respondWithList[T metaObject](c *gin.Context, items []T) {
+	page, next, err := paginate(items, c.Query("limit"), c.Query("continue"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	etag := listETag(page)
+	if next != "" {
+		c.Header(api.ContinueHeader, next)
+		// The same items with or without a next page are different responses.
+		etag = strings.TrimSuffix(etag, `"`) + "-" + next + `"`
+	}
+	respondWithETag(c, etag, page)
+}
+
+// paginate returns the page of items selected by the limit and continue query
+// parameters, in namespace/name order, and the continue token for the page after it
+// ("" on the last page).
+func paginate[T metaObject](items []T, limitParam, continueParam string) ([]T, string, error) {
+	limit := 0
+	if limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid limit %q: must be a non-negative integer", limitParam)
+		}
+		limit = n
+	}
+	after := ""
+	if continueParam != "" {
+		key, err := base64.RawURLEncoding.DecodeString(continueParam)
+		if err != nil || len(key) == 0 {
+			return nil, "", fmt.Errorf("invalid continue token %q", continueParam)
+		}
+		after = string(key)
+	}
+
+	sorted := make([]T, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool { return listKey(sorted[i]) < listKey(sorted[j]) })
+	if limitParam == "" && continueParam == "" {
+		return sorted, "", nil
+	}
+	start := 0
+	if after != "" {
+		start = sort.Search(len(sorted), func(i int) bool { return listKey(sorted[i]) > after })
+	}
+	rest := sorted[start:]
+	if limit == 0 || len(rest) <= limit {
+		return rest, "", nil
+	}
+	page := rest[:limit]
+	return page, base64.RawURLEncoding.EncodeToString([]byte(listKey(page[limit-1]))), nil
+}
+
+// listKey orders list items for pagination. A continue token is the key of the last
+// item on the previous page, so a page stays correct when items are added or removed
+// between requests.
+func listKey(obj metaObject) string {
+	meta := obj.GetObjectMeta()
+	return meta.Namespace + "/" + meta.Name
+}
@@ -0,0 +1,88 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/patch"
+	"github.com/gin-gonic/gin"
+)
+
+// patch handles a JSON merge patch or JSON patch, as patchType says, of the object
+// named in the URL. The patch is applied to the live object's JSON form and the result
+// handed to the kind's update handler, so it is validated and stored exactly as a PUT
+// would be, under the live object's resourceVersion unless the patch sets another.
+func (s *APIServer) patch(c *gin.Context, r applyResource, patchType api.PatchType) {
+	ctx := c.Request.Context()
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	var ops []patch.Operation
+	var mergePatch interface{}
+	if patchType == api.JSONPatchType {
+		ops, err = patch.ParseJSONPatch(raw)
+	} else {
+		err = json.Unmarshal(raw, &mergePatch)
+	}
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid patch: " + err.Error()})
+		return
+	}
+
+	name := c.Param(r.nameParam)
+	namespace := ""
+	if r.namespaced {
+		namespace = c.Param("namespace")
+	}
+	live, err := r.get(ctx, s.store, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to patch %s: %v", strings.ToLower(r.kind), err)})
+		return
+	}
+	fields, err := toFields(live)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to patch %s: %v", strings.ToLower(r.kind), err)})
+		return
+	}
+	before, _ := toFields(live)
+
+	var patched interface{}
+	if patchType == api.JSONPatchType {
+		patched, err = patch.ApplyJSONPatch(fields, ops)
+	} else {
+		patched = patch.MergePatch(fields, mergePatch)
+	}
+	if err != nil {
+		c.JSON(422, gin.H{"error": fmt.Sprintf("Failed to patch %s %q: %v", strings.ToLower(r.kind), name, err)})
+		return
+	}
+	result, ok := patched.(map[string]interface{})
+	if !ok {
+		c.JSON(422, gin.H{"error": fmt.Sprintf("Failed to patch %s %q: the patch leaves no object", strings.ToLower(r.kind), name)})
+		return
+	}
+	if jsonEqual(before, result) {
+		c.JSON(200, live) // Don't bump the resourceVersion of an object nothing changed in
+		return
+	}
+
+	obj := r.newObject()
+	if err := decodeFields(result, obj, true); err != nil {
+		c.JSON(422, gin.H{"error": fmt.Sprintf("%s %q is invalid: %v", r.kind, name, err)})
+		return
+	}
+	body, err := json.Marshal(obj)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": fmt.Sprintf("Failed to patch %s: %v", strings.ToLower(r.kind), err)})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	r.update(s, c)
+}
@@ -0,0 +1,215 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/disruption"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// withStatus returns a copy of pdb with its status computed from the pods currently in
+// its namespace.
+func (s *APIServer) withStatus(ctx context.Context, pdb *api.PodDisruptionBudget) (api.PodDisruptionBudget, error) {
+	out := *pdb
+	pods, err := s.store.ListPods(ctx, pdb.Namespace)
+	if err != nil {
+		return out, err
+	}
+	out.Status = disruption.Status(pdb, pods)
+	return out, nil
+}
+
+// Gin handler for creating a pod disruption budget
+func (s *APIServer) createPodDisruptionBudgetHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	var pdb api.PodDisruptionBudget
+	if err := c.ShouldBindJSON(&pdb); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	pdb.Namespace = namespace
+	if pdb.Namespace == "" {
+		pdb.Namespace = DefaultNamespace
+	}
+	if s.rejectInvalid(c, "PodDisruptionBudget", pdb.Name, validation.Validate_PodDisruptionBudget(&pdb)) {
+		return
+	}
+	pdb.Status = api.PodDisruptionBudgetStatus{}
+
+	if body := s.terminatingNamespace(c, "PodDisruptionBudget", pdb.Name, pdb.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetPodDisruptionBudget(ctx, pdb.Namespace, pdb.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create poddisruptionbudget: poddisruptionbudget %s in namespace %s already exists", pdb.Name, pdb.Namespace)})
+			return
+		}
+		c.JSON(201, pdb)
+		return
+	}
+
+	if err := s.store.CreatePodDisruptionBudget(ctx, &pdb); err != nil {
+		log.Printf("Error creating poddisruptionbudget %s/%s in store: %v", pdb.Namespace, pdb.Name, err)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create poddisruptionbudget: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create poddisruptionbudget: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created poddisruptionbudget %s/%s", pdb.Namespace, pdb.Name)
+	out, _ := s.withStatus(ctx, &pdb)
+	c.JSON(201, out)
+}
+
+// Gin handler for getting a specific pod disruption budget
+func (s *APIServer) getPodDisruptionBudgetHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	pdb, err := s.store.GetPodDisruptionBudget(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get poddisruptionbudget: " + err.Error()})
+		return
+	}
+	out, err := s.withStatus(ctx, pdb)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to compute poddisruptionbudget status: " + err.Error()})
+		return
+	}
+	c.JSON(200, out)
+}
+
+// Gin handler for listing pod disruption budgets in a namespace
+func (s *APIServer) listPodDisruptionBudgetsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	pdbs, err := s.store.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list poddisruptionbudgets: " + err.Error()})
+		return
+	}
+	result := make([]api.PodDisruptionBudget, 0, len(pdbs))
+	for _, pdb := range pdbs {
+		out, err := s.withStatus(ctx, pdb)
+		if err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to compute poddisruptionbudget status: " + err.Error()})
+			return
+		}
+		result = append(result, out)
+	}
+	c.JSON(200, result)
+}
+
+// Gin handler for deleting a specific pod disruption budget
+func (s *APIServer) deletePodDisruptionBudgetHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	policy, err := propagationPolicy(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetPodDisruptionBudget(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete poddisruptionbudget: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("PodDisruptionBudget %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	deleteNow := true
+	err = s.store.Txn(ctx, func(tx store.StoreTxn) error {
+		if existing, err := tx.GetPodDisruptionBudget(ctx, namespace, name); err == nil {
+			deleteNow, err = propagateDeletion(ctx, tx, policy, existing.ObjectMeta, func(meta api.ObjectMeta) error {
+				updated := *existing
+				updated.ObjectMeta = meta
+				return tx.UpdatePodDisruptionBudget(ctx, &updated)
+			})
+			if err != nil || !deleteNow {
+				return err
+			}
+		}
+		return tx.DeletePodDisruptionBudget(ctx, namespace, name)
+	})
+	if err != nil {
+		log.Printf("Error deleting poddisruptionbudget %s/%s from store: %v", namespace, name, err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete poddisruptionbudget: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete poddisruptionbudget: " + err.Error()})
+		}
+		return
+	}
+	if !deleteNow {
+		log.Printf("Marked poddisruptionbudget %s/%s for foreground deletion", namespace, name)
+		c.JSON(200, gin.H{"message": fmt.Sprintf("PodDisruptionBudget %s/%s marked for deletion; waiting for its dependents", namespace, name)})
+		return
+	}
+	log.Printf("Deleted poddisruptionbudget %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("PodDisruptionBudget %s/%s deleted", namespace, name)})
+}
+
+// Gin handler for evicting a pod: a delete that first checks the disruption budgets
+// covering the pod. An eviction that would leave a budget with fewer healthy pods than
+// it requires is refused with 429 Too Many Requests, which callers such as drain treat
+// as "retry later". Evicting a pod that is already being deleted succeeds.
+func (s *APIServer) evictPodHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	podName := c.Param("podname")
+
+	s.evictionMu.Lock()
+	defer s.evictionMu.Unlock()
+
+	pod, err := s.store.GetPod(ctx, namespace, podName)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to evict pod: " + err.Error()})
+		return
+	}
+	if pod.DeletionTimestamp != nil {
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Pod %s/%s is already being deleted", namespace, podName)})
+		return
+	}
+
+	pdbs, err := s.store.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to evict pod: " + err.Error()})
+		return
+	}
+	pods, err := s.store.ListPods(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to evict pod: " + err.Error()})
+		return
+	}
+	if err := disruption.CheckEviction(pod, pdbs, pods); err != nil {
+		if errors.Is(err, disruption.ErrBudgetViolated) {
+			c.JSON(429, gin.H{"error": "Failed to evict pod: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to evict pod: " + err.Error()})
+		}
+		return
+	}
+
+	if isDryRun(c) {
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Pod %s/%s evicted (dry run)", namespace, podName)})
+		return
+	}
+	if err := s.store.DeletePod(ctx, namespace, podName); err != nil {
+		log.Printf("Error evicting pod %s/%s: %v", namespace, podName, err)
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to evict pod: " + err.Error()})
+		return
+	}
+	log.Printf("Evicted pod %s/%s", namespace, podName)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Pod %s/%s evicted", namespace, podName)})
+}
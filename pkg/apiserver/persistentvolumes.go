@@ -0,0 +1,277 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a persistent volume. New volumes start Available; the
+// volume binder moves them on from there.
+func (s *APIServer) createPersistentVolumeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var pv api.PersistentVolume
+	if err := c.ShouldBindJSON(&pv); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	pv.Namespace = ""
+	pv.Phase = api.VolumeAvailable
+	validation.SetDefaults_PersistentVolume(&pv)
+	if s.rejectInvalid(c, "PersistentVolume", pv.Name, validation.Validate_PersistentVolume(&pv)) {
+		return
+	}
+
+	if isDryRun(c) {
+		if _, err := s.store.GetPersistentVolume(ctx, pv.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create persistentvolume: persistentvolume %s already exists", pv.Name)})
+			return
+		}
+		c.JSON(201, pv)
+		return
+	}
+
+	if err := s.store.CreatePersistentVolume(ctx, &pv); err != nil {
+		log.Printf("Error creating persistentvolume %s in store: %v", pv.Name, err)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create persistentvolume: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create persistentvolume: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created persistentvolume %s", pv.Name)
+	c.JSON(201, pv)
+}
+
+// Gin handler for getting a specific persistent volume
+func (s *APIServer) getPersistentVolumeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	pv, err := s.store.GetPersistentVolume(ctx, c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get persistentvolume: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(pv), pv)
+}
+
+// Gin handler for listing persistent volumes
+func (s *APIServer) listPersistentVolumesHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	pvs, err := s.store.ListPersistentVolumes(ctx)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list persistentvolumes: " + err.Error()})
+		return
+	}
+	respondWithList(c, pvs)
+}
+
+// Gin handler for updating a specific persistent volume
+func (s *APIServer) updatePersistentVolumeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	var pv api.PersistentVolume
+	if err := c.ShouldBindJSON(&pv); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if pv.Name != name {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("PersistentVolume name in body (%s) does not match name in URL (%s)", pv.Name, name)})
+		return
+	}
+	existing, err := s.store.GetPersistentVolume(ctx, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update persistentvolume: " + err.Error()})
+		return
+	}
+	validation.SetDefaults_PersistentVolume(&pv)
+	if s.rejectInvalid(c, "PersistentVolume", pv.Name, validation.Validate_PersistentVolumeUpdate(&pv, existing)) {
+		return
+	}
+
+	if isDryRun(c) {
+		c.JSON(200, pv)
+		return
+	}
+
+	if err := s.store.UpdatePersistentVolume(ctx, &pv); err != nil {
+		log.Printf("Failed to update persistentvolume in store: %v", err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update persistentvolume: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update persistentvolume: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, pv)
+}
+
+// Gin handler for deleting a specific persistent volume. A claim bound to it becomes Lost.
+func (s *APIServer) deletePersistentVolumeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.store.GetPersistentVolume(ctx, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete persistentvolume: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("PersistentVolume %s deleted (dry run)", name)})
+		return
+	}
+	if err := s.store.DeletePersistentVolume(ctx, name); err != nil {
+		log.Printf("Error deleting persistentvolume %s from store: %v", name, err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete persistentvolume: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete persistentvolume: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted persistentvolume %s", name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("PersistentVolume %s deleted", name)})
+}
+
+// Gin handler for creating a persistent volume claim. New claims start Pending until
+// the volume binder finds them a volume.
+func (s *APIServer) createPersistentVolumeClaimHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	var pvc api.PersistentVolumeClaim
+	if err := c.ShouldBindJSON(&pvc); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	pvc.Namespace = namespace
+	if pvc.Namespace == "" {
+		pvc.Namespace = DefaultNamespace
+	}
+	pvc.Phase = api.ClaimPending
+	pvc.Capacity = ""
+	validation.SetDefaults_PersistentVolumeClaim(&pvc)
+	if s.rejectInvalid(c, "PersistentVolumeClaim", pvc.Name, validation.Validate_PersistentVolumeClaim(&pvc)) {
+		return
+	}
+
+	if body := s.terminatingNamespace(c, "PersistentVolumeClaim", pvc.Name, pvc.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetPersistentVolumeClaim(ctx, pvc.Namespace, pvc.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create persistentvolumeclaim: persistentvolumeclaim %s in namespace %s already exists", pvc.Name, pvc.Namespace)})
+			return
+		}
+		c.JSON(201, pvc)
+		return
+	}
+
+	if err := s.store.CreatePersistentVolumeClaim(ctx, &pvc); err != nil {
+		log.Printf("Error creating persistentvolumeclaim %s/%s in store: %v", pvc.Namespace, pvc.Name, err)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create persistentvolumeclaim: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create persistentvolumeclaim: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created persistentvolumeclaim %s/%s", pvc.Namespace, pvc.Name)
+	c.JSON(201, pvc)
+}
+
+// Gin handler for getting a specific persistent volume claim
+func (s *APIServer) getPersistentVolumeClaimHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	pvc, err := s.store.GetPersistentVolumeClaim(ctx, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get persistentvolumeclaim: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(pvc), pvc)
+}
+
+// Gin handler for listing persistent volume claims in a namespace, or in all
+// namespaces for /api/v1/persistentvolumeclaims
+func (s *APIServer) listPersistentVolumeClaimsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	pvcs, err := s.store.ListPersistentVolumeClaims(ctx, c.Param("namespace"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list persistentvolumeclaims: " + err.Error()})
+		return
+	}
+	respondWithList(c, pvcs)
+}
+
+// Gin handler for updating a specific persistent volume claim
+func (s *APIServer) updatePersistentVolumeClaimHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	var pvc api.PersistentVolumeClaim
+	if err := c.ShouldBindJSON(&pvc); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if pvc.Name != name || pvc.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("PersistentVolumeClaim %s/%s in body does not match URL (%s/%s)", pvc.Namespace, pvc.Name, namespace, name)})
+		return
+	}
+	existing, err := s.store.GetPersistentVolumeClaim(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update persistentvolumeclaim: " + err.Error()})
+		return
+	}
+	validation.SetDefaults_PersistentVolumeClaim(&pvc)
+	if s.rejectInvalid(c, "PersistentVolumeClaim", pvc.Name, validation.Validate_PersistentVolumeClaimUpdate(&pvc, existing)) {
+		return
+	}
+
+	if isDryRun(c) {
+		c.JSON(200, pvc)
+		return
+	}
+
+	if err := s.store.UpdatePersistentVolumeClaim(ctx, &pvc); err != nil {
+		log.Printf("Failed to update persistentvolumeclaim in store: %v", err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update persistentvolumeclaim: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update persistentvolumeclaim: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, pvc)
+}
+
+// Gin handler for deleting a specific persistent volume claim. The volume binder then
+// releases (or, for the Delete reclaim policy, deletes) the volume it was bound to.
+func (s *APIServer) deletePersistentVolumeClaimHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.store.GetPersistentVolumeClaim(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete persistentvolumeclaim: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("PersistentVolumeClaim %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	if err := s.store.DeletePersistentVolumeClaim(ctx, namespace, name); err != nil {
+		log.Printf("Error deleting persistentvolumeclaim %s/%s from store: %v", namespace, name, err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete persistentvolumeclaim: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete persistentvolumeclaim: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted persistentvolumeclaim %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("PersistentVolumeClaim %s/%s deleted", namespace, name)})
+}
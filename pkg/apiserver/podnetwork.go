@@ -0,0 +1,84 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/ipam"
+)
+
+// PodIP and HostIP are owned by the API server: whatever a client sends is replaced on
+// every update. A pod gets an IP from the pod CIDR when it is bound to a node and keeps
+// it until it is Deleted or unbound (the node lifecycle controller sends a pod whose
+// node vanished back to Pending), at which point the IP is released for reuse.
+
+func podIPOwner(pod *api.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// assignPodNetwork sets the PodIP and HostIP of pod, which is replacing existing. It
+// reports whether it allocated a new IP, so the caller can release it if the update
+// fails.
+func (s *APIServer) assignPodNetwork(ctx context.Context, pod, existing *api.Pod) (allocated bool, err error) {
+	if s.podIPs == nil {
+		return false, nil
+	}
+	owner := podIPOwner(pod)
+	if pod.NodeName == "" || pod.Phase == api.PodDeleted {
+		s.podIPs.Release(owner)
+		pod.PodIP = ""
+		if pod.NodeName == "" {
+			pod.HostIP = ""
+		}
+		return false, nil
+	}
+
+	ip, err := s.podIPs.Allocate(owner)
+	if err != nil {
+		return false, err
+	}
+	pod.PodIP = ip
+	pod.HostIP = existing.HostIP
+	if node, err := s.store.GetNode(ctx, pod.NodeName); err == nil {
+		pod.HostIP = nodeHostIP(node)
+	}
+	return existing.PodIP == "", nil
+}
+
+// nodeHostIP returns the host part of a node's address ("10.0.0.5:10250" -> "10.0.0.5").
+func nodeHostIP(node *api.Node) string {
+	if host, _, err := net.SplitHostPort(node.Address); err == nil {
+		return host
+	}
+	return node.Address
+}
+
+// restorePodIPs reserves the IPs of pods already in the store, so a restarted API
+// server doesn't hand them out again.
+func (s *APIServer) restorePodIPs(ctx context.Context) error {
+	pods, err := s.store.ListPods(ctx, api.NamespaceAll)
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	for _, pod := range pods {
+		if pod.PodIP == "" || pod.Phase == api.PodDeleted {
+			continue
+		}
+		if err := s.podIPs.Reserve(podIPOwner(pod), pod.PodIP); err != nil {
+			log.Printf("Not restoring IP of pod %s: %v", podIPOwner(pod), err)
+		}
+	}
+	return nil
+}
+
+// NewPodIPAllocator returns an allocator for cidr, or nil if cidr is empty, which
+// turns pod IP allocation off.
+func NewPodIPAllocator(cidr string) (*ipam.Allocator, error) {
+	if cidr == "" {
+		return nil, nil
+	}
+	return ipam.NewAllocator(cidr)
+}
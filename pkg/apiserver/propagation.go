@@ -0,0 +1,178 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// propagationPolicy reads the propagationPolicy query parameter of a DELETE request,
+// defaulting to Background.
+func propagationPolicy(c *gin.Context) (api.DeletionPropagation, error) {
+	switch policy := api.DeletionPropagation(c.Query("propagationPolicy")); policy {
+	case "":
+		return api.DeletePropagationBackground, nil
+	case api.DeletePropagationBackground, api.DeletePropagationForeground, api.DeletePropagationOrphan:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid propagationPolicy %q: must be Background, Foreground, or Orphan", policy)
+	}
+}
+
+// propagateDeletion applies policy, through tx, to the dependents of the namespaced
+// object described by meta, before the object itself is deleted in the same
+// transaction, so no dependent is created or adopted in between. It returns false if
+// the object must instead be kept for now: a foreground deletion of an object that
+// still has dependents marks it with a deletion timestamp and the foregroundDeletion
+// finalizer through markDeleting, and the garbage collector deletes it once the
+// dependents are gone.
+func propagateDeletion(ctx context.Context, tx store.StoreTxn, policy api.DeletionPropagation, meta api.ObjectMeta, markDeleting func(api.ObjectMeta) error) (deleteNow bool, err error) {
+	switch policy {
+	case api.DeletePropagationOrphan:
+		return true, orphanDependents(ctx, tx, meta.Namespace, meta.UID)
+	case api.DeletePropagationForeground:
+		has, err := hasDependents(ctx, tx, meta.Namespace, meta.UID)
+		if err != nil || !has {
+			return err == nil, err
+		}
+		if meta.DeletionTimestamp == nil {
+			now := time.Now()
+			meta.DeletionTimestamp = &now
+		}
+		if !hasFinalizer(meta, api.FinalizerForegroundDeletion) {
+			meta.Finalizers = append(meta.Finalizers, api.FinalizerForegroundDeletion)
+		}
+		return false, markDeleting(meta)
+	default:
+		return true, nil
+	}
+}
+
+func hasFinalizer(meta api.ObjectMeta, finalizer string) bool {
+	for _, f := range meta.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutOwner returns refs minus any reference to uid, and whether there was one.
+func withoutOwner(refs []api.OwnerReference, uid string) ([]api.OwnerReference, bool) {
+	var kept []api.OwnerReference
+	for _, ref := range refs {
+		if ref.UID != uid {
+			kept = append(kept, ref)
+		}
+	}
+	return kept, len(kept) != len(refs)
+}
+
+func ownedBy(refs []api.OwnerReference, uid string) bool {
+	_, owned := withoutOwner(refs, uid)
+	return owned
+}
+
+// hasDependents reports whether any object in namespace is owned by uid. Pods the
+// kubelet has already reclaimed don't count.
+func hasDependents(ctx context.Context, tx store.StoreTxn, namespace, uid string) (bool, error) {
+	pods, err := tx.ListPods(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		if pod.Phase != api.PodDeleted && ownedBy(pod.OwnerReferences, uid) {
+			return true, nil
+		}
+	}
+	deployments, err := tx.ListDeployments(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range deployments {
+		if ownedBy(d.OwnerReferences, uid) {
+			return true, nil
+		}
+	}
+	services, err := tx.ListServices(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, svc := range services {
+		if ownedBy(svc.OwnerReferences, uid) {
+			return true, nil
+		}
+	}
+	pdbs, err := tx.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, pdb := range pdbs {
+		if ownedBy(pdb.OwnerReferences, uid) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// orphanDependents removes references to uid from every object in namespace, so the
+// garbage collector leaves them alone once their owner is deleted.
+func orphanDependents(ctx context.Context, tx store.StoreTxn, namespace, uid string) error {
+	pods, err := tx.ListPods(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if refs, owned := withoutOwner(pod.OwnerReferences, uid); owned {
+			updated := *pod
+			updated.OwnerReferences = refs
+			if err := tx.UpdatePod(ctx, &updated); err != nil {
+				return fmt.Errorf("orphaning pod %s: %w", pod.Name, err)
+			}
+		}
+	}
+	deployments, err := tx.ListDeployments(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, d := range deployments {
+		if refs, owned := withoutOwner(d.OwnerReferences, uid); owned {
+			updated := *d
+			updated.OwnerReferences = refs
+			if err := tx.UpdateDeployment(ctx, &updated); err != nil {
+				return fmt.Errorf("orphaning deployment %s: %w", d.Name, err)
+			}
+		}
+	}
+	services, err := tx.ListServices(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, svc := range services {
+		if refs, owned := withoutOwner(svc.OwnerReferences, uid); owned {
+			updated := *svc
+			updated.OwnerReferences = refs
+			if err := tx.UpdateService(ctx, &updated); err != nil {
+				return fmt.Errorf("orphaning service %s: %w", svc.Name, err)
+			}
+		}
+	}
+	pdbs, err := tx.ListPodDisruptionBudgets(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, pdb := range pdbs {
+		if refs, owned := withoutOwner(pdb.OwnerReferences, uid); owned {
+			updated := *pdb
+			updated.OwnerReferences = refs
+			if err := tx.UpdatePodDisruptionBudget(ctx, &updated); err != nil {
+				return fmt.Errorf("orphaning poddisruptionbudget %s: %w", pdb.Name, err)
+			}
+		}
+	}
+	return nil
+}
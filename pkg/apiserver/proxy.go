@@ -0,0 +1,126 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for GET .../pods/:podname/log, the output of a pod from its kubelet.
+// ?container=, ?tailLines=N, ?sinceSeconds=N, and ?previous=true select part of it, as
+// api.PodLogOptions describes.
+func (s *APIServer) podLogsHandlerGin(c *gin.Context) {
+	query := url.Values{}
+	for _, param := range []string{"container", "tailLines", "sinceSeconds", "previous"} {
+		if value, ok := c.GetQuery(param); ok {
+			query.Set(param, value)
+		}
+	}
+	s.proxyToPodKubelet(c, "logs", query)
+}
+
+// Gin handler for POST .../pods/:podname/exec?command=..., which runs a command in a
+// pod on its kubelet and answers with an api.ExecResult.
+func (s *APIServer) podExecHandlerGin(c *gin.Context) {
+	command := c.QueryArray("command")
+	if len(command) == 0 {
+		c.JSON(400, gin.H{"error": "No command given; pass it as ?command=..."})
+		return
+	}
+	s.proxyToPodKubelet(c, "exec", url.Values{"command": command})
+}
+
+// Gin handler for GET .../pods/:podname/exec?command=..., which runs a command in a pod
+// over a stream to its kubelet; see package streaming. ?stdin=true sends the command
+// the client's input and ?tty=true gives it a terminal.
+func (s *APIServer) podExecStreamHandlerGin(c *gin.Context) {
+	command := c.QueryArray("command")
+	if len(command) == 0 {
+		c.JSON(400, gin.H{"error": "No command given; pass it as ?command=..."})
+		return
+	}
+	s.proxyToPodKubelet(c, "exec", url.Values{"command": command, "stdin": {c.Query("stdin")}, "tty": {c.Query("tty")}})
+}
+
+// Gin handler for GET .../pods/:podname/attach, which streams a pod's output, and with
+// ?stdin=true its input, to and from its kubelet.
+func (s *APIServer) podAttachHandlerGin(c *gin.Context) {
+	s.proxyToPodKubelet(c, "attach", url.Values{"stdin": {c.Query("stdin")}})
+}
+
+// Gin handler for GET .../pods/:podname/portforward?port=N, which forwards a stream to
+// a port of a pod through its kubelet.
+func (s *APIServer) podPortForwardHandlerGin(c *gin.Context) {
+	s.proxyToPodKubelet(c, "portforward", url.Values{"port": {c.Query("port")}})
+}
+
+// proxyToPodKubelet proxies the request to the kubelet endpoint of the request's pod,
+// /<endpoint>/<pod>, with query added to the pod's namespace.
+func (s *APIServer) proxyToPodKubelet(c *gin.Context, endpoint string, query url.Values) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("podname")
+	pod, err := s.store.GetPod(ctx, namespace, name)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get pod: " + err.Error()})
+		}
+		return
+	}
+	if pod.NodeName == "" {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Pod %s/%s is not scheduled to a node yet", namespace, name)})
+		return
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("namespace", namespace)
+	s.proxyToKubelet(c, pod.NodeName, "/"+endpoint+"/"+url.PathEscape(name), query)
+}
+
+// Gin handler for /api/v1/nodes/:nodename/proxy/*path, which passes the request on to
+// path on the node's kubelet, with the request's query.
+func (s *APIServer) nodeProxyHandlerGin(c *gin.Context) {
+	s.proxyToKubelet(c, c.Param("nodename"), c.Param("path"), c.Request.URL.Query())
+}
+
+// proxyToKubelet passes the request on to path on the kubelet of nodeName, at the node's
+// address, authenticating with s.KubeletToken rather than the client's credentials.
+// Streams pass through as the WebSocket upgrades they are.
+func (s *APIServer) proxyToKubelet(c *gin.Context, nodeName, path string, query url.Values) {
+	ctx := c.Request.Context()
+	node, err := s.store.GetNode(ctx, nodeName)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get node: " + err.Error()})
+		}
+		return
+	}
+	if node.Address == "" {
+		c.JSON(503, gin.H{"error": fmt.Sprintf("Node %s has no address", nodeName)})
+		return
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL = &url.URL{Scheme: "http", Host: node.Address, Path: path, RawQuery: query.Encode()}
+			r.Out.Host = ""
+			r.Out.Header.Del("Authorization")
+			if s.KubeletToken != "" {
+				r.Out.Header.Set("Authorization", "Bearer "+s.KubeletToken)
+			}
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			c.JSON(502, gin.H{"error": fmt.Sprintf("Failed to reach the kubelet of node %s at %s: %v", nodeName, node.Address, err)})
+		},
+	}
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
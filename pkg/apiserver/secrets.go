@@ -0,0 +1,148 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a secret
+func (s *APIServer) createSecretHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var secret api.Secret
+	if err := c.ShouldBindJSON(&secret); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	secret.Namespace = c.Param("namespace")
+	if secret.Namespace == "" {
+		secret.Namespace = DefaultNamespace
+	}
+	validation.SetDefaults_Secret(&secret)
+	if s.rejectInvalid(c, "Secret", secret.Name, validation.Validate_Secret(&secret)) {
+		return
+	}
+	s.trackManagedFields(c, nil, &secret)
+
+	if body := s.terminatingNamespace(c, "Secret", secret.Name, secret.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetSecret(ctx, secret.Namespace, secret.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create secret: secret %s in namespace %s already exists", secret.Name, secret.Namespace)})
+			return
+		}
+		c.JSON(201, secret)
+		return
+	}
+
+	if err := s.store.CreateSecret(ctx, &secret); err != nil {
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create secret: " + err.Error()})
+		} else {
+			log.Printf("Error creating secret %s/%s in store: %v", secret.Namespace, secret.Name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create secret: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created secret %s/%s", secret.Namespace, secret.Name)
+	c.JSON(201, secret)
+}
+
+// Gin handler for getting a specific secret
+func (s *APIServer) getSecretHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	secret, err := s.store.GetSecret(ctx, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get secret: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(secret), secret)
+}
+
+// Gin handler for listing secrets in a namespace
+func (s *APIServer) listSecretsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	secrets, err := s.store.ListSecrets(ctx, c.Param("namespace"))
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list secrets: " + err.Error()})
+		return
+	}
+	respondWithList(c, secrets)
+}
+
+// Gin handler for updating a specific secret
+func (s *APIServer) updateSecretHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var secret api.Secret
+	if err := c.ShouldBindJSON(&secret); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if secret.Name != name || secret.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Secret %s/%s in body does not match URL (%s/%s)", secret.Namespace, secret.Name, namespace, name)})
+		return
+	}
+	validation.SetDefaults_Secret(&secret)
+	if s.rejectInvalid(c, "Secret", secret.Name, validation.Validate_Secret(&secret)) {
+		return
+	}
+	existing, err := s.store.GetSecret(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update secret: " + err.Error()})
+		return
+	}
+	s.trackManagedFields(c, existing, &secret)
+
+	if isDryRun(c) {
+		c.JSON(200, secret)
+		return
+	}
+
+	if err := s.store.UpdateSecret(ctx, &secret); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update secret: " + err.Error()})
+		} else {
+			log.Printf("Failed to update secret in store: %v", err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update secret: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, secret)
+}
+
+// Gin handler for deleting a specific secret
+func (s *APIServer) deleteSecretHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if isDryRun(c) {
+		if _, err := s.store.GetSecret(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete secret: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Secret %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	if err := s.store.DeleteSecret(ctx, namespace, name); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete secret: " + err.Error()})
+		} else {
+			log.Printf("Error deleting secret %s/%s from store: %v", namespace, name, err)
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete secret: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted secret %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Secret %s/%s deleted", namespace, name)})
+}
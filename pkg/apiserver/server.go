@@ -0,0 +1,804 @@
+// Package apiserver serves the k8s-lite REST API. The apiserver binary and kubelite
+// both run it; tests can embed it with NewAPIServer and Serve on a local listener.
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/fields"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/ipam"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultNamespace is used for namespaced objects created without a namespace.
+const DefaultNamespace = "default"
+
+// APIServer serves the k8s-lite REST API on top of a store. Servers share no state
+// beyond their store, so several can run in one process.
+type APIServer struct {
+	store  store.Store
+	podIPs *ipam.Allocator // nil disables pod IP allocation
+
+	// Chaos, if set before the server starts, fails and delays requests.
+	Chaos *chaos.Injector
+	// SlowRequestThreshold is how long a request may take before it is logged as
+	// slow; zero disables the logging. NewAPIServer sets DefaultSlowRequestThreshold,
+	// and SetSlowRequestThreshold changes it once the server has started.
+	SlowRequestThreshold time.Duration
+	// RequestTimeout, if set before the server starts, is how long a request other
+	// than a watch or a stream may take before its store calls fail and it is answered
+	// with 504. NewAPIServer sets DefaultRequestTimeout; zero disables the timeout.
+	RequestTimeout time.Duration
+	// MaxRequestsInFlight, if set before the server starts, is how many requests it
+	// runs at once, shared between priority levels so that lists can't starve node
+	// heartbeats; see flowcontrol.go. NewAPIServer sets DefaultMaxRequestsInFlight;
+	// zero disables flow control.
+	MaxRequestsInFlight int
+	// WatchBookmarkInterval is how often a watch sends a bookmark. NewAPIServer sets
+	// DefaultWatchBookmarkInterval.
+	WatchBookmarkInterval time.Duration
+	// KubeletToken, if set, is the bearer token sent to kubelets on the requests the
+	// server proxies to them, such as pod logs and exec.
+	KubeletToken string
+	// CORSAllowedOrigins, if set before the server starts, are the origins, such as
+	// "http://localhost:8081", whose browser scripts may call the API. "*" allows any.
+	CORSAllowedOrigins []string
+	// RequireNodeApproval, if set, holds the nodes registered from then on NotReady and
+	// unschedulable until they are approved through their approval subresource.
+	RequireNodeApproval bool
+	// EventTTL, if set before the server starts, is how long an event is kept after
+	// it last happened, and MaxEventsPerNamespace, if set, how many events a namespace
+	// keeps, the least recently seen deleted first. NewAPIServer sets DefaultEventTTL
+	// and DefaultMaxEventsPerNamespace; zero keeps events forever, or without limit.
+	EventTTL              time.Duration
+	MaxEventsPerNamespace int
+
+	metrics       *requestMetrics
+	flowControl   *flowController // Nil without MaxRequestsInFlight
+	slowThreshold atomic.Int64    // The SlowRequestThreshold in effect, as a time.Duration
+
+	// eventsMu serializes event creation so that two reports of the same event can't
+	// both miss the existing copy and create duplicates.
+	eventsMu sync.Mutex
+	// evictionMu serializes evictions so that two concurrent requests can't both spend
+	// the last disruption a budget allows.
+	evictionMu sync.Mutex
+
+	// components holds the last heartbeat of each component, by name. Heartbeats are
+	// not persisted: after a restart, components show up again as they next report.
+	componentsMu sync.Mutex
+	components   map[string]api.ComponentStatus
+}
+
+// NewAPIServer returns an API server backed by s that assigns pod IPs from podIPs,
+// or leaves them unset if podIPs is nil.
+func NewAPIServer(s store.Store, podIPs *ipam.Allocator) *APIServer {
+	server := &APIServer{
+		store:                 s,
+		podIPs:                podIPs,
+		SlowRequestThreshold:  DefaultSlowRequestThreshold,
+		RequestTimeout:        DefaultRequestTimeout,
+		MaxRequestsInFlight:   DefaultMaxRequestsInFlight,
+		WatchBookmarkInterval: DefaultWatchBookmarkInterval,
+		EventTTL:              DefaultEventTTL,
+		MaxEventsPerNamespace: DefaultMaxEventsPerNamespace,
+		metrics:               newRequestMetrics(),
+		components:            make(map[string]api.ComponentStatus),
+	}
+	if podIPs != nil {
+		if err := server.restorePodIPs(context.Background()); err != nil {
+			log.Printf("Failed to restore pod IPs: %v", err)
+		}
+	}
+	return server
+}
+
+// NewStore returns an in-memory store holding just what bootstrap creates, the state
+// a fresh cluster starts from.
+func NewStore() (store.Store, error) {
+	dataStore := store.NewInMemoryStore()
+	if err := bootstrap(context.Background(), dataStore); err != nil {
+		return nil, err
+	}
+	return dataStore, nil
+}
+
+// OpenStore returns an in-memory store that journals every write to the file at
+// journalPath, restoring what an earlier API server journaled there first, so the
+// cluster survives a restart or crash. An empty journalPath keeps the store in memory
+// only. Above 1, shards splits each resource's objects into that many shards with
+// locks of their own, so that a busy cluster's reads and writes wait less on one
+// another; see store.NewShardedInMemoryStore.
+func OpenStore(journalPath string, shards int) (store.Store, error) {
+	var dataStore *store.InMemoryStore
+	if journalPath == "" {
+		dataStore = store.NewShardedInMemoryStore(shards)
+	} else {
+		var err error
+		if dataStore, err = store.OpenShardedInMemoryStore(journalPath, shards); err != nil {
+			return nil, err
+		}
+	}
+	if err := bootstrap(context.Background(), dataStore); err != nil {
+		return nil, err
+	}
+	return dataStore, nil
+}
+
+// Run serves the API on addr (for example ":8080") until ctx is cancelled.
+func (s *APIServer) Run(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves the API on ln until ctx is cancelled, then shuts down gracefully. It
+// lets callers that need the address before the server starts, such as kubelite,
+// open the listener themselves.
+func (s *APIServer) Serve(ctx context.Context, ln net.Listener) error {
+	// Requests are cancelled when shutdown begins, so that open watches end rather
+	// than hold it up.
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	srv := &http.Server{Handler: s.Handler(), BaseContext: func(net.Listener) context.Context { return baseCtx }}
+	srv.RegisterOnShutdown(cancelRequests)
+	if err := s.publishClusterInfo(ctx, ln.Addr()); err != nil {
+		log.Printf("Failed to publish the API server's address in cluster-info: %v", err)
+	}
+	go s.expireEvents(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("API Server listening on %s", ln.Addr())
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// SetSlowRequestThreshold changes how long a request may take before it is logged as
+// slow. It is safe to call while the server runs.
+func (s *APIServer) SetSlowRequestThreshold(d time.Duration) {
+	s.slowThreshold.Store(int64(d))
+}
+
+// Handler returns the HTTP handler serving every API route.
+func (s *APIServer) Handler() http.Handler {
+	router := gin.New() // Use Gin router
+	router.Use(requestIDMiddleware(), loggerMiddleware())
+	if len(s.CORSAllowedOrigins) > 0 {
+		// Before everything else that could fail a preflight request, such as chaos.
+		router.Use(corsMiddleware(s.CORSAllowedOrigins))
+	}
+	// Record requests before chaos so that injected failures and delays show up too, and
+	// before recovery so that panics are counted as the 500s they become.
+	s.slowThreshold.Store(int64(s.SlowRequestThreshold))
+	router.Use(metricsMiddleware(s.metrics, &s.slowThreshold), gzipMiddleware(), recoveryMiddleware())
+	if s.RequestTimeout > 0 {
+		// Before chaos, so that injected delays count against the timeout.
+		router.Use(timeoutMiddleware(s.RequestTimeout))
+	}
+	if s.MaxRequestsInFlight > 0 {
+		// Also before chaos, so that a request chaos delays holds its seat meanwhile.
+		s.flowControl = newFlowController(s.MaxRequestsInFlight)
+		router.Use(flowControlMiddleware(s.flowControl))
+	}
+	if s.Chaos != nil {
+		router.Use(chaosMiddleware(s.Chaos))
+	}
+
+	// Pod routes
+	// /api/v1/namespaces/{namespace}/pods
+	podsGroup := router.Group("/api/v1/namespaces/:namespace/pods")
+	{
+		podsGroup.POST("", s.createPodHandlerGin)
+		podsGroup.GET("", watchable[*api.Pod](s, store.Pods, s.listPodsHandlerGin))
+		podsGroup.DELETE("", s.deletePodCollectionHandlerGin)
+		podsGroup.GET("/:podname", s.getPodHandlerGin)
+		podsGroup.PUT("/:podname", s.updatePodHandlerGin) // Added route for updating a pod
+		podsGroup.PATCH("/:podname", s.patchHandlerGin(applyPods))
+		podsGroup.DELETE("/:podname", s.deletePodHandlerGin)
+		podsGroup.POST("/:podname/eviction", s.evictPodHandlerGin)
+		podsGroup.GET("/:podname/log", s.podLogsHandlerGin)
+		podsGroup.POST("/:podname/exec", s.podExecHandlerGin)
+		podsGroup.GET("/:podname/exec", s.podExecStreamHandlerGin)
+		podsGroup.GET("/:podname/attach", s.podAttachHandlerGin)
+		podsGroup.GET("/:podname/portforward", s.podPortForwardHandlerGin)
+	}
+
+	// Namespace routes
+	// /api/v1/namespaces
+	namespacesGroup := router.Group("/api/v1/namespaces")
+	{
+		namespacesGroup.POST("", s.createNamespaceHandlerGin)
+		namespacesGroup.GET("", watchable[*api.Namespace](s, store.Namespaces, s.listNamespacesHandlerGin))
+		namespacesGroup.GET("/:namespace", s.getNamespaceHandlerGin)
+		namespacesGroup.PUT("/:namespace", s.updateNamespaceHandlerGin)
+		namespacesGroup.PATCH("/:namespace", s.patchHandlerGin(applyNamespaces))
+		namespacesGroup.DELETE("/:namespace", s.deleteNamespaceHandlerGin)
+	}
+
+	// Service routes
+	// /api/v1/namespaces/{namespace}/services
+	servicesGroup := router.Group("/api/v1/namespaces/:namespace/services")
+	{
+		servicesGroup.POST("", s.createServiceHandlerGin)
+		servicesGroup.GET("", watchable[*api.Service](s, store.Services, s.listServicesHandlerGin))
+		servicesGroup.GET("/:name", s.getServiceHandlerGin)
+		servicesGroup.PUT("/:name", s.updateServiceHandlerGin)
+		servicesGroup.PATCH("/:name", s.patchHandlerGin(applyServices))
+		servicesGroup.DELETE("/:name", s.deleteServiceHandlerGin)
+	}
+
+	// Event routes
+	// /api/v1/namespaces/{namespace}/events
+	eventsGroup := router.Group("/api/v1/namespaces/:namespace/events")
+	{
+		eventsGroup.POST("", s.createEventHandlerGin)
+		eventsGroup.GET("", watchable[*api.Event](s, store.Events, s.listEventsHandlerGin))
+	}
+
+	// Deployment routes
+	// /apis/apps/v1/namespaces/{namespace}/deployments
+	deploymentsGroup := router.Group("/apis/apps/v1/namespaces/:namespace/deployments")
+	{
+		deploymentsGroup.POST("", s.createDeploymentHandlerGin)
+		deploymentsGroup.GET("", watchable[*api.Deployment](s, store.Deployments, s.listDeploymentsHandlerGin))
+		deploymentsGroup.GET("/:name", s.getDeploymentHandlerGin)
+		deploymentsGroup.PUT("/:name", s.updateDeploymentHandlerGin)
+		deploymentsGroup.PATCH("/:name", s.patchHandlerGin(applyDeployments))
+		deploymentsGroup.DELETE("/:name", s.deleteDeploymentHandlerGin)
+	}
+
+	// PodDisruptionBudget routes
+	// /apis/policy/v1/namespaces/{namespace}/poddisruptionbudgets
+	pdbsGroup := router.Group("/apis/policy/v1/namespaces/:namespace/poddisruptionbudgets")
+	{
+		pdbsGroup.POST("", s.createPodDisruptionBudgetHandlerGin)
+		pdbsGroup.GET("", watchable[*api.PodDisruptionBudget](s, store.PodDisruptionBudgets, s.listPodDisruptionBudgetsHandlerGin))
+		pdbsGroup.GET("/:name", s.getPodDisruptionBudgetHandlerGin)
+		pdbsGroup.DELETE("/:name", s.deletePodDisruptionBudgetHandlerGin)
+	}
+
+	// PersistentVolume routes
+	// /api/v1/persistentvolumes
+	pvsGroup := router.Group("/api/v1/persistentvolumes")
+	{
+		pvsGroup.POST("", s.createPersistentVolumeHandlerGin)
+		pvsGroup.GET("", watchable[*api.PersistentVolume](s, store.PersistentVolumes, s.listPersistentVolumesHandlerGin))
+		pvsGroup.GET("/:name", s.getPersistentVolumeHandlerGin)
+		pvsGroup.PUT("/:name", s.updatePersistentVolumeHandlerGin)
+		pvsGroup.DELETE("/:name", s.deletePersistentVolumeHandlerGin)
+	}
+
+	// PersistentVolumeClaim routes
+	// /api/v1/namespaces/{namespace}/persistentvolumeclaims
+	pvcsGroup := router.Group("/api/v1/namespaces/:namespace/persistentvolumeclaims")
+	{
+		pvcsGroup.POST("", s.createPersistentVolumeClaimHandlerGin)
+		pvcsGroup.GET("", watchable[*api.PersistentVolumeClaim](s, store.PersistentVolumeClaims, s.listPersistentVolumeClaimsHandlerGin))
+		pvcsGroup.GET("/:name", s.getPersistentVolumeClaimHandlerGin)
+		pvcsGroup.PUT("/:name", s.updatePersistentVolumeClaimHandlerGin)
+		pvcsGroup.DELETE("/:name", s.deletePersistentVolumeClaimHandlerGin)
+	}
+
+	// Node routes
+	// /api/v1/nodes
+	nodesGroup := router.Group("/api/v1/nodes")
+	{
+		nodesGroup.POST("", s.createNodeHandlerGin)
+		nodesGroup.GET("", watchable[*api.Node](s, store.Nodes, s.listNodesHandlerGin))
+		nodesGroup.GET("/:nodename", s.getNodeHandlerGin)
+		nodesGroup.PUT("/:nodename", s.updateNodeHandlerGin) // Add PUT route for updating a node
+		nodesGroup.PATCH("/:nodename", s.patchHandlerGin(applyNodes))
+		nodesGroup.DELETE("/:nodename", s.deleteNodeHandlerGin)
+		nodesGroup.POST("/:nodename/approval", s.approveNodeHandlerGin)
+		nodesGroup.Any("/:nodename/proxy/*path", s.nodeProxyHandlerGin)
+	}
+
+	// Pods across all namespaces
+	// /api/v1/pods
+	router.GET("/api/v1/pods", watchable[*api.Pod](s, store.Pods, s.listPodsHandlerGin))
+
+	// PersistentVolumeClaims across all namespaces
+	// /api/v1/persistentvolumeclaims
+	router.GET("/api/v1/persistentvolumeclaims", watchable[*api.PersistentVolumeClaim](s, store.PersistentVolumeClaims, s.listPersistentVolumeClaimsHandlerGin))
+
+	// Deployments across all namespaces
+	// /apis/apps/v1/deployments
+	router.GET("/apis/apps/v1/deployments", watchable[*api.Deployment](s, store.Deployments, s.listDeploymentsHandlerGin))
+
+	// ConfigMap routes
+	// /api/v1/namespaces/{namespace}/configmaps
+	configMapsGroup := router.Group("/api/v1/namespaces/:namespace/configmaps")
+	{
+		configMapsGroup.POST("", s.createConfigMapHandlerGin)
+		configMapsGroup.GET("", watchable[*api.ConfigMap](s, store.ConfigMaps, s.listConfigMapsHandlerGin))
+		configMapsGroup.GET("/:name", s.getConfigMapHandlerGin)
+		configMapsGroup.PUT("/:name", s.updateConfigMapHandlerGin)
+		configMapsGroup.DELETE("/:name", s.deleteConfigMapHandlerGin)
+	}
+
+	// Secret routes
+	// /api/v1/namespaces/{namespace}/secrets
+	secretsGroup := router.Group("/api/v1/namespaces/:namespace/secrets")
+	{
+		secretsGroup.POST("", s.createSecretHandlerGin)
+		secretsGroup.GET("", watchable[*api.Secret](s, store.Secrets, s.listSecretsHandlerGin))
+		secretsGroup.GET("/:name", s.getSecretHandlerGin)
+		secretsGroup.PUT("/:name", s.updateSecretHandlerGin)
+		secretsGroup.DELETE("/:name", s.deleteSecretHandlerGin)
+	}
+
+	// Lease routes
+	// /apis/coordination/v1/namespaces/{namespace}/leases
+	leasesGroup := router.Group("/apis/coordination/v1/namespaces/:namespace/leases")
+	{
+		leasesGroup.POST("", s.createLeaseHandlerGin)
+		leasesGroup.GET("", watchable[*api.Lease](s, store.Leases, s.listLeasesHandlerGin))
+		leasesGroup.GET("/:name", s.getLeaseHandlerGin)
+		leasesGroup.PUT("/:name", s.updateLeaseHandlerGin)
+		leasesGroup.DELETE("/:name", s.deleteLeaseHandlerGin)
+	}
+
+	// NetworkPolicy routes
+	// /apis/networking/v1/namespaces/{namespace}/networkpolicies
+	networkPoliciesGroup := router.Group("/apis/networking/v1/namespaces/:namespace/networkpolicies")
+	{
+		networkPoliciesGroup.POST("", s.createNetworkPolicyHandlerGin)
+		networkPoliciesGroup.GET("", watchable[*api.NetworkPolicy](s, store.NetworkPolicies, s.listNetworkPoliciesHandlerGin))
+		networkPoliciesGroup.GET("/:name", s.getNetworkPolicyHandlerGin)
+		networkPoliciesGroup.PUT("/:name", s.updateNetworkPolicyHandlerGin)
+		networkPoliciesGroup.DELETE("/:name", s.deleteNetworkPolicyHandlerGin)
+	}
+
+	// APIService routes, registering aggregated API servers
+	// /apis/apiregistration/v1/apiservices
+	apiServicesGroup := router.Group("/apis/apiregistration/v1/apiservices")
+	{
+		apiServicesGroup.POST("", s.createAPIServiceHandlerGin)
+		apiServicesGroup.GET("", watchable[*api.APIService](s, store.APIServices, s.listAPIServicesHandlerGin))
+		apiServicesGroup.GET("/:name", s.getAPIServiceHandlerGin)
+		apiServicesGroup.PUT("/:name", s.updateAPIServiceHandlerGin)
+		apiServicesGroup.DELETE("/:name", s.deleteAPIServiceHandlerGin)
+	}
+
+	// Component health, reported by heartbeats
+	// /api/v1/componentstatuses
+	componentsGroup := router.Group("/api/v1/componentstatuses")
+	{
+		componentsGroup.GET("", s.listComponentStatusesHandlerGin)
+		componentsGroup.PUT("/:name", s.reportComponentStatusHandlerGin)
+	}
+
+	// Actions on a whole collection, such as POST .../pods:batch
+	router.POST("/api/v1/namespaces/:namespace/:collectionAction", s.collectionActionHandlerGin)
+
+	// Request, conflict, and latency metrics in the Prometheus text format
+	router.GET("/metrics", s.metricsHandlerGin)
+
+	// Discovery of the groups, versions, and resources served above
+	// /api, /apis, /api/v1, /apis/{group}/{version}
+	registerDiscovery(router, s.aggregatedGroups)
+
+	// Group versions aggregated API servers serve
+	// /apis/{group}/{version}/...
+	router.NoRoute(s.aggregatedAPIHandlerGin)
+
+	return router
+}
+
+// Gin handler for creating a pod
+func (s *APIServer) createPodHandlerGin(c *gin.Context) {
+	var pod api.Pod
+	if err := c.ShouldBindJSON(&pod); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if code, body := s.createPod(c, &pod); body != nil {
+		c.JSON(code, body)
+		return
+	}
+	c.JSON(201, pod)
+}
+
+// createPod defaults, validates, and stores a new pod in the request's namespace. It
+// returns 201 and a nil body on success, leaving the created pod in pod, or the status
+// code and error body to answer with.
+func (s *APIServer) createPod(c *gin.Context, pod *api.Pod) (int, gin.H) {
+	ctx := c.Request.Context()
+	pod.Namespace = c.Param("namespace") // Ensure namespace from URL is used
+	if pod.Namespace == "" {
+		pod.Namespace = DefaultNamespace
+	}
+	pod.Phase = api.PodPending // Set initial phase
+	pod.NodeName = ""          // Not scheduled yet
+	validation.SetDefaults_Pod(pod)
+	if errs := validation.Validate_Pod(pod); len(errs) > 0 {
+		return 422, s.rejected(c, admissionValidation, "Pod", pod.Name, invalidBody("Pod", pod.Name, errs))
+	}
+	s.trackManagedFields(c, nil, pod)
+
+	if body := s.terminatingNamespace(c, "Pod", pod.Name, pod.Namespace); body != nil {
+		return 403, body
+	}
+	if body := s.admitPodSecurity(c, pod); body != nil {
+		return 403, body
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetPod(ctx, pod.Namespace, pod.Name); err == nil {
+			return 409, gin.H{"error": fmt.Sprintf("Failed to create pod: pod %s in namespace %s already exists", pod.Name, pod.Namespace)}
+		}
+		return 201, nil
+	}
+
+	if err := s.store.CreatePod(ctx, pod); err != nil {
+		log.Printf("Error creating pod %s/%s in store: %v", pod.Namespace, pod.Name, err) // Log the actual error
+		if errors.Is(err, store.ErrAlreadyExists) {
+			return 409, gin.H{"error": "Failed to create pod: " + err.Error()} // 409 Conflict
+		}
+		return storeErrorCode(err), gin.H{"error": "Failed to create pod: " + err.Error()}
+	}
+	log.Printf("Created pod %s/%s", pod.Namespace, pod.Name)
+	return 201, nil
+}
+
+// Gin handler for getting a specific pod
+func (s *APIServer) getPodHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	podName := c.Param("podname")
+	pod, err := s.store.GetPod(ctx, namespace, podName)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get pod: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(pod), pod)
+}
+
+// Gin handler for listing the pods in a namespace, or in all namespaces for
+// /api/v1/pods, that match the optional labelSelector and fieldSelector query parameters.
+func (s *APIServer) listPodsHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	labelSelector, fieldSelector, ok := listSelectors(c, fields.PodFields(&api.Pod{}))
+	if !ok {
+		return
+	}
+	pods, err := s.store.ListPods(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list pods: " + err.Error()})
+		return
+	}
+	if !labelSelector.Empty() || !fieldSelector.Empty() {
+		matched := pods[:0]
+		for _, pod := range pods {
+			if labelSelector.Matches(pod.Labels) && fieldSelector.Matches(fields.PodFields(pod)) {
+				matched = append(matched, pod)
+			}
+		}
+		pods = matched
+	}
+	respondWithList(c, pods)
+}
+
+// listSelectors parses the labelSelector and fieldSelector query parameters of a list
+// or collection delete, checking that the field selector only names fields in allowed.
+// If either is invalid it answers 400 and reports false.
+func listSelectors(c *gin.Context, allowed fields.Set) (labels.Selector, fields.Selector, bool) {
+	labelSelector, err := labels.Parse(c.Query("labelSelector"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	fieldSelector, err := fields.Parse(c.Query("fieldSelector"))
+	if err == nil {
+		err = fieldSelector.Validate(allowed)
+	}
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return labelSelector, fieldSelector, true
+}
+
+// Gin handler for deleting a specific pod
+func (s *APIServer) deletePodHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	podName := c.Param("podname")
+	if isDryRun(c) {
+		pod, err := s.store.GetPod(ctx, namespace, podName)
+		if err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete pod: " + err.Error()})
+			return
+		}
+		if pod.DeletionTimestamp != nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to delete pod: conflict: pod %s in namespace %s is already being deleted", podName, namespace)})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Pod %s/%s deleted (dry run)", namespace, podName)})
+		return
+	}
+	if err := s.store.DeletePod(ctx, namespace, podName); err != nil {
+		log.Printf("Error deleting pod %s/%s from store: %v", namespace, podName, err) // Log the actual error
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete pod: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete pod: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted pod %s/%s", namespace, podName)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Pod %s/%s deleted", namespace, podName)})
+}
+
+// Gin handler for deleting every pod in a namespace that matches the optional
+// labelSelector and fieldSelector query parameters. Pods already being deleted are
+// skipped. The response lists the pods that were marked for deletion.
+func (s *APIServer) deletePodCollectionHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	labelSelector, fieldSelector, ok := listSelectors(c, fields.PodFields(&api.Pod{}))
+	if !ok {
+		return
+	}
+
+	pods, err := s.store.ListPods(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list pods: " + err.Error()})
+		return
+	}
+
+	deleted := []api.Pod{}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !labelSelector.Matches(pod.Labels) || !fieldSelector.Matches(fields.PodFields(pod)) {
+			continue
+		}
+		if !isDryRun(c) {
+			if err := s.store.DeletePod(ctx, namespace, pod.Name); err != nil {
+				// Lost a race with another delete; the pod is going away either way.
+				log.Printf("Error deleting pod %s/%s from store: %v", namespace, pod.Name, err)
+				continue
+			}
+			if updated, err := s.store.GetPod(ctx, namespace, pod.Name); err == nil {
+				pod = updated
+			}
+		}
+		deleted = append(deleted, *pod)
+	}
+	log.Printf("Deleted %d pods in namespace %s (labelSelector=%q, fieldSelector=%q)", len(deleted), namespace, labelSelector, fieldSelector)
+	c.JSON(200, deleted)
+}
+
+// Gin handler for updating a specific pod
+func (s *APIServer) updatePodHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	podName := c.Param("podname")
+
+	var pod api.Pod
+	if err := c.ShouldBindJSON(&pod); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if pod.Name != podName {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Pod name in body (%s) does not match name in URL (%s)", pod.Name, podName)})
+		return
+	}
+	if pod.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Pod namespace in body (%s) does not match namespace in URL (%s)", pod.Namespace, namespace)})
+		return
+	}
+
+	// Ensure the pod exists before updating (optional, store might handle this)
+	existing, err := s.store.GetPod(ctx, namespace, podName)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update pod: " + err.Error()})
+		return
+	}
+	api.ConvertDeprecatedPodPhase(&pod, existing)
+	validation.SetDefaults_Pod(&pod)
+	if s.rejectInvalid(c, "Pod", pod.Name, validation.Validate_PodUpdate(&pod, existing)) {
+		return
+	}
+	s.trackManagedFields(c, existing, &pod)
+
+	if isDryRun(c) {
+		if err := store.ValidatePodUpdate(existing, &pod); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update pod: " + err.Error()})
+			return
+		}
+		c.JSON(200, pod)
+		return
+	}
+
+	allocated, err := s.assignPodNetwork(ctx, &pod, existing)
+	if err != nil {
+		log.Printf("Failed to allocate an IP for pod %s/%s: %v", namespace, podName, err)
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update pod: " + err.Error()})
+		return
+	}
+	if err := s.store.UpdatePod(ctx, &pod); err != nil {
+		if allocated {
+			s.podIPs.Release(podIPOwner(&pod))
+		}
+		log.Printf("Failed to update pod in store: %v", err)
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update pod: " + err.Error()})
+		return
+	}
+
+	c.JSON(200, pod)
+}
+
+// Gin handler for creating a node
+func (s *APIServer) createNodeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	var node api.Node
+	if err := c.ShouldBindJSON(&node); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	validation.SetDefaults_Node(&node)
+	if s.rejectInvalid(c, "Node", node.Name, validation.Validate_Node(&node)) {
+		return
+	}
+	s.holdForApproval(&node, nil)
+	s.trackManagedFields(c, nil, &node)
+
+	if isDryRun(c) {
+		if _, err := s.store.GetNode(ctx, node.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create node: node %s already exists", node.Name)})
+			return
+		}
+		c.JSON(201, node)
+		return
+	}
+
+	if err := s.store.CreateNode(ctx, &node); err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create node: " + err.Error()})
+		return
+	}
+	if node.PendingApproval {
+		log.Printf("Registered node %s, pending approval", node.Name)
+	} else {
+		log.Printf("Registered node %s", node.Name)
+	}
+	c.JSON(201, node)
+}
+
+// Gin handler for getting a specific node
+func (s *APIServer) getNodeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	nodeName := c.Param("nodename")
+	node, err := s.store.GetNode(ctx, nodeName)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get node: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(node), node)
+}
+
+// Gin handler for listing all nodes, or those that match the optional labelSelector
+// and fieldSelector query parameters
+func (s *APIServer) listNodesHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	labelSelector, fieldSelector, ok := listSelectors(c, fields.NodeFields(&api.Node{}))
+	if !ok {
+		return
+	}
+	nodes, err := s.store.ListNodes(ctx)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list nodes: " + err.Error()})
+		return
+	}
+	if !labelSelector.Empty() || !fieldSelector.Empty() {
+		matched := nodes[:0]
+		for _, node := range nodes {
+			if labelSelector.Matches(node.Labels) && fieldSelector.Matches(fields.NodeFields(node)) {
+				matched = append(matched, node)
+			}
+		}
+		nodes = matched
+	}
+	respondWithList(c, nodes)
+}
+
+// Gin handler for deleting a specific node. Pods bound to the node are left for the
+// node lifecycle controller to fail or reschedule.
+func (s *APIServer) deleteNodeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	nodeName := c.Param("nodename")
+	if isDryRun(c) {
+		if _, err := s.store.GetNode(ctx, nodeName); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete node: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Node %s deleted (dry run)", nodeName)})
+		return
+	}
+	if err := s.store.DeleteNode(ctx, nodeName); err != nil {
+		log.Printf("Error deleting node %s from store: %v", nodeName, err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete node: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete node: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Deleted node %s", nodeName)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Node %s deleted", nodeName)})
+}
+
+// Gin handler for updating a specific node
+func (s *APIServer) updateNodeHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	nodeName := c.Param("nodename")
+	var updatedNode api.Node
+
+	if err := c.ShouldBindJSON(&updatedNode); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	// Ensure the name from the path is used and matches the body if provided.
+	if updatedNode.Name != "" && updatedNode.Name != nodeName {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Node name in body (%s) does not match path (%s)", updatedNode.Name, nodeName)})
+		return
+	}
+	updatedNode.Name = nodeName // Use name from path
+	validation.SetDefaults_Node(&updatedNode)
+	if s.rejectInvalid(c, "Node", updatedNode.Name, validation.Validate_Node(&updatedNode)) {
+		return
+	}
+
+	// Check if node exists before updating - GetNode also serves this purpose
+	existing, err := s.store.GetNode(ctx, nodeName)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update node: " + err.Error()})
+		return
+	}
+	s.holdForApproval(&updatedNode, existing)
+	s.trackManagedFields(c, existing, &updatedNode)
+
+	if isDryRun(c) {
+		c.JSON(200, updatedNode)
+		return
+	}
+
+	if err := s.store.UpdateNode(ctx, &updatedNode); err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update node: " + err.Error()})
+		return
+	}
+	log.Printf("Updated node %s", updatedNode.Name)
+	c.JSON(200, updatedNode)
+}
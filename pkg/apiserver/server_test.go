@@ -0,0 +1,2035 @@
+package apiserver
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/podsecurity"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+	"github.com/gin-gonic/gin"
+)
+
+// newTestServer starts an API server on a freshly bootstrapped store, configured by
+// configure if it isn't nil, and returns it with a client for it. The server is shut
+// down when the test ends.
+func newTestServer(t *testing.T, configure func(*APIServer)) (*APIServer, *api.Client) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	if configure != nil {
+		configure(server)
+	}
+	srv := httptest.NewServer(server.Handler())
+	t.Cleanup(srv.Close)
+	client, err := api.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return server, client
+}
+
+func TestServeUntilCancelled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	podIPs, err := NewPodIPAllocator("10.0.0.0/30")
+	if err != nil {
+		t.Fatalf("NewPodIPAllocator: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- NewAPIServer(dataStore, podIPs).Serve(ctx, ln) }()
+
+	client, err := api.NewClient("http://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.CreatePod("", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx:1.25"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	pod, err := client.GetPod(DefaultNamespace, "web")
+	if err != nil {
+		t.Fatalf("GetPod: %v", err)
+	}
+	if pod.Namespace != DefaultNamespace || pod.Phase != api.PodPending {
+		t.Errorf("expected a Pending pod in %s, got %s in %q", DefaultNamespace, pod.Phase, pod.Namespace)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned %v after cancellation, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after cancellation")
+	}
+	if _, err := client.GetPod(DefaultNamespace, "web"); err == nil {
+		t.Error("expected the server to stop answering after cancellation")
+	}
+}
+
+func TestChaosFailsRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.Chaos = chaos.NewWithSource(chaos.Config{APIErrorRate: 1}, rand.NewSource(1))
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected an injected 500, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestStoreErrorStatusCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	handler := NewAPIServer(dataStore, nil).Handler()
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	configMap := `{"name":"settings","data":{"a":"1"}}`
+	if code := do(http.MethodPost, "/api/v1/namespaces/default/configmaps", configMap); code != http.StatusCreated {
+		t.Fatalf("creating a config map: %d", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/namespaces/default/pods", `{"name":"web","image":"nginx"}`); code != http.StatusCreated {
+		t.Fatalf("creating a pod: %d", code)
+	}
+	do(http.MethodDelete, "/api/v1/namespaces/default/pods/web", "")
+
+	for _, tc := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/v1/namespaces/default/configmaps", configMap, http.StatusConflict},
+		{http.MethodGet, "/api/v1/namespaces/default/configmaps/missing", "", http.StatusNotFound},
+		{http.MethodPatch, "/api/v1/namespaces/default/configmaps/missing", `{"data":{"a":"2"}}`, http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/namespaces/default/pods/web", "", http.StatusConflict},
+	} {
+		if code := do(tc.method, tc.path, tc.body); code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.path, tc.want, code)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.RequestTimeout = time.Nanosecond
+	handler := server.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected a 504 once the request timed out, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// A watch isn't cut off: it lasts until the client goes away.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces?watch=true", nil).WithContext(ctx))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ADDED"`) {
+		t.Errorf("expected the watch to run until the client left, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPriorityLevels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		method, route, path, userAgent string
+		want                           string // "" for exempt
+	}{
+		{http.MethodPut, "/api/v1/nodes/:nodename", "/api/v1/nodes/node1", "kubelet/k8s-lite-go", priorityNodeHigh},
+		{http.MethodPut, "/apis/coordination.k8s.io/v1/namespaces/:namespace/leases/:name", "/apis/coordination.k8s.io/v1/namespaces/kube-node-lease/leases/node1", "kubelet/k8s-lite-go", priorityNodeHigh},
+		{http.MethodPut, "/api/v1/namespaces/:namespace/pods/:podname", "/api/v1/namespaces/default/pods/web", "kubelet/k8s-lite-go", priorityNodeHigh},
+		{http.MethodPut, "/api/v1/namespaces/:namespace/pods/:podname", "/api/v1/namespaces/default/pods/web", "kubectl-lite/k8s-lite-go", priorityWorkload},
+		{http.MethodGet, "/api/v1/namespaces/:namespace/pods/:podname", "/api/v1/namespaces/default/pods/web", "kubectl-lite/k8s-lite-go", priorityWorkload},
+		{http.MethodGet, "/api/v1/namespaces/:namespace/pods", "/api/v1/namespaces/default/pods", "kubectl-lite/k8s-lite-go", priorityBulk},
+		{http.MethodGet, "/api/v1/nodes", "/api/v1/nodes", "kubelet/k8s-lite-go", priorityBulk},
+		{http.MethodGet, "/api/v1/namespaces/:namespace/pods", "/api/v1/namespaces/default/pods?watch=true", "kubelet/k8s-lite-go", ""},
+		{http.MethodGet, "/api/v1/namespaces/:namespace/pods/:podname/log", "/api/v1/namespaces/default/pods/web/log", "kubectl-lite/k8s-lite-go", ""},
+		{http.MethodGet, "/healthz", "/healthz", "curl/8.0", ""},
+	} {
+		var level string
+		var exempt bool
+		router := gin.New()
+		router.Handle(tc.method, tc.route, func(c *gin.Context) { level, exempt = priorityLevelFor(c) })
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("User-Agent", tc.userAgent)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if want := tc.want; exempt != (want == "") || level != want {
+			t.Errorf("%s %s from %s: expected priority level %q, got %q (exempt %v)", tc.method, tc.path, tc.userAgent, want, level, exempt)
+		}
+	}
+}
+
+func TestFlowControl(t *testing.T) {
+	fc := newFlowController(10) // 4 node-high, 4 workload, and 2 bulk seats
+	ctx := context.Background()
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, ok := fc.acquire(ctx, priorityBulk, "lister")
+		if !ok {
+			t.Fatalf("expected bulk seat %d to be free", i)
+		}
+		releases = append(releases, release)
+	}
+
+	// Lists taking every bulk seat leave node heartbeats alone.
+	release, ok := fc.acquire(ctx, priorityNodeHigh, "kubelet")
+	if !ok {
+		t.Fatal("expected a node-high seat while the bulk seats are taken")
+	}
+	release()
+
+	// Waiting flows are served in turn: the greedy lister's backlog doesn't hold up
+	// the other client's list.
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	wait := func(flow string) {
+		defer wg.Done()
+		release, ok := fc.acquire(ctx, priorityBulk, flow)
+		if !ok {
+			t.Errorf("expected %s to get a seat", flow)
+			return
+		}
+		mu.Lock()
+		served = append(served, flow)
+		mu.Unlock()
+		release()
+	}
+	queued := func(n int) {
+		for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+			fc.mu.Lock()
+			q := fc.levels[priorityBulk].queued
+			fc.mu.Unlock()
+			if q == n || time.Now().After(deadline) {
+				return
+			}
+		}
+	}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go wait("lister")
+		queued(i + 1)
+	}
+	wg.Add(1)
+	go wait("dashboard")
+	queued(4)
+
+	releases[0]()
+	wg.Wait()
+	releases[1]()
+	if strings.Join(served[:2], ",") != "lister,dashboard" {
+		t.Errorf("expected the flows to take turns, got %v", served)
+	}
+
+	// The queue holds 4 requests per seat; past that, requests are turned away at once,
+	// and those that give up waiting leave it.
+	for i := 0; i < 2; i++ {
+		release, _ := fc.acquire(ctx, priorityBulk, "lister")
+		defer release()
+	}
+	waitCtx, cancel := context.WithCancel(ctx)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := fc.acquire(waitCtx, priorityBulk, "lister"); ok {
+				t.Error("expected a request that gave up waiting to be rejected")
+			}
+		}()
+	}
+	queued(8)
+	if _, ok := fc.acquire(ctx, priorityBulk, "dashboard"); ok {
+		t.Error("expected a request to be rejected once the queue is full")
+	}
+	cancel()
+	wg.Wait()
+
+	var metrics bytes.Buffer
+	fc.writeTo(&metrics)
+	for _, want := range []string{
+		`apiserver_flowcontrol_nominal_limit_seats{priority_level="bulk"} 2`,
+		`apiserver_flowcontrol_current_executing_requests{priority_level="bulk"} 2`,
+		`apiserver_flowcontrol_current_inqueue_requests{priority_level="bulk"} 0`,
+		`apiserver_flowcontrol_rejected_requests_total{priority_level="bulk"} 9`,
+	} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("expected %s in the metrics, got:\n%s", want, metrics.String())
+		}
+	}
+}
+
+func TestMetricsCountWriteConflicts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	handler := NewAPIServer(dataStore, nil).Handler()
+
+	for i := 0; i < 2; i++ {
+		body := strings.NewReader(`{"name":"web","image":"nginx"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/pods", body)
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: %d: %s", rec.Code, rec.Body)
+	}
+
+	for _, want := range []string{
+		`apiserver_request_total{verb="create",resource="pods",code="201"} 1`,
+		`apiserver_request_total{verb="create",resource="pods",code="409"} 1`,
+		`apiserver_write_conflicts_total{verb="create",resource="pods"} 1`,
+		`apiserver_write_duration_seconds_count{verb="create",resource="pods"} 2`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %s:\n%s", want, rec.Body)
+		}
+	}
+}
+
+func TestMetricsCountApplyConflicts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := httptest.NewServer(NewAPIServer(dataStore, nil).Handler())
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	apply := func(manager, tier string) error {
+		var pod api.Pod
+		_, err := client.Apply("Pod", DefaultNamespace, "web", []byte("image: nginx\nlabels:\n  tier: "+tier+"\n"), api.ApplyOptions{FieldManager: manager}, &pod)
+		return err
+	}
+
+	// A second manager applying another value to a field conflicts with the first.
+	if err := apply("kubectl-lite", "frontend"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	var statusErr *api.StatusError
+	if err := apply("other", "backend"); !errors.As(err, &statusErr) || statusErr.Code != http.StatusConflict {
+		t.Fatalf("expected the second manager's apply to conflict, got %v", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	metrics, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`apiserver_request_total{verb="patch",resource="pods",code="409"} 1`,
+		`apiserver_write_conflicts_total{verb="patch",resource="pods"} 1`,
+		`apiserver_write_duration_seconds_count{verb="patch",resource="pods"} 2`,
+	} {
+		if !strings.Contains(string(metrics), want) {
+			t.Errorf("metrics missing %s:\n%s", want, metrics)
+		}
+	}
+}
+
+func TestRequestKeyFor(t *testing.T) {
+	for _, tc := range []struct {
+		method, route string
+		want          requestKey
+	}{
+		{"GET", "/api/v1/namespaces/:namespace/pods", requestKey{"list", "pods"}},
+		{"PUT", "/api/v1/namespaces/:namespace/pods/:podname", requestKey{"update", "pods"}},
+		{"POST", "/api/v1/namespaces/:namespace/pods/:podname/eviction", requestKey{"create", "pods/eviction"}},
+		{"DELETE", "/api/v1/namespaces/:namespace/pods", requestKey{"deletecollection", "pods"}},
+		{"GET", "/api/v1/namespaces/:namespace", requestKey{"get", "namespaces"}},
+		{"PUT", "/apis/apps/v1/namespaces/:namespace/deployments/:name", requestKey{"update", "deployments"}},
+		{"DELETE", "/api/v1/nodes/:nodename", requestKey{"delete", "nodes"}},
+		{"GET", "/api/v1/pods", requestKey{"list", "pods"}},
+		{"GET", "/api/v1/nodes/:nodename/proxy/*path", requestKey{"get", "nodes/proxy"}},
+	} {
+		got, ok := requestKeyFor(tc.method, tc.route)
+		if !ok || got != tc.want {
+			t.Errorf("requestKeyFor(%s, %s) = %+v, %v; want %+v", tc.method, tc.route, got, ok, tc.want)
+		}
+	}
+	if _, ok := requestKeyFor("GET", "/metrics"); ok {
+		t.Error("expected /metrics not to be counted as an API request")
+	}
+}
+
+func TestComponentStatuses(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	if err := client.ReportComponentStatus(&api.ComponentStatus{Name: "scheduler", HeartbeatPeriodSeconds: 10}); err != nil {
+		t.Fatalf("ReportComponentStatus: %v", err)
+	}
+	statuses, err := client.ListComponentStatuses()
+	if err != nil {
+		t.Fatalf("ListComponentStatuses: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "scheduler" || !statuses[0].Healthy || statuses[0].LastHeartbeatTime.IsZero() {
+		t.Fatalf("expected a healthy scheduler, got %+v", statuses)
+	}
+
+	// Three missed heartbeats make a component unhealthy.
+	status := statuses[0]
+	componentHealth(&status, status.LastHeartbeatTime.Add(30*time.Second))
+	if !status.Healthy {
+		t.Errorf("expected the scheduler to be healthy after 30s, got %+v", status)
+	}
+	componentHealth(&status, status.LastHeartbeatTime.Add(31*time.Second))
+	if status.Healthy || status.Message != "no heartbeat for 31s" {
+		t.Errorf("expected the scheduler to be unhealthy after 31s, got %+v", status)
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestServer(t, nil)
+	// Bootstrapping again, as every start does, creates nothing twice.
+	if err := bootstrap(ctx, server.store); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	namespaces, _ := server.store.ListNamespaces(ctx)
+	if len(namespaces) != len(systemNamespaces) {
+		t.Errorf("expected the %d system namespaces, got %d", len(systemNamespaces), len(namespaces))
+	}
+
+	if err := server.publishClusterInfo(ctx, &net.TCPAddr{IP: net.IPv6unspecified, Port: 8080}); err != nil {
+		t.Fatalf("publishClusterInfo: %v", err)
+	}
+	clusterInfo, err := client.GetConfigMap(api.PublicNamespace, api.ClusterInfoConfigMap)
+	if err != nil || clusterInfo.Data["server"] != "http://localhost:8080" {
+		t.Errorf("expected cluster-info to publish the server's address, got %+v, %v", clusterInfo, err)
+	}
+	var statusErr *api.StatusError
+	if err := client.DeleteNamespace(api.SystemNamespace); !errors.As(err, &statusErr) || statusErr.Code != http.StatusForbidden {
+		t.Errorf("expected deleting kube-system to be forbidden, got %v", err)
+	}
+
+	created, err := client.CreateConfigMap("", &api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: "settings"}, Data: map[string]string{"mode": "fast"}})
+	if err != nil || created.Namespace != DefaultNamespace {
+		t.Fatalf("CreateConfigMap: %+v, %v", created, err)
+	}
+	if _, err := client.CreateConfigMap("", &api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: "bad"}, Data: map[string]string{"no spaces": "x"}}); err == nil {
+		t.Errorf("expected a config map key with a space to be rejected")
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	created, err := client.CreateSecret("", &api.Secret{ObjectMeta: api.ObjectMeta{Name: "db"}, Data: map[string][]byte{"password": []byte("s3cret")}})
+	if err != nil || created.Type != api.SecretTypeOpaque {
+		t.Fatalf("expected the secret to default to Opaque, got %+v, %v", created, err)
+	}
+	got, err := client.GetSecret(DefaultNamespace, "db")
+	if err != nil || string(got.Data["password"]) != "s3cret" {
+		t.Errorf("expected the secret's data back, got %+v, %v", got, err)
+	}
+	bad := &api.Secret{ObjectMeta: api.ObjectMeta{Name: "regcred"}, Type: api.SecretTypeDockerConfigJSON}
+	if _, err := client.CreateSecret("", bad); err == nil {
+		t.Errorf("expected a docker-registry secret without credentials to be rejected")
+	}
+	if err := client.DeleteSecret(DefaultNamespace, "db"); err != nil {
+		t.Errorf("DeleteSecret: %v", err)
+	}
+}
+
+func TestNodeApproval(t *testing.T) {
+	server, client := newTestServer(t, nil)
+	if _, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "old"}, Status: api.NodeReady}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+
+	server.RequireNodeApproval = true
+	node, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "new"}, Status: api.NodeReady})
+	if err != nil || !node.PendingApproval || node.Status != api.NodeNotReady || !node.Unschedulable {
+		t.Fatalf("expected a new node to be held NotReady and unschedulable, got %+v, %v", node, err)
+	}
+	// Its kubelet reporting it Ready, or anyone uncordoning it, doesn't let it in.
+	node.Status, node.Unschedulable, node.PendingApproval = api.NodeReady, false, false
+	if err := client.UpdateNode(node); err != nil || !node.PendingApproval || node.Status != api.NodeNotReady || !node.Unschedulable {
+		t.Errorf("expected an update to keep the node held, got %+v, %v", node, err)
+	}
+	if old, _ := client.GetNode("old"); old.PendingApproval || old.Status != api.NodeReady {
+		t.Errorf("expected a node registered before approval was required to be left alone, got %+v", old)
+	}
+
+	approved, err := client.ApproveNode("new")
+	if err != nil || approved.PendingApproval || approved.Status != api.NodeReady || approved.Unschedulable {
+		t.Fatalf("expected the approved node to be Ready and schedulable, got %+v, %v", approved, err)
+	}
+	approved.Unschedulable = true
+	if err := client.UpdateNode(approved); err != nil || !approved.Unschedulable || approved.PendingApproval {
+		t.Errorf("expected an approved node to be cordoned like any other, got %+v, %v", approved, err)
+	}
+	if _, err := client.ApproveNode("missing"); err == nil {
+		t.Errorf("expected approving a missing node to fail")
+	}
+}
+
+func TestLeaseUpdatesConflictOnStaleReads(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	created, err := client.CreateLease(api.NodeLeaseNamespace, &api.Lease{ObjectMeta: api.ObjectMeta{Name: "node1"}})
+	if err != nil {
+		t.Fatalf("CreateLease: %v", err)
+	}
+	if created.DurationSeconds != 40 {
+		t.Errorf("expected the default duration of 40s, got %d", created.DurationSeconds)
+	}
+
+	now := time.Now()
+	first, second := *created, *created
+	first.HolderIdentity, first.RenewTime = "a", &now
+	second.HolderIdentity, second.RenewTime = "b", &now
+	if err := client.UpdateLease(&first); err != nil {
+		t.Fatalf("UpdateLease: %v", err)
+	}
+	if err := client.UpdateLease(&second); !api.IsConflict(err) {
+		t.Fatalf("expected the second update from the same read to conflict, got %v", err)
+	}
+	lease, err := client.GetLease(api.NodeLeaseNamespace, "node1")
+	if err != nil || lease.HolderIdentity != "a" || lease.ResourceVersion != first.ResourceVersion {
+		t.Fatalf("expected a to hold the lease at %s, got %+v, %v", first.ResourceVersion, lease, err)
+	}
+}
+
+func TestRecoverPanicWithRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	handler := NewAPIServer(dataStore, nil).Handler()
+	handler.(*gin.Engine).GET("/api/v1/panic", func(c *gin.Context) { panic("boom") })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/panic", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected a 500, got %d: %s", rec.Code, rec.Body)
+	}
+	id := rec.Header().Get(api.RequestIDHeader)
+	if id == "" {
+		t.Fatal("expected a request ID header")
+	}
+	for _, want := range []string{`"error":"Internal server error: boom"`, `"requestID":"` + id + `"`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("body missing %s: %s", want, rec.Body)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil)
+	req.Header.Set(api.RequestIDHeader, "client-chosen-1")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get(api.RequestIDHeader); got != "client-chosen-1" {
+		t.Errorf("expected the client's request ID to be kept, got %q", got)
+	}
+}
+
+func TestClientErrorsIncludeRequestID(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	_, err := client.GetPod(DefaultNamespace, "missing")
+	if err == nil || !strings.Contains(err.Error(), "not found") || !strings.Contains(err.Error(), "request ID ") {
+		t.Fatalf("expected a not found error with a request ID, got %v", err)
+	}
+}
+
+func TestListsAnswerNotModifiedForCurrentETag(t *testing.T) {
+	ctx := context.Background()
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	handler := NewAPIServer(dataStore, nil).Handler()
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, name := range []string{"web", "api", "db", "cache", "queue", "worker", "proxy", "auth", "search", "mail"} {
+		if err := dataStore.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: DefaultNamespace}, Image: "nginx"}); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	first := get("/api/v1/namespaces/default/pods", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected a 200 with an ETag, got %d %q", first.Code, etag)
+	}
+	// The store holds the pods in no particular order; the list and its ETag are the
+	// same on every request all the same.
+	for i := 0; i < 20; i++ {
+		if rec := get("/api/v1/namespaces/default/pods", ""); rec.Header().Get("ETag") != etag || rec.Body.String() != first.Body.String() {
+			t.Fatalf("expected the unchanged list to keep ETag %s, got %s", etag, rec.Header().Get("ETag"))
+		}
+		if rec := get("/api/v1/namespaces/default/pods", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Fatalf("expected an empty 304 for an unchanged list, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+
+	pod, _ := dataStore.GetPod(ctx, DefaultNamespace, "web")
+	podETag := get("/api/v1/namespaces/default/pods/web", "").Header().Get("ETag")
+	if podETag != `"`+pod.ResourceVersion+`"` {
+		t.Errorf("expected the pod's ETag to be its resourceVersion %s, got %s", pod.ResourceVersion, podETag)
+	}
+	pod.Phase = api.PodRunning
+	if err := dataStore.UpdatePod(ctx, pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	if rec := get("/api/v1/namespaces/default/pods", etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("expected a 200 with a new ETag after a write, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+	if rec := get("/api/v1/namespaces/default/pods/web", podETag); rec.Code != http.StatusOK {
+		t.Errorf("expected a 200 for a changed pod, got %d", rec.Code)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	ctx := context.Background()
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	for _, name := range []string{"e", "c", "a", "d", "b"} {
+		if err := dataStore.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: DefaultNamespace}, Image: "nginx"}); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	server := httptest.NewServer(NewAPIServer(dataStore, nil).Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/namespaces/default/pods?limit=2")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	var page []api.Pod
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("decoding page: %v", err)
+	}
+	next := resp.Header.Get(api.ContinueHeader)
+	if len(page) != 2 || page[0].Name != "a" || page[1].Name != "b" || next == "" {
+		t.Fatalf("expected pods a and b and a continue token, got %v %q", page, next)
+	}
+
+	for _, query := range []string{"limit=-1", "limit=two", "continue=%25%25"} {
+		resp, err := http.Get(server.URL + "/api/v1/namespaces/default/pods?" + query)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", query, resp.StatusCode)
+		}
+	}
+
+	client, err := api.NewClient(server.URL, api.WithChunkSize(2))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	pods, err := client.ListPods(DefaultNamespace, "")
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	if strings.Join(names, ",") != "a,b,c,d,e" {
+		t.Errorf("expected every pod across three pages, got %v", names)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.CORSAllowedOrigins = []string{"http://localhost:8081"}
+	handler := server.Handler()
+	send := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/namespaces/default/pods", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodOptions, "http://localhost:8081")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "http://localhost:8081" || !strings.Contains(rec.Header().Get("Access-Control-Allow-Methods"), "POST") {
+		t.Errorf("expected a 204 preflight response allowing POST, got %d %v", rec.Code, rec.Header())
+	}
+	rec = send(http.MethodGet, "http://localhost:8081")
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "http://localhost:8081" || !strings.Contains(rec.Header().Get("Access-Control-Expose-Headers"), "ETag") || len(rec.Header().Values("Vary")) != 2 {
+		t.Errorf("expected a 200 with CORS headers, got %d %v", rec.Code, rec.Header())
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("expected credentials not to be allowed, got %v", rec.Header())
+	}
+	if rec := send(http.MethodGet, "http://evil.example"); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected no CORS headers for another origin, got %v", rec.Header())
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.CORSAllowedOrigins = []string{"*"}
+	handler := server.Handler()
+
+	for _, method := range []string{http.MethodOptions, http.MethodGet} {
+		req := httptest.NewRequest(method, "/api/v1/namespaces/default/pods", nil)
+		req.Header.Set("Origin", "http://evil.example")
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		// Any origin may call the API, but never with the browser's credentials.
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("%s: expected Access-Control-Allow-Origin *, not the request's origin, got %q", method, got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("%s: expected no Access-Control-Allow-Credentials, got %q", method, got)
+		}
+	}
+}
+
+func TestGzipLargeResponses(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestServer(t, nil)
+	for i := 0; i < 50; i++ {
+		pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: DefaultNamespace}, Image: "nginx"}
+		if err := server.store.CreatePod(ctx, pod); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	handler := server.Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzipped list, got headers %v", rec.Header())
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	var pods []api.Pod
+	if err := json.NewDecoder(gz).Decode(&pods); err != nil || len(pods) != 50 {
+		t.Fatalf("expected 50 pods in the decompressed body, got %d, %v", len(pods), err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods/web-0", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected a small response to go uncompressed")
+	}
+
+	if pods, err := client.ListPods(DefaultNamespace, ""); err != nil || len(pods) != 50 {
+		t.Fatalf("expected the client to list 50 pods, got %d, %v", len(pods), err)
+	}
+}
+
+func TestClientResponseCacheRevalidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	handler := NewAPIServer(dataStore, nil).Handler()
+	var notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		if rec.Code == http.StatusNotModified {
+			notModified++
+		}
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL, api.WithResponseCache())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for _, name := range []string{"web", "api", "db", "cache", "queue", "worker", "proxy", "auth"} {
+		if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: name}, Image: "nginx"}); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	// The store holds the pods in no particular order, but every relist of them is
+	// answered 304 all the same.
+	for i := 0; i < 10; i++ {
+		pods, err := client.ListPods(DefaultNamespace, "")
+		if err != nil || len(pods) != 8 {
+			t.Fatalf("ListPods: %v, %v", pods, err)
+		}
+		pods[0].Phase = api.PodFailed // Must not leak into the cache
+	}
+	if notModified != 9 {
+		t.Errorf("expected the nine relists to be answered 304, got %d", notModified)
+	}
+
+	pod, err := client.GetPod(DefaultNamespace, "web")
+	if err != nil {
+		t.Fatalf("GetPod: %v", err)
+	}
+	pod.Labels = map[string]string{"app": "web"}
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	pods, err := client.ListPods(DefaultNamespace, "")
+	if err != nil || len(pods) != 8 {
+		t.Fatalf("ListPods: %v, %v", pods, err)
+	}
+	for _, p := range pods {
+		if p.Phase != api.PodPending || (p.Name == "web") != (p.Labels["app"] == "web") {
+			t.Fatalf("expected the relist after an update to see it, got %v", pods)
+		}
+	}
+}
+
+func TestClientMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	handler := NewAPIServer(dataStore, nil).Handler()
+	var requests int
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		if requests == 2 {
+			http.Error(w, `{"error": "overloaded"}`, http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	var observed []int
+	observe := api.ObserveResponses(func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+		if err == nil {
+			observed = append(observed, resp.StatusCode)
+		}
+	})
+	retry := func(next http.RoundTripper) http.RoundTripper {
+		return api.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err == nil && resp.StatusCode == http.StatusServiceUnavailable && req.Method == http.MethodGet {
+				resp.Body.Close()
+				return next.RoundTrip(req)
+			}
+			return resp, err
+		})
+	}
+	token := api.InterceptRequests(func(req *http.Request) { req.Header.Set("Authorization", "Bearer fresh") })
+	client, err := api.NewClient(srv.URL, api.WithMiddleware(observe, retry, token), api.WithTransport(http.DefaultTransport))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	// The server fails the first get; the retry, inside the observer, hides that from it.
+	if _, err := client.GetPod(DefaultNamespace, "web"); err != nil {
+		t.Fatalf("expected the get to be retried, got %v", err)
+	}
+	if want := []int{201, 200}; !reflect.DeepEqual(observed, want) {
+		t.Errorf("expected the observer to see %v, got %v", want, observed)
+	}
+	if want := []string{"Bearer fresh", "Bearer fresh", "Bearer fresh"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("expected every request to carry the intercepted token, got %q", tokens)
+	}
+}
+
+func TestCreatePodsInBatch(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestServer(t, nil)
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "taken"}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+
+	results, err := client.CreatePods(DefaultNamespace, []api.Pod{
+		{ObjectMeta: api.ObjectMeta{Name: "web-1"}, Image: "nginx"},
+		{ObjectMeta: api.ObjectMeta{Name: "taken"}, Image: "nginx"},
+		{ObjectMeta: api.ObjectMeta{Name: "no-image"}},
+		{ObjectMeta: api.ObjectMeta{Name: "web-2"}, Image: "nginx"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePods: %v", err)
+	}
+	var codes []int
+	for _, r := range results {
+		codes = append(codes, r.Code)
+	}
+	if fmt.Sprint(codes) != "[201 409 422 201]" {
+		t.Fatalf("expected results [201 409 422 201], got %v", results)
+	}
+	if results[0].Err() != nil || results[0].Pod == nil || results[0].Pod.UID == "" || results[0].Pod.Phase != api.PodPending {
+		t.Errorf("expected the created pod back, got %+v", results[0])
+	}
+	if err := results[1].Err(); !strings.Contains(fmt.Sprint(err), "already exists") {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+	if _, err := server.store.GetPod(ctx, DefaultNamespace, "web-2"); err != nil {
+		t.Errorf("expected the pods after a failure to be created: %v", err)
+	}
+
+	metrics, err := client.Metrics()
+	if err != nil {
+		t.Fatalf("Metrics: %v", err)
+	}
+	if want := `apiserver_request_total{verb="create",resource="pods:batch",code="200"} 1`; !strings.Contains(metrics, want) {
+		t.Errorf("metrics missing %s:\n%s", want, metrics)
+	}
+}
+
+func TestServerSideApply(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := httptest.NewServer(NewAPIServer(dataStore, nil).Handler())
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	opts := api.ApplyOptions{FieldManager: "kubectl-lite"}
+	apply := func(config string, opts api.ApplyOptions) (*api.Pod, bool, error) {
+		var pod api.Pod
+		created, err := client.Apply("Pod", DefaultNamespace, "web", []byte(config), opts, &pod)
+		return &pod, created, err
+	}
+
+	const config = "image: nginx\nlabels:\n  app: web\n  tier: frontend\n"
+	pod, created, err := apply(config, opts)
+	if err != nil || !created {
+		t.Fatalf("expected the first apply to create the pod, got created=%v, err=%v", created, err)
+	}
+	if len(pod.ManagedFields) != 1 || fmt.Sprint(pod.ManagedFields[0].Fields) != "[image labels.app labels.tier]" {
+		t.Fatalf("expected kubectl-lite to own the applied fields, got %+v", pod.ManagedFields)
+	}
+	again, created, err := apply(config, opts)
+	if err != nil || created || again.ResourceVersion != pod.ResourceVersion {
+		t.Fatalf("expected applying the same config to change nothing, got created=%v, err=%v, resourceVersion %s -> %s", created, err, pod.ResourceVersion, again.ResourceVersion)
+	}
+
+	// Another manager takes over a field with an ordinary update.
+	pod.Labels["tier"] = "backend"
+	body, _ := json.Marshal(pod)
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/api/v1/namespaces/default/pods/web", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "scheduler/k8s-lite-go")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("update as the scheduler: %v %v", resp, err)
+	}
+	resp.Body.Close()
+
+	_, _, err = apply(config, opts)
+	var statusErr *api.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusConflict || !strings.Contains(err.Error(), `conflict with "scheduler": labels.tier`) {
+		t.Fatalf("expected a conflict with the scheduler over labels.tier, got %v", err)
+	}
+	pod, _, err = apply(config, api.ApplyOptions{FieldManager: "kubectl-lite", Force: true})
+	if err != nil || pod.Labels["tier"] != "frontend" {
+		t.Fatalf("expected a forced apply to take labels.tier back, got %v, %v", pod.Labels, err)
+	}
+
+	// A field left out of the next apply is removed.
+	pod, _, err = apply("image: nginx\nlabels:\n  app: web\n", opts)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if _, ok := pod.Labels["tier"]; ok || pod.Labels["app"] != "web" {
+		t.Errorf("expected labels.tier to be removed, got %v", pod.Labels)
+	}
+
+	if _, _, err := apply("kind: Pod\nname: other\nimage: nginx\n", opts); err == nil || !strings.Contains(err.Error(), "does not match URL") {
+		t.Errorf("expected a name mismatch to be rejected, got %v", err)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web", "canary": "true"}}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	patch := func(patchType api.PatchType, data string) (*api.Pod, error) {
+		var pod api.Pod
+		err := client.Patch("Pod", DefaultNamespace, "web", patchType, []byte(data), &pod)
+		return &pod, err
+	}
+
+	pod, err := patch(api.MergePatchType, `{"labels":{"tier":"backend","canary":null}}`)
+	if err != nil {
+		t.Fatalf("merge patch: %v", err)
+	}
+	if len(pod.Labels) != 2 || pod.Labels["app"] != "web" || pod.Labels["tier"] != "backend" || pod.Image != "nginx" {
+		t.Fatalf("expected the merge patch to add tier and remove canary, got %+v", pod)
+	}
+	again, err := patch(api.MergePatchType, `{"labels":{"tier":"backend"}}`)
+	if err != nil || again.ResourceVersion != pod.ResourceVersion {
+		t.Fatalf("expected a patch that changes nothing to keep the resourceVersion, got %v, resourceVersion %s -> %s", err, pod.ResourceVersion, again.ResourceVersion)
+	}
+
+	pod, err = patch(api.JSONPatchType, `[{"op":"test","path":"/image","value":"nginx"},{"op":"replace","path":"/image","value":"nginx:1.26"}]`)
+	if err != nil || pod.Image != "nginx:1.26" {
+		t.Fatalf("expected the JSON patch to replace the image, got %q, %v", pod.Image, err)
+	}
+
+	var statusErr *api.StatusError
+	for _, tt := range []struct {
+		patchType api.PatchType
+		data      string
+		code      int
+	}{
+		{api.JSONPatchType, `[{"op":"test","path":"/image","value":"httpd"}]`, http.StatusUnprocessableEntity},
+		{api.MergePatchType, `{"imag":"httpd"}`, http.StatusUnprocessableEntity},
+		{api.MergePatchType, `{"name":"other"}`, http.StatusBadRequest},
+		{api.JSONPatchType, `{"op":"add"}`, http.StatusBadRequest},
+	} {
+		if _, err := patch(tt.patchType, tt.data); !errors.As(err, &statusErr) || statusErr.Code != tt.code {
+			t.Errorf("patch %s: expected status %d, got %v", tt.data, tt.code, err)
+		}
+	}
+	if err := client.Patch("Pod", DefaultNamespace, "missing", api.MergePatchType, []byte(`{}`), nil); !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Errorf("expected patching a missing pod to be a 404, got %v", err)
+	}
+}
+
+func TestListSelectors(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	for _, pod := range []*api.Pod{
+		{ObjectMeta: api.ObjectMeta{Name: "web-1", Labels: map[string]string{"app": "web"}}, Image: "nginx"},
+		{ObjectMeta: api.ObjectMeta{Name: "web-2", Labels: map[string]string{"app": "web"}}, Image: "nginx"},
+		{ObjectMeta: api.ObjectMeta{Name: "db", Labels: map[string]string{"app": "db"}}, Image: "postgres"},
+	} {
+		if _, err := client.CreatePod(DefaultNamespace, pod); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	if _, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	if _, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node2"}, Status: api.NodeNotReady}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+
+	pods, err := client.ListPodsMatching(api.NamespaceAll, "app=web", "metadata.name!=web-2")
+	if err != nil || len(pods) != 1 || pods[0].Name != "web-1" {
+		t.Errorf("expected only web-1 to match, got %v, %v", pods, err)
+	}
+	nodes, err := client.ListNodesMatching("", "status=Ready")
+	if err != nil || len(nodes) != 1 || nodes[0].Name != "node1" {
+		t.Errorf("expected only node1 to match, got %v, %v", nodes, err)
+	}
+	var statusErr *api.StatusError
+	if _, err := client.ListPodsMatching(DefaultNamespace, "", "spec.image=nginx"); !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("expected an unsupported field to be a 400, got %v", err)
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	groupVersions, err := client.ServerGroupVersions()
+	if err != nil {
+		t.Fatalf("ServerGroupVersions: %v", err)
+	}
+	if len(groupVersions) == 0 || groupVersions[0] != "v1" || !strings.Contains(fmt.Sprint(groupVersions), "apps/v1") {
+		t.Errorf("expected v1 first and apps/v1 among the group versions, got %v", groupVersions)
+	}
+	lists, err := client.ServerResources()
+	if err != nil {
+		t.Fatalf("ServerResources: %v", err)
+	}
+	resources := map[string]api.APIResource{}
+	for _, list := range lists {
+		for _, r := range list.Resources {
+			if len(r.Verbs) == 0 {
+				t.Errorf("%s/%s: expected routes to give it verbs", list.GroupVersion, r.Name)
+			}
+			resources[list.GroupVersion+"/"+r.Name] = r
+		}
+	}
+	if len(resources) != len(api.Resources) {
+		t.Errorf("expected every resource in api.Resources to be discovered, got %d of %d", len(resources), len(api.Resources))
+	}
+	pods := resources["v1/pods"]
+	if !pods.Namespaced || pods.Kind != "Pod" || fmt.Sprint(pods.ShortNames) != "[po]" ||
+		fmt.Sprint(pods.Verbs) != "[create delete deletecollection get list patch update watch]" {
+		t.Errorf("unexpected discovery of pods: %+v", pods)
+	}
+	if nodes := resources["v1/nodes"]; nodes.Namespaced || nodes.Kind != "Node" {
+		t.Errorf("expected nodes to be cluster-scoped, got %+v", nodes)
+	}
+	if pdbs := resources["policy/v1/poddisruptionbudgets"]; fmt.Sprint(pdbs.Verbs) != "[create delete get list watch]" {
+		t.Errorf("expected the verbs of poddisruptionbudgets to follow its routes, got %v", pdbs.Verbs)
+	}
+}
+
+func TestTypedClients(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched := make(chan string, 10)
+	go client.Pods("team").Watch(ctx, api.ListOptions{LabelSelector: "app=web"}, func(eventType api.WatchEventType, pod *api.Pod) error {
+		watched <- fmt.Sprintf("%s %s %s", eventType, pod.Name, pod.Labels["tier"])
+		return nil
+	})
+
+	pods := client.Pods("team")
+	created, err := pods.Create(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}}, Image: "nginx"})
+	if err != nil || created.Namespace != "team" || created.ResourceVersion == "" {
+		t.Fatalf("Create: %+v, %v", created, err)
+	}
+	if _, err := client.Pods(DefaultNamespace).Create(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "db"}, Image: "postgres"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	created.Labels["tier"] = "front"
+	updated, err := pods.Update(created)
+	if err != nil || updated.Labels["tier"] != "front" || updated.ResourceVersion == created.ResourceVersion {
+		t.Fatalf("Update: %+v, %v", updated, err)
+	}
+	patched, err := pods.Patch("web", api.MergePatchType, []byte(`{"labels": {"tier": "edge"}}`))
+	if err != nil || patched.Labels["tier"] != "edge" {
+		t.Fatalf("Patch: %+v, %v", patched, err)
+	}
+	if got, err := pods.Get("web"); err != nil || got.Labels["tier"] != "edge" {
+		t.Fatalf("Get: %+v, %v", got, err)
+	}
+	if list, err := pods.List(api.ListOptions{}); err != nil || len(list) != 1 || list[0].Name != "web" {
+		t.Errorf("expected to list only the pod in team, got %+v, %v", list, err)
+	}
+	if list, err := client.Pods(api.NamespaceAll).List(api.ListOptions{FieldSelector: "metadata.name=db"}); err != nil || len(list) != 1 || list[0].Namespace != DefaultNamespace {
+		t.Errorf("expected to list db across namespaces, got %+v, %v", list, err)
+	}
+
+	// Cluster-scoped and grouped resources take the same calls.
+	if _, err := client.Nodes().Create(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Address: "localhost:10250"}); err != nil {
+		t.Fatalf("creating a node: %v", err)
+	}
+	if nodes, err := client.Nodes().List(api.ListOptions{}); err != nil || len(nodes) != 1 || nodes[0].Name != "node1" {
+		t.Errorf("expected to list node1, got %+v, %v", nodes, err)
+	}
+	deployment := &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web"}, Replicas: 1, Selector: map[string]string{"app": "web"},
+		Template: api.PodTemplate{Labels: map[string]string{"app": "web"}, Image: "nginx"}}
+	if _, err := client.Deployments("team").Create(deployment); err != nil {
+		t.Fatalf("creating a deployment: %v", err)
+	}
+	if got, err := client.Deployments("team").Get("web"); err != nil || got.Replicas != 1 {
+		t.Errorf("Get deployment: %+v, %v", got, err)
+	}
+
+	// Pods are deleted gracefully; a config map is gone at once.
+	if err := pods.Delete("web"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, err := pods.Get("web"); err != nil || got.DeletionTimestamp == nil {
+		t.Errorf("expected the deleted pod to be terminating, got %+v, %v", got, err)
+	}
+	if _, err := client.ConfigMaps("team").Create(&api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: "settings"}}); err != nil {
+		t.Fatalf("creating a config map: %v", err)
+	}
+	if err := client.ConfigMaps("team").Delete("settings"); err != nil {
+		t.Fatalf("deleting a config map: %v", err)
+	}
+	var statusErr *api.StatusError
+	if _, err := client.ConfigMaps("team").Get("settings"); !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Errorf("expected the deleted config map to be gone, got %v", err)
+	}
+
+	var got []string
+	for len(got) < 4 {
+		select {
+		case event := <-watched:
+			got = append(got, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for watch events, got %q", got)
+		}
+	}
+	if want := []string{"ADDED web ", "MODIFIED web front", "MODIFIED web edge", "MODIFIED web edge"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected watch events %q, got %q", want, got)
+	}
+}
+
+func TestListOptions(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	var rv string
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		pod, err := client.Pods(DefaultNamespace).Create(&api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Labels: map[string]string{"odd": strconv.FormatBool(i%2 == 0)}}, Image: "nginx"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if name == "c" {
+			rv = pod.ResourceVersion
+		}
+	}
+	names := func(pods []api.Pod) string {
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	pods, err := client.ListPodsWithOptions(DefaultNamespace, api.ListOptions{LabelSelector: "odd=true", Limit: 2})
+	if err != nil || names(pods) != "a,c" {
+		t.Errorf("expected the first page of odd pods to be a,c, got %q, %v", names(pods), err)
+	}
+	var pages []string
+	opts := api.ListOptions{Limit: 2}
+	for {
+		page, next, err := client.Pods(DefaultNamespace).ListPage(opts)
+		if err != nil {
+			t.Fatalf("ListPage: %v", err)
+		}
+		pages = append(pages, names(page))
+		if opts.Continue = next; next == "" {
+			break
+		}
+	}
+	if want := []string{"a,b", "c,d", "e"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("expected pages %q, got %q", want, pages)
+	}
+	// The positional shorthands filter on the server now, through the same options.
+	if pods, err := client.ListPods(DefaultNamespace, api.PodPending); err != nil || len(pods) != 5 {
+		t.Errorf("expected 5 pending pods, got %q, %v", names(pods), err)
+	}
+	if pods, err := client.ListPods(DefaultNamespace, api.PodRunning); err != nil || len(pods) != 0 {
+		t.Errorf("expected no running pods, got %q, %v", names(pods), err)
+	}
+
+	// A watch from a resource version sees only what came after it.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	added := make(chan string, 5)
+	go client.Pods(DefaultNamespace).Watch(ctx, api.ListOptions{ResourceVersion: rv}, func(eventType api.WatchEventType, pod *api.Pod) error {
+		added <- pod.Name
+		return nil
+	})
+	var got []string
+	for len(got) < 2 {
+		select {
+		case name := <-added:
+			got = append(got, name)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for watch events, got %q", got)
+		}
+	}
+	if want := []string{"d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected a watch from c's resource version to see %q, got %q", want, got)
+	}
+
+	deleted, err := client.DeleteCollectionWithOptions(DefaultNamespace, api.ListOptions{LabelSelector: "odd=false"})
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].Name < deleted[j].Name })
+	if err != nil || names(deleted) != "b,d" {
+		t.Errorf("expected b and d to be deleted, got %q, %v", names(deleted), err)
+	}
+}
+
+func TestWatchResumesAfterDisconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.WatchBookmarkInterval = 20 * time.Millisecond
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "a"}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan string, 10)
+	bookmarked := false
+	go client.Watch(ctx, "pods", DefaultNamespace, "", func(event api.WatchEvent) error {
+		var pod api.Pod
+		json.Unmarshal(event.Object, &pod)
+		switch {
+		case event.Type != api.WatchBookmark:
+			events <- fmt.Sprintf("%s %s", event.Type, pod.Name)
+		case !bookmarked:
+			bookmarked = true
+			events <- string(event.Type)
+		}
+		return nil
+	})
+	next := func() string {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			return "nothing"
+		}
+	}
+	for _, want := range []string{"ADDED a", "BOOKMARK"} {
+		if got := next(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+
+	// Changes made while the watch is disconnected arrive once it resumes, and the
+	// initial state isn't sent again.
+	time.Sleep(50 * time.Millisecond) // Let a bookmark or two through
+	srv.CloseClientConnections()
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "b"}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if err := dataStore.DeletePod(ctx, DefaultNamespace, "a"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	for _, want := range []string{"ADDED b", "MODIFIED a"} {
+		if got := next(); got != want {
+			t.Fatalf("expected %q after reconnecting, got %q", want, got)
+		}
+	}
+
+	// A bad request isn't retried.
+	err = client.Watch(ctx, "pods", DefaultNamespace, "not-a-number", func(api.WatchEvent) error { return nil })
+	var statusErr *api.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("expected a 400 for an invalid resource version, got %v", err)
+	}
+}
+
+func TestWatchServerSentEvents(t *testing.T) {
+	ctx := context.Background()
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	if err := dataStore.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: DefaultNamespace}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.WatchBookmarkInterval = time.Hour
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch := func(lastEventID string) *bufio.Reader {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/namespaces/default/pods?watch=true", nil)
+		req.Header.Set("Accept", "text/event-stream")
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("watch: %v", err)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("expected an event stream, got %q", ct)
+		}
+		return bufio.NewReader(resp.Body)
+	}
+	// next reads the next event, returning its ID and the watch event in its data.
+	next := func(r *bufio.Reader) (string, api.WatchEvent) {
+		var id string
+		var event api.WatchEvent
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading the stream: %v", err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && event.Type != "":
+				return id, event
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+					t.Fatalf("decoding %q: %v", line, err)
+				}
+			}
+		}
+	}
+
+	stream := watch("")
+	if id, event := next(stream); event.Type != api.WatchAdded || id != "" {
+		t.Fatalf("expected an initial ADDED event without an ID, got %s %q", event.Type, id)
+	}
+	id, event := next(stream)
+	if event.Type != api.WatchBookmark || id == "" {
+		t.Fatalf("expected a BOOKMARK with an ID, got %s %q", event.Type, id)
+	}
+
+	// Reconnecting with the last event ID resumes the watch rather than relisting.
+	if err := dataStore.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "b", Namespace: DefaultNamespace}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	resumed := watch(id)
+	if _, event := next(resumed); event.Type != api.WatchBookmark {
+		t.Fatalf("expected a resumed watch to start with a BOOKMARK, got %s", event.Type)
+	}
+	_, event = next(resumed)
+	var pod api.Pod
+	json.Unmarshal(event.Object, &pod)
+	if event.Type != api.WatchAdded || pod.Name != "b" {
+		t.Errorf("expected ADDED b, got %s %s", event.Type, pod.Name)
+	}
+}
+
+func TestWatchSelectors(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	for name, app := range map[string]string{"a": "web", "b": "db"} {
+		if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Labels: map[string]string{"app": app}}, Image: "nginx"}); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	relabel := func(name, app string) {
+		t.Helper()
+		pod, err := client.GetPod(DefaultNamespace, name)
+		if err != nil {
+			t.Fatalf("GetPod: %v", err)
+		}
+		pod.Labels = map[string]string{"app": app}
+		if err := client.UpdatePod(pod); err != nil {
+			t.Fatalf("UpdatePod: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan string, 10)
+	bookmarked := false
+	go client.WatchMatching(ctx, "pods", DefaultNamespace, "", "app=web", "", func(event api.WatchEvent) error {
+		var pod api.Pod
+		json.Unmarshal(event.Object, &pod)
+		switch {
+		case event.Type != api.WatchBookmark:
+			events <- fmt.Sprintf("%s %s", event.Type, pod.Name)
+		case !bookmarked:
+			bookmarked = true
+			events <- string(event.Type)
+		}
+		return nil
+	})
+	next := func() string {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			return "nothing"
+		}
+	}
+
+	// Objects entering the selection are ADDED and those leaving it DELETED; changes
+	// to objects outside it aren't sent at all.
+	for _, want := range []string{"ADDED a", "BOOKMARK"} {
+		if got := next(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+	relabel("b", "cache")
+	relabel("b", "web")
+	relabel("b", "web2")
+	relabel("a", "api")
+	for _, want := range []string{"ADDED b", "DELETED b", "DELETED a"} {
+		if got := next(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+
+	err := client.WatchMatching(ctx, "pods", DefaultNamespace, "", "", "image=nginx", func(api.WatchEvent) error { return nil })
+	var statusErr *api.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("expected a 400 for a field pods can't be selected by, got %v", err)
+	}
+}
+
+func TestPodSecurityAdmission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := httptest.NewServer(NewAPIServer(dataStore, nil).Handler())
+	defer srv.Close()
+	var warnings []string
+	client, err := api.NewClient(srv.URL, api.WithWarningHandler(func(message string) { warnings = append(warnings, message) }))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.CreateNamespace(&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "bad", Labels: map[string]string{podsecurity.EnforceLabel: "strict"}}}); err == nil {
+		t.Errorf("expected a namespace with an unknown pod security level to be rejected")
+	}
+	labels := map[string]string{podsecurity.EnforceLabel: "baseline", podsecurity.WarnLabel: "restricted"}
+	if _, err := client.CreateNamespace(&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "team", Labels: labels}}); err != nil {
+		t.Fatalf("CreateNamespace: %v", err)
+	}
+
+	hostPath := api.Volume{Name: "logs", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: "/var/log"}}}
+	_, err = client.CreatePod("team", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "spy"}, Image: "nginx", Volumes: []api.Volume{hostPath}})
+	var statusErr *api.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusForbidden || !strings.Contains(err.Error(), `violates PodSecurity "baseline": hostPath volumes (volume "logs")`) {
+		t.Errorf("expected a hostPath pod to be forbidden in a baseline namespace, got %v", err)
+	}
+	// Elsewhere it's allowed, without warnings.
+	if _, err := client.CreatePod("", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "spy"}, Image: "nginx", Volumes: []api.Volume{hostPath}}); err != nil || len(warnings) != 0 {
+		t.Errorf("expected a hostPath pod to be allowed in the default namespace, got %v, warnings %q", err, warnings)
+	}
+	if _, err := client.CreatePod("team", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx"}); err != nil || len(warnings) != 0 {
+		t.Errorf("expected a pod without volumes to be allowed without warnings, got %v, warnings %q", err, warnings)
+	}
+
+	// Where baseline is only warned about, the pod is created and the client warned.
+	if _, err := client.CreateNamespace(&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "lax", Labels: map[string]string{podsecurity.WarnLabel: "baseline"}}}); err != nil {
+		t.Fatalf("CreateNamespace: %v", err)
+	}
+	want := []string{`would violate PodSecurity "baseline": hostPath volumes (volume "logs")`}
+	if _, err := client.CreatePod("lax", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "spy"}, Image: "nginx", Volumes: []api.Volume{hostPath}}); err != nil || !reflect.DeepEqual(warnings, want) {
+		t.Errorf("expected the pod to be created with warnings %q, got %v, warnings %q", want, err, warnings)
+	}
+}
+
+func TestAdmissionRejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := httptest.NewServer(NewAPIServer(dataStore, nil).Handler())
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.CreateNamespace(&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "team", Labels: map[string]string{podsecurity.EnforceLabel: "baseline"}}}); err != nil {
+		t.Fatalf("CreateNamespace: %v", err)
+	}
+
+	hostPath := api.Volume{Name: "logs", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: "/var/log"}}}
+	spy := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "spy"}, Image: "nginx", Volumes: []api.Volume{hostPath}}
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreatePod("team", spy); err == nil {
+			t.Fatalf("expected a hostPath pod to be forbidden in a baseline namespace")
+		}
+	}
+	// A dry run is counted, but leaves no event behind.
+	if _, err := client.DryRun().CreatePod("team", spy); err == nil {
+		t.Fatalf("expected a dry-run hostPath pod to be forbidden in a baseline namespace")
+	}
+	if _, err := client.CreateConfigMap("team", &api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: "Bad_Name"}}); err == nil {
+		t.Fatalf("expected a configmap with an invalid name to be rejected")
+	}
+
+	events, err := client.ListEvents("team")
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	got := make(map[api.ObjectReference]api.Event)
+	for _, e := range events {
+		if e.Type != api.EventTypeWarning || e.Reason != "FailedAdmission" || e.Source.Component != "apiserver" {
+			t.Errorf("unexpected event %+v", e)
+		}
+		got[e.InvolvedObject] = e
+	}
+	if e := got[api.ObjectReference{Kind: "Pod", Namespace: "team", Name: "spy"}]; e.Count != 2 || !strings.HasPrefix(e.Message, `PodSecurity: pods "spy" is forbidden`) {
+		t.Errorf("expected one PodSecurity event for the pod, seen twice, got %+v", e)
+	}
+	if e := got[api.ObjectReference{Kind: "ConfigMap", Namespace: "team", Name: "Bad_Name"}]; e.Count != 1 || !strings.HasPrefix(e.Message, `Validation: ConfigMap "Bad_Name" is invalid`) {
+		t.Errorf("expected a Validation event for the configmap, got %+v", e)
+	}
+	if len(events) != 2 {
+		t.Errorf("expected 2 events, got %+v", events)
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`apiserver_admission_rejections_total{plugin="PodSecurity",resource="pods"} 3`,
+		`apiserver_admission_rejections_total{plugin="Validation",resource="configmaps"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected /metrics to contain %s, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestNamespaceDeletion(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestServer(t, nil)
+	if _, err := client.CreateNamespace(&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "team"}}); err != nil {
+		t.Fatalf("CreateNamespace: %v", err)
+	}
+	if _, err := client.CreatePod("team", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	client.CreateEvent("team", &api.Event{ObjectMeta: api.ObjectMeta{Name: "web.1"}, Reason: "Created"})
+
+	// The first delete only marks the namespace, which then takes nothing new.
+	if err := client.DeleteNamespace("team"); err != nil {
+		t.Fatalf("DeleteNamespace: %v", err)
+	}
+	if ns, err := client.GetNamespace("team"); err != nil || ns.Phase != api.NamespaceTerminating || ns.DeletionTimestamp == nil {
+		t.Fatalf("expected the namespace to be Terminating, got %+v (%v)", ns, err)
+	}
+	var statusErr *api.StatusError
+	_, err := client.CreatePod("team", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "late"}, Image: "nginx"})
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusForbidden {
+		t.Errorf("expected a 403 creating a pod in a terminating namespace, got %v", err)
+	}
+
+	// It is only removed once nothing but gone pods and events is left.
+	if err := client.DeleteNamespace("team"); !api.IsConflict(err) || !strings.Contains(err.Error(), "pod/web") {
+		t.Fatalf("expected a conflict naming pod/web, got %v", err)
+	}
+	if err := client.DeletePod("team", "web"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	if err := client.DeleteNamespace("team"); err != nil {
+		t.Fatalf("DeleteNamespace once empty: %v", err)
+	}
+	if _, err := client.GetNamespace("team"); err == nil {
+		t.Errorf("expected the namespace to be gone")
+	}
+	if pods, _ := server.store.ListPods(ctx, "team"); len(pods) != 0 {
+		t.Errorf("expected the deleted pod to be removed with its namespace, got %d pods", len(pods))
+	}
+	if events, _ := server.store.ListEvents(ctx, "team"); len(events) != 0 {
+		t.Errorf("expected events to be removed with their namespace, got %d", len(events))
+	}
+
+	if err := client.DeleteNamespace(DefaultNamespace); !errors.As(err, &statusErr) || statusErr.Code != http.StatusForbidden {
+		t.Errorf("expected a 403 deleting the default namespace, got %v", err)
+	}
+}
+
+func TestProxyToKubelet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// The kubelet echoes what it was asked for, if the API server authenticates.
+	kubelet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer kubelet-secret" {
+			http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/exec/") && r.Method == http.MethodGet {
+			conn, err := streaming.Upgrade(w, r)
+			if err != nil {
+				return
+			}
+			input, _ := io.ReadAll(conn.Stdin())
+			fmt.Fprintf(conn.Stdout(), "%s %s: %s", strings.Join(r.URL.Query()["command"], " "), r.URL.Query().Get("tty"), input)
+			conn.Close(streaming.Status{ExitCode: 4})
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/exec/") {
+			json.NewEncoder(w).Encode(api.ExecResult{Stdout: strings.Join(r.URL.Query()["command"], " ") + "\n", ExitCode: 3})
+			return
+		}
+		fmt.Fprintf(w, "%s %s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+	}))
+	defer kubelet.Close()
+
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	server := NewAPIServer(dataStore, nil)
+	server.KubeletToken = "kubelet-secret"
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL, api.WithBearerToken("user-token"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Address: strings.TrimPrefix(kubelet.URL, "http://"), Status: api.NodeReady}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	if _, err := client.CreatePod("default", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+
+	var statusErr *api.StatusError
+	if _, err := client.GetPodLogs("default", "web"); !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("expected a 400 for the logs of an unscheduled pod, got %v", err)
+	}
+	pod, _ := client.GetPod("default", "web")
+	pod.NodeName = "node1"
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+
+	if logs, err := client.GetPodLogs("default", "web"); err != nil || logs != "GET /logs/web?namespace=default" {
+		t.Errorf("expected the logs request to reach the kubelet, got %q (%v)", logs, err)
+	}
+	if logs, err := client.GetPodLogsWithOptions("default", "web", api.PodLogOptions{TailLines: 5, SinceSeconds: 60, Previous: true}); err != nil || logs != "GET /logs/web?namespace=default&previous=true&sinceSeconds=60&tailLines=5" {
+		t.Errorf("expected the log options to reach the kubelet, got %q (%v)", logs, err)
+	}
+	if result, err := client.ExecPod("default", "web", []string{"ls", "/data"}); err != nil || result.Stdout != "ls /data\n" || result.ExitCode != 3 {
+		t.Errorf("expected the exec result from the kubelet, got %+v (%v)", result, err)
+	}
+	var stdout strings.Builder
+	if code, err := client.ExecPodStream("default", "web", []string{"cat"}, true, streaming.Options{Stdin: strings.NewReader("hi"), Stdout: &stdout}); err != nil || code != 4 || stdout.String() != "cat true: hi" {
+		t.Errorf("expected the exec stream to pass through to the kubelet, got %q, exit code %d (%v)", stdout.String(), code, err)
+	}
+	resp, err := http.Get(srv.URL + "/api/v1/nodes/node1/proxy/metrics?x=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "GET /metrics?x=1" {
+		t.Errorf("expected the node proxy to reach the kubelet, got %d: %q", resp.StatusCode, body)
+	}
+
+	kubelet.Close()
+	if _, err := client.GetPodLogs("default", "web"); !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadGateway {
+		t.Errorf("expected a 502 when the kubelet is unreachable, got %v", err)
+	}
+}
+
+func TestNetworkPolicies(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	policy := &api.NetworkPolicy{
+		ObjectMeta: api.ObjectMeta{Name: "db"},
+		Ingress: []api.NetworkPolicyIngressRule{{
+			From: []api.NetworkPolicyPeer{{PodSelector: map[string]string{}}},
+		}},
+	}
+	if _, err := client.CreateNetworkPolicy("default", policy); err != nil {
+		t.Fatalf("CreateNetworkPolicy: %v", err)
+	}
+	got, err := client.GetNetworkPolicy("default", "db")
+	if err != nil {
+		t.Fatalf("GetNetworkPolicy: %v", err)
+	}
+	// An empty selector selects everything, so it must not come back unset.
+	if peer := got.Ingress[0].From[0]; peer.PodSelector == nil || peer.NamespaceSelector != nil {
+		t.Errorf("expected the peer's empty pod selector to survive, got %+v", peer)
+	}
+
+	invalid := &api.NetworkPolicy{
+		ObjectMeta: api.ObjectMeta{Name: "invalid"},
+		Ingress:    []api.NetworkPolicyIngressRule{{From: []api.NetworkPolicyPeer{{}}, Ports: []int{70000}}},
+	}
+	if _, err := client.CreateNetworkPolicy("default", invalid); err == nil || !strings.Contains(err.Error(), "ingress[0].from[0]") || !strings.Contains(err.Error(), "ingress[0].ports[0]") {
+		t.Errorf("expected a peer without selectors and an out of range port to be rejected, got %v", err)
+	}
+
+	if err := client.DeleteNetworkPolicy("default", "db"); err != nil {
+		t.Fatalf("DeleteNetworkPolicy: %v", err)
+	}
+	if policies, err := client.ListNetworkPolicies("default"); err != nil || len(policies) != 0 {
+		t.Errorf("expected no policies left, got %+v, %v", policies, err)
+	}
+}
+
+func TestAggregatedAPIServers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dataStore, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := httptest.NewServer(NewAPIServer(dataStore, nil).Handler())
+	defer srv.Close()
+	client, err := api.NewClient(srv.URL, api.WithBearerToken("secret"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	var forwarded *http.Request
+	metricsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/apis/metrics.lite/v1" {
+			fmt.Fprint(w, `{"groupVersion":"metrics.lite/v1","resources":[{"name":"nodes","kind":"NodeMetrics","verbs":["get","list"]}]}`)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"node1","cpu":"250m"}]`)
+	}))
+	defer metricsServer.Close()
+
+	apiService := &api.APIService{ObjectMeta: api.ObjectMeta{Name: "v1.metrics.lite"}, Group: "metrics.lite", Version: "v1", URL: metricsServer.URL}
+	if _, err := client.APIServices().Create(apiService); err != nil {
+		t.Fatalf("creating the API service: %v", err)
+	}
+	invalid := &api.APIService{ObjectMeta: api.ObjectMeta{Name: "v1.apps"}, Group: "apps", Version: "v1", URL: metricsServer.URL}
+	if _, err := client.APIServices().Create(invalid); err == nil || !strings.Contains(err.Error(), "group") {
+		t.Errorf("expected claiming a built-in group to be rejected, got %v", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/apis/metrics.lite/v1/nodes?limit=1")
+	if err != nil {
+		t.Fatalf("GET through the aggregator: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "node1") {
+		t.Errorf("expected the aggregated server's answer, got %d %s", resp.StatusCode, body)
+	}
+	if forwarded == nil || forwarded.URL.Path != "/apis/metrics.lite/v1/nodes" || forwarded.URL.RawQuery != "limit=1" {
+		t.Errorf("expected the request passed on with its path and query, got %+v", forwarded)
+	}
+
+	lists, err := client.ServerResources()
+	if err != nil {
+		t.Fatalf("ServerResources: %v", err)
+	}
+	if last := lists[len(lists)-1]; last.GroupVersion != "metrics.lite/v1" || len(last.Resources) != 1 {
+		t.Errorf("expected discovery to end with the aggregated group version, got %+v", last)
+	}
+	if forwarded.Header.Get("Authorization") != "" {
+		t.Errorf("expected the client's credentials not to be passed on, got %q", forwarded.Header.Get("Authorization"))
+	}
+
+	if err := client.APIServices().Delete("v1.metrics.lite"); err != nil {
+		t.Fatalf("deleting the API service: %v", err)
+	}
+	resp, err = http.Get(srv.URL + "/apis/metrics.lite/v1/nodes")
+	if err != nil {
+		t.Fatalf("GET after deregistering: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 once the API service is gone, got %d", resp.StatusCode)
+	}
+	if groupVersions, err := client.ServerGroupVersions(); err != nil || strings.Contains(fmt.Sprint(groupVersions), "metrics.lite") {
+		t.Errorf("expected the group gone from discovery, got %v, %v", groupVersions, err)
+	}
+}
+
+func TestEventTTLAndCap(t *testing.T) {
+	server, client := newTestServer(t, func(server *APIServer) {
+		server.EventTTL, server.MaxEventsPerNamespace = time.Hour, 3
+	})
+	record := func(pod string, last time.Time) {
+		t.Helper()
+		event := &api.Event{
+			InvolvedObject: api.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+			Type:           api.EventTypeNormal,
+			Reason:         "Pulled",
+			LastTimestamp:  last,
+		}
+		if _, err := client.CreateEvent("default", event); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+	}
+	names := func() []string {
+		t.Helper()
+		events, err := client.ListEvents("default")
+		if err != nil {
+			t.Fatalf("ListEvents: %v", err)
+		}
+		var pods []string
+		for _, e := range events {
+			pods = append(pods, fmt.Sprintf("%s:%d", e.InvolvedObject.Name, e.Count))
+		}
+		sort.Strings(pods)
+		return pods
+	}
+
+	now := time.Now()
+	record("stale", now.Add(-2*time.Hour))
+	record("a", now.Add(-3*time.Minute))
+	record("b", now.Add(-2*time.Minute))
+	record("a", now.Add(-time.Minute)) // A repeat, so a is now seen after b
+	// The stale event expired as the others were recorded; the repeat was aggregated.
+	if got := strings.Join(names(), ","); got != "a:2,b:1" {
+		t.Errorf("expected the stale event to expire and a's repeat to be counted, got %s", got)
+	}
+	// A repeat of the stale event starts over rather than bumping the expired one.
+	record("stale", now)
+	record("c", now)
+	if got := strings.Join(names(), ","); got != "a:2,c:1,stale:1" {
+		t.Errorf("expected the least recently seen event, b, to make room, got %s", got)
+	}
+
+	if err := server.expireEventsOnce(context.Background(), now.Add(59*time.Minute+30*time.Second)); err != nil {
+		t.Fatalf("expireEventsOnce: %v", err)
+	}
+	if got := strings.Join(names(), ","); got != "c:1,stale:1" {
+		t.Errorf("expected a to expire an hour after it was last seen, got %s", got)
+	}
+}
+
+func TestDryRunCreateOfExistingNodeConflicts(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	node := &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady}
+	if _, err := client.CreateNode(node); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+
+	// A dry run answers as the real create would.
+	for _, c := range []*api.Client{client.DryRun(), client} {
+		var statusErr *api.StatusError
+		if _, err := c.CreateNode(node); !errors.As(err, &statusErr) || statusErr.Code != http.StatusConflict {
+			t.Errorf("expected a 409 creating an existing node, got %v", err)
+		}
+	}
+}
+
+func TestDryRunLeavesStoreUnchanged(t *testing.T) {
+	server, client := newTestServer(t, nil)
+	handler := server.Handler()
+	do := func(method, path, body string, into interface{}) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if into != nil && rec.Code < 300 {
+			if err := json.Unmarshal(rec.Body.Bytes(), into); err != nil {
+				t.Fatalf("%s %s: decoding %q: %v", method, path, rec.Body, err)
+			}
+		}
+		return rec.Code
+	}
+
+	if _, err := client.CreatePod("default", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Image: "nginx:1"}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if _, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Address: "localhost:8081", Status: api.NodeReady}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+
+	// Pods: each dry run answers with what the real request would, and persists nothing.
+	var pod api.Pod
+	if code := do(http.MethodPost, "/api/v1/namespaces/default/pods?dryRun=true", `{"name":"dry","image":"nginx:1"}`, &pod); code != http.StatusCreated || pod.Name != "dry" || pod.Namespace != "default" {
+		t.Errorf("dry-run create pod: got %d %+v", code, pod)
+	}
+	if _, err := client.GetPod("default", "dry"); !api.IsNotFound(err) {
+		t.Errorf("expected the dry-run created pod not to exist, got %v", err)
+	}
+	pod = api.Pod{}
+	if code := do(http.MethodPut, "/api/v1/namespaces/default/pods/web?dryRun=true", `{"name":"web","namespace":"default","image":"nginx:2"}`, &pod); code != http.StatusOK || pod.Image != "nginx:2" {
+		t.Errorf("dry-run update pod: got %d %+v", code, pod)
+	}
+	if got, err := client.GetPod("default", "web"); err != nil || got.Image != "nginx:1" {
+		t.Errorf("expected the pod's image to stay nginx:1 after a dry-run update, got %+v, %v", got, err)
+	}
+	if code := do(http.MethodDelete, "/api/v1/namespaces/default/pods/web?dryRun=true", "", nil); code != http.StatusOK {
+		t.Errorf("dry-run delete pod: got %d", code)
+	}
+	if got, err := client.GetPod("default", "web"); err != nil || got.DeletionTimestamp != nil {
+		t.Errorf("expected the pod to survive a dry-run delete, got %+v, %v", got, err)
+	}
+
+	// Nodes likewise.
+	var node api.Node
+	if code := do(http.MethodPost, "/api/v1/nodes?dryRun=true", `{"name":"node2","address":"localhost:8082"}`, &node); code != http.StatusCreated || node.Name != "node2" {
+		t.Errorf("dry-run create node: got %d %+v", code, node)
+	}
+	if _, err := client.GetNode("node2"); !api.IsNotFound(err) {
+		t.Errorf("expected the dry-run created node not to exist, got %v", err)
+	}
+	node = api.Node{}
+	if code := do(http.MethodPut, "/api/v1/nodes/node1?dryRun=true", `{"name":"node1","address":"localhost:9091","status":"Ready"}`, &node); code != http.StatusOK || node.Address != "localhost:9091" {
+		t.Errorf("dry-run update node: got %d %+v", code, node)
+	}
+	if got, err := client.GetNode("node1"); err != nil || got.Address != "localhost:8081" {
+		t.Errorf("expected the node's address to stay localhost:8081 after a dry-run update, got %+v, %v", got, err)
+	}
+	if code := do(http.MethodDelete, "/api/v1/nodes/node1?dryRun=true", "", nil); code != http.StatusOK {
+		t.Errorf("dry-run delete node: got %d", code)
+	}
+	if _, err := client.GetNode("node1"); err != nil {
+		t.Errorf("expected the node to survive a dry-run delete, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin handler for creating a service
+func (s *APIServer) createServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	var svc api.Service
+	if err := c.ShouldBindJSON(&svc); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	svc.Namespace = namespace
+	if svc.Namespace == "" {
+		svc.Namespace = DefaultNamespace
+	}
+	validation.SetDefaults_Service(&svc)
+	if s.rejectInvalid(c, "Service", svc.Name, validation.Validate_Service(&svc)) {
+		return
+	}
+	s.trackManagedFields(c, nil, &svc)
+
+	if body := s.terminatingNamespace(c, "Service", svc.Name, svc.Namespace); body != nil {
+		c.JSON(403, body)
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetService(ctx, svc.Namespace, svc.Name); err == nil {
+			c.JSON(409, gin.H{"error": fmt.Sprintf("Failed to create service: service %s in namespace %s already exists", svc.Name, svc.Namespace)})
+			return
+		}
+		c.JSON(201, svc)
+		return
+	}
+
+	if err := s.store.CreateService(ctx, &svc); err != nil {
+		log.Printf("Error creating service %s/%s in store: %v", svc.Namespace, svc.Name, err)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			c.JSON(409, gin.H{"error": "Failed to create service: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to create service: " + err.Error()})
+		}
+		return
+	}
+	log.Printf("Created service %s/%s", svc.Namespace, svc.Name)
+	c.JSON(201, svc)
+}
+
+// Gin handler for getting a specific service
+func (s *APIServer) getServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	svc, err := s.store.GetService(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to get service: " + err.Error()})
+		return
+	}
+	respondWithETag(c, objectETag(svc), svc)
+}
+
+// Gin handler for listing services in a namespace
+func (s *APIServer) listServicesHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	services, err := s.store.ListServices(ctx, namespace)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list services: " + err.Error()})
+		return
+	}
+	respondWithList(c, services)
+}
+
+// Gin handler for deleting a specific service
+func (s *APIServer) deleteServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	policy, err := propagationPolicy(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if isDryRun(c) {
+		if _, err := s.store.GetService(ctx, namespace, name); err != nil {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete service: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Service %s/%s deleted (dry run)", namespace, name)})
+		return
+	}
+	deleteNow := true
+	err = s.store.Txn(ctx, func(tx store.StoreTxn) error {
+		if existing, err := tx.GetService(ctx, namespace, name); err == nil {
+			deleteNow, err = propagateDeletion(ctx, tx, policy, existing.ObjectMeta, func(meta api.ObjectMeta) error {
+				updated := *existing
+				updated.ObjectMeta = meta
+				return tx.UpdateService(ctx, &updated)
+			})
+			if err != nil || !deleteNow {
+				return err
+			}
+		}
+		return tx.DeleteService(ctx, namespace, name)
+	})
+	if err != nil {
+		log.Printf("Error deleting service %s/%s from store: %v", namespace, name, err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to delete service: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to delete service: " + err.Error()})
+		}
+		return
+	}
+	if !deleteNow {
+		log.Printf("Marked service %s/%s for foreground deletion", namespace, name)
+		c.JSON(200, gin.H{"message": fmt.Sprintf("Service %s/%s marked for deletion; waiting for its dependents", namespace, name)})
+		return
+	}
+	log.Printf("Deleted service %s/%s", namespace, name)
+	c.JSON(200, gin.H{"message": fmt.Sprintf("Service %s/%s deleted", namespace, name)})
+}
+
+// Gin handler for updating a specific service
+func (s *APIServer) updateServiceHandlerGin(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var svc api.Service
+	if err := c.ShouldBindJSON(&svc); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if svc.Name != name || svc.Namespace != namespace {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Service %s/%s in body does not match URL (%s/%s)", svc.Namespace, svc.Name, namespace, name)})
+		return
+	}
+	validation.SetDefaults_Service(&svc)
+	if s.rejectInvalid(c, "Service", svc.Name, validation.Validate_Service(&svc)) {
+		return
+	}
+	existing, err := s.store.GetService(ctx, namespace, name)
+	if err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update service: " + err.Error()})
+		return
+	}
+	s.trackManagedFields(c, existing, &svc)
+
+	if isDryRun(c) {
+		c.JSON(200, svc)
+		return
+	}
+
+	if err := s.store.UpdateService(ctx, &svc); err != nil {
+		log.Printf("Failed to update service in store: %v", err)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Failed to update service: " + err.Error()})
+		} else {
+			c.JSON(storeErrorCode(err), gin.H{"error": "Failed to update service: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(200, svc)
+}
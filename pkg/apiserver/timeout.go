@@ -0,0 +1,44 @@
+package apiserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultRequestTimeout is how long the API server gives a request before the store
+// calls it makes fail, unless told otherwise.
+const DefaultRequestTimeout = time.Minute
+
+// timeoutMiddleware gives each request a context that expires after timeout, so a slow
+// store fails the request with a 504 rather than holding its handler indefinitely.
+// Watches and streams are exempt: they last as long as the client wants them to.
+func timeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isLongRunning(c) {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// isLongRunning reports whether a request is a watch, or streams logs, a command, or a
+// proxied connection, none of which has a natural end the server can wait for.
+func isLongRunning(c *gin.Context) bool {
+	if isWatch(c) {
+		return true
+	}
+	path := c.FullPath()
+	for _, suffix := range []string{"/log", "/exec", "/attach", "/portforward"} {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return strings.Contains(path, "/proxy")
+}
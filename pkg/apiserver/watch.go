@@ -0,0 +1,282 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/fields"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultWatchBookmarkInterval is how often NewAPIServer has watches send a bookmark.
+const DefaultWatchBookmarkInterval = 10 * time.Second
+
+// isWatch reports whether a list request asks for a watch instead, with ?watch=true.
+func isWatch(c *gin.Context) bool {
+	watch, _ := strconv.ParseBool(c.Query("watch"))
+	return watch
+}
+
+// watchable serves a list request with ?watch=true as a watch of gr, and any other
+// with list.
+func watchable[T store.Object[T]](s *APIServer, gr store.GroupResource, list gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isWatch(c) {
+			serveWatch[T](s, c, gr)
+			return
+		}
+		list(c)
+	}
+}
+
+// serveWatch streams the changes to the objects of gr in the request's namespace, one
+// api.WatchEvent per line, until the client goes away or the server shuts down. A
+// request with "Accept: text/event-stream" gets them as server-sent events instead.
+//
+// Without a resourceVersion parameter, the watch starts with an ADDED event for each
+// object, then a BOOKMARK at the revision they were read at. With one, it starts with a
+// BOOKMARK at that revision, then sends the changes after it, or answers 410 if the
+// journal no longer has them. Either way a BOOKMARK with the latest revision follows
+// every s.WatchBookmarkInterval, so a client can resume from a recent revision even if
+// none of the objects it watches changed.
+//
+// The labelSelector and fieldSelector parameters narrow the watch to the objects that
+// match them, as they do a list. An object that comes to match is sent as ADDED, and
+// one that stops matching as DELETED, so the watcher's view is the selected objects.
+func serveWatch[T store.Object[T]](s *APIServer, c *gin.Context, gr store.GroupResource) {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	var zero T
+	labelSelector, fieldSelector, ok := listSelectors(c, selectableFields(zero))
+	if !ok {
+		return
+	}
+	filter := newWatchFilter(labelSelector, fieldSelector)
+	stream := acceptsEventStream(c)
+	rv := c.Query("resourceVersion")
+	if rv == "" && stream {
+		// An EventSource reconnects with the ID of the last event it got, a revision.
+		rv = c.GetHeader("Last-Event-ID")
+	}
+	var initial []T
+	var revision uint64
+	if rv != "" && rv != "0" {
+		var err error
+		if revision, err = strconv.ParseUint(rv, 10, 64); err != nil {
+			c.JSON(400, gin.H{"error": "Invalid resourceVersion: " + rv})
+			return
+		}
+	} else if err := s.store.Txn(ctx, func(tx store.StoreTxn) error {
+		var err error
+		initial, err = store.RegistryFor[T](tx, gr).List(ctx, namespace)
+		revision = tx.Revision()
+		return err
+	}); err != nil {
+		c.JSON(storeErrorCode(err), gin.H{"error": "Failed to list " + gr.Resource + ": " + err.Error()})
+		return
+	}
+	changes, changed, err := s.store.Changes(revision)
+	if err != nil {
+		code := 500
+		if errors.Is(err, store.ErrRevisionCompacted) {
+			code = http.StatusGone
+		}
+		c.JSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	var write func(event api.WatchEvent, id uint64) error
+	if stream {
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		write = func(event api.WatchEvent, id uint64) error {
+			return writeServerSentEvent(c.Writer, event, id)
+		}
+	} else {
+		c.Header("Content-Type", "application/json")
+		enc := json.NewEncoder(c.Writer)
+		write = func(event api.WatchEvent, _ uint64) error { return enc.Encode(event) }
+	}
+	c.Status(200)
+	// send writes an event; id is the revision a watch resumed from it should start
+	// at, or 0 for the initial ADDED events, which can't be resumed from.
+	send := func(t api.WatchEventType, obj interface{}, id uint64) bool {
+		raw, err := json.Marshal(obj)
+		if err == nil {
+			err = write(api.WatchEvent{Type: t, Object: raw}, id)
+		}
+		if err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+	bookmark := func() bool {
+		if stream {
+			// A comment, which EventSource ignores, keeps idle proxies from closing
+			// the connection even where they don't count the bookmark as traffic.
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return false
+			}
+		}
+		return send(api.WatchBookmark, gin.H{"resourceVersion": strconv.FormatUint(revision, 10)}, revision)
+	}
+
+	for _, obj := range initial {
+		if filter.initial(obj) && !send(api.WatchAdded, obj, 0) {
+			return
+		}
+	}
+	if !bookmark() {
+		return
+	}
+	ticker := time.NewTicker(s.WatchBookmarkInterval)
+	defer ticker.Stop()
+	for {
+		for _, change := range changes {
+			revision = change.Revision
+			if change.Resource != gr {
+				continue
+			}
+			if ns := change.Object.GetObjectMeta().Namespace; namespace != api.NamespaceAll && ns != "" && ns != namespace {
+				continue
+			}
+			t, ok := filter.change(api.WatchEventType(change.Type), change.Object)
+			if ok && !send(t, change.Object, change.Revision) {
+				return
+			}
+		}
+		changes = nil
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+			if !bookmark() {
+				return
+			}
+			continue
+		case <-changed:
+		}
+		if changes, changed, err = s.store.Changes(revision); err != nil {
+			// The watch fell so far behind that the journal dropped changes it
+			// hadn't sent yet.
+			send(api.WatchError, gin.H{"code": http.StatusGone, "error": err.Error()}, 0)
+			return
+		}
+	}
+}
+
+// acceptsEventStream reports whether a watch request asks for server-sent events, as
+// a browser's EventSource does, rather than newline-delimited JSON.
+func acceptsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// writeServerSentEvent writes event as a server-sent event whose data is the JSON of
+// the api.WatchEvent, so an EventSource's onmessage gets the same events as a JSON
+// watcher. A non-zero id is sent as the event's ID, so that a reconnecting
+// EventSource resumes the watch from it.
+func writeServerSentEvent(w io.Writer, event api.WatchEvent, id uint64) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if id != 0 {
+		fmt.Fprintf(&buf, "id: %d\n", id)
+	}
+	fmt.Fprintf(&buf, "data: %s\n\n", data)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// selectableFields returns the fields obj can be selected by in a field selector. For
+// a nil pointer, it returns the fields of an empty object, for their names.
+func selectableFields(obj metaObject) fields.Set {
+	if v := reflect.ValueOf(obj); v.Kind() == reflect.Pointer && v.IsNil() {
+		obj = reflect.New(v.Type().Elem()).Interface().(metaObject)
+	}
+	switch o := obj.(type) {
+	case *api.Pod:
+		return fields.PodFields(o)
+	case *api.Node:
+		return fields.NodeFields(o)
+	}
+	return fields.ObjectMetaFields(obj.GetObjectMeta())
+}
+
+// watchFilter narrows a watch to the objects matching its selectors. The store's
+// changes carry only the new state of an object, so it remembers which objects the
+// watcher has been sent, to tell an object entering or leaving the selection from
+// one changing within it or outside it.
+type watchFilter struct {
+	labels labels.Selector
+	fields fields.Selector
+	// selected records, by namespace/name, whether the watcher holds each object it
+	// knows the state of. A watch resumed from a resourceVersion starts out knowing
+	// none, and sends a DELETED for the first change to an object that doesn't match,
+	// in case the watcher had it; watchers ignore deletes of objects they don't hold.
+	selected map[string]bool
+}
+
+// newWatchFilter returns the filter for the selectors, or nil if both are empty.
+func newWatchFilter(labelSelector labels.Selector, fieldSelector fields.Selector) *watchFilter {
+	if labelSelector.Empty() && fieldSelector.Empty() {
+		return nil
+	}
+	return &watchFilter{labels: labelSelector, fields: fieldSelector, selected: make(map[string]bool)}
+}
+
+func (f *watchFilter) matches(obj metaObject) bool {
+	return f.labels.Matches(obj.GetObjectMeta().Labels) && f.fields.Matches(selectableFields(obj))
+}
+
+func watchKey(meta *api.ObjectMeta) string {
+	return meta.Namespace + "/" + meta.Name
+}
+
+// initial reports whether to send obj, which exists as the watch starts.
+func (f *watchFilter) initial(obj metaObject) bool {
+	if f == nil {
+		return true
+	}
+	matches := f.matches(obj)
+	f.selected[watchKey(obj.GetObjectMeta())] = matches
+	return matches
+}
+
+// change returns the event to send the watcher for a change of type t to obj, and
+// false if it shouldn't be told of it.
+func (f *watchFilter) change(t api.WatchEventType, obj metaObject) (api.WatchEventType, bool) {
+	if f == nil {
+		return t, true
+	}
+	key := watchKey(obj.GetObjectMeta())
+	held, known := f.selected[key]
+	matches := t != api.WatchDeleted && f.matches(obj)
+	if t == api.WatchDeleted {
+		delete(f.selected, key)
+	} else {
+		f.selected[key] = matches
+	}
+	switch {
+	case matches && held:
+		return api.WatchModified, true
+	case matches:
+		return api.WatchAdded, true
+	case held || (!known && t != api.WatchAdded):
+		return api.WatchDeleted, true
+	}
+	return "", false
+}
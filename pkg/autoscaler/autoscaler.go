@@ -0,0 +1,223 @@
+// Package autoscaler is cluster-autoscaler-lite: it adds simulated nodes while pods
+// can't be scheduled, and removes the nodes it added once they have sat idle.
+//
+// Every interval the autoscaler looks for pods the scheduler has marked Unschedulable.
+// If one of them would fit a new, empty node, and no node the autoscaler added is
+// still coming up, it adds a node, up to MaxNodes of its own. A node it added that
+// runs no pods for ScaleDownDelay is removed again. Nodes it didn't add are never
+// touched.
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/scheduler"
+)
+
+// NodeProvider adds and removes the autoscaler's nodes.
+type NodeProvider interface {
+	// AddNode starts a node named name, which registers itself with the API server.
+	AddNode(name string) error
+	// RemoveNode stops the node named name. The autoscaler deletes the Node object.
+	RemoveNode(name string) error
+}
+
+// Options configures an Autoscaler.
+type Options struct {
+	// MaxNodes is the most nodes the autoscaler runs at once.
+	MaxNodes int
+	// ScaleDownDelay is how long one of the autoscaler's nodes must run no pods before
+	// it is removed.
+	ScaleDownDelay time.Duration
+	// Interval is how often the autoscaler checks the cluster.
+	Interval time.Duration
+	// NamePrefix names the autoscaler's nodes, which are NamePrefix followed by a number.
+	NamePrefix string
+}
+
+// DefaultNamePrefix is the name prefix of the autoscaler's nodes if Options has none.
+const DefaultNamePrefix = "autoscaled-"
+
+// Autoscaler adds and removes nodes as the cluster's pods need them.
+type Autoscaler struct {
+	client   api.Interface
+	provider NodeProvider
+	recorder record.EventRecorder
+	clock    clock.Clock
+	opts     Options
+
+	idleSince map[string]time.Time // When each of the autoscaler's idle nodes was first seen idle
+}
+
+// New returns an autoscaler that adds and removes nodes through provider, as measured
+// by clk.
+func New(client api.Interface, provider NodeProvider, recorder record.EventRecorder, clk clock.Clock, opts Options) *Autoscaler {
+	if opts.NamePrefix == "" {
+		opts.NamePrefix = DefaultNamePrefix
+	}
+	return &Autoscaler{
+		client:    client,
+		provider:  provider,
+		recorder:  recorder,
+		clock:     clk,
+		opts:      opts,
+		idleSince: make(map[string]time.Time),
+	}
+}
+
+// Run checks the cluster every interval until ctx is cancelled.
+func (a *Autoscaler) Run(ctx context.Context) {
+	log.Printf("Autoscaler starting with up to %d node(s), removing them after %v idle.", a.opts.MaxNodes, a.opts.ScaleDownDelay)
+	ticker := a.clock.NewTicker(a.opts.Interval)
+	defer ticker.Stop()
+	for {
+		a.reconcile()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// reconcile scales the cluster up or down once.
+func (a *Autoscaler) reconcile() {
+	nodes, err := a.client.ListNodes("")
+	if err != nil {
+		log.Printf("Autoscaler: error listing nodes: %v", err)
+		return
+	}
+	pods, err := a.client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		log.Printf("Autoscaler: error listing pods: %v", err)
+		return
+	}
+	var own []*api.Node
+	for i := range nodes {
+		if a.owns(nodes[i].Name) {
+			own = append(own, &nodes[i])
+		}
+	}
+	if !a.scaleUp(own, pods) {
+		a.scaleDown(own, pods)
+	}
+}
+
+// scaleUp adds a node if an unschedulable pod would fit one, reporting whether it did.
+func (a *Autoscaler) scaleUp(own []*api.Node, pods []api.Pod) bool {
+	var waiting []*api.Pod
+	template := &api.Node{Status: api.NodeReady}
+	for i := range pods {
+		pod := &pods[i]
+		cond := api.GetPodCondition(pod, api.PodScheduledCondition)
+		if pod.Phase != api.PodPending || pod.NodeName != "" || pod.DeletionTimestamp != nil ||
+			cond == nil || cond.Status != api.ConditionFalse || cond.Reason != api.PodReasonUnschedulable {
+			continue
+		}
+		if len(scheduler.FitsNode(pod, template)) == 0 {
+			waiting = append(waiting, pod)
+		}
+	}
+	if len(waiting) == 0 {
+		return false
+	}
+	for _, node := range own {
+		if !api.IsNodeReady(node) {
+			// A node that is still coming up may well take the pods.
+			return true
+		}
+	}
+	if len(own) >= a.opts.MaxNodes {
+		log.Printf("Autoscaler: %d pod(s) unschedulable, but already at the maximum of %d node(s)", len(waiting), a.opts.MaxNodes)
+		return true
+	}
+
+	name := a.nextNodeName(own)
+	if err := a.provider.AddNode(name); err != nil {
+		log.Printf("Autoscaler: error adding node %s: %v", name, err)
+		return true
+	}
+	log.Printf("Autoscaler: added node %s for %d unschedulable pod(s)", name, len(waiting))
+	for _, pod := range waiting {
+		a.recorder.Eventf(pod, api.EventTypeNormal, "TriggeredScaleUp", "Pod triggered scale-up: added node %s (%d/%d)", name, len(own)+1, a.opts.MaxNodes)
+	}
+	return true
+}
+
+// scaleDown removes the autoscaler's nodes that have run no pods for ScaleDownDelay.
+func (a *Autoscaler) scaleDown(own []*api.Node, pods []api.Pod) {
+	busy := map[string]bool{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.NodeName != "" && !api.IsPodGone(pod) && pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed {
+			busy[pod.NodeName] = true
+		}
+	}
+	now := a.clock.Now()
+	seen := map[string]bool{}
+	for _, node := range own {
+		seen[node.Name] = true
+		if busy[node.Name] {
+			delete(a.idleSince, node.Name)
+			continue
+		}
+		since, ok := a.idleSince[node.Name]
+		if !ok {
+			a.idleSince[node.Name] = now
+			continue
+		}
+		if now.Sub(since) < a.opts.ScaleDownDelay {
+			continue
+		}
+		if err := a.provider.RemoveNode(node.Name); err != nil {
+			log.Printf("Autoscaler: error removing node %s: %v", node.Name, err)
+			continue
+		}
+		if err := a.client.DeleteNode(node.Name); err != nil {
+			log.Printf("Autoscaler: error deleting node %s: %v", node.Name, err)
+			continue
+		}
+		delete(a.idleSince, node.Name)
+		log.Printf("Autoscaler: removed node %s, idle for %v", node.Name, now.Sub(since))
+		a.recorder.Eventf(node, api.EventTypeNormal, "ScaleDown", "Node removed by the autoscaler after %v without pods", now.Sub(since).Round(time.Second))
+	}
+	for name := range a.idleSince {
+		if !seen[name] {
+			delete(a.idleSince, name)
+		}
+	}
+}
+
+// owns reports whether the autoscaler added the node named name.
+func (a *Autoscaler) owns(name string) bool {
+	suffix, ok := strings.CutPrefix(name, a.opts.NamePrefix)
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// nextNodeName returns a name for a new node, numbered after the autoscaler's nodes.
+func (a *Autoscaler) nextNodeName(own []*api.Node) string {
+	numbers := make([]int, 0, len(own))
+	for _, node := range own {
+		n, _ := strconv.Atoi(strings.TrimPrefix(node.Name, a.opts.NamePrefix))
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	next := 1
+	if len(numbers) > 0 {
+		next = numbers[len(numbers)-1] + 1
+	}
+	return fmt.Sprintf("%s%d", a.opts.NamePrefix, next)
+}
@@ -0,0 +1,132 @@
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+// fakeProvider registers a NotReady node for every node added, as a kubelet would
+// before its first heartbeat.
+type fakeProvider struct {
+	client  api.Interface
+	removed []string
+}
+
+func (p *fakeProvider) AddNode(name string) error {
+	_, err := p.client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: name}, Status: api.NodeNotReady})
+	return err
+}
+
+func (p *fakeProvider) RemoveNode(name string) error {
+	p.removed = append(p.removed, name)
+	return nil
+}
+
+func TestAutoscaler(t *testing.T) {
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady, Unschedulable: true},
+		&api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"},
+			Phase:      api.PodPending,
+			Conditions: []api.PodCondition{{Type: api.PodScheduledCondition, Status: api.ConditionFalse, Reason: api.PodReasonUnschedulable}},
+		},
+	)
+	provider := &fakeProvider{client: client}
+	clk := clock.NewFakeClock(time.Now())
+	a := New(client, provider, record.NewRecorder(client, api.EventSource{Component: "cluster-autoscaler"}), clk, Options{MaxNodes: 2, ScaleDownDelay: time.Minute})
+
+	nodeNames := func() map[string]api.NodeStatus {
+		t.Helper()
+		nodes, err := client.ListNodes("")
+		if err != nil {
+			t.Fatalf("ListNodes: %v", err)
+		}
+		names := map[string]api.NodeStatus{}
+		for _, n := range nodes {
+			names[n.Name] = n.Status
+		}
+		return names
+	}
+	setReady := func(name string) {
+		t.Helper()
+		node, err := client.GetNode(name)
+		if err != nil {
+			t.Fatalf("GetNode: %v", err)
+		}
+		api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionTrue})
+		if err := client.UpdateNode(node); err != nil {
+			t.Fatalf("UpdateNode: %v", err)
+		}
+	}
+
+	a.reconcile()
+	a.reconcile()
+	if got := nodeNames(); len(got) != 2 || got["autoscaled-1"] != api.NodeNotReady {
+		t.Fatalf("expected one node added while the pod is unschedulable and none more while it comes up, got %v", got)
+	}
+	setReady("autoscaled-1")
+	a.reconcile()
+	setReady("autoscaled-2")
+	a.reconcile()
+	if got := nodeNames(); len(got) != 3 {
+		t.Fatalf("expected no more than MaxNodes nodes of the autoscaler's own, got %v", got)
+	}
+
+	events, err := client.ListEvents("default")
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	scaleUps := 0
+	for _, e := range events {
+		if e.Reason == "TriggeredScaleUp" && e.InvolvedObject.Name == "web" {
+			scaleUps++
+		}
+	}
+	if scaleUps != 2 {
+		t.Errorf("expected a TriggeredScaleUp event on the pod per node added, got %d", scaleUps)
+	}
+
+	// Once the pod is bound to autoscaled-1, autoscaled-2 is idle and removed after the delay.
+	pod, err := client.GetPod("default", "web")
+	if err != nil {
+		t.Fatalf("GetPod: %v", err)
+	}
+	pod.NodeName = "autoscaled-1"
+	pod.Phase = api.PodScheduled
+	pod.Conditions = nil
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	a.reconcile()
+	clk.Step(30 * time.Second)
+	a.reconcile()
+	if len(provider.removed) != 0 {
+		t.Fatalf("expected no node removed before the scale-down delay, got %v", provider.removed)
+	}
+	clk.Step(30 * time.Second)
+	a.reconcile()
+	got := nodeNames()
+	if len(provider.removed) != 1 || provider.removed[0] != "autoscaled-2" || len(got) != 2 || got["autoscaled-1"] == "" {
+		t.Errorf("expected only the idle autoscaled-2 removed, got removed %v, nodes %v", provider.removed, got)
+	}
+
+	// Once the pod has finished, autoscaled-1 goes too. node1 is idle as well, but the
+	// autoscaler didn't add it.
+	for _, phase := range []api.PodPhase{api.PodRunning, api.PodSucceeded} {
+		pod.Phase = phase
+		if err := client.UpdatePod(pod); err != nil {
+			t.Fatalf("UpdatePod: %v", err)
+		}
+	}
+	a.reconcile()
+	clk.Step(time.Minute)
+	a.reconcile()
+	if got := nodeNames(); len(got) != 1 || got["node1"] == "" {
+		t.Errorf("expected only node1 to remain, got %v", got)
+	}
+}
@@ -0,0 +1,74 @@
+// Package backoff spaces out the retries of an operation that keeps failing, such as
+// a component's calls to an API server that is down. Each failure in a row doubles
+// the delay, up to a cap, and every delay is stretched by a random jitter, so that
+// components that lost the API server at the same moment don't all come back to it
+// at the same moment when it restarts.
+package backoff
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Jitter is the largest fraction of a delay added to it at random.
+const Jitter = 0.2
+
+// Backoff tracks a run of consecutive failures. It is safe for concurrent use, so a
+// component's health and metrics handlers can read it while its loop updates it.
+type Backoff struct {
+	initial, max time.Duration
+	rand         func() float64 // In [0, 1); replaced by tests
+
+	mu       sync.Mutex
+	failures int
+	delay    time.Duration // The delay Next last returned, before jitter
+}
+
+// New returns a Backoff whose first delay is initial, doubling up to max.
+func New(initial, max time.Duration) *Backoff {
+	if max < initial {
+		max = initial
+	}
+	return &Backoff{initial: initial, max: max, rand: rand.Float64}
+}
+
+// Next records a failure and returns how long to wait before trying again: initial
+// after the first failure in a row, twice the previous delay after each one after
+// that up to max, plus up to Jitter of that at random.
+func (b *Backoff) Next() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures == 1 {
+		b.delay = b.initial
+	} else if b.delay *= 2; b.delay > b.max {
+		b.delay = b.max
+	}
+	return b.delay + time.Duration(Jitter*b.rand()*float64(b.delay))
+}
+
+// Reset records a success, ending the run of failures, and returns how many failures
+// the run had, so that callers can log the recovery once.
+func (b *Backoff) Reset() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	failures := b.failures
+	b.failures, b.delay = 0, 0
+	return failures
+}
+
+// Failures returns how many times in a row the operation has failed.
+func (b *Backoff) Failures() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures
+}
+
+// Delay returns the delay Next last returned, without its jitter, or zero if the last
+// attempt succeeded.
+func (b *Backoff) Delay() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.delay
+}
@@ -0,0 +1,44 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	b := New(time.Second, 5*time.Second)
+	b.rand = func() float64 { return 0 }
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := b.Next(); got != want {
+			t.Errorf("failure %d: got delay %v, want %v", i+1, got, want)
+		}
+	}
+	if b.Failures() != 5 || b.Delay() != 5*time.Second {
+		t.Errorf("expected 5 failures and a 5s delay, got %d and %v", b.Failures(), b.Delay())
+	}
+
+	if n := b.Reset(); n != 5 {
+		t.Errorf("Reset returned %d, want 5", n)
+	}
+	if b.Failures() != 0 || b.Delay() != 0 {
+		t.Errorf("expected Reset to clear the run, got %d failures and a %v delay", b.Failures(), b.Delay())
+	}
+	if got := b.Next(); got != time.Second {
+		t.Errorf("expected the first failure after Reset to wait %v, got %v", time.Second, got)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	b := New(10*time.Second, time.Minute)
+	b.rand = func() float64 { return 0.5 }
+	if got, want := b.Next(), 11*time.Second; got != want {
+		t.Errorf("got delay %v, want %v (10s plus half the %v jitter)", got, want, Jitter)
+	}
+
+	b = New(10*time.Second, time.Minute)
+	for i := 0; i < 100; i++ {
+		if got := b.Next(); got < b.Delay() || got >= b.Delay()+time.Duration(Jitter*float64(b.Delay())) {
+			t.Fatalf("delay %v is outside [%v, %v + %v of it)", got, b.Delay(), b.Delay(), Jitter)
+		}
+	}
+}
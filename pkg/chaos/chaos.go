@@ -0,0 +1,139 @@
+// Package chaos injects faults into a running cluster so that the control loops have
+// something to recover from: API requests that fail or stall, kubelets that crash and
+// restart, nodes that flap between Ready and NotReady, and informer events that
+// controllers never see. Every fault is off unless its flag is set.
+package chaos
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Config says how often each fault happens. Rates are probabilities from 0 to 1.
+type Config struct {
+	// APIErrorRate is the fraction of API requests answered with a 500.
+	APIErrorRate float64
+	// APILatency is the most extra latency added to each API request; each request
+	// waits a random time up to it.
+	APILatency time.Duration
+
+	// KubeletCrashRate is the chance, on every pod sync, that a kubelet crashes. A
+	// crashed kubelet stops syncing for KubeletDowntime, then restarts with empty
+	// in-memory state and registers its node again.
+	KubeletCrashRate float64
+	KubeletDowntime  time.Duration
+
+	// NodeFlapRate is the chance, on every pod sync, that a node reports NotReady for
+	// NodeFlapDuration before going back to Ready.
+	NodeFlapRate     float64
+	NodeFlapDuration time.Duration
+
+	// DropEventRate is the fraction of informer events a controller never receives,
+	// like a dropped watch event. Only a resync or a later change brings them back.
+	DropEventRate float64
+}
+
+// AddAPIServerFlags registers the flags for faults injected by the API server.
+func (c *Config) AddAPIServerFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&c.APIErrorRate, "chaos-api-error-rate", 0, "Fraction of API requests to fail with a 500, from 0 to 1")
+	fs.DurationVar(&c.APILatency, "chaos-api-latency", 0, "Add a random delay of up to this much to every API request")
+}
+
+// AddKubeletFlags registers the flags for faults injected by kubelets.
+func (c *Config) AddKubeletFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&c.KubeletCrashRate, "chaos-kubelet-crash-rate", 0, "Chance per pod sync that the kubelet crashes and restarts, from 0 to 1")
+	fs.DurationVar(&c.KubeletDowntime, "chaos-kubelet-downtime", 10*time.Second, "How long a crashed kubelet stays down")
+	fs.Float64Var(&c.NodeFlapRate, "chaos-node-flap-rate", 0, "Chance per pod sync that the node turns NotReady for a while, from 0 to 1")
+	fs.DurationVar(&c.NodeFlapDuration, "chaos-node-flap-duration", 20*time.Second, "How long a flapping node stays NotReady")
+}
+
+// AddControllerFlags registers the flags for faults injected into controllers.
+func (c *Config) AddControllerFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&c.DropEventRate, "chaos-drop-event-rate", 0, "Fraction of informer events controllers never receive, from 0 to 1")
+}
+
+// Validate checks that every rate is a probability.
+func (c Config) Validate() error {
+	for name, rate := range map[string]float64{
+		"chaos-api-error-rate":     c.APIErrorRate,
+		"chaos-kubelet-crash-rate": c.KubeletCrashRate,
+		"chaos-node-flap-rate":     c.NodeFlapRate,
+		"chaos-drop-event-rate":    c.DropEventRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("--%s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+	return nil
+}
+
+// Injector decides when faults happen. A nil *Injector never injects anything, so
+// components can call it unconditionally.
+type Injector struct {
+	cfg Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// New returns an injector for cfg, or nil if cfg injects no faults.
+func New(cfg Config) *Injector {
+	if cfg.APIErrorRate == 0 && cfg.APILatency == 0 && cfg.KubeletCrashRate == 0 && cfg.NodeFlapRate == 0 && cfg.DropEventRate == 0 {
+		return nil
+	}
+	return NewWithSource(cfg, rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewWithSource returns an injector for cfg drawing from src, for reproducible runs.
+func NewWithSource(cfg Config, src rand.Source) *Injector {
+	return &Injector{cfg: cfg, rand: rand.New(src)}
+}
+
+// roll reports true with probability rate.
+func (i *Injector) roll(rate float64) bool {
+	if i == nil || rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < rate
+}
+
+// FailAPIRequest reports whether the API server should fail the current request.
+func (i *Injector) FailAPIRequest() bool {
+	return i != nil && i.roll(i.cfg.APIErrorRate)
+}
+
+// APIDelay returns how long the API server should stall the current request.
+func (i *Injector) APIDelay() time.Duration {
+	if i == nil || i.cfg.APILatency <= 0 {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Duration(i.rand.Int63n(int64(i.cfg.APILatency) + 1))
+}
+
+// CrashKubelet reports whether a kubelet should crash now, and if so for how long.
+func (i *Injector) CrashKubelet() (bool, time.Duration) {
+	if i == nil || !i.roll(i.cfg.KubeletCrashRate) {
+		return false, 0
+	}
+	return true, i.cfg.KubeletDowntime
+}
+
+// FlapNode reports whether a node should turn NotReady now, and if so for how long.
+func (i *Injector) FlapNode() (bool, time.Duration) {
+	if i == nil || !i.roll(i.cfg.NodeFlapRate) {
+		return false, 0
+	}
+	return true, i.cfg.NodeFlapDuration
+}
+
+// DropEvent reports whether a controller should ignore the current informer event.
+func (i *Injector) DropEvent() bool {
+	return i != nil && i.roll(i.cfg.DropEventRate)
+}
@@ -0,0 +1,67 @@
+package chaos
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestNilInjectorNeverInjects(t *testing.T) {
+	var inj *Injector
+	if inj.FailAPIRequest() || inj.APIDelay() != 0 || inj.DropEvent() {
+		t.Error("expected a nil injector to inject nothing")
+	}
+	if crash, _ := inj.CrashKubelet(); crash {
+		t.Error("expected a nil injector not to crash kubelets")
+	}
+	if flap, _ := inj.FlapNode(); flap {
+		t.Error("expected a nil injector not to flap nodes")
+	}
+	if New(Config{KubeletDowntime: time.Second, NodeFlapDuration: time.Second}) != nil {
+		t.Error("expected New to return nil when no fault has a rate")
+	}
+}
+
+func TestInjectorRates(t *testing.T) {
+	inj := NewWithSource(Config{
+		APIErrorRate:     1,
+		APILatency:       50 * time.Millisecond,
+		KubeletCrashRate: 1,
+		KubeletDowntime:  10 * time.Second,
+		DropEventRate:    0.5,
+	}, rand.NewSource(1))
+
+	if !inj.FailAPIRequest() {
+		t.Error("expected a rate of 1 to always fail API requests")
+	}
+	if crash, downtime := inj.CrashKubelet(); !crash || downtime != 10*time.Second {
+		t.Errorf("expected a crash with 10s downtime, got %v, %v", crash, downtime)
+	}
+	if flap, _ := inj.FlapNode(); flap {
+		t.Error("expected a rate of 0 never to flap nodes")
+	}
+
+	dropped := 0
+	for i := 0; i < 1000; i++ {
+		if d := inj.APIDelay(); d < 0 || d > 50*time.Millisecond {
+			t.Fatalf("API delay %v outside [0, 50ms]", d)
+		}
+		if inj.DropEvent() {
+			dropped++
+		}
+	}
+	if dropped < 400 || dropped > 600 {
+		t.Errorf("expected about half of 1000 events dropped, got %d", dropped)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Config{APIErrorRate: 0.2, DropEventRate: 1}).Validate(); err != nil {
+		t.Errorf("unexpected error for valid rates: %v", err)
+	}
+	for _, cfg := range []Config{{APIErrorRate: 1.5}, {KubeletCrashRate: -0.1}, {NodeFlapRate: 2}, {DropEventRate: -1}} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", cfg)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package chart renders bundles of templated manifests, a small take on Helm charts.
+// A chart is a directory holding default values and the manifests to render with
+// them:
+//
+//	web/
+//	  values.yaml          replicas: 2, image: nginx, ...
+//	  templates/app.yaml   name: {{ .Release.Name }}-web
+//	                       replicas: {{ .Values.replicas }}
+//
+// Each file in templates is a Go text/template. It is given the chart's values, with
+// any the user passed merged over them, as .Values; the release being rendered as
+// .Release.Name and .Release.Namespace; and the chart's directory name as .Chart.Name.
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValuesFile and TemplatesDir are where a chart keeps its default values and its
+// templates.
+const (
+	ValuesFile   = "values.yaml"
+	TemplatesDir = "templates"
+)
+
+// Release names one rendering of a chart, and the namespace its objects go in.
+type Release struct {
+	Name      string
+	Namespace string
+}
+
+// Values are the settings a chart's templates are rendered with, as decoded from YAML.
+type Values map[string]interface{}
+
+// ReadValues reads the values file at path. An empty file holds no values.
+func ReadValues(path string) (Values, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Decoding into a Values would make nested maps Values too; templates and Merge
+	// expect plain maps.
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, nil
+}
+
+// Merge sets the values in overrides on v. Maps in both are merged key by key, so an
+// override need only list the values it changes; anything else is replaced whole.
+func (v Values) Merge(overrides Values) {
+	for key, value := range overrides {
+		override, overrideIsMap := value.(map[string]interface{})
+		existing, existingIsMap := v[key].(map[string]interface{})
+		if overrideIsMap && existingIsMap {
+			Values(existing).Merge(override)
+			continue
+		}
+		v[key] = value
+	}
+}
+
+// Set sets one value from an assignment such as "image.tag=1.27", as helm's --set
+// does: the key's dots descend into nested maps, created as needed, and a value that
+// reads as a YAML number, boolean, or null takes that type. Anything else is a string.
+func (v Values) Set(assignment string) error {
+	key, raw, ok := strings.Cut(assignment, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid value %q: must be KEY=VALUE", assignment)
+	}
+	var value interface{} = raw
+	var scalar interface{}
+	if err := yaml.Unmarshal([]byte(raw), &scalar); err == nil {
+		switch scalar.(type) {
+		case int, float64, bool, nil:
+			value = scalar
+		}
+	}
+	path := strings.Split(key, ".")
+	m := v
+	for _, part := range path[:len(path)-1] {
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[part] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+	return nil
+}
+
+// Render renders the chart in dir for release, with values merged over the chart's
+// own, and returns the manifests as one multi-document YAML stream. Each document is
+// preceded by a comment naming the template it came from, and templates that render
+// to nothing are left out.
+func Render(dir string, release Release, values Values) ([]byte, error) {
+	merged := Values{}
+	if defaults, err := ReadValues(filepath.Join(dir, ValuesFile)); err == nil {
+		merged = defaults
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	merged.Merge(values)
+
+	paths, err := filepath.Glob(filepath.Join(dir, TemplatesDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(abs)
+	data := map[string]interface{}{
+		"Values":  map[string]interface{}(merged),
+		"Release": release,
+		"Chart":   map[string]string{"Name": name},
+	}
+
+	var out bytes.Buffer
+	rendered := 0
+	for _, path := range paths {
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		source := filepath.ToSlash(filepath.Join(name, TemplatesDir, filepath.Base(path)))
+		tmpl, err := template.New(source).Funcs(funcs).Parse(string(src))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		// Values that aren't set render as "<no value>"; Helm renders them as
+		// nothing, so that a template can leave out what isn't configured.
+		doc := strings.ReplaceAll(buf.String(), "<no value>", "")
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		fmt.Fprintf(&out, "---\n# Source: %s\n%s", source, doc)
+		if !strings.HasSuffix(doc, "\n") {
+			out.WriteByte('\n')
+		}
+		rendered++
+	}
+	if rendered == 0 {
+		return nil, fmt.Errorf("chart %s has no templates that render to anything in %s", name, filepath.Join(dir, TemplatesDir))
+	}
+	return out.Bytes(), nil
+}
+
+// funcs are the functions templates can call beyond text/template's own, named as in
+// Helm.
+var funcs = template.FuncMap{
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" || value == false || value == 0 {
+			return fallback
+		}
+		return value
+	},
+	"required": func(msg string, value interface{}) (interface{}, error) {
+		if value == nil || value == "" {
+			return nil, errors.New(msg)
+		}
+		return value, nil
+	},
+	"quote": func(value interface{}) string {
+		return fmt.Sprintf("%q", fmt.Sprint(value))
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"toYaml": func(value interface{}) (string, error) {
+		data, err := yaml.Marshal(value)
+		return strings.TrimSuffix(string(data), "\n"), err
+	},
+	"indent": indent,
+	"nindent": func(spaces int, s string) string {
+		return "\n" + indent(spaces, s)
+	},
+}
+
+// indent puts spaces before every line of s.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
@@ -0,0 +1,108 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeChart(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "web")
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRender(t *testing.T) {
+	dir := writeChart(t, map[string]string{
+		"values.yaml": "replicas: 1\nimage:\n  repository: nginx\n  tag: \"1.25\"\nlabels:\n  tier: frontend\nservice:\n  enabled: false\n",
+		"templates/deployment.yaml": `kind: Deployment
+name: {{ .Release.Name }}-{{ .Chart.Name }}
+namespace: {{ .Release.Namespace }}
+replicas: {{ .Values.replicas }}
+selector:
+  app: {{ .Release.Name }}
+template:
+  labels:
+    app: {{ .Release.Name }}
+    {{- toYaml .Values.labels | nindent 4 }}
+  image: {{ .Values.image.repository }}:{{ .Values.image.tag }}
+  annotations:
+    owner: {{ .Values.owner | default "platform" | quote }}
+`,
+		"templates/service.yaml": "{{ if .Values.service.enabled }}kind: Service\nname: {{ .Release.Name }}\n{{ end }}",
+		"templates/NOTES.txt":    "not a manifest",
+	})
+
+	values := Values{"image": map[string]interface{}{"tag": "1.27"}}
+	for _, set := range []string{"replicas=3", "labels.track=stable"} {
+		if err := values.Set(set); err != nil {
+			t.Fatalf("Set %s: %v", set, err)
+		}
+	}
+	out, err := Render(dir, Release{Name: "shop", Namespace: "prod"}, values)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := `---
+# Source: web/templates/deployment.yaml
+kind: Deployment
+name: shop-web
+namespace: prod
+replicas: 3
+selector:
+  app: shop
+template:
+  labels:
+    app: shop
+    tier: frontend
+    track: stable
+  image: nginx:1.27
+  annotations:
+    owner: "platform"
+`
+	if string(out) != want {
+		t.Errorf("unexpected rendering, with the disabled service left out:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"no templates":   {"values.yaml": "replicas: 1\n"},
+		"bad template":   {"templates/pod.yaml": "name: {{ .Values.name\n"},
+		"required value": {"templates/pod.yaml": "name: {{ required \"name is required\" .Values.name }}\n"},
+		"bad values":     {"values.yaml": "replicas: [\n", "templates/pod.yaml": "kind: Pod\n"},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Render(writeChart(t, files), Release{Name: "shop"}, nil); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestValuesSet(t *testing.T) {
+	values := Values{}
+	for _, set := range []string{"replicas=3", "debug=true", "image.tag=1.27", "image.repository=nginx", "name=web: front"} {
+		if err := values.Set(set); err != nil {
+			t.Fatalf("Set %s: %v", set, err)
+		}
+	}
+	image, _ := values["image"].(map[string]interface{})
+	if values["replicas"] != 3 || values["debug"] != true || image["tag"] != 1.27 || image["repository"] != "nginx" || values["name"] != "web: front" {
+		t.Errorf("unexpected values %v", values)
+	}
+	if err := values.Set("replicas"); err == nil || !strings.Contains(err.Error(), "KEY=VALUE") {
+		t.Errorf("expected an assignment without = to fail, got %v", err)
+	}
+}
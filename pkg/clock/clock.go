@@ -0,0 +1,115 @@
+// Package clock abstracts the passage of time for the components' loops: sync
+// intervals, back-offs, and timeouts. Components use RealClock by default, a
+// ScaledClock to run simulations faster than real time, and tests use a FakeClock
+// that only moves when told to.
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	// After waits for d to pass and then sends the current time on the channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a ticker that ticks every d.
+	NewTicker(d time.Duration) Ticker
+	// AfterFunc calls f in its own goroutine once d has passed.
+	AfterFunc(d time.Duration, f func()) Timer
+	Sleep(d time.Duration)
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer is a pending AfterFunc call.
+type Timer interface {
+	// Stop cancels the call, reporting whether it was still pending.
+	Stop() bool
+}
+
+// RealClock is the wall clock.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time                         { return time.Now() }
+func (RealClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (RealClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (RealClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{time.NewTicker(d)}
+}
+
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time { return t.t.C }
+func (t *realTicker) Stop()               { t.t.Stop() }
+
+// ScaledClock runs scale times faster than the wall clock: a ten-second interval on a
+// 10x clock passes in one real second. Now starts at the wall-clock time the clock
+// was created and advances scale seconds per real second. The times sent on its
+// channels are wall-clock times; the loops using them only wait for the signal.
+type ScaledClock struct {
+	scale float64
+	start time.Time
+}
+
+// NewScaledClock returns a clock running scale times faster than real time. A scale
+// of 1 behaves like RealClock.
+func NewScaledClock(scale float64) *ScaledClock {
+	return &ScaledClock{scale: scale, start: time.Now()}
+}
+
+// Scale returns how many times faster than real time the clock runs.
+func (c *ScaledClock) Scale() float64 { return c.scale }
+
+func (c *ScaledClock) real(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / c.scale)
+}
+
+func (c *ScaledClock) Now() time.Time {
+	return c.start.Add(time.Duration(float64(time.Since(c.start)) * c.scale))
+}
+
+func (c *ScaledClock) Since(t time.Time) time.Duration        { return c.Now().Sub(t) }
+func (c *ScaledClock) After(d time.Duration) <-chan time.Time { return time.After(c.real(d)) }
+func (c *ScaledClock) Sleep(d time.Duration)                  { time.Sleep(c.real(d)) }
+
+func (c *ScaledClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{time.NewTicker(c.real(d))}
+}
+
+func (c *ScaledClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(c.real(d), f)
+}
+
+// ForScale returns RealClock for a scale of 1 and a ScaledClock otherwise.
+func ForScale(scale float64) Clock {
+	if scale == 1 {
+		return RealClock{}
+	}
+	return NewScaledClock(scale)
+}
+
+// ParseScale parses a --time-scale value such as "10x", "10", or "0.5x".
+func ParseScale(s string) (float64, error) {
+	scale, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "x"), 64)
+	if err != nil || scale <= 0 {
+		return 0, fmt.Errorf("invalid time scale %q: want a positive factor such as 10x", s)
+	}
+	return scale, nil
+}
@@ -0,0 +1,87 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	after := c.After(10 * time.Second)
+	ticker := c.NewTicker(3 * time.Second)
+	fired := make(chan struct{})
+	c.AfterFunc(5*time.Second, func() { close(fired) })
+	cancelled := c.AfterFunc(time.Second, func() { t.Error("stopped AfterFunc was called") })
+	if !cancelled.Stop() || cancelled.Stop() {
+		t.Error("expected Stop to report true once, then false")
+	}
+	if c.Waiters() != 3 {
+		t.Fatalf("expected 3 waiters, got %d", c.Waiters())
+	}
+
+	c.Step(4 * time.Second)
+	if got := <-ticker.C(); !got.Equal(start.Add(4 * time.Second)) {
+		t.Errorf("ticker: got %v, want the time of the step", got)
+	}
+	select {
+	case <-after:
+		t.Fatal("After fired early")
+	default:
+	}
+
+	// Stepping past several ticks delivers a single one.
+	c.Step(6 * time.Second)
+	<-after
+	<-fired
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Error("expected missed ticks to be dropped")
+	default:
+	}
+	if got := c.Since(start); got != 10*time.Second {
+		t.Errorf("Since: got %v, want 10s", got)
+	}
+
+	ticker.Stop()
+	if c.Waiters() != 0 {
+		t.Errorf("expected no waiters after the ticker stopped, got %d", c.Waiters())
+	}
+	c.Sleep(time.Minute)
+	if got := c.Now(); !got.Equal(start.Add(70 * time.Second)) {
+		t.Errorf("Sleep should advance the clock: got %v", got)
+	}
+}
+
+func TestScaledClock(t *testing.T) {
+	c := NewScaledClock(100)
+	before, realBefore := c.Now(), time.Now()
+	ticker := c.NewTicker(time.Second)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("a 1s tick on a 100x clock should arrive in about 10ms")
+	}
+	if elapsed, realElapsed := c.Since(before), time.Since(realBefore); elapsed < 50*realElapsed {
+		t.Errorf("expected scaled time to run ~100x faster, got %v for %v of real time", elapsed, realElapsed)
+	}
+}
+
+func TestParseScale(t *testing.T) {
+	for in, want := range map[string]float64{"10x": 10, "10": 10, "0.5x": 0.5, " 1x ": 1} {
+		if got, err := ParseScale(in); err != nil || got != want {
+			t.Errorf("ParseScale(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "x", "0x", "-2x", "fast"} {
+		if _, err := ParseScale(in); err == nil {
+			t.Errorf("ParseScale(%q): expected an error", in)
+		}
+	}
+	if _, ok := ForScale(1).(RealClock); !ok {
+		t.Error("expected ForScale(1) to return the real clock")
+	}
+}
@@ -0,0 +1,135 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock for tests whose time only moves when Step (or Sleep) is
+// called. Tickers, After channels, and AfterFunc calls that come due are fired as
+// part of the step.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending After, ticker, or AfterFunc. A ticker has a period and is
+// rescheduled after each tick; the others fire once.
+type fakeWaiter struct {
+	clock  *FakeClock
+	target time.Time
+	period time.Duration
+	ch     chan time.Time
+	fn     func()
+}
+
+// NewFakeClock returns a fake clock set to t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.add(d, 0, nil).ch
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	return fakeTicker{c.add(d, d, nil)}
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	return c.add(d, 0, f)
+}
+
+// Sleep advances the clock by d, as if the caller had slept that long.
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.Step(d)
+}
+
+func (c *FakeClock) add(d, period time.Duration, fn func()) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &fakeWaiter{clock: c, target: c.now.Add(d), period: period, ch: make(chan time.Time, 1), fn: fn}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// Step advances the clock by d and fires everything that came due. A ticker that
+// missed several ticks fires once, like time.Ticker does for a slow receiver.
+func (c *FakeClock) Step(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	var due []*fakeWaiter
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.target.After(now) {
+			pending = append(pending, w)
+			continue
+		}
+		due = append(due, w)
+		if w.period > 0 {
+			for !w.target.After(now) {
+				w.target = w.target.Add(w.period)
+			}
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+	c.mu.Unlock()
+
+	for _, w := range due {
+		if w.fn != nil {
+			go w.fn()
+			continue
+		}
+		select {
+		case w.ch <- now:
+		default:
+		}
+	}
+}
+
+// Waiters returns how many tickers, After channels, and AfterFunc calls are pending,
+// so tests can wait for a loop to block on the clock before stepping it.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeWaiter.Stop()
+}
+
+// Stop removes the waiter, reporting whether it was still pending.
+func (w *fakeWaiter) Stop() bool {
+	c := w.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+// Package componentconfig lets a component take its flags from a YAML file and the
+// environment as well as the command line, and reload some of them while it runs.
+//
+// The file maps flag names to values:
+//
+//	sync-interval: 5s
+//	image-pull-failure-rate: 0.1
+//	preloaded-images: [busybox:1.36, nginx:1.25]
+//
+// K8S_LITE_<COMPONENT>_<FLAG> environment variables, such as
+// K8S_LITE_KUBELET_SYNC_INTERVAL, override the file, and flags given on the command
+// line override both. A component reads them once it has parsed its flags:
+//
+//	cfg := componentconfig.AddFlags(flag.CommandLine, "kubelet")
+//	flag.Parse()
+//	if err := cfg.Load(); err != nil {
+//		log.Fatalf("%v", err)
+//	}
+//	go cfg.Watch(ctx, []string{"v", "sync-interval"}, apply)
+package componentconfig
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of every environment variable a component reads settings
+// from.
+const EnvPrefix = "K8S_LITE_"
+
+// Config is where a component's flags come from besides the command line.
+type Config struct {
+	fs        *flag.FlagSet
+	component string
+	path      string // --config
+
+	explicit map[string]bool   // Flags given on the command line, which nothing overrides
+	loaded   map[string]string // What the file and environment last said, by flag name
+}
+
+// AddFlags registers --config on fs, for component, such as "kubelet" or
+// "controller-manager", and returns the Config to Load once fs is parsed.
+func AddFlags(fs *flag.FlagSet, component string) *Config {
+	c := &Config{fs: fs, component: component}
+	fs.StringVar(&c.path, "config", "", "YAML file of flag names to values, reloaded on SIGHUP or when it changes; flags on the command line override it")
+	return c
+}
+
+// EnvVar returns the environment variable that sets flag name for the component.
+func (c *Config) EnvVar(name string) string {
+	return EnvPrefix + envName(c.component) + "_" + envName(name)
+}
+
+func envName(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+}
+
+// Load sets the flags the file and environment give values for, other than those on
+// the command line. It fails on a setting that isn't a flag or a value its flag
+// doesn't accept.
+func (c *Config) Load() error {
+	c.explicit = make(map[string]bool)
+	c.fs.Visit(func(f *flag.Flag) { c.explicit[f.Name] = true })
+	values, err := c.read()
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(values) {
+		if c.explicit[name] {
+			continue
+		}
+		if err := c.fs.Set(name, values[name]); err != nil {
+			return fmt.Errorf("setting %s from %s: %w", name, c.source(name), err)
+		}
+	}
+	c.loaded = values
+	return nil
+}
+
+// Watch reloads the file and environment whenever the process gets a SIGHUP or the
+// file changes, until ctx is cancelled. Settings in reloadable that changed are set,
+// and a setting removed from the file goes back to its default; then apply, if not
+// nil, puts them into effect. Any other setting that changed only takes effect on a
+// restart, which Watch logs. Without --config, Watch just waits for ctx.
+func (c *Config) Watch(ctx context.Context, reloadable []string, apply func() error) {
+	if c.path == "" {
+		<-ctx.Done()
+		return
+	}
+	canReload := make(map[string]bool, len(reloadable))
+	for _, name := range reloadable {
+		canReload[name] = true
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	changed := make(chan struct{}, 1)
+	go func() {
+		if err := watchFile(ctx, c.path, changed); err != nil {
+			log.Printf("Not watching %s for changes, reload it with SIGHUP instead: %v", c.path, err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-changed:
+		}
+		if err := c.reload(canReload, apply); err != nil {
+			log.Printf("Failed to reload %s: %v", c.path, err)
+		}
+	}
+}
+
+// reload sets the settings in canReload that changed since the last load and calls
+// apply, if any did.
+func (c *Config) reload(canReload map[string]bool, apply func() error) error {
+	values, err := c.read()
+	if err != nil {
+		return err
+	}
+	var applied []string
+	for _, name := range sortedKeys(c.loaded) {
+		if _, ok := values[name]; !ok {
+			values[name] = c.fs.Lookup(name).DefValue
+		}
+	}
+	for _, name := range sortedKeys(values) {
+		value := values[name]
+		if c.explicit[name] {
+			continue
+		}
+		if previous, ok := c.loaded[name]; value == previous || !ok && value == c.fs.Lookup(name).DefValue {
+			continue
+		}
+		if !canReload[name] {
+			log.Printf("Setting %s changed in %s; restart to apply it", name, c.source(name))
+			continue
+		}
+		if err := c.fs.Set(name, value); err != nil {
+			return fmt.Errorf("setting %s from %s: %w", name, c.source(name), err)
+		}
+		applied = append(applied, fmt.Sprintf("%s=%s", name, value))
+	}
+	c.loaded = values
+	if len(applied) == 0 {
+		return nil
+	}
+	if apply != nil {
+		if err := apply(); err != nil {
+			return fmt.Errorf("applying %s: %w", strings.Join(applied, ", "), err)
+		}
+	}
+	log.Printf("Reloaded %s: %s", c.path, strings.Join(applied, ", "))
+	return nil
+}
+
+// read returns the settings in the file, overridden by those in the environment.
+func (c *Config) read() (map[string]string, error) {
+	values := make(map[string]string)
+	if c.path != "" {
+		data, err := os.ReadFile(c.path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		var settings map[string]interface{}
+		if err := yaml.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", c.path, err)
+		}
+		for name, value := range settings {
+			if c.fs.Lookup(name) == nil || name == "config" {
+				return nil, fmt.Errorf("unknown setting %q in %s", name, c.path)
+			}
+			values[name] = formatValue(value)
+		}
+	}
+	c.fs.VisitAll(func(f *flag.Flag) {
+		if value, ok := os.LookupEnv(c.EnvVar(f.Name)); ok && f.Name != "config" {
+			values[f.Name] = value
+		}
+	})
+	return values, nil
+}
+
+// source names where the setting of flag name comes from, for messages.
+func (c *Config) source(name string) string {
+	if _, ok := os.LookupEnv(c.EnvVar(name)); ok {
+		return "$" + c.EnvVar(name)
+	}
+	return c.path
+}
+
+// formatValue returns a YAML value as a flag would take it on the command line; a
+// list becomes a comma-separated string.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package componentconfig
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testFlags struct {
+	fs       *flag.FlagSet
+	cfg      *Config
+	interval *time.Duration
+	rate     *float64
+	images   *string
+	port     *string
+}
+
+func newTestFlags(t *testing.T, file string, args ...string) *testFlags {
+	t.Helper()
+	f := &testFlags{fs: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.cfg = AddFlags(f.fs, "test-component")
+	f.interval = f.fs.Duration("sync-interval", 10*time.Second, "")
+	f.rate = f.fs.Float64("failure-rate", 0, "")
+	f.images = f.fs.String("images", "", "")
+	f.port = f.fs.String("port", "8080", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, file)
+	if err := f.fs.Parse(append([]string{"--config", path}, args...)); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return f
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("K8S_LITE_TEST_COMPONENT_FAILURE_RATE", "0.5")
+	f := newTestFlags(t, "sync-interval: 5s\nfailure-rate: 0.1\nimages: [busybox:1.36, nginx:1.25]\nport: 9090\n", "--port", "7070")
+	if err := f.cfg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *f.interval != 5*time.Second || *f.images != "busybox:1.36,nginx:1.25" {
+		t.Errorf("expected the file's settings, got sync-interval %v and images %q", *f.interval, *f.images)
+	}
+	if *f.rate != 0.5 {
+		t.Errorf("expected the environment to override the file, got failure-rate %v", *f.rate)
+	}
+	if *f.port != "7070" {
+		t.Errorf("expected the command line to override the file, got port %s", *f.port)
+	}
+
+	for _, file := range []string{"sync-intervl: 5s\n", "sync-interval: soon\n", "- not a map\n"} {
+		if err := newTestFlags(t, file).cfg.Load(); err == nil {
+			t.Errorf("expected an error loading %q", file)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	f := newTestFlags(t, "sync-interval: 5s\nfailure-rate: 0.1\n", "--images", "nginx")
+	if err := f.cfg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	applied := 0
+	apply := func() error { applied++; return nil }
+	canReload := map[string]bool{"sync-interval": true, "images": true}
+
+	writeFile(t, f.cfg.path, "failure-rate: 0.2\nimages: busybox\nport: 9090\n")
+	if err := f.cfg.reload(canReload, apply); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if applied != 1 || *f.interval != 10*time.Second {
+		t.Errorf("expected the removed sync-interval to go back to its default and be applied, got %v after %d applies", *f.interval, applied)
+	}
+	if *f.rate != 0.1 || *f.port != "8080" {
+		t.Errorf("expected settings that can't be reloaded to keep their values, got failure-rate %v and port %s", *f.rate, *f.port)
+	}
+	if *f.images != "nginx" {
+		t.Errorf("expected the command line to win over a reload, got images %q", *f.images)
+	}
+
+	if err := f.cfg.reload(canReload, apply); err != nil || applied != 1 {
+		t.Errorf("expected a reload without changes not to apply anything, got %d applies and error %v", applied, err)
+	}
+}
+
+func TestWatchReloadsChangedFile(t *testing.T) {
+	f := newTestFlags(t, "sync-interval: 5s\n")
+	if err := f.cfg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan time.Duration, 1)
+	go f.cfg.Watch(ctx, []string{"sync-interval"}, func() error {
+		reloaded <- *f.interval
+		return nil
+	})
+
+	// Replace the file as an editor would, by renaming a new one over it. The first
+	// write may land before the watch starts, so keep writing until it is seen.
+	deadline := time.After(5 * time.Second)
+	for {
+		tmp := f.cfg.path + ".tmp"
+		writeFile(t, tmp, "sync-interval: 1s\n")
+		if err := os.Rename(tmp, f.cfg.path); err != nil {
+			t.Fatalf("Rename: %v", err)
+		}
+		select {
+		case got := <-reloaded:
+			if got != time.Second {
+				t.Errorf("expected sync-interval 1s after the reload, got %v", got)
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("timed out waiting for the changed file to be reloaded")
+		}
+	}
+}
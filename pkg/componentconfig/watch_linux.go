@@ -0,0 +1,67 @@
+package componentconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchFile signals changed whenever path is written, or replaced, as editors and
+// configuration management tools tend to, until ctx is cancelled. It watches the
+// directory rather than the file, whose inotify watch a replacement would remove.
+func watchFile(ctx context.Context, path string, changed chan<- struct{}) error {
+	fd, err := unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
+	if err != nil {
+		return err
+	}
+	// A non-blocking descriptor goes through the runtime poller, so closing the file
+	// ends a pending Read.
+	events := os.NewFile(uintptr(fd), "inotify")
+	defer events.Close()
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(path), unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO|unix.IN_CREATE); err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		events.Close()
+	}()
+
+	name := filepath.Base(path)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := events.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			offset = nameStart + int(event.Len)
+			if event.Len == 0 || offset > n {
+				continue
+			}
+			if string(trimNUL(buf[nameStart:offset])) == name {
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// trimNUL drops the NUL padding after a file name in an inotify event.
+func trimNUL(b []byte) []byte {
+	for i, c := range b {
+		if c == 0 {
+			return b[:i]
+		}
+	}
+	return b
+}
@@ -0,0 +1,38 @@
+//go:build !linux
+
+package componentconfig
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// pollInterval is how often watchFile checks the file where there is no inotify.
+const pollInterval = 2 * time.Second
+
+// watchFile signals changed whenever the modification time or size of path changes,
+// until ctx is cancelled.
+func watchFile(ctx context.Context, path string, changed chan<- struct{}) error {
+	last, _ := os.Stat(path)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Being replaced, perhaps; look again next time
+		}
+		if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+			last = info
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package controller provides the shared plumbing used by k8s-lite-go controllers:
+// an informer that feeds object keys into a work queue, a pool of workers that call a
+// reconcile function for each key, optional leader election, and graceful shutdown.
+//
+// A controller only has to supply its reconcile logic:
+//
+//	informer := controller.NewPodInformer(client, "default", 5*time.Second)
+//	c := controller.New(informer, controller.NewWorkQueue(), func(ctx context.Context, key string) error {
+//		namespace, name := controller.SplitMetaNamespaceKey(key)
+//		// ... drive the object towards its desired state ...
+//		return nil
+//	})
+//	c.Run(ctx, 2)
+package controller
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+// ReconcileFunc brings the object identified by key to its desired state.
+// Returning an error re-queues the key with exponential backoff.
+type ReconcileFunc func(ctx context.Context, key string) error
+
+// Controller wires an Informer, a WorkQueue, and a ReconcileFunc together.
+type Controller struct {
+	Name string
+
+	informer  Informer
+	queue     *WorkQueue
+	reconcile ReconcileFunc
+	keyFunc   KeyFunc
+	elector   LeaderElector
+	chaos     *chaos.Injector
+
+	// MaxRetries is how many times a failing key is retried before it is dropped
+	// until the informer reports another change for it. Zero means retry forever.
+	MaxRetries int
+	// ResyncPeriod, if set, re-queues every cached object on this interval so
+	// reconcilers also correct drift that produced no informer event.
+	ResyncPeriod time.Duration
+	// Clock times resyncs; New sets it to the real clock unless WithClock is given.
+	Clock clock.Clock
+}
+
+// Option configures a Controller.
+type Option func(*Controller)
+
+// WithName sets the name used in the controller's log lines.
+func WithName(name string) Option {
+	return func(c *Controller) { c.Name = name }
+}
+
+// WithKeyFunc overrides the function used to turn informer objects into queue keys.
+func WithKeyFunc(keyFunc KeyFunc) Option {
+	return func(c *Controller) { c.keyFunc = keyFunc }
+}
+
+// WithLeaderElector makes the controller only run its workers while it holds leadership.
+func WithLeaderElector(elector LeaderElector) Option {
+	return func(c *Controller) { c.elector = elector }
+}
+
+// WithChaos makes the controller drop informer events as inj decides, to show how
+// resyncs and later changes make up for missed events.
+func WithChaos(inj *chaos.Injector) Option {
+	return func(c *Controller) { c.chaos = inj }
+}
+
+// WithClock sets the clock used for resyncs and retry back-off, and for polling when
+// the informer is a PollingInformer. Secondary informers need their Clock set
+// separately.
+func WithClock(clk clock.Clock) Option {
+	return func(c *Controller) {
+		c.Clock = clk
+		c.queue.Clock = clk
+		if inf, ok := c.informer.(*PollingInformer); ok {
+			inf.Clock = clk
+		}
+	}
+}
+
+// New creates a controller that reconciles every object reported by informer.
+func New(informer Informer, queue *WorkQueue, reconcile ReconcileFunc, opts ...Option) *Controller {
+	c := &Controller{
+		Name:       "controller",
+		informer:   informer,
+		queue:      queue,
+		reconcile:  reconcile,
+		keyFunc:    MetaNamespaceKeyFunc,
+		MaxRetries: 10,
+		Clock:      clock.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	informer.AddEventHandler(EventHandler{
+		OnAdd:    c.handleEvent,
+		OnUpdate: func(_, newObj interface{}) { c.handleEvent(newObj) },
+		OnDelete: c.handleEvent,
+	})
+	return c
+}
+
+// Queue returns the controller's work queue, so reconcilers and other event
+// sources can enqueue additional keys.
+func (c *Controller) Queue() *WorkQueue {
+	return c.queue
+}
+
+// Informer returns the informer feeding this controller.
+func (c *Controller) Informer() Informer {
+	return c.informer
+}
+
+// handleEvent enqueues an object the informer reported, unless chaos drops the event.
+func (c *Controller) handleEvent(obj interface{}) {
+	if c.chaos.DropEvent() {
+		key, _ := c.keyFunc(obj)
+		log.Printf("[%s] Chaos: dropped informer event for %q", c.Name, key)
+		return
+	}
+	c.enqueue(obj)
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := c.keyFunc(obj)
+	if err != nil {
+		log.Printf("[%s] %v", c.Name, err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run starts the informer and the given number of workers and blocks until ctx is
+// cancelled, or until leadership is lost when a LeaderElector is configured.
+// On shutdown it stops accepting new work and waits for in-flight reconciles to
+// finish before returning.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if c.elector == nil {
+		c.run(ctx, workers)
+		return
+	}
+
+	// The work queue cannot be restarted once shut down, so losing leadership ends Run.
+	// Callers typically exit and let a fresh replica compete for the lock.
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c.elector.Run(electionCtx, LeaderCallbacks{
+		OnStartedLeading: func(leaderCtx context.Context) {
+			log.Printf("[%s] Acquired leadership", c.Name)
+			c.run(leaderCtx, workers)
+		},
+		OnStoppedLeading: func() {
+			log.Printf("[%s] Lost leadership", c.Name)
+			cancel()
+		},
+	})
+}
+
+func (c *Controller) run(ctx context.Context, workers int) {
+	go c.informer.Run(ctx)
+
+	log.Printf("[%s] Waiting for informer cache to sync", c.Name)
+	if !WaitForCacheSync(ctx, c.informer) {
+		log.Printf("[%s] Shutting down before cache synced", c.Name)
+		c.queue.ShutDown()
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c.processNextItem(ctx) {
+			}
+		}()
+	}
+
+	if c.ResyncPeriod > 0 {
+		go c.resyncLoop(ctx)
+	}
+
+	log.Printf("[%s] Started %d workers", c.Name, workers)
+	<-ctx.Done()
+
+	log.Printf("[%s] Shutting down, waiting for workers to finish", c.Name)
+	c.queue.ShutDown()
+	wg.Wait()
+	log.Printf("[%s] Stopped", c.Name)
+}
+
+func (c *Controller) resyncLoop(ctx context.Context) {
+	ticker := c.Clock.NewTicker(c.ResyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			for _, obj := range c.informer.List() {
+				c.enqueue(obj)
+			}
+		}
+	}
+}
+
+// processNextItem handles one key from the queue. It returns false once the queue
+// has been shut down and drained.
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if ctx.Err() != nil {
+		// Shutting down: leave remaining keys unprocessed.
+		return true
+	}
+
+	if err := c.reconcile(ctx, key); err != nil {
+		if c.MaxRetries > 0 && c.queue.NumRequeues(key) >= c.MaxRetries {
+			log.Printf("[%s] Dropping %q after %d retries: %v", c.Name, key, c.MaxRetries, err)
+			c.queue.Forget(key)
+			return true
+		}
+		log.Printf("[%s] Error reconciling %q, requeueing: %v", c.Name, key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// WaitForCacheSync blocks until all informers have synced or ctx is cancelled.
+// It returns false if ctx was cancelled first.
+func WaitForCacheSync(ctx context.Context, informers ...Informer) bool {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		synced := true
+		for _, inf := range informers {
+			if !inf.HasSynced() {
+				synced = false
+				break
+			}
+		}
+		if synced {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestWorkQueueDeduplicates(t *testing.T) {
+	q := NewWorkQueue()
+	q.Add("default/a")
+	q.Add("default/a")
+	q.Add("default/b")
+
+	if got := q.Len(); got != 2 {
+		t.Fatalf("expected 2 queued keys, got %d", got)
+	}
+
+	key, _ := q.Get()
+	if key != "default/a" {
+		t.Fatalf("expected default/a first, got %s", key)
+	}
+
+	// Adding a key while it is being processed defers it until Done.
+	q.Add("default/a")
+	if got := q.Len(); got != 1 {
+		t.Fatalf("expected in-flight key not to be queued twice, got len %d", got)
+	}
+	q.Done("default/a")
+	if got := q.Len(); got != 2 {
+		t.Fatalf("expected key to be re-queued after Done, got len %d", got)
+	}
+
+	q.ShutDown()
+	for {
+		if _, quit := q.Get(); quit {
+			break
+		}
+	}
+}
+
+func TestWorkQueueBackOffFollowsClock(t *testing.T) {
+	q := NewWorkQueue()
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	q.Clock = clk
+
+	// Failures back off 5ms, 10ms, 20ms, ... measured on the queue's clock.
+	q.AddRateLimited("default/a")
+	q.AddRateLimited("default/a")
+	clk.Step(5 * time.Millisecond)
+	waitForLen(t, q, 1)
+	key, _ := q.Get()
+	q.Done(key)
+
+	clk.Step(4 * time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if got := q.Len(); got != 0 {
+		t.Fatalf("expected the second retry to wait for its 10ms back-off, got len %d", got)
+	}
+	clk.Step(time.Millisecond)
+	waitForLen(t, q, 1)
+}
+
+// waitForLen waits up to a second for q to hold n keys.
+func waitForLen(t *testing.T, q *WorkQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for q.Len() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d queued keys, have %d", n, q.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestControllerReconcilesAndRetries(t *testing.T) {
+	pods := []interface{}{
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: "default"}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "b", Namespace: "default"}},
+	}
+	informer := NewPollingInformer(func() ([]interface{}, error) { return pods, nil }, MetaNamespaceKeyFunc, time.Hour)
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	c := New(informer, NewWorkQueue(), func(ctx context.Context, key string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[key]++
+		if key == "default/b" && calls[key] < 3 {
+			return errors.New("transient failure")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx, 2)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		ok := calls["default/a"] == 1 && calls["default/b"] == 3
+		mu.Unlock()
+		if ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("controller did not shut down")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["default/a"] != 1 || calls["default/b"] != 3 {
+		t.Fatalf("unexpected reconcile calls: %v", calls)
+	}
+}
+
+func TestLeaseElectorSingleLeader(t *testing.T) {
+	var mu sync.Mutex
+	holder := ""
+	lock := LockFuncs{
+		TryAcquireOrRenew: func(_ context.Context, id string) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if holder == "" || holder == id {
+				holder = id
+				return true, nil
+			}
+			return false, nil
+		},
+		Release: func(_ context.Context, id string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if holder == id {
+				holder = ""
+			}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var leading sync.Map
+	for _, id := range []string{"one", "two"} {
+		id := id
+		e := &LeaseElector{Identity: id, Lock: lock, LeaseDuration: time.Second, RetryPeriod: 10 * time.Millisecond}
+		go e.Run(ctx, LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				leading.Store(id, true)
+				<-ctx.Done()
+			},
+		})
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	count := 0
+	leading.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Fatalf("expected exactly one leader, got %d", count)
+	}
+}
+
+func TestLeaseLock(t *testing.T) {
+	client := fake.NewClient()
+	clk := clock.NewFakeClock(time.Now())
+	lock := NewLeaseLock(client, "default", "scheduler", 15*time.Second, clk)
+	ctx := context.Background()
+
+	if ok, err := lock.TryAcquireOrRenew(ctx, "one"); !ok || err != nil {
+		t.Fatalf("expected one to create and take the lease, got %v, %v", ok, err)
+	}
+	if ok, err := lock.TryAcquireOrRenew(ctx, "two"); ok || err != nil {
+		t.Fatalf("expected two to be refused a held lease, got %v, %v", ok, err)
+	}
+	if ok, err := lock.TryAcquireOrRenew(ctx, "one"); !ok || err != nil {
+		t.Fatalf("expected one to renew its lease, got %v, %v", ok, err)
+	}
+
+	clk.Step(16 * time.Second)
+	if ok, err := lock.TryAcquireOrRenew(ctx, "two"); !ok || err != nil {
+		t.Fatalf("expected two to take the expired lease, got %v, %v", ok, err)
+	}
+	if err := lock.Release(ctx, "two"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	lease, err := client.GetLease("default", "scheduler")
+	if err != nil || lease.HolderIdentity != "" {
+		t.Fatalf("expected the released lease to have no holder, got %+v, %v", lease, err)
+	}
+	if ok, err := lock.TryAcquireOrRenew(ctx, "one"); !ok || err != nil {
+		t.Fatalf("expected one to take the released lease, got %v, %v", ok, err)
+	}
+}
+
+func TestPollingInformerFollowsWatch(t *testing.T) {
+	pod := func(name, image string) *api.Pod {
+		return &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: "default"}, Image: image}
+	}
+	watches := 0
+	informer := NewPollingInformer(nil, MetaNamespaceKeyFunc, time.Millisecond)
+	informer.watchFunc = func(ctx context.Context, fn func(t api.WatchEventType, obj interface{}) error) error {
+		watches++
+		if watches == 1 {
+			fn(api.WatchAdded, pod("a", "v1"))
+			fn(api.WatchAdded, pod("b", "v1"))
+			fn(api.WatchBookmark, nil)
+			fn(api.WatchModified, pod("a", "v2"))
+			fn(api.WatchDeleted, pod("b", "v1"))
+			return api.ErrWatchExpired
+		}
+		// The next watch's initial state is diffed against the cache.
+		fn(api.WatchAdded, pod("a", "v2"))
+		fn(api.WatchAdded, pod("c", "v1"))
+		fn(api.WatchBookmark, nil)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	var mu sync.Mutex
+	var got []string
+	record := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, fmt.Sprintf(format, args...))
+	}
+	informer.AddEventHandler(EventHandler{
+		OnAdd:    func(obj interface{}) { record("add %s", obj.(*api.Pod).Name) },
+		OnUpdate: func(old, obj interface{}) { record("update %s %s", obj.(*api.Pod).Name, obj.(*api.Pod).Image) },
+		OnDelete: func(obj interface{}) { record("delete %s", obj.(*api.Pod).Name) },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informer.Run(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		if len(got) >= 5 || time.Now().After(deadline) {
+			break
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	defer mu.Unlock()
+	if len(got) >= 2 {
+		sort.Strings(got[:2]) // The initial adds come in no particular order
+	}
+	if want := "[add a add b update a v2 delete b add c]"; fmt.Sprint(got) != want {
+		t.Errorf("expected notifications %s, got %v", want, got)
+	}
+}
+
+func TestExpectations(t *testing.T) {
+	clk := clock.NewFakeClock(time.Now())
+	e := NewExpectations(clk)
+	const key = "default/web"
+	if !e.Satisfied(key) {
+		t.Fatal("expected a key with no expectations to be satisfied")
+	}
+
+	e.ExpectCreations(key, 2)
+	e.CreationObserved(key)
+	if e.Satisfied(key) {
+		t.Fatal("expected one of two creates to leave the key unsatisfied")
+	}
+	e.CreationObserved(key)
+	if !e.Satisfied(key) {
+		t.Fatal("expected the key to be satisfied once both creates were observed")
+	}
+
+	// A delete observed twice, once as a deletionTimestamp and once as the object going
+	// away, counts once.
+	e.ExpectDeletions(key, []string{"uid-1", "uid-2"})
+	e.DeletionObserved(key, "uid-1")
+	e.DeletionObserved(key, "uid-1")
+	if e.Satisfied(key) {
+		t.Fatal("expected uid-2 still to be awaited")
+	}
+	clk.Step(ExpectationsTimeout)
+	if !e.Satisfied(key) {
+		t.Fatal("expected expectations to expire after ExpectationsTimeout")
+	}
+
+	e.ExpectCreations(key, 1)
+	e.Delete(key)
+	if !e.Satisfied(key) {
+		t.Fatal("expected Delete to forget the key's expectations")
+	}
+}
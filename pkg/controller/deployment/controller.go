@@ -0,0 +1,345 @@
+// Package deployment keeps the number of pods each deployment asks for running.
+//
+// The controller owns the pods it creates through a controller ownerReference, so
+// deleting a deployment leaves its pods to the garbage collector. For each deployment
+// it counts its active pods, those that are neither finished nor being deleted, and
+// creates pods from the template or deletes surplus ones until the count matches
+// replicas. Surplus pods that aren't running yet go first, then the newest.
+//
+// Each pod is labelled with pod-template-hash, a hash of the template it was created
+// from. Once the template changes, the pods with another hash are old, and the
+// controller replaces them in a rolling update: it creates one pod from the new
+// template beyond replicas, and deletes an old pod only while replicas others are
+// Running, until no old pods are left.
+//
+// The counts come from the pod informer's cache, which lags behind the controller's
+// own writes. Expectations keep the controller from acting on a deployment again until
+// the pods it created or deleted show up in the cache, so that a slow watch doesn't
+// make it create the same pods over and over.
+package deployment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"maps"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+const controllerName = "deployment"
+
+// maxSurge is how many pods beyond replicas a rolling update may create.
+const maxSurge = 1
+
+// Controller creates and deletes pods to match each deployment's replicas.
+type Controller struct {
+	client   api.Interface
+	recorder record.EventRecorder
+	interval time.Duration
+
+	deployments  controller.Informer
+	pods         controller.Informer
+	expectations *controller.Expectations
+	ctrl         *controller.Controller
+}
+
+// NewController creates a deployment controller that follows deployments and pods
+// through informers' shared informers. opts are passed on to controller.New.
+func NewController(client api.Interface, recorder record.EventRecorder, informers *informer.SharedInformerFactory, opts ...controller.Option) *Controller {
+	c := &Controller{
+		client:       client,
+		recorder:     recorder,
+		interval:     informers.Interval(),
+		deployments:  informers.Deployments(),
+		pods:         informers.Pods(),
+		expectations: controller.NewExpectations(informers.Clock()),
+	}
+	opts = append([]controller.Option{controller.WithName(controllerName), controller.WithClock(informers.Clock())}, opts...)
+	c.ctrl = controller.New(c.deployments, controller.NewWorkQueue(), c.reconcile, opts...)
+
+	c.pods.AddEventHandler(controller.EventHandler{
+		OnAdd: func(obj interface{}) {
+			pod, ok := obj.(*api.Pod)
+			if !ok {
+				return
+			}
+			if pod.DeletionTimestamp != nil {
+				c.podDeleted(pod)
+				return
+			}
+			if key, ok := ownerKey(pod); ok {
+				c.expectations.CreationObserved(key)
+				c.ctrl.Queue().Add(key)
+			}
+		},
+		OnUpdate: func(oldObj, newObj interface{}) {
+			pod, ok := newObj.(*api.Pod)
+			if !ok {
+				return
+			}
+			// A pod marked for deletion no longer counts, so the deletion is observed
+			// as soon as the mark is, not when the kubelet lets the pod go.
+			if pod.DeletionTimestamp != nil {
+				c.podDeleted(pod)
+				return
+			}
+			if key, ok := ownerKey(pod); ok {
+				c.ctrl.Queue().Add(key)
+			}
+		},
+		OnDelete: func(obj interface{}) {
+			if pod, ok := obj.(*api.Pod); ok {
+				c.podDeleted(pod)
+			}
+		},
+	})
+	return c
+}
+
+// podDeleted observes the deletion of pod and enqueues its deployment.
+func (c *Controller) podDeleted(pod *api.Pod) {
+	if key, ok := ownerKey(pod); ok {
+		c.expectations.DeletionObserved(key, pod.UID)
+		c.ctrl.Queue().Add(key)
+	}
+}
+
+// ownerKey returns the key of the deployment that controls pod, if one does.
+func ownerKey(pod *api.Pod) (string, bool) {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller && ref.Kind == "Deployment" {
+			return pod.Namespace + "/" + ref.Name, true
+		}
+	}
+	return "", false
+}
+
+// Run runs the controller with the given number of workers until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	go c.pods.Run(ctx)
+	if !controller.WaitForCacheSync(ctx, c.pods) {
+		return
+	}
+	c.ctrl.Run(ctx, workers)
+}
+
+// reconcile handles a single deployment, identified by its namespace/name key.
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	obj, exists := c.deployments.GetByKey(key)
+	if !exists {
+		c.expectations.Delete(key)
+		return nil
+	}
+	d := obj.(*api.Deployment)
+	if d.DeletionTimestamp != nil {
+		return nil
+	}
+	if !c.expectations.Satisfied(key) {
+		// The pod events still to come enqueue the deployment again; checking back
+		// covers expectations that time out instead.
+		c.ctrl.Queue().AddAfter(key, c.interval)
+		return nil
+	}
+
+	hash := PodTemplateHash(&d.Template)
+	var current, old []*api.Pod
+	for _, pod := range c.activePods(d) {
+		if pod.Labels[api.PodTemplateHashLabel] == hash {
+			current = append(current, pod)
+		} else {
+			old = append(old, pod)
+		}
+	}
+	if len(old) > 0 {
+		return c.rollOut(d, key, hash, current, old)
+	}
+	switch diff := d.Replicas - len(current); {
+	case diff > 0:
+		return c.createPods(d, key, hash, diff)
+	case diff < 0:
+		sortForDeletion(current)
+		return c.deletePods(d, key, current[:-diff])
+	}
+	return nil
+}
+
+// rollOut takes one step of replacing old with pods from d's current template: it
+// creates new pods up to replicas, as far as maxSurge allows, and otherwise deletes
+// the old pods that can go without fewer than replicas pods Running.
+func (c *Controller) rollOut(d *api.Deployment, key, hash string, current, old []*api.Pod) error {
+	if n := min(d.Replicas-len(current), d.Replicas+maxSurge-len(current)-len(old)); n > 0 {
+		return c.createPods(d, key, hash, n)
+	}
+	running := 0
+	for _, pod := range append(append([]*api.Pod(nil), current...), old...) {
+		if pod.Phase == api.PodRunning {
+			running++
+		}
+	}
+	sortForDeletion(old)
+	var victims []*api.Pod
+	for _, pod := range old {
+		if pod.Phase == api.PodRunning {
+			if running-1 < d.Replicas {
+				break // The rest are Running too
+			}
+			running--
+		}
+		victims = append(victims, pod)
+	}
+	if len(victims) == 0 {
+		return nil
+	}
+	return c.deletePods(d, key, victims)
+}
+
+// PodTemplateHash returns the pod-template-hash of pods created from template: a
+// short, label-safe hash of its labels, annotations, and image.
+func PodTemplateHash(template *api.PodTemplate) string {
+	data, _ := json.Marshal(template) // Map keys are sorted, so equal templates hash alike
+	h := fnv.New32a()
+	h.Write(data)
+	return safeEncode(strconv.FormatUint(uint64(h.Sum32()), 10))
+}
+
+// activePods returns the cached pods d controls that are neither finished nor being
+// deleted.
+func (c *Controller) activePods(d *api.Deployment) []*api.Pod {
+	var pods []*api.Pod
+	for _, obj := range c.pods.List() {
+		pod, ok := obj.(*api.Pod)
+		if !ok || pod.Namespace != d.Namespace || !controlledBy(pod, d) {
+			continue
+		}
+		if pod.DeletionTimestamp != nil || api.IsPodGone(pod) || pod.Phase == api.PodSucceeded || pod.Phase == api.PodFailed {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func controlledBy(pod *api.Pod, d *api.Deployment) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller && ref.UID == d.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// createPods creates n pods from d's template, whose hash is hash. A create that
+// fails, and those not issued after it, will never be observed, so they are taken off
+// the expectations straight away.
+func (c *Controller) createPods(d *api.Deployment, key, hash string, n int) error {
+	c.expectations.ExpectCreations(key, n)
+	for i := 0; i < n; i++ {
+		pod := &api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:            d.Name + "-" + randomSuffix(),
+				Namespace:       d.Namespace,
+				Labels:          templateLabels(&d.Template, hash),
+				Annotations:     maps.Clone(d.Template.Annotations),
+				OwnerReferences: []api.OwnerReference{{Kind: "Deployment", Name: d.Name, UID: d.UID, Controller: true}},
+			},
+			Image: d.Template.Image,
+		}
+		created, err := c.client.CreatePod(d.Namespace, pod)
+		if err != nil {
+			for ; i < n; i++ {
+				c.expectations.CreationObserved(key)
+			}
+			c.recorder.Eventf(d, api.EventTypeWarning, "FailedCreate", "Error creating pod: %v", err)
+			return fmt.Errorf("creating pod for deployment %s: %w", key, err)
+		}
+		log.Printf("[%s] Created pod %s/%s for deployment %s", controllerName, created.Namespace, created.Name, key)
+		c.recorder.Eventf(d, api.EventTypeNormal, "SuccessfulCreate", "Created pod: %s", created.Name)
+	}
+	return nil
+}
+
+// templateLabels returns the labels of a pod created from template.
+func templateLabels(template *api.PodTemplate, hash string) map[string]string {
+	labels := maps.Clone(template.Labels)
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[api.PodTemplateHashLabel] = hash
+	return labels
+}
+
+// sortForDeletion orders pods by which to delete first: those furthest from running,
+// then the newest.
+func sortForDeletion(pods []*api.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		if ri, rj := startRank(pods[i]), startRank(pods[j]); ri != rj {
+			return ri < rj
+		}
+		return pods[i].CreationTimestamp.After(pods[j].CreationTimestamp)
+	})
+}
+
+// deletePods deletes victims, pods of d.
+func (c *Controller) deletePods(d *api.Deployment, key string, victims []*api.Pod) error {
+	uids := make([]string, 0, len(victims))
+	for _, pod := range victims {
+		uids = append(uids, pod.UID)
+	}
+	c.expectations.ExpectDeletions(key, uids)
+	for i, pod := range victims {
+		if err := c.client.DeletePod(pod.Namespace, pod.Name); err != nil {
+			// Neither this delete nor the ones not issued will be observed.
+			for _, rest := range victims[i:] {
+				c.expectations.DeletionObserved(key, rest.UID)
+			}
+			c.recorder.Eventf(d, api.EventTypeWarning, "FailedDelete", "Error deleting pod %s: %v", pod.Name, err)
+			return fmt.Errorf("deleting pod %s/%s of deployment %s: %w", pod.Namespace, pod.Name, key, err)
+		}
+		log.Printf("[%s] Deleted pod %s/%s of deployment %s", controllerName, pod.Namespace, pod.Name, key)
+		c.recorder.Eventf(d, api.EventTypeNormal, "SuccessfulDelete", "Deleted pod: %s", pod.Name)
+	}
+	return nil
+}
+
+// startRank orders pods by how far along they are: unscheduled, scheduled, running.
+func startRank(pod *api.Pod) int {
+	switch {
+	case pod.NodeName == "":
+		return 0
+	case pod.Phase != api.PodRunning:
+		return 1
+	}
+	return 2
+}
+
+// alphabet has the characters of generated names and hashes: lowercase letters and
+// digits, without vowels or look-alikes so that they spell no words and read clearly.
+const alphabet = "bcdfghjklmnpqrstvwxz2456789"
+
+// randomSuffix returns five random characters of alphabet for a pod name.
+func randomSuffix() string {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("deployment: reading random bytes: %v", err))
+	}
+	return safeEncode(string(b))
+}
+
+// safeEncode maps every byte of s to a character of alphabet.
+func safeEncode(s string) string {
+	b := []byte(s)
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b)
+}
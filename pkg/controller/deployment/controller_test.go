@@ -0,0 +1,191 @@
+package deployment
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+// runPods does what the scheduler and a kubelet would: it binds and starts every
+// pending pod.
+func runPods(t *testing.T, client api.Interface) {
+	t.Helper()
+	pods, err := client.ListPods("default", api.PodPending)
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	for i := range pods {
+		pod := &pods[i]
+		pod.NodeName = "node1"
+		for _, phase := range []api.PodPhase{api.PodScheduled, api.PodRunning} {
+			pod.Phase = phase
+			if err := client.UpdatePod(pod); err != nil {
+				t.Fatalf("UpdatePod: %v", err)
+			}
+		}
+	}
+}
+
+// waitFor polls cond until it holds or a deadline passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestControllerScalesWithoutDuplicateCreates(t *testing.T) {
+	client := fake.NewClient(&api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"},
+		Replicas:   3,
+		Selector:   map[string]string{"app": "web"},
+		Template:   api.PodTemplate{Labels: map[string]string{"app": "web"}, Image: "nginx:1.25"},
+	})
+	// Until stale is cleared the pod informer sees no pods at all, like a watch that
+	// has fallen far behind.
+	var stale atomic.Bool
+	stale.Store(true)
+	client.PrependReactor("list", "pods", func(fake.Action) (bool, interface{}, error) {
+		if stale.Load() {
+			return true, []api.Pod{}, nil
+		}
+		return false, nil, nil
+	})
+	var creates atomic.Int32
+	client.PrependReactor("create", "pods", func(fake.Action) (bool, interface{}, error) {
+		creates.Add(1)
+		return false, nil, nil
+	})
+	recorder := record.NewRecorder(client, api.EventSource{Component: "deployment-controller"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	waitFor(t, "three pods to be created", func() bool { return creates.Load() == 3 })
+	// Every change to the deployment syncs it again, while its pods are still missing
+	// from the cache.
+	for i := 0; i < 3; i++ {
+		d, err := client.GetDeployment("default", "web")
+		if err != nil {
+			t.Fatalf("GetDeployment: %v", err)
+		}
+		d.Annotations = map[string]string{"touch": string(rune('a' + i))}
+		if err := client.UpdateDeployment(d); err != nil {
+			t.Fatalf("UpdateDeployment: %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	if got := creates.Load(); got != 3 {
+		t.Fatalf("expected the controller to wait for its creates to be observed, got %d creates", got)
+	}
+
+	pods, err := client.ListPods("default", "")
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	for _, pod := range pods {
+		if pod.Labels["app"] != "web" || pod.Labels[api.PodTemplateHashLabel] == "" || pod.Image != "nginx:1.25" || len(pod.OwnerReferences) != 1 || !pod.OwnerReferences[0].Controller {
+			t.Errorf("pod %s: expected the template's labels and image and a controller reference, got %+v", pod.Name, pod)
+		}
+	}
+
+	stale.Store(false)
+	d, err := client.GetDeployment("default", "web")
+	if err != nil {
+		t.Fatalf("GetDeployment: %v", err)
+	}
+	d.Replicas = 1
+	if err := client.UpdateDeployment(d); err != nil {
+		t.Fatalf("UpdateDeployment: %v", err)
+	}
+	waitFor(t, "the deployment to scale down to one pod", func() bool {
+		pods, err := client.ListPods("default", "")
+		active := 0
+		for _, pod := range pods {
+			if pod.DeletionTimestamp == nil && !api.IsPodGone(&pod) {
+				active++
+			}
+		}
+		return err == nil && active == 1
+	})
+	time.Sleep(50 * time.Millisecond)
+	if got := creates.Load(); got != 3 {
+		t.Errorf("expected no creates while scaling down, got %d in all", got)
+	}
+}
+
+func TestControllerRollsOutNewTemplate(t *testing.T) {
+	client := fake.NewClient(&api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"},
+		Replicas:   2,
+		Selector:   map[string]string{"app": "web"},
+		Template:   api.PodTemplate{Labels: map[string]string{"app": "web"}, Image: "nginx:1.25"},
+	})
+	recorder := record.NewRecorder(client, api.EventSource{Component: "deployment-controller"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	// activeByImage counts the pods that are neither finished nor being deleted, by
+	// image, and how many of them are Running.
+	activeByImage := func() (map[string]int, int) {
+		pods, err := client.ListPods("default", "")
+		if err != nil {
+			t.Fatalf("ListPods: %v", err)
+		}
+		images := map[string]int{}
+		running := 0
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil || api.IsPodGone(&pod) {
+				continue
+			}
+			images[pod.Image]++
+			if pod.Phase == api.PodRunning {
+				running++
+			}
+		}
+		return images, running
+	}
+	waitFor(t, "two pods to run", func() bool {
+		runPods(t, client)
+		_, running := activeByImage()
+		return running == 2
+	})
+
+	d, err := client.GetDeployment("default", "web")
+	if err != nil {
+		t.Fatalf("GetDeployment: %v", err)
+	}
+	oldHash := PodTemplateHash(&d.Template)
+	d.Template.Image = "nginx:1.26"
+	if err := client.UpdateDeployment(d); err != nil {
+		t.Fatalf("UpdateDeployment: %v", err)
+	}
+	if PodTemplateHash(&d.Template) == oldHash {
+		t.Fatal("expected a new template to hash differently")
+	}
+
+	waitFor(t, "the rollout to replace both pods", func() bool {
+		images, running := activeByImage()
+		if running < 2 {
+			t.Fatalf("expected two pods to stay Running throughout the rollout, got %d (%v)", running, images)
+		}
+		if total := images["nginx:1.25"] + images["nginx:1.26"]; total > 2+maxSurge {
+			t.Fatalf("expected at most %d pods during the rollout, got %v", 2+maxSurge, images)
+		}
+		runPods(t, client)
+		return images["nginx:1.25"] == 0 && images["nginx:1.26"] == 2 && running == 2
+	})
+}
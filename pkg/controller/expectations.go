@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+// ExpectationsTimeout is how long Expectations waits to see a controller's creates and
+// deletes before it gives up on them, in case a watch event was lost for good.
+const ExpectationsTimeout = 5 * time.Minute
+
+// Expectations tracks the creates and deletes a controller has issued but not yet seen
+// in its informer's cache, per controller key.
+//
+// A controller that counts the objects in its cache to decide how many to create sees
+// its own creates only once the informer catches up. Until then every sync would create
+// the missing objects again. Instead, the controller records what it is about to do
+// with ExpectCreations or ExpectDeletions. Its event handlers report each object that
+// turns up with CreationObserved or DeletionObserved. It skips syncs while Satisfied
+// reports false.
+//
+// Deletions are tracked by UID, so an object that is deleted twice, or a delete
+// observed both as a deletionTimestamp and as the object going away, is only counted
+// once.
+type Expectations struct {
+	clock clock.Clock
+
+	mu    sync.Mutex
+	byKey map[string]*expectation
+}
+
+// expectation is what a controller still waits to see for one key.
+type expectation struct {
+	creates int             // Creates not yet observed
+	deletes map[string]bool // UIDs of deletes not yet observed
+	set     time.Time       // When the expectation was recorded
+}
+
+// NewExpectations returns an empty Expectations whose timeout is measured by clk.
+func NewExpectations(clk clock.Clock) *Expectations {
+	return &Expectations{clock: clk, byKey: make(map[string]*expectation)}
+}
+
+// ExpectCreations records that the controller for key is about to create n objects,
+// replacing whatever it expected before.
+func (e *Expectations) ExpectCreations(key string, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.byKey[key] = &expectation{creates: n, set: e.clock.Now()}
+}
+
+// ExpectDeletions records that the controller for key is about to delete the objects
+// with uids, replacing whatever it expected before.
+func (e *Expectations) ExpectDeletions(key string, uids []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	deletes := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		deletes[uid] = true
+	}
+	e.byKey[key] = &expectation{deletes: deletes, set: e.clock.Now()}
+}
+
+// CreationObserved records that one of key's creates turned up, or failed and won't.
+func (e *Expectations) CreationObserved(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if exp, ok := e.byKey[key]; ok && exp.creates > 0 {
+		exp.creates--
+	}
+}
+
+// DeletionObserved records that the object with uid, which key's controller may have
+// deleted, is gone or going, or that deleting it failed.
+func (e *Expectations) DeletionObserved(key, uid string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if exp, ok := e.byKey[key]; ok {
+		delete(exp.deletes, uid)
+	}
+}
+
+// Satisfied reports whether the controller for key may sync: it expects nothing, has
+// observed everything it expected, or has waited ExpectationsTimeout for it.
+func (e *Expectations) Satisfied(key string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	exp, ok := e.byKey[key]
+	if !ok {
+		return true
+	}
+	if exp.creates <= 0 && len(exp.deletes) == 0 {
+		return true
+	}
+	return e.clock.Since(exp.set) >= ExpectationsTimeout
+}
+
+// Delete forgets key's expectations, once the object behind key is gone.
+func (e *Expectations) Delete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.byKey, key)
+}
@@ -0,0 +1,302 @@
+// Package garbagecollector deletes objects whose owners are gone and finishes
+// foreground deletions.
+//
+// Dependents name their owners in metadata.ownerReferences. The API server applies the
+// propagation policy of a DELETE request:
+//
+//   - Background (the default) deletes the owner straight away; the collector then
+//     deletes every dependent whose owners no longer exist.
+//   - Foreground marks an owner that still has dependents with a deletion timestamp and
+//     the foregroundDeletion finalizer; the collector deletes the dependents, waits for
+//     them to disappear, and then deletes the owner.
+//   - Orphan strips the owner's references from its dependents, so the collector leaves
+//     them alone.
+package garbagecollector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+)
+
+const controllerName = "garbage-collector"
+
+// object is the collector's view of any API object: its kind and metadata.
+type object struct {
+	kind string
+	meta api.ObjectMeta
+}
+
+// keyFunc returns "Kind/namespace/name", so objects of different kinds never collide.
+func keyFunc(obj interface{}) (string, error) {
+	o, ok := obj.(*object)
+	if !ok {
+		return "", fmt.Errorf("cannot compute key for object of type %T", obj)
+	}
+	return o.kind + "/" + o.meta.Namespace + "/" + o.meta.Name, nil
+}
+
+// Controller deletes dependents of deleted owners.
+type Controller struct {
+	client   api.Interface
+	interval time.Duration
+
+	objects *controller.PollingInformer
+	ctrl    *controller.Controller
+}
+
+// NewController creates a garbage collector that polls the API server at informers'
+// interval and on its clock. Owners and dependents come in every kind, so it polls
+// them through an informer of its own rather than a shared one. opts are passed on to
+// controller.New.
+func NewController(client api.Interface, informers *informer.SharedInformerFactory, opts ...controller.Option) *Controller {
+	c := &Controller{client: client, interval: informers.Interval()}
+	c.objects = controller.NewPollingInformer(c.listObjects, keyFunc, c.interval)
+	opts = append([]controller.Option{controller.WithName(controllerName), controller.WithKeyFunc(keyFunc), controller.WithClock(informers.Clock())}, opts...)
+	c.ctrl = controller.New(c.objects, controller.NewWorkQueue(), c.reconcile, opts...)
+
+	// A dependent doesn't change when its owner is deleted, so the owner's deletion has
+	// to enqueue its dependents.
+	c.objects.AddEventHandler(controller.EventHandler{
+		OnDelete: func(obj interface{}) {
+			owner, ok := obj.(*object)
+			if !ok {
+				return
+			}
+			for _, dep := range c.dependents(owner.meta.UID) {
+				key, _ := keyFunc(dep)
+				c.ctrl.Queue().Add(key)
+			}
+		},
+	})
+	return c
+}
+
+// Run runs the controller with the given number of workers until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	c.ctrl.Run(ctx, workers)
+}
+
+// listObjects lists every object that can be an owner or a dependent.
+func (c *Controller) listObjects() ([]interface{}, error) {
+	var objs []interface{}
+	namespaces := map[string]bool{"default": true}
+
+	pods, err := c.client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods {
+		namespaces[pods[i].Namespace] = true
+		if !api.IsPodGone(&pods[i]) {
+			objs = append(objs, &object{kind: "Pod", meta: pods[i].ObjectMeta})
+		}
+	}
+	nodes, err := c.client.ListNodes("")
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		objs = append(objs, &object{kind: "Node", meta: node.ObjectMeta})
+	}
+	nsList, err := c.client.ListNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range nsList {
+		namespaces[ns.Name] = true
+		objs = append(objs, &object{kind: "Namespace", meta: ns.ObjectMeta})
+	}
+
+	for ns := range namespaces {
+		deployments, err := c.client.ListDeployments(ns)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deployments {
+			objs = append(objs, &object{kind: "Deployment", meta: d.ObjectMeta})
+		}
+		services, err := c.client.ListServices(ns)
+		if err != nil {
+			return nil, err
+		}
+		for _, svc := range services {
+			objs = append(objs, &object{kind: "Service", meta: svc.ObjectMeta})
+		}
+		pdbs, err := c.client.ListPodDisruptionBudgets(ns)
+		if err != nil {
+			return nil, err
+		}
+		for _, pdb := range pdbs {
+			objs = append(objs, &object{kind: "PodDisruptionBudget", meta: pdb.ObjectMeta})
+		}
+	}
+	return objs, nil
+}
+
+// dependents returns the cached objects that list uid as an owner.
+func (c *Controller) dependents(uid string) []*object {
+	if uid == "" {
+		return nil
+	}
+	var deps []*object
+	for _, obj := range c.objects.List() {
+		o := obj.(*object)
+		for _, ref := range o.meta.OwnerReferences {
+			if ref.UID == uid {
+				deps = append(deps, o)
+				break
+			}
+		}
+	}
+	return deps
+}
+
+// reconcile handles a single object, identified by its Kind/namespace/name key.
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	cached, exists := c.objects.GetByKey(key)
+	if !exists {
+		return nil
+	}
+	obj := cached.(*object)
+
+	if obj.meta.DeletionTimestamp != nil {
+		if hasFinalizer(obj.meta, api.FinalizerForegroundDeletion) {
+			return c.finishForegroundDeletion(key, obj)
+		}
+		return nil
+	}
+	if len(obj.meta.OwnerReferences) == 0 {
+		return nil
+	}
+	for _, ref := range obj.meta.OwnerReferences {
+		exists, err := c.ownerExists(obj.meta.Namespace, ref)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+	}
+	log.Printf("[%s] Deleting %s: all of its owners are gone", controllerName, key)
+	return c.deleteObject(obj)
+}
+
+// finishForegroundDeletion deletes the dependents of an owner being deleted in the
+// foreground, and the owner itself once none are left.
+func (c *Controller) finishForegroundDeletion(key string, owner *object) error {
+	deps := c.dependents(owner.meta.UID)
+	if len(deps) == 0 {
+		log.Printf("[%s] Deleting %s: its dependents are gone", controllerName, key)
+		return c.deleteObject(owner)
+	}
+	for _, dep := range deps {
+		if dep.meta.DeletionTimestamp != nil {
+			continue
+		}
+		depKey, _ := keyFunc(dep)
+		log.Printf("[%s] Deleting %s: its owner %s is being deleted in the foreground", controllerName, depKey, key)
+		if err := c.deleteObject(dep); err != nil {
+			return err
+		}
+	}
+	// Dependents can take a while to go away (pods wait for their kubelet), so check
+	// back rather than waiting for the next change to the owner.
+	c.ctrl.Queue().AddAfter(key, c.interval)
+	return nil
+}
+
+// ownerExists reports whether the object ref points at still exists. The cache may lag
+// behind an owner that was just created, so a cache miss is confirmed with the API
+// server.
+func (c *Controller) ownerExists(namespace string, ref api.OwnerReference) (bool, error) {
+	for _, obj := range c.objects.List() {
+		if o := obj.(*object); o.meta.UID == ref.UID {
+			return true, nil
+		}
+	}
+
+	var meta *api.ObjectMeta
+	var err error
+	switch ref.Kind {
+	case "Pod":
+		var pod *api.Pod
+		if pod, err = c.client.GetPod(namespace, ref.Name); err == nil && !api.IsPodGone(pod) {
+			meta = &pod.ObjectMeta
+		}
+	case "Node":
+		var node *api.Node
+		if node, err = c.client.GetNode(ref.Name); err == nil {
+			meta = &node.ObjectMeta
+		}
+	case "Namespace":
+		var ns *api.Namespace
+		if ns, err = c.client.GetNamespace(ref.Name); err == nil {
+			meta = &ns.ObjectMeta
+		}
+	case "Deployment":
+		var d *api.Deployment
+		if d, err = c.client.GetDeployment(namespace, ref.Name); err == nil {
+			meta = &d.ObjectMeta
+		}
+	case "Service":
+		var svc *api.Service
+		if svc, err = c.client.GetService(namespace, ref.Name); err == nil {
+			meta = &svc.ObjectMeta
+		}
+	case "PodDisruptionBudget":
+		var pdb *api.PodDisruptionBudget
+		if pdb, err = c.client.GetPodDisruptionBudget(namespace, ref.Name); err == nil {
+			meta = &pdb.ObjectMeta
+		}
+	default:
+		// An owner of a kind we can't look up is assumed to exist; deleting its
+		// dependents on a guess would be worse than leaking them.
+		return true, nil
+	}
+	if err != nil && !api.IsNotFound(err) {
+		return false, fmt.Errorf("checking owner %s %s: %w", ref.Kind, ref.Name, err)
+	}
+	return meta != nil && meta.UID == ref.UID, nil
+}
+
+// deleteObject deletes obj with the default (background) propagation policy. An
+// object that is already gone counts as deleted.
+func (c *Controller) deleteObject(obj *object) error {
+	ns, name := obj.meta.Namespace, obj.meta.Name
+	var err error
+	switch obj.kind {
+	case "Pod":
+		err = c.client.DeletePod(ns, name)
+	case "Node":
+		err = c.client.DeleteNode(name)
+	case "Namespace":
+		err = c.client.DeleteNamespace(name)
+	case "Deployment":
+		err = c.client.DeleteDeployment(ns, name)
+	case "Service":
+		err = c.client.DeleteService(ns, name)
+	case "PodDisruptionBudget":
+		err = c.client.DeletePodDisruptionBudget(ns, name)
+	default:
+		return fmt.Errorf("cannot delete object of kind %s", obj.kind)
+	}
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("deleting %s %s/%s: %w", obj.kind, ns, name, err)
+	}
+	return nil
+}
+
+func hasFinalizer(meta api.ObjectMeta, finalizer string) bool {
+	for _, f := range meta.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package garbagecollector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+)
+
+func ownedBy(d *api.Deployment) []api.OwnerReference {
+	return []api.OwnerReference{{Kind: "Deployment", Name: d.Name, UID: d.UID, Controller: true}}
+}
+
+// waitFor polls cond until it holds or a deadline passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func deploymentGone(client api.Interface, name string) func() bool {
+	return func() bool {
+		_, err := client.GetDeployment("default", name)
+		return err != nil && strings.Contains(err.Error(), "not found")
+	}
+}
+
+func podDeleting(client api.Interface, name string) func() bool {
+	return func() bool {
+		pod, err := client.GetPod("default", name)
+		return err == nil && pod.DeletionTimestamp != nil
+	}
+}
+
+func TestDeletesDependentsOfMissingOwners(t *testing.T) {
+	client := fake.NewClient(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "standalone"}})
+	live, err := client.CreateDeployment("default", &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "live"}})
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	deleted, err := client.CreateDeployment("default", &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "deleted"}})
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	for name, refs := range map[string][]api.OwnerReference{
+		"kept":     ownedBy(live),
+		"orphaned": ownedBy(deleted),
+		"shared":   append(ownedBy(live), ownedBy(deleted)...),
+	} {
+		if _, err := client.CreatePod("default", &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, OwnerReferences: refs}}); err != nil {
+			t.Fatalf("CreatePod %s: %v", name, err)
+		}
+	}
+	if err := client.DeleteDeployment("default", "deleted"); err != nil {
+		t.Fatalf("DeleteDeployment: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	waitFor(t, "orphaned pod to be deleted", podDeleting(client, "orphaned"))
+	// Give the collector a few more polls to make any wrong deletions.
+	time.Sleep(50 * time.Millisecond)
+	for _, name := range []string{"kept", "shared", "standalone"} {
+		if podDeleting(client, name)() {
+			t.Errorf("pod %s was deleted, but it has a live owner or none at all", name)
+		}
+	}
+}
+
+func TestForegroundDeletion(t *testing.T) {
+	client := fake.NewClient()
+	owner, err := client.CreateDeployment("default", &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web"}})
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	if _, err := client.CreatePod("default", &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web-1", OwnerReferences: ownedBy(owner)}}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if _, err := client.CreateService("default", &api.Service{ObjectMeta: api.ObjectMeta{Name: "web", OwnerReferences: ownedBy(owner)}}); err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	// This is how the API server leaves an owner deleted with propagationPolicy=Foreground.
+	now := time.Now()
+	owner.DeletionTimestamp = &now
+	owner.Finalizers = []string{api.FinalizerForegroundDeletion}
+	if err := client.UpdateDeployment(owner); err != nil {
+		t.Fatalf("UpdateDeployment: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	waitFor(t, "owner to be deleted", deploymentGone(client, "web"))
+	if !podDeleting(client, "web-1")() {
+		t.Errorf("owner was deleted before its pod")
+	}
+	if _, err := client.GetService("default", "web"); err == nil {
+		t.Errorf("owner was deleted before its service")
+	}
+}
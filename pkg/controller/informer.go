@@ -0,0 +1,433 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+// EventHandler receives notifications about changes seen by an Informer.
+// Any of the functions may be nil.
+type EventHandler struct {
+	OnAdd    func(obj interface{})
+	OnUpdate func(oldObj, newObj interface{})
+	OnDelete func(obj interface{})
+}
+
+// Informer watches a set of API objects and notifies registered handlers of changes.
+type Informer interface {
+	// AddEventHandler registers a handler. Handlers added after the informer has
+	// synced receive an OnAdd for every object already in the cache.
+	AddEventHandler(handler EventHandler)
+	// Run keeps the cache up to date until ctx is cancelled.
+	Run(ctx context.Context)
+	// HasSynced reports whether the initial list has been delivered to handlers.
+	HasSynced() bool
+	// GetByKey returns the cached object for key, if any.
+	GetByKey(key string) (interface{}, bool)
+	// List returns all cached objects.
+	List() []interface{}
+}
+
+// KeyFunc returns the queue key of an API object.
+type KeyFunc func(obj interface{}) (string, error)
+
+// ListFunc fetches the current state of every object an informer tracks.
+type ListFunc func() ([]interface{}, error)
+
+// WatchFunc streams the changes to the objects an informer tracks to fn, starting from
+// the current state, like api.Client.Watch without a resource version: an ADDED for
+// every object, then a BOOKMARK. Deleted objects are the last state seen, and fn gets
+// a nil object with a BOOKMARK.
+type WatchFunc func(ctx context.Context, fn func(t api.WatchEventType, obj interface{}) error) error
+
+// NewWatchFunc returns a WatchFunc over the objects of resource, such as "pods", in
+// namespace, decoding them into new values of T; or nil if client can't watch.
+func NewWatchFunc[T any](client api.Interface, resource, namespace string) WatchFunc {
+	watcher, ok := client.(api.Watcher)
+	if !ok {
+		return nil
+	}
+	return func(ctx context.Context, fn func(t api.WatchEventType, obj interface{}) error) error {
+		return watcher.Watch(ctx, resource, namespace, "", func(event api.WatchEvent) error {
+			if event.Type == api.WatchBookmark {
+				return fn(event.Type, nil)
+			}
+			obj := new(T)
+			if err := json.Unmarshal(event.Object, obj); err != nil {
+				return fmt.Errorf("decoding %s: %w", resource, err)
+			}
+			return fn(event.Type, obj)
+		})
+	}
+}
+
+// MetaNamespaceKeyFunc returns "namespace/name" for namespaced objects and "name" for
+// cluster-scoped ones.
+func MetaNamespaceKeyFunc(obj interface{}) (string, error) {
+	switch o := obj.(type) {
+	case *api.Pod:
+		return o.Namespace + "/" + o.Name, nil
+	case api.Pod:
+		return o.Namespace + "/" + o.Name, nil
+	case *api.Node:
+		return o.Name, nil
+	case api.Node:
+		return o.Name, nil
+	case *api.Namespace:
+		return o.Name, nil
+	case *api.Deployment:
+		return o.Namespace + "/" + o.Name, nil
+	case *api.PersistentVolume:
+		return o.Name, nil
+	case *api.PersistentVolumeClaim:
+		return o.Namespace + "/" + o.Name, nil
+	default:
+		return "", fmt.Errorf("cannot compute key for object of type %T", obj)
+	}
+}
+
+// SplitMetaNamespaceKey splits a key produced by MetaNamespaceKeyFunc into its parts.
+// Cluster-scoped keys return an empty namespace.
+func SplitMetaNamespaceKey(key string) (namespace, name string) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '/' {
+			return key[:i], key[i+1:]
+		}
+	}
+	return "", key
+}
+
+// PollingInformer is an Informer that relists objects on a fixed interval and
+// diffs the result against its cache to produce add, update, and delete notifications.
+// The constructors for the API's resources give it a watch instead when the client
+// supports one: it then lists once, through the watch's initial state, and follows the
+// changes from there. The client resumes a watch that breaks, so only a watch that
+// expired, or broke while listing, costs another list, after interval.
+type PollingInformer struct {
+	listFunc  ListFunc
+	watchFunc WatchFunc // Nil to poll
+	keyFunc   KeyFunc
+	interval  time.Duration
+	// Clock drives polling; NewPollingInformer sets it to the real clock.
+	Clock clock.Clock
+
+	mu       sync.RWMutex
+	cache    map[string]interface{}
+	handlers []EventHandler
+	synced   bool
+}
+
+// NewPollingInformer creates an informer that calls listFunc every interval.
+func NewPollingInformer(listFunc ListFunc, keyFunc KeyFunc, interval time.Duration) *PollingInformer {
+	return &PollingInformer{
+		listFunc: listFunc,
+		keyFunc:  keyFunc,
+		interval: interval,
+		Clock:    clock.RealClock{},
+		cache:    make(map[string]interface{}),
+	}
+}
+
+// NewPodInformer creates a PollingInformer over all pods in namespace.
+func NewPodInformer(client api.Interface, namespace string, interval time.Duration) *PollingInformer {
+	informer := NewPollingInformer(func() ([]interface{}, error) {
+		pods, err := client.ListPods(namespace, "")
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]interface{}, 0, len(pods))
+		for i := range pods {
+			objs = append(objs, &pods[i])
+		}
+		return objs, nil
+	}, MetaNamespaceKeyFunc, interval)
+	informer.watchFunc = NewWatchFunc[api.Pod](client, "pods", namespace)
+	return informer
+}
+
+// NewNodeInformer creates a PollingInformer over all nodes.
+func NewNodeInformer(client api.Interface, interval time.Duration) *PollingInformer {
+	informer := NewPollingInformer(func() ([]interface{}, error) {
+		nodes, err := client.ListNodes("")
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]interface{}, 0, len(nodes))
+		for i := range nodes {
+			objs = append(objs, &nodes[i])
+		}
+		return objs, nil
+	}, MetaNamespaceKeyFunc, interval)
+	informer.watchFunc = NewWatchFunc[api.Node](client, "nodes", api.NamespaceAll)
+	return informer
+}
+
+// NewNamespaceInformer creates a PollingInformer over all namespaces.
+func NewNamespaceInformer(client api.Interface, interval time.Duration) *PollingInformer {
+	informer := NewPollingInformer(func() ([]interface{}, error) {
+		namespaces, err := client.ListNamespaces()
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]interface{}, 0, len(namespaces))
+		for i := range namespaces {
+			objs = append(objs, &namespaces[i])
+		}
+		return objs, nil
+	}, MetaNamespaceKeyFunc, interval)
+	informer.watchFunc = NewWatchFunc[api.Namespace](client, "namespaces", api.NamespaceAll)
+	return informer
+}
+
+// NewDeploymentInformer creates a PollingInformer over all deployments in namespace.
+func NewDeploymentInformer(client api.Interface, namespace string, interval time.Duration) *PollingInformer {
+	informer := NewPollingInformer(func() ([]interface{}, error) {
+		deployments, err := client.ListDeployments(namespace)
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]interface{}, 0, len(deployments))
+		for i := range deployments {
+			objs = append(objs, &deployments[i])
+		}
+		return objs, nil
+	}, MetaNamespaceKeyFunc, interval)
+	informer.watchFunc = NewWatchFunc[api.Deployment](client, "deployments", namespace)
+	return informer
+}
+
+// NewPersistentVolumeInformer creates a PollingInformer over all persistent volumes.
+func NewPersistentVolumeInformer(client api.Interface, interval time.Duration) *PollingInformer {
+	informer := NewPollingInformer(func() ([]interface{}, error) {
+		pvs, err := client.ListPersistentVolumes()
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]interface{}, 0, len(pvs))
+		for i := range pvs {
+			objs = append(objs, &pvs[i])
+		}
+		return objs, nil
+	}, MetaNamespaceKeyFunc, interval)
+	informer.watchFunc = NewWatchFunc[api.PersistentVolume](client, "persistentvolumes", api.NamespaceAll)
+	return informer
+}
+
+// NewPersistentVolumeClaimInformer creates a PollingInformer over all persistent volume
+// claims in namespace.
+func NewPersistentVolumeClaimInformer(client api.Interface, namespace string, interval time.Duration) *PollingInformer {
+	informer := NewPollingInformer(func() ([]interface{}, error) {
+		pvcs, err := client.ListPersistentVolumeClaims(namespace)
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]interface{}, 0, len(pvcs))
+		for i := range pvcs {
+			objs = append(objs, &pvcs[i])
+		}
+		return objs, nil
+	}, MetaNamespaceKeyFunc, interval)
+	informer.watchFunc = NewWatchFunc[api.PersistentVolumeClaim](client, "persistentvolumeclaims", namespace)
+	return informer
+}
+
+// AddEventHandler registers handler with the informer.
+func (i *PollingInformer) AddEventHandler(handler EventHandler) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.handlers = append(i.handlers, handler)
+	if i.synced && handler.OnAdd != nil {
+		for _, obj := range i.cache {
+			handler.OnAdd(obj)
+		}
+	}
+}
+
+// Run relists, or watches, until ctx is cancelled.
+func (i *PollingInformer) Run(ctx context.Context) {
+	if i.watchFunc != nil {
+		i.runWatch(ctx)
+		return
+	}
+	ticker := i.Clock.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		i.resync()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// HasSynced reports whether the first list has completed.
+func (i *PollingInformer) HasSynced() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.synced
+}
+
+// GetByKey returns the cached object for key.
+func (i *PollingInformer) GetByKey(key string) (interface{}, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	obj, ok := i.cache[key]
+	return obj, ok
+}
+
+// List returns all cached objects.
+func (i *PollingInformer) List() []interface{} {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	objs := make([]interface{}, 0, len(i.cache))
+	for _, obj := range i.cache {
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// resync lists all objects once and notifies handlers of the differences.
+func (i *PollingInformer) resync() {
+	objs, err := i.listFunc()
+	if err != nil {
+		log.Printf("Informer: error listing objects: %v", err)
+		return
+	}
+	i.replace(objs)
+}
+
+// runWatch keeps the cache up to date from watches until ctx is cancelled, starting a
+// new one, after i.interval, whenever one fails.
+func (i *PollingInformer) runWatch(ctx context.Context) {
+	for {
+		var initial []interface{}
+		listed := false
+		err := i.watchFunc(ctx, func(t api.WatchEventType, obj interface{}) error {
+			switch {
+			case t == api.WatchBookmark:
+				if !listed {
+					i.replace(initial)
+					listed = true
+				}
+			case !listed:
+				initial = append(initial, obj)
+			default:
+				i.apply(t, obj)
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Informer: watch failed, listing again: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.Clock.After(i.interval):
+		}
+	}
+}
+
+// replace makes objs the cache and notifies handlers of the differences.
+func (i *PollingInformer) replace(objs []interface{}) {
+	next := make(map[string]interface{}, len(objs))
+	for _, obj := range objs {
+		key, err := i.keyFunc(obj)
+		if err != nil {
+			log.Printf("Informer: %v", err)
+			continue
+		}
+		next[key] = obj
+	}
+
+	i.mu.Lock()
+	var notifications []func(h EventHandler)
+	for key, obj := range next {
+		notifications = append(notifications, i.diff(key, obj)...)
+	}
+	for key, old := range i.cache {
+		if _, exists := next[key]; !exists {
+			notifications = append(notifications, deleted(old))
+		}
+	}
+	i.cache = next
+	handlers := append([]EventHandler(nil), i.handlers...)
+	i.mu.Unlock()
+	notify(notifications, handlers)
+
+	i.mu.Lock()
+	i.synced = true
+	i.mu.Unlock()
+}
+
+// apply updates the cache with one watch event and notifies handlers.
+func (i *PollingInformer) apply(t api.WatchEventType, obj interface{}) {
+	key, err := i.keyFunc(obj)
+	if err != nil {
+		log.Printf("Informer: %v", err)
+		return
+	}
+	i.mu.Lock()
+	var notifications []func(h EventHandler)
+	if t == api.WatchDeleted {
+		if old, exists := i.cache[key]; exists {
+			notifications = append(notifications, deleted(old))
+			delete(i.cache, key)
+		}
+	} else {
+		notifications = i.diff(key, obj)
+		i.cache[key] = obj
+	}
+	handlers := append([]EventHandler(nil), i.handlers...)
+	i.mu.Unlock()
+	notify(notifications, handlers)
+}
+
+// diff returns the notification for obj replacing the cached object under key, if
+// it changed anything. The caller must hold i.mu.
+func (i *PollingInformer) diff(key string, obj interface{}) []func(h EventHandler) {
+	old, exists := i.cache[key]
+	switch {
+	case !exists:
+		return []func(h EventHandler){func(h EventHandler) {
+			if h.OnAdd != nil {
+				h.OnAdd(obj)
+			}
+		}}
+	case !reflect.DeepEqual(old, obj):
+		return []func(h EventHandler){func(h EventHandler) {
+			if h.OnUpdate != nil {
+				h.OnUpdate(old, obj)
+			}
+		}}
+	}
+	return nil
+}
+
+func deleted(old interface{}) func(h EventHandler) {
+	return func(h EventHandler) {
+		if h.OnDelete != nil {
+			h.OnDelete(old)
+		}
+	}
+}
+
+// notify calls handlers without holding the lock, so they may read from the cache.
+func notify(notifications []func(h EventHandler), handlers []EventHandler) {
+	for _, fn := range notifications {
+		for _, h := range handlers {
+			fn(h)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+// LeaderCallbacks are invoked as an elector gains and loses leadership.
+type LeaderCallbacks struct {
+	// OnStartedLeading runs while leadership is held. Its context is cancelled as
+	// soon as leadership is lost.
+	OnStartedLeading func(ctx context.Context)
+	// OnStoppedLeading runs after leadership is lost or ctx is cancelled.
+	OnStoppedLeading func()
+}
+
+// LeaderElector decides which of several replicas of a controller is active.
+type LeaderElector interface {
+	// Run blocks until ctx is cancelled, invoking callbacks each time leadership
+	// is acquired or lost.
+	Run(ctx context.Context, callbacks LeaderCallbacks)
+}
+
+// LockFuncs describes a lock shared between replicas. TryAcquireOrRenew attempts to
+// take the lock for identity, or extend it if identity already holds it, and reports
+// whether identity is the holder afterwards.
+type LockFuncs struct {
+	TryAcquireOrRenew func(ctx context.Context, identity string) (bool, error)
+	Release           func(ctx context.Context, identity string) error
+}
+
+// NewLeaseLock returns a lock backed by the Lease namespace/name, which it creates on
+// first use. Taking or renewing the lock sets the holder and renewTime and lasts for
+// duration; another identity can only take it once the holder's renewal has expired.
+// The API server rejects updates made from a stale read, so two identities can't
+// both take the lease. clk dates renewals; nil means the real clock, which callers
+// outside tests should use so that renewTime, like other timestamps on objects, is
+// real time.
+func NewLeaseLock(client api.Interface, namespace, name string, duration time.Duration, clk clock.Clock) LockFuncs {
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	seconds := int(math.Ceil(duration.Seconds()))
+	return LockFuncs{
+		TryAcquireOrRenew: func(ctx context.Context, identity string) (bool, error) {
+			now := clk.Now()
+			lease, err := client.GetLease(namespace, name)
+			if err != nil {
+				if !api.IsNotFound(err) {
+					return false, err
+				}
+				_, err := client.CreateLease(namespace, &api.Lease{
+					ObjectMeta:      api.ObjectMeta{Name: name},
+					HolderIdentity:  identity,
+					DurationSeconds: seconds,
+					RenewTime:       &now,
+				})
+				if api.IsAlreadyExists(err) {
+					return false, nil // Another identity created it first
+				}
+				return err == nil, err
+			}
+			if lease.HolderIdentity != identity && !api.IsLeaseExpired(lease, now) {
+				return false, nil
+			}
+			lease.HolderIdentity = identity
+			lease.DurationSeconds = seconds
+			lease.RenewTime = &now
+			if err := client.UpdateLease(lease); err != nil {
+				if api.IsConflict(err) {
+					return false, nil // Another identity wrote it since we read it
+				}
+				return false, err
+			}
+			return true, nil
+		},
+		Release: func(ctx context.Context, identity string) error {
+			lease, err := client.GetLease(namespace, name)
+			if err != nil || lease.HolderIdentity != identity {
+				return err
+			}
+			lease.HolderIdentity = ""
+			lease.RenewTime = nil
+			if err := client.UpdateLease(lease); err != nil && !api.IsConflict(err) {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// LeaseElector is a LeaderElector that periodically tries to acquire or renew a
+// shared lock. Leadership is considered lost if it cannot be renewed within
+// LeaseDuration.
+type LeaseElector struct {
+	Identity      string
+	Lock          LockFuncs
+	LeaseDuration time.Duration
+	RetryPeriod   time.Duration
+	// Clock times retries and lease expiry; nil means the real clock.
+	Clock clock.Clock
+}
+
+// Run implements LeaderElector.
+func (e *LeaseElector) Run(ctx context.Context, callbacks LeaderCallbacks) {
+	retry := e.RetryPeriod
+	if retry <= 0 {
+		retry = 2 * time.Second
+	}
+	if e.Clock == nil {
+		e.Clock = clock.RealClock{}
+	}
+
+	for {
+		if !e.acquire(ctx, retry) {
+			return
+		}
+
+		leaderCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			callbacks.OnStartedLeading(leaderCtx)
+		}()
+
+		e.renew(leaderCtx, retry)
+		cancel()
+		<-done
+
+		if e.Lock.Release != nil {
+			// Use a fresh context: ctx may already be cancelled during shutdown.
+			releaseCtx, releaseCancel := context.WithTimeout(context.Background(), retry)
+			if err := e.Lock.Release(releaseCtx, e.Identity); err != nil {
+				log.Printf("Leader election: failed to release lock for %s: %v", e.Identity, err)
+			}
+			releaseCancel()
+		}
+		if callbacks.OnStoppedLeading != nil {
+			callbacks.OnStoppedLeading()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// acquire blocks until the lock is held or ctx is cancelled.
+func (e *LeaseElector) acquire(ctx context.Context, retry time.Duration) bool {
+	for {
+		ok, err := e.Lock.TryAcquireOrRenew(ctx, e.Identity)
+		if err != nil {
+			log.Printf("Leader election: error acquiring lock for %s: %v", e.Identity, err)
+		}
+		if ok {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-e.Clock.After(retry):
+		}
+	}
+}
+
+// renew keeps the lock held until ctx is cancelled or renewal fails for longer
+// than LeaseDuration.
+func (e *LeaseElector) renew(ctx context.Context, retry time.Duration) {
+	lastRenew := e.Clock.Now()
+	ticker := e.Clock.NewTicker(retry)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		ok, err := e.Lock.TryAcquireOrRenew(ctx, e.Identity)
+		if err != nil {
+			log.Printf("Leader election: error renewing lock for %s: %v", e.Identity, err)
+		}
+		if ok {
+			lastRenew = e.Clock.Now()
+			continue
+		}
+		if err == nil || e.Clock.Since(lastRenew) > e.LeaseDuration {
+			return
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package namespace empties namespaces that are being deleted.
+//
+// Deleting a namespace only marks it Terminating; the API server then refuses to
+// create anything in it. For each terminating namespace the controller deletes every
+// object left in it, deployments first so that they stop replacing the pods deleted
+// after them, and then deletes the namespace again. The API server removes it once
+// nothing is left but pods that are gone; until the kubelets have finished with the
+// pods, that fails with a conflict and the controller checks back after its interval.
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+)
+
+const controllerName = "namespace"
+
+// Controller deletes the contents of terminating namespaces, then the namespaces.
+type Controller struct {
+	client   api.Interface
+	interval time.Duration
+
+	namespaces controller.Informer
+	ctrl       *controller.Controller
+}
+
+// NewController creates a namespace controller that follows namespaces through
+// informers' shared informer, and checks back on a namespace that isn't empty yet
+// after informers' interval. opts are passed on to controller.New.
+func NewController(client api.Interface, informers *informer.SharedInformerFactory, opts ...controller.Option) *Controller {
+	c := &Controller{
+		client:     client,
+		interval:   informers.Interval(),
+		namespaces: informers.Namespaces(),
+	}
+	opts = append([]controller.Option{controller.WithName(controllerName), controller.WithClock(informers.Clock())}, opts...)
+	c.ctrl = controller.New(c.namespaces, controller.NewWorkQueue(), c.reconcile, opts...)
+	return c
+}
+
+// Run runs the controller with the given number of workers until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	c.ctrl.Run(ctx, workers)
+}
+
+// content is a kind of object a namespace holds. A new namespaced resource needs an
+// entry in contents, and in the API server's check that a namespace is empty.
+type content struct {
+	kind   string
+	list   func(client api.Interface, namespace string) ([]api.ObjectMeta, error)
+	delete func(client api.Interface, namespace, name string) error
+}
+
+// contents is in the order the controller deletes them.
+var contents = []content{
+	{"deployment", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListDeployments(namespace))
+	}, api.Interface.DeleteDeployment},
+	{"pod", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListPods(namespace, ""))
+	}, api.Interface.DeletePod},
+	{"service", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListServices(namespace))
+	}, api.Interface.DeleteService},
+	{"poddisruptionbudget", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListPodDisruptionBudgets(namespace))
+	}, api.Interface.DeletePodDisruptionBudget},
+	{"networkpolicy", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListNetworkPolicies(namespace))
+	}, api.Interface.DeleteNetworkPolicy},
+	{"persistentvolumeclaim", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListPersistentVolumeClaims(namespace))
+	}, api.Interface.DeletePersistentVolumeClaim},
+	{"lease", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListLeases(namespace))
+	}, api.Interface.DeleteLease},
+	{"configmap", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListConfigMaps(namespace))
+	}, api.Interface.DeleteConfigMap},
+	{"secret", func(client api.Interface, namespace string) ([]api.ObjectMeta, error) {
+		return metas(client.ListSecrets(namespace))
+	}, api.Interface.DeleteSecret},
+}
+
+// metas returns the metadata of objs, passing err through.
+func metas[T any, P interface {
+	*T
+	GetObjectMeta() *api.ObjectMeta
+}](objs []T, err error) ([]api.ObjectMeta, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make([]api.ObjectMeta, len(objs))
+	for i := range objs {
+		result[i] = *P(&objs[i]).GetObjectMeta()
+	}
+	return result, nil
+}
+
+// reconcile handles a single namespace, identified by its name.
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	obj, exists := c.namespaces.GetByKey(key)
+	if !exists {
+		return nil
+	}
+	ns := obj.(*api.Namespace)
+	if ns.Phase != api.NamespaceTerminating {
+		return nil
+	}
+
+	for _, content := range contents {
+		objs, err := content.list(c.client, ns.Name)
+		if err != nil {
+			return fmt.Errorf("listing %ss in namespace %s: %w", content.kind, ns.Name, err)
+		}
+		for _, meta := range objs {
+			if meta.DeletionTimestamp != nil {
+				continue // Already on its way out
+			}
+			log.Printf("[%s] Deleting %s %s/%s: its namespace is terminating", controllerName, content.kind, ns.Name, meta.Name)
+			if err := content.delete(c.client, ns.Name, meta.Name); err != nil && !api.IsNotFound(err) {
+				return fmt.Errorf("deleting %s %s/%s: %w", content.kind, ns.Name, meta.Name, err)
+			}
+		}
+	}
+
+	err := c.client.DeleteNamespace(ns.Name)
+	switch {
+	case err == nil:
+		log.Printf("[%s] Namespace %s is empty and has been deleted", controllerName, ns.Name)
+	case api.IsConflict(err):
+		// Pods wait for their kubelets, so check back rather than waiting for the
+		// next change to the namespace, which may never come.
+		log.Printf("[%s] Namespace %s is not empty yet: %v", controllerName, ns.Name, err)
+		c.ctrl.Queue().AddAfter(key, c.interval)
+	case !api.IsNotFound(err):
+		return fmt.Errorf("deleting namespace %s: %w", ns.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package namespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+)
+
+func TestTerminatingNamespaceIsEmptiedThenDeleted(t *testing.T) {
+	client := fake.NewClient(
+		&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "team"}, Phase: api.NamespaceActive},
+		&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "other"}, Phase: api.NamespaceActive},
+		&api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "team"}, Replicas: 1},
+		&api.Service{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "team"}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "pending", Namespace: "team"}, Phase: api.PodPending},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "running", Namespace: "team"}, NodeName: "node1", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "bystander", Namespace: "other"}, Phase: api.PodPending},
+	)
+	if err := client.DeleteNamespace("team"); err != nil {
+		t.Fatalf("DeleteNamespace: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor("the namespace's objects to be deleted", func() bool {
+		running, _ := client.GetPod("team", "running")
+		return running != nil && running.DeletionTimestamp != nil
+	})
+	if _, err := client.GetDeployment("team", "web"); err == nil {
+		t.Errorf("expected the deployment to be deleted")
+	}
+	if _, err := client.GetService("team", "web"); err == nil {
+		t.Errorf("expected the service to be deleted")
+	}
+	// The running pod's kubelet hasn't finished with it, so the namespace stays.
+	time.Sleep(50 * time.Millisecond)
+	if ns, err := client.GetNamespace("team"); err != nil || ns.Phase != api.NamespaceTerminating {
+		t.Fatalf("expected the namespace to stay Terminating while a pod is running, got %+v (%v)", ns, err)
+	}
+
+	running, _ := client.GetPod("team", "running")
+	running.Phase = api.PodDeleted
+	if err := client.UpdatePod(running); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	waitFor("the namespace to be deleted", func() bool {
+		_, err := client.GetNamespace("team")
+		return err != nil
+	})
+	if pod, err := client.GetPod("other", "bystander"); err != nil || pod.DeletionTimestamp != nil {
+		t.Errorf("expected pods in other namespaces to be left alone, got %+v (%v)", pod, err)
+	}
+}
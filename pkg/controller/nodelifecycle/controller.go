@@ -0,0 +1,209 @@
+// Package nodelifecycle watches over nodes whose kubelet has gone quiet, and cleans
+// up pods that are bound to nodes which no longer exist.
+//
+// A kubelet renews its node's Lease in api.NodeLeaseNamespace as a heartbeat. Once
+// the lease expires, nothing is known about the node any more, so the controller marks
+// all of its conditions Unknown, which makes it NotReady for the scheduler. The
+// kubelet reports them again when it comes back.
+//
+// When a node is deleted its kubelet is gone, so nothing will ever advance the pods
+// bound to it. For each such pod the controller:
+//
+//   - marks pods that were already terminating as Deleted, finishing the deletion the
+//     kubelet would have completed;
+//   - reschedules pods that were Scheduled but never started, by clearing NodeName and
+//     returning them to Pending;
+//   - fails pods that were Running (or otherwise started), since their containers died
+//     with the node.
+package nodelifecycle
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+const controllerName = "node-lifecycle"
+
+// nodeMonitorPeriod is how often the controller checks the nodes' leases.
+const nodeMonitorPeriod = 5 * time.Second
+
+// Controller marks nodes whose lease expired Unknown and reconciles pods whose node
+// has been deleted.
+type Controller struct {
+	client   api.Interface
+	recorder record.EventRecorder
+
+	pods  controller.Informer
+	nodes controller.Informer
+	ctrl  *controller.Controller
+	clock clock.Clock
+}
+
+// NewController creates a node lifecycle controller that follows pods and nodes through
+// informers' shared informers. opts are passed on to controller.New.
+func NewController(client api.Interface, recorder record.EventRecorder, informers *informer.SharedInformerFactory, opts ...controller.Option) *Controller {
+	c := &Controller{
+		client:   client,
+		recorder: recorder,
+		pods:     informers.Pods(),
+		nodes:    informers.Nodes(),
+		clock:    informers.Clock(),
+	}
+	opts = append([]controller.Option{controller.WithName(controllerName), controller.WithClock(informers.Clock())}, opts...)
+	c.ctrl = controller.New(c.pods, controller.NewWorkQueue(), c.reconcile, opts...)
+
+	// Pods only change when the kubelet acts, and the kubelet of a deleted node never
+	// will, so node deletions have to enqueue the node's pods themselves.
+	c.nodes.AddEventHandler(controller.EventHandler{
+		OnDelete: func(obj interface{}) {
+			node, ok := obj.(*api.Node)
+			if !ok {
+				return
+			}
+			log.Printf("[%s] Node %s was deleted, checking its pods", controllerName, node.Name)
+			for _, obj := range c.pods.List() {
+				if pod, ok := obj.(*api.Pod); ok && pod.NodeName == node.Name {
+					key, _ := controller.MetaNamespaceKeyFunc(pod)
+					c.ctrl.Queue().Add(key)
+				}
+			}
+		},
+	})
+	return c
+}
+
+// Run runs the controller with the given number of workers until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	go c.nodes.Run(ctx)
+	if !controller.WaitForCacheSync(ctx, c.nodes) {
+		return
+	}
+	go c.monitorNodeHealth(ctx)
+	c.ctrl.Run(ctx, workers)
+}
+
+// monitorNodeHealth checks the nodes' leases every nodeMonitorPeriod until ctx is
+// cancelled.
+func (c *Controller) monitorNodeHealth(ctx context.Context) {
+	ticker := c.clock.NewTicker(nodeMonitorPeriod)
+	defer ticker.Stop()
+	for {
+		if err := c.markUnresponsiveNodes(); err != nil {
+			log.Printf("[%s] Error checking node leases: %v", controllerName, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// markUnresponsiveNodes marks the conditions of every node whose lease has expired
+// Unknown. Nodes without a lease, such as those registered by hand, are left alone.
+func (c *Controller) markUnresponsiveNodes() error {
+	leases, err := c.client.ListLeases(api.NodeLeaseNamespace)
+	if err != nil {
+		return fmt.Errorf("listing node leases: %w", err)
+	}
+	// Renewal times are stored on the lease, so they are real whatever the clock.
+	now := time.Now()
+	for _, lease := range leases {
+		if lease.RenewTime == nil || now.Before(lease.RenewTime.Add(time.Duration(lease.DurationSeconds)*time.Second)) {
+			continue
+		}
+		obj, exists := c.nodes.GetByKey(lease.Name)
+		if !exists {
+			continue
+		}
+		node := obj.(*api.Node).DeepCopy()
+		if cond := api.GetNodeCondition(node, api.NodeReadyCondition); cond != nil && cond.Status == api.ConditionUnknown {
+			continue
+		}
+		if err := c.markUnknown(node, *lease.RenewTime); err != nil {
+			log.Printf("[%s] %v", controllerName, err)
+		}
+	}
+	return nil
+}
+
+// markUnknown sets all of the node's conditions, and a Ready condition if it has none,
+// Unknown, keeping the heartbeat times of when they were last reported.
+func (c *Controller) markUnknown(node *api.Node, lastRenewed time.Time) error {
+	if api.GetNodeCondition(node, api.NodeReadyCondition) == nil {
+		api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionUnknown, LastHeartbeatTime: lastRenewed})
+	}
+	for _, cond := range node.Conditions {
+		cond.Status, cond.Reason, cond.Message = api.ConditionUnknown, "NodeStatusUnknown", "Kubelet stopped posting node status."
+		cond.LastTransitionTime = time.Time{}
+		api.SetNodeCondition(node, cond)
+	}
+	if err := c.client.UpdateNode(node); err != nil {
+		return fmt.Errorf("marking node %s unknown: %w", node.Name, err)
+	}
+	log.Printf("[%s] Node %s: lease expired at %s, marked its conditions Unknown", controllerName, node.Name, lastRenewed.Format(time.RFC3339))
+	c.recorder.Eventf(node, api.EventTypeNormal, "NodeNotReady", "Node %s status is now: NodeNotReady", node.Name)
+	return nil
+}
+
+// reconcile handles a single pod, identified by its namespace/name key.
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	obj, exists := c.pods.GetByKey(key)
+	if !exists {
+		return nil
+	}
+	pod := *obj.(*api.Pod)
+	if pod.NodeName == "" || isFinished(&pod) {
+		return nil
+	}
+	if _, exists := c.nodes.GetByKey(pod.NodeName); exists {
+		return nil
+	}
+	// The node cache may lag behind a node that was just registered; ask the API
+	// server before acting on a pod that may be perfectly healthy.
+	if _, err := c.client.GetNode(pod.NodeName); err == nil {
+		return nil
+	} else if !api.IsNotFound(err) {
+		return fmt.Errorf("checking node %s: %w", pod.NodeName, err)
+	}
+
+	lostNode := pod.NodeName
+	var reason, message string
+	switch {
+	case pod.DeletionTimestamp != nil:
+		pod.Phase = api.PodDeleted
+		reason, message = "NodeLost", fmt.Sprintf("Node %s was deleted; finishing termination of the pod", lostNode)
+	case pod.Phase == api.PodScheduled:
+		pod.Phase = api.PodPending
+		pod.NodeName = ""
+		pod.HostIP = ""
+		reason, message = "Rescheduled", fmt.Sprintf("Node %s was deleted before the pod started; returning it to the scheduler", lostNode)
+	default:
+		pod.Phase = api.PodFailed
+		reason, message = "NodeLost", fmt.Sprintf("Node %s which was running the pod was deleted", lostNode)
+	}
+
+	if err := c.client.UpdatePod(&pod); err != nil {
+		return fmt.Errorf("updating pod %s after losing node %s: %w", key, lostNode, err)
+	}
+	log.Printf("[%s] Pod %s: %s", controllerName, key, message)
+	c.recorder.Event(&pod, api.EventTypeWarning, reason, message)
+	return nil
+}
+
+// isFinished reports whether the pod has reached a phase nothing will move it out of.
+func isFinished(pod *api.Pod) bool {
+	switch pod.Phase {
+	case api.PodSucceeded, api.PodFailed, api.PodDeleted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package nodelifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+func TestPodsOnDeletedNode(t *testing.T) {
+	terminatingSince := time.Now()
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "alive"}, Status: api.NodeReady},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "running"}, NodeName: "gone", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "scheduled"}, NodeName: "gone", Phase: api.PodScheduled},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "terminating", DeletionTimestamp: &terminatingSince}, NodeName: "gone", Phase: api.PodRunning},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "done"}, NodeName: "gone", Phase: api.PodSucceeded},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "healthy"}, NodeName: "alive", Phase: api.PodRunning},
+	)
+	recorder := record.NewRecorder(client, api.EventSource{Component: controllerName})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	want := map[string]struct {
+		phase    api.PodPhase
+		nodeName string
+	}{
+		"running":     {api.PodFailed, "gone"},
+		"scheduled":   {api.PodPending, ""},
+		"terminating": {api.PodDeleted, "gone"},
+		"done":        {api.PodSucceeded, "gone"},
+		"healthy":     {api.PodRunning, "alive"},
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		pods, err := client.ListPods("default", "")
+		if err != nil {
+			t.Fatalf("ListPods: %v", err)
+		}
+		var mismatches []string
+		for _, pod := range pods {
+			if w := want[pod.Name]; pod.Phase != w.phase || pod.NodeName != w.nodeName {
+				mismatches = append(mismatches, pod.Name+"="+string(pod.Phase)+"@"+pod.NodeName)
+			}
+		}
+		if len(mismatches) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pods did not reach the expected state: %v", mismatches)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	events, err := client.ListEvents("default")
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("expected one event per cleaned-up pod, got %d: %+v", len(events), events)
+	}
+}
+
+func TestNodeWithExpiredLease(t *testing.T) {
+	ready := func(name string) *api.Node {
+		node := &api.Node{ObjectMeta: api.ObjectMeta{Name: name}}
+		api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionTrue, Reason: "KubeletReady"})
+		api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeMemoryPressure, Status: api.ConditionFalse})
+		return node
+	}
+	client := fake.NewClient(ready("silent"), ready("alive"), ready("manual"))
+	expired, renewed := time.Now().Add(-time.Hour), time.Now()
+	for name, renewTime := range map[string]*time.Time{"silent": &expired, "alive": &renewed} {
+		lease := &api.Lease{ObjectMeta: api.ObjectMeta{Name: name}, HolderIdentity: name, DurationSeconds: 40, RenewTime: renewTime}
+		if _, err := client.CreateLease(api.NodeLeaseNamespace, lease); err != nil {
+			t.Fatalf("CreateLease: %v", err)
+		}
+	}
+	recorder := record.NewRecorder(client, api.EventSource{Component: controllerName})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		node, err := client.GetNode("silent")
+		if err != nil {
+			t.Fatalf("GetNode: %v", err)
+		}
+		if node.Status == api.NodeNotReady {
+			for _, cond := range node.Conditions {
+				if cond.Status != api.ConditionUnknown || cond.Reason != "NodeStatusUnknown" {
+					t.Errorf("expected every condition of the silent node Unknown, got %+v", cond)
+				}
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the node with an expired lease to turn NotReady, got %+v", node)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, name := range []string{"alive", "manual"} {
+		if node, _ := client.GetNode(name); !api.IsNodeReady(node) {
+			t.Errorf("expected node %s to stay Ready, got %+v", name, node.Conditions)
+		}
+	}
+}
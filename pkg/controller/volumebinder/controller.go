@@ -0,0 +1,339 @@
+// Package volumebinder binds persistent volume claims to persistent volumes.
+//
+// A Pending claim is bound to the smallest Available volume that offers at least the
+// requested storage and every requested access mode; ties go to the volume whose name
+// sorts first. A volume whose claimRef already names the claim, or the volume named by
+// the claim's volumeName, is used instead of searching. Binding updates the volume
+// first (claimRef and phase Bound) and then the claim (volumeName, capacity, and phase
+// Bound), so a bind interrupted half way is completed on the next pass.
+//
+// When a bound claim is deleted its volume is reclaimed according to its policy: Retain
+// volumes become Released and keep their data until an administrator clears claimRef,
+// while Delete volumes are removed. A bound claim whose volume disappears becomes Lost.
+package volumebinder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/resource"
+)
+
+const controllerName = "persistentvolume-binder"
+
+// Controller binds claims to volumes and reclaims volumes whose claims are gone.
+type Controller struct {
+	client   api.Interface
+	recorder record.EventRecorder
+
+	claims  controller.Informer
+	volumes controller.Informer
+	ctrl    *controller.Controller
+
+	// bindMu serializes binding so that two claims can't both pick the same volume.
+	bindMu sync.Mutex
+}
+
+// NewController creates a volume binder that follows claims and volumes through
+// informers' shared informers. opts are passed on to controller.New.
+func NewController(client api.Interface, recorder record.EventRecorder, informers *informer.SharedInformerFactory, opts ...controller.Option) *Controller {
+	c := &Controller{
+		client:   client,
+		recorder: recorder,
+		claims:   informers.PersistentVolumeClaims(),
+		volumes:  informers.PersistentVolumes(),
+	}
+	opts = append([]controller.Option{controller.WithName(controllerName), controller.WithClock(informers.Clock())}, opts...)
+	c.ctrl = controller.New(c.claims, controller.NewWorkQueue(), c.reconcile, opts...)
+
+	// Claim keys are "namespace/name" and volume keys a bare name, so both share the
+	// queue. A deleted claim enqueues its volume to have it reclaimed.
+	c.claims.AddEventHandler(controller.EventHandler{
+		OnDelete: func(obj interface{}) {
+			if claim, ok := obj.(*api.PersistentVolumeClaim); ok && claim.VolumeName != "" {
+				c.ctrl.Queue().Add(claim.VolumeName)
+			}
+		},
+	})
+	c.volumes.AddEventHandler(controller.EventHandler{
+		OnAdd:    c.volumeChanged,
+		OnUpdate: func(_, newObj interface{}) { c.volumeChanged(newObj) },
+		OnDelete: c.volumeDeleted,
+	})
+	return c
+}
+
+// volumeDeleted enqueues the claims bound to a deleted volume so they are marked Lost.
+// The cached volume may predate its bind, so claims naming it count as well as its
+// claimRef.
+func (c *Controller) volumeDeleted(obj interface{}) {
+	pv, ok := obj.(*api.PersistentVolume)
+	if !ok {
+		return
+	}
+	if pv.ClaimRef != nil {
+		c.ctrl.Queue().Add(pv.ClaimRef.Namespace + "/" + pv.ClaimRef.Name)
+	}
+	for _, obj := range c.claims.List() {
+		if claim, ok := obj.(*api.PersistentVolumeClaim); ok && claim.VolumeName == pv.Name {
+			key, _ := controller.MetaNamespaceKeyFunc(claim)
+			c.ctrl.Queue().Add(key)
+		}
+	}
+}
+
+// volumeChanged enqueues the volume and, when it may be bound, every pending claim.
+func (c *Controller) volumeChanged(obj interface{}) {
+	pv, ok := obj.(*api.PersistentVolume)
+	if !ok {
+		return
+	}
+	c.ctrl.Queue().Add(pv.Name)
+	if pv.Phase != api.VolumeAvailable {
+		return
+	}
+	for _, obj := range c.claims.List() {
+		if claim, ok := obj.(*api.PersistentVolumeClaim); ok && claim.Phase == api.ClaimPending {
+			key, _ := controller.MetaNamespaceKeyFunc(claim)
+			c.ctrl.Queue().Add(key)
+		}
+	}
+}
+
+// Run runs the controller with the given number of workers until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	go c.volumes.Run(ctx)
+	if !controller.WaitForCacheSync(ctx, c.volumes) {
+		return
+	}
+	c.ctrl.Run(ctx, workers)
+}
+
+// reconcile handles a claim ("namespace/name") or a volume ("name").
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	if strings.Contains(key, "/") {
+		return c.syncClaim(key)
+	}
+	return c.syncVolume(key)
+}
+
+func (c *Controller) syncClaim(key string) error {
+	obj, exists := c.claims.GetByKey(key)
+	if !exists {
+		return nil
+	}
+	claim := *obj.(*api.PersistentVolumeClaim)
+
+	switch claim.Phase {
+	case api.ClaimBound:
+		return c.checkBoundClaim(&claim)
+	case api.ClaimLost:
+		return nil
+	}
+
+	c.bindMu.Lock()
+	defer c.bindMu.Unlock()
+
+	pv, err := c.findVolume(&claim)
+	if err != nil {
+		return err
+	}
+	if pv == nil {
+		return nil
+	}
+	return c.bind(pv, &claim)
+}
+
+// checkBoundClaim marks a bound claim Lost once its volume no longer exists.
+func (c *Controller) checkBoundClaim(claim *api.PersistentVolumeClaim) error {
+	if _, exists := c.volumes.GetByKey(claim.VolumeName); exists {
+		return nil
+	}
+	// The volume cache may lag behind a volume that was just created.
+	if _, err := c.client.GetPersistentVolume(claim.VolumeName); err == nil {
+		return nil
+	} else if !isNotFound(err) {
+		return fmt.Errorf("checking persistentvolume %s: %w", claim.VolumeName, err)
+	}
+	claim.Phase = api.ClaimLost
+	if err := c.client.UpdatePersistentVolumeClaim(claim); err != nil {
+		return fmt.Errorf("marking persistentvolumeclaim %s/%s lost: %w", claim.Namespace, claim.Name, err)
+	}
+	log.Printf("[%s] Claim %s/%s lost its volume %s", controllerName, claim.Namespace, claim.Name, claim.VolumeName)
+	c.recorder.Eventf(claim, api.EventTypeWarning, "ClaimLost", "Bound persistentvolume %s no longer exists", claim.VolumeName)
+	return nil
+}
+
+// findVolume returns the volume to bind claim to, or nil if there is none yet.
+func (c *Controller) findVolume(claim *api.PersistentVolumeClaim) (*api.PersistentVolume, error) {
+	if claim.VolumeName != "" {
+		pv, err := c.client.GetPersistentVolume(claim.VolumeName)
+		if isNotFound(err) {
+			c.recorder.Eventf(claim, api.EventTypeWarning, "FailedBinding", "persistentvolume %s not found", claim.VolumeName)
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("getting persistentvolume %s: %w", claim.VolumeName, err)
+		}
+		if pv.ClaimRef != nil && !refersTo(pv.ClaimRef, claim) {
+			c.recorder.Eventf(claim, api.EventTypeWarning, "FailedBinding", "persistentvolume %s is already bound to %s/%s", pv.Name, pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+			return nil, nil
+		}
+		return pv, nil
+	}
+
+	request, err := resource.ParseQuantity(claim.Storage)
+	if err != nil {
+		return nil, nil
+	}
+	var candidates []*api.PersistentVolume
+	for _, obj := range c.volumes.List() {
+		pv := obj.(*api.PersistentVolume)
+		if pv.ClaimRef != nil {
+			if refersTo(pv.ClaimRef, claim) {
+				return c.liveVolume(pv.Name)
+			}
+			continue
+		}
+		if pv.Phase == api.VolumeAvailable && satisfies(pv, request, claim.AccessModes) {
+			candidates = append(candidates, pv)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		si, sj := resource.MustParse(candidates[i].Capacity), resource.MustParse(candidates[j].Capacity)
+		if si != sj {
+			return si < sj
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	for _, candidate := range candidates {
+		// The cache may not yet show a bind made moments ago.
+		pv, err := c.liveVolume(candidate.Name)
+		if isNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if pv.Phase == api.VolumeAvailable && pv.ClaimRef == nil {
+			return pv, nil
+		}
+	}
+	c.recorder.Eventf(claim, api.EventTypeNormal, "FailedBinding", "no persistent volumes available for this claim")
+	return nil, nil
+}
+
+// liveVolume fetches a volume from the API server.
+func (c *Controller) liveVolume(name string) (*api.PersistentVolume, error) {
+	pv, err := c.client.GetPersistentVolume(name)
+	if err != nil {
+		return nil, fmt.Errorf("getting persistentvolume %s: %w", name, err)
+	}
+	return pv, nil
+}
+
+// bind points pv at claim and then claim at pv.
+func (c *Controller) bind(pv *api.PersistentVolume, claim *api.PersistentVolumeClaim) error {
+	if pv.ClaimRef != nil && !refersTo(pv.ClaimRef, claim) {
+		// Bound by someone else since it was cached; try again on the next pass.
+		return fmt.Errorf("persistentvolume %s was bound to %s/%s in the meantime", pv.Name, pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+	}
+	if pv.Phase != api.VolumeBound || pv.ClaimRef == nil || pv.ClaimRef.UID != claim.UID {
+		pv.ClaimRef = &api.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: claim.Namespace, Name: claim.Name, UID: claim.UID}
+		pv.Phase = api.VolumeBound
+		if err := c.client.UpdatePersistentVolume(pv); err != nil {
+			return fmt.Errorf("binding persistentvolume %s: %w", pv.Name, err)
+		}
+	}
+
+	claim.VolumeName = pv.Name
+	claim.Capacity = pv.Capacity
+	claim.Phase = api.ClaimBound
+	if err := c.client.UpdatePersistentVolumeClaim(claim); err != nil {
+		return fmt.Errorf("binding persistentvolumeclaim %s/%s: %w", claim.Namespace, claim.Name, err)
+	}
+	log.Printf("[%s] Bound claim %s/%s to volume %s", controllerName, claim.Namespace, claim.Name, pv.Name)
+	return nil
+}
+
+func (c *Controller) syncVolume(name string) error {
+	obj, exists := c.volumes.GetByKey(name)
+	if !exists {
+		return nil
+	}
+	pv := *obj.(*api.PersistentVolume)
+
+	if pv.ClaimRef == nil {
+		// An administrator cleared claimRef on a released volume to make it usable again.
+		if pv.Phase != api.VolumeAvailable {
+			pv.Phase = api.VolumeAvailable
+			if err := c.client.UpdatePersistentVolume(&pv); err != nil {
+				return fmt.Errorf("making persistentvolume %s available: %w", name, err)
+			}
+		}
+		return nil
+	}
+	// A claimRef without a UID pre-binds the volume to a claim that may not exist yet.
+	if pv.ClaimRef.UID == "" || pv.Phase == api.VolumeReleased {
+		return nil
+	}
+
+	claim, err := c.client.GetPersistentVolumeClaim(pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+	if err == nil && claim.UID == pv.ClaimRef.UID {
+		return nil
+	} else if err != nil && !isNotFound(err) {
+		return fmt.Errorf("getting persistentvolumeclaim %s/%s: %w", pv.ClaimRef.Namespace, pv.ClaimRef.Name, err)
+	}
+
+	if pv.ReclaimPolicy == api.PersistentVolumeReclaimDelete {
+		if err := c.client.DeletePersistentVolume(name); err != nil && !isNotFound(err) {
+			return fmt.Errorf("deleting released persistentvolume %s: %w", name, err)
+		}
+		log.Printf("[%s] Deleted volume %s after its claim %s/%s was removed", controllerName, name, pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+		return nil
+	}
+	pv.Phase = api.VolumeReleased
+	if err := c.client.UpdatePersistentVolume(&pv); err != nil {
+		return fmt.Errorf("releasing persistentvolume %s: %w", name, err)
+	}
+	log.Printf("[%s] Released volume %s after its claim %s/%s was removed", controllerName, name, pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+	c.recorder.Eventf(&pv, api.EventTypeNormal, "VolumeReleased", "Claim %s/%s was deleted; the volume is retained", pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+	return nil
+}
+
+// refersTo reports whether ref names claim. A ref without a UID matches any claim of
+// that name.
+func refersTo(ref *api.ObjectReference, claim *api.PersistentVolumeClaim) bool {
+	return ref.Namespace == claim.Namespace && ref.Name == claim.Name && (ref.UID == "" || ref.UID == claim.UID)
+}
+
+// satisfies reports whether pv offers at least request bytes and every mode in modes.
+func satisfies(pv *api.PersistentVolume, request int64, modes []api.PersistentVolumeAccessMode) bool {
+	capacity, err := resource.ParseQuantity(pv.Capacity)
+	if err != nil || capacity < request {
+		return false
+	}
+	for _, mode := range modes {
+		found := false
+		for _, offered := range pv.AccessModes {
+			if offered == mode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func isNotFound(err error) bool {
+	return api.IsNotFound(err)
+}
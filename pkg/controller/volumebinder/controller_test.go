@@ -0,0 +1,160 @@
+package volumebinder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+func hostPathVolume(name, capacity string, policy api.PersistentVolumeReclaimPolicy, modes ...api.PersistentVolumeAccessMode) *api.PersistentVolume {
+	return &api.PersistentVolume{
+		ObjectMeta:    api.ObjectMeta{Name: name},
+		Capacity:      capacity,
+		AccessModes:   modes,
+		ReclaimPolicy: policy,
+		HostPath:      &api.HostPathVolumeSource{Path: "/srv/" + name},
+	}
+}
+
+func claim(name, storage string, modes ...api.PersistentVolumeAccessMode) *api.PersistentVolumeClaim {
+	return &api.PersistentVolumeClaim{
+		ObjectMeta:  api.ObjectMeta{Name: name},
+		Storage:     storage,
+		AccessModes: modes,
+	}
+}
+
+// waitFor polls until cond holds or fails the test after two seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBindsClaimsToSmallestMatchingVolume(t *testing.T) {
+	client := fake.NewClient(
+		hostPathVolume("small", "1Gi", api.PersistentVolumeReclaimRetain, api.ReadWriteOnce),
+		hostPathVolume("large", "10Gi", api.PersistentVolumeReclaimRetain, api.ReadWriteOnce),
+		hostPathVolume("medium", "5Gi", api.PersistentVolumeReclaimRetain, api.ReadWriteOnce),
+		hostPathVolume("shared", "100Gi", api.PersistentVolumeReclaimRetain, api.ReadOnlyMany),
+		claim("data", "2Gi", api.ReadWriteOnce),
+		claim("too-big", "1Ti", api.ReadWriteOnce),
+	)
+	recorder := record.NewRecorder(client, api.EventSource{Component: controllerName})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	waitFor(t, "claim data to be bound", func() bool {
+		pvc, err := client.GetPersistentVolumeClaim("default", "data")
+		return err == nil && pvc.Phase == api.ClaimBound
+	})
+	pvc, _ := client.GetPersistentVolumeClaim("default", "data")
+	if pvc.VolumeName != "medium" || pvc.Capacity != "5Gi" {
+		t.Errorf("expected data to be bound to the 5Gi volume medium, got %q (%s)", pvc.VolumeName, pvc.Capacity)
+	}
+	pv, _ := client.GetPersistentVolume("medium")
+	if pv.Phase != api.VolumeBound || pv.ClaimRef == nil || pv.ClaimRef.Name != "data" || pv.ClaimRef.UID != pvc.UID {
+		t.Errorf("expected medium to be bound to default/data, got %s %+v", pv.Phase, pv.ClaimRef)
+	}
+
+	pvc, _ = client.GetPersistentVolumeClaim("default", "too-big")
+	if pvc.Phase != api.ClaimPending {
+		t.Errorf("expected too-big to stay Pending, got %s", pvc.Phase)
+	}
+	for _, name := range []string{"small", "large", "shared"} {
+		if pv, _ := client.GetPersistentVolume(name); pv.Phase != api.VolumeAvailable {
+			t.Errorf("expected %s to stay Available, got %s", name, pv.Phase)
+		}
+	}
+}
+
+func TestReclaimsVolumesOfDeletedClaims(t *testing.T) {
+	client := fake.NewClient(
+		hostPathVolume("retained", "1Gi", api.PersistentVolumeReclaimRetain, api.ReadWriteOnce),
+		hostPathVolume("deleted", "1Gi", api.PersistentVolumeReclaimDelete, api.ReadWriteMany),
+		claim("keep", "1Gi", api.ReadWriteOnce),
+		claim("scratch", "1Gi", api.ReadWriteMany),
+	)
+	recorder := record.NewRecorder(client, api.EventSource{Component: controllerName})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 2)
+
+	waitFor(t, "both claims to be bound", func() bool {
+		pvcs, err := client.ListPersistentVolumeClaims("default")
+		if err != nil {
+			return false
+		}
+		for _, pvc := range pvcs {
+			if pvc.Phase != api.ClaimBound {
+				return false
+			}
+		}
+		return len(pvcs) == 2
+	})
+
+	for _, name := range []string{"keep", "scratch"} {
+		if err := client.DeletePersistentVolumeClaim("default", name); err != nil {
+			t.Fatalf("DeletePersistentVolumeClaim: %v", err)
+		}
+	}
+	waitFor(t, "retained volume to be released", func() bool {
+		pv, err := client.GetPersistentVolume("retained")
+		return err == nil && pv.Phase == api.VolumeReleased
+	})
+	waitFor(t, "deleted volume to be removed", func() bool {
+		_, err := client.GetPersistentVolume("deleted")
+		return err != nil
+	})
+
+	// Clearing claimRef makes a released volume available to new claims.
+	pv, _ := client.GetPersistentVolume("retained")
+	pv.ClaimRef = nil
+	if err := client.UpdatePersistentVolume(pv); err != nil {
+		t.Fatalf("UpdatePersistentVolume: %v", err)
+	}
+	if _, err := client.CreatePersistentVolumeClaim("default", claim("again", "500Mi", api.ReadWriteOnce)); err != nil {
+		t.Fatalf("CreatePersistentVolumeClaim: %v", err)
+	}
+	waitFor(t, "new claim to be bound to the reclaimed volume", func() bool {
+		pvc, err := client.GetPersistentVolumeClaim("default", "again")
+		return err == nil && pvc.Phase == api.ClaimBound && pvc.VolumeName == "retained"
+	})
+}
+
+func TestClaimLosesDeletedVolume(t *testing.T) {
+	pv := hostPathVolume("gone", "1Gi", api.PersistentVolumeReclaimRetain, api.ReadWriteOnce)
+	pvc := claim("orphan", "1Gi", api.ReadWriteOnce)
+	client := fake.NewClient(pv, pvc)
+	recorder := record.NewRecorder(client, api.EventSource{Component: controllerName})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewController(client, recorder, informer.NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})).Run(ctx, 1)
+
+	waitFor(t, "claim to be bound", func() bool {
+		pvc, err := client.GetPersistentVolumeClaim("default", "orphan")
+		return err == nil && pvc.Phase == api.ClaimBound
+	})
+	if err := client.DeletePersistentVolume("gone"); err != nil {
+		t.Fatalf("DeletePersistentVolume: %v", err)
+	}
+	waitFor(t, "claim to be lost", func() bool {
+		pvc, err := client.GetPersistentVolumeClaim("default", "orphan")
+		return err == nil && pvc.Phase == api.ClaimLost
+	})
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+const (
+	defaultBaseDelay = 5 * time.Millisecond
+	defaultMaxDelay  = 60 * time.Second
+)
+
+// WorkQueue is a deduplicating queue of object keys.
+// A key that is added while it is already queued is stored only once, and a key
+// that is added while it is being processed is re-queued once processing finishes,
+// so a single key is never handled by two workers at the same time.
+type WorkQueue struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	queue      []string
+	dirty      map[string]struct{} // Keys waiting to be processed
+	processing map[string]struct{} // Keys currently held by a worker
+	failures   map[string]int      // Consecutive failures per key, for backoff
+	shutdown   bool
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+
+	// Clock times AddAfter and the retry back-off; NewWorkQueue sets it to the real
+	// clock.
+	Clock clock.Clock
+}
+
+// NewWorkQueue creates a new, empty WorkQueue.
+func NewWorkQueue() *WorkQueue {
+	q := &WorkQueue{
+		dirty:      make(map[string]struct{}),
+		processing: make(map[string]struct{}),
+		failures:   make(map[string]int),
+		baseDelay:  defaultBaseDelay,
+		maxDelay:   defaultMaxDelay,
+		Clock:      clock.RealClock{},
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Add marks key as needing processing.
+func (q *WorkQueue) Add(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.shutdown {
+		return
+	}
+	if _, exists := q.dirty[key]; exists {
+		return
+	}
+	q.dirty[key] = struct{}{}
+	if _, busy := q.processing[key]; busy {
+		// Done will re-queue it once the current worker finishes.
+		return
+	}
+	q.queue = append(q.queue, key)
+	q.cond.Signal()
+}
+
+// AddAfter adds key to the queue once delay has passed.
+func (q *WorkQueue) AddAfter(key string, delay time.Duration) {
+	if delay <= 0 {
+		q.Add(key)
+		return
+	}
+	q.Clock.AfterFunc(delay, func() { q.Add(key) })
+}
+
+// AddRateLimited re-adds key after an exponential backoff based on how many
+// times it has failed in a row. Call Forget once the key succeeds.
+func (q *WorkQueue) AddRateLimited(key string) {
+	q.mu.Lock()
+	failures := q.failures[key]
+	q.failures[key] = failures + 1
+	q.mu.Unlock()
+
+	delay := q.baseDelay
+	for i := 0; i < failures && delay < q.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > q.maxDelay {
+		delay = q.maxDelay
+	}
+	q.AddAfter(key, delay)
+}
+
+// Forget clears the failure history of key.
+func (q *WorkQueue) Forget(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.failures, key)
+}
+
+// NumRequeues returns how many times key has been re-added with AddRateLimited.
+func (q *WorkQueue) NumRequeues(key string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.failures[key]
+}
+
+// Get blocks until a key is available and returns it.
+// The second return value is true once the queue has been shut down and drained.
+func (q *WorkQueue) Get() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.queue) == 0 && !q.shutdown {
+		q.cond.Wait()
+	}
+	if len(q.queue) == 0 {
+		return "", true
+	}
+
+	key := q.queue[0]
+	q.queue = q.queue[1:]
+	q.processing[key] = struct{}{}
+	delete(q.dirty, key)
+	return key, false
+}
+
+// Done marks key as no longer being processed.
+// If key was added again while it was being processed, it is re-queued.
+func (q *WorkQueue) Done(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.processing, key)
+	if _, exists := q.dirty[key]; exists {
+		q.queue = append(q.queue, key)
+		q.cond.Signal()
+	}
+}
+
+// Len returns the number of keys waiting to be processed.
+func (q *WorkQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queue)
+}
+
+// ShutDown stops the queue from accepting new keys and wakes up all waiting workers.
+// Keys already queued are still handed out by Get.
+func (q *WorkQueue) ShutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shutdown = true
+	q.cond.Broadcast()
+}
+
+// ShuttingDown reports whether ShutDown has been called.
+func (q *WorkQueue) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shutdown
+}
@@ -0,0 +1,172 @@
+// Package controllermanager runs the built-in controllers side by side.
+package controllermanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller/deployment"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller/garbagecollector"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller/namespace"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller/nodelifecycle"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller/volumebinder"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/heartbeat"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/informer"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+// Options configures the controllers started by Run.
+type Options struct {
+	// SyncInterval is how often each controller polls the API server, as measured
+	// by Clock.
+	SyncInterval time.Duration
+	// Workers is the number of workers per controller.
+	Workers int
+	Clock   clock.Clock
+	// Chaos, if set, makes the controllers drop informer events.
+	Chaos *chaos.Injector
+	// LeaderElect makes each controller run only while it holds a Lease named after
+	// it in LeaderElectionNamespace, so that several controller managers can run
+	// with one active at a time. Identity names this one; it defaults to the
+	// hostname and process ID.
+	LeaderElect bool
+	Identity    string
+	// Controllers selects the controllers to run, as the --controllers flag does: "*"
+	// for every controller, a name to add that controller, and "-name" to leave it
+	// out. Empty means every controller.
+	Controllers []string
+}
+
+// KnownControllers names the controllers Run can start.
+var KnownControllers = []string{"deployment", "garbage-collector", "namespace", "node-lifecycle", "persistentvolume-binder"}
+
+// ValidateControllers checks that every entry of a --controllers list is "*", a
+// known controller, or a known controller prefixed with "-".
+func ValidateControllers(controllers []string) error {
+	for _, c := range controllers {
+		if c == "*" {
+			continue
+		}
+		if !slices.Contains(KnownControllers, strings.TrimPrefix(c, "-")) {
+			return fmt.Errorf("unknown controller %q in --controllers; known controllers are %s", c, strings.Join(KnownControllers, ", "))
+		}
+	}
+	return nil
+}
+
+// ControllerEnabled reports whether name is selected by controllers, a --controllers
+// list. An explicit "name" or "-name" wins over "*".
+func ControllerEnabled(name string, controllers []string) bool {
+	if len(controllers) == 0 {
+		return true
+	}
+	all := false
+	for _, c := range controllers {
+		switch c {
+		case name:
+			return true
+		case "-" + name:
+			return false
+		case "*":
+			all = true
+		}
+	}
+	return all
+}
+
+// LeaderElectionNamespace holds the leases controllers elect their leaders with.
+const LeaderElectionNamespace = "kube-system"
+
+const (
+	leaseDuration = 15 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// Run starts the controllers selected by o.Controllers against client and blocks until
+// ctx is cancelled and they have all stopped.
+func Run(ctx context.Context, client api.Interface, o Options) {
+	if o.LeaderElect && o.Identity == "" {
+		hostname, _ := os.Hostname()
+		o.Identity = fmt.Sprintf("%s_%d", hostname, os.Getpid())
+	}
+	// The controllers are all built before the shared informers start, so that each
+	// informer they ask for runs once, for the whole controller manager.
+	informers := informer.NewSharedInformerFactory(client, o.SyncInterval, o.Clock)
+	type runner interface {
+		Run(ctx context.Context, workers int)
+	}
+	controllers := map[string]runner{}
+	add := func(name string, newController func(opts ...controller.Option) runner) {
+		if !ControllerEnabled(name, o.Controllers) {
+			log.Printf("Not starting %s controller: disabled by --controllers", name)
+			return
+		}
+		opts := []controller.Option{controller.WithChaos(o.Chaos)}
+		if o.LeaderElect {
+			opts = append(opts, controller.WithLeaderElector(&controller.LeaseElector{
+				Identity:      o.Identity,
+				Lock:          controller.NewLeaseLock(client, LeaderElectionNamespace, name+"-controller", leaseDuration, nil),
+				LeaseDuration: leaseDuration,
+				RetryPeriod:   retryPeriod,
+				Clock:         o.Clock,
+			}))
+		}
+		controllers[name] = newController(opts...)
+	}
+
+	add("deployment", func(opts ...controller.Option) runner {
+		recorder := record.NewRecorder(client, api.EventSource{Component: "deployment-controller"})
+		return deployment.NewController(client, recorder, informers, opts...)
+	})
+	add("node-lifecycle", func(opts ...controller.Option) runner {
+		recorder := record.NewRecorder(client, api.EventSource{Component: "node-lifecycle-controller"})
+		return nodelifecycle.NewController(client, recorder, informers, opts...)
+	})
+	add("garbage-collector", func(opts ...controller.Option) runner {
+		return garbagecollector.NewController(client, informers, opts...)
+	})
+	add("namespace", func(opts ...controller.Option) runner {
+		return namespace.NewController(client, informers, opts...)
+	})
+	add("persistentvolume-binder", func(opts ...controller.Option) runner {
+		recorder := record.NewRecorder(client, api.EventSource{Component: "persistentvolume-binder"})
+		return volumebinder.NewController(client, recorder, informers, opts...)
+	})
+
+	informers.Start(ctx)
+	log.Println("Waiting for shared informer caches to sync")
+	if !informers.WaitForCacheSync(ctx) {
+		log.Println("Controller manager shutting down before caches synced")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for name, c := range controllers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Printf("Starting %s controller", name)
+			c.Run(ctx, o.Workers)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		heartbeat.Run(ctx, client, "controller-manager", heartbeat.DefaultPeriod)
+	}()
+
+	<-ctx.Done()
+	log.Println("Controller manager shutting down")
+	wg.Wait()
+}
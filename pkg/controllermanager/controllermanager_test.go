@@ -0,0 +1,33 @@
+package controllermanager
+
+import "testing"
+
+func TestControllerEnabled(t *testing.T) {
+	tests := []struct {
+		controllers []string
+		name        string
+		want        bool
+	}{
+		{nil, "namespace", true},
+		{[]string{"*"}, "namespace", true},
+		{[]string{"*", "-namespace"}, "namespace", false},
+		{[]string{"*", "-namespace"}, "garbage-collector", true},
+		{[]string{"node-lifecycle"}, "node-lifecycle", true},
+		{[]string{"node-lifecycle"}, "namespace", false},
+		{[]string{"-namespace"}, "garbage-collector", false},
+	}
+	for _, tt := range tests {
+		if got := ControllerEnabled(tt.name, tt.controllers); got != tt.want {
+			t.Errorf("ControllerEnabled(%q, %q) = %v, want %v", tt.name, tt.controllers, got, tt.want)
+		}
+	}
+}
+
+func TestValidateControllers(t *testing.T) {
+	if err := ValidateControllers([]string{"*", "-garbage-collector", "namespace"}); err != nil {
+		t.Errorf("expected a valid list to pass, got %v", err)
+	}
+	if err := ValidateControllers([]string{"*", "-replicaset"}); err == nil {
+		t.Error("expected an unknown controller to be rejected")
+	}
+}
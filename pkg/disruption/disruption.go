@@ -0,0 +1,76 @@
+// Package disruption implements PodDisruptionBudget accounting: how many of a budget's
+// pods are healthy, and whether evicting a given pod would take the budget below its
+// minimum.
+package disruption
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+)
+
+// ErrBudgetViolated is returned (wrapped) by CheckEviction when evicting the pod would
+// violate a disruption budget. The eviction may succeed later, once pods recover.
+var ErrBudgetViolated = errors.New("cannot evict pod as it would violate the pod's disruption budget")
+
+// Matches reports whether pod is covered by pdb.
+func Matches(pdb *api.PodDisruptionBudget, pod *api.Pod) bool {
+	return pod.Namespace == pdb.Namespace && labels.SelectorFromSet(pdb.Selector).Matches(pod.Labels)
+}
+
+func isHealthy(pod *api.Pod) bool {
+	return pod.DeletionTimestamp == nil && pod.Phase == api.PodRunning
+}
+
+// Status computes pdb's status from pods, which may include pods the budget doesn't
+// select.
+func Status(pdb *api.PodDisruptionBudget, pods []*api.Pod) api.PodDisruptionBudgetStatus {
+	var status api.PodDisruptionBudgetStatus
+	for _, pod := range pods {
+		if !Matches(pdb, pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		switch pod.Phase {
+		case api.PodSucceeded, api.PodFailed, api.PodDeleted:
+			continue
+		}
+		status.ExpectedPods++
+		if isHealthy(pod) {
+			status.CurrentHealthy++
+		}
+	}
+	switch {
+	case pdb.MinAvailable != nil:
+		status.DesiredHealthy = *pdb.MinAvailable
+	case pdb.MaxUnavailable != nil:
+		status.DesiredHealthy = max(status.ExpectedPods-*pdb.MaxUnavailable, 0)
+	}
+	status.DisruptionsAllowed = max(status.CurrentHealthy-status.DesiredHealthy, 0)
+	return status
+}
+
+// CheckEviction returns nil if pod may be evicted given the budgets in its namespace
+// and the pods they cover. Evicting a pod that isn't healthy never reduces
+// availability, so it is always allowed.
+func CheckEviction(pod *api.Pod, pdbs []*api.PodDisruptionBudget, pods []*api.Pod) error {
+	var matching []*api.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		if Matches(pdb, pod) {
+			matching = append(matching, pdb)
+		}
+	}
+	if len(matching) == 0 || !isHealthy(pod) {
+		return nil
+	}
+	if len(matching) > 1 {
+		return fmt.Errorf("pod %s/%s is covered by %d disruption budgets; only one is supported", pod.Namespace, pod.Name, len(matching))
+	}
+	pdb := matching[0]
+	status := Status(pdb, pods)
+	if status.DisruptionsAllowed < 1 {
+		return fmt.Errorf("%w %s: needs %d healthy pods and has %d", ErrBudgetViolated, pdb.Name, status.DesiredHealthy, status.CurrentHealthy)
+	}
+	return nil
+}
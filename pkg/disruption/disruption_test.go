@@ -0,0 +1,78 @@
+package disruption
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func intPtr(i int) *int { return &i }
+
+func webPods(phases ...api.PodPhase) []*api.Pod {
+	var pods []*api.Pod
+	for i, phase := range phases {
+		pods = append(pods, &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Phase:      phase,
+		})
+	}
+	return pods
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Now()
+	pods := webPods(api.PodRunning, api.PodRunning, api.PodPending, api.PodRunning, api.PodFailed)
+	pods[3].DeletionTimestamp = &now
+	pods = append(pods, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default", Labels: map[string]string{"app": "db"}}, Phase: api.PodRunning})
+
+	tests := []struct {
+		name string
+		pdb  api.PodDisruptionBudget
+		want api.PodDisruptionBudgetStatus
+	}{
+		{
+			name: "minAvailable",
+			pdb:  api.PodDisruptionBudget{ObjectMeta: api.ObjectMeta{Namespace: "default"}, Selector: map[string]string{"app": "web"}, MinAvailable: intPtr(1)},
+			want: api.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 2, DesiredHealthy: 1, DisruptionsAllowed: 1},
+		},
+		{
+			name: "maxUnavailable",
+			pdb:  api.PodDisruptionBudget{ObjectMeta: api.ObjectMeta{Namespace: "default"}, Selector: map[string]string{"app": "web"}, MaxUnavailable: intPtr(1)},
+			want: api.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 2, DesiredHealthy: 2, DisruptionsAllowed: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Status(&tt.pdb, pods); got != tt.want {
+				t.Errorf("Status() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckEviction(t *testing.T) {
+	pods := webPods(api.PodRunning, api.PodRunning, api.PodPending)
+	pdbs := []*api.PodDisruptionBudget{
+		{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Selector: map[string]string{"app": "web"}, MinAvailable: intPtr(2)},
+	}
+
+	if err := CheckEviction(pods[0], pdbs, pods); !errors.Is(err, ErrBudgetViolated) {
+		t.Errorf("evicting a healthy pod at the budget minimum: expected ErrBudgetViolated, got %v", err)
+	}
+	if err := CheckEviction(pods[2], pdbs, pods); err != nil {
+		t.Errorf("evicting an unhealthy pod should always be allowed, got %v", err)
+	}
+
+	pdbs[0].MinAvailable = intPtr(1)
+	if err := CheckEviction(pods[0], pdbs, pods); err != nil {
+		t.Errorf("evicting with a disruption to spare: %v", err)
+	}
+
+	unrelated := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default"}, Phase: api.PodRunning}
+	if err := CheckEviction(unrelated, pdbs, pods); err != nil {
+		t.Errorf("pods outside every budget should be evictable, got %v", err)
+	}
+}
@@ -0,0 +1,359 @@
+// Package fieldmanager tracks which manager, such as kubectl-lite or the scheduler,
+// owns each field of an object, for server-side apply.
+//
+// Objects are handled in their generic JSON form. A field is a path to a leaf of the
+// object: a value that is not a map with keys, so lists are owned as a whole. An
+// apply declares the complete set of fields its manager wants, with their values; it
+// conflicts with other managers that own one of those fields with a different value,
+// unless forced, and fields the manager applied before but left out are removed.
+// Any other write simply takes over the fields it changes.
+package fieldmanager
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// ignoredFields are set by the server or identify the object; nobody owns them.
+var ignoredFields = map[string]bool{
+	"name":              true,
+	"namespace":         true,
+	"uid":               true,
+	"creationTimestamp": true,
+	"resourceVersion":   true,
+	"deletionTimestamp": true,
+	"managedFields":     true,
+}
+
+// Conflict is a field an apply would change that another manager owns.
+type Conflict struct {
+	Manager string `json:"manager"`
+	Field   string `json:"field"`
+}
+
+// ConflictError is returned by Apply when the applied fields conflict with other
+// managers'. Applying again with force takes the fields over.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	parts := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		parts[i] = fmt.Sprintf("conflict with %q: %s", c.Manager, c.Field)
+	}
+	noun := "conflicts"
+	if len(parts) == 1 {
+		noun = "conflict"
+	}
+	return fmt.Sprintf("Apply failed with %d %s: %s", len(parts), noun, strings.Join(parts, "; "))
+}
+
+// Fields returns the paths of every leaf of obj, sorted, leaving out ignoredFields.
+func Fields(obj map[string]interface{}) []string {
+	var paths []string
+	var walk func(prefix []string, m map[string]interface{})
+	walk = func(prefix []string, m map[string]interface{}) {
+		for k, v := range m {
+			if len(prefix) == 0 && ignoredFields[k] {
+				continue
+			}
+			keys := append(append([]string(nil), prefix...), k)
+			if sub, ok := v.(map[string]interface{}); ok && len(sub) > 0 {
+				walk(keys, sub)
+				continue
+			}
+			paths = append(paths, JoinPath(keys))
+		}
+	}
+	walk(nil, obj)
+	sort.Strings(paths)
+	return paths
+}
+
+// Apply merges applied, the complete configuration manager wants, into live, given
+// live's managed fields, and returns the merged object and its new managed fields.
+// live is nil for an object that doesn't exist yet. live is modified.
+//
+// Applying a value another manager set to the same value shares the field. A
+// different value is a conflict, reported as a *ConflictError, unless force is set,
+// in which case the other managers lose the field.
+func Apply(live map[string]interface{}, managed []api.ManagedFieldsEntry, applied map[string]interface{}, manager string, force bool, now time.Time) (map[string]interface{}, []api.ManagedFieldsEntry, error) {
+	if live == nil {
+		live = make(map[string]interface{})
+	}
+	managed = copyEntries(managed)
+	self := func(e api.ManagedFieldsEntry) bool {
+		return e.Manager == manager && e.Operation == api.ManagedFieldsOperationApply
+	}
+	fields := Fields(applied)
+
+	var conflicts []Conflict
+	for _, field := range fields {
+		keys := SplitPath(field)
+		want, _ := getPath(applied, keys)
+		have, _ := getPath(live, keys)
+		if reflect.DeepEqual(want, have) {
+			continue
+		}
+		for i := range managed {
+			if self(managed[i]) {
+				continue
+			}
+			owned := relatedFields(managed[i].Fields, keys)
+			if len(owned) == 0 {
+				continue
+			}
+			if force {
+				managed[i].Fields = without(managed[i].Fields, owned)
+				continue
+			}
+			for _, f := range owned {
+				c := Conflict{Manager: managed[i].Manager, Field: f}
+				if !containsConflict(conflicts, c) {
+					conflicts = append(conflicts, c)
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	// Fields applied last time but not now are removed, unless someone else owns them.
+	var previous []string
+	for _, e := range managed {
+		if self(e) {
+			previous = e.Fields
+		}
+	}
+	for _, field := range previous {
+		keys := SplitPath(field)
+		if contains(fields, field) || ownedByOthers(managed, keys, self) {
+			continue
+		}
+		deletePath(live, keys)
+	}
+	for _, field := range fields {
+		keys := SplitPath(field)
+		v, _ := getPath(applied, keys)
+		setPath(live, keys, v)
+	}
+
+	managed = setEntry(managed, api.ManagedFieldsEntry{Manager: manager, Operation: api.ManagedFieldsOperationApply, Time: now, Fields: fields})
+	return live, managed, nil
+}
+
+// Update records a write by manager that changed old into obj: manager takes over
+// every field whose value changed, and nobody owns a removed field any more. old is
+// nil for a create. It returns obj's new managed fields.
+func Update(old, obj map[string]interface{}, managed []api.ManagedFieldsEntry, manager string, now time.Time) []api.ManagedFieldsEntry {
+	managed = copyEntries(managed)
+	if old == nil {
+		old = map[string]interface{}{}
+	}
+
+	var taken []string
+	candidates := append(Fields(old), Fields(obj)...)
+	sort.Strings(candidates)
+	for i, field := range candidates {
+		if i > 0 && candidates[i-1] == field {
+			continue
+		}
+		keys := SplitPath(field)
+		before, _ := getPath(old, keys)
+		after, present := getPath(obj, keys)
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		for j := range managed {
+			managed[j].Fields = without(managed[j].Fields, relatedFields(managed[j].Fields, keys))
+		}
+		// A leaf that became a map is taken over through the fields under it instead.
+		if sub, isMap := after.(map[string]interface{}); present && (!isMap || len(sub) == 0) {
+			taken = append(taken, field)
+		}
+	}
+	if len(taken) == 0 {
+		return dropEmpty(managed)
+	}
+
+	var fields []string
+	for _, e := range managed {
+		if e.Manager == manager && e.Operation == api.ManagedFieldsOperationUpdate {
+			fields = e.Fields
+		}
+	}
+	fields = append(append([]string(nil), fields...), taken...)
+	sort.Strings(fields)
+	return setEntry(managed, api.ManagedFieldsEntry{Manager: manager, Operation: api.ManagedFieldsOperationUpdate, Time: now, Fields: fields})
+}
+
+// JoinPath joins keys into a field path. Keys that contain a dot, or would otherwise
+// be ambiguous, are quoted.
+func JoinPath(keys []string) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "" || strings.ContainsAny(k, `."`) {
+			k = strconv.Quote(k)
+		}
+		parts[i] = k
+	}
+	return strings.Join(parts, ".")
+}
+
+// SplitPath splits a field path made by JoinPath back into its keys.
+func SplitPath(path string) []string {
+	var keys []string
+	for path != "" {
+		var key string
+		if path[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(path)
+			if err != nil {
+				return append(keys, path) // Not made by JoinPath; treat the rest as one key
+			}
+			key, _ = strconv.Unquote(quoted)
+			path = path[len(quoted):]
+		} else {
+			end := strings.IndexByte(path, '.')
+			if end < 0 {
+				end = len(path)
+			}
+			key, path = path[:end], path[end:]
+		}
+		keys = append(keys, key)
+		path = strings.TrimPrefix(path, ".")
+	}
+	return keys
+}
+
+// relatedFields returns the fields that are keys, lie under keys, or contain keys.
+func relatedFields(fields []string, keys []string) []string {
+	var related []string
+	for _, f := range fields {
+		fk := SplitPath(f)
+		n := min(len(fk), len(keys))
+		if reflect.DeepEqual(fk[:n], keys[:n]) {
+			related = append(related, f)
+		}
+	}
+	return related
+}
+
+func ownedByOthers(managed []api.ManagedFieldsEntry, keys []string, self func(api.ManagedFieldsEntry) bool) bool {
+	for _, e := range managed {
+		if !self(e) && len(relatedFields(e.Fields, keys)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func getPath(obj map[string]interface{}, keys []string) (interface{}, bool) {
+	var cur interface{} = obj
+	for _, k := range keys {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func setPath(obj map[string]interface{}, keys []string, v interface{}) {
+	m := obj
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = v
+}
+
+func deletePath(obj map[string]interface{}, keys []string) {
+	m := obj
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, keys[len(keys)-1])
+}
+
+// setEntry replaces the entry with e's manager and operation, or appends e.
+func setEntry(managed []api.ManagedFieldsEntry, e api.ManagedFieldsEntry) []api.ManagedFieldsEntry {
+	for i := range managed {
+		if managed[i].Manager == e.Manager && managed[i].Operation == e.Operation {
+			managed[i] = e
+			return dropEmpty(managed)
+		}
+	}
+	return dropEmpty(append(managed, e))
+}
+
+func dropEmpty(managed []api.ManagedFieldsEntry) []api.ManagedFieldsEntry {
+	out := managed[:0]
+	for _, e := range managed {
+		if len(e.Fields) > 0 {
+			out = append(out, e)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func copyEntries(managed []api.ManagedFieldsEntry) []api.ManagedFieldsEntry {
+	out := make([]api.ManagedFieldsEntry, len(managed))
+	for i, e := range managed {
+		out[i] = e
+		out[i].Fields = append([]string(nil), e.Fields...)
+	}
+	return out
+}
+
+func without(fields, remove []string) []string {
+	if len(remove) == 0 {
+		return fields
+	}
+	var out []string
+	for _, f := range fields {
+		if !contains(remove, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func contains(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func containsConflict(conflicts []Conflict, c Conflict) bool {
+	for _, existing := range conflicts {
+		if existing == c {
+			return true
+		}
+	}
+	return false
+}
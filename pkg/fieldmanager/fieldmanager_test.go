@@ -0,0 +1,99 @@
+package fieldmanager
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestApply(t *testing.T) {
+	now := time.Now()
+	applied := map[string]interface{}{
+		"image":  "nginx:1.0",
+		"labels": map[string]interface{}{"app": "web", "tier": "frontend"},
+	}
+	live, managed, err := Apply(nil, nil, applied, "kubectl", false, now)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if fmt.Sprint(managed) != fmt.Sprint([]api.ManagedFieldsEntry{{Manager: "kubectl", Operation: api.ManagedFieldsOperationApply, Time: now, Fields: []string{"image", "labels.app", "labels.tier"}}}) {
+		t.Fatalf("unexpected managed fields after the first apply: %+v", managed)
+	}
+
+	// The scheduler sets a field of its own and changes one of kubectl's.
+	old := copyMap(live)
+	live["nodeName"] = "node1"
+	live["labels"].(map[string]interface{})["tier"] = "backend"
+	managed = Update(old, live, managed, "scheduler", now)
+	if fields := managed[0].Fields; !reflect.DeepEqual(fields, []string{"image", "labels.app"}) {
+		t.Errorf("expected kubectl to lose labels.tier, still owns %v", fields)
+	}
+	if fields := managed[1].Fields; !reflect.DeepEqual(fields, []string{"labels.tier", "nodeName"}) {
+		t.Errorf("expected the scheduler to own labels.tier and nodeName, owns %v", fields)
+	}
+
+	_, _, err = Apply(copyMap(live), managed, applied, "kubectl", false, now)
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) || !reflect.DeepEqual(conflictErr.Conflicts, []Conflict{{Manager: "scheduler", Field: "labels.tier"}}) {
+		t.Fatalf("expected a conflict over labels.tier, got %v", err)
+	}
+
+	// Forcing takes the field back; dropping the image leaves nodeName alone.
+	applied = map[string]interface{}{"labels": map[string]interface{}{"app": "web", "tier": "frontend"}}
+	live, managed, err = Apply(copyMap(live), managed, applied, "kubectl", true, now)
+	if err != nil {
+		t.Fatalf("forced Apply: %v", err)
+	}
+	want := map[string]interface{}{
+		"labels":   map[string]interface{}{"app": "web", "tier": "frontend"},
+		"nodeName": "node1",
+	}
+	if !reflect.DeepEqual(live, want) {
+		t.Errorf("Apply() = %v, want %v", live, want)
+	}
+	if len(managed) != 2 || !reflect.DeepEqual(managed[1].Fields, []string{"nodeName"}) {
+		t.Errorf("expected the scheduler to keep only nodeName, got %+v", managed)
+	}
+}
+
+func TestApplySharesFieldsWithTheSameValue(t *testing.T) {
+	live := map[string]interface{}{"replicas": 3.0}
+	managed := []api.ManagedFieldsEntry{{Manager: "autoscaler", Operation: api.ManagedFieldsOperationUpdate, Fields: []string{"replicas"}}}
+	_, managed, err := Apply(live, managed, map[string]interface{}{"replicas": 3.0}, "kubectl", false, time.Now())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(managed) != 2 || managed[0].Fields[0] != "replicas" || managed[1].Fields[0] != "replicas" {
+		t.Errorf("expected both managers to own replicas, got %+v", managed)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	obj := map[string]interface{}{
+		"name":   "web",
+		"labels": map[string]interface{}{"app.kubernetes.io/name": "web"},
+		"ports":  []interface{}{80.0},
+	}
+	fields := Fields(obj)
+	if want := []string{`labels."app.kubernetes.io/name"`, "ports"}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("Fields() = %q, want %q", fields, want)
+	}
+	if keys := SplitPath(fields[0]); !reflect.DeepEqual(keys, []string{"labels", "app.kubernetes.io/name"}) {
+		t.Errorf("SplitPath(%q) = %q", fields[0], keys)
+	}
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if sub, ok := v.(map[string]interface{}); ok {
+			v = copyMap(sub)
+		}
+		out[k] = v
+	}
+	return out
+}
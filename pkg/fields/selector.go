@@ -0,0 +1,135 @@
+// Package fields parses and evaluates field selectors such as
+// "status.phase=Running,spec.nodeName!=node1". Only equality ("=" or "==") and
+// inequality ("!=") are supported, and every term must match.
+package fields
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// Set maps selectable field names to their values for one object.
+type Set map[string]string
+
+// Requirement is a single field comparison.
+type Requirement struct {
+	Field    string
+	Value    string
+	NotEqual bool
+}
+
+// Selector is a set of requirements that must all match.
+type Selector []Requirement
+
+// Everything returns a selector that matches all objects.
+func Everything() Selector {
+	return nil
+}
+
+// Parse parses a selector string. The empty string selects everything.
+func Parse(s string) (Selector, error) {
+	var sel Selector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r Requirement
+		var found bool
+		for _, op := range []string{"!=", "==", "="} {
+			var field, value string
+			if field, value, found = strings.Cut(term, op); found {
+				r = Requirement{Field: strings.TrimSpace(field), Value: strings.TrimSpace(value), NotEqual: op == "!="}
+				break
+			}
+		}
+		if !found || r.Field == "" {
+			return nil, fmt.Errorf("invalid field selector %q: expected <field>=<value> or <field>!=<value>, got %q", s, term)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches reports whether the object's fields satisfy every requirement. Fields the
+// object doesn't have compare as the empty string.
+func (s Selector) Matches(fields Set) bool {
+	for _, r := range s {
+		if (fields[r.Field] == r.Value) == r.NotEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate returns an error if the selector names a field not present in allowed.
+func (s Selector) Validate(allowed Set) error {
+	for _, r := range s {
+		if _, ok := allowed[r.Field]; !ok {
+			return fmt.Errorf("field %q is not supported by this field selector", r.Field)
+		}
+	}
+	return nil
+}
+
+// Empty reports whether the selector matches everything.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		op := "="
+		if r.NotEqual {
+			op = "!="
+		}
+		parts[i] = r.Field + op + r.Value
+	}
+	return strings.Join(parts, ",")
+}
+
+// PodFields returns the selectable fields of a pod, under both their Kubernetes names
+// (metadata.name, spec.nodeName, status.phase, ...) and the flat JSON names used by
+// this API (name, nodeName, phase, ...).
+func PodFields(pod *api.Pod) Set {
+	return Set{
+		"metadata.name":      pod.Name,
+		"metadata.namespace": pod.Namespace,
+		"spec.nodeName":      pod.NodeName,
+		"status.phase":       string(pod.Phase),
+		"status.podIP":       pod.PodIP,
+		"name":               pod.Name,
+		"namespace":          pod.Namespace,
+		"nodeName":           pod.NodeName,
+		"phase":              string(pod.Phase),
+		"podIP":              pod.PodIP,
+	}
+}
+
+// NodeFields returns the selectable fields of a node, under their Kubernetes and flat
+// JSON names like PodFields.
+func NodeFields(node *api.Node) Set {
+	unschedulable := strconv.FormatBool(node.Unschedulable)
+	return Set{
+		"metadata.name":      node.Name,
+		"spec.unschedulable": unschedulable,
+		"status":             string(node.Status),
+		"name":               node.Name,
+		"unschedulable":      unschedulable,
+	}
+}
+
+// ObjectMetaFields returns the fields any object can be selected by: its name and
+// namespace.
+func ObjectMetaFields(meta *api.ObjectMeta) Set {
+	return Set{
+		"metadata.name":      meta.Name,
+		"metadata.namespace": meta.Namespace,
+		"name":               meta.Name,
+		"namespace":          meta.Namespace,
+	}
+}
@@ -0,0 +1,48 @@
+package fields
+
+import (
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestSelectorMatchesPod(t *testing.T) {
+	pod := PodFields(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, NodeName: "node1", Phase: api.PodRunning})
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"status.phase=Running", true},
+		{"phase==Running", true},
+		{"status.phase!=Running", false},
+		{"spec.nodeName=node1,metadata.name=web", true},
+		{"spec.nodeName=node2,metadata.name=web", false},
+		{"status.podIP=", true},
+	}
+	for _, tt := range tests {
+		sel, err := Parse(tt.selector)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.selector, err)
+			continue
+		}
+		if got := sel.Matches(pod); got != tt.want {
+			t.Errorf("Parse(%q).Matches(pod) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestParseAndValidateErrors(t *testing.T) {
+	for _, s := range []string{"phase", "=Running"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected an error", s)
+		}
+	}
+	sel, err := Parse("spec.image=nginx")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := sel.Validate(PodFields(&api.Pod{})); err == nil {
+		t.Errorf("Validate: expected an error for an unsupported field")
+	}
+}
@@ -0,0 +1,44 @@
+// Package heartbeat reports a component's liveness to the API server, which lists
+// every component's health under /api/v1/componentstatuses.
+package heartbeat
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// DefaultPeriod is how often components report themselves.
+const DefaultPeriod = 10 * time.Second
+
+// Run reports name as alive every period until ctx is cancelled. Heartbeats go by the
+// real clock, not a scaled one, because the API server judges them by its own.
+func Run(ctx context.Context, client api.Interface, name string, period time.Duration) {
+	status := &api.ComponentStatus{
+		Name:                   name,
+		HeartbeatPeriodSeconds: int(math.Ceil(period.Seconds())),
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	failing := false
+	for {
+		// Log only the first of a run of failures; a down API server would otherwise
+		// fill the log.
+		if err := client.ReportComponentStatus(status); err != nil {
+			if !failing {
+				log.Printf("Failed to report heartbeat for %s: %v", name, err)
+			}
+			failing = true
+		} else {
+			failing = false
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
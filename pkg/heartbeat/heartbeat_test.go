@@ -0,0 +1,44 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+func TestRunReportsUntilCancelled(t *testing.T) {
+	client := fake.NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, client, "scheduler", 10*time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		statuses, err := client.ListComponentStatuses()
+		if err != nil {
+			t.Fatalf("ListComponentStatuses: %v", err)
+		}
+		if len(statuses) == 1 {
+			if statuses[0].Name != "scheduler" || statuses[0].HeartbeatPeriodSeconds != 1 {
+				t.Errorf("unexpected status %+v", statuses[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a heartbeat")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
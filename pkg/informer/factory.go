@@ -0,0 +1,157 @@
+// Package informer shares informers between controllers, so that the controller
+// manager keeps one watch and one cache per resource however many of its controllers
+// follow that resource.
+//
+// Controllers ask the factory for the informers they need while they are built; the
+// manager then starts the factory and waits for its caches before the controllers
+// reconcile anything:
+//
+//	factory := informer.NewSharedInformerFactory(client, 2*time.Second, clk)
+//	nodes := nodelifecycle.NewController(client, recorder, factory)
+//	binder := volumebinder.NewController(client, recorder, factory)
+//	factory.Start(ctx)
+//	factory.WaitForCacheSync(ctx)
+package informer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+)
+
+// NewInformerFunc creates the informer for a resource.
+type NewInformerFunc func(client api.Interface, interval time.Duration) *controller.PollingInformer
+
+// SharedInformerFactory hands out one informer per resource, over every namespace.
+type SharedInformerFactory struct {
+	client   api.Interface
+	interval time.Duration
+	clock    clock.Clock
+
+	mu        sync.Mutex
+	informers map[string]*sharedInformer
+	started   map[string]bool
+}
+
+// NewSharedInformerFactory returns a factory whose informers list or watch through
+// client, relisting every interval when they poll or after a failed watch, as measured
+// by clk.
+func NewSharedInformerFactory(client api.Interface, interval time.Duration, clk clock.Clock) *SharedInformerFactory {
+	return &SharedInformerFactory{
+		client:    client,
+		interval:  interval,
+		clock:     clk,
+		informers: make(map[string]*sharedInformer),
+		started:   make(map[string]bool),
+	}
+}
+
+// Interval returns the interval the factory's informers poll at.
+func (f *SharedInformerFactory) Interval() time.Duration {
+	return f.interval
+}
+
+// Clock returns the clock the factory's informers run on.
+func (f *SharedInformerFactory) Clock() clock.Clock {
+	return f.clock
+}
+
+// InformerFor returns the informer for resource, creating it with newInformer the
+// first time it is asked for.
+func (f *SharedInformerFactory) InformerFor(resource string, newInformer NewInformerFunc) controller.Informer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if inf, ok := f.informers[resource]; ok {
+		return inf
+	}
+	inf := &sharedInformer{PollingInformer: newInformer(f.client, f.interval)}
+	inf.Clock = f.clock
+	f.informers[resource] = inf
+	return inf
+}
+
+// Pods returns the shared informer over every pod.
+func (f *SharedInformerFactory) Pods() controller.Informer {
+	return f.InformerFor("pods", func(client api.Interface, interval time.Duration) *controller.PollingInformer {
+		return controller.NewPodInformer(client, api.NamespaceAll, interval)
+	})
+}
+
+// Nodes returns the shared informer over every node.
+func (f *SharedInformerFactory) Nodes() controller.Informer {
+	return f.InformerFor("nodes", controller.NewNodeInformer)
+}
+
+// Namespaces returns the shared informer over every namespace.
+func (f *SharedInformerFactory) Namespaces() controller.Informer {
+	return f.InformerFor("namespaces", controller.NewNamespaceInformer)
+}
+
+// Deployments returns the shared informer over every deployment.
+func (f *SharedInformerFactory) Deployments() controller.Informer {
+	return f.InformerFor("deployments", func(client api.Interface, interval time.Duration) *controller.PollingInformer {
+		return controller.NewDeploymentInformer(client, api.NamespaceAll, interval)
+	})
+}
+
+// PersistentVolumes returns the shared informer over every persistent volume.
+func (f *SharedInformerFactory) PersistentVolumes() controller.Informer {
+	return f.InformerFor("persistentvolumes", controller.NewPersistentVolumeInformer)
+}
+
+// PersistentVolumeClaims returns the shared informer over every persistent volume claim.
+func (f *SharedInformerFactory) PersistentVolumeClaims() controller.Informer {
+	return f.InformerFor("persistentvolumeclaims", func(client api.Interface, interval time.Duration) *controller.PollingInformer {
+		return controller.NewPersistentVolumeClaimInformer(client, api.NamespaceAll, interval)
+	})
+}
+
+// Start runs every informer asked for so far, and not yet started, until ctx is
+// cancelled. It may be called again for informers asked for later.
+func (f *SharedInformerFactory) Start(ctx context.Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for resource, inf := range f.informers {
+		if f.started[resource] {
+			continue
+		}
+		f.started[resource] = true
+		go inf.Run(ctx)
+	}
+}
+
+// WaitForCacheSync blocks until every informer asked for so far has synced, and
+// reports false if ctx was cancelled first.
+func (f *SharedInformerFactory) WaitForCacheSync(ctx context.Context) bool {
+	f.mu.Lock()
+	informers := make([]controller.Informer, 0, len(f.informers))
+	for _, inf := range f.informers {
+		informers = append(informers, inf)
+	}
+	f.mu.Unlock()
+	return controller.WaitForCacheSync(ctx, informers...)
+}
+
+// sharedInformer is an informer several controllers run. Only the first Run, normally
+// the factory's Start, runs it; the rest wait for their context, so that a controller
+// losing its leader lease doesn't stop the cache for the others.
+type sharedInformer struct {
+	*controller.PollingInformer
+	once sync.Once
+}
+
+// Run runs the informer until ctx is cancelled if it isn't running yet, and otherwise
+// just waits for ctx.
+func (s *sharedInformer) Run(ctx context.Context) {
+	first := false
+	s.once.Do(func() { first = true })
+	if first {
+		s.PollingInformer.Run(ctx)
+		return
+	}
+	<-ctx.Done()
+}
@@ -0,0 +1,60 @@
+package informer
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+)
+
+func TestSharedInformerFactory(t *testing.T) {
+	client := fake.NewClient(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodPending})
+	factory := NewSharedInformerFactory(client, 10*time.Millisecond, clock.RealClock{})
+
+	var lists atomic.Int32
+	newInformer := func(client api.Interface, interval time.Duration) *controller.PollingInformer {
+		return controller.NewPollingInformer(func() ([]interface{}, error) {
+			lists.Add(1)
+			pods, err := client.ListPods(api.NamespaceAll, "")
+			if err != nil {
+				return nil, err
+			}
+			objs := make([]interface{}, 0, len(pods))
+			for i := range pods {
+				objs = append(objs, &pods[i])
+			}
+			return objs, nil
+		}, controller.MetaNamespaceKeyFunc, time.Hour)
+	}
+	first := factory.InformerFor("pods", newInformer)
+	second := factory.InformerFor("pods", newInformer)
+	if first != second {
+		t.Fatal("expected the same informer for the same resource")
+	}
+	var added atomic.Int32
+	for _, inf := range []controller.Informer{first, second} {
+		inf.AddEventHandler(controller.EventHandler{OnAdd: func(obj interface{}) { added.Add(1) }})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx)
+	// Controllers run their informers too; a shared one only runs once.
+	go first.Run(ctx)
+	go second.Run(ctx)
+	if !factory.WaitForCacheSync(ctx) {
+		t.Fatal("WaitForCacheSync returned false")
+	}
+	if got := added.Load(); got != 2 {
+		t.Errorf("expected both handlers to see the pod, got %d adds", got)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := lists.Load(); got != 1 {
+		t.Errorf("expected one list for the resource, got %d", got)
+	}
+}
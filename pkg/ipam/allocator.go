@@ -0,0 +1,123 @@
+// Package ipam hands out pod IPs from a CIDR range.
+package ipam
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Allocator assigns each owner (a pod, keyed by namespace/name) a unique IPv4 address
+// from a CIDR. The network and broadcast addresses are never handed out. Allocation
+// is next-fit: it carries on from the last address it gave out, so a released address
+// is not reused until the rest of the range has been.
+type Allocator struct {
+	mu      sync.Mutex
+	cidr    *net.IPNet
+	base    uint32 // first usable address
+	size    uint32 // number of usable addresses
+	next    uint32 // offset to try first
+	owners  map[string]uint32
+	byIndex map[uint32]string
+}
+
+// NewAllocator returns an allocator for cidr, e.g. "10.244.0.0/16". The range must be
+// IPv4 and leave at least one usable address.
+func NewAllocator(cidr string) (*Allocator, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod CIDR %q: %w", cidr, err)
+	}
+	if ipNet.IP.To4() == nil {
+		return nil, fmt.Errorf("invalid pod CIDR %q: only IPv4 ranges are supported", cidr)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones < 2 {
+		return nil, fmt.Errorf("invalid pod CIDR %q: range has no usable addresses", cidr)
+	}
+	total := uint64(1) << uint(bits-ones)
+	return &Allocator{
+		cidr:    ipNet,
+		base:    binary.BigEndian.Uint32(ipNet.IP.To4()) + 1,
+		size:    uint32(total - 2),
+		owners:  make(map[string]uint32),
+		byIndex: make(map[uint32]string),
+	}, nil
+}
+
+// CIDR returns the range the allocator hands addresses out from.
+func (a *Allocator) CIDR() string {
+	return a.cidr.String()
+}
+
+// Allocate returns owner's address, assigning it the next free one if it has none.
+func (a *Allocator) Allocate(owner string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if i, ok := a.owners[owner]; ok {
+		return a.ip(i), nil
+	}
+	for n := uint32(0); n < a.size; n++ {
+		i := (a.next + n) % a.size
+		if _, used := a.byIndex[i]; used {
+			continue
+		}
+		a.owners[owner] = i
+		a.byIndex[i] = owner
+		a.next = (i + 1) % a.size
+		return a.ip(i), nil
+	}
+	return "", fmt.Errorf("no pod IPs left in %s", a.cidr)
+}
+
+// Reserve records that owner already holds ip, e.g. when rebuilding the allocator from
+// stored pods. It fails if ip is outside the range or held by someone else.
+func (a *Allocator) Reserve(owner, ip string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil || !a.cidr.Contains(parsed) {
+		return fmt.Errorf("IP %q is not in %s", ip, a.cidr)
+	}
+	v := binary.BigEndian.Uint32(parsed)
+	if v < a.base || v-a.base >= a.size {
+		return fmt.Errorf("IP %s is the network or broadcast address of %s", ip, a.cidr)
+	}
+	i := v - a.base
+	if holder, used := a.byIndex[i]; used && holder != owner {
+		return fmt.Errorf("IP %s is already allocated to %s", ip, holder)
+	}
+	if old, ok := a.owners[owner]; ok && old != i {
+		delete(a.byIndex, old)
+	}
+	a.owners[owner] = i
+	a.byIndex[i] = owner
+	return nil
+}
+
+// Release frees owner's address, if it has one.
+func (a *Allocator) Release(owner string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if i, ok := a.owners[owner]; ok {
+		delete(a.owners, owner)
+		delete(a.byIndex, i)
+	}
+}
+
+// Free returns the number of addresses still available.
+func (a *Allocator) Free() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return int(a.size) - len(a.owners)
+}
+
+func (a *Allocator) ip(i uint32) string {
+	b := make(net.IP, 4)
+	binary.BigEndian.PutUint32(b, a.base+i)
+	return b.String()
+}
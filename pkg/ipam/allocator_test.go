@@ -0,0 +1,60 @@
+package ipam
+
+import "testing"
+
+func TestAllocateIsStablePerOwnerAndSkipsReservedAddresses(t *testing.T) {
+	a, err := NewAllocator("10.0.0.0/30")
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+	first, err := a.Allocate("default/web")
+	if err != nil || first != "10.0.0.1" {
+		t.Fatalf("Allocate = %q, %v; want 10.0.0.1", first, err)
+	}
+	if again, _ := a.Allocate("default/web"); again != first {
+		t.Errorf("expected the same owner to keep %s, got %s", first, again)
+	}
+	if second, _ := a.Allocate("default/db"); second != "10.0.0.2" {
+		t.Errorf("second Allocate = %s, want 10.0.0.2", second)
+	}
+	if _, err := a.Allocate("default/cache"); err == nil {
+		t.Fatalf("expected the range to be exhausted")
+	}
+
+	a.Release("default/web")
+	if got, _ := a.Allocate("default/cache"); got != first {
+		t.Errorf("expected the released %s to be reused, got %s", first, got)
+	}
+	if a.Free() != 0 {
+		t.Errorf("Free = %d, want 0", a.Free())
+	}
+}
+
+func TestReserve(t *testing.T) {
+	a, err := NewAllocator("10.244.0.0/24")
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+	if err := a.Reserve("default/web", "10.244.0.1"); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if err := a.Reserve("default/db", "10.244.0.1"); err == nil {
+		t.Errorf("expected reserving a held IP for another owner to fail")
+	}
+	for _, ip := range []string{"10.244.0.0", "10.244.0.255", "10.245.0.1", "bogus"} {
+		if err := a.Reserve("default/db", ip); err == nil {
+			t.Errorf("expected Reserve(%s) to fail", ip)
+		}
+	}
+	if got, _ := a.Allocate("default/db"); got != "10.244.0.2" {
+		t.Errorf("Allocate after Reserve = %s, want 10.244.0.2", got)
+	}
+}
+
+func TestNewAllocatorRejectsBadRanges(t *testing.T) {
+	for _, cidr := range []string{"10.0.0.0", "fd00::/64", "10.0.0.0/31"} {
+		if _, err := NewAllocator(cidr); err == nil {
+			t.Errorf("NewAllocator(%q) succeeded, want an error", cidr)
+		}
+	}
+}
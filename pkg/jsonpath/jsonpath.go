@@ -0,0 +1,347 @@
+// Package jsonpath implements the subset of kubectl's JSONPath template syntax used by
+// kubectl-lite output options:
+//
+//	{.phase}                                  field access
+//	{.items[0].name}, {.items[*].name}        array index and wildcard
+//	{range .items[*]}{.name}{"\n"}{end}       iteration
+//	{"literal"}                               quoted text, with Go escapes
+//
+// Text outside braces is copied to the output unchanged.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// nodeKind identifies the type of a parsed template element.
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	pathNode
+	rangeNode
+)
+
+type node struct {
+	kind  nodeKind
+	text  string // For textNode
+	path  []step // For pathNode and rangeNode
+	nodes []node // Body of a rangeNode
+	raw   string // Original expression, for error messages
+}
+
+// step is a single path segment: a field name, an array index, or a wildcard.
+type step struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Template is a parsed JSONPath template.
+type Template struct {
+	nodes []node
+	// AllowMissingKeys controls whether missing fields produce an error (the default)
+	// or are silently skipped.
+	AllowMissingKeys bool
+}
+
+// Parse parses a template such as "{.items[*].name}".
+func Parse(template string) (*Template, error) {
+	nodes, _, err := parseNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{nodes: nodes}, nil
+}
+
+// parseNodes parses template text until the end of input or, when inRange is set,
+// until the matching {end}. It returns the unparsed remainder after {end}.
+func parseNodes(s string, inRange bool) ([]node, string, error) {
+	var nodes []node
+	for len(s) > 0 {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			nodes = append(nodes, node{kind: textNode, text: s})
+			s = ""
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, node{kind: textNode, text: s[:open]})
+		}
+		closeIdx := findClose(s, open)
+		if closeIdx < 0 {
+			return nil, "", fmt.Errorf("unclosed action in template %q", s)
+		}
+		action := strings.TrimSpace(s[open+1 : closeIdx])
+		s = s[closeIdx+1:]
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("{end} without matching {range}")
+			}
+			return nodes, s, nil
+		case strings.HasPrefix(action, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseNodes(s, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, node{kind: rangeNode, path: path, nodes: body, raw: action})
+			s = rest
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid string literal %s: %w", action, err)
+			}
+			nodes = append(nodes, node{kind: textNode, text: text})
+		default:
+			path, err := parsePath(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, node{kind: pathNode, path: path, raw: action})
+		}
+	}
+	if inRange {
+		return nil, "", fmt.Errorf("{range} is missing {end}")
+	}
+	return nodes, "", nil
+}
+
+// findClose returns the index of the '}' closing the action that starts at open,
+// skipping over braces inside quoted strings.
+func findClose(s string, open int) int {
+	inQuote := false
+	for i := open + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if inQuote {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		case '}':
+			if !inQuote {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parsePath(expr string) ([]step, error) {
+	expr = strings.TrimPrefix(expr, "@")
+	if expr == "" || expr == "." {
+		return nil, nil
+	}
+	var steps []step
+	for len(expr) > 0 {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			field := expr[:end]
+			if field == "" {
+				return nil, fmt.Errorf("empty field name in path")
+			}
+			if field == "*" {
+				steps = append(steps, step{wildcard: true})
+			} else {
+				steps = append(steps, step{field: field})
+			}
+			expr = expr[end:]
+		case '[':
+			end := strings.IndexByte(expr, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in path")
+			}
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, step{wildcard: true})
+			case strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'") && len(inner) >= 2:
+				steps = append(steps, step{field: inner[1 : len(inner)-1]})
+			default:
+				idx, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid array index %q", inner)
+				}
+				steps = append(steps, step{index: idx, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q in path, expected '.' or '['", expr[0])
+		}
+	}
+	return steps, nil
+}
+
+// Execute evaluates the template against data and writes the result to w.
+// data is first converted to its generic JSON form, so struct field names follow
+// their json tags.
+func (t *Template) Execute(w io.Writer, data interface{}) error {
+	generic, err := ToGeneric(data)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := t.execute(&buf, t.nodes, generic); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func (t *Template) execute(buf *bytes.Buffer, nodes []node, current interface{}) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			buf.WriteString(n.text)
+		case pathNode:
+			results, err := evaluate(n.path, current, t.AllowMissingKeys)
+			if err != nil {
+				return fmt.Errorf("evaluating {%s}: %w", n.raw, err)
+			}
+			for i, r := range results {
+				if i > 0 {
+					buf.WriteByte(' ')
+				}
+				buf.WriteString(FormatValue(r))
+			}
+		case rangeNode:
+			results, err := evaluate(n.path, current, t.AllowMissingKeys)
+			if err != nil {
+				return fmt.Errorf("evaluating {range %s}: %w", n.raw, err)
+			}
+			for _, r := range results {
+				if err := t.execute(buf, n.nodes, r); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns every value selected by a bare path expression such as
+// ".nodeName" or "{.items[*].name}". Missing fields yield no results rather than an error.
+func Evaluate(expr string, data interface{}) ([]interface{}, error) {
+	steps, err := parsePath(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(expr), "{"), "}"))
+	if err != nil {
+		return nil, err
+	}
+	generic, err := ToGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+	return evaluate(steps, generic, true)
+}
+
+func evaluate(steps []step, root interface{}, allowMissing bool) ([]interface{}, error) {
+	current := []interface{}{root}
+	for _, s := range steps {
+		var next []interface{}
+		for _, v := range current {
+			switch {
+			case s.wildcard:
+				switch c := v.(type) {
+				case []interface{}:
+					next = append(next, c...)
+				case map[string]interface{}:
+					for _, item := range c {
+						next = append(next, item)
+					}
+				}
+			case s.isIndex:
+				arr, ok := v.([]interface{})
+				if !ok {
+					if allowMissing {
+						continue
+					}
+					return nil, fmt.Errorf("cannot index non-array value")
+				}
+				idx := s.index
+				if idx < 0 {
+					idx += len(arr)
+				}
+				if idx < 0 || idx >= len(arr) {
+					if allowMissing {
+						continue
+					}
+					return nil, fmt.Errorf("array index %d out of bounds", s.index)
+				}
+				next = append(next, arr[idx])
+			default:
+				obj, ok := v.(map[string]interface{})
+				if !ok {
+					if allowMissing {
+						continue
+					}
+					return nil, fmt.Errorf("%s is not found", s.field)
+				}
+				val, exists := obj[s.field]
+				if !exists {
+					if allowMissing {
+						continue
+					}
+					return nil, fmt.Errorf("%s is not found", s.field)
+				}
+				next = append(next, val)
+			}
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// ToGeneric converts a Go value into the map/slice/scalar form produced by
+// encoding/json, so paths can address fields by their JSON names.
+func ToGeneric(data interface{}) (interface{}, error) {
+	switch data.(type) {
+	case string, float64, bool, nil:
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("converting object to JSON: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("converting object to JSON: %w", err)
+	}
+	return generic, nil
+}
+
+// FormatValue renders a value selected by a path: strings verbatim, numbers without
+// exponent notation, and objects or arrays as compact JSON.
+func FormatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(raw)
+	}
+}
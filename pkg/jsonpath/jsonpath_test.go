@@ -0,0 +1,64 @@
+package jsonpath
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTemplateExecute(t *testing.T) {
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "web", "phase": "Running", "port": float64(8080)},
+			map[string]interface{}{"name": "db", "phase": "Pending"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"field", "{.items[0].phase}", "Running"},
+		{"negative index", "{.items[-1].name}", "db"},
+		{"wildcard", "{.items[*].name}", "web db"},
+		{"number", "{.items[0].port}", "8080"},
+		{"range", `{range .items[*]}{.name}={.phase}{"\n"}{end}`, "web=Running\ndb=Pending\n"},
+		{"text", "pods: {.items[*].name}!", "pods: web db!"},
+		{"quoted brace", `{"}"}`, "}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.template, err)
+			}
+			tmpl.AllowMissingKeys = true
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, template := range []string{"{.name", "{range .items[*]}{.name}", "{end}", "{.items[x]}", "{name}"} {
+		if _, err := Parse(template); err == nil {
+			t.Errorf("Parse(%q): expected error", template)
+		}
+	}
+}
+
+func TestMissingKey(t *testing.T) {
+	tmpl, err := Parse("{.nodeName}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{}); err == nil {
+		t.Error("expected error for missing key")
+	}
+}
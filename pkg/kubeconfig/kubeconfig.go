@@ -0,0 +1,224 @@
+// Package kubeconfig reads and writes the kubectl-lite configuration file, which
+// names API servers (clusters), credentials (users), and the pairing of the two
+// (contexts), so commands don't need --apiserver on every invocation.
+//
+// The file lives at ~/.kubelite/config unless KUBELITE_CONFIG points elsewhere:
+//
+//	current-context: local
+//	clusters:
+//	  - name: local
+//	    cluster:
+//	      server: http://localhost:8080
+//	users:
+//	  - name: admin
+//	    user:
+//	      token: secret
+//	contexts:
+//	  - name: local
+//	    context:
+//	      cluster: local
+//	      user: admin
+//	      namespace: default
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVar overrides the default config file location.
+const EnvVar = "KUBELITE_CONFIG"
+
+// Config is the on-disk kubeconfig-lite file.
+type Config struct {
+	CurrentContext string         `yaml:"current-context,omitempty"`
+	Clusters       []NamedCluster `yaml:"clusters,omitempty"`
+	Users          []NamedUser    `yaml:"users,omitempty"`
+	Contexts       []NamedContext `yaml:"contexts,omitempty"`
+}
+
+// Cluster describes how to reach an API server.
+type Cluster struct {
+	Server string `yaml:"server"`
+}
+
+// User holds the credentials presented to an API server.
+type User struct {
+	Token string `yaml:"token,omitempty"`
+}
+
+// Context pairs a cluster with a user and a default namespace.
+type Context struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// NamedCluster is a Cluster with its name.
+type NamedCluster struct {
+	Name    string  `yaml:"name"`
+	Cluster Cluster `yaml:"cluster"`
+}
+
+// NamedUser is a User with its name.
+type NamedUser struct {
+	Name string `yaml:"name"`
+	User User   `yaml:"user"`
+}
+
+// NamedContext is a Context with its name.
+type NamedContext struct {
+	Name    string  `yaml:"name"`
+	Context Context `yaml:"context"`
+}
+
+// Resolved is the fully-resolved connection information for one context.
+type Resolved struct {
+	Context   string
+	Server    string
+	Token     string
+	Namespace string
+}
+
+// DefaultPath returns the config file location: $KUBELITE_CONFIG if set, otherwise
+// ~/.kubelite/config.
+func DefaultPath() string {
+	if p := os.Getenv(EnvVar); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".kubelite", "config")
+	}
+	return filepath.Join(home, ".kubelite", "config")
+}
+
+// Load reads the config at path. A missing file yields an empty Config, not an error.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading kubeconfig %s: %w", path, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save writes cfg to path, creating the parent directory if needed.
+// The file is only readable by the owner since it may hold tokens.
+func Save(path string, cfg *Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("encoding kubeconfig: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating kubeconfig directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing kubeconfig %s: %w", path, err)
+	}
+	return nil
+}
+
+// Resolve looks up contextName (or the current context if empty) and returns the
+// server, token, and namespace it refers to.
+func (c *Config) Resolve(contextName string) (*Resolved, error) {
+	if contextName == "" {
+		contextName = c.CurrentContext
+	}
+	if contextName == "" {
+		return nil, fmt.Errorf("no current context set")
+	}
+	ctx := c.GetContext(contextName)
+	if ctx == nil {
+		return nil, fmt.Errorf("context %q not found", contextName)
+	}
+	cluster := c.GetCluster(ctx.Cluster)
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster %q referenced by context %q not found", ctx.Cluster, contextName)
+	}
+	resolved := &Resolved{Context: contextName, Server: cluster.Server, Namespace: ctx.Namespace}
+	if ctx.User != "" {
+		user := c.GetUser(ctx.User)
+		if user == nil {
+			return nil, fmt.Errorf("user %q referenced by context %q not found", ctx.User, contextName)
+		}
+		resolved.Token = user.Token
+	}
+	return resolved, nil
+}
+
+// GetCluster returns the named cluster, or nil.
+func (c *Config) GetCluster(name string) *Cluster {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name == name {
+			return &c.Clusters[i].Cluster
+		}
+	}
+	return nil
+}
+
+// GetUser returns the named user, or nil.
+func (c *Config) GetUser(name string) *User {
+	for i := range c.Users {
+		if c.Users[i].Name == name {
+			return &c.Users[i].User
+		}
+	}
+	return nil
+}
+
+// GetContext returns the named context, or nil.
+func (c *Config) GetContext(name string) *Context {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i].Context
+		}
+	}
+	return nil
+}
+
+// SetCluster adds or replaces the named cluster.
+func (c *Config) SetCluster(name string, cluster Cluster) {
+	if existing := c.GetCluster(name); existing != nil {
+		*existing = cluster
+		return
+	}
+	c.Clusters = append(c.Clusters, NamedCluster{Name: name, Cluster: cluster})
+}
+
+// SetUser adds or replaces the named user.
+func (c *Config) SetUser(name string, user User) {
+	if existing := c.GetUser(name); existing != nil {
+		*existing = user
+		return
+	}
+	c.Users = append(c.Users, NamedUser{Name: name, User: user})
+}
+
+// SetContext adds or replaces the named context.
+func (c *Config) SetContext(name string, ctx Context) {
+	if existing := c.GetContext(name); existing != nil {
+		*existing = ctx
+		return
+	}
+	c.Contexts = append(c.Contexts, NamedContext{Name: name, Context: ctx})
+}
+
+// UseContext makes name the current context.
+func (c *Config) UseContext(name string) error {
+	if c.GetContext(name) == nil {
+		return fmt.Errorf("context %q not found", name)
+	}
+	c.CurrentContext = name
+	return nil
+}
@@ -0,0 +1,45 @@
+package kubeconfig
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of missing file: %v", err)
+	}
+	cfg.SetCluster("dev", Cluster{Server: "http://localhost:9090"})
+	cfg.SetUser("alice", User{Token: "t0ken"})
+	cfg.SetContext("dev", Context{Cluster: "dev", User: "alice", Namespace: "staging"})
+	if err := cfg.UseContext("dev"); err != nil {
+		t.Fatalf("UseContext: %v", err)
+	}
+	if err := cfg.UseContext("missing"); err == nil {
+		t.Fatal("expected error switching to unknown context")
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	resolved, err := loaded.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := Resolved{Context: "dev", Server: "http://localhost:9090", Token: "t0ken", Namespace: "staging"}
+	if *resolved != want {
+		t.Fatalf("got %+v, want %+v", *resolved, want)
+	}
+
+	loaded.SetContext("broken", Context{Cluster: "nope"})
+	if _, err := loaded.Resolve("broken"); err == nil {
+		t.Fatal("expected error for context with unknown cluster")
+	}
+}
@@ -0,0 +1,51 @@
+package kubelet
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// injectFaults runs before each pod sync and applies the faults chaos mode asks of
+// the kubelet: crashing (and later restarting) and flapping the node's status. It
+// returns false if ctx was cancelled while the kubelet was down.
+func (k *Kubelet) injectFaults(ctx context.Context) bool {
+	if crash, downtime := k.Chaos.CrashKubelet(); crash {
+		log.Printf("[%s] Chaos: kubelet crashed, restarting in %v", k.NodeName, downtime)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-k.Clock.After(downtime):
+		}
+		k.restart()
+		return true
+	}
+
+	if k.flapUntil.IsZero() {
+		if flap, duration := k.Chaos.FlapNode(); flap {
+			log.Printf("[%s] Chaos: node flapping to NotReady for %v", k.NodeName, duration)
+			k.flapUntil = k.Clock.Now().Add(duration)
+			if !k.syncNodeStatus() {
+				k.flapUntil = time.Time{}
+			}
+		}
+	} else if !k.Clock.Now().Before(k.flapUntil) {
+		// Reported Ready again now, or by a later syncNodeStatus if this one fails.
+		k.flapUntil = time.Time{}
+		k.syncNodeStatus()
+	}
+	return true
+}
+
+// restart simulates the kubelet process starting over: everything it only kept in
+// memory, such as pulled images and pull back-offs, is gone, and it registers its
+// node again, which also ends any flap.
+func (k *Kubelet) restart() {
+	k.pullsMu.Lock()
+	k.Images = newImageManager(k.Recorder, k.Clock, k.pulls.Delay, k.pulls.FailureRate, k.pulls.BackOff, k.pulls.Preloaded, k.pulls.PrivateRegistries)
+	k.pullsMu.Unlock()
+	k.flapUntil = time.Time{}
+	if err := k.registerNode(); err != nil {
+		log.Printf("[%s] Error re-registering node after restart: %v", k.NodeName, err)
+	}
+}
@@ -0,0 +1,89 @@
+package kubelet
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func newChaosKubelet(t *testing.T, cfg chaos.Config) (*Kubelet, *fake.Client, *clock.FakeClock) {
+	t.Helper()
+	client := fake.NewClient()
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clk)
+	k.Chaos = chaos.NewWithSource(cfg, rand.NewSource(1))
+	if err := k.registerNode(); err != nil {
+		t.Fatalf("registerNode: %v", err)
+	}
+	return k, client, clk
+}
+
+func nodeStatus(t *testing.T, client *fake.Client) api.NodeStatus {
+	t.Helper()
+	node, err := client.GetNode("node1")
+	if err != nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	return node.Status
+}
+
+func TestChaosNodeFlap(t *testing.T) {
+	k, client, clk := newChaosKubelet(t, chaos.Config{NodeFlapRate: 1, NodeFlapDuration: 20 * time.Second})
+	ctx := context.Background()
+
+	k.injectFaults(ctx)
+	if got := nodeStatus(t, client); got != api.NodeNotReady {
+		t.Fatalf("expected the node to flap to NotReady, got %s", got)
+	}
+	clk.Step(10 * time.Second)
+	k.injectFaults(ctx)
+	if got := nodeStatus(t, client); got != api.NodeNotReady {
+		t.Errorf("expected the node to stay NotReady until the flap ends, got %s", got)
+	}
+	clk.Step(10 * time.Second)
+	k.injectFaults(ctx)
+	if got := nodeStatus(t, client); got != api.NodeReady {
+		t.Errorf("expected the node to be Ready again after the flap, got %s", got)
+	}
+}
+
+func TestChaosKubeletCrashRestarts(t *testing.T) {
+	k, client, clk := newChaosKubelet(t, chaos.Config{KubeletCrashRate: 1, KubeletDowntime: 10 * time.Second})
+	images := k.Images
+	node, _ := client.GetNode("node1")
+	api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionFalse})
+	if err := client.UpdateNode(node); err != nil {
+		t.Fatalf("UpdateNode: %v", err)
+	}
+
+	done := make(chan bool, 1)
+	go func() { done <- k.injectFaults(context.Background()) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for clk.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the crashed kubelet to wait out its downtime")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("expected the kubelet to stay down until its downtime passed")
+	default:
+	}
+	clk.Step(10 * time.Second)
+	if !<-done {
+		t.Fatal("expected the kubelet to come back up")
+	}
+	if k.Images == images {
+		t.Error("expected the restart to drop the image cache")
+	}
+	if got := nodeStatus(t, client); got != api.NodeReady {
+		t.Errorf("expected the restarted kubelet to register its node Ready, got %s", got)
+	}
+}
@@ -0,0 +1,168 @@
+package kubelet
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// checkpointFile is the file, under the kubelet's root directory, where it keeps what
+// it knows of the pods it runs. A kubelet that restarts reads it to pick up the
+// containers that kept running without it, instead of starting them over:
+//
+//	{"pods": [{"namespace": "default", "name": "web", "uid": "...",
+//	           "started": "2024-01-01T00:00:00Z", "restartCount": 2, ...}]}
+const checkpointFile = "pod-state.json"
+
+// podCheckpoint is the content of checkpointFile.
+type podCheckpoint struct {
+	Pods []checkpointedPod `json:"pods"`
+}
+
+// checkpointedPod is a pod the runtime runs. The simulated runtime has no container
+// IDs of its own; the pod's UID tells its container apart from that of a pod
+// recreated under the same name.
+type checkpointedPod struct {
+	Namespace    string            `json:"namespace"`
+	Name         string            `json:"name"`
+	UID          string            `json:"uid,omitempty"`
+	Started      time.Time         `json:"started"` // When the current run of its container started
+	RestartCount int               `json:"restartCount,omitempty"`
+	Mounts       map[string]string `json:"mounts,omitempty"`
+	// BackOff is the container's restart back-off, if it has exited and crashed
+	// before.
+	BackOff *checkpointedBackOff `json:"backOff,omitempty"`
+}
+
+// checkpointedBackOff is a crashBackOff.
+type checkpointedBackOff struct {
+	Delay time.Duration `json:"delay"`
+	Until time.Time     `json:"until,omitempty"`
+}
+
+func (k *Kubelet) checkpointPath() string {
+	return filepath.Join(k.rootDir, checkpointFile)
+}
+
+// saveCheckpoint writes the pods the runtime runs to the checkpoint, if they changed
+// since it was last written. The file is replaced whole, so that a kubelet stopped
+// halfway through never leaves half of one behind.
+func (k *Kubelet) saveCheckpoint() {
+	var checkpoint podCheckpoint
+	k.runtime.mu.Lock()
+	for key, p := range k.runtime.pods {
+		c := checkpointedPod{
+			Namespace:    p.pod.Namespace,
+			Name:         p.pod.Name,
+			UID:          p.pod.UID,
+			Started:      p.started.UTC(),
+			RestartCount: p.pod.RestartCount,
+			Mounts:       p.mounts,
+		}
+		if b := k.crashBackOffs[key]; b != nil {
+			c.BackOff = &checkpointedBackOff{Delay: b.delay, Until: b.until.UTC()}
+		}
+		checkpoint.Pods = append(checkpoint.Pods, c)
+	}
+	k.runtime.mu.Unlock()
+	// Sorted, so that the checkpoint of the same pods is always written the same.
+	sort.Slice(checkpoint.Pods, func(i, j int) bool {
+		a, b := checkpoint.Pods[i], checkpoint.Pods[j]
+		return podKey(a.Namespace, a.Name) < podKey(b.Namespace, b.Name)
+	})
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil || bytes.Equal(data, k.lastCheckpoint) {
+		return
+	}
+	if err := writeFileAtomic(k.checkpointPath(), data); err != nil {
+		log.Printf("[%s] Error writing the pod checkpoint: %v", k.NodeName, err)
+		return
+	}
+	k.lastCheckpoint = data
+}
+
+// writeFileAtomic writes data to path by way of a temporary file renamed over it.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// restoreCheckpoint takes the pods in the checkpoint back into the runtime as the
+// kubelet starts, reconciled against the API server: a pod is adopted, with its
+// container's start time and restart back-off, only if it is still bound to the node
+// under the same UID, not terminating, and Scheduled or Running. The containers of
+// any other pods in it, which were deleted or recreated while the kubelet was down,
+// are left stopped. If the API server can't be asked, the checkpoint is ignored and
+// running pods are taken back as if they had just started.
+func (k *Kubelet) restoreCheckpoint() {
+	data, err := os.ReadFile(k.checkpointPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[%s] Error reading the pod checkpoint: %v", k.NodeName, err)
+		}
+		return
+	}
+	var checkpoint podCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		log.Printf("[%s] Ignoring the pod checkpoint, which can't be read: %v", k.NodeName, err)
+		return
+	}
+	pods, err := k.APIClient.ListPodsMatching(api.NamespaceAll, "", "spec.nodeName="+k.NodeName)
+	if err != nil {
+		log.Printf("[%s] Ignoring the pod checkpoint, which can't be reconciled with the API server: %v", k.NodeName, err)
+		return
+	}
+	bound := make(map[string]*api.Pod, len(pods))
+	for i := range pods {
+		if pods[i].NodeName == k.NodeName {
+			bound[podKey(pods[i].Namespace, pods[i].Name)] = &pods[i]
+		}
+	}
+
+	for _, c := range checkpoint.Pods {
+		key := podKey(c.Namespace, c.Name)
+		pod := bound[key]
+		var reason string
+		switch {
+		case pod == nil:
+			reason = "is no longer bound to the node"
+		case pod.UID != c.UID:
+			reason = "was recreated"
+		case pod.DeletionTimestamp != nil:
+			reason = "is terminating"
+		case pod.Phase != api.PodScheduled && pod.Phase != api.PodRunning:
+			reason = "is " + string(pod.Phase)
+		}
+		if reason != "" {
+			log.Printf("[%s] Not restoring checkpointed pod %s, which %s; its container stays stopped.", k.NodeName, key, reason)
+			continue
+		}
+		k.runtime.adopt(pod, c.Mounts, c.Started)
+		if c.BackOff != nil {
+			k.crashBackOffs[key] = &crashBackOff{delay: c.BackOff.Delay, until: c.BackOff.Until}
+		}
+		log.Printf("[%s] Restored pod %s from the checkpoint, running since %s (restart %d).", k.NodeName, key, c.Started.Format(time.RFC3339), c.RestartCount)
+	}
+	k.saveCheckpoint()
+}
@@ -0,0 +1,85 @@
+package kubelet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestCheckpointRestoresPodsAfterRestart(t *testing.T) {
+	pod := func(namespace, name string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace},
+			Image:      "nginx:1.25",
+			NodeName:   "node1",
+			Phase:      api.PodScheduled,
+		}
+	}
+	client := fake.NewClient(pod(DefaultNamespace, "web"), pod("kube-system", "dns"), pod(DefaultNamespace, "old"), pod(DefaultNamespace, "gone"))
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+	k := NewKubelet(client, "node1", "localhost:10250", dir, time.Second, ImagePullOptions{}, clk)
+	if err := k.syncPods(); err != nil {
+		t.Fatalf("syncPods: %v", err)
+	}
+	started, _ := k.runtime.startedAt(DefaultNamespace, "web")
+	k.crashBackOffs[podKey(DefaultNamespace, "web")] = &crashBackOff{delay: 20 * time.Second}
+	k.saveCheckpoint()
+
+	// While the kubelet is down, one pod is recreated under the same name and another
+	// is removed.
+	clk.Step(time.Minute)
+	pods, err := client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var after []api.Pod
+	for _, p := range pods {
+		switch p.Name {
+		case "old":
+			p.UID = "recreated"
+		case "gone":
+			continue
+		}
+		after = append(after, p)
+	}
+	client.PrependReactor("list", "pods", func(fake.Action) (bool, interface{}, error) {
+		return true, after, nil
+	})
+	restarted := NewKubelet(client, "node1", "localhost:10250", dir, time.Second, ImagePullOptions{}, clk)
+	restarted.restoreCheckpoint()
+	restored := map[string]bool{}
+	for _, p := range restarted.runtime.list() {
+		restored[podKey(p.Namespace, p.Name)] = true
+	}
+	if len(restored) != 2 || !restored[podKey(DefaultNamespace, "web")] || !restored[podKey("kube-system", "dns")] {
+		t.Fatalf("expected only web and kube-system/dns to be restored, got %v", restored)
+	}
+	if got, _ := restarted.runtime.startedAt(DefaultNamespace, "web"); !got.Equal(started) {
+		t.Errorf("expected web to keep running since %v, got %v", started, got)
+	}
+	if b := restarted.crashBackOffs[podKey(DefaultNamespace, "web")]; b == nil || b.delay != 20*time.Second {
+		t.Errorf("expected web's restart back-off to be restored, got %+v", b)
+	}
+	if _, starts, _ := restarted.runtime.counts(); starts != 0 {
+		t.Errorf("expected the restored pod not to be started again, got %d starts", starts)
+	}
+	if logs, _, _ := restarted.runtime.logs(DefaultNamespace, "web", api.PodLogOptions{}); len(logs) != 2 || !strings.Contains(logs[0], "Started container") || !strings.Contains(logs[1], "still running since 2024-01-01T00:00:00Z") {
+		t.Errorf("expected web's logs to carry on from before the restart, got %q", logs)
+	}
+
+	// The first sync starts the recreated pod afresh and keeps web running.
+	if err := restarted.syncPods(); err != nil {
+		t.Fatalf("syncPods: %v", err)
+	}
+	if _, starts, _ := restarted.runtime.counts(); starts != 1 {
+		t.Errorf("expected only the recreated pod to start, got %d starts", starts)
+	}
+	if got, _ := restarted.runtime.startedAt(DefaultNamespace, "web"); !got.Equal(started) {
+		t.Errorf("expected the sync to leave web running since %v, got %v", started, got)
+	}
+}
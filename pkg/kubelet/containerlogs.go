@@ -0,0 +1,191 @@
+package kubelet
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// The runtime writes the output of pods' containers to files under the kubelet's root
+// directory, as a container runtime writes them to /var/log/pods:
+//
+//	<rootDir>/logs/<namespace>_<name>_<uid>/<restart>.log     output of the run after restart <restart>
+//	<rootDir>/logs/<namespace>_<name>_<uid>/<restart>.log.1   its older output, then .log.2, and so on
+//
+// A run's file is rotated once it would grow past the maximum size, and only the
+// newest maximum number of files of a run are kept. The files of the current run and
+// the one before it, for logs of the previous container, are kept until the pod
+// stops.
+const (
+	DefaultContainerLogMaxSize  = 10 << 20
+	DefaultContainerLogMaxFiles = 5
+)
+
+// containerLog is the log file of the current run of a pod's container.
+type containerLog struct {
+	dir  string // The pod's log directory
+	run  int    // The pod's restart count when the run started
+	file *os.File
+	size int64
+}
+
+// podLogDir returns the directory holding pod's logs under logDir. Pods are keyed by
+// UID, as well as name, so that a pod recreated under the same name starts afresh.
+func podLogDir(logDir string, pod *api.Pod) string {
+	id := pod.Namespace + "_" + pod.Name
+	if pod.UID != "" {
+		id += "_" + pod.UID
+	}
+	return filepath.Join(logDir, id)
+}
+
+func runLogPath(dir string, run int) string {
+	return filepath.Join(dir, strconv.Itoa(run)+".log")
+}
+
+// openContainerLog opens the log file of run run in dir, appending to what it holds
+// already, such as the output of a pod adopted after the kubelet restarted. The files
+// of runs before the previous one are removed.
+func openContainerLog(dir string, run int) (*containerLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(runLogPath(dir, run), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		prefix, _, _ := strings.Cut(e.Name(), ".")
+		if n, err := strconv.Atoi(prefix); err == nil && n < run-1 {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+	return &containerLog{dir: dir, run: run, file: f, size: info.Size()}, nil
+}
+
+// write appends line to the log, first rotating the file if line would take it past
+// maxSize, keeping at most maxFiles files of the run.
+func (l *containerLog) write(line string, maxSize int64, maxFiles int) error {
+	n := int64(len(line)) + 1
+	if l.size > 0 && l.size+n > maxSize {
+		if err := l.rotate(maxFiles); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(l.file, line); err != nil {
+		return err
+	}
+	l.size += n
+	return nil
+}
+
+// rotate moves the run's file to <run>.log.1, and each older one a number up, and
+// starts a new file. Files numbered maxFiles-1 and beyond are removed.
+func (l *containerLog) rotate(maxFiles int) error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	current := runLogPath(l.dir, l.run)
+	for _, i := range rotatedLogs(l.dir, l.run) {
+		if i >= maxFiles-1 {
+			os.Remove(fmt.Sprintf("%s.%d", current, i))
+		} else {
+			os.Rename(fmt.Sprintf("%s.%d", current, i), fmt.Sprintf("%s.%d", current, i+1))
+		}
+	}
+	if maxFiles > 1 {
+		if err := os.Rename(current, current+".1"); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(current, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	l.file, l.size = f, 0
+	return nil
+}
+
+// rotatedLogs returns the numbers of the rotated files of run in dir, highest, and
+// so oldest, first.
+func rotatedLogs(dir string, run int) []int {
+	paths, _ := filepath.Glob(runLogPath(dir, run) + ".*")
+	var numbers []int
+	for _, p := range paths {
+		if i, err := strconv.Atoi(p[strings.LastIndexByte(p, '.')+1:]); err == nil {
+			numbers = append(numbers, i)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(numbers)))
+	return numbers
+}
+
+func (l *containerLog) close() {
+	l.file.Close()
+}
+
+// readRunLogs returns the output of run in dir that opts selects, oldest line first.
+// A run with no files has no output.
+func readRunLogs(dir string, run int, opts api.PodLogOptions, now time.Time) ([]string, error) {
+	current := runLogPath(dir, run)
+	var paths []string
+	for _, i := range rotatedLogs(dir, run) {
+		paths = append(paths, fmt.Sprintf("%s.%d", current, i))
+	}
+	paths = append(paths, current)
+
+	var since time.Time
+	if opts.SinceSeconds > 0 {
+		since = now.Add(-time.Duration(opts.SinceSeconds) * time.Second)
+	}
+	var lines []string
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !since.IsZero() && logTime(line).Before(since) {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.TailLines > 0 && len(lines) > opts.TailLines {
+		lines = lines[len(lines)-opts.TailLines:]
+	}
+	return lines, nil
+}
+
+// logTime returns when a line of output was written, from the timestamp logf puts
+// before it. A line without one is from the beginning of time.
+func logTime(line string) time.Time {
+	stamp, _, _ := strings.Cut(line, " ")
+	t, _ := time.Parse(time.RFC3339, stamp)
+	return t
+}
@@ -0,0 +1,75 @@
+package kubelet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestContainerLogRotation(t *testing.T) {
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	logDir := t.TempDir()
+	r := newPodRuntime(clk, logDir)
+	// Each line is 29 bytes with its timestamp, so a file holds three.
+	r.setLogLimits(100, 3)
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: DefaultNamespace, UID: "uid-1"}, Image: "nginx"}
+	r.start(pod, nil, nil)
+	for i := 1; i <= 10; i++ {
+		clk.Step(time.Second)
+		r.input(DefaultNamespace, "web", fmt.Sprintf("line %02d", i))
+	}
+
+	dir := filepath.Join(logDir, "default_web_uid-1")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, e := range entries {
+		files = append(files, e.Name())
+	}
+	if strings.Join(files, ",") != "0.log,0.log.1,0.log.2" {
+		t.Errorf("expected the run's log to be rotated and kept to 3 files, got %v", files)
+	}
+
+	logs, _, err := r.logs(DefaultNamespace, "web", api.PodLogOptions{})
+	if err != nil || len(logs) != 9 || !strings.HasSuffix(logs[0], "line 02") || !strings.HasSuffix(logs[8], "line 10") {
+		t.Errorf("expected lines 2 to 10 to be kept, got %q, %v", logs, err)
+	}
+	if logs, _, _ := r.logs(DefaultNamespace, "web", api.PodLogOptions{TailLines: 2}); len(logs) != 2 || !strings.HasSuffix(logs[0], "line 09") {
+		t.Errorf("expected the last 2 lines, got %q", logs)
+	}
+	if logs, _, _ := r.logs(DefaultNamespace, "web", api.PodLogOptions{SinceSeconds: 3}); len(logs) != 4 || !strings.HasSuffix(logs[0], "line 07") {
+		t.Errorf("expected the lines of the last 3 seconds, got %q", logs)
+	}
+	if _, _, err := r.logs(DefaultNamespace, "web", api.PodLogOptions{Previous: true}); !errors.Is(err, errNoPreviousLogs) {
+		t.Errorf("expected no previous logs before a restart, got %v", err)
+	}
+
+	// A restart starts a new file; the previous run's are kept, and those before it
+	// removed.
+	for restart := 1; restart <= 2; restart++ {
+		pod.RestartCount = restart
+		r.restart(pod)
+		r.input(DefaultNamespace, "web", fmt.Sprintf("run %d", restart))
+	}
+	previous, _, err := r.logs(DefaultNamespace, "web", api.PodLogOptions{Previous: true})
+	if err != nil || len(previous) != 2 || !strings.HasSuffix(previous[1], "run 1") {
+		t.Errorf("expected the logs of the previous run, got %q, %v", previous, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "0.log")); !os.IsNotExist(err) {
+		t.Errorf("expected the logs of the first run to be removed, got %v", err)
+	}
+
+	r.stop(DefaultNamespace, "web")
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected the pod's logs to be removed when it stops, got %v", err)
+	}
+}
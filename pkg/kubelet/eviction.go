@@ -0,0 +1,122 @@
+package kubelet
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// burstMemoryUsage is how much memory a simulated pod uses beyond what it requests, as
+// far as its memory limit allows. A pod with neither uses just this.
+const burstMemoryUsage = 64 << 20
+
+// DefaultEvictionThreshold is the memory a node keeps available by evicting pods.
+const DefaultEvictionThreshold = 100 << 20
+
+// qosRanks orders the QoS classes for eviction: lowest first.
+var qosRanks = map[api.PodQOSClass]int{
+	api.PodQOSBestEffort: 0,
+	api.PodQOSBurstable:  1,
+	api.PodQOSGuaranteed: 2,
+}
+
+// podMemory is what a running pod requests and, in the simulation, uses.
+type podMemory struct {
+	pod            api.Pod
+	qos            api.PodQOSClass
+	request, usage int64
+}
+
+// memoryOf works out the memory pod requests and uses. Requests, limits, and overhead
+// were validated when the pod was created, so they parse.
+func memoryOf(pod *api.Pod) podMemory {
+	quantity := func(list api.ResourceList) (int64, bool) {
+		s, ok := list[api.ResourceMemory]
+		if !ok {
+			return 0, false
+		}
+		v, _ := api.ParseResourceQuantity(api.ResourceMemory, s)
+		return v, true
+	}
+	m := podMemory{pod: *pod, qos: pod.QOSClass}
+	if m.qos == "" {
+		m.qos = api.GetPodQOS(pod)
+	}
+	m.request, _ = quantity(pod.Resources.Requests)
+	m.usage = m.request + burstMemoryUsage
+	if limit, ok := quantity(pod.Resources.Limits); ok && m.usage > limit {
+		m.usage = limit
+	}
+	overhead, _ := quantity(pod.Overhead)
+	m.request += overhead
+	m.usage += overhead
+	return m
+}
+
+// evictPods evicts running pods, lowest QoS class first, while the memory they use
+// leaves less than EvictionThreshold of the node's MemoryCapacity available. Within a
+// class, the pod using the most memory beyond its request goes first. An evicted pod
+// fails with reason Evicted, so that its controller replaces it on another node.
+func (k *Kubelet) evictPods() {
+	if k.MemoryCapacity <= 0 {
+		return
+	}
+	var pods []podMemory
+	available := k.MemoryCapacity
+	for _, pod := range k.runtime.list() {
+		m := memoryOf(&pod)
+		pods = append(pods, m)
+		available -= m.usage
+	}
+	if available >= k.EvictionThreshold {
+		k.memoryPressure = false
+		return
+	}
+	if !k.memoryPressure {
+		k.memoryPressure = true
+		log.Printf("[%s] Memory available %s is below the eviction threshold %s", k.NodeName, formatBytes(available), formatBytes(k.EvictionThreshold))
+		node := &api.Node{ObjectMeta: api.ObjectMeta{Name: k.NodeName}}
+		k.Recorder.Eventf(node, api.EventTypeWarning, "EvictionThresholdMet", "Attempting to reclaim memory: %s available, threshold %s", formatBytes(available), formatBytes(k.EvictionThreshold))
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		if ri, rj := qosRanks[pods[i].qos], qosRanks[pods[j].qos]; ri != rj {
+			return ri < rj
+		}
+		return pods[i].usage-pods[i].request > pods[j].usage-pods[j].request
+	})
+	for _, m := range pods {
+		if available >= k.EvictionThreshold {
+			break
+		}
+		msg := fmt.Sprintf("The node was low on resource: memory. Threshold quantity: %s, available: %s. Pod was using %s, request is %s.",
+			formatBytes(k.EvictionThreshold), formatBytes(available), formatBytes(m.usage), formatBytes(m.request))
+		if k.evictPod(m, msg) {
+			available += m.usage
+		}
+	}
+}
+
+// evictPod stops a running pod and marks it Failed, reporting whether it could.
+func (k *Kubelet) evictPod(m podMemory, msg string) bool {
+	pod := &m.pod
+	updatedPod := *pod
+	updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+	updatedPod.Phase = api.PodFailed
+	api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: api.PodReasonEvicted, Message: msg})
+	if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+		log.Printf("[%s] Error marking evicted pod %s as Failed: %v", k.NodeName, pod.Name, err)
+		return false
+	}
+	k.runtime.stop(pod.Namespace, pod.Name)
+	log.Printf("[%s] Evicted %s pod %s: %s", k.NodeName, m.qos, pod.Name, msg)
+	k.Recorder.Event(&updatedPod, api.EventTypeWarning, api.PodReasonEvicted, msg)
+	return true
+}
+
+// formatBytes formats a quantity of memory in Mi, as people usually write it.
+func formatBytes(n int64) string {
+	return fmt.Sprintf("%dMi", n>>20)
+}
@@ -0,0 +1,67 @@
+package kubelet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func runningPod(name string, qos api.PodQOSClass, resources api.ResourceRequirements) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: DefaultNamespace},
+		Image:      "nginx:1.25",
+		NodeName:   "node1",
+		Phase:      api.PodRunning,
+		Resources:  resources,
+		QOSClass:   qos,
+	}
+}
+
+func TestEvictPodsByQOSClass(t *testing.T) {
+	tests := []struct {
+		capacity string
+		evicted  []string
+	}{
+		{capacity: "1Gi"},
+		{capacity: "512Mi", evicted: []string{"best-effort"}},
+		{capacity: "400Mi", evicted: []string{"best-effort", "burstable"}},
+	}
+	for _, tt := range tests {
+		// The pods use 256Mi, 128Mi, and 64Mi: the Guaranteed pod its limit, and the
+		// others their request plus burstMemoryUsage.
+		guaranteed := api.ResourceList{"cpu": "1", "memory": "256Mi"}
+		client := fake.NewClient(
+			runningPod("guaranteed", api.PodQOSGuaranteed, api.ResourceRequirements{Requests: guaranteed, Limits: guaranteed}),
+			runningPod("burstable", api.PodQOSBurstable, api.ResourceRequirements{Requests: api.ResourceList{"memory": "64Mi"}}),
+			runningPod("best-effort", api.PodQOSBestEffort, api.ResourceRequirements{}),
+		)
+		k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clock.NewFakeClock(time.Now()))
+		k.MemoryCapacity, _ = api.ParseResourceQuantity(api.ResourceMemory, tt.capacity)
+		if err := k.syncPods(); err != nil {
+			t.Fatalf("syncPods: %v", err)
+		}
+		k.evictPods()
+
+		evicted := make(map[string]bool)
+		for _, name := range tt.evicted {
+			evicted[name] = true
+		}
+		for _, name := range []string{"guaranteed", "burstable", "best-effort"} {
+			pod, err := client.GetPod(DefaultNamespace, name)
+			if err != nil {
+				t.Fatalf("GetPod %s: %v", name, err)
+			}
+			cond := api.GetPodCondition(pod, api.PodReadyCondition)
+			gotEvicted := pod.Phase == api.PodFailed && cond != nil && cond.Reason == api.PodReasonEvicted
+			if gotEvicted != evicted[name] {
+				t.Errorf("capacity %s: pod %s evicted = %v, want %v (phase %s)", tt.capacity, name, gotEvicted, evicted[name], pod.Phase)
+			}
+			if running := k.runtime.running(pod); running == evicted[name] {
+				t.Errorf("capacity %s: pod %s running = %v after eviction", tt.capacity, name, running)
+			}
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package kubelet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+)
+
+// execStreams are where a command run in a pod reads its input and writes its output.
+type execStreams struct {
+	stdin          io.Reader // nil for none
+	stdout, stderr io.Writer
+	// size returns the size of the command's terminal, and false if it has none.
+	size func() (streaming.TerminalSize, bool)
+}
+
+// execCommand runs command in pod's simulated container, as runCommand does, and
+// returns its output.
+func execCommand(pod *api.Pod, mounts map[string]string, connect connectFunc, command []string) api.ExecResult {
+	var stdout, stderr strings.Builder
+	exitCode := runCommand(pod, mounts, connect, command, execStreams{stdout: &stdout, stderr: &stderr})
+	return api.ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: exitCode}
+}
+
+// runCommand runs command in pod's simulated container, whose filesystem is just the
+// pod's volume mounts, given by host path per mount path, and returns its exit code.
+// Relative paths are taken from the pod's working directory. Only a handful of
+// commands exist:
+//
+//	echo ARGS...   print ARGS
+//	hostname       print the pod's name
+//	pwd            print the working directory
+//	env            print the container's environment
+//	id, whoami     print the user the container runs as
+//	ls [PATH...]   list directories in volume mounts; with no PATH, the working
+//	               directory, or the mount paths when that is /
+//	cat [PATH...]  print files in volume mounts; with no PATH, the input
+//	touch PATH...  create empty files in volume mounts; the root filesystem keeps
+//	               nothing written to it, and refuses writes if it is read-only
+//	stty size      print the terminal's height and width
+//	true, false    exit 0 or 1
+//	nc [-z] [-v] HOST PORT
+//	               connect to PORT on HOST, a service name or pod IP, through connect
+//
+// Any other command fails with exit code 127, as a missing executable does.
+func runCommand(pod *api.Pod, mounts map[string]string, connect connectFunc, command []string, s execStreams) int {
+	stdout, stderr := s.stdout, s.stderr
+	exitCode := 0
+	if len(command) == 0 {
+		fmt.Fprintln(stderr, "no command given")
+		return 126
+	}
+	workingDir := pod.WorkingDir
+	if workingDir == "" {
+		workingDir = "/"
+	}
+	abs := func(p string) string {
+		if path.IsAbs(p) {
+			return p
+		}
+		return path.Join(workingDir, p)
+	}
+	args := command[1:]
+	switch command[0] {
+	case "echo":
+		fmt.Fprintln(stdout, strings.Join(args, " "))
+	case "hostname":
+		fmt.Fprintln(stdout, pod.Name)
+	case "pwd":
+		fmt.Fprintln(stdout, workingDir)
+	case "env":
+		fmt.Fprintf(stdout, "HOSTNAME=%s\nPWD=%s\n", pod.Name, workingDir)
+	case "id":
+		uid := runAsUser(pod)
+		if uid == 0 {
+			fmt.Fprintln(stdout, "uid=0(root) gid=0(root) groups=0(root)")
+		} else {
+			fmt.Fprintf(stdout, "uid=%d gid=%d groups=%d\n", uid, uid, uid)
+		}
+	case "whoami":
+		if uid := runAsUser(pod); uid != 0 {
+			fmt.Fprintf(stderr, "whoami: cannot find name for user ID %d\n", uid)
+			exitCode = 1
+		} else {
+			fmt.Fprintln(stdout, "root")
+		}
+	case "true":
+	case "false":
+		exitCode = 1
+	case "ls":
+		if len(args) == 0 && workingDir != "/" {
+			args = []string{"."}
+		}
+		if len(args) == 0 {
+			paths := make([]string, 0, len(mounts))
+			for mountPath := range mounts {
+				paths = append(paths, mountPath)
+			}
+			sort.Strings(paths)
+			for _, p := range paths {
+				fmt.Fprintln(stdout, p)
+			}
+			break
+		}
+		for _, arg := range args {
+			hostPath, ok := resolveMountPath(mounts, abs(arg))
+			var entries []os.DirEntry
+			var err error
+			if ok {
+				entries, err = os.ReadDir(hostPath)
+			}
+			if !ok || (err != nil && os.IsNotExist(err)) {
+				fmt.Fprintf(stderr, "ls: cannot access '%s': No such file or directory\n", arg)
+				exitCode = 2
+				continue
+			}
+			if err != nil {
+				// Not a directory: list the file itself.
+				fmt.Fprintln(stdout, arg)
+				continue
+			}
+			for _, entry := range entries {
+				fmt.Fprintln(stdout, entry.Name())
+			}
+		}
+	case "cat":
+		if len(args) == 0 && s.stdin != nil {
+			io.Copy(stdout, s.stdin)
+		}
+		for _, arg := range args {
+			hostPath, ok := resolveMountPath(mounts, abs(arg))
+			var data []byte
+			err := os.ErrNotExist
+			if ok {
+				data, err = os.ReadFile(hostPath)
+			}
+			if err != nil {
+				reason := "No such file or directory"
+				if !os.IsNotExist(err) {
+					reason = "Is a directory"
+				}
+				fmt.Fprintf(stderr, "cat: %s: %s\n", arg, reason)
+				exitCode = 1
+				continue
+			}
+			stdout.Write(data)
+		}
+	case "touch":
+		readOnly := pod.SecurityContext != nil && pod.SecurityContext.ReadOnlyRootFilesystem
+		for _, arg := range args {
+			hostPath, ok := resolveMountPath(mounts, abs(arg))
+			if !ok {
+				if readOnly {
+					fmt.Fprintf(stderr, "touch: cannot touch '%s': Read-only file system\n", arg)
+					exitCode = 1
+				}
+				continue
+			}
+			f, err := os.OpenFile(hostPath, os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				fmt.Fprintf(stderr, "touch: cannot touch '%s': No such file or directory\n", arg)
+				exitCode = 1
+				continue
+			}
+			f.Close()
+			now := time.Now()
+			os.Chtimes(hostPath, now, now)
+		}
+	case "stty":
+		var size streaming.TerminalSize
+		ok := false
+		if s.size != nil {
+			size, ok = s.size()
+		}
+		switch {
+		case len(args) != 1 || args[0] != "size":
+			fmt.Fprintln(stderr, "stty: only 'stty size' is supported")
+			exitCode = 1
+		case !ok:
+			fmt.Fprintln(stderr, "stty: 'standard input': Inappropriate ioctl for device")
+			exitCode = 1
+		default:
+			fmt.Fprintf(stdout, "%d %d\n", size.Height, size.Width)
+		}
+	case "nc":
+		exitCode = netcat(pod, connect, args, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "exec: %q: executable file not found in $PATH\n", command[0])
+		exitCode = 127
+	}
+	return exitCode
+}
+
+// runAsUser returns the UID pod's container runs as: root unless its security
+// context says otherwise, since every simulated image runs as root.
+func runAsUser(pod *api.Pod) int64 {
+	if pod.SecurityContext != nil && pod.SecurityContext.RunAsUser != nil {
+		return *pod.SecurityContext.RunAsUser
+	}
+	return 0
+}
+
+// connectFunc opens a connection from pod to port on host, returning the pod it reached.
+type connectFunc func(from *api.Pod, host string, port int) (*api.Pod, error)
+
+// netcat runs nc with args: it connects to the host and port they name and closes the
+// connection again, as with -z. -v also reports a successful connection.
+func netcat(pod *api.Pod, connect connectFunc, args []string, stdout, stderr io.Writer) int {
+	verbose := false
+	var operands []string
+	for _, arg := range args {
+		switch {
+		case arg == "-z":
+		case arg == "-v", arg == "-zv", arg == "-vz":
+			verbose = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(stderr, "nc: invalid option -- '%s'\n", strings.TrimPrefix(arg, "-"))
+			return 1
+		default:
+			operands = append(operands, arg)
+		}
+	}
+	if len(operands) != 2 {
+		fmt.Fprintln(stderr, "usage: nc [-z] [-v] HOST PORT")
+		return 1
+	}
+	host := operands[0]
+	port, err := strconv.Atoi(operands[1])
+	if err != nil || port < 1 || port > 65535 {
+		fmt.Fprintf(stderr, "nc: port number invalid: %s\n", operands[1])
+		return 1
+	}
+	if connect == nil {
+		fmt.Fprintf(stderr, "nc: connect to %s port %d (tcp) failed: Network is unreachable\n", host, port)
+		return 1
+	}
+	to, err := connect(pod, host, port)
+	if err != nil {
+		fmt.Fprintf(stderr, "nc: connect to %s port %d (tcp) failed: %v\n", host, port, err)
+		return 1
+	}
+	if verbose {
+		fmt.Fprintf(stdout, "Connection to %s (pod %s/%s) %d port [tcp/*] succeeded!\n", host, to.Namespace, to.Name, port)
+	}
+	return 0
+}
+
+// resolveMountPath returns the host path of p, an absolute path in the container, if it
+// lies in one of mounts. The deepest mount containing p wins.
+func resolveMountPath(mounts map[string]string, p string) (string, bool) {
+	p = path.Clean("/" + p)
+	best, hostPath := "", ""
+	for mountPath, host := range mounts {
+		mountPath = path.Clean(mountPath)
+		if (p == mountPath || strings.HasPrefix(p, strings.TrimSuffix(mountPath, "/")+"/")) && len(mountPath) > len(best) {
+			best, hostPath = mountPath, host
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return filepath.Join(hostPath, filepath.FromSlash(strings.TrimPrefix(p, best))), true
+}
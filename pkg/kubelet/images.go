@@ -0,0 +1,190 @@
+package kubelet
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+// maxImagePullBackOff caps the delay between retries of a failing pull.
+const maxImagePullBackOff = 5 * time.Minute
+
+// imageReference matches an image such as "nginx", "nginx:1.25", or
+// "registry.local:5000/team/app:v1@sha256:<digest>": an optional registry host, lowercase
+// path components, an optional tag, and an optional digest.
+var imageReference = regexp.MustCompile(`^(?:[a-zA-Z0-9.-]+(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:[._-]+[a-z0-9]+)*(?:/[a-z0-9]+(?:[._-]+[a-z0-9]+)*)*` +
+	`(?::[A-Za-z0-9_][A-Za-z0-9_.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+
+// imagePullError is returned when a pod's image can't be made available. Reason is one
+// of the api.PodReason* image reasons and ends up on the pod's Ready condition.
+type imagePullError struct {
+	Reason  string
+	Message string
+}
+
+func (e *imagePullError) Error() string {
+	return e.Message
+}
+
+// imageManager simulates the node's image store. Pods are simulated, so there is no
+// registry: a pull just waits pullDelay and then fails with probability failureRate,
+// or, from a registry in privateRegistries, without the credentials it accepts.
+// Images pulled successfully are remembered so that IfNotPresent pods skip the pull,
+// and a pod whose pull failed is not retried until its back-off, which doubles with
+// every failure up to maxImagePullBackOff, has passed.
+type imageManager struct {
+	recorder record.EventRecorder
+
+	mu          sync.Mutex
+	pullDelay   time.Duration
+	failureRate float64
+	backOff     time.Duration     // Delay after the first failed pull
+	private     map[string]string // "user:password" accepted by each private registry host
+	present     map[string]bool
+	backOffs    map[string]*pullBackOff // Keyed by pod UID and image
+	rand        *rand.Rand
+	clock       clock.Clock
+}
+
+type pullBackOff struct {
+	delay time.Duration
+	until time.Time
+}
+
+func newImageManager(recorder record.EventRecorder, clk clock.Clock, pullDelay time.Duration, failureRate float64, backOff time.Duration, preloaded []string, privateRegistries map[string]string) *imageManager {
+	im := &imageManager{
+		recorder:    recorder,
+		pullDelay:   pullDelay,
+		failureRate: failureRate,
+		backOff:     backOff,
+		private:     privateRegistries,
+		present:     make(map[string]bool),
+		backOffs:    make(map[string]*pullBackOff),
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:       clk,
+	}
+	for _, image := range preloaded {
+		im.present[image] = true
+	}
+	return im
+}
+
+// setPullOptions changes how later pulls behave; pulls under way and back-offs
+// already running are left as they are.
+func (im *imageManager) setPullOptions(pullDelay time.Duration, failureRate float64, backOff time.Duration) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.pullDelay, im.failureRate, im.backOff = pullDelay, failureRate, backOff
+}
+
+// EnsureImage makes pod's image available on the node according to its pull policy,
+// pulling it if needed with credentials, the "user:password" to log in to each
+// registry host with. The error, if any, is an *imagePullError.
+func (im *imageManager) EnsureImage(pod *api.Pod, credentials map[string]string) error {
+	image := pod.Image
+	if !imageReference.MatchString(image) {
+		msg := fmt.Sprintf("Failed to parse image reference %q", image)
+		im.recorder.Event(pod, api.EventTypeWarning, "InspectFailed", msg)
+		return &imagePullError{Reason: api.PodReasonInvalidImageName, Message: msg}
+	}
+
+	im.mu.Lock()
+	present := im.present[image]
+	im.mu.Unlock()
+
+	switch pod.ImagePullPolicy {
+	case api.PullNever:
+		if present {
+			im.recorder.Eventf(pod, api.EventTypeNormal, "Pulled", "Container image %q already present on machine", image)
+			return nil
+		}
+		msg := fmt.Sprintf("Container image %q is not present with pull policy of Never", image)
+		im.recorder.Event(pod, api.EventTypeWarning, "ErrImageNeverPull", msg)
+		return &imagePullError{Reason: api.PodReasonErrImageNeverPull, Message: msg}
+	case api.PullAlways:
+	default:
+		if present {
+			im.recorder.Eventf(pod, api.EventTypeNormal, "Pulled", "Container image %q already present on machine", image)
+			return nil
+		}
+	}
+
+	key := string(pod.UID) + "/" + image
+	im.mu.Lock()
+	b := im.backOffs[key]
+	im.mu.Unlock()
+	if b != nil && im.clock.Now().Before(b.until) {
+		msg := fmt.Sprintf("Back-off pulling image %q", image)
+		im.recorder.Event(pod, api.EventTypeNormal, "BackOff", msg)
+		return &imagePullError{Reason: api.PodReasonImagePullBackOff, Message: msg}
+	}
+
+	im.recorder.Eventf(pod, api.EventTypeNormal, "Pulling", "Pulling image %q", image)
+	im.mu.Lock()
+	pullDelay := im.pullDelay
+	im.mu.Unlock()
+	start := im.clock.Now()
+	im.clock.Sleep(pullDelay)
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	var failure string
+	registry := imageRegistry(image)
+	if want, ok := im.private[registry]; ok {
+		switch got, ok := credentials[registry]; {
+		case !ok:
+			failure = fmt.Sprintf("pull access denied for %s: no basic auth credentials", registry)
+		case got != want:
+			failure = fmt.Sprintf("pull access denied for %s: incorrect username or password", registry)
+		}
+	}
+	if failure == "" && im.rand.Float64() < im.failureRate {
+		failure = "simulated registry error"
+	}
+	if failure != "" {
+		if b == nil {
+			b = &pullBackOff{delay: im.backOff}
+			im.backOffs[key] = b
+		} else {
+			b.delay *= 2
+			if b.delay > maxImagePullBackOff {
+				b.delay = maxImagePullBackOff
+			}
+		}
+		b.until = im.clock.Now().Add(b.delay)
+		msg := fmt.Sprintf("Failed to pull image %q: %s", image, failure)
+		log.Printf("%s (retrying in %v)", msg, b.delay)
+		im.recorder.Event(pod, api.EventTypeWarning, "Failed", msg)
+		return &imagePullError{Reason: api.PodReasonErrImagePull, Message: msg}
+	}
+	delete(im.backOffs, key)
+	im.present[image] = true
+	im.recorder.Eventf(pod, api.EventTypeNormal, "Pulled", "Successfully pulled image %q in %v", image, im.clock.Now().Sub(start).Round(time.Millisecond))
+	return nil
+}
+
+// Forget drops the pull back-off of a pod that is going away.
+func (im *imageManager) Forget(pod *api.Pod) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	delete(im.backOffs, string(pod.UID)+"/"+pod.Image)
+}
+
+// imageRegistry returns the registry host image is pulled from: its first path
+// component if that names a host, and Docker Hub otherwise.
+func imageRegistry(image string) string {
+	host, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		return host
+	}
+	return "docker.io"
+}
@@ -0,0 +1,135 @@
+package kubelet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+// newTestImageManager returns an image manager whose clock only moves when the test
+// advances it and whose pulls take no real time.
+func newTestImageManager(failureRate float64, preloaded ...string) (*imageManager, *clock.FakeClock) {
+	recorder := record.NewRecorder(fake.NewClient(), api.EventSource{Component: "kubelet"})
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return newImageManager(recorder, clk, time.Second, failureRate, 10*time.Second, preloaded, nil), clk
+}
+
+func imagePod(image string, policy api.PullPolicy) *api.Pod {
+	return &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-1"}, Image: image, ImagePullPolicy: policy}
+}
+
+// pullReason returns the reason of an *imagePullError, or "" for nil.
+func pullReason(t *testing.T, err error) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	var pullErr *imagePullError
+	if !errors.As(err, &pullErr) {
+		t.Fatalf("expected an *imagePullError, got %T: %v", err, err)
+	}
+	return pullErr.Reason
+}
+
+func TestImagePullPolicies(t *testing.T) {
+	im, clk := newTestImageManager(0, "busybox:1.36")
+
+	start := clk.Now()
+	if err := im.EnsureImage(imagePod("nginx:1.25", api.PullIfNotPresent), nil); err != nil {
+		t.Fatalf("first IfNotPresent pull: %v", err)
+	}
+	if clk.Since(start) != time.Second {
+		t.Errorf("expected the pull to take the configured delay, took %v", clk.Since(start))
+	}
+
+	start = clk.Now()
+	if err := im.EnsureImage(imagePod("nginx:1.25", api.PullIfNotPresent), nil); err != nil || !clk.Now().Equal(start) {
+		t.Errorf("expected a cached IfNotPresent image not to be pulled again (err=%v, took %v)", err, clk.Since(start))
+	}
+	if err := im.EnsureImage(imagePod("nginx:1.25", api.PullAlways), nil); err != nil || clk.Now().Equal(start) {
+		t.Errorf("expected Always to pull even a cached image (err=%v)", err)
+	}
+
+	if err := im.EnsureImage(imagePod("busybox:1.36", api.PullNever), nil); err != nil {
+		t.Errorf("Never with a preloaded image: %v", err)
+	}
+	if got := pullReason(t, im.EnsureImage(imagePod("redis:7", api.PullNever), nil)); got != api.PodReasonErrImageNeverPull {
+		t.Errorf("Never with a missing image: reason %q, want %q", got, api.PodReasonErrImageNeverPull)
+	}
+	if got := pullReason(t, im.EnsureImage(imagePod("Nginx:latest", api.PullAlways), nil)); got != api.PodReasonInvalidImageName {
+		t.Errorf("invalid image: reason %q, want %q", got, api.PodReasonInvalidImageName)
+	}
+}
+
+func TestImagePullBackOff(t *testing.T) {
+	im, clk := newTestImageManager(1)
+	pod := imagePod("nginx:1.25", api.PullIfNotPresent)
+
+	if got := pullReason(t, im.EnsureImage(pod, nil)); got != api.PodReasonErrImagePull {
+		t.Fatalf("failing pull: reason %q, want %q", got, api.PodReasonErrImagePull)
+	}
+	if got := pullReason(t, im.EnsureImage(pod, nil)); got != api.PodReasonImagePullBackOff {
+		t.Fatalf("retry during back-off: reason %q, want %q", got, api.PodReasonImagePullBackOff)
+	}
+
+	// The back-off doubles with every failure: 10s, then 20s.
+	clk.Step(10 * time.Second)
+	if got := pullReason(t, im.EnsureImage(pod, nil)); got != api.PodReasonErrImagePull {
+		t.Fatalf("retry after back-off: reason %q, want %q", got, api.PodReasonErrImagePull)
+	}
+	clk.Step(15 * time.Second)
+	if got := pullReason(t, im.EnsureImage(pod, nil)); got != api.PodReasonImagePullBackOff {
+		t.Fatalf("retry before the doubled back-off: reason %q, want %q", got, api.PodReasonImagePullBackOff)
+	}
+
+	// Once the registry recovers the next retry succeeds and the back-off is cleared.
+	im.failureRate = 0
+	clk.Step(10 * time.Second)
+	if err := im.EnsureImage(pod, nil); err != nil {
+		t.Fatalf("retry after recovery: %v", err)
+	}
+	if len(im.backOffs) != 0 {
+		t.Errorf("expected the back-off to be cleared, got %v", im.backOffs)
+	}
+}
+
+func TestPrivateRegistryPulls(t *testing.T) {
+	im, clk := newTestImageManager(0)
+	im.private = map[string]string{"registry.local:5000": "alice:s3cret"}
+	pod := imagePod("registry.local:5000/team/app:v1", api.PullAlways)
+
+	if got := pullReason(t, im.EnsureImage(pod, nil)); got != api.PodReasonErrImagePull {
+		t.Fatalf("pull without credentials: reason %q, want %q", got, api.PodReasonErrImagePull)
+	}
+	clk.Step(10 * time.Second)
+	if got := pullReason(t, im.EnsureImage(pod, map[string]string{"registry.local:5000": "alice:wrong"})); got != api.PodReasonErrImagePull {
+		t.Fatalf("pull with the wrong password: reason %q, want %q", got, api.PodReasonErrImagePull)
+	}
+	clk.Step(20 * time.Second)
+	if err := im.EnsureImage(pod, map[string]string{"registry.local:5000": "alice:s3cret"}); err != nil {
+		t.Fatalf("pull with credentials: %v", err)
+	}
+	// Images from other registries need no credentials.
+	if err := im.EnsureImage(imagePod("nginx:1.25", api.PullAlways), nil); err != nil {
+		t.Fatalf("public pull: %v", err)
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	for image, want := range map[string]string{
+		"nginx":                           "docker.io",
+		"library/nginx:1.25":              "docker.io",
+		"registry.local:5000/team/app:v1": "registry.local:5000",
+		"ghcr.io/org/tool":                "ghcr.io",
+		"localhost/app":                   "localhost",
+	} {
+		if got := imageRegistry(image); got != want {
+			t.Errorf("imageRegistry(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+// Package kubelet implements the node agent: it registers its node and simulates the
+// pods scheduled to it, preparing their volumes and pulling their images.
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/backoff"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/chaos"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/heartbeat"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/logs"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/proxy"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+const DefaultNamespace = "default"
+
+// Kubelet represents a node agent.
+type Kubelet struct {
+	NodeName    string
+	NodeAddress string // Mock address for this Kubelet/Node
+	APIClient   api.Interface
+	Clock       clock.Clock
+	Recorder    record.EventRecorder
+	Volumes     *volumeManager
+	Images      *imageManager
+	// Chaos, if set before Run, crashes the kubelet and flaps its node.
+	Chaos *chaos.Injector
+	// Serve, if set before Run, has the kubelet serve its HTTP API (see Handler) on the
+	// port of NodeAddress.
+	Serve bool
+	// ServerToken, if set, is the bearer token the kubelet's HTTP API requires.
+	ServerToken string
+	// MemoryCapacity, if set before Run, is the node's memory in bytes. Once the
+	// simulated memory use of its pods leaves less than EvictionThreshold of it
+	// available, the kubelet evicts pods (see evictPods).
+	MemoryCapacity    int64
+	EvictionThreshold int64
+	// CPUCapacity, if set before Run, is the node's CPU in thousandths of a CPU. The
+	// node reports it, and MemoryCapacity less EvictionThreshold, as allocatable, so
+	// that the scheduler only places pods whose requests fit.
+	CPUCapacity int64
+	// ContainerLogMaxSize and ContainerLogMaxFiles, if changed before Run, are how
+	// large the log file of a pod's container grows before it is rotated, and how
+	// many files of each run of the container are kept (see containerlogs.go).
+	ContainerLogMaxSize  int64
+	ContainerLogMaxFiles int
+
+	syncInterval atomic.Int64 // time.Duration; see SetSyncInterval
+	pullsMu      sync.Mutex   // Guards pulls, and Images against a chaos restart replacing it
+	pulls        ImagePullOptions
+
+	rootDir        string    // Holds pod volumes and logs, and the checkpoint of the pods the kubelet runs
+	flapUntil      time.Time // When a chaos flap ends; zero while the node isn't flapping
+	memoryPressure bool      // Whether the last sync found memory below EvictionThreshold
+	runtime        *podRuntime
+	proxy          *proxy.Proxy             // kube-proxy-lite, for connections made by the node's pods
+	startups       map[string]time.Time     // When the containers of pods with a startup delay finish starting, by namespace/name
+	crashBackOffs  map[string]*crashBackOff // Restart back-offs of pods' containers, by namespace/name
+	rand           *rand.Rand               // Decides when containers with a crash probability crash
+
+	reportedConditions   []api.NodeCondition // The node's conditions as last reported; see syncNodeStatus
+	lastNodeStatusReport time.Time
+
+	lastCheckpoint []byte // The checkpoint as last written; see saveCheckpoint
+
+	syncs      atomic.Uint64
+	lastSync   atomic.Int64     // When the last pod sync finished, in Unix nanoseconds by Clock
+	apiBackoff *backoff.Backoff // Spaces out pod syncs while the API server can't be reached
+}
+
+// ImagePullOptions configures the kubelet's simulated image pulls.
+type ImagePullOptions struct {
+	Delay       time.Duration
+	FailureRate float64
+	BackOff     time.Duration
+	Preloaded   []string
+	// PrivateRegistries maps the host of each registry that only serves pulls with
+	// credentials to the "user:password" it accepts.
+	PrivateRegistries map[string]string
+}
+
+// NewKubelet returns the kubelet of node nodeName, syncing its pods every syncInterval
+// as measured by clk, which also times image pulls. Pod volumes and logs, and the
+// checkpoint of the pods the kubelet runs, live under rootDir, which defaults to
+// <tmp>/k8s-lite-kubelet/<nodeName>.
+func NewKubelet(client api.Interface, nodeName, nodeAddress, rootDir string, syncInterval time.Duration, pulls ImagePullOptions, clk clock.Clock) *Kubelet {
+	if rootDir == "" {
+		rootDir = filepath.Join(os.TempDir(), "k8s-lite-kubelet", nodeName)
+	}
+	recorder := record.NewRecorder(client, api.EventSource{Component: "kubelet", Host: nodeName})
+	k := &Kubelet{
+		NodeName:      nodeName,
+		NodeAddress:   nodeAddress,
+		APIClient:     client,
+		Clock:         clk,
+		Recorder:      recorder,
+		Volumes:       newVolumeManager(rootDir, client),
+		Images:        newImageManager(recorder, clk, pulls.Delay, pulls.FailureRate, pulls.BackOff, pulls.Preloaded, pulls.PrivateRegistries),
+		pulls:         pulls,
+		rootDir:       rootDir,
+		runtime:       newPodRuntime(clk, filepath.Join(rootDir, "logs")),
+		startups:      make(map[string]time.Time),
+		crashBackOffs: make(map[string]*crashBackOff),
+		rand:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		proxy:         proxy.New(client, record.NewRecorder(client, api.EventSource{Component: "kube-proxy", Host: nodeName})),
+		apiBackoff:    backoff.New(syncInterval, maxAPIBackoff),
+
+		EvictionThreshold:    DefaultEvictionThreshold,
+		ContainerLogMaxSize:  DefaultContainerLogMaxSize,
+		ContainerLogMaxFiles: DefaultContainerLogMaxFiles,
+	}
+	k.syncInterval.Store(int64(syncInterval))
+	return k
+}
+
+// SyncInterval returns how often the kubelet syncs all of its node's pods.
+func (k *Kubelet) SyncInterval() time.Duration {
+	return time.Duration(k.syncInterval.Load())
+}
+
+// SetSyncInterval changes how often the kubelet syncs all of its node's pods. It is
+// safe to call while the kubelet runs, and takes effect after the next sync.
+func (k *Kubelet) SetSyncInterval(d time.Duration) {
+	k.syncInterval.Store(int64(d))
+}
+
+// SetImagePullOptions changes the delay, failure rate, and back-off of the kubelet's
+// simulated image pulls; the preloaded images stay as they were. It is safe to call
+// while the kubelet runs.
+func (k *Kubelet) SetImagePullOptions(pulls ImagePullOptions) {
+	k.pullsMu.Lock()
+	defer k.pullsMu.Unlock()
+	k.pulls.Delay, k.pulls.FailureRate, k.pulls.BackOff = pulls.Delay, pulls.FailureRate, pulls.BackOff
+	k.Images.setPullOptions(pulls.Delay, pulls.FailureRate, pulls.BackOff)
+}
+
+// Run registers the node and then syncs its pods until ctx is cancelled. If the
+// client can watch, each pod is synced as soon as the watch of the node's pods says
+// it changed; every SyncInterval all of them are synced again regardless, which
+// retries what failed and is all the kubelet does with a client that can't watch.
+func (k *Kubelet) Run(ctx context.Context) error {
+	// Retry registration a few times so that a flaky API server (say, one in chaos
+	// mode) doesn't stop the kubelet, while one that isn't there still fails fast.
+	for attempt := 1; ; attempt++ {
+		err := k.registerNode()
+		if err == nil {
+			break
+		}
+		if attempt == registerAttempts {
+			return fmt.Errorf("failed to register node with API server: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-k.Clock.After(registerRetryInterval):
+		}
+	}
+	k.runtime.setLogLimits(k.ContainerLogMaxSize, k.ContainerLogMaxFiles)
+	interval := k.SyncInterval()
+	log.Printf("Kubelet for node '%s' registered. Starting pod sync loop with interval %v.", k.NodeName, interval)
+	k.restoreCheckpoint()
+	go heartbeat.Run(ctx, k.APIClient, "kubelet-"+k.NodeName, heartbeat.DefaultPeriod)
+	go k.runNodeLease(ctx)
+	if k.Serve {
+		go k.serve(ctx)
+	}
+	podEvents := make(chan podEvent)
+	if watcher, ok := k.APIClient.(api.Watcher); ok {
+		go k.watchPods(ctx, watcher, podEvents)
+	}
+
+	ticker := k.Clock.NewTicker(interval)
+	defer func() { ticker.Stop() }()
+	for {
+		if d := k.SyncInterval(); d != interval {
+			log.Printf("[%s] Sync interval changed from %v to %v", k.NodeName, interval, d)
+			ticker.Stop()
+			ticker, interval = k.Clock.NewTicker(d), d
+		}
+		if !k.injectFaults(ctx) {
+			return nil
+		}
+		// While the API server can't be reached, wait out a growing back-off instead
+		// of the sync interval, so its restart isn't met by a storm of log lines and
+		// every node's kubelet retrying at once.
+		next := ticker.C()
+		if err := k.syncPods(); err != nil {
+			delay := k.apiBackoff.Next()
+			log.Printf("[%s] Pod sync failed (%d in a row), retrying in %v: %v", k.NodeName, k.apiBackoff.Failures(), delay.Round(time.Millisecond), err)
+			next = k.Clock.After(delay)
+		} else {
+			if failures := k.apiBackoff.Reset(); failures > 0 {
+				log.Printf("[%s] Pod sync succeeded again after %d failures", k.NodeName, failures)
+			}
+			k.evictPods()
+			k.saveCheckpoint()
+			k.syncNodeStatus()
+			k.syncs.Add(1)
+			k.lastSync.Store(k.Clock.Now().UnixNano())
+		}
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event := <-podEvents:
+				k.syncPodEvent(event)
+			case <-next:
+				break wait
+			}
+		}
+	}
+}
+
+// maxAPIBackoff caps the delay between pod syncs while the API server can't be
+// reached; the first retry waits one sync interval.
+const maxAPIBackoff = time.Minute
+
+// registerAttempts is how many times Run tries to register the node, registerRetryInterval
+// apart, before giving up.
+const (
+	registerAttempts      = 5
+	registerRetryInterval = time.Second
+)
+
+// registerNode registers this Kubelet's node with the API server.
+func (k *Kubelet) registerNode() error {
+	node := &api.Node{
+		ObjectMeta: api.ObjectMeta{Name: k.NodeName},
+		Address:    k.NodeAddress,
+	}
+	conditions := k.nodeConditions()
+	for _, cond := range conditions {
+		api.SetNodeCondition(node, cond)
+	}
+	node.Capacity, node.Allocatable = k.nodeResources()
+	createdNode, err := k.APIClient.CreateNode(node)
+	if err != nil {
+		// It might already exist if Kubelet restarted, try to update (get and then put if needed)
+		// For simplicity, we'll just log an error. A real Kubelet would handle this more gracefully.
+		log.Printf("Failed to register node %s, attempting to update: %v", k.NodeName, err)
+		// Attempt to update if creation failed (e.g. node already exists), keeping any
+		// cordon, labels, and annotations others put on the node
+		if existing, errGet := k.APIClient.GetNode(k.NodeName); errGet == nil {
+			node.Unschedulable = existing.Unschedulable
+			node.Labels, node.Annotations = existing.Labels, existing.Annotations
+		}
+		if errUpdate := k.APIClient.UpdateNode(node); errUpdate != nil {
+			return fmt.Errorf("failed to register or update node %s: %w (update error: %v)", k.NodeName, err, errUpdate)
+		}
+		log.Printf("Node %s updated successfully after initial registration failure.", k.NodeName)
+		k.reportedConditions, k.lastNodeStatusReport = conditions, k.Clock.Now()
+		k.Recorder.Event(node, api.EventTypeNormal, "Starting", "Starting kubelet.")
+		return nil
+	}
+	k.reportedConditions, k.lastNodeStatusReport = conditions, k.Clock.Now()
+	log.Printf("Node %s registered successfully with address %s and status %s", createdNode.Name, createdNode.Address, createdNode.Status)
+	k.Recorder.Eventf(createdNode, api.EventTypeNormal, "RegisteredNode", "Node %s registered with the API server", createdNode.Name)
+	k.Recorder.Event(createdNode, api.EventTypeNormal, "Starting", "Starting kubelet.")
+	return nil
+}
+
+// syncPods syncs every pod bound to the node. It returns an error if it couldn't list
+// them; failures to update single pods are only logged, and retried on the next sync.
+func (k *Kubelet) syncPods() error {
+	if logs.V(2) {
+		log.Printf("[%s] Syncing pods...", k.NodeName)
+	}
+
+	// 1. Get the pods in every namespace bound to this node, in any phase
+	pods, err := k.APIClient.ListPodsMatching(api.NamespaceAll, "", "spec.nodeName="+k.NodeName)
+	if err != nil {
+		return fmt.Errorf("fetching pods: %w", err)
+	}
+
+	active := make(map[string]bool)  // volume directories of pods still on this node
+	running := make(map[string]bool) // pods still running on this node, by namespace/name
+	listed := make(map[string]bool)  // pods on this node, by namespace/name
+	for _, pod := range pods {
+		if pod.NodeName != k.NodeName {
+			continue
+		}
+		listed[podKey(pod.Namespace, pod.Name)] = true
+		if pod.Phase != api.PodDeleted {
+			active[filepath.Base(k.Volumes.podDir(&pod))] = true
+		}
+		if k.syncPod(&pod) {
+			running[podKey(pod.Namespace, pod.Name)] = true
+		}
+	}
+	// Stop pods that are no longer running here, say because they failed or were
+	// removed from the API server without a graceful deletion.
+	k.runtime.retain(running)
+	k.forgetPods(listed)
+	k.Volumes.CleanupOrphans(active)
+	return nil
+}
+
+// syncPod brings one pod bound to the node closer to its desired state: it stops a
+// terminating pod, starts a scheduled one, and keeps the runtime's copy of a running
+// one current, ending or restarting its container as the pod's simulated behavior
+// (see workload.go) says. It returns whether the pod is running on the node afterwards.
+func (k *Kubelet) syncPod(pod *api.Pod) bool {
+	// Terminating pods are marked by their DeletionTimestamp; stop them first.
+	if pod.DeletionTimestamp != nil {
+		if pod.Phase != api.PodDeleted {
+			log.Printf("[%s] Detected terminating pod %s. Simulating cleanup and marking as Deleted.", k.NodeName, pod.Name)
+			k.Recorder.Eventf(pod, api.EventTypeNormal, "Killing", "Stopping pod %s", pod.Name)
+			if err := k.Volumes.TearDownPod(pod); err != nil {
+				log.Printf("[%s] Error removing volumes of pod %s: %v", k.NodeName, pod.Name, err)
+				k.Recorder.Eventf(pod, api.EventTypeWarning, "FailedUnmount", "Error removing volumes: %v", err)
+				return false
+			}
+			k.Images.Forget(pod)
+			k.runtime.stop(pod.Namespace, pod.Name)
+			updatedPod := *pod
+			updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+			api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: "Terminating", Message: "The pod is being deleted"})
+			updatedPod.Phase = api.PodDeleted
+
+			if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+				log.Printf("[%s] Error updating pod %s to Deleted after termination: %v", k.NodeName, pod.Name, err)
+			} else {
+				log.Printf("[%s] Pod %s marked as Deleted after termination processing.", k.NodeName, pod.Name)
+			}
+		}
+		return false
+	}
+
+	switch pod.Phase {
+	case api.PodScheduled:
+		log.Printf("[%s] Found scheduled pod %s. 'Starting' it...", k.NodeName, pod.Name)
+		mounts, err := k.Volumes.SetUpPod(pod)
+		if err != nil {
+			log.Printf("[%s] Error setting up volumes of pod %s: %v", k.NodeName, pod.Name, err)
+			k.Recorder.Eventf(pod, api.EventTypeWarning, "FailedMount", "Unable to set up volumes: %v", err)
+			return false
+		}
+		for mountPath, hostPath := range mounts {
+			log.Printf("[%s] Pod %s: mounted %s at %s", k.NodeName, pod.Name, hostPath, mountPath)
+		}
+		if err := k.Images.EnsureImage(pod, k.pullCredentials(pod)); err != nil {
+			log.Printf("[%s] Pod %s can't start: %v", k.NodeName, pod.Name, err)
+			k.reportImageError(pod, err.(*imagePullError))
+			return false
+		}
+		if !k.containerCreated(pod, k.simulatedBehavior(pod)) {
+			return false
+		}
+		updatedPod := *pod
+		updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+		updatedPod.Phase = api.PodRunning
+		api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionTrue, Reason: "Started"})
+		if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+			log.Printf("[%s] Error updating pod %s to Running: %v", k.NodeName, pod.Name, err)
+			k.Recorder.Eventf(pod, api.EventTypeWarning, "FailedStart", "Error reporting pod as running: %v", err)
+			return false
+		}
+		log.Printf("[%s] Pod %s with image '%s' is now 'Running'.", k.NodeName, pod.Name, pod.Image)
+		k.Recorder.Eventf(&updatedPod, api.EventTypeNormal, "Started", "Started pod with image %s", pod.Image)
+		k.runtime.start(&updatedPod, mounts, k.proxy.Connect)
+		return true
+	case api.PodRunning:
+		// Keep the runtime's copy of the pod current. A pod started before the
+		// kubelet restarted is taken back into the runtime.
+		var mounts map[string]string
+		if !k.runtime.running(pod) {
+			var err error
+			if mounts, err = k.Volumes.SetUpPod(pod); err != nil {
+				log.Printf("[%s] Error finding the volumes of running pod %s: %v", k.NodeName, pod.Name, err)
+			}
+		}
+		k.runtime.start(pod, mounts, k.proxy.Connect)
+		return k.syncContainer(pod, k.simulatedBehavior(pod))
+	default:
+		// Do nothing for other phases like Pending (handled by scheduler), Succeeded, Failed (final states)
+		if pod.Phase != api.PodPending && pod.Phase != api.PodSucceeded && pod.Phase != api.PodFailed {
+			log.Printf("[%s] Pod %s found in unhandled phase: %s", k.NodeName, pod.Name, pod.Phase)
+		}
+		return false
+	}
+}
+
+// reportImageError records on pod's Ready condition why its image isn't available, so
+// that clients see ErrImagePull, ImagePullBackOff, and the like. The pod stays
+// Scheduled and is retried on the next sync.
+func (k *Kubelet) reportImageError(pod *api.Pod, err *imagePullError) {
+	k.reportWaiting(pod, err.Reason, err.Message)
+}
+
+// reportWaiting records on a scheduled pod's Ready condition why its container hasn't
+// started yet, unless it already says so.
+func (k *Kubelet) reportWaiting(pod *api.Pod, reason, message string) {
+	if cond := api.GetPodCondition(pod, api.PodReadyCondition); cond != nil && cond.Reason == reason && cond.Message == message {
+		return
+	}
+	updatedPod := *pod
+	updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+	api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: reason, Message: message})
+	if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+		log.Printf("[%s] Error reporting the status of pod %s: %v", k.NodeName, pod.Name, err)
+	}
+}
@@ -0,0 +1,147 @@
+package kubelet
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestRunRegistersNodeAndStartsPods(t *testing.T) {
+	client := fake.NewClient(&api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: DefaultNamespace},
+		Image:      "nginx:1.25",
+		NodeName:   "node1",
+		Phase:      api.PodScheduled,
+	}, &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "dns", Namespace: "kube-system"},
+		Image:      "coredns:1.11",
+		NodeName:   "node1",
+		Phase:      api.PodScheduled,
+	})
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), 10*time.Millisecond, ImagePullOptions{}, clock.RealClock{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- k.Run(ctx) }()
+
+	// Pods bound to the node run whatever their namespace.
+	deadline := time.Now().Add(2 * time.Second)
+	for _, key := range [][2]string{{DefaultNamespace, "web"}, {"kube-system", "dns"}} {
+		for {
+			pod, err := client.GetPod(key[0], key[1])
+			if err == nil && pod.Phase == api.PodRunning {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for pod %s/%s to run (last: %+v, %v)", key[0], key[1], pod, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if node, err := client.GetNode("node1"); err != nil || node.Status != api.NodeReady {
+		t.Errorf("expected node1 to be registered Ready, got %+v, %v", node, err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v after cancellation, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
+
+// watchingClient is a fake client that can watch: WatchMatching sends it the events
+// given to events, whatever the selectors, and keeps the namespace it last watched.
+type watchingClient struct {
+	*fake.Client
+	events  chan api.WatchEvent
+	watched atomic.Value // string
+}
+
+func (c *watchingClient) Watch(ctx context.Context, resource, namespace, resourceVersion string, fn func(api.WatchEvent) error) error {
+	return c.WatchMatching(ctx, resource, namespace, resourceVersion, "", "", fn)
+}
+
+func (c *watchingClient) WatchMatching(ctx context.Context, resource, namespace, resourceVersion, labelSelector, fieldSelector string, fn func(api.WatchEvent) error) error {
+	c.watched.Store(namespace)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-c.events:
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func TestRunSyncsPodsOnWatchEvents(t *testing.T) {
+	client := &watchingClient{Client: fake.NewClient(&api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: DefaultNamespace},
+		Image:      "nginx:1.25",
+		NodeName:   "node1",
+		Phase:      api.PodPending,
+	}), events: make(chan api.WatchEvent)}
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Hour, ImagePullOptions{}, clk)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go k.Run(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for k.syncs.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the first full sync")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The next full sync is an hour away, so only the watch event can start the pod.
+	pod, _ := client.GetPod(DefaultNamespace, "web")
+	pod.Phase = api.PodScheduled
+	if err := client.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	send := func(t api.WatchEventType, pod *api.Pod) {
+		raw, _ := json.Marshal(pod)
+		client.events <- api.WatchEvent{Type: t, Object: raw}
+	}
+	send(api.WatchAdded, pod)
+	deadline = time.Now().Add(2 * time.Second)
+	for !k.runtime.running(pod) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the watch event to start the pod")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, _ := client.GetPod(DefaultNamespace, "web"); got.Phase != api.PodRunning {
+		t.Errorf("expected the pod to be reported Running, got %s", got.Phase)
+	}
+
+	// A pod removed from the API server is stopped as soon as the kubelet hears of it.
+	send(api.WatchDeleted, pod)
+	deadline = time.Now().Add(2 * time.Second)
+	for k.runtime.running(pod) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the watch event to stop the pod")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if ns, _ := client.watched.Load().(string); ns != api.NamespaceAll {
+		t.Errorf("expected the node's pods to be watched in every namespace, got namespace %q", ns)
+	}
+	if n := k.syncs.Load(); n != 1 {
+		t.Errorf("expected no full sync besides the first, got %d", n)
+	}
+}
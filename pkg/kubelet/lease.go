@@ -0,0 +1,40 @@
+package kubelet
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+)
+
+const (
+	// nodeLeaseDuration is how long the node's lease lasts without being renewed.
+	nodeLeaseDuration = 40 * time.Second
+	// nodeLeaseRenewInterval is how often the kubelet renews the node's lease.
+	nodeLeaseRenewInterval = 10 * time.Second
+)
+
+// runNodeLease keeps the Lease named after the node in api.NodeLeaseNamespace held
+// by the kubelet, renewing it every nodeLeaseRenewInterval until ctx is cancelled.
+// The lease says the node's kubelet is alive, separately from the node's status.
+func (k *Kubelet) runNodeLease(ctx context.Context) {
+	// Renewal times are stored on the lease, so like other timestamps on objects they
+	// are real even when the kubelet's clock is scaled.
+	lock := controller.NewLeaseLock(k.APIClient, api.NodeLeaseNamespace, k.NodeName, nodeLeaseDuration, nil)
+	ticker := k.Clock.NewTicker(nodeLeaseRenewInterval)
+	defer ticker.Stop()
+	for {
+		if ok, err := lock.TryAcquireOrRenew(ctx, k.NodeName); err != nil {
+			log.Printf("[%s] Error renewing node lease: %v", k.NodeName, err)
+		} else if !ok {
+			log.Printf("[%s] Node lease is held by someone else", k.NodeName)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package kubelet
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// nodeStatusReportFrequency is how often the kubelet reports its node's conditions
+// while they stay the same. The report refreshes their heartbeat times and puts back
+// conditions others wrote over, such as the Unknown the node lifecycle controller
+// sets while the node's lease is expired.
+const nodeStatusReportFrequency = time.Minute
+
+// conditionEvents are the reasons of the events recorded when a condition turns True
+// and when it turns False.
+var conditionEvents = map[api.NodeConditionType][2]string{
+	api.NodeReadyCondition: {"NodeReady", "NodeNotReady"},
+	api.NodeMemoryPressure: {"NodeHasInsufficientMemory", "NodeHasSufficientMemory"},
+	api.NodeDiskPressure:   {"NodeHasDiskPressure", "NodeHasNoDiskPressure"},
+	api.NodePIDPressure:    {"NodeHasInsufficientPID", "NodeHasSufficientPID"},
+}
+
+// nodeConditions returns the node's conditions as the kubelet sees them: NotReady
+// while chaos flaps the node, and under memory pressure while the last sync found
+// memory below EvictionThreshold. The simulated nodes never run short of disk or
+// process IDs.
+func (k *Kubelet) nodeConditions() []api.NodeCondition {
+	ready := api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionTrue, Reason: "KubeletReady", Message: "kubelet is posting ready status"}
+	if !k.flapUntil.IsZero() {
+		ready = api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionFalse, Reason: "KubeletNotReady", Message: "chaos: the node is flapping"}
+	}
+	memory := api.NodeCondition{Type: api.NodeMemoryPressure, Status: api.ConditionFalse, Reason: "KubeletHasSufficientMemory", Message: "kubelet has sufficient memory available"}
+	if k.memoryPressure {
+		memory = api.NodeCondition{Type: api.NodeMemoryPressure, Status: api.ConditionTrue, Reason: "KubeletHasInsufficientMemory", Message: "kubelet has insufficient memory available"}
+	}
+	return []api.NodeCondition{
+		ready,
+		memory,
+		{Type: api.NodeDiskPressure, Status: api.ConditionFalse, Reason: "KubeletHasNoDiskPressure", Message: "kubelet has no disk pressure"},
+		{Type: api.NodePIDPressure, Status: api.ConditionFalse, Reason: "KubeletHasSufficientPID", Message: "kubelet has sufficient PID available"},
+	}
+}
+
+// setNodeConditions sets the node's conditions to the kubelet's, recording an event
+// for each whose status changed, and notes them as reported.
+func (k *Kubelet) setNodeConditions(node *api.Node, conditions []api.NodeCondition) {
+	var changed []api.NodeCondition
+	for _, cond := range conditions {
+		if api.SetNodeCondition(node, cond) {
+			changed = append(changed, cond)
+		}
+	}
+	k.reportedConditions, k.lastNodeStatusReport = conditions, k.Clock.Now()
+	for _, cond := range changed {
+		reason := conditionEvents[cond.Type][1]
+		if cond.Status == api.ConditionTrue {
+			reason = conditionEvents[cond.Type][0]
+		}
+		k.Recorder.Eventf(node, api.EventTypeNormal, reason, "Node %s status is now: %s", k.NodeName, reason)
+	}
+}
+
+// syncNodeStatus reports the node's conditions if they changed since the last report
+// or nodeStatusReportFrequency has passed, and returns whether the node is up to
+// date. A failed report is retried on the next call.
+func (k *Kubelet) syncNodeStatus() bool {
+	conditions := k.nodeConditions()
+	if slices.Equal(conditions, k.reportedConditions) && k.Clock.Since(k.lastNodeStatusReport) < nodeStatusReportFrequency {
+		return true
+	}
+	node, err := k.APIClient.GetNode(k.NodeName)
+	if err != nil {
+		log.Printf("[%s] Error getting node to report its status: %v", k.NodeName, err)
+		return false
+	}
+	reported := node.DeepCopy()
+	for _, cond := range conditions {
+		api.SetNodeCondition(reported, cond)
+	}
+	if err := k.APIClient.UpdateNode(reported); err != nil {
+		log.Printf("[%s] Error reporting node status: %v", k.NodeName, err)
+		return false
+	}
+	k.setNodeConditions(node, conditions)
+	return true
+}
+
+// MaxPods is how many pods a node runs, reported as its pods capacity.
+const MaxPods = 110
+
+// nodeResources returns the node's capacity, and what of it is allocatable to pods:
+// all of its CPU and pods, and its memory less EvictionThreshold, which the kubelet
+// keeps available by evicting pods. Resources the kubelet wasn't given are left out,
+// and so unlimited.
+func (k *Kubelet) nodeResources() (capacity, allocatable api.ResourceList) {
+	capacity = api.ResourceList{api.ResourcePods: api.FormatResourceQuantity(api.ResourcePods, MaxPods)}
+	allocatable = api.ResourceList{api.ResourcePods: capacity[api.ResourcePods]}
+	if k.CPUCapacity > 0 {
+		capacity[api.ResourceCPU] = api.FormatResourceQuantity(api.ResourceCPU, k.CPUCapacity)
+		allocatable[api.ResourceCPU] = capacity[api.ResourceCPU]
+	}
+	if k.MemoryCapacity > 0 {
+		capacity[api.ResourceMemory] = api.FormatResourceQuantity(api.ResourceMemory, k.MemoryCapacity)
+		allocatable[api.ResourceMemory] = api.FormatResourceQuantity(api.ResourceMemory, max(k.MemoryCapacity-k.EvictionThreshold, 0))
+	}
+	return capacity, allocatable
+}
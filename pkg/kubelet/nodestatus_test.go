@@ -0,0 +1,86 @@
+package kubelet
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestSyncNodeStatus(t *testing.T) {
+	client := fake.NewClient()
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clk)
+	k.CPUCapacity, k.MemoryCapacity = 2000, 1<<30
+	if err := k.registerNode(); err != nil {
+		t.Fatalf("registerNode: %v", err)
+	}
+	registered, _ := client.GetNode("node1")
+	wantAllocatable := api.ResourceList{api.ResourceCPU: "2", api.ResourceMemory: "924Mi", api.ResourcePods: "110"}
+	if !reflect.DeepEqual(registered.Allocatable, wantAllocatable) || registered.Capacity[api.ResourceMemory] != "1Gi" {
+		t.Errorf("expected capacity 1Gi of memory and allocatable %v, got %v and %v", wantAllocatable, registered.Capacity, registered.Allocatable)
+	}
+	condition := func(t api.NodeConditionType) api.ConditionStatus {
+		node, err := client.GetNode("node1")
+		if err != nil {
+			panic(err)
+		}
+		if cond := api.GetNodeCondition(node, t); cond != nil {
+			return cond.Status
+		}
+		return ""
+	}
+	for _, ct := range []api.NodeConditionType{api.NodeReadyCondition, api.NodeMemoryPressure, api.NodeDiskPressure, api.NodePIDPressure} {
+		want := api.ConditionFalse
+		if ct == api.NodeReadyCondition {
+			want = api.ConditionTrue
+		}
+		if got := condition(ct); got != want {
+			t.Errorf("registered node: %s = %q, want %q", ct, got, want)
+		}
+	}
+
+	// Nothing changed: no report until nodeStatusReportFrequency passes.
+	client.ClearActions()
+	k.syncNodeStatus()
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("expected no requests while the conditions stay the same, got %+v", actions)
+	}
+
+	k.memoryPressure = true
+	k.syncNodeStatus()
+	if got := condition(api.NodeMemoryPressure); got != api.ConditionTrue {
+		t.Errorf("MemoryPressure = %q after memory ran low, want True", got)
+	}
+	if node, _ := client.GetNode("node1"); node.Status != api.NodeReady {
+		t.Errorf("expected a node under memory pressure to stay Ready, got %s", node.Status)
+	}
+	events, _ := client.ListEvents(DefaultNamespace)
+	var reasons []string
+	for _, e := range events {
+		reasons = append(reasons, e.Reason)
+	}
+	if !slices.Contains(reasons, "NodeHasInsufficientMemory") {
+		t.Errorf("expected a NodeHasInsufficientMemory event, got %v", reasons)
+	}
+
+	// The periodic report puts back a Ready condition someone else marked Unknown.
+	node, _ := client.GetNode("node1")
+	api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionUnknown, Reason: "NodeStatusUnknown"})
+	if err := client.UpdateNode(node); err != nil {
+		t.Fatalf("UpdateNode: %v", err)
+	}
+	k.syncNodeStatus()
+	if got := condition(api.NodeReadyCondition); got != api.ConditionUnknown {
+		t.Fatalf("expected no report before nodeStatusReportFrequency passed, got Ready = %q", got)
+	}
+	clk.Step(nodeStatusReportFrequency)
+	k.syncNodeStatus()
+	if got := condition(api.NodeReadyCondition); got != api.ConditionTrue {
+		t.Errorf("Ready = %q after the periodic report, want True", got)
+	}
+}
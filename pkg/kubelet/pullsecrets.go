@@ -0,0 +1,75 @@
+package kubelet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// pullCredentials returns the credentials of pod's image pull secrets, as the
+// "user:password" for each registry host. A secret that can't be read or isn't a
+// SecretTypeDockerConfigJSON secret is skipped with a warning event, as the image may
+// still be pullable without it.
+func (k *Kubelet) pullCredentials(pod *api.Pod) map[string]string {
+	if len(pod.ImagePullSecrets) == 0 {
+		return nil
+	}
+	credentials := make(map[string]string)
+	for _, ref := range pod.ImagePullSecrets {
+		secret, err := k.APIClient.GetSecret(pod.Namespace, ref.Name)
+		if err == nil {
+			err = dockerConfigCredentials(secret, credentials)
+		}
+		if err != nil {
+			log.Printf("[%s] Pod %s: image pull secret %q: %v", k.NodeName, pod.Name, ref.Name, err)
+			k.Recorder.Eventf(pod, api.EventTypeWarning, "FailedToRetrieveImagePullSecret",
+				"Unable to retrieve some image pull secrets (%s); attempting to pull the image may not succeed.", ref.Name)
+		}
+	}
+	return credentials
+}
+
+// dockerConfigCredentials adds the credentials of a SecretTypeDockerConfigJSON secret
+// to credentials, leaving the hosts credentials already has, from earlier secrets,
+// as they are.
+func dockerConfigCredentials(secret *api.Secret, credentials map[string]string) error {
+	if secret.Type != api.SecretTypeDockerConfigJSON {
+		return fmt.Errorf("secret is of type %q, not %q", secret.Type, api.SecretTypeDockerConfigJSON)
+	}
+	var config api.DockerConfigJSON
+	if err := json.Unmarshal(secret.Data[api.DockerConfigJSONKey], &config); err != nil {
+		return fmt.Errorf("decoding %s: %w", api.DockerConfigJSONKey, err)
+	}
+	for server, entry := range config.Auths {
+		auth := entry.Username + ":" + entry.Password
+		if entry.Username == "" && entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return fmt.Errorf("decoding the auth of %s: %w", server, err)
+			}
+			auth = string(decoded)
+		}
+		host := registryHost(server)
+		if _, ok := credentials[host]; !ok {
+			credentials[host] = auth
+		}
+	}
+	return nil
+}
+
+// registryHost returns the host of a registry as a docker config names it, which may
+// be a URL such as "https://index.docker.io/v1/".
+func registryHost(server string) string {
+	if _, rest, ok := strings.Cut(server, "://"); ok {
+		server = rest
+	}
+	host, _, _ := strings.Cut(server, "/")
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return "docker.io"
+	}
+	return host
+}
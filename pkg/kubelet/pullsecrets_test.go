@@ -0,0 +1,48 @@
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestPullCredentials(t *testing.T) {
+	dockerConfig := func(name, config string) *api.Secret {
+		return &api.Secret{
+			ObjectMeta: api.ObjectMeta{Name: name, Namespace: DefaultNamespace},
+			Type:       api.SecretTypeDockerConfigJSON,
+			Data:       map[string][]byte{api.DockerConfigJSONKey: []byte(config)},
+		}
+	}
+	client := fake.NewClient(
+		dockerConfig("regcred", `{"auths":{"https://registry.local:5000/v1/":{"username":"alice","password":"s3cret"}}}`),
+		// "Ym9iOmh1bnRlcjI=" is "bob:hunter2".
+		dockerConfig("hub", `{"auths":{"https://index.docker.io/v1/":{"auth":"Ym9iOmh1bnRlcjI="},"registry.local:5000":{"username":"eve","password":"x"}}}`),
+		&api.Secret{ObjectMeta: api.ObjectMeta{Name: "opaque", Namespace: DefaultNamespace}, Data: map[string][]byte{"password": []byte("x")}},
+	)
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clock.RealClock{})
+	pod := &api.Pod{
+		ObjectMeta:       api.ObjectMeta{Name: "web", Namespace: DefaultNamespace, UID: "uid-1"},
+		Image:            "registry.local:5000/team/app:v1",
+		ImagePullSecrets: []api.LocalObjectReference{{Name: "regcred"}, {Name: "missing"}, {Name: "opaque"}, {Name: "hub"}},
+	}
+
+	want := map[string]string{"registry.local:5000": "alice:s3cret", "docker.io": "bob:hunter2"}
+	if got := k.pullCredentials(pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected credentials %v, with the first secret's winning, got %v", want, got)
+	}
+	events, _ := client.ListEvents(DefaultNamespace)
+	failed := 0
+	for _, event := range events {
+		if event.Reason == "FailedToRetrieveImagePullSecret" {
+			failed++
+		}
+	}
+	if failed != 2 {
+		t.Errorf("expected a warning for the missing and the opaque secrets, got %d", failed)
+	}
+}
@@ -0,0 +1,424 @@
+package kubelet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+)
+
+// podRuntime stands in for a container runtime: it tracks the pods the kubelet is
+// running, from the sync that starts each one until the sync that stops it, along with
+// their output, which it writes to log files under logDir (see containerlogs.go). The
+// kubelet's HTTP server reports from it.
+type podRuntime struct {
+	clock  clock.Clock
+	logDir string
+
+	mu          sync.Mutex
+	pods        map[string]*runtimePod // By namespace/name
+	starts      uint64
+	stops       uint64
+	logMaxSize  int64 // How large a log file grows before it is rotated
+	logMaxFiles int   // How many log files of each run of a container are kept
+}
+
+// runtimePod is a pod as the runtime runs it.
+type runtimePod struct {
+	pod     api.Pod
+	mounts  map[string]string // Host path of each volume mount, by mount path
+	started time.Time
+	logDir  string        // Holds the logs of the pod's container, by UID as it started
+	log     *containerLog // Nil if the log file couldn't be opened
+	// followers receive each line of output as it is written, for attach.
+	followers map[chan string]bool
+}
+
+// followBuffer is how many lines of output a follower may fall behind by before it
+// misses lines.
+const followBuffer = 256
+
+func newPodRuntime(clk clock.Clock, logDir string) *podRuntime {
+	return &podRuntime{
+		clock:       clk,
+		logDir:      logDir,
+		pods:        make(map[string]*runtimePod),
+		logMaxSize:  DefaultContainerLogMaxSize,
+		logMaxFiles: DefaultContainerLogMaxFiles,
+	}
+}
+
+// setLogLimits changes how large log files grow before they are rotated, and how many
+// are kept of each run of a container.
+func (r *podRuntime) setLogLimits(maxSize int64, maxFiles int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logMaxSize, r.logMaxFiles = maxSize, maxFiles
+}
+
+// openLog opens the log file of the current run of p's container. The runtime's lock
+// must be held.
+func (r *podRuntime) openLog(p *runtimePod) {
+	if p.log != nil {
+		p.log.close()
+		p.log = nil
+	}
+	if p.logDir == "" {
+		p.logDir = podLogDir(r.logDir, &p.pod)
+	}
+	l, err := openContainerLog(p.logDir, p.pod.RestartCount)
+	if err != nil {
+		log.Printf("Error opening the log file of pod %s/%s, whose output is not kept: %v", p.pod.Namespace, p.pod.Name, err)
+		return
+	}
+	p.log = l
+}
+
+// remove stops the pod with key, discarding its logs. The runtime's lock must be held.
+func (r *podRuntime) remove(key string, p *runtimePod) {
+	p.unfollowAll()
+	if p.log != nil {
+		p.log.close()
+	}
+	os.RemoveAll(p.logDir)
+	delete(r.pods, key)
+	r.stops++
+}
+
+func podKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// start runs pod with its volumes mounted as in mounts. Starting a pod that is already
+// running just updates the runtime's copy of it. A pod with a command runs it as the
+// container starts, connecting through connect, with its output going to the pod's
+// logs.
+func (r *podRuntime) start(pod *api.Pod, mounts map[string]string, connect connectFunc) {
+	r.mu.Lock()
+	key := podKey(pod.Namespace, pod.Name)
+	if p, ok := r.pods[key]; ok {
+		p.pod = *pod
+		r.mu.Unlock()
+		return
+	}
+	p := &runtimePod{pod: *pod, mounts: mounts, started: r.clock.Now()}
+	r.pods[key] = p
+	r.starts++
+	r.openLog(p)
+	mountPaths := make([]string, 0, len(mounts))
+	for mountPath := range mounts {
+		mountPaths = append(mountPaths, mountPath)
+	}
+	sort.Strings(mountPaths)
+	for _, mountPath := range mountPaths {
+		r.logf(p, "Mounted %s at %s", mounts[mountPath], mountPath)
+	}
+	r.logf(p, "Started container with image %s%s", pod.Image, describeProcess(pod))
+	r.mu.Unlock()
+
+	// Outside the lock: the command may connect to other pods, which asks the runtime.
+	if len(pod.Command) > 0 {
+		out := &logWriter{runtime: r, namespace: pod.Namespace, name: pod.Name}
+		streams := execStreams{stdout: out, stderr: out}
+		if pod.TTY {
+			streams.size = func() (streaming.TerminalSize, bool) { return defaultTerminalSize, true }
+		}
+		code := runCommand(pod, mounts, connect, append(append([]string(nil), pod.Command...), pod.Args...), streams)
+		out.flush()
+		r.input(pod.Namespace, pod.Name, fmt.Sprintf("Process exited with code %d", code))
+	}
+}
+
+// adopt takes back a pod the runtime ran before the kubelet restarted, whose container
+// started at started and has been running since: unlike start, it neither runs the
+// pod's command again nor counts a start.
+func (r *podRuntime) adopt(pod *api.Pod, mounts map[string]string, started time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := podKey(pod.Namespace, pod.Name)
+	if _, ok := r.pods[key]; ok {
+		return
+	}
+	p := &runtimePod{pod: *pod, mounts: mounts, started: started}
+	r.pods[key] = p
+	r.openLog(p)
+	r.logf(p, "Kubelet restarted; container with image %s still running since %s", pod.Image, started.UTC().Format(time.RFC3339))
+}
+
+// defaultTerminalSize is the size of the terminal of a pod with TTY set, which no
+// client has sized.
+var defaultTerminalSize = streaming.TerminalSize{Width: 80, Height: 24}
+
+// describeProcess describes the process a pod's container runs, for its logs: nothing
+// for the image's own entrypoint run as is.
+func describeProcess(pod *api.Pod) string {
+	var b strings.Builder
+	switch {
+	case len(pod.Command) > 0:
+		fmt.Fprintf(&b, " running %q", strings.Join(append(append([]string(nil), pod.Command...), pod.Args...), " "))
+	case len(pod.Args) > 0:
+		fmt.Fprintf(&b, " passing its entrypoint %q", strings.Join(pod.Args, " "))
+	}
+	if pod.WorkingDir != "" {
+		fmt.Fprintf(&b, " in %s", pod.WorkingDir)
+	}
+	if pod.TTY {
+		b.WriteString(" with a terminal")
+	}
+	if sc := pod.SecurityContext; sc != nil {
+		if sc.RunAsUser != nil {
+			fmt.Fprintf(&b, " as user %d", *sc.RunAsUser)
+		}
+		if sc.Privileged {
+			b.WriteString(", privileged")
+		}
+		if sc.ReadOnlyRootFilesystem {
+			b.WriteString(", with a read-only root filesystem")
+		}
+	}
+	return b.String()
+}
+
+// exited notes in a running pod's output that its container exited with code.
+func (r *podRuntime) exited(namespace, name string, code int) {
+	r.input(namespace, name, fmt.Sprintf("Process exited with code %d", code))
+}
+
+// restart runs a running pod's container again after it exited: the pod's output is
+// kept, and its run starts over now.
+func (r *podRuntime) restart(pod *api.Pod) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pods[podKey(pod.Namespace, pod.Name)]
+	if !ok {
+		return
+	}
+	p.pod, p.started = *pod, r.clock.Now()
+	r.starts++
+	r.openLog(p)
+	r.logf(p, "Restarted container with image %s (restart %d)", pod.Image, pod.RestartCount)
+}
+
+// startedAt returns when the current run of a running pod's container started.
+func (r *podRuntime) startedAt(namespace, name string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pods[podKey(namespace, name)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return p.started, true
+}
+
+// running reports whether pod is running.
+func (r *podRuntime) running(pod *api.Pod) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.pods[podKey(pod.Namespace, pod.Name)]
+	return ok
+}
+
+// stop stops pod, discarding its output, if it is running.
+func (r *podRuntime) stop(namespace, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := podKey(namespace, name)
+	if p, ok := r.pods[key]; ok {
+		r.remove(key, p)
+	}
+}
+
+// retain stops every pod whose key is not in keep, and removes the logs of pods that
+// aren't running, such as pods deleted while the kubelet was down.
+func (r *podRuntime) retain(keep map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, p := range r.pods {
+		if !keep[key] {
+			r.remove(key, p)
+		}
+	}
+	entries, err := os.ReadDir(r.logDir)
+	if err != nil {
+		return
+	}
+	running := make(map[string]bool, len(r.pods))
+	for _, p := range r.pods {
+		running[filepath.Base(p.logDir)] = true
+	}
+	for _, e := range entries {
+		if !running[e.Name()] {
+			os.RemoveAll(filepath.Join(r.logDir, e.Name()))
+		}
+	}
+}
+
+// list returns the running pods, sorted by namespace and name.
+func (r *podRuntime) list() []api.Pod {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pods := make([]api.Pod, 0, len(r.pods))
+	for _, p := range r.pods {
+		pods = append(pods, p.pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return podKey(pods[i].Namespace, pods[i].Name) < podKey(pods[j].Namespace, pods[j].Name)
+	})
+	return pods
+}
+
+// errNoPreviousLogs is returned by logs for the previous container of a pod whose
+// container hasn't restarted, or whose previous container's logs are gone.
+var errNoPreviousLogs = errors.New("previous terminated container not found")
+
+// logs returns the output of a running pod that opts selects, one line per element:
+// that of its container's current run, or with opts.Previous that of the run before.
+func (r *podRuntime) logs(namespace, name string, opts api.PodLogOptions) ([]string, bool, error) {
+	r.mu.Lock()
+	p, ok := r.pods[podKey(namespace, name)]
+	if !ok {
+		r.mu.Unlock()
+		return nil, false, nil
+	}
+	dir, run := p.logDir, p.pod.RestartCount
+	if p.log != nil {
+		run = p.log.run
+	}
+	r.mu.Unlock()
+
+	if opts.Previous {
+		run--
+		if _, err := os.Stat(runLogPath(dir, run)); run < 0 || err != nil {
+			return nil, true, errNoPreviousLogs
+		}
+	}
+	lines, err := readRunLogs(dir, run, opts, r.clock.Now())
+	return lines, true, err
+}
+
+// container returns a running pod and the host paths of its volume mounts, by mount
+// path, for running commands in it.
+func (r *podRuntime) container(namespace, name string) (api.Pod, map[string]string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pods[podKey(namespace, name)]
+	if !ok {
+		return api.Pod{}, nil, false
+	}
+	return p.pod, p.mounts, true
+}
+
+// exec runs command in a running pod.
+func (r *podRuntime) exec(namespace, name string, connect connectFunc, command []string) (api.ExecResult, bool) {
+	pod, mounts, ok := r.container(namespace, name)
+	if !ok {
+		return api.ExecResult{}, false
+	}
+	return execCommand(&pod, mounts, connect, command), true
+}
+
+// follow returns a channel receiving each line a running pod outputs from now on.
+// It is closed when the pod stops or unfollow is called.
+func (r *podRuntime) follow(namespace, name string) (lines <-chan string, unfollow func(), ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pods[podKey(namespace, name)]
+	if !ok {
+		return nil, nil, false
+	}
+	ch := make(chan string, followBuffer)
+	if p.followers == nil {
+		p.followers = make(map[chan string]bool)
+	}
+	p.followers[ch] = true
+	return ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if p.followers[ch] {
+			delete(p.followers, ch)
+			close(ch)
+		}
+	}, true
+}
+
+// input gives a running pod a line of input, which its simulated process echoes to
+// its output. It also adds the output of the pod's command to its logs.
+func (r *podRuntime) input(namespace, name, line string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pods[podKey(namespace, name)]
+	if ok {
+		r.logf(p, "%s", line)
+	}
+	return ok
+}
+
+// counts returns how many pods are running, and how many have been started and
+// stopped in all.
+func (r *podRuntime) counts() (running int, starts, stops uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pods), r.starts, r.stops
+}
+
+// logf adds a timestamped line to p's output. The runtime's lock must be held.
+func (r *podRuntime) logf(p *runtimePod, format string, args ...interface{}) {
+	line := r.clock.Now().UTC().Format(time.RFC3339) + " " + fmt.Sprintf(format, args...)
+	if p.log != nil {
+		if err := p.log.write(line, r.logMaxSize, r.logMaxFiles); err != nil {
+			log.Printf("Error writing the output of pod %s/%s to its log file: %v", p.pod.Namespace, p.pod.Name, err)
+		}
+	}
+	for ch := range p.followers {
+		select {
+		case ch <- line:
+		default: // The follower fell behind; it misses the line
+		}
+	}
+}
+
+// logWriter writes a running pod's output to its logs, a line at a time.
+type logWriter struct {
+	runtime         *podRuntime
+	namespace, name string
+	partial         []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.runtime.input(w.namespace, w.name, string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+}
+
+// flush writes out the last line of output, if it didn't end with a newline.
+func (w *logWriter) flush() {
+	if len(w.partial) > 0 {
+		w.runtime.input(w.namespace, w.name, string(w.partial))
+		w.partial = nil
+	}
+}
+
+// unfollowAll closes the channels of the pod's followers, as it stops. The runtime's
+// lock must be held.
+func (p *runtimePod) unfollowAll() {
+	for ch := range p.followers {
+		close(ch)
+	}
+	p.followers = nil
+}
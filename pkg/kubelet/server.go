@@ -0,0 +1,234 @@
+package kubelet
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// unhealthySyncs is how many sync intervals may pass without a pod sync, or how many
+// pod syncs may fail in a row for want of the API server, before the kubelet reports
+// itself unhealthy.
+const unhealthySyncs = 3
+
+// Handler returns the HTTP handler serving the kubelet's API, which the API server
+// proxies pod logs, exec, attach, port-forward, and node proxy requests to:
+//
+//	GET  /pods                      the pods the kubelet is running
+//	GET  /healthz                   "ok", or 503 if pod syncs have stalled or keep failing
+//	GET  /logs/{pod}                a pod's output as text; see logsHandler
+//	POST /exec/{pod}?command=...    run a command in a pod; the result is an api.ExecResult
+//	GET  /exec/{pod}?command=...    run a command in a pod over a stream
+//	GET  /attach/{pod}              stream a pod's output, and with ?stdin=true its input
+//	GET  /portforward/{pod}?port=N  forward a stream to a port of a pod
+//	GET  /metrics                   pod and sync counts and API back-off in the Prometheus text format
+//
+// Streams are WebSockets speaking the protocol of package streaming. Requests about
+// a pod take its namespace as ?namespace=, defaulting to "default".
+// Everything but /healthz requires the bearer token in ServerToken, if it is set.
+func (k *Kubelet) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", k.healthzHandler)
+	mux.Handle("GET /pods", k.authenticated(k.podsHandler))
+	mux.Handle("GET /logs/{pod}", k.authenticated(k.logsHandler))
+	mux.Handle("POST /exec/{pod}", k.authenticated(k.execHandler))
+	mux.Handle("GET /exec/{pod}", k.authenticated(k.execStreamHandler))
+	mux.Handle("GET /attach/{pod}", k.authenticated(k.attachHandler))
+	mux.Handle("GET /portforward/{pod}", k.authenticated(k.portForwardHandler))
+	mux.Handle("GET /metrics", k.authenticated(k.metricsHandler))
+	return mux
+}
+
+// serve serves the kubelet's API on the port of NodeAddress until ctx is cancelled.
+// A port that can't be listened on is logged rather than fatal, as the pods still run.
+func (k *Kubelet) serve(ctx context.Context) {
+	_, port, err := net.SplitHostPort(k.NodeAddress)
+	if err != nil {
+		log.Printf("[%s] Not serving the kubelet API: node address %q has no port", k.NodeName, k.NodeAddress)
+		return
+	}
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Printf("[%s] Not serving the kubelet API: %v", k.NodeName, err)
+		return
+	}
+	srv := &http.Server{Handler: k.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	log.Printf("[%s] Kubelet API listening on %s", k.NodeName, ln.Addr())
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
+		log.Printf("[%s] Kubelet API stopped: %v", k.NodeName, err)
+	}
+}
+
+// authenticated rejects requests without the kubelet's bearer token, if it has one.
+func (k *Kubelet) authenticated(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if k.ServerToken != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(k.ServerToken)) != 1 {
+				writeError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+		}
+		h(w, r)
+	})
+}
+
+func (k *Kubelet) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if failures := k.apiBackoff.Failures(); failures >= unhealthySyncs {
+		http.Error(w, fmt.Sprintf("API server unreachable: %d pod syncs failed in a row, backing off %v", failures, k.apiBackoff.Delay()), http.StatusServiceUnavailable)
+		return
+	}
+	last := k.lastSync.Load()
+	if last == 0 {
+		http.Error(w, "pods not synced yet", http.StatusServiceUnavailable)
+		return
+	}
+	switch since := k.Clock.Since(time.Unix(0, last)); {
+	case since > unhealthySyncs*k.SyncInterval():
+		http.Error(w, fmt.Sprintf("pods last synced %v ago", since.Round(time.Second)), http.StatusServiceUnavailable)
+	default:
+		fmt.Fprintln(w, "ok")
+	}
+}
+
+func (k *Kubelet) podsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, k.runtime.list())
+}
+
+// logsHandler serves a pod's output: that of its container's current run, or with
+// ?previous=true the run before it restarted, only the last lines with ?tailLines=N,
+// and only the lines of the last seconds with ?sinceSeconds=N. ?container= may name
+// the pod's container, which is named after the pod.
+func (k *Kubelet) logsHandler(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestPod(r)
+	opts, err := podLogOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.Container != "" && opts.Container != name {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Container %s is not valid for pod %s/%s; its only container is %s", opts.Container, namespace, name, name))
+		return
+	}
+	lines, ok, err := k.runtime.logs(namespace, name, opts)
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Pod %s/%s is not running on node %s", namespace, name, k.NodeName))
+		return
+	case errors.Is(err, errNoPreviousLogs):
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Pod %s/%s: %v", namespace, name, err))
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Reading the logs of pod %s/%s: %v", namespace, name, err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func (k *Kubelet) execHandler(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestPod(r)
+	command := r.URL.Query()["command"]
+	if len(command) == 0 {
+		writeError(w, http.StatusBadRequest, "No command given; pass it as ?command=...")
+		return
+	}
+	result, ok := k.runtime.exec(namespace, name, k.proxy.Connect, command)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Pod %s/%s is not running on node %s", namespace, name, k.NodeName))
+		return
+	}
+	log.Printf("[%s] Ran %q in pod %s/%s: exit code %d", k.NodeName, command, namespace, name, result.ExitCode)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (k *Kubelet) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	running, starts, stops := k.runtime.counts()
+	node := fmt.Sprintf("{node=%q}", k.NodeName)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprintln(w, "# HELP kubelet_running_pods Number of pods the kubelet is running.")
+	fmt.Fprintln(w, "# TYPE kubelet_running_pods gauge")
+	fmt.Fprintf(w, "kubelet_running_pods%s %d\n", node, running)
+	fmt.Fprintln(w, "# HELP kubelet_pod_starts_total Number of pods the kubelet has started.")
+	fmt.Fprintln(w, "# TYPE kubelet_pod_starts_total counter")
+	fmt.Fprintf(w, "kubelet_pod_starts_total%s %d\n", node, starts)
+	fmt.Fprintln(w, "# HELP kubelet_pod_stops_total Number of pods the kubelet has stopped.")
+	fmt.Fprintln(w, "# TYPE kubelet_pod_stops_total counter")
+	fmt.Fprintf(w, "kubelet_pod_stops_total%s %d\n", node, stops)
+	fmt.Fprintln(w, "# HELP kubelet_pod_syncs_total Number of pod syncs the kubelet has run.")
+	fmt.Fprintln(w, "# TYPE kubelet_pod_syncs_total counter")
+	fmt.Fprintf(w, "kubelet_pod_syncs_total%s %d\n", node, k.syncs.Load())
+	fmt.Fprintln(w, "# HELP kubelet_api_consecutive_failures Number of pod syncs in a row that failed to reach the API server.")
+	fmt.Fprintln(w, "# TYPE kubelet_api_consecutive_failures gauge")
+	fmt.Fprintf(w, "kubelet_api_consecutive_failures%s %d\n", node, k.apiBackoff.Failures())
+	fmt.Fprintln(w, "# HELP kubelet_api_backoff_seconds How long the kubelet waits before retrying a failed pod sync, 0 while syncs succeed.")
+	fmt.Fprintln(w, "# TYPE kubelet_api_backoff_seconds gauge")
+	fmt.Fprintf(w, "kubelet_api_backoff_seconds%s %g\n", node, k.apiBackoff.Delay().Seconds())
+	if last := k.lastSync.Load(); last != 0 {
+		fmt.Fprintln(w, "# HELP kubelet_last_sync_timestamp_seconds When the last pod sync finished, in seconds since the epoch.")
+		fmt.Fprintln(w, "# TYPE kubelet_last_sync_timestamp_seconds gauge")
+		fmt.Fprintf(w, "kubelet_last_sync_timestamp_seconds%s %d\n", node, time.Unix(0, last).Unix())
+	}
+}
+
+// podLogOptions returns the options of a request for a pod's logs.
+func podLogOptions(r *http.Request) (api.PodLogOptions, error) {
+	var opts api.PodLogOptions
+	query := r.URL.Query()
+	if s := query.Get("tailLines"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("tailLines must be a non-negative integer, got %q", s)
+		}
+		opts.TailLines = n
+	}
+	if s := query.Get("sinceSeconds"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n <= 0 {
+			return opts, fmt.Errorf("sinceSeconds must be a positive integer, got %q", s)
+		}
+		opts.SinceSeconds = n
+	}
+	opts.Previous = queryBool(r, "previous")
+	opts.Container = query.Get("container")
+	return opts, nil
+}
+
+// requestPod returns the namespace and name of the pod a request is about.
+func requestPod(r *http.Request) (namespace, name string) {
+	namespace = r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return namespace, r.PathValue("pod")
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with an error body like the API server's, which api.Client
+// turns into a *api.StatusError.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
@@ -0,0 +1,281 @@
+package kubelet
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+)
+
+func TestServer(t *testing.T) {
+	hostDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(hostDir, "index.html"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewClient(&api.Pod{
+		ObjectMeta:   api.ObjectMeta{Name: "web", Namespace: DefaultNamespace, UID: "uid-1"},
+		Image:        "nginx:1.25",
+		NodeName:     "node1",
+		Phase:        api.PodScheduled,
+		Volumes:      []api.Volume{{Name: "html", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: hostDir}}}},
+		VolumeMounts: []api.VolumeMount{{Name: "html", MountPath: "/usr/share/nginx/html"}},
+	})
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clock.RealClock{})
+	k.ServerToken = "secret"
+	srv := httptest.NewServer(k.Handler())
+	defer srv.Close()
+
+	request := func(method, path string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	if resp := request(http.MethodGet, "/healthz"); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected /healthz to fail before the first sync, got %d", resp.StatusCode)
+	}
+	if resp := request(http.MethodGet, "/logs/web"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for the logs of a pod that isn't running, got %d", resp.StatusCode)
+	}
+
+	k.syncPods()
+	k.lastSync.Store(time.Now().UnixNano())
+	if resp := request(http.MethodGet, "/healthz"); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected /healthz to pass after a sync, got %d", resp.StatusCode)
+	}
+
+	var pods []api.Pod
+	if err := json.NewDecoder(request(http.MethodGet, "/pods").Body).Decode(&pods); err != nil || len(pods) != 1 || pods[0].Name != "web" {
+		t.Errorf("expected /pods to list the running pod, got %+v (%v)", pods, err)
+	}
+
+	resp := request(http.MethodGet, "/logs/web?namespace=default")
+	logs, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(logs), "Started container with image nginx:1.25") {
+		t.Errorf("expected the pod's start in its logs, got %d: %q", resp.StatusCode, logs)
+	}
+	for path, code := range map[string]int{
+		"/logs/web?container=web":   http.StatusOK,
+		"/logs/web?container=proxy": http.StatusBadRequest,
+		"/logs/web?previous=true":   http.StatusBadRequest, // It hasn't restarted
+		"/logs/web?tailLines=-1":    http.StatusBadRequest,
+	} {
+		if resp := request(http.MethodGet, path); resp.StatusCode != code {
+			t.Errorf("GET %s: expected %d, got %d", path, code, resp.StatusCode)
+		}
+	}
+
+	var result api.ExecResult
+	if err := json.NewDecoder(request(http.MethodPost, "/exec/web?command=cat&command=/usr/share/nginx/html/index.html").Body).Decode(&result); err != nil {
+		t.Fatalf("decoding exec result: %v", err)
+	}
+	if result.Stdout != "hello\n" || result.ExitCode != 0 {
+		t.Errorf("expected cat to print the mounted file, got %+v", result)
+	}
+	result = api.ExecResult{}
+	if err := json.NewDecoder(request(http.MethodPost, "/exec/web?command=sh").Body).Decode(&result); err != nil {
+		t.Fatalf("decoding exec result: %v", err)
+	}
+	if result.ExitCode != 127 {
+		t.Errorf("expected exit code 127 for a missing command, got %+v", result)
+	}
+	result = api.ExecResult{}
+	if err := json.NewDecoder(request(http.MethodPost, "/exec/web?command=nc&command=-z&command=10.244.9.9&command=80").Body).Decode(&result); err != nil {
+		t.Fatalf("decoding exec result: %v", err)
+	}
+	if result.ExitCode != 1 || !strings.Contains(result.Stderr, "connection refused") {
+		t.Errorf("expected nc to a pod IP nothing has to be refused, got %+v", result)
+	}
+
+	unauthenticated, err := http.Get(srv.URL + "/pods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unauthenticated.Body.Close()
+	if unauthenticated.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the token, got %d", unauthenticated.StatusCode)
+	}
+
+	// Once the pod is deleted, the kubelet stops it.
+	if err := client.DeletePod(DefaultNamespace, "web"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	k.syncPods()
+	if resp := request(http.MethodGet, "/logs/web"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for the logs of a stopped pod, got %d", resp.StatusCode)
+	}
+
+	// Pod syncs that keep failing to reach the API server make the kubelet unhealthy,
+	// however recently the last one succeeded.
+	for i := 0; i < unhealthySyncs; i++ {
+		k.apiBackoff.Next()
+	}
+	if resp := request(http.MethodGet, "/healthz"); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected /healthz to fail while the API server is unreachable, got %d", resp.StatusCode)
+	}
+	metrics, _ := io.ReadAll(request(http.MethodGet, "/metrics").Body)
+	if !strings.Contains(string(metrics), `kubelet_api_consecutive_failures{node="node1"} 3`) {
+		t.Errorf("expected the failures in /metrics, got:\n%s", metrics)
+	}
+}
+
+func TestServerStreams(t *testing.T) {
+	client := fake.NewClient(&api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: DefaultNamespace, UID: "uid-1"},
+		Image:      "nginx:1.25",
+		NodeName:   "node1",
+		Phase:      api.PodScheduled,
+	})
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clock.RealClock{})
+	srv := httptest.NewServer(k.Handler())
+	defer srv.Close()
+	k.syncPods()
+
+	stream := func(path string, opts streaming.Options) (streaming.Status, error) {
+		t.Helper()
+		req, err := streaming.NewRequest(srv.URL + path)
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			resp.Body.Close()
+			t.Fatalf("expected GET %s to switch protocols, got %s", path, resp.Status)
+		}
+		conn, err := streaming.Open(resp)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		return conn.Stream(opts)
+	}
+
+	var stdout strings.Builder
+	status, err := stream("/exec/web?command=cat&stdin=true", streaming.Options{Stdin: strings.NewReader("hello\n"), Stdout: &stdout})
+	if err != nil || status.ExitCode != 0 || stdout.String() != "hello\n" {
+		t.Errorf("expected cat to echo its input, got %q %+v (%v)", stdout.String(), status, err)
+	}
+
+	stdout.Reset()
+	resize := make(chan streaming.TerminalSize, 1)
+	resize <- streaming.TerminalSize{Width: 120, Height: 40}
+	status, err = stream("/exec/web?command=stty&command=size&tty=true", streaming.Options{Stdout: &stdout, Resize: resize})
+	if err != nil || status.ExitCode != 0 || stdout.String() != "40 120\n" {
+		t.Errorf("expected stty size to print the terminal size, got %q %+v (%v)", stdout.String(), status, err)
+	}
+
+	stdout.Reset()
+	status, err = stream("/attach/web?stdin=true", streaming.Options{Stdin: strings.NewReader("ping\n"), Stdout: &stdout})
+	if err != nil || status.Error != "" || !strings.HasSuffix(stdout.String(), " ping\n") {
+		t.Errorf("expected attach to stream the echoed input, got %q %+v (%v)", stdout.String(), status, err)
+	}
+	if logs, _, _ := k.runtime.logs(DefaultNamespace, "web", api.PodLogOptions{}); !strings.HasSuffix(logs[len(logs)-1], " ping") {
+		t.Errorf("expected the attached input in the pod's logs, got %q", logs)
+	}
+
+	stdout.Reset()
+	status, err = stream("/portforward/web?port=80", streaming.Options{Stdin: strings.NewReader("GET /index.html HTTP/1.1\r\nHost: web\r\nConnection: close\r\n\r\n"), Stdout: &stdout})
+	if err != nil || status.Error != "" || !strings.HasPrefix(stdout.String(), "HTTP/1.1 200 OK") || !strings.Contains(stdout.String(), "Hello from pod default/web (image nginx:1.25) on port 80: GET /index.html") {
+		t.Errorf("expected an HTTP response from the forwarded port, got %q %+v (%v)", stdout.String(), status, err)
+	}
+
+	resp, err := http.Get(srv.URL + "/exec/web?command=hostname")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a stream request that isn't a WebSocket upgrade, got %d", resp.StatusCode)
+	}
+}
+
+func TestPodProcess(t *testing.T) {
+	hostDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(hostDir, "index.html"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewClient(&api.Pod{
+		ObjectMeta:   api.ObjectMeta{Name: "web", Namespace: DefaultNamespace, UID: "uid-1"},
+		Image:        "busybox:1.36",
+		NodeName:     "node1",
+		Phase:        api.PodScheduled,
+		Command:      []string{"cat"},
+		Args:         []string{"index.html"},
+		WorkingDir:   "/usr/share/nginx/html",
+		TTY:          true,
+		Volumes:      []api.Volume{{Name: "html", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: hostDir}}}},
+		VolumeMounts: []api.VolumeMount{{Name: "html", MountPath: "/usr/share/nginx/html"}},
+	})
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clock.RealClock{})
+	k.syncPods()
+
+	logs, _, _ := k.runtime.logs(DefaultNamespace, "web", api.PodLogOptions{})
+	want := []string{
+		`Started container with image busybox:1.36 running "cat index.html" in /usr/share/nginx/html with a terminal`,
+		"hello",
+		"Process exited with code 0",
+	}
+	if len(logs) < len(want) {
+		t.Fatalf("expected the command's output in the logs, got %q", logs)
+	}
+	for i, line := range logs[len(logs)-len(want):] {
+		if !strings.HasSuffix(line, " "+want[i]) {
+			t.Errorf("log line %d = %q, want it to end with %q", i, line, want[i])
+		}
+	}
+
+	result, _ := k.runtime.exec(DefaultNamespace, "web", nil, []string{"pwd"})
+	if result.Stdout != "/usr/share/nginx/html\n" {
+		t.Errorf("expected exec to start in the working directory, got %+v", result)
+	}
+	result, _ = k.runtime.exec(DefaultNamespace, "web", nil, []string{"ls"})
+	if result.Stdout != "index.html\n" {
+		t.Errorf("expected ls to list the working directory, got %+v", result)
+	}
+}
+
+func TestPodSecurityContext(t *testing.T) {
+	uid := int64(1000)
+	client := fake.NewClient(&api.Pod{
+		ObjectMeta:      api.ObjectMeta{Name: "web", Namespace: DefaultNamespace, UID: "uid-1"},
+		Image:           "busybox:1.36",
+		NodeName:        "node1",
+		Phase:           api.PodScheduled,
+		SecurityContext: &api.SecurityContext{RunAsUser: &uid, ReadOnlyRootFilesystem: true},
+		Volumes:         []api.Volume{{Name: "data", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: t.TempDir()}}}},
+		VolumeMounts:    []api.VolumeMount{{Name: "data", MountPath: "/data"}},
+	})
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clock.RealClock{})
+	k.syncPods()
+
+	if result, _ := k.runtime.exec(DefaultNamespace, "web", nil, []string{"id"}); result.Stdout != "uid=1000 gid=1000 groups=1000\n" {
+		t.Errorf("expected id to report the pod's runAsUser, got %+v", result)
+	}
+	if result, _ := k.runtime.exec(DefaultNamespace, "web", nil, []string{"touch", "/tmp/x"}); result.ExitCode != 1 || !strings.Contains(result.Stderr, "Read-only file system") {
+		t.Errorf("expected writing the read-only root filesystem to fail, got %+v", result)
+	}
+	if result, _ := k.runtime.exec(DefaultNamespace, "web", nil, []string{"touch", "/data/x"}); result.ExitCode != 0 {
+		t.Errorf("expected writing a volume to succeed, got %+v", result)
+	}
+	if result, _ := k.runtime.exec(DefaultNamespace, "web", nil, []string{"ls", "/data"}); result.Stdout != "x\n" {
+		t.Errorf("expected the touched file in the volume, got %+v", result)
+	}
+}
@@ -0,0 +1,174 @@
+package kubelet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/streaming"
+)
+
+// firstResizeWait is how long a command given a terminal waits for the client to send
+// the terminal's size before it starts.
+const firstResizeWait = time.Second
+
+// execStreamHandler runs a command in a pod over a stream, with ?stdin=true to give it
+// the client's input and ?tty=true to give it a terminal, whose output goes to stdout
+// only. The stream's status carries the command's exit code.
+func (k *Kubelet) execStreamHandler(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestPod(r)
+	command := r.URL.Query()["command"]
+	if len(command) == 0 {
+		writeError(w, http.StatusBadRequest, "No command given; pass it as ?command=...")
+		return
+	}
+	pod, mounts, ok := k.runtime.container(namespace, name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Pod %s/%s is not running on node %s", namespace, name, k.NodeName))
+		return
+	}
+	conn, err := streaming.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	streams := execStreams{stdout: conn.Stdout(), stderr: conn.Stderr()}
+	if queryBool(r, "stdin") {
+		streams.stdin = conn.Stdin()
+	}
+	if queryBool(r, "tty") {
+		streams.stderr = streams.stdout
+		var size streaming.TerminalSize
+		select {
+		case size = <-conn.Resizes():
+		case <-time.After(firstResizeWait):
+		}
+		streams.size = func() (streaming.TerminalSize, bool) {
+			select {
+			case size = <-conn.Resizes():
+			default:
+			}
+			return size, true
+		}
+	}
+	code := runCommand(&pod, mounts, k.proxy.Connect, command, streams)
+	log.Printf("[%s] Ran %q in pod %s/%s over a stream: exit code %d", k.NodeName, command, namespace, name, code)
+	conn.Close(streaming.Status{ExitCode: code})
+}
+
+// attachHandler streams a pod's output from now on, until the pod stops or the client
+// goes away. With ?stdin=true, each line the client sends is input to the pod's
+// process, which echoes it to its output, and the stream ends with the input.
+func (k *Kubelet) attachHandler(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestPod(r)
+	lines, unfollow, ok := k.runtime.follow(namespace, name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Pod %s/%s is not running on node %s", namespace, name, k.NodeName))
+		return
+	}
+	defer unfollow()
+	conn, err := streaming.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	var inputDone chan struct{}
+	if queryBool(r, "stdin") {
+		inputDone = make(chan struct{})
+		go func() {
+			defer close(inputDone)
+			scanner := bufio.NewScanner(conn.Stdin())
+			for scanner.Scan() {
+				if !k.runtime.input(namespace, name, scanner.Text()) {
+					return
+				}
+			}
+		}()
+	}
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				conn.Close(streaming.Status{Error: fmt.Sprintf("pod %s/%s stopped", namespace, name)})
+				return
+			}
+			fmt.Fprintln(conn.Stdout(), line)
+		case <-inputDone:
+			// The input's echoes are already waiting in lines.
+			for len(lines) > 0 {
+				fmt.Fprintln(conn.Stdout(), <-lines)
+			}
+			conn.Close(streaming.Status{})
+			return
+		case <-conn.Done():
+			conn.Close(streaming.Status{})
+			return
+		}
+	}
+}
+
+// portForwardHandler forwards a stream to ?port= on a pod: what the client sends on
+// stdin goes to the port and what the port answers comes back on stdout.
+func (k *Kubelet) portForwardHandler(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestPod(r)
+	port, err := strconv.Atoi(r.URL.Query().Get("port"))
+	if err != nil || port < 1 || port > 65535 {
+		writeError(w, http.StatusBadRequest, "Invalid port; pass a port from 1 to 65535 as ?port=...")
+		return
+	}
+	pod, _, ok := k.runtime.container(namespace, name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Pod %s/%s is not running on node %s", namespace, name, k.NodeName))
+		return
+	}
+	conn, err := streaming.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	var status streaming.Status
+	if err := servePort(&pod, port, conn.Stdin(), conn.Stdout()); err != nil {
+		status.Error = fmt.Sprintf("port %d of pod %s/%s: %v", port, namespace, name, err)
+	}
+	conn.Close(status)
+}
+
+// servePort answers what a connection to port on pod sends, standing in for the
+// container's server: every port of a simulated pod speaks HTTP/1.1 and answers each
+// request with a line naming the pod.
+func servePort(pod *api.Pod, port int, in io.Reader, out io.Writer) error {
+	br := bufio.NewReader(in)
+	for {
+		req, err := http.ReadRequest(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading HTTP request: %w", err)
+		}
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+		body := fmt.Sprintf("Hello from pod %s/%s (image %s) on port %d: %s %s\n", pod.Namespace, pod.Name, pod.Image, port, req.Method, req.URL.RequestURI())
+		resp := &http.Response{
+			StatusCode:    http.StatusOK,
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+			ContentLength: int64(len(body)),
+			Body:          io.NopCloser(strings.NewReader(body)),
+			Close:         req.Close,
+		}
+		if err := resp.Write(out); err != nil || req.Close {
+			return err
+		}
+	}
+}
+
+// queryBool reports whether the request's query sets name to true.
+func queryBool(r *http.Request, name string) bool {
+	b, _ := strconv.ParseBool(r.URL.Query().Get(name))
+	return b
+}
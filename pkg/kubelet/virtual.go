@@ -0,0 +1,110 @@
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// VirtualNode names and addresses one of several nodes simulated by a single process.
+type VirtualNode struct {
+	Name    string
+	Address string
+}
+
+// VirtualNodes returns n nodes named prefix-1 through prefix-n. If address has a
+// numeric port, node i gets that port plus i-1 so that the addresses stay unique;
+// otherwise they all share address.
+func VirtualNodes(prefix, address string, n int) []VirtualNode {
+	host, portStr, err := net.SplitHostPort(address)
+	port, errPort := strconv.Atoi(portStr)
+	nodes := make([]VirtualNode, n)
+	for i := range nodes {
+		nodes[i].Name = fmt.Sprintf("%s-%d", prefix, i+1)
+		nodes[i].Address = address
+		if err == nil && errPort == nil {
+			nodes[i].Address = net.JoinHostPort(host, strconv.Itoa(port+i))
+		}
+	}
+	return nodes
+}
+
+// RunAll runs the kubelets concurrently until ctx is cancelled or one of them fails,
+// in which case the rest are stopped and the first error is returned.
+func RunAll(ctx context.Context, kubelets ...*Kubelet) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, k := range kubelets {
+		wg.Add(1)
+		go func(k *Kubelet) {
+			defer wg.Done()
+			if err := k.Run(ctx); err != nil {
+				once.Do(func() { firstErr = fmt.Errorf("node %s: %w", k.NodeName, err) })
+				cancel()
+			}
+		}(k)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// NodePool runs kubelets that are added and removed while the process runs, such as
+// the nodes the cluster autoscaler provisions.
+type NodePool struct {
+	ctx        context.Context
+	newKubelet func(name string) *Kubelet
+
+	mu      sync.Mutex
+	running map[string]*context.CancelFunc
+}
+
+// NewNodePool returns a pool whose kubelets, made by newKubelet, run until they are
+// removed or ctx is cancelled.
+func NewNodePool(ctx context.Context, newKubelet func(name string) *Kubelet) *NodePool {
+	return &NodePool{ctx: ctx, newKubelet: newKubelet, running: make(map[string]*context.CancelFunc)}
+}
+
+// AddNode starts a kubelet for the node named name, which registers the node.
+func (p *NodePool) AddNode(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.running[name]; ok {
+		return fmt.Errorf("node %s is already running", name)
+	}
+	ctx, cancel := context.WithCancel(p.ctx)
+	running := &cancel
+	p.running[name] = running
+	k := p.newKubelet(name)
+	go func() {
+		if err := k.Run(ctx); err != nil {
+			log.Printf("Node %s stopped: %v", name, err)
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.running[name] == running { // Not removed and added again meanwhile
+			delete(p.running, name)
+		}
+	}()
+	return nil
+}
+
+// RemoveNode stops the kubelet of the node named name. It doesn't delete the Node.
+func (p *NodePool) RemoveNode(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cancel, ok := p.running[name]
+	if !ok {
+		return fmt.Errorf("node %s is not running", name)
+	}
+	(*cancel)()
+	delete(p.running, name)
+	return nil
+}
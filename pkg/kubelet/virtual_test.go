@@ -0,0 +1,66 @@
+package kubelet
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestVirtualNodes(t *testing.T) {
+	got := VirtualNodes("sim", "localhost:10250", 3)
+	want := []VirtualNode{{"sim-1", "localhost:10250"}, {"sim-2", "localhost:10251"}, {"sim-3", "localhost:10252"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VirtualNodes = %v, want %v", got, want)
+	}
+	if got := VirtualNodes("sim", "rack-1", 2); got[0].Address != "rack-1" || got[1].Address != "rack-1" {
+		t.Errorf("expected an address without a port to be shared, got %v", got)
+	}
+}
+
+func TestRunAllRunsEveryNode(t *testing.T) {
+	var pods []interface{}
+	for _, node := range []string{"sim-1", "sim-2", "sim-3"} {
+		pods = append(pods, &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: "web-" + node, Namespace: DefaultNamespace},
+			Image:      "nginx:1.25",
+			NodeName:   node,
+			Phase:      api.PodScheduled,
+		})
+	}
+	client := fake.NewClient(pods...)
+	var kubelets []*Kubelet
+	for _, node := range VirtualNodes("sim", "localhost:10250", 3) {
+		kubelets = append(kubelets, NewKubelet(client, node.Name, node.Address, t.TempDir(), 10*time.Millisecond, ImagePullOptions{}, clock.RealClock{}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- RunAll(ctx, kubelets...) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		running, err := client.ListPods(DefaultNamespace, api.PodRunning)
+		if err == nil && len(running) == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for every node to run its pod (running: %d, %v)", len(running), err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("RunAll returned %v after cancellation, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunAll did not return after cancellation")
+	}
+}
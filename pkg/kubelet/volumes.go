@@ -0,0 +1,154 @@
+package kubelet
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// volumeManager prepares the volumes of the pods on this node. Everything it creates
+// lives under rootDir, one directory per pod:
+//
+//	<rootDir>/pods/<pod uid>/volumes/empty-dir/<volume name>
+//
+// emptyDir volumes are created there when the pod starts and removed with the pod's
+// directory when it is deleted. hostPath volumes point at the node's own filesystem and
+// are checked (or, for DirectoryOrCreate, created) but never removed. A
+// persistentVolumeClaim volume resolves to the hostPath of the volume its claim is
+// bound to and is likewise left in place.
+type volumeManager struct {
+	rootDir string
+	client  api.Interface
+}
+
+func newVolumeManager(rootDir string, client api.Interface) *volumeManager {
+	return &volumeManager{rootDir: rootDir, client: client}
+}
+
+func (vm *volumeManager) podsDir() string {
+	return filepath.Join(vm.rootDir, "pods")
+}
+
+// podDir returns the directory holding pod's volumes. Pods are keyed by UID so that a
+// pod recreated under the same name never sees its predecessor's data.
+func (vm *volumeManager) podDir(pod *api.Pod) string {
+	id := pod.UID
+	if id == "" {
+		id = pod.Namespace + "_" + pod.Name
+	}
+	return filepath.Join(vm.podsDir(), id)
+}
+
+// SetUpPod prepares every volume of pod and returns the host path backing each volume
+// mount, keyed by mount path. It is safe to call again for a pod already set up.
+func (vm *volumeManager) SetUpPod(pod *api.Pod) (map[string]string, error) {
+	hostPaths := make(map[string]string, len(pod.Volumes))
+	for _, vol := range pod.Volumes {
+		var (
+			p   string
+			err error
+		)
+		switch {
+		case vol.EmptyDir != nil:
+			p = filepath.Join(vm.podDir(pod), "volumes", "empty-dir", vol.Name)
+			err = os.MkdirAll(p, 0o755)
+		case vol.HostPath != nil:
+			p = vol.HostPath.Path
+			err = checkHostPath(vol.HostPath)
+		case vol.PersistentVolumeClaim != nil:
+			p, err = vm.claimPath(pod.Namespace, vol.PersistentVolumeClaim.ClaimName)
+		default:
+			err = fmt.Errorf("no volume source")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("setting up volume %q: %w", vol.Name, err)
+		}
+		hostPaths[vol.Name] = p
+	}
+
+	mounts := make(map[string]string, len(pod.VolumeMounts))
+	for _, m := range pod.VolumeMounts {
+		p, ok := hostPaths[m.Name]
+		if !ok {
+			return nil, fmt.Errorf("volume mount %s refers to unknown volume %q", m.MountPath, m.Name)
+		}
+		mounts[m.MountPath] = p
+	}
+	return mounts, nil
+}
+
+// claimPath returns the host path of the persistent volume bound to the named claim.
+// The pod can't start until the volume binder has bound the claim.
+func (vm *volumeManager) claimPath(namespace, claimName string) (string, error) {
+	claim, err := vm.client.GetPersistentVolumeClaim(namespace, claimName)
+	if err != nil {
+		return "", err
+	}
+	if claim.Phase != api.ClaimBound {
+		return "", fmt.Errorf("persistentvolumeclaim %s is %s, not Bound", claimName, claim.Phase)
+	}
+	pv, err := vm.client.GetPersistentVolume(claim.VolumeName)
+	if err != nil {
+		return "", err
+	}
+	if pv.HostPath == nil {
+		return "", fmt.Errorf("persistentvolume %s has no hostPath", pv.Name)
+	}
+	if err := checkHostPath(pv.HostPath); err != nil {
+		return "", err
+	}
+	return pv.HostPath.Path, nil
+}
+
+// checkHostPath verifies that a hostPath volume's path is what its type asks for.
+func checkHostPath(src *api.HostPathVolumeSource) error {
+	switch src.Type {
+	case api.HostPathUnset:
+		return nil
+	case api.HostPathDirectoryOrCreate:
+		return os.MkdirAll(src.Path, 0o755)
+	}
+	info, err := os.Stat(src.Path)
+	if err != nil {
+		return err
+	}
+	switch {
+	case src.Type == api.HostPathDirectory && !info.IsDir():
+		return fmt.Errorf("%s is not a directory", src.Path)
+	case src.Type == api.HostPathFile && !info.Mode().IsRegular():
+		return fmt.Errorf("%s is not a regular file", src.Path)
+	}
+	return nil
+}
+
+// TearDownPod removes pod's emptyDir volumes. hostPath and persistent volumes are left
+// alone.
+func (vm *volumeManager) TearDownPod(pod *api.Pod) error {
+	return os.RemoveAll(vm.podDir(pod))
+}
+
+// CleanupOrphans removes the volume directories of pods that are not in active, e.g.
+// pods that were deleted while the kubelet was down. active holds the directory names
+// (see podDir) of the pods still running on this node.
+func (vm *volumeManager) CleanupOrphans(active map[string]bool) {
+	entries, err := os.ReadDir(vm.podsDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error reading pod volume directories: %v", err)
+		}
+		return
+	}
+	for _, e := range entries {
+		if active[e.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(vm.podsDir(), e.Name())); err != nil {
+			log.Printf("Error removing volumes of orphaned pod %s: %v", e.Name(), err)
+			continue
+		}
+		log.Printf("Removed volumes of orphaned pod %s", e.Name())
+	}
+}
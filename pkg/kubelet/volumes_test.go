@@ -0,0 +1,134 @@
+package kubelet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+)
+
+func TestVolumeManagerSetUpAndTearDown(t *testing.T) {
+	root := t.TempDir()
+	hostDir := filepath.Join(t.TempDir(), "logs")
+	vm := newVolumeManager(root, fake.NewClient())
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-1"},
+		Volumes: []api.Volume{
+			{Name: "cache", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}},
+			{Name: "logs", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: hostDir, Type: api.HostPathDirectoryOrCreate}}},
+		},
+		VolumeMounts: []api.VolumeMount{{Name: "cache", MountPath: "/cache"}, {Name: "logs", MountPath: "/var/log"}},
+	}
+
+	mounts, err := vm.SetUpPod(pod)
+	if err != nil {
+		t.Fatalf("SetUpPod: %v", err)
+	}
+	cache := filepath.Join(root, "pods", "uid-1", "volumes", "empty-dir", "cache")
+	if mounts["/cache"] != cache || mounts["/var/log"] != hostDir {
+		t.Fatalf("unexpected mounts %v", mounts)
+	}
+	for _, dir := range []string{cache, hostDir} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Fatalf("expected %s to be a directory (err=%v)", dir, err)
+		}
+	}
+
+	if err := vm.TearDownPod(pod); err != nil {
+		t.Fatalf("TearDownPod: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "pods", "uid-1")); !os.IsNotExist(err) {
+		t.Errorf("expected the pod's emptyDir volumes to be removed, got %v", err)
+	}
+	if _, err := os.Stat(hostDir); err != nil {
+		t.Errorf("expected the hostPath volume to be left in place, got %v", err)
+	}
+}
+
+func TestVolumeManagerChecksHostPathType(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vm := newVolumeManager(t.TempDir(), fake.NewClient())
+	hostPathPod := func(path string, typ api.HostPathType) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-1"},
+			Volumes:    []api.Volume{{Name: "host", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: path, Type: typ}}}},
+		}
+	}
+
+	if _, err := vm.SetUpPod(hostPathPod(file, api.HostPathFile)); err != nil {
+		t.Errorf("File check on a file: %v", err)
+	}
+	if _, err := vm.SetUpPod(hostPathPod(file, api.HostPathDirectory)); err == nil {
+		t.Errorf("expected Directory check on a file to fail")
+	}
+	if _, err := vm.SetUpPod(hostPathPod(filepath.Join(file, "missing"), api.HostPathFile)); err == nil {
+		t.Errorf("expected File check on a missing path to fail")
+	}
+}
+
+func TestVolumeManagerResolvesClaims(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "data")
+	client := fake.NewClient(
+		&api.PersistentVolume{
+			ObjectMeta:  api.ObjectMeta{Name: "pv-1"},
+			Capacity:    "1Gi",
+			AccessModes: []api.PersistentVolumeAccessMode{api.ReadWriteOnce},
+			HostPath:    &api.HostPathVolumeSource{Path: dataDir, Type: api.HostPathDirectoryOrCreate},
+			Phase:       api.VolumeBound,
+		},
+		&api.PersistentVolumeClaim{ObjectMeta: api.ObjectMeta{Name: "bound"}, Storage: "1Gi", VolumeName: "pv-1", Phase: api.ClaimBound},
+		&api.PersistentVolumeClaim{ObjectMeta: api.ObjectMeta{Name: "pending"}, Storage: "1Gi"},
+	)
+	vm := newVolumeManager(t.TempDir(), client)
+	claimPod := func(claimName string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta:   api.ObjectMeta{Name: "db", Namespace: "default", UID: "uid-1"},
+			Volumes:      []api.Volume{{Name: "data", VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: claimName}}}},
+			VolumeMounts: []api.VolumeMount{{Name: "data", MountPath: "/var/lib/db"}},
+		}
+	}
+
+	mounts, err := vm.SetUpPod(claimPod("bound"))
+	if err != nil {
+		t.Fatalf("SetUpPod: %v", err)
+	}
+	if mounts["/var/lib/db"] != dataDir {
+		t.Errorf("expected the claim to mount %s, got %v", dataDir, mounts)
+	}
+	if _, err := os.Stat(dataDir); err != nil {
+		t.Errorf("expected the volume's DirectoryOrCreate path to be created, got %v", err)
+	}
+	if _, err := vm.SetUpPod(claimPod("pending")); err == nil {
+		t.Errorf("expected a pending claim to fail")
+	}
+	if _, err := vm.SetUpPod(claimPod("missing")); err == nil {
+		t.Errorf("expected a missing claim to fail")
+	}
+}
+
+func TestVolumeManagerCleanupOrphans(t *testing.T) {
+	root := t.TempDir()
+	vm := newVolumeManager(root, fake.NewClient())
+	for _, uid := range []string{"kept", "orphan"} {
+		pod := &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: uid, Namespace: "default", UID: uid},
+			Volumes:    []api.Volume{{Name: "cache", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}}},
+		}
+		if _, err := vm.SetUpPod(pod); err != nil {
+			t.Fatalf("SetUpPod: %v", err)
+		}
+	}
+
+	vm.CleanupOrphans(map[string]bool{"kept": true})
+	if _, err := os.Stat(filepath.Join(root, "pods", "kept")); err != nil {
+		t.Errorf("expected the active pod's volumes to stay, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "pods", "orphan")); !os.IsNotExist(err) {
+		t.Errorf("expected the orphaned pod's volumes to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package kubelet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/backoff"
+)
+
+// podEvent is a change to a pod bound to the node, as seen by watchPods.
+type podEvent struct {
+	pod *api.Pod
+	// gone is set when the pod was removed from the API server, or is no longer bound
+	// to the node.
+	gone bool
+}
+
+// watchPods sends every change to the pods bound to the node to events until ctx is
+// cancelled, starting with the pods that exist. The API server does the filtering, so
+// the kubelet only hears about its own pods. A watch the client gives up on is started
+// again, after a back-off while it keeps failing.
+func (k *Kubelet) watchPods(ctx context.Context, watcher api.Watcher, events chan<- podEvent) {
+	retry := backoff.New(k.SyncInterval(), maxAPIBackoff)
+	for {
+		err := watcher.WatchMatching(ctx, "pods", api.NamespaceAll, "", "", "spec.nodeName="+k.NodeName, func(event api.WatchEvent) error {
+			if event.Type == api.WatchBookmark {
+				retry.Reset()
+				return nil
+			}
+			pod := new(api.Pod)
+			if err := json.Unmarshal(event.Object, pod); err != nil {
+				return fmt.Errorf("decoding pod: %w", err)
+			}
+			select {
+			case events <- podEvent{pod: pod, gone: event.Type == api.WatchDeleted}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		delay := retry.Next()
+		log.Printf("[%s] Watch of the node's pods failed, starting it again in %v: %v", k.NodeName, delay.Round(time.Millisecond), err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-k.Clock.After(delay):
+		}
+	}
+}
+
+// syncPodEvent syncs the pod a watch event is about, so that a pod starts or stops as
+// soon as it is bound to the node or deleted rather than at the next full sync.
+func (k *Kubelet) syncPodEvent(event podEvent) {
+	if event.gone || !k.syncPod(event.pod) {
+		k.runtime.stop(event.pod.Namespace, event.pod.Name)
+	}
+	k.saveCheckpoint()
+}
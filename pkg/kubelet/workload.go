@@ -0,0 +1,193 @@
+package kubelet
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// simulatedBehavior returns how the runtime runs pod's container, as its annotations
+// (see api.GetSimulatedBehavior) ask. Annotations validation should have rejected are
+// ignored.
+func (k *Kubelet) simulatedBehavior(pod *api.Pod) api.SimulatedBehavior {
+	behavior, err := api.GetSimulatedBehavior(pod)
+	if err != nil {
+		log.Printf("[%s] Ignoring the simulated behavior of pod %s: %v", k.NodeName, pod.Name, err)
+	}
+	return behavior
+}
+
+// containerCreated reports whether a scheduled pod's container has finished starting:
+// the pod's startup delay has passed since the kubelet first tried to start it. Until
+// then the pod's Ready condition says ContainerCreating.
+func (k *Kubelet) containerCreated(pod *api.Pod, behavior api.SimulatedBehavior) bool {
+	if behavior.StartupDelay <= 0 {
+		return true
+	}
+	key := podKey(pod.Namespace, pod.Name)
+	startsAt, ok := k.startups[key]
+	if !ok {
+		startsAt = k.Clock.Now().Add(behavior.StartupDelay)
+		k.startups[key] = startsAt
+		k.reportWaiting(pod, api.PodReasonContainerCreating, "Starting the container, which takes "+behavior.StartupDelay.String())
+	}
+	if k.Clock.Now().Before(startsAt) {
+		return false
+	}
+	delete(k.startups, key)
+	return true
+}
+
+// forgetPods drops the startup deadlines and restart back-offs of pods that aren't in
+// listed, by namespace/name, such as pods deleted while their container waited.
+func (k *Kubelet) forgetPods(listed map[string]bool) {
+	for key := range k.startups {
+		if !listed[key] {
+			delete(k.startups, key)
+		}
+	}
+	for key := range k.crashBackOffs {
+		if !listed[key] {
+			delete(k.crashBackOffs, key)
+		}
+	}
+}
+
+// exitCode returns the code the container of a running pod exits with at this sync,
+// or -1 if it keeps running: 0 once its run duration has passed, unless it crashes,
+// which a container with no run duration may do at any sync.
+func (k *Kubelet) exitCode(pod *api.Pod, behavior api.SimulatedBehavior) int {
+	started, ok := k.runtime.startedAt(pod.Namespace, pod.Name)
+	if !ok {
+		return -1
+	}
+	crashed := behavior.CrashProbability > 0 && k.rand.Float64() < behavior.CrashProbability
+	if behavior.RunDuration > 0 {
+		switch {
+		case k.Clock.Since(started) < behavior.RunDuration:
+			return -1
+		case crashed:
+			return 1
+		}
+		return 0
+	}
+	if crashed {
+		return 1
+	}
+	return -1
+}
+
+// shouldRestart reports whether a container that exited with code is run again
+// under pod's restart policy. Pods stored before the field existed restart Always.
+func shouldRestart(pod *api.Pod, code int) bool {
+	switch pod.RestartPolicy {
+	case api.RestartPolicyNever:
+		return false
+	case api.RestartPolicyOnFailure:
+		return code != 0
+	default:
+		return true
+	}
+}
+
+// A container that exits and is to be restarted waits a back-off first, as a crash
+// looping container does in Kubernetes: initialCrashBackOff, doubling with every
+// restart up to maxCrashBackOff, and back to initialCrashBackOff once a run lasts
+// crashBackOffReset.
+const (
+	initialCrashBackOff = 10 * time.Second
+	maxCrashBackOff     = 5 * time.Minute
+	crashBackOffReset   = 10 * time.Minute
+)
+
+// crashBackOff is the restart back-off of a pod's container. until is zero while the
+// container runs.
+type crashBackOff struct {
+	delay time.Duration
+	until time.Time
+}
+
+// syncContainer checks on a running pod's container as its simulated behavior says it
+// runs. A container that exited is restarted, once its back-off has passed, counting
+// the restart; or, if the pod's restart policy says not to restart it, the pod ends
+// Succeeded or Failed by the exit code. It returns whether the pod is still running.
+func (k *Kubelet) syncContainer(pod *api.Pod, behavior api.SimulatedBehavior) bool {
+	key := podKey(pod.Namespace, pod.Name)
+	if b := k.crashBackOffs[key]; b != nil && !b.until.IsZero() {
+		if k.Clock.Now().Before(b.until) {
+			return true
+		}
+		return k.restartContainer(pod, b)
+	}
+	code := k.exitCode(pod, behavior)
+	if code < 0 {
+		return true
+	}
+	if !shouldRestart(pod, code) {
+		return k.finishPod(pod, code)
+	}
+
+	b := k.crashBackOffs[key]
+	if started, _ := k.runtime.startedAt(pod.Namespace, pod.Name); b == nil || k.Clock.Since(started) >= crashBackOffReset {
+		b = &crashBackOff{delay: initialCrashBackOff}
+		k.crashBackOffs[key] = b
+	} else {
+		b.delay = min(2*b.delay, maxCrashBackOff)
+	}
+	b.until = k.Clock.Now().Add(b.delay)
+	k.runtime.exited(pod.Namespace, pod.Name, code)
+	log.Printf("[%s] Pod %s exited with code %d; restarting it in %v.", k.NodeName, pod.Name, code, b.delay)
+	msg := fmt.Sprintf("Back-off %v restarting the container, which exited with code %d", b.delay, code)
+	k.Recorder.Eventf(pod, api.EventTypeWarning, "BackOff", "%s", msg)
+	updatedPod := *pod
+	updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+	api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: api.PodReasonCrashLoopBackOff, Message: msg})
+	if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+		log.Printf("[%s] Error reporting the back-off of pod %s: %v", k.NodeName, pod.Name, err)
+	}
+	return true
+}
+
+// restartContainer runs a running pod's container again once its back-off b has
+// passed, counting the restart.
+func (k *Kubelet) restartContainer(pod *api.Pod, b *crashBackOff) bool {
+	updatedPod := *pod
+	updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+	updatedPod.RestartCount++
+	api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionTrue, Reason: "Started"})
+	if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+		log.Printf("[%s] Error reporting the restart of pod %s: %v", k.NodeName, pod.Name, err)
+		return true
+	}
+	b.until = time.Time{}
+	k.runtime.restart(&updatedPod)
+	log.Printf("[%s] Pod %s restarted (restart %d).", k.NodeName, pod.Name, updatedPod.RestartCount)
+	k.Recorder.Eventf(&updatedPod, api.EventTypeNormal, "Started", "Restarted pod with image %s", pod.Image)
+	return true
+}
+
+// finishPod ends a pod whose container exited with code and isn't restarted:
+// Succeeded for code 0, and Failed otherwise. It returns whether the pod is still
+// running, which it is only if the API server couldn't be told.
+func (k *Kubelet) finishPod(pod *api.Pod, code int) bool {
+	updatedPod := *pod
+	updatedPod.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+	updatedPod.Phase = api.PodSucceeded
+	reason, eventType, eventReason := "PodCompleted", api.EventTypeNormal, "Completed"
+	if code != 0 {
+		updatedPod.Phase = api.PodFailed
+		reason, eventType, eventReason = "Error", api.EventTypeWarning, "Failed"
+	}
+	api.SetPodCondition(&updatedPod, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: reason, Message: fmt.Sprintf("The container exited with code %d", code)})
+	if err := k.APIClient.UpdatePod(&updatedPod); err != nil {
+		log.Printf("[%s] Error updating pod %s to %s: %v", k.NodeName, pod.Name, updatedPod.Phase, err)
+		return true
+	}
+	log.Printf("[%s] Pod %s exited with code %d and is now '%s'.", k.NodeName, pod.Name, code, updatedPod.Phase)
+	k.Recorder.Eventf(&updatedPod, eventType, eventReason, "Container exited with code %d", code)
+	k.runtime.stop(pod.Namespace, pod.Name)
+	delete(k.crashBackOffs, podKey(pod.Namespace, pod.Name))
+	return false
+}
@@ -0,0 +1,103 @@
+package kubelet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+)
+
+func TestSimulatedBehavior(t *testing.T) {
+	pod := func(name string, policy api.RestartPolicy, annotations map[string]string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta:    api.ObjectMeta{Name: name, Namespace: DefaultNamespace, Annotations: annotations},
+			Image:         "busybox:1.36",
+			NodeName:      "node1",
+			Phase:         api.PodScheduled,
+			RestartPolicy: policy,
+		}
+	}
+	client := fake.NewClient(
+		pod("job", api.RestartPolicyNever, map[string]string{api.AnnotationStartupDelay: "10s", api.AnnotationRunDuration: "30s"}),
+		pod("crasher", api.RestartPolicyNever, map[string]string{api.AnnotationCrashProbability: "1"}),
+		pod("retrier", api.RestartPolicyOnFailure, map[string]string{api.AnnotationCrashProbability: "1"}),
+		pod("server", api.RestartPolicyAlways, map[string]string{api.AnnotationRunDuration: "30s"}),
+	)
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	k := NewKubelet(client, "node1", "localhost:10250", t.TempDir(), time.Second, ImagePullOptions{}, clk)
+	get := func(name string) *api.Pod {
+		t.Helper()
+		p, err := client.GetPod(DefaultNamespace, name)
+		if err != nil {
+			t.Fatalf("GetPod %s: %v", name, err)
+		}
+		return p
+	}
+	sync := func() {
+		t.Helper()
+		if err := k.syncPods(); err != nil {
+			t.Fatalf("syncPods: %v", err)
+		}
+	}
+
+	sync()
+	job := get("job")
+	if cond := api.GetPodCondition(job, api.PodReadyCondition); job.Phase != api.PodScheduled || cond == nil || cond.Reason != api.PodReasonContainerCreating {
+		t.Errorf("expected the job to wait Scheduled for ContainerCreating during its startup delay, got %s and %+v", job.Phase, cond)
+	}
+	for _, name := range []string{"crasher", "retrier", "server"} {
+		if phase := get(name).Phase; phase != api.PodRunning {
+			t.Errorf("%s: phase %s after the first sync, want Running", name, phase)
+		}
+	}
+
+	// Crashes show on the sync after the container started. One that is restarted
+	// waits out a back-off first, however often the pod is synced meanwhile.
+	sync()
+	sync()
+	if crasher := get("crasher"); crasher.Phase != api.PodFailed || crasher.RestartCount != 0 {
+		t.Errorf("expected the crasher, which is never restarted, to fail, got %s with %d restarts", crasher.Phase, crasher.RestartCount)
+	}
+	if _, running := k.runtime.startedAt(DefaultNamespace, "crasher"); running {
+		t.Error("expected the failed crasher to be stopped")
+	}
+	retrier := get("retrier")
+	if cond := api.GetPodCondition(retrier, api.PodReadyCondition); retrier.Phase != api.PodRunning || retrier.RestartCount != 0 || cond == nil || cond.Reason != api.PodReasonCrashLoopBackOff {
+		t.Errorf("expected the retrier to back off after crashing, got %s with %d restarts and %+v", retrier.Phase, retrier.RestartCount, cond)
+	}
+
+	clk.Step(initialCrashBackOff)
+	sync()
+	if job := get("job"); job.Phase != api.PodRunning {
+		t.Errorf("expected the job to run once its startup delay passed, got %s", job.Phase)
+	}
+	retrier = get("retrier")
+	if cond := api.GetPodCondition(retrier, api.PodReadyCondition); retrier.RestartCount != 1 || cond == nil || cond.Status != api.ConditionTrue {
+		t.Errorf("expected the retrier to be restarted once its back-off passed, got %d restarts and %+v", retrier.RestartCount, cond)
+	}
+	sync() // Crashes again, backing off twice as long
+	if b := k.crashBackOffs[podKey(DefaultNamespace, "retrier")]; b == nil || b.delay != 2*initialCrashBackOff {
+		t.Errorf("expected the retrier's back-off to double, got %+v", b)
+	}
+
+	clk.Step(30 * time.Second)
+	sync()
+	if job := get("job"); job.Phase != api.PodSucceeded {
+		t.Errorf("expected the job to succeed at the end of its run duration, got %s", job.Phase)
+	}
+	clk.Step(initialCrashBackOff)
+	sync()
+	if server := get("server"); server.Phase != api.PodRunning || server.RestartCount != 1 {
+		t.Errorf("expected the server to be restarted after its run ended, got %s with %d restarts", server.Phase, server.RestartCount)
+	}
+	// The exit is in the logs of the previous container, and the restart in the new
+	// one's.
+	previous, _, _ := k.runtime.logs(DefaultNamespace, "server", api.PodLogOptions{Previous: true})
+	logs, _, _ := k.runtime.logs(DefaultNamespace, "server", api.PodLogOptions{})
+	if len(previous) == 0 || !strings.Contains(previous[len(previous)-1], "Process exited with code 0") || len(logs) != 1 || !strings.Contains(logs[0], "Restarted container") {
+		t.Errorf("expected the server's logs to show the exit and restart, got %q and %q", previous, logs)
+	}
+}
@@ -0,0 +1,234 @@
+// Package labels parses and evaluates label selectors, using the same syntax as
+// Kubernetes:
+//
+//	app=web,tier!=db          equality and inequality ("==" also works)
+//	env in (prod,staging)     set membership
+//	env notin (dev)
+//	canary                    the label exists
+//	!canary                   the label does not exist
+//
+// All requirements in a selector must match.
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operator is the comparison a Requirement makes.
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single condition on one label key.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string // One value for Equals/NotEquals, any number for In/NotIn, none otherwise
+}
+
+// Matches reports whether labels satisfy the requirement.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+	switch r.Operator {
+	case Equals:
+		return exists && value == r.Values[0]
+	case NotEquals:
+		return !exists || value != r.Values[0]
+	case In:
+		return exists && contains(r.Values, value)
+	case NotIn:
+		return !exists || !contains(r.Values, value)
+	case Exists:
+		return exists
+	case DoesNotExist:
+		return !exists
+	}
+	return false
+}
+
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Equals, NotEquals:
+		return r.Key + string(r.Operator) + r.Values[0]
+	case In, NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	case DoesNotExist:
+		return "!" + r.Key
+	}
+	return r.Key
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Selector is a set of requirements that must all match.
+type Selector []Requirement
+
+// Everything returns a selector that matches all objects.
+func Everything() Selector {
+	return nil
+}
+
+// SelectorFromSet returns a selector requiring every label in set to have the given value.
+func SelectorFromSet(set map[string]string) Selector {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sel := make(Selector, 0, len(keys))
+	for _, k := range keys {
+		sel = append(sel, Requirement{Key: k, Operator: Equals, Values: []string{set[k]}})
+	}
+	return sel
+}
+
+// ConvertSelectorToLabelsMap parses a selector made only of key=value terms, such as
+// "app=web,tier=frontend", into the label set it requires.
+func ConvertSelectorToLabelsMap(s string) (map[string]string, error) {
+	sel, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]string, len(sel))
+	for _, r := range sel {
+		if r.Operator != Equals {
+			return nil, fmt.Errorf("invalid label selector %q: only key=value terms are allowed, got %q", s, r.String())
+		}
+		set[r.Key] = r.Values[0]
+	}
+	return set, nil
+}
+
+// Matches reports whether labels satisfy every requirement.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty reports whether the selector matches everything.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+var (
+	setRequirement = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+	validToken     = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.\-/]*[A-Za-z0-9])?$`)
+)
+
+// Parse parses a selector string. The empty string selects everything.
+func Parse(s string) (Selector, error) {
+	var sel Selector
+	for _, term := range splitTerms(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		r, err := parseRequirement(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", s, err)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	if m := setRequirement.FindStringSubmatch(term); m != nil {
+		var values []string
+		for _, v := range strings.Split(m[3], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				if !validToken.MatchString(v) {
+					return Requirement{}, fmt.Errorf("invalid value %q", v)
+				}
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return Requirement{}, fmt.Errorf("%q needs at least one value", term)
+		}
+		r := Requirement{Key: m[1], Operator: Operator(m[2]), Values: values}
+		return r, validateKey(r.Key)
+	}
+
+	if strings.HasPrefix(term, "!") {
+		key := strings.TrimSpace(term[1:])
+		return Requirement{Key: key, Operator: DoesNotExist}, validateKey(key)
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		key, value, found := strings.Cut(term, op)
+		if !found {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if err := validateKey(key); err != nil {
+			return Requirement{}, err
+		}
+		if value != "" && !validToken.MatchString(value) {
+			return Requirement{}, fmt.Errorf("invalid value %q", value)
+		}
+		operator := Equals
+		if op == "!=" {
+			operator = NotEquals
+		}
+		return Requirement{Key: key, Operator: operator, Values: []string{value}}, nil
+	}
+
+	return Requirement{Key: term, Operator: Exists}, validateKey(term)
+}
+
+func validateKey(key string) error {
+	if !validToken.MatchString(key) {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	return nil
+}
+
+// splitTerms splits s on commas that are not inside parentheses.
+func splitTerms(s string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, s[start:])
+}
@@ -0,0 +1,71 @@
+package labels
+
+import "testing"
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend", "env": "prod"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"app=web", true},
+		{"app==web", true},
+		{"app=db", false},
+		{"app!=db", true},
+		{"missing!=x", true},
+		{"app=web,tier=frontend", true},
+		{"app=web,tier=backend", false},
+		{"env in (prod, staging)", true},
+		{"env in (dev)", false},
+		{"env notin (dev,staging),app=web", true},
+		{"missing notin (a)", true},
+		{"tier", true},
+		{"canary", false},
+		{"!canary", true},
+		{"!app", false},
+	}
+	for _, tt := range tests {
+		sel, err := Parse(tt.selector)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.selector, err)
+			continue
+		}
+		if got := sel.Matches(labels); got != tt.want {
+			t.Errorf("Parse(%q).Matches(%v) = %v, want %v", tt.selector, labels, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{"=web", "app in ()", "a b=c", "app=we b", "!"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected an error", s)
+		}
+	}
+}
+
+func TestSelectorFromSet(t *testing.T) {
+	sel := SelectorFromSet(map[string]string{"tier": "frontend", "app": "web"})
+	if got, want := sel.String(), "app=web,tier=frontend"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !sel.Matches(map[string]string{"app": "web", "tier": "frontend", "extra": "x"}) {
+		t.Errorf("expected the selector to match a superset of its labels")
+	}
+}
+
+func TestConvertSelectorToLabelsMap(t *testing.T) {
+	set, err := ConvertSelectorToLabelsMap("app=web, tier==frontend")
+	if err != nil {
+		t.Fatalf("ConvertSelectorToLabelsMap: %v", err)
+	}
+	if len(set) != 2 || set["app"] != "web" || set["tier"] != "frontend" {
+		t.Errorf("got %v, want app=web and tier=frontend", set)
+	}
+	for _, s := range []string{"app!=web", "app in (web)", "app"} {
+		if _, err := ConvertSelectorToLabelsMap(s); err == nil {
+			t.Errorf("ConvertSelectorToLabelsMap(%q): expected an error", s)
+		}
+	}
+}
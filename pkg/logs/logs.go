@@ -0,0 +1,57 @@
+// Package logs gives the components a klog-style verbosity on top of the standard
+// logger. The lines a component logs for every request, pod sync, or scheduling pass
+// are only written at -v=2 and above, which is the default, so a busy component can be
+// quietened, or made chatty again, without a restart (see componentconfig).
+package logs
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultVerbosity logs everything the components log.
+const DefaultVerbosity = 2
+
+var verbosity atomic.Int32
+
+func init() {
+	verbosity.Store(DefaultVerbosity)
+}
+
+// V reports whether lines at level are logged, as in
+//
+//	if logs.V(2) {
+//		log.Printf("Syncing pods...")
+//	}
+func V(level int) bool {
+	return int(verbosity.Load()) >= level
+}
+
+// SetVerbosity sets the level up to which lines are logged. It is safe to call while
+// the component runs.
+func SetVerbosity(level int) {
+	verbosity.Store(int32(level))
+}
+
+// AddFlags registers -v on fs.
+func AddFlags(fs *flag.FlagSet) {
+	fs.Var(verbosityValue{}, "v", fmt.Sprintf("Log verbosity: %d logs a line for every request, pod sync, and scheduling pass; lower levels leave them out", DefaultVerbosity))
+}
+
+// verbosityValue is the flag.Value of -v; setting it sets the verbosity at once.
+type verbosityValue struct{}
+
+func (verbosityValue) String() string {
+	return strconv.Itoa(int(verbosity.Load()))
+}
+
+func (verbosityValue) Set(s string) error {
+	level, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("verbosity must be a number, got %q", s)
+	}
+	SetVerbosity(level)
+	return nil
+}
@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KustomizationFile is the file that makes a directory a kustomization.
+const KustomizationFile = "kustomization.yaml"
+
+// Kustomization is a small subset of kustomize's kustomization.yaml: the manifests it
+// builds on, and the changes made to every object in them. For example, an overlay
+// that runs a base's objects in their own namespace:
+//
+//	resources:
+//	  - ../base
+//	namePrefix: prod-
+//	namespace: prod
+//	commonLabels:
+//	  env: prod
+type Kustomization struct {
+	APIVersion string `yaml:"apiVersion,omitempty"`
+	Kind       string `yaml:"kind,omitempty"`
+	// Resources are manifest files, or directories holding another kustomization,
+	// relative to the kustomization's directory.
+	Resources []string `yaml:"resources"`
+	// NamePrefix is put before the name of every namespaced object.
+	NamePrefix string `yaml:"namePrefix,omitempty"`
+	// Namespace replaces the namespace of every namespaced object.
+	Namespace string `yaml:"namespace,omitempty"`
+	// CommonLabels are added to every object's labels, and to the selectors of
+	// deployments and services and deployments' pod templates so that they still
+	// match. A service without a selector is left without one.
+	CommonLabels map[string]string `yaml:"commonLabels,omitempty"`
+}
+
+// Kustomize builds the kustomization in dir: it reads the objects in its resources,
+// building those that are kustomizations themselves first, and applies its changes
+// to them.
+func Kustomize(dir string) ([]Object, error) {
+	return kustomize(dir, nil)
+}
+
+// kustomize builds the kustomization in dir. seen holds the directories of the
+// kustomizations that include this one, to catch cycles.
+func kustomize(dir string, seen []string) ([]Object, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range seen {
+		if d == abs {
+			return nil, fmt.Errorf("%s includes itself", dir)
+		}
+	}
+	seen = append(seen, abs)
+
+	k, err := readKustomization(filepath.Join(dir, KustomizationFile))
+	if err != nil {
+		return nil, err
+	}
+	var objects []Object
+	for _, resource := range k.Resources {
+		path := filepath.Join(dir, resource)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: resource %s: %w", dir, resource, err)
+		}
+		var decoded []Object
+		if info.IsDir() {
+			decoded, err = kustomize(path, seen)
+		} else {
+			decoded, err = DecodeFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, decoded...)
+	}
+	for i, obj := range objects {
+		if objects[i], err = k.transform(obj); err != nil {
+			return nil, fmt.Errorf("%s: %w", dir, err)
+		}
+	}
+	return objects, nil
+}
+
+// readKustomization reads the kustomization file at path, rejecting fields
+// kustomize-lite doesn't support rather than ignoring them.
+func readKustomization(path string) (*Kustomization, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var k Kustomization
+	if err := dec.Decode(&k); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(k.Resources) == 0 {
+		return nil, fmt.Errorf("%s: no resources", path)
+	}
+	return &k, nil
+}
+
+// transform returns obj with the kustomization's changes made to it. They are made to
+// the fields as written and the object decoded again from them, so that applying it
+// sends the changed fields.
+func (k *Kustomization) transform(obj Object) (Object, error) {
+	doc := make(map[string]interface{}, len(obj.Fields)+1)
+	for field, value := range obj.Fields {
+		doc[field] = value
+	}
+	if Namespaced(obj.Kind) {
+		if k.NamePrefix != "" {
+			name, _ := doc["name"].(string)
+			doc["name"] = k.NamePrefix + name
+		}
+		if k.Namespace != "" {
+			doc["namespace"] = k.Namespace
+		}
+	}
+	if len(k.CommonLabels) > 0 {
+		doc["labels"] = withLabels(doc["labels"], k.CommonLabels)
+		if _, ok := doc["selector"]; ok && (obj.Kind == "Deployment" || obj.Kind == "Service") {
+			doc["selector"] = withLabels(doc["selector"], k.CommonLabels)
+		}
+		if obj.Kind == "Deployment" {
+			template, _ := doc["template"].(map[string]interface{})
+			copied := make(map[string]interface{}, len(template)+1)
+			for field, value := range template {
+				copied[field] = value
+			}
+			copied["labels"] = withLabels(copied["labels"], k.CommonLabels)
+			doc["template"] = copied
+		}
+	}
+	doc["kind"] = obj.Kind
+	return decodeDocument(doc)
+}
+
+// withLabels returns a copy of the label map m, as decoded from a manifest, with
+// labels added.
+func withLabels(m interface{}, labels map[string]string) map[string]interface{} {
+	existing, _ := m.(map[string]interface{})
+	result := make(map[string]interface{}, len(existing)+len(labels))
+	for key, value := range existing {
+		result[key] = value
+	}
+	for key, value := range labels {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestKustomize(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base/kustomization.yaml": "resources:\n  - app.yaml\ncommonLabels:\n  app: web\n",
+		"base/app.yaml": `kind: Deployment
+name: web
+selector:
+  tier: frontend
+template:
+  labels:
+    tier: frontend
+  image: nginx
+---
+kind: Service
+name: web
+selector:
+  tier: frontend
+ports:
+  - port: 80
+    targetPort: 8080
+`,
+		"prod/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - ../base
+  - namespace.yaml
+namePrefix: prod-
+namespace: prod
+commonLabels:
+  env: prod
+`,
+		"prod/namespace.yaml": "kind: Namespace\nname: prod\n",
+	})
+
+	objects, err := Kustomize(filepath.Join(dir, "prod"))
+	if err != nil {
+		t.Fatalf("Kustomize: %v", err)
+	}
+	if len(objects) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(objects))
+	}
+	want := map[string]string{"app": "web", "env": "prod", "tier": "frontend"}
+	deployment := objects[0].Object.(*api.Deployment)
+	if deployment.Namespace != "prod" || deployment.Name != "prod-web" {
+		t.Errorf("expected the deployment to be prod/prod-web, got %s/%s", deployment.Namespace, deployment.Name)
+	}
+	for what, labels := range map[string]map[string]string{"selector": deployment.Selector, "template labels": deployment.Template.Labels} {
+		if !reflect.DeepEqual(labels, want) {
+			t.Errorf("expected the deployment's %s to be %v, got %v", what, want, labels)
+		}
+	}
+	if labels := deployment.Labels; len(labels) != 2 || labels["app"] != "web" || labels["env"] != "prod" {
+		t.Errorf("expected the deployment to get both kustomizations' labels, got %v", labels)
+	}
+	if svc := objects[1].Object.(*api.Service); svc.Name != "prod-web" || len(svc.Selector) != 3 {
+		t.Errorf("expected the service's name and selector to change, got %s and %v", svc.Name, svc.Selector)
+	}
+	if got := objects[0].Fields["namespace"]; got != "prod" {
+		t.Errorf("expected the changes in Fields too, got namespace %v", got)
+	}
+	if ns := objects[2].Object.(*api.Namespace); ns.Name != "prod" || ns.Labels["env"] != "prod" {
+		t.Errorf("expected the namespace to keep its name and get the labels, got %s with %v", ns.Name, ns.Labels)
+	}
+}
+
+func TestKustomizeErrors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing file":      {"kustomization.yaml": "resources:\n  - missing.yaml\n"},
+		"no resources":      {"kustomization.yaml": "namePrefix: dev-\n"},
+		"unsupported field": {"kustomization.yaml": "resources:\n  - pod.yaml\npatches: []\n", "pod.yaml": "kind: Pod\nname: web\nimage: nginx\n"},
+		"cycle":             {"kustomization.yaml": "resources:\n  - .\n"},
+		"not a kustomization": {
+			"kustomization.yaml": "resources:\n  - base\n",
+			"base/pod.yaml":      "kind: Pod\nname: web\nimage: nginx\n",
+		},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, files)
+			if _, err := Kustomize(dir); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Package manifest decodes the YAML (or JSON) files kubectl-lite reads with -f. A
+// manifest holds one or more documents separated by "---", each naming its kind
+// alongside the object's usual fields:
+//
+//	kind: Pod
+//	name: web
+//	image: nginx
+//	---
+//	kind: Service
+//	name: web
+//	ports:
+//	  - port: 80
+//	    targetPort: 8080
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Object is a single decoded manifest document.
+type Object struct {
+	Kind string
+	// Object is a *api.Pod, *api.Node, *api.Namespace, *api.Deployment, or *api.Service.
+	Object interface{}
+	// Fields holds the document exactly as written (minus kind and apiVersion), so
+	// callers can tell fields that were set from ones left at their zero value.
+	Fields map[string]interface{}
+}
+
+// newObject returns a pointer to an empty object of the given kind.
+func newObject(kind string) (interface{}, error) {
+	switch kind {
+	case "Pod":
+		return &api.Pod{}, nil
+	case "Node":
+		return &api.Node{}, nil
+	case "Namespace":
+		return &api.Namespace{}, nil
+	case "Deployment":
+		return &api.Deployment{}, nil
+	case "Service":
+		return &api.Service{}, nil
+	}
+	return nil, fmt.Errorf("unsupported kind %q", kind)
+}
+
+// Decode reads every document in r. Empty documents are skipped.
+func Decode(r io.Reader) ([]Object, error) {
+	dec := yaml.NewDecoder(r)
+	var objects []Object
+	for i := 1; ; i++ {
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+		obj, err := decodeDocument(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		objects = append(objects, obj)
+	}
+}
+
+// DecodeFile reads the manifest at path, or standard input if path is "-".
+func DecodeFile(path string) ([]Object, error) {
+	if path == "-" {
+		return Decode(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	objects, err := Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return objects, nil
+}
+
+// DecodePath reads the manifest at path as DecodeFile does or, if path is a directory,
+// every .yaml, .yml, and .json file in it, in lexical order. With recursive set the
+// directory's subdirectories are read too.
+func DecodePath(path string, recursive bool) ([]Object, error) {
+	if path == "-" {
+		return DecodeFile(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return DecodeFile(path)
+	}
+	var objects []Object
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch filepath.Ext(p) {
+		case ".yaml", ".yml", ".json":
+			decoded, err := DecodeFile(p)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, decoded...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no manifests found in %s", path)
+	}
+	return objects, nil
+}
+
+// kindOrder is the order objects are created in, as kubectl sorts them: namespaces
+// before anything in them, and services before the workloads they front. Deleting goes
+// the other way.
+var kindOrder = map[string]int{"Namespace": 0, "Node": 1, "Service": 2, "Deployment": 3, "Pod": 4}
+
+// SortForApply orders objects for creating them, by kind as kindOrder says, keeping
+// the manifest's order among objects of the same kind.
+func SortForApply(objects []Object) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return kindOrder[objects[i].Kind] < kindOrder[objects[j].Kind]
+	})
+}
+
+func decodeDocument(doc map[string]interface{}) (Object, error) {
+	kind, _ := doc["kind"].(string)
+	if kind == "" {
+		return Object{}, fmt.Errorf("missing kind")
+	}
+	delete(doc, "kind")
+	delete(doc, "apiVersion")
+
+	obj, err := newObject(kind)
+	if err != nil {
+		return Object{}, err
+	}
+	// Round-trip through JSON so the api types' json tags define the field names.
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return Object{}, fmt.Errorf("converting %s to JSON: %w", kind, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(obj); err != nil {
+		return Object{}, fmt.Errorf("decoding %s: %w", kind, err)
+	}
+	return Object{Kind: kind, Object: obj, Fields: doc}, nil
+}
+
+// Name returns the name and namespace of a decoded object.
+func (o Object) Name() (namespace, name string) {
+	switch obj := o.Object.(type) {
+	case *api.Pod:
+		return obj.Namespace, obj.Name
+	case *api.Node:
+		return "", obj.Name
+	case *api.Namespace:
+		return "", obj.Name
+	case *api.Deployment:
+		return obj.Namespace, obj.Name
+	case *api.Service:
+		return obj.Namespace, obj.Name
+	}
+	return "", ""
+}
+
+// Namespaced reports whether objects of kind live in a namespace.
+func Namespaced(kind string) bool {
+	return kind != "Node" && kind != "Namespace"
+}
@@ -0,0 +1,111 @@
+package manifest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestDecode(t *testing.T) {
+	input := `
+apiVersion: v1
+kind: Pod
+name: web
+labels:
+  app: web
+image: nginx
+---
+---
+kind: Service
+name: web
+namespace: staging
+ports:
+  - port: 80
+    targetPort: 8080
+`
+	objects, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objects))
+	}
+
+	pod, ok := objects[0].Object.(*api.Pod)
+	if !ok || objects[0].Kind != "Pod" {
+		t.Fatalf("expected a Pod first, got %s %T", objects[0].Kind, objects[0].Object)
+	}
+	if pod.Name != "web" || pod.Image != "nginx" || pod.Labels["app"] != "web" {
+		t.Errorf("unexpected pod: %+v", pod)
+	}
+	if _, ok := objects[0].Fields["apiVersion"]; ok {
+		t.Errorf("apiVersion should be stripped from Fields")
+	}
+
+	svc := objects[1].Object.(*api.Service)
+	if ns, name := objects[1].Name(); ns != "staging" || name != "web" {
+		t.Errorf("Name() = %s/%s, want staging/web", ns, name)
+	}
+	if len(svc.Ports) != 1 || svc.Ports[0].TargetPort != 8080 {
+		t.Errorf("unexpected service ports: %+v", svc.Ports)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing kind":  "name: web\n",
+		"unknown kind":  "kind: Widget\nname: web\n",
+		"unknown field": "kind: Pod\nname: web\nimagee: nginx\n",
+		"bad yaml":      "kind: Pod\n  name: [\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Decode(strings.NewReader(input)); err == nil {
+				t.Errorf("expected an error decoding %q", input)
+			}
+		})
+	}
+}
+
+func TestDecodePath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml":           "kind: Pod\nname: web\nnamespace: shop\nimage: nginx\n---\nkind: Service\nname: web\nnamespace: shop\n",
+		"namespace.yml":      "kind: Namespace\nname: shop\n",
+		"README.md":          "not a manifest",
+		"db/deployment.json": `{"kind": "Deployment", "name": "db", "namespace": "shop", "template": {"image": "postgres"}}`,
+	}
+	writeFiles(t, dir, files)
+	kinds := func(objects []Object) string {
+		var kinds []string
+		for _, obj := range objects {
+			kinds = append(kinds, obj.Kind)
+		}
+		return strings.Join(kinds, ",")
+	}
+
+	objects, err := DecodePath(dir, false)
+	if err != nil {
+		t.Fatalf("DecodePath: %v", err)
+	}
+	if got := kinds(objects); got != "Pod,Service,Namespace" {
+		t.Errorf("expected the directory's manifests in file order without its subdirectory, got %s", got)
+	}
+	objects, err = DecodePath(dir, true)
+	if err != nil {
+		t.Fatalf("DecodePath recursively: %v", err)
+	}
+	SortForApply(objects)
+	if got := kinds(objects); got != "Namespace,Service,Deployment,Pod" {
+		t.Errorf("expected every manifest in dependency order, got %s", got)
+	}
+
+	if _, err := DecodePath(filepath.Join(dir, "db"), false); err != nil {
+		t.Errorf("expected a directory with only a JSON manifest to decode, got %v", err)
+	}
+	if _, err := DecodePath(t.TempDir(), true); err == nil || !strings.Contains(err.Error(), "no manifests found") {
+		t.Errorf("expected an empty directory to fail, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Package networkpolicy decides whether a NetworkPolicy allows a connection between
+// two pods, for kube-proxy-lite to enforce.
+package networkpolicy
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+)
+
+// Selects reports whether policy restricts the traffic pod accepts.
+func Selects(policy *api.NetworkPolicy, pod *api.Pod) bool {
+	return pod.Namespace == policy.Namespace && labels.SelectorFromSet(policy.PodSelector).Matches(pod.Labels)
+}
+
+// Allows reports whether a connection from pod from, in a namespace labelled
+// fromNamespaceLabels, to port of pod to is allowed by policies, the network policies
+// in to's namespace. When it isn't, the reason says why.
+func Allows(policies []api.NetworkPolicy, from *api.Pod, fromNamespaceLabels map[string]string, to *api.Pod, port int) (bool, string) {
+	var selecting []string
+	for i := range policies {
+		policy := &policies[i]
+		if !Selects(policy, to) {
+			continue
+		}
+		for _, rule := range policy.Ingress {
+			if ruleAllows(policy, rule, from, fromNamespaceLabels, port) {
+				return true, ""
+			}
+		}
+		selecting = append(selecting, policy.Name)
+	}
+	if len(selecting) == 0 {
+		return true, ""
+	}
+	slices.Sort(selecting)
+	return false, fmt.Sprintf("not allowed by the network policies selecting pod %s/%s: %v", to.Namespace, to.Name, selecting)
+}
+
+func ruleAllows(policy *api.NetworkPolicy, rule api.NetworkPolicyIngressRule, from *api.Pod, fromNamespaceLabels map[string]string, port int) bool {
+	if len(rule.Ports) > 0 && !slices.Contains(rule.Ports, port) {
+		return false
+	}
+	if len(rule.From) == 0 {
+		return true
+	}
+	for _, peer := range rule.From {
+		if peerMatches(policy, peer, from, fromNamespaceLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerMatches(policy *api.NetworkPolicy, peer api.NetworkPolicyPeer, from *api.Pod, fromNamespaceLabels map[string]string) bool {
+	if peer.NamespaceSelector == nil {
+		if from.Namespace != policy.Namespace {
+			return false
+		}
+	} else if !labels.SelectorFromSet(peer.NamespaceSelector).Matches(fromNamespaceLabels) {
+		return false
+	}
+	return peer.PodSelector == nil || labels.SelectorFromSet(peer.PodSelector).Matches(from.Labels)
+}
@@ -0,0 +1,62 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func pod(namespace, name string, lbls map[string]string) *api.Pod {
+	return &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace, Labels: lbls}}
+}
+
+func TestAllows(t *testing.T) {
+	db := pod("default", "db", map[string]string{"app": "db"})
+	web := pod("default", "web", map[string]string{"app": "web"})
+	other := pod("default", "other", map[string]string{"app": "other"})
+	monitor := pod("monitoring", "prometheus", map[string]string{"app": "prometheus"})
+	monitoringLabels := map[string]string{"team": "ops"}
+
+	onlyWeb := api.NetworkPolicy{
+		ObjectMeta:  api.ObjectMeta{Name: "db-from-web", Namespace: "default"},
+		PodSelector: map[string]string{"app": "db"},
+		Ingress:     []api.NetworkPolicyIngressRule{{From: []api.NetworkPolicyPeer{{PodSelector: map[string]string{"app": "web"}}}, Ports: []int{5432}}},
+	}
+	fromOps := api.NetworkPolicy{
+		ObjectMeta:  api.ObjectMeta{Name: "db-from-ops", Namespace: "default"},
+		PodSelector: map[string]string{"app": "db"},
+		Ingress:     []api.NetworkPolicyIngressRule{{From: []api.NetworkPolicyPeer{{NamespaceSelector: map[string]string{"team": "ops"}}}}},
+	}
+	denyAll := api.NetworkPolicy{ObjectMeta: api.ObjectMeta{Name: "deny-all", Namespace: "default"}}
+
+	tests := []struct {
+		name     string
+		policies []api.NetworkPolicy
+		from     *api.Pod
+		nsLabels map[string]string
+		to       *api.Pod
+		port     int
+		want     bool
+	}{
+		{name: "no policies", from: other, to: db, port: 5432, want: true},
+		{name: "allowed peer and port", policies: []api.NetworkPolicy{onlyWeb}, from: web, to: db, port: 5432, want: true},
+		{name: "other port", policies: []api.NetworkPolicy{onlyWeb}, from: web, to: db, port: 22, want: false},
+		{name: "other pod", policies: []api.NetworkPolicy{onlyWeb}, from: other, to: db, port: 5432, want: false},
+		{name: "pod selector does not reach other namespaces", policies: []api.NetworkPolicy{onlyWeb}, from: pod("staging", "web", map[string]string{"app": "web"}), to: db, port: 5432, want: false},
+		{name: "unselected pod", policies: []api.NetworkPolicy{onlyWeb}, from: other, to: web, port: 80, want: true},
+		{name: "namespace selector", policies: []api.NetworkPolicy{onlyWeb, fromOps}, from: monitor, nsLabels: monitoringLabels, to: db, port: 9187, want: true},
+		{name: "namespace selector mismatch", policies: []api.NetworkPolicy{fromOps}, from: monitor, nsLabels: map[string]string{"team": "dev"}, to: db, port: 9187, want: false},
+		{name: "empty policy denies everything", policies: []api.NetworkPolicy{denyAll}, from: web, to: other, port: 80, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := Allows(tt.policies, tt.from, tt.nsLabels, tt.to, tt.port)
+			if got != tt.want {
+				t.Errorf("Allows() = %v (%s), want %v", got, reason, tt.want)
+			}
+			if !got && reason == "" {
+				t.Error("expected a reason for the denial")
+			}
+		})
+	}
+}
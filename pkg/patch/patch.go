@@ -0,0 +1,337 @@
+// Package patch applies JSON merge patches (RFC 7386) and JSON patches (RFC 6902) to
+// objects in their generic JSON form, as encoding/json decodes them into an
+// interface{}.
+//
+// A merge patch is a partial object: its fields replace the object's, maps are merged
+// recursively, and a null removes a field. Lists are replaced as a whole.
+//
+//	{"labels": {"tier": "backend", "canary": null}}
+//
+// A JSON patch is a list of operations on the values that JSON pointers (RFC 6901)
+// point at: add, remove, replace, move, copy, and test. The operations are applied
+// in order, and if one fails the patch as a whole fails.
+//
+//	[{"op": "replace", "path": "/image", "value": "nginx:1.26"}, {"op": "remove", "path": "/labels/canary"}]
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MergePatch applies the merge patch patch to doc and returns the result. doc may be
+// modified.
+func MergePatch(doc, patch interface{}) interface{} {
+	fields, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	target, ok := doc.(map[string]interface{})
+	if !ok {
+		target = make(map[string]interface{}, len(fields))
+	}
+	for key, value := range fields {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		target[key] = MergePatch(target[key], value)
+	}
+	return target
+}
+
+// Operation is one operation of a JSON patch.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`  // The source of move and copy
+	Value json.RawMessage `json:"value,omitempty"` // The value of add, replace, and test
+}
+
+// ParseJSONPatch decodes a JSON patch and checks that each operation is one it knows
+// with the members it needs.
+func ParseJSONPatch(data []byte) ([]Operation, error) {
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("a JSON patch must be a list of operations: %w", err)
+	}
+	for i, op := range ops {
+		switch op.Op {
+		case "add", "replace", "test":
+			if len(op.Value) == 0 {
+				return nil, fmt.Errorf("operation %d (%s %s): missing value", i, op.Op, op.Path)
+			}
+		case "move", "copy":
+			if _, err := parsePointer(op.From); err != nil {
+				return nil, fmt.Errorf("operation %d (%s %s): from: %w", i, op.Op, op.Path, err)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("operation %d: unknown op %q", i, op.Op)
+		}
+		if _, err := parsePointer(op.Path); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): path: %w", i, op.Op, err)
+		}
+	}
+	return ops, nil
+}
+
+// ApplyJSONPatch applies ops to doc, in order, and returns the result. doc may be
+// modified, even if an operation fails.
+func ApplyJSONPatch(doc interface{}, ops []Operation) (interface{}, error) {
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+			if list, ok := parent.([]interface{}); ok {
+				i, _ := index(key, len(list)-1) // get found it, so it is in range
+				list[i] = value
+				return list, nil
+			}
+			parent.(map[string]interface{})[key] = value
+			return parent, nil
+		})
+	case "move":
+		from, _ := parsePointer(op.From)
+		if len(path) > len(from) && isPrefix(from, path) {
+			return nil, fmt.Errorf("cannot move %s into one of its children", op.From)
+		}
+		doc, moved, err := remove(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		return add(doc, path, moved)
+	case "copy":
+		from, _ := parsePointer(op.From)
+		copied, err := get(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		if copied, err = deepCopy(copied); err != nil {
+			return nil, err
+		}
+		return add(doc, path, copied)
+	case "test":
+		got, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(got, value) {
+			return nil, fmt.Errorf("test failed: the value is %s, not %s", mustMarshal(got), op.Value)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// add adds value at path: it sets a map key, or inserts into a list at an index or,
+// for "-", at the end.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			parent[key] = value
+			return parent, nil
+		case []interface{}:
+			if key == "-" {
+				return append(parent, value), nil
+			}
+			i, err := index(key, len(parent))
+			if err != nil {
+				return nil, err
+			}
+			parent = append(parent, nil)
+			copy(parent[i+1:], parent[i:])
+			parent[i] = value
+			return parent, nil
+		}
+		return nil, fmt.Errorf("cannot add to a %s", typeName(parent))
+	})
+}
+
+// remove removes the value at path, which must exist, and returns it.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	var removed interface{}
+	doc, err := update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			value, ok := parent[key]
+			if !ok {
+				return nil, fmt.Errorf("no field %q", key)
+			}
+			removed = value
+			delete(parent, key)
+			return parent, nil
+		case []interface{}:
+			i, err := index(key, len(parent)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = parent[i]
+			return append(parent[:i], parent[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove from a %s", typeName(parent))
+	})
+	return doc, removed, err
+}
+
+// update finds the map or list holding the last element of path and replaces it with
+// what fn makes of it, given that element's key. Lists may grow or shrink, so every
+// container on the way is stored back into its parent.
+func update(doc interface{}, path []string, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("no field %q", path[0])
+		}
+		child, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[path[0]] = child
+		return node, nil
+	case []interface{}:
+		i, err := index(path[0], len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(node[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = child
+		return node, nil
+	}
+	return nil, fmt.Errorf("cannot look up %q in a %s", path[0], typeName(doc))
+}
+
+// get returns the value at path, which must exist.
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, key := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("no field %q", key)
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(key, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("cannot look up %q in a %s", key, typeName(doc))
+		}
+	}
+	return doc, nil
+}
+
+// parsePointer splits a JSON pointer such as "/labels/app" into its unescaped
+// reference tokens. The empty pointer is the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer %q does not start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// index parses a list index, which may be at most last.
+func index(key string, last int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (len(key) > 1 && key[0] == '0') {
+		return 0, fmt.Errorf("invalid list index %q", key)
+	}
+	if i > last {
+		return 0, fmt.Errorf("list index %d out of range", i)
+	}
+	return i, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopy(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var copied interface{}
+	err = json.Unmarshal(raw, &copied)
+	return copied, err
+}
+
+func mustMarshal(value interface{}) string {
+	raw, _ := json.Marshal(value)
+	return string(raw)
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case nil:
+		return "null"
+	}
+	return "scalar"
+}
@@ -0,0 +1,100 @@
+package patch
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decoding %s: %v", s, err)
+	}
+	return v
+}
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encoding %v: %v", v, err)
+	}
+	return string(raw)
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"adds and replaces", `{"image":"nginx","labels":{"app":"web"}}`, `{"image":"nginx:1.26","nodeName":"node1"}`, `{"image":"nginx:1.26","labels":{"app":"web"},"nodeName":"node1"}`},
+		{"merges maps", `{"labels":{"app":"web","tier":"frontend"}}`, `{"labels":{"tier":"backend","canary":"true"}}`, `{"labels":{"app":"web","canary":"true","tier":"backend"}}`},
+		{"null removes", `{"labels":{"app":"web","tier":"frontend"}}`, `{"labels":{"tier":null},"missing":null}`, `{"labels":{"app":"web"}}`},
+		{"replaces lists", `{"ports":[80,443]}`, `{"ports":[8080]}`, `{"ports":[8080]}`},
+		{"creates maps", `{"image":"nginx"}`, `{"labels":{"app":"web"}}`, `{"image":"nginx","labels":{"app":"web"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encode(t, MergePatch(decode(t, tt.doc), decode(t, tt.patch))); got != tt.want {
+				t.Errorf("MergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	const doc = `{"image":"nginx","labels":{"app":"web","a/b":"x"},"ports":[80,443]}`
+	tests := []struct {
+		name, patch, want, wantErr string
+	}{
+		{name: "add field", patch: `[{"op":"add","path":"/nodeName","value":"node1"}]`, want: `{"image":"nginx","labels":{"a/b":"x","app":"web"},"nodeName":"node1","ports":[80,443]}`},
+		{name: "add to list", patch: `[{"op":"add","path":"/ports/1","value":8080},{"op":"add","path":"/ports/-","value":9090}]`, want: `{"image":"nginx","labels":{"a/b":"x","app":"web"},"ports":[80,8080,443,9090]}`},
+		{name: "remove escaped key", patch: `[{"op":"remove","path":"/labels/a~1b"}]`, want: `{"image":"nginx","labels":{"app":"web"},"ports":[80,443]}`},
+		{name: "remove from list", patch: `[{"op":"remove","path":"/ports/0"}]`, want: `{"image":"nginx","labels":{"a/b":"x","app":"web"},"ports":[443]}`},
+		{name: "replace", patch: `[{"op":"replace","path":"/image","value":"nginx:1.26"}]`, want: `{"image":"nginx:1.26","labels":{"a/b":"x","app":"web"},"ports":[80,443]}`},
+		{name: "move", patch: `[{"op":"move","from":"/labels/app","path":"/labels/name"}]`, want: `{"image":"nginx","labels":{"a/b":"x","name":"web"},"ports":[80,443]}`},
+		{name: "copy", patch: `[{"op":"copy","from":"/ports","path":"/targetPorts"}]`, want: `{"image":"nginx","labels":{"a/b":"x","app":"web"},"ports":[80,443],"targetPorts":[80,443]}`},
+		{name: "test passes", patch: `[{"op":"test","path":"/ports","value":[80,443]},{"op":"replace","path":"/ports/1","value":8443}]`, want: `{"image":"nginx","labels":{"a/b":"x","app":"web"},"ports":[80,8443]}`},
+		{name: "test fails", patch: `[{"op":"test","path":"/image","value":"httpd"}]`, wantErr: "test failed"},
+		{name: "replace missing", patch: `[{"op":"replace","path":"/nodeName","value":"node1"}]`, wantErr: `no field "nodeName"`},
+		{name: "remove out of range", patch: `[{"op":"remove","path":"/ports/2"}]`, wantErr: "out of range"},
+		{name: "add under missing parent", patch: `[{"op":"add","path":"/spec/x","value":1}]`, wantErr: `no field "spec"`},
+		{name: "move into child", patch: `[{"op":"move","from":"/labels","path":"/labels/inner"}]`, wantErr: "into one of its children"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := ParseJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParseJSONPatch: %v", err)
+			}
+			got, err := ApplyJSONPatch(decode(t, doc), ops)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch: %v", err)
+			}
+			if s := encode(t, got); s != tt.want {
+				t.Errorf("got %s, want %s", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPatchRejectsInvalidOperations(t *testing.T) {
+	for _, patch := range []string{
+		`{"op":"add"}`,
+		`[{"op":"frobnicate","path":"/a"}]`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"remove","path":"a"}]`,
+		`[{"op":"copy","from":"a","path":"/b"}]`,
+	} {
+		if _, err := ParseJSONPatch([]byte(patch)); err == nil {
+			t.Errorf("ParseJSONPatch(%s): expected an error", patch)
+		}
+	}
+}
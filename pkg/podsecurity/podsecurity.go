@@ -0,0 +1,175 @@
+// Package podsecurity implements the Pod Security Standards: the privileged, baseline,
+// and restricted levels, what each forbids a pod, and the namespace labels that set the
+// level a namespace enforces, audits, and warns about:
+//
+//	pod-security.kubernetes.io/enforce: baseline   # pods violating baseline are rejected
+//	pod-security.kubernetes.io/warn: restricted    # clients are warned about the rest
+package podsecurity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// Level is a Pod Security Standard. Each level allows less than the one before it.
+// +enum
+type Level string
+
+const (
+	Privileged Level = "privileged" // Anything goes
+	Baseline   Level = "baseline"   // No known privilege escalations
+	Restricted Level = "restricted" // Baseline, and only what a hardened pod needs
+)
+
+// Levels are the valid levels, from least to most restrictive.
+var Levels = []string{string(Privileged), string(Baseline), string(Restricted)}
+
+// The namespace labels that set a namespace's Policy.
+const (
+	EnforceLabel = "pod-security.kubernetes.io/enforce" // Pods violating the level are rejected
+	AuditLabel   = "pod-security.kubernetes.io/audit"   // Pods violating the level are logged by the API server
+	WarnLabel    = "pod-security.kubernetes.io/warn"    // Clients creating pods violating the level are warned
+)
+
+// Labels are the namespace labels that set a Policy.
+var Labels = []string{EnforceLabel, AuditLabel, WarnLabel}
+
+// Policy is the level a namespace holds its pods to in each mode.
+type Policy struct {
+	Enforce Level
+	Audit   Level
+	Warn    Level
+}
+
+// ParseLevel returns the level named s.
+func ParseLevel(s string) (Level, error) {
+	for _, level := range Levels {
+		if s == level {
+			return Level(s), nil
+		}
+	}
+	return "", fmt.Errorf("unknown pod security level %q, expected one of %s", s, strings.Join(Levels, ", "))
+}
+
+// PolicyFor returns the policy set by a namespace's labels. A mode without a label is
+// Privileged, holding pods to nothing. A mode whose label isn't a level is Restricted,
+// so that a mistyped label fails closed; the error says which labels are invalid.
+func PolicyFor(labels map[string]string) (Policy, error) {
+	policy := Policy{Enforce: Privileged, Audit: Privileged, Warn: Privileged}
+	var errs []error
+	for _, mode := range []struct {
+		label string
+		level *Level
+	}{{EnforceLabel, &policy.Enforce}, {AuditLabel, &policy.Audit}, {WarnLabel, &policy.Warn}} {
+		value, ok := labels[mode.label]
+		if !ok {
+			continue
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("label %s: %w", mode.label, err))
+			level = Restricted
+		}
+		*mode.level = level
+	}
+	return policy, errors.Join(errs...)
+}
+
+// check is one control of a level: it returns why a pod fails it, if it does.
+type check struct {
+	level Level // The least restrictive level with the control
+	check func(pod *api.Pod) (string, bool)
+}
+
+// checks are the controls of the levels, in the order their violations are reported.
+var checks = []check{
+	{Baseline, privileged},
+	{Baseline, hostPathVolumes},
+	{Restricted, restrictedVolumeTypes},
+	{Restricted, runAsRoot},
+}
+
+// includes reports whether level has the controls of other.
+func (level Level) includes(other Level) bool {
+	return slices.Index(Levels, string(level)) >= slices.Index(Levels, string(other))
+}
+
+// Check returns how pod violates level, or nothing if it doesn't.
+func Check(level Level, pod *api.Pod) []string {
+	var violations []string
+	for _, c := range checks {
+		if !level.includes(c.level) {
+			continue
+		}
+		if violation, failed := c.check(pod); failed {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// Describe describes the violations of level Check found, for errors and warnings
+// to say what a pod violates, such as `PodSecurity "baseline": hostPath volumes`.
+func Describe(level Level, violations []string) string {
+	return fmt.Sprintf("PodSecurity %q: %s", level, strings.Join(violations, ", "))
+}
+
+// privileged forbids privileged containers, which are root on their node.
+func privileged(pod *api.Pod) (string, bool) {
+	if pod.SecurityContext == nil || !pod.SecurityContext.Privileged {
+		return "", false
+	}
+	return "privileged (pod must not set securityContext.privileged=true)", true
+}
+
+// runAsRoot forbids running as root explicitly. A pod that doesn't set runAsUser
+// runs as its image's user.
+func runAsRoot(pod *api.Pod) (string, bool) {
+	if pod.SecurityContext == nil || pod.SecurityContext.RunAsUser == nil || *pod.SecurityContext.RunAsUser != 0 {
+		return "", false
+	}
+	return "runAsUser=0 (pod must not set securityContext.runAsUser=0)", true
+}
+
+// hostPathVolumes forbids volumes on the node's filesystem, which let a pod read and
+// write the node's files.
+func hostPathVolumes(pod *api.Pod) (string, bool) {
+	var names []string
+	for _, v := range pod.Volumes {
+		if v.HostPath != nil {
+			names = append(names, v.Name)
+		}
+	}
+	return describeVolumes("hostPath volumes", names)
+}
+
+// restrictedVolumeTypes allows only the volume types a pod needs for its own storage.
+// Any volume type not listed here, including ones added later, is forbidden.
+func restrictedVolumeTypes(pod *api.Pod) (string, bool) {
+	var names []string
+	for _, v := range pod.Volumes {
+		if v.EmptyDir == nil && v.PersistentVolumeClaim == nil {
+			names = append(names, v.Name)
+		}
+	}
+	return describeVolumes("restricted volume types", names)
+}
+
+// describeVolumes names the volumes failing a control, if any.
+func describeVolumes(control string, names []string) (string, bool) {
+	switch len(names) {
+	case 0:
+		return "", false
+	case 1:
+		return fmt.Sprintf("%s (volume %q)", control, names[0]), true
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return fmt.Sprintf("%s (volumes %s)", control, strings.Join(quoted, ", ")), true
+}
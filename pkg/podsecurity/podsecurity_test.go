@@ -0,0 +1,49 @@
+package podsecurity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestCheck(t *testing.T) {
+	hostPath := api.Volume{Name: "logs", VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: "/var/log"}}}
+	scratch := api.Volume{Name: "scratch", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}}
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web"}, Volumes: []api.Volume{hostPath, scratch}}
+
+	if violations := Check(Privileged, pod); len(violations) != 0 {
+		t.Errorf("expected privileged to allow anything, got %v", violations)
+	}
+	if violations, want := Check(Baseline, pod), []string{`hostPath volumes (volume "logs")`}; !reflect.DeepEqual(violations, want) {
+		t.Errorf("expected %q for baseline, got %q", want, violations)
+	}
+	if violations := Check(Restricted, pod); len(violations) != 2 {
+		t.Errorf("expected restricted to add its own volume control to baseline's, got %q", violations)
+	}
+	if violations := Check(Restricted, &api.Pod{Volumes: []api.Volume{scratch}}); len(violations) != 0 {
+		t.Errorf("expected an emptyDir volume to be allowed, got %v", violations)
+	}
+
+	root := int64(0)
+	pod = &api.Pod{SecurityContext: &api.SecurityContext{RunAsUser: &root, Privileged: true}}
+	if violations, want := Check(Baseline, pod), []string{"privileged (pod must not set securityContext.privileged=true)"}; !reflect.DeepEqual(violations, want) {
+		t.Errorf("expected %q for baseline, got %q", want, violations)
+	}
+	pod.SecurityContext.Privileged = false
+	if violations, want := Check(Restricted, pod), []string{"runAsUser=0 (pod must not set securityContext.runAsUser=0)"}; !reflect.DeepEqual(violations, want) {
+		t.Errorf("expected %q for restricted, got %q", want, violations)
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	policy, err := PolicyFor(map[string]string{EnforceLabel: "baseline", WarnLabel: "restricted"})
+	if err != nil || policy != (Policy{Enforce: Baseline, Audit: Privileged, Warn: Restricted}) {
+		t.Errorf("unexpected policy %+v, %v", policy, err)
+	}
+	// A mistyped level fails closed.
+	policy, err = PolicyFor(map[string]string{EnforceLabel: "strict"})
+	if err == nil || policy.Enforce != Restricted {
+		t.Errorf("expected an invalid level to be an error and enforce restricted, got %+v, %v", policy, err)
+	}
+}
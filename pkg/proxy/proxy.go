@@ -0,0 +1,143 @@
+// Package proxy is kube-proxy-lite: it forwards connections between simulated pods,
+// from a pod to a service's port or straight to another pod's IP, and refuses the ones
+// the destination's NetworkPolicies don't allow. Each kubelet runs one for the pods on
+// its node.
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/labels"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/networkpolicy"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+// ErrConnectionRefused is returned (wrapped) by Connect when nothing accepts the
+// connection: no pod has the IP, the service has no such port or no ready endpoints,
+// or a network policy forbids it.
+var ErrConnectionRefused = errors.New("connection refused")
+
+// Proxy forwards connections between pods.
+type Proxy struct {
+	client   api.Interface
+	recorder record.EventRecorder
+
+	mu   sync.Mutex
+	next map[string]int // Round-robin position per service
+}
+
+// New creates a proxy that looks up services, pods, and policies through client and
+// records refused connections with recorder.
+func New(client api.Interface, recorder record.EventRecorder) *Proxy {
+	return &Proxy{client: client, recorder: recorder, next: make(map[string]int)}
+}
+
+// Connect forwards a connection from pod from to port on host, which is a pod IP or a
+// service name: "svc" for a service in from's namespace, or "svc.namespace", optionally
+// followed by ".svc" or ".svc.cluster.local". A service's port is forwarded to the
+// target port of one of its Running endpoints, chosen round-robin. It returns the pod
+// the connection reached.
+//
+// A connection the destination's network policies don't allow is refused, with a
+// Warning event on the destination pod.
+func (p *Proxy) Connect(from *api.Pod, host string, port int) (*api.Pod, error) {
+	to, targetPort, err := p.resolve(from, host, port)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := p.client.ListNetworkPolicies(to.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("listing network policies in %s: %w", to.Namespace, err)
+	}
+	var fromNamespaceLabels map[string]string
+	if ns, err := p.client.GetNamespace(from.Namespace); err == nil {
+		fromNamespaceLabels = ns.Labels
+	}
+	if ok, reason := networkpolicy.Allows(policies, from, fromNamespaceLabels, to, targetPort); !ok {
+		log.Printf("Refused connection from pod %s/%s to pod %s/%s port %d: %s", from.Namespace, from.Name, to.Namespace, to.Name, targetPort, reason)
+		p.recorder.Eventf(to, api.EventTypeWarning, "NetworkPolicyDenied", "Refused connection from pod %s/%s to port %d: %s", from.Namespace, from.Name, targetPort, reason)
+		return nil, fmt.Errorf("%w: %s", ErrConnectionRefused, reason)
+	}
+	return to, nil
+}
+
+// resolve returns the pod and port a connection to host and port goes to.
+func (p *Proxy) resolve(from *api.Pod, host string, port int) (*api.Pod, int, error) {
+	if net.ParseIP(host) != nil {
+		pods, err := p.client.ListPods(api.NamespaceAll, "")
+		if err != nil {
+			return nil, 0, fmt.Errorf("listing pods: %w", err)
+		}
+		for i := range pods {
+			if pods[i].PodIP == host && isReady(&pods[i]) {
+				return &pods[i], port, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("%w: no running pod has IP %s", ErrConnectionRefused, host)
+	}
+
+	name, namespace := serviceName(host, from.Namespace)
+	svc, err := p.client.GetService(namespace, name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not resolve host %s: %w", host, err)
+	}
+	targetPort := 0
+	for _, sp := range svc.Ports {
+		if sp.Port == port {
+			targetPort = sp.TargetPort
+			if targetPort == 0 {
+				targetPort = sp.Port
+			}
+			break
+		}
+	}
+	if targetPort == 0 {
+		return nil, 0, fmt.Errorf("%w: service %s/%s has no port %d", ErrConnectionRefused, namespace, name, port)
+	}
+	if len(svc.Selector) == 0 {
+		return nil, 0, fmt.Errorf("%w: service %s/%s has no selector", ErrConnectionRefused, namespace, name)
+	}
+	pods, err := p.client.ListPods(namespace, api.PodRunning)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing pods of service %s/%s: %w", namespace, name, err)
+	}
+	selector := labels.SelectorFromSet(svc.Selector)
+	var endpoints []*api.Pod
+	for i := range pods {
+		if isReady(&pods[i]) && selector.Matches(pods[i].Labels) {
+			endpoints = append(endpoints, &pods[i])
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, 0, fmt.Errorf("%w: service %s/%s has no running endpoints", ErrConnectionRefused, namespace, name)
+	}
+	// Pods may be listed in any order; round-robin over them in a fixed one.
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Name < endpoints[j].Name })
+	p.mu.Lock()
+	key := namespace + "/" + name
+	to := endpoints[p.next[key]%len(endpoints)]
+	p.next[key]++
+	p.mu.Unlock()
+	return to, targetPort, nil
+}
+
+// serviceName splits host into a service name and namespace, defaulting to namespace.
+func serviceName(host, namespace string) (string, string) {
+	host = strings.TrimSuffix(strings.TrimSuffix(host, ".cluster.local"), ".svc")
+	if name, ns, ok := strings.Cut(host, "."); ok {
+		return name, ns
+	}
+	return host, namespace
+}
+
+// isReady reports whether pod can take connections.
+func isReady(pod *api.Pod) bool {
+	return pod.Phase == api.PodRunning && pod.DeletionTimestamp == nil
+}
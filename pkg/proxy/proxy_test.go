@@ -0,0 +1,122 @@
+package proxy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+func runningPod(name, ip string, lbls map[string]string) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: "default", Labels: lbls},
+		Phase:      api.PodRunning,
+		PodIP:      ip,
+	}
+}
+
+func TestConnect(t *testing.T) {
+	web := runningPod("web", "10.244.0.2", map[string]string{"app": "web"})
+	other := runningPod("other", "10.244.0.3", map[string]string{"app": "other"})
+	client := fake.NewClient(
+		&api.Namespace{ObjectMeta: api.ObjectMeta{Name: "default"}},
+		web, other,
+		runningPod("db-0", "10.244.1.2", map[string]string{"app": "db"}),
+		runningPod("db-1", "10.244.1.3", map[string]string{"app": "db"}),
+		&api.Service{
+			ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default"},
+			Selector:   map[string]string{"app": "db"},
+			Ports:      []api.ServicePort{{Protocol: "TCP", Port: 5432, TargetPort: 15432}},
+		},
+		&api.NetworkPolicy{
+			ObjectMeta:  api.ObjectMeta{Name: "db-from-web", Namespace: "default"},
+			PodSelector: map[string]string{"app": "db"},
+			Ingress: []api.NetworkPolicyIngressRule{{
+				From:  []api.NetworkPolicyPeer{{PodSelector: map[string]string{"app": "web"}}},
+				Ports: []int{15432},
+			}},
+		},
+	)
+	p := New(client, record.NewRecorder(client, api.EventSource{Component: "kube-proxy", Host: "node1"}))
+
+	// A service spreads connections over its endpoints.
+	reached := map[string]bool{}
+	for _, host := range []string{"db", "db.default", "db.default.svc.cluster.local"} {
+		to, err := p.Connect(web, host, 5432)
+		if err != nil {
+			t.Fatalf("Connect(web, %s): %v", host, err)
+		}
+		reached[to.Name] = true
+	}
+	if !reached["db-0"] || !reached["db-1"] {
+		t.Errorf("expected connections to reach both endpoints, got %v", reached)
+	}
+
+	if _, err := p.Connect(other, "db", 5432); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("expected a connection from a pod the policy doesn't allow to be refused, got %v", err)
+	}
+	if _, err := p.Connect(web, "10.244.1.2", 22); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("expected a connection to a port the policy doesn't allow to be refused, got %v", err)
+	}
+	if _, err := p.Connect(other, "10.244.0.2", 80); err != nil {
+		t.Errorf("expected a pod no policy selects to accept connections, got %v", err)
+	}
+	if _, err := p.Connect(web, "db", 80); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("expected a connection to a port the service lacks to be refused, got %v", err)
+	}
+	if _, err := p.Connect(web, "10.244.9.9", 80); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("expected a connection to an unknown IP to be refused, got %v", err)
+	}
+
+	events, err := client.ListEvents("default")
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	denied := 0
+	for _, event := range events {
+		if event.Reason == "NetworkPolicyDenied" && event.Type == api.EventTypeWarning && event.InvolvedObject.Kind == "Pod" {
+			denied++
+		}
+	}
+	if denied != 2 {
+		t.Errorf("expected a NetworkPolicyDenied event per refused connection, got %d in %+v", denied, events)
+	}
+}
+
+func TestConnectRoundRobinOrderIsStable(t *testing.T) {
+	web := runningPod("web", "10.244.0.2", map[string]string{"app": "web"})
+	db := func(name string) api.Pod { return *runningPod(name, "", map[string]string{"app": "db"}) }
+	client := fake.NewClient(&api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default"},
+		Selector:   map[string]string{"app": "db"},
+		Ports:      []api.ServicePort{{Protocol: "TCP", Port: 5432}},
+	})
+	// The store lists the endpoints in a different order every time.
+	orders := [][]api.Pod{
+		{db("db-0"), db("db-1"), db("db-2")},
+		{db("db-2"), db("db-0"), db("db-1")},
+		{db("db-1"), db("db-2"), db("db-0")},
+		{db("db-2"), db("db-1"), db("db-0")},
+	}
+	lists := 0
+	client.PrependReactor("list", "pods", func(fake.Action) (bool, interface{}, error) {
+		lists++
+		return true, orders[lists%len(orders)], nil
+	})
+	p := New(client, record.NewRecorder(client, api.EventSource{Component: "kube-proxy", Host: "node1"}))
+
+	var got []string
+	for i := 0; i < 6; i++ {
+		to, err := p.Connect(web, "db", 5432)
+		if err != nil {
+			t.Fatalf("Connect: %v", err)
+		}
+		got = append(got, to.Name)
+	}
+	if want := []string{"db-0", "db-1", "db-2", "db-0", "db-1", "db-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected connections to go round-robin in name order %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,94 @@
+// Package record lets components report Events about the objects they act on, e.g.
+//
+//	recorder := record.NewRecorder(client, api.EventSource{Component: "scheduler"})
+//	recorder.Eventf(pod, api.EventTypeNormal, "Scheduled", "Successfully assigned %s/%s to %s", pod.Namespace, pod.Name, node)
+//
+// Events are best effort: a failure to record one is logged and otherwise ignored, so
+// reporting can never block the work being reported on.
+package record
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// EventRecorder records events about API objects.
+type EventRecorder interface {
+	// Event records that reason happened to obj. obj is a *api.Pod, *api.Node,
+	// *api.Deployment, *api.Service, *api.Namespace, *api.PersistentVolume,
+	// *api.PersistentVolumeClaim, or an api.ObjectReference.
+	Event(obj interface{}, eventType api.EventType, reason, message string)
+	// Eventf is like Event but formats the message with fmt.Sprintf.
+	Eventf(obj interface{}, eventType api.EventType, reason, messageFmt string, args ...interface{})
+}
+
+// eventNamespace is where events about cluster-scoped objects such as nodes are stored.
+const eventNamespace = "default"
+
+// GetReference returns a reference to obj, or false if obj is not a supported type.
+func GetReference(obj interface{}) (api.ObjectReference, bool) {
+	switch o := obj.(type) {
+	case api.ObjectReference:
+		return o, true
+	case *api.ObjectReference:
+		return *o, true
+	case *api.Pod:
+		return api.ObjectReference{Kind: "Pod", Namespace: o.Namespace, Name: o.Name}, true
+	case *api.Node:
+		return api.ObjectReference{Kind: "Node", Name: o.Name}, true
+	case *api.Deployment:
+		return api.ObjectReference{Kind: "Deployment", Namespace: o.Namespace, Name: o.Name}, true
+	case *api.Service:
+		return api.ObjectReference{Kind: "Service", Namespace: o.Namespace, Name: o.Name}, true
+	case *api.Namespace:
+		return api.ObjectReference{Kind: "Namespace", Name: o.Name}, true
+	case *api.PersistentVolume:
+		return api.ObjectReference{Kind: "PersistentVolume", Name: o.Name}, true
+	case *api.PersistentVolumeClaim:
+		return api.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: o.Namespace, Name: o.Name}, true
+	}
+	return api.ObjectReference{}, false
+}
+
+type recorder struct {
+	client api.Interface
+	source api.EventSource
+}
+
+// NewRecorder returns an EventRecorder that posts events to the API server through
+// client, attributed to source.
+func NewRecorder(client api.Interface, source api.EventSource) EventRecorder {
+	return &recorder{client: client, source: source}
+}
+
+func (r *recorder) Event(obj interface{}, eventType api.EventType, reason, message string) {
+	ref, ok := GetReference(obj)
+	if !ok {
+		log.Printf("record: cannot record event %s for unsupported object type %T", reason, obj)
+		return
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = eventNamespace
+	}
+	now := time.Now()
+	event := &api.Event{
+		InvolvedObject: ref,
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Source:         r.source,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+	}
+	if _, err := r.client.CreateEvent(namespace, event); err != nil {
+		log.Printf("record: failed to record event %s for %s %s: %v", reason, ref.Kind, ref.Name, err)
+	}
+}
+
+func (r *recorder) Eventf(obj interface{}, eventType api.EventType, reason, messageFmt string, args ...interface{}) {
+	r.Event(obj, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
@@ -0,0 +1,105 @@
+// Package resource parses resource quantities such as "512Mi", "10G", or, for CPU,
+// "250m".
+package resource
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// suffixes maps each supported suffix to its multiplier. Binary suffixes (Ki, Mi, ...)
+// are powers of 1024; decimal ones (k, M, ...) powers of 1000.
+var suffixes = map[string]int64{
+	"":   1,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+}
+
+// ParseQuantity returns the value of a quantity such as "10Gi", "1.5G", or "500": a
+// non-negative number followed by an optional suffix. Fractions are rounded up.
+func ParseQuantity(s string) (int64, error) {
+	return parse(strings.TrimSpace(s), 1)
+}
+
+// ParseMilliQuantity returns the value of a quantity in thousandths, so that CPU
+// quantities come out whole: "250m" (a quarter of a CPU) is 250, and "1.5" is 1500. It
+// takes the suffixes ParseQuantity does, as well as m for thousandths.
+func ParseMilliQuantity(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if number, ok := strings.CutSuffix(s, "m"); ok {
+		return parse(number, 1)
+	}
+	return parse(s, 1000)
+}
+
+// parse returns the value of quantity s multiplied by scale.
+func parse(s string, scale int64) (int64, error) {
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	number, suffix := s, ""
+	if i >= 0 {
+		number, suffix = s[:i], s[i:]
+	}
+	multiplier, ok := suffixes[suffix]
+	if !ok {
+		return 0, fmt.Errorf("quantity %q has unknown suffix %q", s, suffix)
+	}
+	multiplier *= scale
+	if number == "" {
+		return 0, fmt.Errorf("quantity %q has no number", s)
+	}
+	if whole, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if whole > math.MaxInt64/multiplier {
+			return 0, fmt.Errorf("quantity %q is too large", s)
+		}
+		return whole * multiplier, nil
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("quantity %q is not a number: %w", s, err)
+	}
+	v := math.Ceil(f * float64(multiplier))
+	if v >= math.MaxInt64 {
+		return 0, fmt.Errorf("quantity %q is too large", s)
+	}
+	return int64(v), nil
+}
+
+// MustParse is like ParseQuantity but panics on an invalid quantity. It is meant for
+// constants and tests.
+func MustParse(s string) int64 {
+	v, err := ParseQuantity(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// FormatQuantity formats v as ParseQuantity reads it, with the largest binary suffix
+// that leaves it whole: 1<<30 is "1Gi", and 1500 is "1500".
+func FormatQuantity(v int64) string {
+	for _, suffix := range []string{"Pi", "Ti", "Gi", "Mi", "Ki"} {
+		if m := suffixes[suffix]; v != 0 && v%m == 0 {
+			return strconv.FormatInt(v/m, 10) + suffix
+		}
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// FormatMilliQuantity formats v thousandths as ParseMilliQuantity reads them: 2000 is
+// "2", and 250 is "250m".
+func FormatMilliQuantity(v int64) string {
+	if v%1000 == 0 {
+		return strconv.FormatInt(v/1000, 10)
+	}
+	return strconv.FormatInt(v, 10) + "m"
+}
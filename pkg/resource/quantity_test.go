@@ -0,0 +1,69 @@
+package resource
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "500", want: 500},
+		{in: "1k", want: 1000},
+		{in: "10Gi", want: 10 << 30},
+		{in: "1.5G", want: 1500000000},
+		{in: "0.5Ki", want: 512},
+		{in: " 2Mi ", want: 2 << 20},
+		{in: "", wantErr: true},
+		{in: "Gi", wantErr: true},
+		{in: "10GB", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "9000000Pi", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuantity(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %d, %v; want %d (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseMilliQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "250m", want: 250},
+		{in: "1", want: 1000},
+		{in: "1.5", want: 1500},
+		{in: "0.0005", want: 1},
+		{in: "2k", want: 2000000},
+		{in: "1Mi", want: 1000 << 20},
+		{in: "m", wantErr: true},
+		{in: "1.5mm", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMilliQuantity(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMilliQuantity(%q) = %d, %v; want %d (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	for v, want := range map[int64]string{0: "0", 1500: "1500", 1 << 10: "1Ki", 3 << 29: "1536Mi", 4 << 30: "4Gi"} {
+		if got := FormatQuantity(v); got != want {
+			t.Errorf("FormatQuantity(%d) = %q, want %q", v, got, want)
+		}
+		if back := MustParse(want); back != v {
+			t.Errorf("ParseQuantity(%q) = %d, want %d", want, back, v)
+		}
+	}
+	for v, want := range map[int64]string{0: "0", 250: "250m", 2000: "2", 1500: "1500m"} {
+		if got := FormatMilliQuantity(v); got != want {
+			t.Errorf("FormatMilliQuantity(%d) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+)
+
+// watchCluster has the scheduler follow the cluster's pods and nodes through watches,
+// rather than listing them every pass, and wake up for the changes that may let a pod
+// be scheduled. A failed watch is started again after interval, as measured by clk.
+func (s *Scheduler) watchCluster(interval time.Duration, clk clock.Clock) {
+	s.pods = controller.NewPodInformer(s.client, api.NamespaceAll, interval)
+	s.nodes = controller.NewNodeInformer(s.client, interval)
+	s.pods.Clock, s.nodes.Clock = clk, clk
+
+	s.cache = newSchedulerCache()
+
+	s.pods.AddEventHandler(controller.EventHandler{
+		OnAdd:    s.podChanged,
+		OnUpdate: func(_, obj interface{}) { s.podChanged(obj) },
+		OnDelete: func(obj interface{}) {
+			if pod, ok := obj.(*api.Pod); ok {
+				s.cache.removePod(pod)
+				s.wakeUp() // Its node may now have room for another
+			}
+		},
+	})
+	s.nodes.AddEventHandler(controller.EventHandler{
+		OnAdd: func(obj interface{}) {
+			s.cache.setNode(obj.(*api.Node))
+			s.wakeUp()
+		},
+		OnUpdate: func(oldObj, newObj interface{}) {
+			old, node := oldObj.(*api.Node), newObj.(*api.Node)
+			s.cache.setNode(node)
+			if conditionsChanged(old, node) || old.Unschedulable != node.Unschedulable || !reflect.DeepEqual(old.Allocatable, node.Allocatable) {
+				s.wakeUp()
+			}
+		},
+		OnDelete: func(obj interface{}) {
+			if node, ok := obj.(*api.Node); ok {
+				s.cache.removeNode(node.Name)
+			}
+		},
+	})
+}
+
+// conditionsChanged reports whether the status of any of the node's conditions
+// changed, which may make it pass the filters a pod failed.
+func conditionsChanged(old, node *api.Node) bool {
+	if old.Status != node.Status || len(old.Conditions) != len(node.Conditions) {
+		return true
+	}
+	for _, cond := range node.Conditions {
+		if prev := api.GetNodeCondition(old, cond.Type); prev == nil || prev.Status != cond.Status {
+			return true
+		}
+	}
+	return false
+}
+
+// podChanged counts obj, a pod, in the cache, and wakes the scheduler if the pod is
+// waiting for a node, or has finished and so may have left room for one.
+func (s *Scheduler) podChanged(obj interface{}) {
+	pod := obj.(*api.Pod)
+	s.cache.updatePod(pod)
+	if pod.Phase == api.PodPending && pod.NodeName == "" && pod.DeletionTimestamp == nil ||
+		pod.NodeName != "" && (api.IsPodGone(pod) || pod.Phase == api.PodSucceeded || pod.Phase == api.PodFailed) {
+		s.wakeUp()
+	}
+}
+
+// wakeUp has Run start a pass as soon as the current one, if any, is done. Wake-ups
+// that arrive during a pass make one more pass, not one each.
+func (s *Scheduler) wakeUp() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nodeInfos returns the nodes with the pods bound to each counted: from the cache
+// once the watches have filled it, and from the API server while they do or if the
+// client can't watch.
+func (s *Scheduler) nodeInfos() ([]*nodeInfo, error) {
+	if s.cache != nil && s.pods.HasSynced() && s.nodes.HasSynced() {
+		return s.cache.snapshot(), nil
+	}
+
+	nodes, err := s.client.ListNodes("")
+	if err != nil {
+		return nil, fmt.Errorf("fetching nodes: %w", err)
+	}
+	pods, err := s.client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		return nil, fmt.Errorf("fetching pods: %w", err)
+	}
+	return newNodeInfos(nodes, pods), nil
+}
+
+// newNodeInfos returns the nodes in name order, with the pods bound to them counted.
+func newNodeInfos(nodes []api.Node, pods []api.Pod) []*nodeInfo {
+	c := newSchedulerCache()
+	for i := range nodes {
+		c.setNode(&nodes[i])
+	}
+	for i := range pods {
+		c.updatePod(&pods[i])
+	}
+	return c.snapshot()
+}
+
+// schedulerCache keeps, for every node, how many pods are bound to it and what they
+// request, updated a pod at a time from the scheduler's watches, so that a pass sees
+// what room each node has left without going through every pod in the cluster.
+type schedulerCache struct {
+	mu    sync.Mutex
+	nodes map[string]*api.Node
+	// usage is by node name, and also covers pods bound to a node the cache hasn't
+	// seen (yet), which count once it has.
+	usage map[string]*nodeUsage
+	pods  map[string]podUsage // The pods counted in usage, by namespace/name
+}
+
+type nodeUsage struct {
+	pods      int
+	requested resources
+}
+
+type podUsage struct {
+	node     string
+	requests resources
+}
+
+func newSchedulerCache() *schedulerCache {
+	return &schedulerCache{
+		nodes: make(map[string]*api.Node),
+		usage: make(map[string]*nodeUsage),
+		pods:  make(map[string]podUsage),
+	}
+}
+
+// setNode adds node to the cache, or replaces the cached node of its name.
+func (c *schedulerCache) setNode(node *api.Node) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes[node.Name] = node
+}
+
+// removeNode drops the node named name. The pods still bound to it stay counted, in
+// case it comes back.
+func (c *schedulerCache) removeNode(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.nodes, name)
+}
+
+// updatePod counts pod against the node it is bound to, in place of whatever it was
+// counted as before, or stops counting it once it is unbound, finished, or gone.
+func (c *schedulerCache) updatePod(pod *api.Pod) {
+	key := pod.Namespace + "/" + pod.Name
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(key)
+	if pod.NodeName == "" || api.IsPodGone(pod) || pod.Phase == api.PodSucceeded || pod.Phase == api.PodFailed {
+		return
+	}
+	p := podUsage{node: pod.NodeName, requests: api.PodRequests(pod)}
+	usage := c.usage[p.node]
+	if usage == nil {
+		usage = &nodeUsage{requested: make(resources)}
+		c.usage[p.node] = usage
+	}
+	usage.pods++
+	for name, v := range p.requests {
+		usage.requested[name] += v
+	}
+	c.pods[key] = p
+}
+
+// removePod stops counting a deleted pod.
+func (c *schedulerCache) removePod(pod *api.Pod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(pod.Namespace + "/" + pod.Name)
+}
+
+// forget takes the pod under key, if counted, off its node. The caller must hold c.mu.
+func (c *schedulerCache) forget(key string) {
+	p, ok := c.pods[key]
+	if !ok {
+		return
+	}
+	delete(c.pods, key)
+	usage := c.usage[p.node]
+	usage.pods--
+	for name, v := range p.requests {
+		usage.requested[name] -= v
+	}
+	if usage.pods == 0 {
+		delete(c.usage, p.node)
+	}
+}
+
+// snapshot returns the cached nodes in name order, with their pods counted, for a pass
+// to count the pods it binds against without changing the cache.
+func (c *schedulerCache) snapshot() []*nodeInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	infos := make([]*nodeInfo, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		info := newNodeInfo(node)
+		if usage := c.usage[node.Name]; usage != nil {
+			info.pods = usage.pods
+			for name, v := range usage.requested {
+				info.requested[name] = v
+			}
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].node.Name < infos[j].node.Name })
+	return infos
+}
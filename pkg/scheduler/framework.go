@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// maxNodeScore is the highest score a score plugin gives a node.
+const maxNodeScore = 100
+
+// resources are quantities of resources, as api.ParseResourceQuantity returns them.
+type resources map[api.ResourceName]int64
+
+// nodeInfo is what the plugins know about a node: the node, how many pods are bound
+// to it, and what they request.
+type nodeInfo struct {
+	node        *api.Node
+	allocatable resources // node.Allocatable, parsed; resources it leaves out aren't limited
+	pods        int       // Bound pods that haven't finished
+	requested   resources // What they request, overhead included
+}
+
+// newNodeInfo returns the nodeInfo of node with no pods bound to it.
+func newNodeInfo(node *api.Node) *nodeInfo {
+	info := &nodeInfo{node: node, allocatable: make(resources, len(node.Allocatable)), requested: make(resources)}
+	for name, quantity := range node.Allocatable {
+		if v, err := api.ParseResourceQuantity(name, quantity); err == nil {
+			info.allocatable[name] = v
+		}
+	}
+	return info
+}
+
+// add counts a pod that requests requests as bound to the node.
+func (n *nodeInfo) add(requests resources) {
+	n.pods++
+	for name, v := range requests {
+		n.requested[name] += v
+	}
+}
+
+// filterPlugin rules out the nodes a pod can't run on.
+type filterPlugin struct {
+	name string
+	// filter returns why pod can't run on node, or "" if it can. Reasons are phrased to
+	// be counted, as in "2 node(s) were not Ready".
+	filter func(pod *api.Pod, node *nodeInfo) string
+}
+
+// scorePlugin ranks the nodes that passed every filter.
+type scorePlugin struct {
+	name string
+	// score rates node, one of feasible, from 0 to maxNodeScore; higher is better.
+	score func(pod *api.Pod, node *nodeInfo, feasible []*nodeInfo) int
+}
+
+// filterPlugins and scorePlugins are the scheduling pipeline, in the order they run.
+var (
+	filterPlugins = []filterPlugin{
+		{"NodeReady", func(pod *api.Pod, node *nodeInfo) string {
+			if !api.IsNodeReady(node.node) {
+				return "node(s) were not Ready"
+			}
+			return ""
+		}},
+		{"NodePressure", nodePressureFilter},
+		{"NodeResourcesFit", nodeResourcesFit},
+		{"NodeUnschedulable", func(pod *api.Pod, node *nodeInfo) string {
+			if node.node.Unschedulable {
+				return "node(s) were unschedulable"
+			}
+			return ""
+		}},
+	}
+	scorePlugins = []scorePlugin{
+		{"LeastPods", leastPodsScore},
+	}
+)
+
+// nodePressureFilter keeps pods off nodes running low on a resource, as the kubelet
+// would evict them again: off nodes under disk or PID pressure altogether, and
+// BestEffort pods, the first evicted, off nodes under memory pressure.
+func nodePressureFilter(pod *api.Pod, node *nodeInfo) string {
+	under := func(t api.NodeConditionType) bool {
+		cond := api.GetNodeCondition(node.node, t)
+		return cond != nil && cond.Status == api.ConditionTrue
+	}
+	switch {
+	case under(api.NodeDiskPressure):
+		return "node(s) had disk pressure"
+	case under(api.NodePIDPressure):
+		return "node(s) had PID pressure"
+	case under(api.NodeMemoryPressure) && api.GetPodQOS(pod) == api.PodQOSBestEffort:
+		return "node(s) had memory pressure"
+	}
+	return ""
+}
+
+// nodeResourcesFit keeps pods off nodes without room for them: where what the node's
+// pods request and what the pod requests add up to more CPU or memory than the node
+// has allocatable, or where the node already runs as many pods as it may.
+func nodeResourcesFit(pod *api.Pod, node *nodeInfo) string {
+	if limit, ok := node.allocatable[api.ResourcePods]; ok && int64(node.pods) >= limit {
+		return "node(s) had too many pods"
+	}
+	requests := api.PodRequests(pod)
+	for _, name := range []api.ResourceName{api.ResourceCPU, api.ResourceMemory} {
+		limit, ok := node.allocatable[name]
+		if ok && requests[name] > 0 && node.requested[name]+requests[name] > limit {
+			return "node(s) had insufficient " + string(name)
+		}
+	}
+	return ""
+}
+
+// nodePodCapacity is how many pods leastPodsScore takes a node to hold, the kubelet's
+// default maxPods in Kubernetes.
+const nodePodCapacity = 110
+
+// leastPodsScore spreads pods: an empty node scores maxNodeScore, and a node with
+// nodePodCapacity pods or more 0.
+func leastPodsScore(pod *api.Pod, node *nodeInfo, feasible []*nodeInfo) int {
+	free := max(nodePodCapacity-node.pods, 0)
+	return maxNodeScore * free / nodePodCapacity
+}
+
+// FilterFailure is a filter a node failed, and why.
+type FilterFailure struct {
+	Plugin string `json:"plugin"`
+	Reason string `json:"reason"`
+}
+
+// NodeResult is how a node fared in the pipeline.
+type NodeResult struct {
+	Node     string          `json:"node"`
+	Failures []FilterFailure `json:"failures,omitempty"` // Every filter the node failed
+	Scores   map[string]int  `json:"scores,omitempty"`   // Per score plugin, once the node passed every filter
+	Score    int             `json:"score"`              // The sum of Scores
+}
+
+// Explanation is the outcome of running the pipeline for a pod.
+type Explanation struct {
+	Pod   string       `json:"pod"`   // namespace/name
+	Nodes []NodeResult `json:"nodes"` // By node name
+	// BestNodes are the feasible nodes with the highest score. The scheduler binds the
+	// pod to one of them, round-robin.
+	BestNodes []string `json:"bestNodes,omitempty"`
+	// Message says why no node fits, as in "0/3 nodes are available: 1 node(s) were
+	// unschedulable, 2 node(s) were not Ready."
+	Message string `json:"message,omitempty"`
+}
+
+// runPipeline runs every filter on every node, so that the explanation lists all the
+// reasons a node was ruled out, then scores the nodes that are left.
+func runPipeline(pod *api.Pod, nodes []*nodeInfo) *Explanation {
+	e := &Explanation{Pod: pod.Namespace + "/" + pod.Name, Nodes: make([]NodeResult, len(nodes))}
+	var feasible []*nodeInfo
+	var feasibleResults []*NodeResult
+	failed := map[string]int{}
+	for i, node := range nodes {
+		result := &e.Nodes[i]
+		result.Node = node.node.Name
+		for _, f := range filterPlugins {
+			if reason := f.filter(pod, node); reason != "" {
+				result.Failures = append(result.Failures, FilterFailure{Plugin: f.name, Reason: reason})
+				failed[reason]++
+			}
+		}
+		if len(result.Failures) == 0 {
+			feasible = append(feasible, node)
+			feasibleResults = append(feasibleResults, result)
+		}
+	}
+
+	best := -1
+	for i, node := range feasible {
+		result := feasibleResults[i]
+		result.Scores = make(map[string]int, len(scorePlugins))
+		for _, s := range scorePlugins {
+			score := s.score(pod, node, feasible)
+			result.Scores[s.name] = score
+			result.Score += score
+		}
+		switch {
+		case result.Score > best:
+			best = result.Score
+			e.BestNodes = []string{result.Node}
+		case result.Score == best:
+			e.BestNodes = append(e.BestNodes, result.Node)
+		}
+	}
+	if len(feasible) == 0 {
+		e.Message = unschedulableMessage(len(nodes), failed)
+	}
+	return e
+}
+
+// unschedulableMessage summarizes why none of total nodes fit, given how many nodes
+// failed for each reason.
+func unschedulableMessage(total int, failed map[string]int) string {
+	if total == 0 {
+		return "0/0 nodes are available: no nodes are registered."
+	}
+	reasons := make([]string, 0, len(failed))
+	for reason, count := range failed {
+		reasons = append(reasons, fmt.Sprintf("%d %s", count, reason))
+	}
+	sort.Strings(reasons)
+	return fmt.Sprintf("0/%d nodes are available: %s.", total, strings.Join(reasons, ", "))
+}
+
+// Explain runs the scheduling pipeline for pod against the cluster's current nodes and
+// pods, without binding it, and reports how every node fared. pod need not exist.
+func Explain(client api.Interface, pod *api.Pod) (*Explanation, error) {
+	nodes, err := client.ListNodes("")
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	pods, err := client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	return runPipeline(pod, newNodeInfos(nodes, pods)), nil
+}
+
+// FitsNode runs the scheduler's filters for pod against node as if it ran no pods, such
+// as a node that has yet to be added, and returns the filters it fails.
+func FitsNode(pod *api.Pod, node *api.Node) []FilterFailure {
+	var failures []FilterFailure
+	for _, f := range filterPlugins {
+		if reason := f.filter(pod, newNodeInfo(node)); reason != "" {
+			failures = append(failures, FilterFailure{Plugin: f.name, Reason: reason})
+		}
+	}
+	return failures
+}
@@ -0,0 +1,216 @@
+// Package scheduler assigns pending pods to ready, schedulable nodes, spreading them
+// across the nodes.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/backoff"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controller"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/heartbeat"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/logs"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+const DefaultNamespace = "default" // Should match apiserver's default if not specified
+
+// Scheduler assigns pending pods to nodes. Each pod goes through a pipeline of filters,
+// which rule out the nodes it can't run on, and scores, which rank the rest (see
+// Explain); ties go round-robin.
+type Scheduler struct {
+	client        api.Interface
+	recorder      record.EventRecorder
+	interval      atomic.Int64 // time.Duration; see SetInterval
+	clock         clock.Clock
+	nextNodeIndex int              // For breaking ties between the best nodes round-robin
+	apiBackoff    *backoff.Backoff // Spaces out passes while the API server can't be reached
+	passes        atomic.Uint64    // Scheduling passes that reached the API server
+
+	// pods and nodes watch the cluster when the client can watch, and keep cache up
+	// to date, so that a pass needn't list every pod and node; they are nil otherwise.
+	pods, nodes *controller.PollingInformer
+	cache       *schedulerCache
+	wake        chan struct{} // Signalled when a pass may have something new to schedule
+}
+
+// maxAPIBackoff caps the delay between passes while the API server can't be reached;
+// the first retry waits one interval.
+const maxAPIBackoff = time.Minute
+
+// NewScheduler returns a scheduler that looks for pending pods every interval, as
+// measured by clk, and, if client can watch, as soon as a pod or node changes.
+func NewScheduler(client api.Interface, recorder record.EventRecorder, interval time.Duration, clk clock.Clock) *Scheduler {
+	s := &Scheduler{
+		client:     client,
+		recorder:   recorder,
+		clock:      clk,
+		apiBackoff: backoff.New(interval, maxAPIBackoff),
+		wake:       make(chan struct{}, 1),
+	}
+	s.interval.Store(int64(interval))
+	if _, ok := client.(api.Watcher); ok {
+		s.watchCluster(interval, clk)
+	}
+	return s
+}
+
+// Interval returns how often the scheduler makes a pass regardless of changes.
+func (s *Scheduler) Interval() time.Duration {
+	return time.Duration(s.interval.Load())
+}
+
+// SetInterval changes how often the scheduler makes a pass regardless of changes. It
+// is safe to call while the scheduler runs, and takes effect with a pass it starts
+// at once.
+func (s *Scheduler) SetInterval(d time.Duration) {
+	s.interval.Store(int64(d))
+	s.wakeUp()
+}
+
+// Run schedules pods until ctx is cancelled. A pass runs whenever the scheduler's
+// caches show a pod that needs a node, or a node that may now take one, and every
+// interval regardless, which retries the pods no node could take.
+func (s *Scheduler) Run(ctx context.Context) {
+	interval := s.Interval()
+	log.Printf("Scheduler starting scheduling loop with interval %v.", interval)
+	go heartbeat.Run(ctx, s.client, "scheduler", heartbeat.DefaultPeriod)
+	if s.pods != nil {
+		go s.pods.Run(ctx)
+		go s.nodes.Run(ctx)
+	}
+	ticker := s.clock.NewTicker(interval)
+	defer func() { ticker.Stop() }()
+	for {
+		if d := s.Interval(); d != interval {
+			log.Printf("Scheduling interval changed from %v to %v", interval, d)
+			ticker.Stop()
+			ticker, interval = s.clock.NewTicker(d), d
+		}
+		// While the API server can't be reached, wait out a growing back-off instead
+		// of the interval, so its restart isn't met by a storm of log lines.
+		next, wake := ticker.C(), s.wake
+		if err := s.schedulePods(); err != nil {
+			delay := s.apiBackoff.Next()
+			log.Printf("Scheduling pass failed (%d in a row), retrying in %v: %v", s.apiBackoff.Failures(), delay.Round(time.Millisecond), err)
+			next, wake = s.clock.After(delay), nil
+		} else {
+			if failures := s.apiBackoff.Reset(); failures > 0 {
+				log.Printf("Scheduling pass succeeded again after %d failures", failures)
+			}
+			s.passes.Add(1)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-next:
+		}
+	}
+}
+
+// schedulePods runs one scheduling pass. It returns an error if it couldn't list the
+// pods or nodes; failures to bind single pods are recorded as events and retried on
+// the next pass.
+func (s *Scheduler) schedulePods() error {
+	// 1. Get pending pods, in every namespace
+	pendingPods, err := s.client.ListPods(api.NamespaceAll, api.PodPending)
+	if err != nil {
+		return fmt.Errorf("fetching pending pods: %w", err)
+	}
+
+	if len(pendingPods) == 0 {
+		if logs.V(2) {
+			log.Println("No pending pods to schedule.")
+		}
+		return nil
+	}
+	if logs.V(2) {
+		log.Printf("Found %d pending pods.", len(pendingPods))
+	}
+
+	// 2. Get the nodes, and count the pods already bound to each
+	infos, err := s.nodeInfos()
+	if err != nil {
+		return err
+	}
+
+	// 3. Run the filter and score pipeline for each pod; ties go round-robin
+	for _, pod := range pendingPods {
+		// Explicitly check if the pod is marked for deletion, even if filtered by ListPods
+		// This handles potential race conditions or changes in ListPods behavior.
+		if pod.DeletionTimestamp != nil {
+			log.Printf("Scheduler: Skipping pod %s/%s as it is marked for deletion.", pod.Namespace, pod.Name)
+			continue
+		}
+
+		result := runPipeline(&pod, infos)
+		if len(result.BestNodes) == 0 {
+			s.markUnschedulable(&pod, result.Message)
+			continue
+		}
+		selectedNode := result.BestNodes[s.nextNodeIndex%len(result.BestNodes)]
+		s.nextNodeIndex++
+
+		// Update pod object
+		podToUpdate := pod // Make a copy to avoid modifying the one in the list directly
+		podToUpdate.NodeName = selectedNode
+		podToUpdate.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+		podToUpdate.Phase = api.PodScheduled
+		api.SetPodCondition(&podToUpdate, api.PodCondition{Type: api.PodScheduledCondition, Status: api.ConditionTrue})
+		// podToUpdate.HostIP = selectedNode.Address // Or some IP from the node if available
+
+		log.Printf("Attempting to schedule pod %s/%s to node %s", podToUpdate.Namespace, podToUpdate.Name, selectedNode)
+
+		// 4. Update pod on API server
+		if err := s.client.UpdatePod(&podToUpdate); err != nil {
+			log.Printf("Error updating pod %s/%s: %v", podToUpdate.Namespace, podToUpdate.Name, err)
+			s.recorder.Eventf(&pod, api.EventTypeWarning, "FailedScheduling", "Binding to node %s failed: %v", selectedNode, err)
+			// Consider if we should retry or skip this pod for now
+		} else {
+			log.Printf("Successfully scheduled pod %s/%s to node %s", podToUpdate.Namespace, podToUpdate.Name, selectedNode)
+			s.recorder.Eventf(&podToUpdate, api.EventTypeNormal, "Scheduled", "Successfully assigned %s/%s to %s", podToUpdate.Namespace, podToUpdate.Name, selectedNode)
+			// Count the pod at once, rather than when the watch reports the binding,
+			// so that neither the rest of the pass nor the next one overfills the node.
+			if s.cache != nil {
+				s.cache.updatePod(&podToUpdate)
+			}
+			requests := api.PodRequests(&podToUpdate)
+			for _, info := range infos {
+				if info.node.Name == selectedNode {
+					info.add(requests)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// markUnschedulable records why pod can't be scheduled, as a PodScheduled=False
+// condition with reason Unschedulable and a FailedScheduling event. Both are only
+// written when the message changes, so a pod waiting for a node isn't rewritten on
+// every pass; the scheduler keeps trying it all the same.
+func (s *Scheduler) markUnschedulable(pod *api.Pod, message string) {
+	if cond := api.GetPodCondition(pod, api.PodScheduledCondition); cond != nil &&
+		cond.Status == api.ConditionFalse && cond.Reason == api.PodReasonUnschedulable && cond.Message == message {
+		return
+	}
+	log.Printf("Cannot schedule pod %s/%s: %s", pod.Namespace, pod.Name, message)
+	s.recorder.Event(pod, api.EventTypeWarning, "FailedScheduling", message)
+	updated := *pod
+	updated.Conditions = append([]api.PodCondition(nil), pod.Conditions...)
+	api.SetPodCondition(&updated, api.PodCondition{
+		Type:    api.PodScheduledCondition,
+		Status:  api.ConditionFalse,
+		Reason:  api.PodReasonUnschedulable,
+		Message: message,
+	})
+	if err := s.client.UpdatePod(&updated); err != nil {
+		log.Printf("Error marking pod %s/%s unschedulable: %v", pod.Namespace, pod.Name, err)
+	}
+}
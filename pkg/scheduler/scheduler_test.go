@@ -0,0 +1,435 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api/fake"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+)
+
+func TestSchedulePodsRoundRobin(t *testing.T) {
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady},
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node2"}, Status: api.NodeNotReady},
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node3"}, Status: api.NodeReady, Unschedulable: true},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: DefaultNamespace}, Phase: api.PodPending},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "b", Namespace: DefaultNamespace}, Phase: api.PodPending},
+	)
+
+	NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), 0, clock.RealClock{}).schedulePods()
+
+	pods, err := client.ListPods(DefaultNamespace, "")
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	for _, pod := range pods {
+		if pod.Phase != api.PodScheduled {
+			t.Errorf("pod %s: expected phase Scheduled, got %s", pod.Name, pod.Phase)
+		}
+		if pod.NodeName != "node1" {
+			t.Errorf("pod %s: expected to land on the only ready, schedulable node, got %q", pod.Name, pod.NodeName)
+		}
+	}
+}
+
+func TestSchedulePodsInEveryNamespace(t *testing.T) {
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: DefaultNamespace}, Phase: api.PodPending},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "b", Namespace: "staging"}, Phase: api.PodPending},
+	)
+
+	NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), 0, clock.RealClock{}).schedulePods()
+
+	pods, err := client.ListPods(api.NamespaceAll, "")
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(pods))
+	}
+	for _, pod := range pods {
+		if pod.Phase != api.PodScheduled || pod.NodeName != "node1" {
+			t.Errorf("pod %s/%s: expected to be bound to node1, got phase %s on %q", pod.Namespace, pod.Name, pod.Phase, pod.NodeName)
+		}
+	}
+}
+
+func TestSchedulePodsRecordsEvents(t *testing.T) {
+	client := fake.NewClient(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: DefaultNamespace}, Phase: api.PodPending})
+	s := NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), 0, clock.RealClock{})
+
+	s.schedulePods()
+	if _, err := client.CreateNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	s.schedulePods()
+
+	events, err := client.ListEvents(DefaultNamespace)
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	reasons := map[string]api.EventType{}
+	for _, e := range events {
+		if e.InvolvedObject != (api.ObjectReference{Kind: "Pod", Namespace: DefaultNamespace, Name: "a"}) {
+			t.Errorf("event %s: unexpected involved object %+v", e.Reason, e.InvolvedObject)
+		}
+		reasons[e.Reason] = e.Type
+	}
+	if reasons["FailedScheduling"] != api.EventTypeWarning {
+		t.Errorf("expected a Warning FailedScheduling event while no node was ready, got %v", reasons)
+	}
+	if reasons["Scheduled"] != api.EventTypeNormal {
+		t.Errorf("expected a Normal Scheduled event once a node was ready, got %v", reasons)
+	}
+}
+
+func TestSchedulePodsMarksUnschedulable(t *testing.T) {
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady, Unschedulable: true},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: DefaultNamespace}, Phase: api.PodPending},
+	)
+	s := NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), 0, clock.RealClock{})
+
+	s.schedulePods()
+	s.schedulePods()
+	pod, err := client.GetPod(DefaultNamespace, "a")
+	if err != nil {
+		t.Fatalf("GetPod: %v", err)
+	}
+	cond := api.GetPodCondition(pod, api.PodScheduledCondition)
+	want := "0/1 nodes are available: 1 node(s) were unschedulable."
+	if cond == nil || cond.Status != api.ConditionFalse || cond.Reason != api.PodReasonUnschedulable || cond.Message != want {
+		t.Fatalf("expected PodScheduled=False (Unschedulable) with message %q, got %+v", want, cond)
+	}
+	if pod.Phase != api.PodPending {
+		t.Errorf("expected the pod to stay Pending, got %s", pod.Phase)
+	}
+	events, err := client.ListEvents(DefaultNamespace)
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Reason != "FailedScheduling" || events[0].Message != want {
+		t.Errorf("expected one FailedScheduling event for two passes with the same reason, got %+v", events)
+	}
+
+	node, _ := client.GetNode("node1")
+	node.Unschedulable = false
+	if err := client.UpdateNode(node); err != nil {
+		t.Fatalf("UpdateNode: %v", err)
+	}
+	s.schedulePods()
+	pod, _ = client.GetPod(DefaultNamespace, "a")
+	if cond := api.GetPodCondition(pod, api.PodScheduledCondition); pod.NodeName != "node1" || cond == nil || cond.Status != api.ConditionTrue || cond.Reason != "" {
+		t.Errorf("expected the pod to be bound with PodScheduled=True once the node is schedulable, got %+v", pod)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	client := fake.NewClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady},
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node2"}, Status: api.NodeReady},
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node3"}, Status: api.NodeNotReady, Unschedulable: true},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "a", Namespace: DefaultNamespace}, NodeName: "node1", Phase: api.PodRunning},
+	)
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "b", Namespace: DefaultNamespace}, Phase: api.PodPending}
+
+	e, err := Explain(client, pod)
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if len(e.BestNodes) != 1 || e.BestNodes[0] != "node2" {
+		t.Errorf("expected the empty node to fit best, got %v", e.BestNodes)
+	}
+	if len(e.Nodes) != 3 || e.Nodes[0].Scores["LeastPods"] != 99 || e.Nodes[1].Scores["LeastPods"] != maxNodeScore {
+		t.Errorf("expected node1 to score 99 and node2 %d, got %+v", maxNodeScore, e.Nodes)
+	}
+	if failures := e.Nodes[2].Failures; len(failures) != 2 || e.Nodes[2].Scores != nil {
+		t.Errorf("expected node3 to fail both filters and go unscored, got %+v", e.Nodes[2])
+	}
+	if pod, _ := client.GetPod(DefaultNamespace, "a"); pod.NodeName != "node1" {
+		t.Errorf("Explain must not change any pod, got %+v", pod)
+	}
+
+	// With no feasible node, the message counts the reasons.
+	if err := client.DeleteNode("node1"); err != nil {
+		t.Fatalf("DeleteNode: %v", err)
+	}
+	if err := client.DeleteNode("node2"); err != nil {
+		t.Fatalf("DeleteNode: %v", err)
+	}
+	e, err = Explain(client, pod)
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if want := "0/1 nodes are available: 1 node(s) were not Ready, 1 node(s) were unschedulable."; e.Message != want {
+		t.Errorf("expected message %q, got %q", want, e.Message)
+	}
+}
+
+func TestNodePressure(t *testing.T) {
+	underPressure := func(name string, t api.NodeConditionType) *api.Node {
+		node := &api.Node{ObjectMeta: api.ObjectMeta{Name: name}}
+		api.SetNodeCondition(node, api.NodeCondition{Type: api.NodeReadyCondition, Status: api.ConditionTrue})
+		api.SetNodeCondition(node, api.NodeCondition{Type: t, Status: api.ConditionTrue})
+		return node
+	}
+	client := fake.NewClient(
+		underPressure("memory", api.NodeMemoryPressure),
+		underPressure("disk", api.NodeDiskPressure),
+		underPressure("pids", api.NodePIDPressure),
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "healthy"}, Status: api.NodeReady},
+	)
+	tests := []struct {
+		pod      *api.Pod
+		feasible []string
+	}{
+		{&api.Pod{ObjectMeta: api.ObjectMeta{Name: "best-effort"}}, []string{"healthy"}},
+		{&api.Pod{ObjectMeta: api.ObjectMeta{Name: "burstable"}, Resources: api.ResourceRequirements{Requests: api.ResourceList{"memory": "64Mi"}}}, []string{"healthy", "memory"}},
+	}
+	for _, tt := range tests {
+		e, err := Explain(client, tt.pod)
+		if err != nil {
+			t.Fatalf("Explain: %v", err)
+		}
+		var feasible []string
+		for _, n := range e.Nodes {
+			if len(n.Failures) == 0 {
+				feasible = append(feasible, n.Node)
+			} else if n.Failures[0].Plugin != "NodePressure" {
+				t.Errorf("%s: expected node %s to fail NodePressure, got %+v", tt.pod.Name, n.Node, n.Failures)
+			}
+		}
+		if !reflect.DeepEqual(feasible, tt.feasible) {
+			t.Errorf("%s: expected feasible nodes %v, got %v (%s)", tt.pod.Name, tt.feasible, feasible, e.Message)
+		}
+	}
+}
+
+func TestNodeResourcesFit(t *testing.T) {
+	node := func(name, cpu, memory, pods string) *api.Node {
+		return &api.Node{ObjectMeta: api.ObjectMeta{Name: name}, Status: api.NodeReady,
+			Allocatable: api.ResourceList{api.ResourceCPU: cpu, api.ResourceMemory: memory, api.ResourcePods: pods}}
+	}
+	requesting := func(name, node, cpu, memory string) *api.Pod {
+		return &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: DefaultNamespace}, NodeName: node, Phase: api.PodRunning,
+			Resources: api.ResourceRequirements{Requests: api.ResourceList{api.ResourceCPU: cpu, api.ResourceMemory: memory}}}
+	}
+	client := fake.NewClient(
+		node("small", "1", "1Gi", "110"),
+		node("big", "4", "8Gi", "110"),
+		node("full", "4", "8Gi", "1"),
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "unlimited"}, Status: api.NodeReady},
+		requesting("a", "small", "500m", "256Mi"),
+		requesting("b", "big", "3", "1Gi"),
+		requesting("c", "full", "100m", "64Mi"),
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "done", Namespace: DefaultNamespace}, NodeName: "small", Phase: api.PodSucceeded,
+			Resources: api.ResourceRequirements{Requests: api.ResourceList{api.ResourceCPU: "1"}}},
+	)
+	tests := []struct {
+		pod  *api.Pod
+		want map[string]string // Failing nodes and why
+	}{
+		{requesting("fits", "", "500m", "512Mi"), map[string]string{"full": "node(s) had too many pods"}},
+		{requesting("cpu", "", "1500m", "512Mi"), map[string]string{"small": "node(s) had insufficient cpu", "big": "node(s) had insufficient cpu", "full": "node(s) had too many pods"}},
+		{requesting("memory", "", "100m", "2Gi"), map[string]string{"small": "node(s) had insufficient memory", "full": "node(s) had too many pods"}},
+	}
+	for _, tt := range tests {
+		e, err := Explain(client, tt.pod)
+		if err != nil {
+			t.Fatalf("Explain: %v", err)
+		}
+		got := map[string]string{}
+		for _, n := range e.Nodes {
+			for _, f := range n.Failures {
+				got[n.Node] = f.Reason
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected failures %v, got %v", tt.pod.Name, tt.want, got)
+		}
+	}
+}
+
+func TestSchedulerCache(t *testing.T) {
+	c := newSchedulerCache()
+	c.setNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Allocatable: api.ResourceList{api.ResourceCPU: "2"}})
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: DefaultNamespace}, Phase: api.PodPending,
+		Resources: api.ResourceRequirements{Requests: api.ResourceList{api.ResourceCPU: "500m"}}, Overhead: api.ResourceList{api.ResourceCPU: "100m"}}
+	usage := func() (int, int64) {
+		info := c.snapshot()[0]
+		return info.pods, info.requested[api.ResourceCPU]
+	}
+
+	c.updatePod(pod)
+	if pods, cpu := usage(); pods != 0 || cpu != 0 {
+		t.Errorf("expected a pending pod not to count, got %d pods requesting %dm", pods, cpu)
+	}
+	bound := *pod
+	bound.NodeName, bound.Phase = "node1", api.PodScheduled
+	c.updatePod(&bound)
+	c.updatePod(&bound) // The watch reporting a binding the scheduler already counted
+	if pods, cpu := usage(); pods != 1 || cpu != 600 {
+		t.Errorf("expected the bound pod to count once with its overhead, got %d pods requesting %dm", pods, cpu)
+	}
+	snapshot := c.snapshot()
+	snapshot[0].add(resources{api.ResourceCPU: 1000})
+	if pods, _ := usage(); pods != 1 {
+		t.Errorf("expected changes to a snapshot to leave the cache alone, got %d pods", pods)
+	}
+
+	// A pod bound to a node the cache hasn't seen counts once it has.
+	other := bound
+	other.Name, other.NodeName = "api", "node2"
+	c.updatePod(&other)
+	c.setNode(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node2"}})
+	if infos := c.snapshot(); len(infos) != 2 || infos[1].pods != 1 {
+		t.Errorf("expected node2 to count the pod bound to it before it was seen, got %+v", infos)
+	}
+
+	failed := bound
+	failed.Phase = api.PodFailed
+	c.updatePod(&failed)
+	if pods, cpu := usage(); pods != 0 || cpu != 0 {
+		t.Errorf("expected a failed pod to stop counting, got %d pods requesting %dm", pods, cpu)
+	}
+	c.updatePod(&bound)
+	c.removePod(&bound)
+	if pods, _ := usage(); pods != 0 {
+		t.Errorf("expected a deleted pod to stop counting, got %d pods", pods)
+	}
+}
+
+func TestRunSchedulesOnEveryTick(t *testing.T) {
+	client := fake.NewClient(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady})
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), time.Minute, clk)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, "the scheduler to wait for its next tick", func() bool { return clk.Waiters() == 1 })
+
+	// A pod created between passes waits for the next tick, however long the interval.
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "a"}, Image: "nginx:1.25", Phase: api.PodPending}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if pod, _ := client.GetPod(DefaultNamespace, "a"); pod.NodeName != "" {
+		t.Fatalf("expected the pod to wait for the next pass, got it bound to %q", pod.NodeName)
+	}
+	clk.Step(time.Minute)
+	waitFor(t, "the pod to be scheduled", func() bool {
+		pod, err := client.GetPod(DefaultNamespace, "a")
+		return err == nil && pod.NodeName == "node1"
+	})
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
+
+func TestSetIntervalWhileRunning(t *testing.T) {
+	client := fake.NewClient(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady})
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), time.Hour, clk)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	waitFor(t, "the first pass", func() bool { return s.passes.Load() == 1 })
+
+	// The new interval starts with a pass of its own, rather than after the hour.
+	s.SetInterval(time.Second)
+	waitFor(t, "a pass on the new interval", func() bool { return s.passes.Load() == 2 && clk.Waiters() == 1 })
+	if _, err := client.CreatePod(DefaultNamespace, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "a"}, Image: "nginx:1.25", Phase: api.PodPending}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	clk.Step(time.Second)
+	waitFor(t, "the pod to be scheduled a second later", func() bool {
+		pod, err := client.GetPod(DefaultNamespace, "a")
+		return err == nil && pod.NodeName == "node1"
+	})
+}
+
+func TestRunBacksOffWhileAPIServerIsDown(t *testing.T) {
+	client := fake.NewClient(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady})
+	var down atomic.Bool
+	down.Store(true)
+	client.PrependReactor("list", "pods", func(fake.Action) (bool, interface{}, error) {
+		if down.Load() {
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(client, record.NewRecorder(client, api.EventSource{Component: "scheduler"}), time.Second, clk)
+	healthz := func() (int, string) {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	// Each failed pass waits twice as long as the one before, plus up to 20% jitter:
+	// 1s, then 2s, then 4s.
+	waitFor(t, "the first pass to fail", func() bool { return s.apiBackoff.Failures() == 1 && clk.Waiters() == 2 })
+	clk.Step(1200 * time.Millisecond)
+	waitFor(t, "the second pass to fail", func() bool { return s.apiBackoff.Failures() == 2 && clk.Waiters() == 2 })
+	clk.Step(1200 * time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if n := s.apiBackoff.Failures(); n != 2 {
+		t.Fatalf("expected the third pass to wait out a 2s back-off, got %d failures after 1.2s", n)
+	}
+	if code, _ := healthz(); code != http.StatusOK {
+		t.Errorf("expected /healthz to pass after only 2 failures, got %d", code)
+	}
+	clk.Step(1200 * time.Millisecond)
+	waitFor(t, "the third pass to fail", func() bool { return s.apiBackoff.Failures() == 3 })
+
+	if code, body := healthz(); code != http.StatusServiceUnavailable || !strings.Contains(body, "API server unreachable") {
+		t.Errorf("expected /healthz to fail after 3 failures, got %d: %q", code, body)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), "scheduler_api_consecutive_failures 3\n") {
+		t.Errorf("expected the failures in /metrics, got:\n%s", rec.Body.String())
+	}
+
+	down.Store(false)
+	clk.Step(5 * time.Second)
+	waitFor(t, "a pass to succeed", func() bool { return s.apiBackoff.Failures() == 0 && s.passes.Load() > 0 })
+	if code, _ := healthz(); code != http.StatusOK {
+		t.Errorf("expected /healthz to pass once the API server is back, got %d", code)
+	}
+}
+
+// waitFor polls until cond holds or fails the test after two seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// unhealthyPasses is how many scheduling passes may fail in a row for want of the API
+// server before the scheduler reports itself unhealthy.
+const unhealthyPasses = 3
+
+// Handler returns the HTTP handler serving the scheduler's health and metrics:
+//
+//	GET /healthz "ok", or 503 while the API server can't be reached
+//	GET /metrics pass counts and API back-off in the Prometheus text format
+func (s *Scheduler) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.healthzHandler)
+	mux.HandleFunc("GET /metrics", s.metricsHandler)
+	return mux
+}
+
+// Serve serves Handler on addr until ctx is cancelled. An address that can't be
+// listened on is logged rather than fatal, as the scheduler still schedules.
+func (s *Scheduler) Serve(ctx context.Context, addr string) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Printf("Not serving scheduler health and metrics: %v", err)
+		return
+	}
+	srv := &http.Server{Handler: s.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	log.Printf("Scheduler health and metrics listening on %s", ln.Addr())
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
+		log.Printf("Scheduler health and metrics stopped: %v", err)
+	}
+}
+
+func (s *Scheduler) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if failures := s.apiBackoff.Failures(); failures >= unhealthyPasses {
+		http.Error(w, fmt.Sprintf("API server unreachable: %d scheduling passes failed in a row, backing off %v", failures, s.apiBackoff.Delay()), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *Scheduler) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprintln(w, "# HELP scheduler_passes_total Number of scheduling passes that reached the API server.")
+	fmt.Fprintln(w, "# TYPE scheduler_passes_total counter")
+	fmt.Fprintf(w, "scheduler_passes_total %d\n", s.passes.Load())
+	fmt.Fprintln(w, "# HELP scheduler_api_consecutive_failures Number of scheduling passes in a row that failed to reach the API server.")
+	fmt.Fprintln(w, "# TYPE scheduler_api_consecutive_failures gauge")
+	fmt.Fprintf(w, "scheduler_api_consecutive_failures %d\n", s.apiBackoff.Failures())
+	fmt.Fprintln(w, "# HELP scheduler_api_backoff_seconds How long the scheduler waits before retrying a failed pass, 0 while passes succeed.")
+	fmt.Fprintln(w, "# TYPE scheduler_api_backoff_seconds gauge")
+	fmt.Fprintf(w, "scheduler_api_backoff_seconds %g\n", s.apiBackoff.Delay().Seconds())
+}
@@ -0,0 +1,16 @@
+package store
+
+import "errors"
+
+// Errors the store's methods wrap, so callers can tell failures apart with errors.Is
+// rather than by their text.
+var (
+	// ErrNotFound is returned for an object that doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned for creating an object that already exists.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict is returned for a write the stored object rules out: a stale
+	// resourceVersion, an illegal pod phase transition, or deleting a pod that is
+	// already being deleted. Rereading the object and retrying may succeed.
+	ErrConflict = errors.New("conflict")
+)
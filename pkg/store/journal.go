@@ -0,0 +1,222 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// ChangeType is the kind of write a Change records.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "ADDED"
+	ChangeModified ChangeType = "MODIFIED"
+	ChangeDeleted  ChangeType = "DELETED"
+)
+
+// Change is one entry of the store's journal: a single write to one object.
+type Change struct {
+	// Revision is the store's resourceVersion after the write. Every write gets its
+	// own, so changes are totally ordered by it. For ADDED and MODIFIED it is also the
+	// object's resourceVersion.
+	Revision uint64        `json:"revision"`
+	Type     ChangeType    `json:"type"`
+	Resource GroupResource `json:"resource"`
+	// Object is the object as written, or as it was when deleted, with the delete's
+	// revision as its resourceVersion. It is shared, so it must not be modified.
+	Object metaObject `json:"object"`
+}
+
+// metaObject is any stored object.
+type metaObject interface {
+	GetObjectMeta() *api.ObjectMeta
+}
+
+// ErrRevisionCompacted is returned by Changes for a revision older than the journal
+// still holds. A watcher that gets it must relist instead of resuming.
+var ErrRevisionCompacted = errors.New("revision has been compacted")
+
+// journalLength is the least number of recent changes the journal keeps in memory.
+const journalLength = 10000
+
+// journal is the append-only log of every change made to a store. It keeps the most
+// recent changes in memory for watchers, and, if it has a file, appends every change
+// to it first, so a store can be rebuilt from the file after a crash.
+type journal struct {
+	mu        sync.Mutex
+	changes   []Change      // At least the most recent journalLength changes, oldest first
+	compacted uint64        // Revision of the newest change dropped from changes, 0 if none
+	changed   chan struct{} // Closed, and replaced, whenever changes are appended
+	file      *os.File      // Nil for a journal kept only in memory
+}
+
+func newJournal() *journal {
+	return &journal{changed: make(chan struct{})}
+}
+
+// append adds changes to the journal, writing them to its file first if it has one.
+// If that fails, nothing is added.
+func (j *journal) append(changes ...Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file != nil {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, c := range changes {
+			if err := enc.Encode(c); err != nil {
+				return fmt.Errorf("encoding change %d to the journal: %w", c.Revision, err)
+			}
+		}
+		if _, err := j.file.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("writing to the journal: %w", err)
+		}
+	}
+	j.add(changes...)
+	return nil
+}
+
+// add adds changes to the in-memory journal. The caller must hold j.mu.
+func (j *journal) add(changes ...Change) {
+	j.changes = append(j.changes, changes...)
+	// Drop the oldest changes only once there are twice as many as the journal keeps,
+	// so that each change is copied once more at most rather than on every write.
+	if len(j.changes) >= 2*journalLength {
+		over := len(j.changes) - journalLength
+		j.compacted = j.changes[over-1].Revision
+		j.changes = append([]Change(nil), j.changes[over:]...)
+	}
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// since returns the changes made after revision, and a channel that is closed when
+// the next change is appended. It fails with ErrRevisionCompacted if changes after
+// revision have been dropped.
+func (j *journal) since(revision uint64) ([]Change, <-chan struct{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if revision < j.compacted {
+		return nil, nil, fmt.Errorf("changes since revision %d: %w (oldest available is %d)", revision, ErrRevisionCompacted, j.compacted)
+	}
+	i := sort.Search(len(j.changes), func(i int) bool { return j.changes[i].Revision > revision })
+	return append([]Change(nil), j.changes[i:]...), j.changed, nil
+}
+
+// Changes returns the writes made to the store after revision, oldest first, and a
+// channel that is closed when the next write is journaled: a watcher replays the
+// changes, waits on the channel, and asks again from the last revision it saw.
+// Revision 0 returns every change the journal still holds.
+func (s *InMemoryStore) Changes(revision uint64) ([]Change, <-chan struct{}, error) {
+	return s.journal.since(revision)
+}
+
+// Revision returns the resource version of the last write.
+func (s *InMemoryStore) Revision() uint64 {
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+	return s.resourceVersion
+}
+
+// record journals a write just made, at the store's current resource version. Inside
+// a transaction, changes are held back until it commits.
+func (s *InMemoryStore) record(t ChangeType, gr GroupResource, obj metaObject) error {
+	c := Change{Revision: s.resourceVersion, Type: t, Resource: gr, Object: obj}
+	if s.txn {
+		s.pending = append(s.pending, c)
+		return nil
+	}
+	return s.journal.append(c)
+}
+
+// OpenInMemoryStore returns an in-memory store whose journal is also written to the
+// file at path, creating it if need be. The objects journaled there before, by an
+// earlier store that may have crashed, are restored first. A last line cut short by
+// a crash is dropped. The file only grows; each write adds a line to it.
+func OpenInMemoryStore(path string) (*InMemoryStore, error) {
+	return OpenShardedInMemoryStore(path, 1)
+}
+
+// OpenShardedInMemoryStore is OpenInMemoryStore for a store with shards shards per
+// resource; see NewShardedInMemoryStore.
+func OpenShardedInMemoryStore(path string, shards int) (*InMemoryStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s := NewShardedInMemoryStore(shards)
+	end, err := s.replay(f)
+	if err == nil {
+		// Append after the last complete change, overwriting any partial one.
+		if err = f.Truncate(end); err == nil {
+			_, err = f.Seek(end, io.SeekStart)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("restoring the store from %s: %w", path, err)
+	}
+	s.journal.file = f
+	return s, nil
+}
+
+// Close closes the store's journal file, if it has one.
+func (s *InMemoryStore) Close() error {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	if s.journal.file == nil {
+		return nil
+	}
+	err := s.journal.file.Close()
+	s.journal.file = nil
+	return err
+}
+
+// replay applies the changes journaled in r to the store, and returns the offset just
+// past the last complete one.
+func (s *InMemoryStore) replay(r io.Reader) (int64, error) {
+	var end int64
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return end, nil // A partial last line is a write the crash interrupted
+		}
+		if err != nil {
+			return end, err
+		}
+		var c struct {
+			Revision uint64          `json:"revision"`
+			Type     ChangeType      `json:"type"`
+			Resource GroupResource   `json:"resource"`
+			Object   json.RawMessage `json:"object"`
+		}
+		if err := json.Unmarshal(line, &c); err != nil {
+			return end, fmt.Errorf("change at offset %d: %w", end, err)
+		}
+		r, ok := s.registries[c.Resource]
+		if !ok {
+			return end, fmt.Errorf("change %d: unknown resource %s", c.Revision, c.Resource)
+		}
+		obj, err := r.restore(c.Type, c.Object)
+		if err != nil {
+			return end, fmt.Errorf("change %d: %w", c.Revision, err)
+		}
+		s.resourceVersion = c.Revision
+		s.journal.add(Change{Revision: c.Revision, Type: c.Type, Resource: c.Resource, Object: obj})
+		end += int64(len(line))
+	}
+}
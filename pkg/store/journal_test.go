@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestChanges(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	_, changed, err := s.Changes(0)
+	if err != nil {
+		t.Fatalf("Changes: %v", err)
+	}
+	if err := s.CreateNode(ctx, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Errorf("expected the channel to be closed by a write")
+	}
+	if err := s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodPending}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	// Neither a failed transaction nor a failed write is journaled.
+	_ = s.Txn(ctx, func(tx StoreTxn) error {
+		_ = tx.DeleteNode(ctx, "node1")
+		return errors.New("abort")
+	})
+	_ = s.CreateNode(ctx, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}})
+	if err := s.Txn(ctx, func(tx StoreTxn) error {
+		if err := tx.DeletePod(ctx, "default", "web"); err != nil {
+			return err
+		}
+		return tx.DeleteNode(ctx, "node1")
+	}); err != nil {
+		t.Fatalf("Txn: %v", err)
+	}
+
+	changes, _, err := s.Changes(1)
+	if err != nil {
+		t.Fatalf("Changes: %v", err)
+	}
+	want := []struct {
+		typ      ChangeType
+		resource GroupResource
+		name     string
+	}{
+		{ChangeAdded, Pods, "web"},
+		{ChangeModified, Pods, "web"},
+		{ChangeDeleted, Nodes, "node1"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes after revision 1, got %+v", len(want), changes)
+	}
+	for i, c := range changes {
+		meta := c.Object.GetObjectMeta()
+		if c.Type != want[i].typ || c.Resource != want[i].resource || meta.Name != want[i].name {
+			t.Errorf("change %d: expected %s %s/%s, got %s %s/%s", i, want[i].typ, want[i].resource, want[i].name, c.Type, c.Resource, meta.Name)
+		}
+		if c.Revision != uint64(i+2) || meta.ResourceVersion != strconv.FormatUint(c.Revision, 10) {
+			t.Errorf("change %d: expected revision %d, got %d with resourceVersion %s", i, i+2, c.Revision, meta.ResourceVersion)
+		}
+	}
+
+	s.journal.compacted = 2
+	if _, _, err := s.Changes(1); !errors.Is(err, ErrRevisionCompacted) {
+		t.Errorf("expected ErrRevisionCompacted for a compacted revision, got %v", err)
+	}
+}
+
+func TestJournalCompaction(t *testing.T) {
+	j := newJournal()
+	for rev := uint64(1); rev <= 3*journalLength; rev++ {
+		j.add(Change{Revision: rev, Type: ChangeAdded, Resource: Nodes, Object: &api.Node{}})
+	}
+	if len(j.changes) < journalLength || len(j.changes) >= 2*journalLength {
+		t.Errorf("expected the journal to hold between %d and %d changes, got %d", journalLength, 2*journalLength, len(j.changes))
+	}
+	if _, _, err := j.since(0); !errors.Is(err, ErrRevisionCompacted) {
+		t.Errorf("expected the oldest changes to be compacted, got %v", err)
+	}
+	changes, _, err := j.since(2 * journalLength)
+	if err != nil || len(changes) != journalLength || changes[0].Revision != 2*journalLength+1 {
+		t.Errorf("expected the last %d changes to be kept, got %d: %v", journalLength, len(changes), err)
+	}
+}
+
+func TestOpenInMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+	s, err := OpenInMemoryStore(path)
+	if err != nil {
+		t.Fatalf("OpenInMemoryStore: %v", err)
+	}
+	for _, name := range []string{"web", "db"} {
+		if err := s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": name}}, Phase: api.PodPending}); err != nil {
+			t.Fatalf("CreatePod: %v", err)
+		}
+	}
+	if err := s.DeletePod(ctx, "default", "web"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	if err := s.CreateDeployment(ctx, &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Replicas: 2}); err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	if err := s.DeleteDeployment(ctx, "default", "web"); err != nil {
+		t.Fatalf("DeleteDeployment: %v", err)
+	}
+	want, _ := s.GetPod(ctx, "default", "db")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// A crash midway through writing a change leaves part of a line behind.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"revision":6,"type":"ADDED","resou`)
+	f.Close()
+
+	s, err = OpenInMemoryStore(path)
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	defer s.Close()
+	if got, err := s.GetPod(ctx, "default", "db"); err != nil || got.UID != want.UID || got.Labels["app"] != "db" || got.ResourceVersion != want.ResourceVersion {
+		t.Errorf("expected pod db to be restored as %+v, got %+v (%v)", want, got, err)
+	}
+	if got, err := s.GetPod(ctx, "default", "web"); err != nil || got.DeletionTimestamp == nil {
+		t.Errorf("expected pod web to be restored terminating, got %+v (%v)", got, err)
+	}
+	if _, err := s.GetDeployment(ctx, "default", "web"); err == nil {
+		t.Errorf("expected the deleted deployment to stay deleted")
+	}
+	if changes, _, _ := s.Changes(0); len(changes) != 5 {
+		t.Errorf("expected the 5 journaled changes to be restored, got %d", len(changes))
+	}
+	// New writes carry on from the restored revision, after the partial line.
+	if err := s.CreateNode(ctx, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	if node, _ := s.GetNode(ctx, "node1"); node.ResourceVersion != "6" {
+		t.Errorf("expected resourceVersion 6 after restoring 5 changes, got %s", node.ResourceVersion)
+	}
+	s.Close()
+	s, err = OpenInMemoryStore(path)
+	if err != nil {
+		t.Fatalf("reopening after the partial line: %v", err)
+	}
+	defer s.Close()
+	if _, err := s.GetNode(ctx, "node1"); err != nil {
+		t.Errorf("expected the node written after recovery to be restored: %v", err)
+	}
+}
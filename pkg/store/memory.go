@@ -1,93 +1,152 @@
 package store
 
 import (
+	"context"
+	"crypto/rand"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apis/validation"
 )
 
 // InMemoryStore is an in-memory implementation of the Store interface.
 // It is primarily for testing and simplicity, not for production use.
+// Objects are deep-copied going in and coming out, so callers can change what they
+// read or wrote without touching the stored objects; only writes change those.
 type InMemoryStore struct {
-	mu    sync.RWMutex
-	pods  map[string]*api.Pod  // Key: "namespace/name"
-	nodes map[string]*api.Node // Key: "name"
+	mu         rwLocker                   // Guards every object, unless the store is sharded
+	shards     int                        // Shards per registry; 1 unless the store is sharded
+	registries map[GroupResource]registry // One per resource; see newRegistries
+	locks      []rwLocker                 // Every registry's shard locks, in the order Txn takes them
+
+	// commitMu is held by every write, inside the lock of the object's shard, from
+	// giving it a resource version to journaling it, so that the journal stays in
+	// resource version order however many shards are written at once.
+	commitMu        sync.Locker
+	resourceVersion uint64 // Bumped on every write, across all object types
+	journal         *journal
+
+	// undo holds, inside a transaction, how to reverse each write made so far, and
+	// pending the changes to journal when it commits.
+	undo    []func()
+	pending []Change
+	txn     bool
 }
 
-// NewInMemoryStore creates a new InMemoryStore.
+// NewInMemoryStore creates a new InMemoryStore, whose objects are all guarded by one
+// lock.
 func NewInMemoryStore() *InMemoryStore {
-	return &InMemoryStore{
-		pods:  make(map[string]*api.Pod),
-		nodes: make(map[string]*api.Node),
+	return NewShardedInMemoryStore(1)
+}
+
+// NewShardedInMemoryStore creates a new InMemoryStore that splits the objects of each
+// resource into shards, by the hash of their names, each with its own lock. Reads and
+// writes of different shards don't wait for each other, except that writes still
+// take turns journaling; lists and transactions take every shard's lock in turn. One
+// shard is NewInMemoryStore.
+func NewShardedInMemoryStore(shards int) *InMemoryStore {
+	s := &InMemoryStore{mu: &sync.RWMutex{}, shards: max(shards, 1), commitMu: &sync.Mutex{}, journal: newJournal()}
+	s.registries = newRegistries(s)
+	if s.shards == 1 {
+		s.locks = []rwLocker{s.mu}
+		return s
+	}
+	resources := make([]GroupResource, 0, len(s.registries))
+	for gr := range s.registries {
+		resources = append(resources, gr)
 	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].String() < resources[j].String() })
+	for _, gr := range resources {
+		s.locks = append(s.locks, s.registries[gr].locks()...)
+	}
+	return s
+}
+
+func (s *InMemoryStore) registry(gr GroupResource) registry {
+	return s.registries[gr]
 }
 
 func podKey(namespace, name string) string {
 	return fmt.Sprintf("%s/%s", namespace, name)
 }
 
-// CreatePod adds a new pod to the store.
-func (s *InMemoryStore) CreatePod(pod *api.Pod) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// nextResourceVersion returns a new, store-wide unique resource version. The caller
+// must hold s.commitMu.
+func (s *InMemoryStore) nextResourceVersion() string {
+	s.resourceVersion++
+	return strconv.FormatUint(s.resourceVersion, 10)
+}
 
-	key := podKey(pod.Namespace, pod.Name)
-	if _, exists := s.pods[key]; exists {
-		return fmt.Errorf("pod %s in namespace %s already exists", pod.Name, pod.Namespace)
-	}
-	s.pods[key] = pod
-	return nil
+// initMeta sets the server-owned metadata of an object being created, ignoring
+// whatever the client sent.
+func (s *InMemoryStore) initMeta(meta *api.ObjectMeta) {
+	meta.UID = newUID()
+	meta.CreationTimestamp = time.Now().UTC()
+	meta.ResourceVersion = s.nextResourceVersion()
 }
 
-// GetPod retrieves a pod from the store.
-func (s *InMemoryStore) GetPod(namespace, name string) (*api.Pod, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// updateMeta carries the immutable metadata of existing over to an object replacing
+// it, and gives the replacement a new resource version. Once deletion has begun it
+// can't be undone, so an existing DeletionTimestamp is kept too.
+func (s *InMemoryStore) updateMeta(meta, existing *api.ObjectMeta) {
+	meta.UID = existing.UID
+	meta.CreationTimestamp = existing.CreationTimestamp
+	if existing.DeletionTimestamp != nil {
+		meta.DeletionTimestamp = existing.DeletionTimestamp
+	}
+	meta.ResourceVersion = s.nextResourceVersion()
+}
 
-	key := podKey(namespace, name)
-	pod, exists := s.pods[key]
-	if !exists {
-		return nil, fmt.Errorf("pod %s in namespace %s not found", name, namespace)
+// newUID returns a random RFC 4122 version 4 UUID.
+func newUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("store: reading random bytes for UID: %v", err))
 	}
-	return pod, nil
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
-// UpdatePod updates an existing pod in the store.
-// It prevents updates to NodeName or Phase if the pod is already marked for deletion,
-// but allows Kubelet to update phase to Succeeded/Failed.
-func (s *InMemoryStore) UpdatePod(pod *api.Pod) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// CreatePod adds a new pod to the store.
+func (s *InMemoryStore) CreatePod(ctx context.Context, pod *api.Pod) error {
+	return RegistryFor[*api.Pod](s, Pods).Create(ctx, pod)
+}
 
-	key := podKey(pod.Namespace, pod.Name)
-	existingPod, exists := s.pods[key]
-	if !exists {
-		return fmt.Errorf("pod %s in namespace %s not found for update", pod.Name, pod.Namespace)
-	}
+// GetPod retrieves a pod from the store.
+func (s *InMemoryStore) GetPod(ctx context.Context, namespace, name string) (*api.Pod, error) {
+	return RegistryFor[*api.Pod](s, Pods).Get(ctx, namespace, name)
+}
 
-	if existingPod.DeletionTimestamp != nil {
-		// Pod is already marked for deletion in the store.
+// UpdatePod updates an existing pod in the store, subject to ValidatePodUpdate.
+func (s *InMemoryStore) UpdatePod(ctx context.Context, pod *api.Pod) error {
+	return RegistryFor[*api.Pod](s, Pods).Update(ctx, pod)
+}
 
+// ValidatePodUpdate checks whether pod may replace existingPod; UpdatePod enforces it.
+// It rejects phase changes the pod phase state machine doesn't allow; once a pod is
+// marked for deletion, that leaves only finishing it (Succeeded, Failed, or Deleted).
+// It also prevents a terminating pod from moving to another node. Those errors wrap
+// ErrConflict: the update was made against an older copy of the pod.
+func ValidatePodUpdate(existingPod, pod *api.Pod) error {
+	terminating := existingPod.DeletionTimestamp != nil
+	if err := validation.ValidatePodPhaseTransition(existingPod.Phase, pod.Phase, terminating); err != nil {
+		return fmt.Errorf("%w: cannot update pod %s in namespace %s: %w", ErrConflict, pod.Name, pod.Namespace, err)
+	}
+	if terminating {
 		// Ensure the incoming update acknowledges the existing DeletionTimestamp.
 		// This prevents a stale update from before deletion was initiated from overwriting it.
 		if pod.DeletionTimestamp == nil || !pod.DeletionTimestamp.Equal(*existingPod.DeletionTimestamp) {
-			return fmt.Errorf("cannot update pod %s in namespace %s: incoming update does not have matching DeletionTimestamp for an already terminating pod", pod.Name, pod.Namespace)
+			return fmt.Errorf("%w: cannot update pod %s in namespace %s: incoming update does not have matching DeletionTimestamp for an already terminating pod", ErrConflict, pod.Name, pod.Namespace)
 		}
-
-		// Allow updates to phase to Succeeded or Failed, or if phase is still Terminating (e.g. Kubelet updating other statuses).
-		// Also, ensure NodeName does not change during termination.
-		if pod.Phase == api.PodSucceeded || pod.Phase == api.PodFailed || pod.Phase == api.PodTerminating || pod.Phase == api.PodDeleted { // ADD PodDeleted HERE
-			if pod.NodeName != existingPod.NodeName {
-				return fmt.Errorf("cannot change NodeName of pod %s in namespace %s as it is terminating", pod.Name, pod.Namespace)
-			}
-			s.pods[key] = pod
-			return nil
+		if pod.NodeName != existingPod.NodeName {
+			return fmt.Errorf("%w: cannot change NodeName of pod %s in namespace %s as it is terminating", ErrConflict, pod.Name, pod.Namespace)
 		}
-
-		// If it's terminating and the update tries to set it to something other than Succeeded, Failed, or Terminating
-		return fmt.Errorf("cannot update pod %s in namespace %s to phase %s as it is terminating; only Succeeded, Failed, or Terminating are allowed", pod.Name, pod.Namespace, pod.Phase)
+		return nil
 	}
 
 	// If the existing pod is NOT terminating, but the update tries to set a DeletionTimestamp,
@@ -95,108 +154,88 @@ func (s *InMemoryStore) UpdatePod(pod *api.Pod) error {
 	if pod.DeletionTimestamp != nil && existingPod.DeletionTimestamp == nil {
 		return fmt.Errorf("to mark pod %s in namespace %s for deletion, use DeletePod method", pod.Name, pod.Namespace)
 	}
-
-	// Standard update for non-terminating pods
-	s.pods[key] = pod
 	return nil
 }
 
-// DeletePod marks a pod for deletion by setting its DeletionTimestamp and Phase.
-// It does not immediately remove the pod from the store.
-func (s *InMemoryStore) DeletePod(namespace, name string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// preparePod keeps the history of a pod's phase: clients can't write it, but each
+// change of phase adds to it.
+func preparePod(existing, pod *api.Pod) {
+	pod.PhaseTransitions = nil
+	if existing != nil {
+		pod.PhaseTransitions = existing.PhaseTransitions
+	}
+	api.RecordPodPhase(pod, time.Now())
+}
 
+// DeletePod marks a pod for deletion by setting its DeletionTimestamp and its Ready
+// condition to False. It does not remove the pod from the store: the pod's kubelet
+// stops it and moves it to Deleted. A pod that was never bound to a node has nothing
+// to reclaim, so it is Deleted at once.
+func (s *InMemoryStore) DeletePod(ctx context.Context, namespace, name string) error {
 	key := podKey(namespace, name)
-	pod, exists := s.pods[key]
+	sh := RegistryFor[*api.Pod](s, Pods).shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pod, exists := sh.objects[key]
 	if !exists {
-		return fmt.Errorf("pod %s in namespace %s not found for deletion", name, namespace)
+		return fmt.Errorf("pod %s in namespace %s %w for deletion", name, namespace, ErrNotFound)
 	}
 
 	if pod.DeletionTimestamp != nil {
-		// Already marked for deletion, could return a specific error or just succeed
-		return fmt.Errorf("pod %s in namespace %s is already being deleted", name, namespace)
+		return fmt.Errorf("%w: pod %s in namespace %s is already being deleted", ErrConflict, name, namespace)
 	}
 
+	// Replace the stored pod rather than change it, so a transaction can undo this.
+	deleted := pod.DeepCopy()
 	now := time.Now()
-	pod.DeletionTimestamp = &now
-	pod.Phase = api.PodTerminating // Set phase to Terminating
-	s.pods[key] = pod              // Update the pod in the store with new phase and timestamp
+	deleted.DeletionTimestamp = &now
+	api.SetPodCondition(deleted, api.PodCondition{Type: api.PodReadyCondition, Status: api.ConditionFalse, Reason: "Terminating", Message: "The pod is being deleted"})
+	if deleted.NodeName == "" {
+		deleted.Phase = api.PodDeleted
+		api.RecordPodPhase(deleted, now)
+	}
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+	deleted.ResourceVersion = s.nextResourceVersion()
+	if err := s.record(ChangeModified, Pods, deleted); err != nil {
+		return err
+	}
+	put(s, sh.objects, key, deleted)
 
 	return nil
 }
 
 // ListPods retrieves all pods in a given namespace.
-// If namespace is empty, it could be interpreted as list all pods across all namespaces (not implemented here for simplicity yet).
-func (s *InMemoryStore) ListPods(namespace string) ([]*api.Pod, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	var result []*api.Pod
-	for _, pod := range s.pods {
-		if pod.Namespace == namespace {
-			result = append(result, pod)
-		}
-	}
-	return result, nil
+// If namespace is empty (api.NamespaceAll), it lists pods across all namespaces.
+func (s *InMemoryStore) ListPods(ctx context.Context, namespace string) ([]*api.Pod, error) {
+	return RegistryFor[*api.Pod](s, Pods).List(ctx, namespace)
 }
 
 // CreateNode adds a new node to the store.
-func (s *InMemoryStore) CreateNode(node *api.Node) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if _, exists := s.nodes[node.Name]; exists {
-		return fmt.Errorf("node %s already exists", node.Name)
-	}
-	s.nodes[node.Name] = node
-	return nil
+func (s *InMemoryStore) CreateNode(ctx context.Context, node *api.Node) error {
+	return RegistryFor[*api.Node](s, Nodes).Create(ctx, node)
 }
 
 // GetNode retrieves a node from the store.
-func (s *InMemoryStore) GetNode(name string) (*api.Node, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	node, exists := s.nodes[name]
-	if !exists {
-		return nil, fmt.Errorf("node %s not found", name)
-	}
-	return node, nil
+func (s *InMemoryStore) GetNode(ctx context.Context, name string) (*api.Node, error) {
+	return RegistryFor[*api.Node](s, Nodes).Get(ctx, "", name)
 }
 
 // UpdateNode updates an existing node in the store.
-func (s *InMemoryStore) UpdateNode(node *api.Node) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if _, exists := s.nodes[node.Name]; !exists {
-		return fmt.Errorf("node %s not found for update", node.Name)
-	}
-	s.nodes[node.Name] = node
-	return nil
+func (s *InMemoryStore) UpdateNode(ctx context.Context, node *api.Node) error {
+	return RegistryFor[*api.Node](s, Nodes).Update(ctx, node)
 }
 
 // DeleteNode removes a node from the store.
-func (s *InMemoryStore) DeleteNode(name string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if _, exists := s.nodes[name]; !exists {
-		return fmt.Errorf("node %s not found for deletion", name)
-	}
-	delete(s.nodes, name)
-	return nil
+func (s *InMemoryStore) DeleteNode(ctx context.Context, name string) error {
+	return RegistryFor[*api.Node](s, Nodes).Delete(ctx, "", name)
 }
 
 // ListNodes retrieves all nodes.
-func (s *InMemoryStore) ListNodes() ([]*api.Node, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	var result []*api.Node
-	for _, node := range s.nodes {
-		result = append(result, node)
-	}
-	return result, nil
+func (s *InMemoryStore) ListNodes(ctx context.Context) ([]*api.Node, error) {
+	return RegistryFor[*api.Node](s, Nodes).List(ctx, api.NamespaceAll)
 }
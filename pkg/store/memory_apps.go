@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// CreateNamespace adds a new namespace to the store.
+func (s *InMemoryStore) CreateNamespace(ctx context.Context, ns *api.Namespace) error {
+	return RegistryFor[*api.Namespace](s, Namespaces).Create(ctx, ns)
+}
+
+// GetNamespace retrieves a namespace from the store.
+func (s *InMemoryStore) GetNamespace(ctx context.Context, name string) (*api.Namespace, error) {
+	return RegistryFor[*api.Namespace](s, Namespaces).Get(ctx, "", name)
+}
+
+// UpdateNamespace updates an existing namespace in the store.
+func (s *InMemoryStore) UpdateNamespace(ctx context.Context, ns *api.Namespace) error {
+	return RegistryFor[*api.Namespace](s, Namespaces).Update(ctx, ns)
+}
+
+// DeleteNamespace removes a namespace from the store. Objects inside it are left alone.
+func (s *InMemoryStore) DeleteNamespace(ctx context.Context, name string) error {
+	return RegistryFor[*api.Namespace](s, Namespaces).Delete(ctx, "", name)
+}
+
+// ListNamespaces retrieves all namespaces.
+func (s *InMemoryStore) ListNamespaces(ctx context.Context) ([]*api.Namespace, error) {
+	return RegistryFor[*api.Namespace](s, Namespaces).List(ctx, api.NamespaceAll)
+}
+
+// CreateDeployment adds a new deployment to the store.
+func (s *InMemoryStore) CreateDeployment(ctx context.Context, d *api.Deployment) error {
+	return RegistryFor[*api.Deployment](s, Deployments).Create(ctx, d)
+}
+
+// GetDeployment retrieves a deployment from the store.
+func (s *InMemoryStore) GetDeployment(ctx context.Context, namespace, name string) (*api.Deployment, error) {
+	return RegistryFor[*api.Deployment](s, Deployments).Get(ctx, namespace, name)
+}
+
+// UpdateDeployment updates an existing deployment in the store.
+func (s *InMemoryStore) UpdateDeployment(ctx context.Context, d *api.Deployment) error {
+	return RegistryFor[*api.Deployment](s, Deployments).Update(ctx, d)
+}
+
+// DeleteDeployment removes a deployment from the store.
+func (s *InMemoryStore) DeleteDeployment(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.Deployment](s, Deployments).Delete(ctx, namespace, name)
+}
+
+// ListDeployments retrieves the deployments in a given namespace, or in every
+// namespace for api.NamespaceAll.
+func (s *InMemoryStore) ListDeployments(ctx context.Context, namespace string) ([]*api.Deployment, error) {
+	return RegistryFor[*api.Deployment](s, Deployments).List(ctx, namespace)
+}
+
+// CreateService adds a new service to the store.
+func (s *InMemoryStore) CreateService(ctx context.Context, svc *api.Service) error {
+	return RegistryFor[*api.Service](s, Services).Create(ctx, svc)
+}
+
+// GetService retrieves a service from the store.
+func (s *InMemoryStore) GetService(ctx context.Context, namespace, name string) (*api.Service, error) {
+	return RegistryFor[*api.Service](s, Services).Get(ctx, namespace, name)
+}
+
+// UpdateService updates an existing service in the store.
+func (s *InMemoryStore) UpdateService(ctx context.Context, svc *api.Service) error {
+	return RegistryFor[*api.Service](s, Services).Update(ctx, svc)
+}
+
+// DeleteService removes a service from the store.
+func (s *InMemoryStore) DeleteService(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.Service](s, Services).Delete(ctx, namespace, name)
+}
+
+// ListServices retrieves the services in a given namespace, or in every namespace for
+// api.NamespaceAll.
+func (s *InMemoryStore) ListServices(ctx context.Context, namespace string) ([]*api.Service, error) {
+	return RegistryFor[*api.Service](s, Services).List(ctx, namespace)
+}
+
+// CreateEvent adds a new event to the store.
+func (s *InMemoryStore) CreateEvent(ctx context.Context, event *api.Event) error {
+	return RegistryFor[*api.Event](s, Events).Create(ctx, event)
+}
+
+// UpdateEvent updates an existing event in the store.
+func (s *InMemoryStore) UpdateEvent(ctx context.Context, event *api.Event) error {
+	return RegistryFor[*api.Event](s, Events).Update(ctx, event)
+}
+
+// ListEvents retrieves the events in a given namespace, or in every namespace for
+// api.NamespaceAll.
+func (s *InMemoryStore) ListEvents(ctx context.Context, namespace string) ([]*api.Event, error) {
+	return RegistryFor[*api.Event](s, Events).List(ctx, namespace)
+}
+
+// CreatePodDisruptionBudget adds a new pod disruption budget to the store.
+func (s *InMemoryStore) CreatePodDisruptionBudget(ctx context.Context, pdb *api.PodDisruptionBudget) error {
+	return RegistryFor[*api.PodDisruptionBudget](s, PodDisruptionBudgets).Create(ctx, pdb)
+}
+
+// GetPodDisruptionBudget retrieves a pod disruption budget from the store.
+func (s *InMemoryStore) GetPodDisruptionBudget(ctx context.Context, namespace, name string) (*api.PodDisruptionBudget, error) {
+	return RegistryFor[*api.PodDisruptionBudget](s, PodDisruptionBudgets).Get(ctx, namespace, name)
+}
+
+// UpdatePodDisruptionBudget updates an existing pod disruption budget in the store.
+func (s *InMemoryStore) UpdatePodDisruptionBudget(ctx context.Context, pdb *api.PodDisruptionBudget) error {
+	return RegistryFor[*api.PodDisruptionBudget](s, PodDisruptionBudgets).Update(ctx, pdb)
+}
+
+// DeletePodDisruptionBudget removes a pod disruption budget from the store.
+func (s *InMemoryStore) DeletePodDisruptionBudget(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.PodDisruptionBudget](s, PodDisruptionBudgets).Delete(ctx, namespace, name)
+}
+
+// ListPodDisruptionBudgets retrieves the pod disruption budgets in a given namespace,
+// or in every namespace for api.NamespaceAll.
+func (s *InMemoryStore) ListPodDisruptionBudgets(ctx context.Context, namespace string) ([]*api.PodDisruptionBudget, error) {
+	return RegistryFor[*api.PodDisruptionBudget](s, PodDisruptionBudgets).List(ctx, namespace)
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// benchPods and benchNamespaces size the store the benchmarks run against: the pod
+// count the scale-testing tool reaches, spread over namespaces of 100 pods.
+const (
+	benchPods       = 20000
+	benchNamespaces = 200
+)
+
+// benchStores are the store configurations each benchmark runs against.
+var benchStores = []struct {
+	name string
+	new  func() *InMemoryStore
+}{
+	{"global", NewInMemoryStore},
+	{"sharded", func() *InMemoryStore { return NewShardedInMemoryStore(64) }},
+}
+
+// newBenchStore returns a store holding benchPods pending pods and a node.
+func newBenchStore(b *testing.B, newStore func() *InMemoryStore) *InMemoryStore {
+	b.Helper()
+	ctx := context.Background()
+	s := newStore()
+	for i := 0; i < benchPods; i++ {
+		pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: benchNamespace(i)}, Image: "nginx", Phase: api.PodPending}
+		if err := s.CreatePod(ctx, pod); err != nil {
+			b.Fatalf("CreatePod: %v", err)
+		}
+	}
+	if err := s.CreateNode(ctx, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeReady}); err != nil {
+		b.Fatalf("CreateNode: %v", err)
+	}
+	return s
+}
+
+func benchNamespace(i int) string {
+	return fmt.Sprintf("ns-%d", i%benchNamespaces)
+}
+
+// runParallel runs op on every store configuration, from GOMAXPROCS goroutines each
+// with its own source of randomness.
+func runParallel(b *testing.B, op func(s *InMemoryStore, rng *rand.Rand) error) {
+	for _, bs := range benchStores {
+		b.Run(bs.name, func(b *testing.B) {
+			s := newBenchStore(b, bs.new)
+			var seed atomic.Int64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				rng := rand.New(rand.NewSource(seed.Add(1)))
+				for pb.Next() {
+					if err := op(s, rng); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGetPod(b *testing.B) {
+	runParallel(b, func(s *InMemoryStore, rng *rand.Rand) error {
+		i := rng.Intn(benchPods)
+		_, err := s.GetPod(context.Background(), benchNamespace(i), fmt.Sprintf("pod-%d", i))
+		return err
+	})
+}
+
+func BenchmarkUpdatePod(b *testing.B) {
+	runParallel(b, func(s *InMemoryStore, rng *rand.Rand) error {
+		return touchPod(s, rng)
+	})
+}
+
+// BenchmarkMixed is the load of a busy cluster: mostly pod reads, then pod status
+// updates, node heartbeats, and now and then a list of a namespace's pods.
+func BenchmarkMixed(b *testing.B) {
+	runParallel(b, func(s *InMemoryStore, rng *rand.Rand) error {
+		ctx := context.Background()
+		switch n := rng.Intn(100); {
+		case n < 70:
+			i := rng.Intn(benchPods)
+			_, err := s.GetPod(ctx, benchNamespace(i), fmt.Sprintf("pod-%d", i))
+			return err
+		case n < 90:
+			return touchPod(s, rng)
+		case n < 99:
+			node, err := s.GetNode(ctx, "node1")
+			if err != nil {
+				return err
+			}
+			return s.UpdateNode(ctx, node)
+		default:
+			_, err := s.ListPods(ctx, benchNamespace(rng.Intn(benchNamespaces)))
+			return err
+		}
+	})
+}
+
+// touchPod rewrites a random pod, as a kubelet reporting its status does.
+func touchPod(s *InMemoryStore, rng *rand.Rand) error {
+	ctx := context.Background()
+	i := rng.Intn(benchPods)
+	pod, err := s.GetPod(ctx, benchNamespace(i), fmt.Sprintf("pod-%d", i))
+	if err != nil {
+		return err
+	}
+	pod.Annotations = map[string]string{"sync": strconv.Itoa(rng.Int())}
+	return s.UpdatePod(ctx, pod)
+}
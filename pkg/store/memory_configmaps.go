@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// CreateConfigMap adds a new config map to the store.
+func (s *InMemoryStore) CreateConfigMap(ctx context.Context, configMap *api.ConfigMap) error {
+	return RegistryFor[*api.ConfigMap](s, ConfigMaps).Create(ctx, configMap)
+}
+
+// GetConfigMap retrieves a config map from the store.
+func (s *InMemoryStore) GetConfigMap(ctx context.Context, namespace, name string) (*api.ConfigMap, error) {
+	return RegistryFor[*api.ConfigMap](s, ConfigMaps).Get(ctx, namespace, name)
+}
+
+// UpdateConfigMap replaces an existing config map.
+func (s *InMemoryStore) UpdateConfigMap(ctx context.Context, configMap *api.ConfigMap) error {
+	return RegistryFor[*api.ConfigMap](s, ConfigMaps).Update(ctx, configMap)
+}
+
+// DeleteConfigMap removes a config map from the store.
+func (s *InMemoryStore) DeleteConfigMap(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.ConfigMap](s, ConfigMaps).Delete(ctx, namespace, name)
+}
+
+// ListConfigMaps retrieves the config maps in a given namespace.
+func (s *InMemoryStore) ListConfigMaps(ctx context.Context, namespace string) ([]*api.ConfigMap, error) {
+	return RegistryFor[*api.ConfigMap](s, ConfigMaps).List(ctx, namespace)
+}
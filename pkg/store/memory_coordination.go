@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// CreateLease adds a new lease to the store.
+func (s *InMemoryStore) CreateLease(ctx context.Context, lease *api.Lease) error {
+	return RegistryFor[*api.Lease](s, Leases).Create(ctx, lease)
+}
+
+// GetLease retrieves a lease from the store.
+func (s *InMemoryStore) GetLease(ctx context.Context, namespace, name string) (*api.Lease, error) {
+	return RegistryFor[*api.Lease](s, Leases).Get(ctx, namespace, name)
+}
+
+// UpdateLease replaces an existing lease, provided lease.ResourceVersion is the one
+// stored. Unlike other objects, leases can't be updated blindly: an empty
+// resourceVersion is a conflict too.
+func (s *InMemoryStore) UpdateLease(ctx context.Context, lease *api.Lease) error {
+	return RegistryFor[*api.Lease](s, Leases).Update(ctx, lease)
+}
+
+func validateLeaseUpdate(existing, lease *api.Lease) error {
+	if lease.ResourceVersion != existing.ResourceVersion {
+		return fmt.Errorf("%w: lease %s in namespace %s has resourceVersion %s, not %q", ErrConflict, lease.Name, lease.Namespace, existing.ResourceVersion, lease.ResourceVersion)
+	}
+	return nil
+}
+
+// DeleteLease removes a lease from the store.
+func (s *InMemoryStore) DeleteLease(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.Lease](s, Leases).Delete(ctx, namespace, name)
+}
+
+// ListLeases retrieves the leases in a given namespace, or in every namespace for
+// api.NamespaceAll.
+func (s *InMemoryStore) ListLeases(ctx context.Context, namespace string) ([]*api.Lease, error) {
+	return RegistryFor[*api.Lease](s, Leases).List(ctx, namespace)
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// CreateNetworkPolicy adds a new network policy to the store.
+func (s *InMemoryStore) CreateNetworkPolicy(ctx context.Context, policy *api.NetworkPolicy) error {
+	return RegistryFor[*api.NetworkPolicy](s, NetworkPolicies).Create(ctx, policy)
+}
+
+// GetNetworkPolicy retrieves a network policy from the store.
+func (s *InMemoryStore) GetNetworkPolicy(ctx context.Context, namespace, name string) (*api.NetworkPolicy, error) {
+	return RegistryFor[*api.NetworkPolicy](s, NetworkPolicies).Get(ctx, namespace, name)
+}
+
+// UpdateNetworkPolicy replaces an existing network policy.
+func (s *InMemoryStore) UpdateNetworkPolicy(ctx context.Context, policy *api.NetworkPolicy) error {
+	return RegistryFor[*api.NetworkPolicy](s, NetworkPolicies).Update(ctx, policy)
+}
+
+// DeleteNetworkPolicy removes a network policy from the store.
+func (s *InMemoryStore) DeleteNetworkPolicy(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.NetworkPolicy](s, NetworkPolicies).Delete(ctx, namespace, name)
+}
+
+// ListNetworkPolicies retrieves the network policies in a given namespace.
+func (s *InMemoryStore) ListNetworkPolicies(ctx context.Context, namespace string) ([]*api.NetworkPolicy, error) {
+	return RegistryFor[*api.NetworkPolicy](s, NetworkPolicies).List(ctx, namespace)
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// CreateSecret adds a new secret to the store.
+func (s *InMemoryStore) CreateSecret(ctx context.Context, secret *api.Secret) error {
+	return RegistryFor[*api.Secret](s, Secrets).Create(ctx, secret)
+}
+
+// GetSecret retrieves a secret from the store.
+func (s *InMemoryStore) GetSecret(ctx context.Context, namespace, name string) (*api.Secret, error) {
+	return RegistryFor[*api.Secret](s, Secrets).Get(ctx, namespace, name)
+}
+
+// UpdateSecret replaces an existing secret.
+func (s *InMemoryStore) UpdateSecret(ctx context.Context, secret *api.Secret) error {
+	return RegistryFor[*api.Secret](s, Secrets).Update(ctx, secret)
+}
+
+// DeleteSecret removes a secret from the store.
+func (s *InMemoryStore) DeleteSecret(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.Secret](s, Secrets).Delete(ctx, namespace, name)
+}
+
+// ListSecrets retrieves the secrets in a given namespace.
+func (s *InMemoryStore) ListSecrets(ctx context.Context, namespace string) ([]*api.Secret, error) {
+	return RegistryFor[*api.Secret](s, Secrets).List(ctx, namespace)
+}
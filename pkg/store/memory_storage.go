@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// CreatePersistentVolume adds a new persistent volume to the store.
+func (s *InMemoryStore) CreatePersistentVolume(ctx context.Context, pv *api.PersistentVolume) error {
+	return RegistryFor[*api.PersistentVolume](s, PersistentVolumes).Create(ctx, pv)
+}
+
+// GetPersistentVolume retrieves a persistent volume from the store.
+func (s *InMemoryStore) GetPersistentVolume(ctx context.Context, name string) (*api.PersistentVolume, error) {
+	return RegistryFor[*api.PersistentVolume](s, PersistentVolumes).Get(ctx, "", name)
+}
+
+// UpdatePersistentVolume updates an existing persistent volume in the store.
+func (s *InMemoryStore) UpdatePersistentVolume(ctx context.Context, pv *api.PersistentVolume) error {
+	return RegistryFor[*api.PersistentVolume](s, PersistentVolumes).Update(ctx, pv)
+}
+
+// DeletePersistentVolume removes a persistent volume from the store.
+func (s *InMemoryStore) DeletePersistentVolume(ctx context.Context, name string) error {
+	return RegistryFor[*api.PersistentVolume](s, PersistentVolumes).Delete(ctx, "", name)
+}
+
+// ListPersistentVolumes retrieves all persistent volumes.
+func (s *InMemoryStore) ListPersistentVolumes(ctx context.Context) ([]*api.PersistentVolume, error) {
+	return RegistryFor[*api.PersistentVolume](s, PersistentVolumes).List(ctx, api.NamespaceAll)
+}
+
+// CreatePersistentVolumeClaim adds a new persistent volume claim to the store.
+func (s *InMemoryStore) CreatePersistentVolumeClaim(ctx context.Context, pvc *api.PersistentVolumeClaim) error {
+	return RegistryFor[*api.PersistentVolumeClaim](s, PersistentVolumeClaims).Create(ctx, pvc)
+}
+
+// GetPersistentVolumeClaim retrieves a persistent volume claim from the store.
+func (s *InMemoryStore) GetPersistentVolumeClaim(ctx context.Context, namespace, name string) (*api.PersistentVolumeClaim, error) {
+	return RegistryFor[*api.PersistentVolumeClaim](s, PersistentVolumeClaims).Get(ctx, namespace, name)
+}
+
+// UpdatePersistentVolumeClaim updates an existing persistent volume claim in the store.
+func (s *InMemoryStore) UpdatePersistentVolumeClaim(ctx context.Context, pvc *api.PersistentVolumeClaim) error {
+	return RegistryFor[*api.PersistentVolumeClaim](s, PersistentVolumeClaims).Update(ctx, pvc)
+}
+
+// DeletePersistentVolumeClaim removes a persistent volume claim from the store.
+func (s *InMemoryStore) DeletePersistentVolumeClaim(ctx context.Context, namespace, name string) error {
+	return RegistryFor[*api.PersistentVolumeClaim](s, PersistentVolumeClaims).Delete(ctx, namespace, name)
+}
+
+// ListPersistentVolumeClaims retrieves the persistent volume claims in a given
+// namespace, or in every namespace for api.NamespaceAll.
+func (s *InMemoryStore) ListPersistentVolumeClaims(ctx context.Context, namespace string) ([]*api.PersistentVolumeClaim, error) {
+	return RegistryFor[*api.PersistentVolumeClaim](s, PersistentVolumeClaims).List(ctx, namespace)
+}
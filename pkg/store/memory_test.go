@@ -0,0 +1,429 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+func TestObjectMetaSetOnWrite(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", UID: "client-supplied"}, Phase: api.PodPending}
+	if err := s.CreatePod(ctx, pod); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if pod.UID == "" || pod.UID == "client-supplied" {
+		t.Errorf("expected the store to assign a UID, got %q", pod.UID)
+	}
+	if pod.CreationTimestamp.IsZero() || time.Since(pod.CreationTimestamp) > time.Minute {
+		t.Errorf("expected CreationTimestamp to be set to now, got %v", pod.CreationTimestamp)
+	}
+	created := pod.ObjectMeta
+
+	other := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "db", Namespace: "default"}}
+	if err := s.CreatePod(ctx, other); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if other.UID == created.UID {
+		t.Errorf("expected distinct UIDs, both got %q", created.UID)
+	}
+
+	update := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodScheduled}
+	if err := s.UpdatePod(ctx, update); err != nil {
+		t.Fatalf("UpdatePod: %v", err)
+	}
+	if update.UID != created.UID || !update.CreationTimestamp.Equal(created.CreationTimestamp) {
+		t.Errorf("expected UID and CreationTimestamp to survive an update, got %q %v", update.UID, update.CreationTimestamp)
+	}
+	if update.ResourceVersion == created.ResourceVersion || update.ResourceVersion == other.ResourceVersion {
+		t.Errorf("expected a new resource version on update, got %q", update.ResourceVersion)
+	}
+
+	before := update.ResourceVersion
+	if err := s.DeletePod(ctx, "default", "web"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	deleted, _ := s.GetPod(ctx, "default", "web")
+	if deleted.ResourceVersion == before {
+		t.Errorf("expected marking the pod for deletion to bump its resource version")
+	}
+}
+
+func TestUpdatePodEnforcesPhaseTransitions(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	if err := s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodRunning}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+
+	err := s.UpdatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodPending})
+	if err == nil || !strings.Contains(err.Error(), "illegal phase transition from Running to Pending") {
+		t.Fatalf("expected Running -> Pending to be rejected, got %v", err)
+	}
+	if pod, _ := s.GetPod(ctx, "default", "web"); pod.Phase != api.PodRunning {
+		t.Errorf("rejected update changed the phase to %s", pod.Phase)
+	}
+
+	if err := s.UpdatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodSucceeded}); err != nil {
+		t.Errorf("expected Running -> Succeeded to be allowed, got %v", err)
+	}
+}
+
+func TestPodPhaseTransitions(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	forged := []api.PodPhaseTransition{{Phase: api.PodRunning, Time: time.Unix(0, 0)}}
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodPending, PhaseTransitions: forged}
+	if err := s.CreatePod(ctx, pod); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	for _, phase := range []api.PodPhase{api.PodScheduled, api.PodScheduled, api.PodRunning} {
+		update, _ := s.GetPod(ctx, "default", "web")
+		update.Phase = phase
+		update.PhaseTransitions = forged
+		if err := s.UpdatePod(ctx, update); err != nil {
+			t.Fatalf("UpdatePod to %s: %v", phase, err)
+		}
+	}
+	got, _ := s.GetPod(ctx, "default", "web")
+	var phases []api.PodPhase
+	for _, tr := range got.PhaseTransitions {
+		phases = append(phases, tr.Phase)
+	}
+	if want := []api.PodPhase{api.PodPending, api.PodScheduled, api.PodRunning}; !slices.Equal(phases, want) {
+		t.Errorf("expected the phase history %v, ignoring what clients sent, got %v", want, phases)
+	}
+	for i := 1; i < len(got.PhaseTransitions); i++ {
+		if got.PhaseTransitions[i].Time.Before(got.PhaseTransitions[i-1].Time) {
+			t.Errorf("expected transition times in order, got %+v", got.PhaseTransitions)
+		}
+	}
+
+	// The history is bounded.
+	history := &api.Pod{Phase: api.PodPending}
+	for i := 0; i < api.MaxPodPhaseTransitions+5; i++ {
+		history.Phase = []api.PodPhase{api.PodPending, api.PodScheduled}[i%2]
+		api.RecordPodPhase(history, time.Unix(int64(i), 0))
+	}
+	if n := len(history.PhaseTransitions); n != api.MaxPodPhaseTransitions || history.PhaseTransitions[n-1].Time != time.Unix(int64(api.MaxPodPhaseTransitions+4), 0).UTC() {
+		t.Errorf("expected the last %d transitions, got %+v", api.MaxPodPhaseTransitions, history.PhaseTransitions)
+	}
+}
+
+func TestDeletePodMarksPodTerminating(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	if err := s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, NodeName: "node1", Phase: api.PodRunning}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	if err := s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "pending", Namespace: "default"}, Phase: api.PodPending}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	for _, name := range []string{"web", "pending"} {
+		if err := s.DeletePod(ctx, "default", name); err != nil {
+			t.Fatalf("DeletePod(%s): %v", name, err)
+		}
+	}
+
+	web, _ := s.GetPod(ctx, "default", "web")
+	if !api.IsPodTerminating(web) || web.Phase != api.PodRunning {
+		t.Fatalf("expected a bound pod to stay Running until its kubelet stops it, got phase %s", web.Phase)
+	}
+	if ready := api.GetPodCondition(web, api.PodReadyCondition); ready == nil || ready.Status != api.ConditionFalse || ready.Reason != "Terminating" {
+		t.Errorf("expected Ready=False (Terminating), got %+v", ready)
+	}
+	if pending, _ := s.GetPod(ctx, "default", "pending"); pending.Phase != api.PodDeleted {
+		t.Errorf("expected an unbound pod to be Deleted at once, got phase %s", pending.Phase)
+	}
+
+	update := *web
+	update.Phase = api.PodPending
+	if err := s.UpdatePod(ctx, &update); err == nil {
+		t.Errorf("expected a terminating pod to be refused Running -> Pending")
+	}
+	update.Phase = api.PodDeleted
+	if err := s.UpdatePod(ctx, &update); err != nil {
+		t.Errorf("expected the kubelet to be allowed to finish a terminating pod, got %v", err)
+	}
+}
+
+func TestUpdateLeaseRequiresCurrentResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	lease := &api.Lease{ObjectMeta: api.ObjectMeta{Name: "scheduler", Namespace: "default"}, DurationSeconds: 15}
+	if err := s.CreateLease(ctx, lease); err != nil {
+		t.Fatalf("CreateLease: %v", err)
+	}
+	read := lease.ResourceVersion
+
+	first := &api.Lease{ObjectMeta: api.ObjectMeta{Name: "scheduler", Namespace: "default", ResourceVersion: read}, HolderIdentity: "a", DurationSeconds: 15}
+	if err := s.UpdateLease(ctx, first); err != nil {
+		t.Fatalf("UpdateLease: %v", err)
+	}
+	second := &api.Lease{ObjectMeta: api.ObjectMeta{Name: "scheduler", Namespace: "default", ResourceVersion: read}, HolderIdentity: "b", DurationSeconds: 15}
+	if err := s.UpdateLease(ctx, second); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected an update from a stale read to conflict, got %v", err)
+	}
+	if got, _ := s.GetLease(ctx, "default", "scheduler"); got.HolderIdentity != "a" {
+		t.Errorf("expected the first writer to hold the lease, got %q", got.HolderIdentity)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodPending}
+	if err := s.CreatePod(ctx, pod); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"create existing", s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}}), ErrAlreadyExists},
+		{"update missing", s.UpdateConfigMap(ctx, &api.ConfigMap{ObjectMeta: api.ObjectMeta{Name: "missing", Namespace: "default"}}), ErrNotFound},
+		{"delete missing", s.DeletePod(ctx, "default", "missing"), ErrNotFound},
+		{"illegal phase transition", s.UpdatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Phase: api.PodSucceeded}), ErrConflict},
+	} {
+		if !errors.Is(tc.err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, tc.err)
+		}
+	}
+	if _, err := s.GetNode(ctx, "missing"); !errors.Is(err, ErrNotFound) || err.Error() != "node missing not found" {
+		t.Errorf("expected node missing not found, got %v", err)
+	}
+	if err := s.DeletePod(ctx, "default", "web"); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	if err := s.DeletePod(ctx, "default", "web"); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected deleting a terminating pod to conflict, got %v", err)
+	}
+}
+
+func TestShardedStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedInMemoryStore(8)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("pod-%d-%d", w, i), Namespace: "default"}, Phase: api.PodPending}
+				if err := s.CreatePod(ctx, pod); err != nil {
+					t.Errorf("CreatePod: %v", err)
+					return
+				}
+				pod.Phase = api.PodScheduled
+				pod.NodeName = "node1"
+				if err := s.UpdatePod(ctx, pod); err != nil {
+					t.Errorf("UpdatePod: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	pods, err := s.ListPods(ctx, "default")
+	if err != nil || len(pods) != 400 {
+		t.Fatalf("expected 400 pods, got %d: %v", len(pods), err)
+	}
+	// However the writes interleaved, the journal has each at its own revision, in order.
+	changes, _, err := s.Changes(0)
+	if err != nil {
+		t.Fatalf("Changes: %v", err)
+	}
+	if len(changes) != 800 || s.Revision() != 800 {
+		t.Fatalf("expected 800 changes up to revision 800, got %d up to %d", len(changes), s.Revision())
+	}
+	for i, c := range changes {
+		if c.Revision != uint64(i+1) || c.Object.GetObjectMeta().ResourceVersion != strconv.Itoa(i+1) {
+			t.Fatalf("change %d: expected revision %d, got %d", i, i+1, c.Revision)
+		}
+	}
+
+	// A transaction spans the shards, and is undone across them.
+	err = s.Txn(ctx, func(tx StoreTxn) error {
+		for _, pod := range pods[:10] {
+			if err := tx.DeletePod(ctx, pod.Namespace, pod.Name); err != nil {
+				return err
+			}
+		}
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Fatal("expected the transaction to fail")
+	}
+	for _, pod := range pods[:10] {
+		if got, _ := s.GetPod(ctx, pod.Namespace, pod.Name); got.DeletionTimestamp != nil {
+			t.Errorf("expected the deletion of pod %s to be undone", pod.Name)
+		}
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.CreateNamespace(ctx, &api.Namespace{ObjectMeta: api.ObjectMeta{Name: "web"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a create with a cancelled context to fail, got %v", err)
+	}
+	if _, err := s.GetNamespace(context.Background(), "web"); err == nil {
+		t.Error("expected nothing to be created with a cancelled context")
+	}
+	if _, err := s.ListNamespaces(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a list with a cancelled context to fail, got %v", err)
+	}
+
+	// A transaction whose caller gave up while it ran leaves nothing behind.
+	ctx, cancel = context.WithCancel(context.Background())
+	err := s.Txn(ctx, func(tx StoreTxn) error {
+		if err := tx.CreateNamespace(ctx, &api.Namespace{ObjectMeta: api.ObjectMeta{Name: "web"}}); err != nil {
+			return err
+		}
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the transaction to fail once its context was cancelled, got %v", err)
+	}
+	if _, err := s.GetNamespace(context.Background(), "web"); err == nil {
+		t.Error("expected the cancelled transaction's write to be undone")
+	}
+}
+
+func TestTxn(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	if err := s.CreateNode(ctx, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}}); err != nil {
+		t.Fatalf("CreateNode: %v", err)
+	}
+	if err := s.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "old", Namespace: "default"}, Phase: api.PodPending}); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	node, _ := s.GetNode(ctx, "node1")
+	before := node.ResourceVersion
+
+	// A failed transaction leaves nothing behind, whatever it wrote before failing.
+	err := s.Txn(ctx, func(tx StoreTxn) error {
+		if err := tx.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "new", Namespace: "default"}, Phase: api.PodPending}); err != nil {
+			return err
+		}
+		if err := tx.UpdateNode(ctx, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node1"}, Status: api.NodeNotReady}); err != nil {
+			return err
+		}
+		if err := tx.DeletePod(ctx, "default", "old"); err != nil {
+			return err
+		}
+		return tx.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "new", Namespace: "default"}})
+	})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected the transaction to fail creating a pod twice, got %v", err)
+	}
+	if _, err := s.GetPod(ctx, "default", "new"); err == nil {
+		t.Errorf("expected the pod created in the failed transaction to be gone")
+	}
+	if old, _ := s.GetPod(ctx, "default", "old"); old.DeletionTimestamp != nil {
+		t.Errorf("expected the pod deleted in the failed transaction to be restored")
+	}
+	if node, _ := s.GetNode(ctx, "node1"); node.ResourceVersion != before || node.Status == api.NodeNotReady {
+		t.Errorf("expected the node update to be undone, got %+v", node)
+	}
+
+	func() {
+		defer func() { recover() }()
+		_ = s.Txn(ctx, func(tx StoreTxn) error {
+			_ = tx.DeleteNode(ctx, "node1")
+			panic("boom")
+		})
+	}()
+	if _, err := s.GetNode(ctx, "node1"); err != nil {
+		t.Errorf("expected a panicking transaction to be undone: %v", err)
+	}
+
+	// A successful one applies everything, with resource versions carrying on after it.
+	if err := s.Txn(ctx, func(tx StoreTxn) error {
+		if err := tx.DeletePod(ctx, "default", "old"); err != nil {
+			return err
+		}
+		return tx.CreatePod(ctx, &api.Pod{ObjectMeta: api.ObjectMeta{Name: "new", Namespace: "default"}, Phase: api.PodPending})
+	}); err != nil {
+		t.Fatalf("Txn: %v", err)
+	}
+	created, err := s.GetPod(ctx, "default", "new")
+	if err != nil {
+		t.Fatalf("expected the pod created in the transaction: %v", err)
+	}
+	if err := s.CreateNamespace(ctx, &api.Namespace{ObjectMeta: api.ObjectMeta{Name: "later"}}); err != nil {
+		t.Fatalf("CreateNamespace: %v", err)
+	}
+	ns, _ := s.GetNamespace(ctx, "later")
+	if after, _ := strconv.Atoi(ns.ResourceVersion); strconv.Itoa(after-1) != created.ResourceVersion {
+		t.Errorf("expected resource versions to carry on after the transaction, got %s after %s", ns.ResourceVersion, created.ResourceVersion)
+	}
+}
+
+func TestObjectsAreCopied(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}}, Phase: api.PodPending}
+	if err := s.CreatePod(ctx, pod); err != nil {
+		t.Fatalf("CreatePod: %v", err)
+	}
+	pod.Labels["app"] = "written"
+
+	got, _ := s.GetPod(ctx, "default", "web")
+	got.Labels["app"] = "read"
+	got.Phase = api.PodRunning
+	listed, _ := s.ListPods(ctx, "default")
+	listed[0].Labels["app"] = "listed"
+
+	stored, _ := s.GetPod(ctx, "default", "web")
+	if stored.Labels["app"] != "web" || stored.Phase != api.PodPending {
+		t.Errorf("expected changes to written and read pods to leave the stored pod alone, got %+v", stored)
+	}
+}
+
+func TestRegistryFor(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryStore()
+	deployments := RegistryFor[*api.Deployment](s, Deployments)
+	if err := deployments.Create(ctx, &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}, Replicas: 1}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := deployments.Create(ctx, &api.Deployment{ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default"}}); err == nil || err.Error() != "deployment web in namespace default already exists" {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+	if d, err := s.GetDeployment(ctx, "default", "web"); err != nil || d.Replicas != 1 {
+		t.Errorf("expected the shorthand to read what the registry wrote, got %+v, %v", d, err)
+	}
+
+	if err := s.Txn(ctx, func(tx StoreTxn) error {
+		return RegistryFor[*api.Deployment](tx, Deployments).Delete(ctx, "default", "web")
+	}); err != nil {
+		t.Fatalf("Txn: %v", err)
+	}
+	if all, _ := deployments.List(ctx, api.NamespaceAll); len(all) != 0 {
+		t.Errorf("expected the deployment deleted in the transaction to be gone, got %v", all)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected RegistryFor with the wrong type to panic")
+		}
+	}()
+	RegistryFor[*api.Pod](s, Deployments)
+}
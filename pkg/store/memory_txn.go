@@ -0,0 +1,99 @@
+package store
+
+import "context"
+
+// rwLocker is the lock of an InMemoryStore or a shard: a *sync.RWMutex, or noLock in
+// the view of the store a transaction works through, whose caller already holds the
+// store's locks.
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
+// Txn runs fn holding the store's write lock, or every shard's in a sharded store, so
+// nothing else reads or writes the store until fn returns. fn works through a view of
+// the store whose registries share their objects with the store's but don't lock, and
+// records how to undo each write; if fn returns an error or panics, the writes are
+// undone in reverse order, resource versions included. The writes are journaled
+// together when fn succeeds, and undone too if that fails.
+func (s *InMemoryStore) Txn(ctx context.Context, fn func(tx StoreTxn) error) error {
+	for _, l := range s.locks {
+		l.Lock()
+		defer l.Unlock()
+	}
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx := &InMemoryStore{
+		mu:              noLock{},
+		shards:          s.shards,
+		commitMu:        noLock{},
+		registries:      make(map[GroupResource]registry, len(s.registries)),
+		resourceVersion: s.resourceVersion,
+		txn:             true,
+	}
+	for gr, r := range s.registries {
+		tx.registries[gr] = r.bind(tx)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			for i := len(tx.undo) - 1; i >= 0; i-- {
+				tx.undo[i]()
+			}
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	// A caller that gave up while fn ran doesn't expect its writes to stick.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := s.journal.append(tx.pending...); err != nil {
+		return err
+	}
+	committed = true
+	s.resourceVersion = tx.resourceVersion
+	return nil
+}
+
+// put stores obj under key in m. In a transaction it first records how to undo that.
+func put[T any](s *InMemoryStore, m map[string]T, key string, obj T) {
+	if s.txn {
+		s.undo = append(s.undo, undoFor(m, key))
+	}
+	m[key] = obj
+}
+
+// remove deletes key from m. In a transaction it first records how to undo that.
+func remove[T any](s *InMemoryStore, m map[string]T, key string) {
+	if s.txn {
+		s.undo = append(s.undo, undoFor(m, key))
+	}
+	delete(m, key)
+}
+
+// undoFor returns a func restoring key in m to what it is now.
+func undoFor[T any](m map[string]T, key string) func() {
+	old, existed := m[key]
+	return func() {
+		if existed {
+			m[key] = old
+		} else {
+			delete(m, key)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sync"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
+
+// GroupResource names a resource by its API group and plural name, such as pods in
+// the core group ("") or deployments in "apps".
+type GroupResource struct {
+	Group    string `json:"group,omitempty"`
+	Resource string `json:"resource"`
+}
+
+func (gr GroupResource) String() string {
+	if gr.Group == "" {
+		return gr.Resource
+	}
+	return gr.Resource + "." + gr.Group
+}
+
+// The resources an InMemoryStore has a registry for.
+var (
+	Pods                   = GroupResource{Resource: "pods"}
+	Nodes                  = GroupResource{Resource: "nodes"}
+	Namespaces             = GroupResource{Resource: "namespaces"}
+	Deployments            = GroupResource{Group: "apps", Resource: "deployments"}
+	Services               = GroupResource{Resource: "services"}
+	Events                 = GroupResource{Resource: "events"}
+	PodDisruptionBudgets   = GroupResource{Group: "policy", Resource: "poddisruptionbudgets"}
+	NetworkPolicies        = GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}
+	PersistentVolumes      = GroupResource{Resource: "persistentvolumes"}
+	PersistentVolumeClaims = GroupResource{Resource: "persistentvolumeclaims"}
+	Leases                 = GroupResource{Group: "coordination.k8s.io", Resource: "leases"}
+	ConfigMaps             = GroupResource{Resource: "configmaps"}
+	Secrets                = GroupResource{Resource: "secrets"}
+	APIServices            = GroupResource{Group: "apiregistration.k8s.io", Resource: "apiservices"}
+)
+
+// newRegistries returns an empty registry for every resource. Adding a resource to
+// the store takes a line here, and its API type needs GetObjectMeta (from ObjectMeta)
+// and DeepCopy.
+func newRegistries(s *InMemoryStore) map[GroupResource]registry {
+	pods := newRegistry[*api.Pod](s, Pods, "pod", true, ValidatePodUpdate)
+	pods.prepare = preparePod
+	return map[GroupResource]registry{
+		Pods:                   pods,
+		Nodes:                  newRegistry[*api.Node](s, Nodes, "node", false, nil),
+		Namespaces:             newRegistry[*api.Namespace](s, Namespaces, "namespace", false, nil),
+		Deployments:            newRegistry[*api.Deployment](s, Deployments, "deployment", true, nil),
+		Services:               newRegistry[*api.Service](s, Services, "service", true, nil),
+		Events:                 newRegistry[*api.Event](s, Events, "event", true, nil),
+		PodDisruptionBudgets:   newRegistry[*api.PodDisruptionBudget](s, PodDisruptionBudgets, "poddisruptionbudget", true, nil),
+		NetworkPolicies:        newRegistry[*api.NetworkPolicy](s, NetworkPolicies, "networkpolicy", true, nil),
+		PersistentVolumes:      newRegistry[*api.PersistentVolume](s, PersistentVolumes, "persistentvolume", false, nil),
+		PersistentVolumeClaims: newRegistry[*api.PersistentVolumeClaim](s, PersistentVolumeClaims, "persistentvolumeclaim", true, nil),
+		Leases:                 newRegistry[*api.Lease](s, Leases, "lease", true, validateLeaseUpdate),
+		ConfigMaps:             newRegistry[*api.ConfigMap](s, ConfigMaps, "configmap", true, nil),
+		Secrets:                newRegistry[*api.Secret](s, Secrets, "secret", true, nil),
+		APIServices:            newRegistry[*api.APIService](s, APIServices, "apiservice", false, nil),
+	}
+}
+
+// Object is what a Registry stores: a pointer to an API type, such as *api.Pod.
+type Object[T any] interface {
+	GetObjectMeta() *api.ObjectMeta
+	DeepCopy() T
+}
+
+// Registry stores the objects of one resource, with the same semantics for every
+// resource: the store sets server-owned metadata on create and update, objects are
+// deep-copied on the way in and out, and writes made inside Store.Txn are undone if
+// the transaction fails.
+type Registry[T Object[T]] struct {
+	store      *InMemoryStore // For its commit lock, resource versions, journal, and transaction
+	resource   GroupResource
+	kind       string // Lower-case singular name used in errors, e.g. "pod"
+	namespaced bool
+	shards     []*shard[T] // Just one, locked by the store's lock, unless the store is sharded
+
+	// validateUpdate, if set, checks whether obj may replace existing. Its errors
+	// should wrap ErrConflict where rereading the object and retrying may succeed.
+	validateUpdate func(existing, obj T) error
+	// prepare, if set, sets the fields of obj the store maintains, before obj is
+	// created (existing is nil) or replaces existing.
+	prepare func(existing, obj T)
+}
+
+// shard holds some of a registry's objects, by the hash of their keys, and the lock
+// that guards them.
+type shard[T any] struct {
+	mu      rwLocker
+	objects map[string]T // Key: "namespace/name", or "name" if cluster-scoped
+}
+
+// registry is a Registry of any type.
+type registry interface {
+	// bind returns a copy of the registry that shares its objects but works through s,
+	// without locking them.
+	bind(s *InMemoryStore) registry
+	// locks returns the locks of the registry's shards.
+	locks() []rwLocker
+	// restore redoes a journaled change to an object, given as JSON, and returns the
+	// object, when a store is rebuilt from its journal.
+	restore(t ChangeType, data []byte) (metaObject, error)
+}
+
+func newRegistry[T Object[T]](s *InMemoryStore, gr GroupResource, kind string, namespaced bool, validateUpdate func(existing, obj T) error) *Registry[T] {
+	r := &Registry[T]{store: s, resource: gr, kind: kind, namespaced: namespaced, validateUpdate: validateUpdate}
+	if s.shards <= 1 {
+		r.shards = []*shard[T]{{mu: s.mu, objects: make(map[string]T)}}
+		return r
+	}
+	for i := 0; i < s.shards; i++ {
+		r.shards = append(r.shards, &shard[T]{mu: &sync.RWMutex{}, objects: make(map[string]T)})
+	}
+	return r
+}
+
+func (r *Registry[T]) bind(s *InMemoryStore) registry {
+	bound := *r
+	bound.store = s
+	bound.shards = make([]*shard[T], len(r.shards))
+	for i, sh := range r.shards {
+		bound.shards[i] = &shard[T]{mu: noLock{}, objects: sh.objects}
+	}
+	return &bound
+}
+
+func (r *Registry[T]) locks() []rwLocker {
+	locks := make([]rwLocker, len(r.shards))
+	for i, sh := range r.shards {
+		locks[i] = sh.mu
+	}
+	return locks
+}
+
+// shard returns the shard holding the object stored under key.
+func (r *Registry[T]) shard(key string) *shard[T] {
+	if len(r.shards) == 1 {
+		return r.shards[0]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return r.shards[h.Sum32()%uint32(len(r.shards))]
+}
+
+// RegistryFor returns the registry for resource gr of st, which may be the StoreTxn of
+// a transaction. It panics if st has no registry for gr or it doesn't store T.
+func RegistryFor[T Object[T]](st StoreTxn, gr GroupResource) *Registry[T] {
+	r, ok := st.registry(gr).(*Registry[T])
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("store: no registry for %s storing %T", gr, zero))
+	}
+	return r
+}
+
+func (r *Registry[T]) restore(t ChangeType, data []byte) (metaObject, error) {
+	var zero T
+	obj := reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", r.kind, err)
+	}
+	meta := obj.GetObjectMeta()
+	key := r.key(meta.Namespace, meta.Name)
+	objects := r.shard(key).objects
+	switch t {
+	case ChangeAdded, ChangeModified:
+		objects[key] = obj
+	case ChangeDeleted:
+		delete(objects, key)
+	default:
+		return nil, fmt.Errorf("unknown change type %q", t)
+	}
+	return obj, nil
+}
+
+func (r *Registry[T]) key(namespace, name string) string {
+	if r.namespaced {
+		return podKey(namespace, name)
+	}
+	return name
+}
+
+// describe names an object in errors, e.g. "pod web in namespace default".
+func (r *Registry[T]) describe(namespace, name string) string {
+	if r.namespaced {
+		return fmt.Sprintf("%s %s in namespace %s", r.kind, name, namespace)
+	}
+	return fmt.Sprintf("%s %s", r.kind, name)
+}
+
+// Create adds obj, setting its UID, creation timestamp, and resource version.
+func (r *Registry[T]) Create(ctx context.Context, obj T) error {
+	meta := obj.GetObjectMeta()
+	key := r.key(meta.Namespace, meta.Name)
+	sh := r.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, exists := sh.objects[key]; exists {
+		return fmt.Errorf("%s %w", r.describe(meta.Namespace, meta.Name), ErrAlreadyExists)
+	}
+	r.store.commitMu.Lock()
+	defer r.store.commitMu.Unlock()
+	r.store.initMeta(meta)
+	if r.prepare != nil {
+		var none T
+		r.prepare(none, obj)
+	}
+	return r.put(sh, ChangeAdded, key, obj.DeepCopy())
+}
+
+// Get returns a copy of the named object. namespace is ignored for cluster-scoped
+// resources.
+func (r *Registry[T]) Get(ctx context.Context, namespace, name string) (T, error) {
+	key := r.key(namespace, name)
+	sh := r.shard(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	obj, exists := sh.objects[key]
+	if !exists {
+		var zero T
+		return zero, fmt.Errorf("%s %w", r.describe(namespace, name), ErrNotFound)
+	}
+	return obj.DeepCopy(), nil
+}
+
+// Update replaces an existing object with obj, carrying over its immutable metadata
+// and giving obj a new resource version.
+func (r *Registry[T]) Update(ctx context.Context, obj T) error {
+	meta := obj.GetObjectMeta()
+	key := r.key(meta.Namespace, meta.Name)
+	sh := r.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	existing, exists := sh.objects[key]
+	if !exists {
+		return fmt.Errorf("%s %w for update", r.describe(meta.Namespace, meta.Name), ErrNotFound)
+	}
+	if r.validateUpdate != nil {
+		if err := r.validateUpdate(existing, obj); err != nil {
+			return err
+		}
+	}
+	r.store.commitMu.Lock()
+	defer r.store.commitMu.Unlock()
+	r.store.updateMeta(meta, existing.GetObjectMeta())
+	if r.prepare != nil {
+		r.prepare(existing, obj)
+	}
+	return r.put(sh, ChangeModified, key, obj.DeepCopy())
+}
+
+// Delete removes the named object.
+func (r *Registry[T]) Delete(ctx context.Context, namespace, name string) error {
+	key := r.key(namespace, name)
+	sh := r.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	existing, exists := sh.objects[key]
+	if !exists {
+		return fmt.Errorf("%s %w for deletion", r.describe(namespace, name), ErrNotFound)
+	}
+	// Watchers see the object as it was, at the revision it was deleted.
+	deleted := existing.DeepCopy()
+	r.store.commitMu.Lock()
+	defer r.store.commitMu.Unlock()
+	deleted.GetObjectMeta().ResourceVersion = r.store.nextResourceVersion()
+	if err := r.store.record(ChangeDeleted, r.resource, deleted); err != nil {
+		return err
+	}
+	remove(r.store, sh.objects, key)
+	return nil
+}
+
+// put journals the write of obj under key in sh, then makes it. The journal and the
+// stored object share obj, which neither changes. The caller must hold sh.mu and the
+// store's commit lock.
+func (r *Registry[T]) put(sh *shard[T], t ChangeType, key string, obj T) error {
+	if err := r.store.record(t, r.resource, obj); err != nil {
+		return err
+	}
+	put(r.store, sh.objects, key, obj)
+	return nil
+}
+
+// List returns copies of the objects in namespace, or of every object for
+// api.NamespaceAll or a cluster-scoped resource, in no particular order. The shards
+// of a sharded store are read one after another, so the list may include a write made
+// meanwhile to one shard but miss an earlier one to another; Store.Txn lists all at
+// one revision.
+func (r *Registry[T]) List(ctx context.Context, namespace string) ([]T, error) {
+	var result []T
+	for _, sh := range r.shards {
+		sh.mu.RLock()
+		if err := ctx.Err(); err != nil {
+			sh.mu.RUnlock()
+			return nil, err
+		}
+		for _, obj := range sh.objects {
+			if !r.namespaced || namespace == api.NamespaceAll || obj.GetObjectMeta().Namespace == namespace {
+				result = append(result, obj.DeepCopy())
+			}
+		}
+		sh.mu.RUnlock()
+	}
+	return result, nil
+}
@@ -1,21 +1,133 @@
 package store
 
-import "github.com/Ayobami-00/k8s-lite-go/pkg/api"
+import (
+	"context"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+)
 
 // Store defines the interface for interacting with the backend data store.
-// It handles the storage and retrieval of API objects like Pods and Nodes.
+// It handles the storage and retrieval of API objects like Pods, Nodes, and Deployments.
 type Store interface {
+	StoreTxn
+
+	// Txn runs fn as one transaction: the writes fn makes through tx take effect
+	// together when it returns nil, and not at all if it returns an error (which Txn
+	// returns) or panics. Nothing else reads or writes the store in between, so reads
+	// through tx stay valid until fn returns. The in-memory store holds its lock for
+	// the duration; a database backend would map Txn to a database transaction. fn
+	// must not use the Store itself, and tx must not be used after fn returns.
+	Txn(ctx context.Context, fn func(tx StoreTxn) error) error
+
+	// Changes returns the writes made after revision, a resource version, oldest
+	// first, and a channel closed at the next write; see InMemoryStore.Changes. It
+	// fails with ErrRevisionCompacted once the changes after revision are forgotten.
+	Changes(revision uint64) ([]Change, <-chan struct{}, error)
+}
+
+// StoreTxn is the operations on the objects in a Store. Called on the Store, each is
+// atomic on its own; called on the StoreTxn passed to Store.Txn, they are part of
+// that transaction.
+//
+// Each resource is kept in a generic Registry, which RegistryFor returns, e.g.
+// RegistryFor[*api.Pod](st, Pods).Get(ctx, namespace, name). The methods below are
+// shorthands for the resources that had them before registries; a new resource needs
+// only a registry in newRegistries, not five more methods here.
+type StoreTxn interface {
+	// registry returns the registry for gr, for RegistryFor.
+	registry(gr GroupResource) registry
+
+	// Revision returns the resource version of the last write. Read in a transaction
+	// together with some objects, it is where a watch of those objects resumes from.
+	Revision() uint64
+
 	// Pod operations
-	CreatePod(pod *api.Pod) error
-	GetPod(namespace, name string) (*api.Pod, error)
-	UpdatePod(pod *api.Pod) error
-	DeletePod(namespace, name string) error
-	ListPods(namespace string) ([]*api.Pod, error)
+	CreatePod(ctx context.Context, pod *api.Pod) error
+	GetPod(ctx context.Context, namespace, name string) (*api.Pod, error)
+	UpdatePod(ctx context.Context, pod *api.Pod) error
+	DeletePod(ctx context.Context, namespace, name string) error
+	ListPods(ctx context.Context, namespace string) ([]*api.Pod, error)
 
 	// Node operations
-	CreateNode(node *api.Node) error
-	GetNode(name string) (*api.Node, error)
-	UpdateNode(node *api.Node) error
-	DeleteNode(name string) error
-	ListNodes() ([]*api.Node, error)
+	CreateNode(ctx context.Context, node *api.Node) error
+	GetNode(ctx context.Context, name string) (*api.Node, error)
+	UpdateNode(ctx context.Context, node *api.Node) error
+	DeleteNode(ctx context.Context, name string) error
+	ListNodes(ctx context.Context) ([]*api.Node, error)
+
+	// Namespace operations
+	CreateNamespace(ctx context.Context, ns *api.Namespace) error
+	GetNamespace(ctx context.Context, name string) (*api.Namespace, error)
+	UpdateNamespace(ctx context.Context, ns *api.Namespace) error
+	DeleteNamespace(ctx context.Context, name string) error
+	ListNamespaces(ctx context.Context) ([]*api.Namespace, error)
+
+	// Deployment operations
+	CreateDeployment(ctx context.Context, d *api.Deployment) error
+	GetDeployment(ctx context.Context, namespace, name string) (*api.Deployment, error)
+	UpdateDeployment(ctx context.Context, d *api.Deployment) error
+	DeleteDeployment(ctx context.Context, namespace, name string) error
+	ListDeployments(ctx context.Context, namespace string) ([]*api.Deployment, error)
+
+	// Service operations
+	CreateService(ctx context.Context, svc *api.Service) error
+	GetService(ctx context.Context, namespace, name string) (*api.Service, error)
+	UpdateService(ctx context.Context, svc *api.Service) error
+	DeleteService(ctx context.Context, namespace, name string) error
+	ListServices(ctx context.Context, namespace string) ([]*api.Service, error)
+
+	// PodDisruptionBudget operations
+	CreatePodDisruptionBudget(ctx context.Context, pdb *api.PodDisruptionBudget) error
+	GetPodDisruptionBudget(ctx context.Context, namespace, name string) (*api.PodDisruptionBudget, error)
+	UpdatePodDisruptionBudget(ctx context.Context, pdb *api.PodDisruptionBudget) error
+	DeletePodDisruptionBudget(ctx context.Context, namespace, name string) error
+	ListPodDisruptionBudgets(ctx context.Context, namespace string) ([]*api.PodDisruptionBudget, error)
+
+	// NetworkPolicy operations
+	CreateNetworkPolicy(ctx context.Context, policy *api.NetworkPolicy) error
+	GetNetworkPolicy(ctx context.Context, namespace, name string) (*api.NetworkPolicy, error)
+	UpdateNetworkPolicy(ctx context.Context, policy *api.NetworkPolicy) error
+	DeleteNetworkPolicy(ctx context.Context, namespace, name string) error
+	ListNetworkPolicies(ctx context.Context, namespace string) ([]*api.NetworkPolicy, error)
+
+	// PersistentVolume operations
+	CreatePersistentVolume(ctx context.Context, pv *api.PersistentVolume) error
+	GetPersistentVolume(ctx context.Context, name string) (*api.PersistentVolume, error)
+	UpdatePersistentVolume(ctx context.Context, pv *api.PersistentVolume) error
+	DeletePersistentVolume(ctx context.Context, name string) error
+	ListPersistentVolumes(ctx context.Context) ([]*api.PersistentVolume, error)
+
+	// PersistentVolumeClaim operations. ListPersistentVolumeClaims accepts api.NamespaceAll.
+	CreatePersistentVolumeClaim(ctx context.Context, pvc *api.PersistentVolumeClaim) error
+	GetPersistentVolumeClaim(ctx context.Context, namespace, name string) (*api.PersistentVolumeClaim, error)
+	UpdatePersistentVolumeClaim(ctx context.Context, pvc *api.PersistentVolumeClaim) error
+	DeletePersistentVolumeClaim(ctx context.Context, namespace, name string) error
+	ListPersistentVolumeClaims(ctx context.Context, namespace string) ([]*api.PersistentVolumeClaim, error)
+
+	// Lease operations. UpdateLease fails with a conflict unless the lease carries the
+	// stored resourceVersion, so that only one of two racing writers wins.
+	CreateLease(ctx context.Context, lease *api.Lease) error
+	GetLease(ctx context.Context, namespace, name string) (*api.Lease, error)
+	UpdateLease(ctx context.Context, lease *api.Lease) error
+	DeleteLease(ctx context.Context, namespace, name string) error
+	ListLeases(ctx context.Context, namespace string) ([]*api.Lease, error)
+
+	// ConfigMap operations
+	CreateConfigMap(ctx context.Context, configMap *api.ConfigMap) error
+	GetConfigMap(ctx context.Context, namespace, name string) (*api.ConfigMap, error)
+	UpdateConfigMap(ctx context.Context, configMap *api.ConfigMap) error
+	DeleteConfigMap(ctx context.Context, namespace, name string) error
+	ListConfigMaps(ctx context.Context, namespace string) ([]*api.ConfigMap, error)
+
+	// Secret operations
+	CreateSecret(ctx context.Context, secret *api.Secret) error
+	GetSecret(ctx context.Context, namespace, name string) (*api.Secret, error)
+	UpdateSecret(ctx context.Context, secret *api.Secret) error
+	DeleteSecret(ctx context.Context, namespace, name string) error
+	ListSecrets(ctx context.Context, namespace string) ([]*api.Secret, error)
+
+	// Event operations
+	CreateEvent(ctx context.Context, event *api.Event) error
+	UpdateEvent(ctx context.Context, event *api.Event) error
+	ListEvents(ctx context.Context, namespace string) ([]*api.Event, error)
 }
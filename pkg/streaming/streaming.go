@@ -0,0 +1,293 @@
+// Package streaming is the protocol that pod exec, attach, and port-forward streams
+// speak between kubectl-lite, the API server, and the kubelet. A stream is a WebSocket
+// (the API server just proxies it to the pod's kubelet) whose binary messages each
+// start with a byte naming their channel:
+//
+//	0 stdin   client to kubelet: the process's input, or bytes sent to a forwarded port
+//	1 stdout  kubelet to client: the process's output, or bytes from a forwarded port
+//	2 stderr  kubelet to client: the process's error output
+//	3 status  kubelet to client: a Status as JSON, the last thing sent on a stream
+//	4 resize  client to kubelet: a TerminalSize as JSON, whenever the terminal changes
+//
+// A message of just the channel byte closes the channel, as the client does for stdin
+// at the end of its input. The WebSocket subprotocol is Protocol.
+package streaming
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Protocol is the WebSocket subprotocol of streams, sent in Sec-WebSocket-Protocol.
+const Protocol = "channel.k8s-lite.io"
+
+// Channel is the first byte of every message on a stream.
+type Channel byte
+
+// The channels of a stream.
+const (
+	StdinChannel Channel = iota
+	StdoutChannel
+	StderrChannel
+	StatusChannel
+	ResizeChannel
+)
+
+// chunkSize is the most data sent in one message.
+const chunkSize = 32 << 10
+
+// TerminalSize is the size of the client's terminal, in characters.
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// Status is how a stream ended: the exit code of the process, or why the stream
+// couldn't do what was asked of it.
+type Status struct {
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Conn is one end of a stream.
+type Conn struct {
+	ws *wsConn
+
+	// The server end's inputs, fed by demux.
+	stdin   *io.PipeReader
+	resizes chan TerminalSize
+	done    chan struct{}
+
+	closeOnce sync.Once
+}
+
+// Upgrade answers a stream request, switching the connection to the stream protocol;
+// it answers anything else with 400. The returned Conn is the server's end of the
+// stream, which must be finished with Close.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	ws, err := upgrade(w, r)
+	if err != nil {
+		return nil, err
+	}
+	stdin, stdinW := io.Pipe()
+	c := &Conn{ws: ws, stdin: stdin, resizes: make(chan TerminalSize, 1), done: make(chan struct{})}
+	go c.demux(stdinW)
+	return c, nil
+}
+
+// demux reads what the client sends, until it goes away.
+func (c *Conn) demux(stdin *io.PipeWriter) {
+	defer close(c.done)
+	defer stdin.Close()
+	for {
+		msg, err := c.ws.readMessage()
+		if err != nil {
+			return
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		switch Channel(msg[0]) {
+		case StdinChannel:
+			if len(msg) == 1 {
+				stdin.Close()
+				continue
+			}
+			// Fails once the process stops reading its input; the rest is dropped.
+			stdin.Write(msg[1:])
+		case ResizeChannel:
+			var size TerminalSize
+			if json.Unmarshal(msg[1:], &size) != nil {
+				continue
+			}
+			// Only the latest size matters.
+			select {
+			case <-c.resizes:
+			default:
+			}
+			c.resizes <- size
+		}
+	}
+}
+
+// Stdin returns what the client sends on the stdin channel. It ends when the client
+// closes the channel or goes away.
+func (c *Conn) Stdin() io.Reader { return c.stdin }
+
+// Stdout returns a writer sending to the client on the stdout channel.
+func (c *Conn) Stdout() io.Writer { return channelWriter{c.ws, StdoutChannel} }
+
+// Stderr returns a writer sending to the client on the stderr channel.
+func (c *Conn) Stderr() io.Writer { return channelWriter{c.ws, StderrChannel} }
+
+// Resizes returns the client's terminal sizes, as it sends them.
+func (c *Conn) Resizes() <-chan TerminalSize { return c.resizes }
+
+// Done is closed once the client goes away.
+func (c *Conn) Done() <-chan struct{} { return c.done }
+
+// Close ends the stream, sending the client status.
+func (c *Conn) Close(status Status) error {
+	var err error
+	c.closeOnce.Do(func() {
+		raw, _ := json.Marshal(status)
+		if err = send(c.ws, StatusChannel, raw); err == nil {
+			err = send(c.ws, StatusChannel, nil)
+		}
+		if c.stdin != nil {
+			c.stdin.Close()
+		}
+		if closeErr := c.ws.close(); err == nil {
+			err = closeErr
+		}
+	})
+	return err
+}
+
+// channelWriter sends what is written to it on a channel.
+type channelWriter struct {
+	ws *wsConn
+	ch Channel
+}
+
+func (w channelWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), chunkSize)
+		if err := send(w.ws, w.ch, p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// send sends data on ch; empty data closes the channel.
+func send(ws *wsConn, ch Channel, data []byte) error {
+	msg := make([]byte, 1+len(data))
+	msg[0] = byte(ch)
+	copy(msg[1:], data)
+	return ws.writeMessage(opBinary, msg)
+}
+
+// NewRequest returns a request opening a stream at urlStr. Send it with an http.Client
+// without a timeout, as streams last as long as the process at the other end, and
+// pass a 101 Switching Protocols response to Open; any other response is an error.
+func NewRequest(urlStr string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString(key[:]))
+	req.Header.Set("Sec-WebSocket-Protocol", Protocol)
+	return req, nil
+}
+
+// Open returns the client's end of the stream that resp, the 101 Switching Protocols
+// response to a request from NewRequest, switched to.
+func Open(resp *http.Response) (*Conn, error) {
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if resp.StatusCode != http.StatusSwitchingProtocols || !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("expected 101 Switching Protocols, got %s", resp.Status)
+	}
+	if resp.Request == nil || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(resp.Request.Header.Get("Sec-WebSocket-Key")) {
+		rwc.Close()
+		return nil, errors.New("the server's WebSocket handshake doesn't match the request")
+	}
+	return &Conn{ws: &wsConn{rwc: rwc, r: bufio.NewReader(rwc), client: true}, done: make(chan struct{})}, nil
+}
+
+// Options are the client's ends of a stream's channels.
+type Options struct {
+	// Stdin, if set, is sent on the stdin channel, which is closed at its end.
+	Stdin io.Reader
+	// Stdout and Stderr, if set, receive what the server sends on those channels.
+	Stdout io.Writer
+	Stderr io.Writer
+	// Resize, if set, delivers the terminal sizes to send, starting with the current
+	// one.
+	Resize <-chan TerminalSize
+}
+
+// Stream runs the client's end of the stream with opts until the server sends the
+// Status, which it returns, and closes the stream. A Stdin that is still being read
+// then is abandoned, and stops being read at its next read.
+func (c *Conn) Stream(opts Options) (Status, error) {
+	defer close(c.done)
+	defer c.ws.close()
+	if opts.Stdin != nil {
+		go func() {
+			if _, err := io.Copy(channelWriter{c.ws, StdinChannel}, opts.Stdin); err == nil {
+				send(c.ws, StdinChannel, nil)
+			}
+		}()
+	}
+	if opts.Resize != nil {
+		go func() {
+			for {
+				select {
+				case <-c.done:
+					return
+				case size, ok := <-opts.Resize:
+					if !ok {
+						return
+					}
+					raw, _ := json.Marshal(size)
+					if send(c.ws, ResizeChannel, raw) != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	var status []byte
+	for {
+		msg, err := c.ws.readMessage()
+		if err != nil {
+			return Status{}, fmt.Errorf("stream ended without a status: %w", err)
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		data := msg[1:]
+		var w io.Writer
+		switch Channel(msg[0]) {
+		case StdoutChannel:
+			w = opts.Stdout
+		case StderrChannel:
+			w = opts.Stderr
+		case StatusChannel:
+			if len(data) > 0 {
+				status = append(status, data...)
+				continue
+			}
+			var s Status
+			if err := json.Unmarshal(status, &s); err != nil {
+				return Status{}, fmt.Errorf("decoding stream status: %w", err)
+			}
+			return s, nil
+		}
+		if w != nil && len(data) > 0 {
+			if _, err := w.Write(data); err != nil {
+				return Status{}, err
+			}
+		}
+	}
+}
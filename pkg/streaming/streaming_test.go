@@ -0,0 +1,65 @@
+package streaming
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	// The server upper-cases its input to stdout, reports the terminal size on stderr,
+	// and exits with the length of the input.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		size := <-conn.Resizes()
+		fmt.Fprintf(conn.Stderr(), "%dx%d\n", size.Width, size.Height)
+		input, _ := io.ReadAll(conn.Stdin())
+		conn.Stdout().Write(bytes.ToUpper(input))
+		conn.Close(Status{ExitCode: len(input)})
+	}))
+	defer server.Close()
+
+	req, err := NewRequest(server.URL)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	conn, err := Open(resp)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	resize := make(chan TerminalSize, 1)
+	resize <- TerminalSize{Width: 80, Height: 24}
+	input := strings.Repeat("hello ", 10000) // More than one message
+	var stdout, stderr bytes.Buffer
+	status, err := conn.Stream(Options{Stdin: strings.NewReader(input), Stdout: &stdout, Stderr: &stderr, Resize: resize})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if status.ExitCode != len(input) || status.Error != "" {
+		t.Errorf("expected exit code %d, got %+v", len(input), status)
+	}
+	if stdout.String() != strings.ToUpper(input) {
+		t.Errorf("expected the input upper-cased on stdout, got %d bytes", stdout.Len())
+	}
+	if stderr.String() != "80x24\n" {
+		t.Errorf("expected the terminal size on stderr, got %q", stderr.String())
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if _, err := Upgrade(rec, httptest.NewRequest(http.MethodGet, "/exec/web", nil)); err == nil || rec.Code != http.StatusBadRequest {
+		t.Errorf("expected a 400 for a request that isn't a WebSocket upgrade, got %d %v", rec.Code, err)
+	}
+}
@@ -0,0 +1,222 @@
+package streaming
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is appended to a handshake's key to compute its accept value (RFC 6455
+// section 1.3).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize bounds the messages either end will read. Streams send their data in
+// chunks of at most a few KiB.
+const maxMessageSize = 1 << 20
+
+// WebSocket opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// errClosed is returned for writes after the close frame.
+var errClosed = errors.New("stream closed")
+
+// wsConn is the WebSocket framing of RFC 6455 over a connection, just enough of it for
+// streams: unfragmented binary messages out, any data messages in, and replies to
+// pings and closes.
+type wsConn struct {
+	rwc    io.ReadWriteCloser
+	r      *bufio.Reader
+	client bool // Clients mask the frames they send; servers don't
+
+	wmu    sync.Mutex
+	closed bool
+}
+
+// writeMessage sends payload as one frame.
+func (ws *wsConn) writeMessage(opcode byte, payload []byte) error {
+	ws.wmu.Lock()
+	defer ws.wmu.Unlock()
+	if ws.closed {
+		return errClosed
+	}
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode // FIN
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	frame := payload
+	if ws.client {
+		header[1] |= 0x80
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header = append(header, mask[:]...)
+		frame = make([]byte, len(payload))
+		for i, b := range payload {
+			frame[i] = b ^ mask[i%4]
+		}
+	}
+	if _, err := ws.rwc.Write(append(header, frame...)); err != nil {
+		return err
+	}
+	if opcode == opClose {
+		ws.closed = true
+	}
+	return nil
+}
+
+// readMessage returns the next data message, answering pings along the way. It
+// returns io.EOF once the other end closes the connection.
+func (ws *wsConn) readMessage() ([]byte, error) {
+	var msg []byte
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := ws.writeMessage(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			ws.writeMessage(opClose, nil)
+			return nil, io.EOF
+		case opText, opBinary, opContinuation:
+			if len(msg)+len(payload) > maxMessageSize {
+				return nil, fmt.Errorf("message larger than %d bytes", maxMessageSize)
+			}
+			msg = append(msg, payload...)
+			if fin {
+				return msg, nil
+			}
+		default:
+			return nil, fmt.Errorf("unknown WebSocket opcode %#x", opcode)
+		}
+	}
+}
+
+func (ws *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(ws.r, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = head[0]&0x80 != 0, head[0]&0x0f
+	masked := head[1]&0x80 != 0
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxMessageSize {
+		return false, 0, nil, fmt.Errorf("frame larger than %d bytes", maxMessageSize)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(ws.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// close sends a close frame, if none was sent, and closes the connection.
+func (ws *wsConn) close() error {
+	ws.writeMessage(opClose, nil)
+	return ws.rwc.Close()
+}
+
+// acceptKey is the Sec-WebSocket-Accept value answering the Sec-WebSocket-Key key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains reports whether the comma-separated header h lists token.
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upgrade completes the server side of a WebSocket handshake, answering a request
+// that isn't one with 400.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		writeError(w, http.StatusBadRequest, "This endpoint streams over a WebSocket; send a WebSocket upgrade request")
+		return nil, errors.New("not a WebSocket upgrade request")
+	}
+	if !headerContains(r.Header, "Sec-WebSocket-Protocol", Protocol) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Unsupported stream protocol; this endpoint speaks %s", Protocol))
+		return nil, errors.New("unsupported stream protocol")
+	}
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to take over the connection: "+err.Error())
+		return nil, err
+	}
+	fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\nSec-WebSocket-Protocol: %s\r\n\r\n", acceptKey(key), Protocol)
+	if err := brw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{rwc: conn, r: brw.Reader}, nil
+}
+
+// writeError answers with an error body like the API server's, which api.Client
+// turns into a *api.StatusError.
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
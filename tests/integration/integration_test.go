@@ -1,5 +1,6 @@
 // Package integration provides end-to-end integration tests for k8s-lite-go.
-// These tests start the actual binaries and verify the full pod lifecycle.
+// These tests run the API server, scheduler, controller manager, and a kubelet
+// in-process and verify the full pod lifecycle through the REST API.
 package integration
 
 import (
@@ -10,29 +11,39 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"syscall"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/Ayobami-00/k8s-lite-go/pkg/api"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/apiserver"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/clock"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/controllermanager"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/kubelet"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/record"
+	"github.com/Ayobami-00/k8s-lite-go/pkg/scheduler"
+	"github.com/gin-gonic/gin"
 )
 
 const (
 	testTimeout     = 60 * time.Second
 	startupTimeout  = 10 * time.Second
 	shutdownTimeout = 5 * time.Second
+
+	// The components poll much faster than their defaults so tests don't wait on them.
+	syncInterval = 100 * time.Millisecond
 )
 
 // TestCluster represents a running test cluster with all components.
 type TestCluster struct {
-	t             *testing.T
-	binDir        string
-	apiServerCmd  *exec.Cmd
-	schedulerCmd  *exec.Cmd
-	kubeletCmd    *exec.Cmd
-	apiServerURL  string
-	apiServerPort string
+	// Clock drives every component's loops. NewTestCluster sets the real clock; set a
+	// clock.FakeClock before Start to step time by hand.
+	Clock clock.Clock
+
+	t            *testing.T
+	apiServerURL string
+	cancel       context.CancelFunc
+	done         sync.WaitGroup
 }
 
 // Pod represents the pod structure for API responses.
@@ -51,123 +62,63 @@ type Node struct {
 	Status  string `json:"status"`
 }
 
-// findProjectRoot finds the project root by looking for go.mod.
-func findProjectRoot() (string, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
-			return dir, nil
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return "", fmt.Errorf("could not find project root (go.mod)")
-		}
-		dir = parent
-	}
-}
-
-// findFreePort finds an available port for testing.
-func findFreePort() (string, error) {
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		return "", err
-	}
-	defer listener.Close()
-	addr := listener.Addr().(*net.TCPAddr)
-	return fmt.Sprintf("%d", addr.Port), nil
-}
-
-// NewTestCluster creates and starts a new test cluster.
+// NewTestCluster creates a new test cluster. Start runs it.
 func NewTestCluster(t *testing.T) *TestCluster {
 	t.Helper()
-
-	projectRoot, err := findProjectRoot()
-	if err != nil {
-		t.Fatalf("Failed to find project root: %v", err)
-	}
-
-	binDir := filepath.Join(projectRoot, "bin")
-
-	// Verify binaries exist
-	binaries := []string{"apiserver", "scheduler", "kubelet"}
-	for _, bin := range binaries {
-		binPath := filepath.Join(binDir, bin)
-		if _, err := os.Stat(binPath); os.IsNotExist(err) {
-			t.Fatalf("Binary %s not found. Run 'make build' first.", binPath)
-		}
-	}
-
-	// Find a free port for the API server
-	port, err := findFreePort()
-	if err != nil {
-		t.Fatalf("Failed to find free port: %v", err)
-	}
-
-	tc := &TestCluster{
-		t:             t,
-		binDir:        binDir,
-		apiServerPort: port,
-		apiServerURL:  fmt.Sprintf("http://localhost:%s", port),
-	}
-
-	return tc
+	gin.SetMode(gin.TestMode)
+	return &TestCluster{t: t, Clock: clock.RealClock{}}
 }
 
-// Start starts all cluster components.
+// Start starts all cluster components in this process. The API server listens on an
+// ephemeral port, so clusters of concurrent tests never collide.
 func (tc *TestCluster) Start(ctx context.Context) error {
 	tc.t.Helper()
 
-	// Start API server
-	tc.apiServerCmd = exec.CommandContext(ctx, filepath.Join(tc.binDir, "apiserver"))
-	tc.apiServerCmd.Env = append(os.Environ(), fmt.Sprintf("PORT=%s", tc.apiServerPort))
-	// The apiserver uses hardcoded port 8080, so we need to modify the binary or use the default
-	// For now, we'll use the default port and hope it's available
-	tc.apiServerPort = "8080"
-	tc.apiServerURL = "http://localhost:8080"
-	tc.apiServerCmd = exec.CommandContext(ctx, filepath.Join(tc.binDir, "apiserver"))
-	tc.apiServerCmd.Stdout = os.Stdout
-	tc.apiServerCmd.Stderr = os.Stderr
-
-	if err := tc.apiServerCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start apiserver: %w", err)
+	dataStore, err := apiserver.NewStore()
+	if err != nil {
+		return err
 	}
-	tc.t.Logf("Started API server (PID: %d)", tc.apiServerCmd.Process.Pid)
-
-	// Wait for API server to be ready
-	if err := tc.waitForAPIServer(ctx); err != nil {
-		tc.Stop()
-		return fmt.Errorf("API server failed to become ready: %w", err)
+	podIPs, err := apiserver.NewPodIPAllocator("10.244.0.0/16")
+	if err != nil {
+		return err
 	}
-
-	// Start scheduler
-	tc.schedulerCmd = exec.CommandContext(ctx, filepath.Join(tc.binDir, "scheduler"),
-		"--apiserver="+tc.apiServerURL)
-	tc.schedulerCmd.Stdout = os.Stdout
-	tc.schedulerCmd.Stderr = os.Stderr
-
-	if err := tc.schedulerCmd.Start(); err != nil {
-		tc.Stop()
-		return fmt.Errorf("failed to start scheduler: %w", err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	tc.apiServerURL = "http://" + ln.Addr().String()
+	client, err := api.NewClient(tc.apiServerURL)
+	if err != nil {
+		ln.Close()
+		return err
 	}
-	tc.t.Logf("Started scheduler (PID: %d)", tc.schedulerCmd.Process.Pid)
-
-	// Start kubelet
-	tc.kubeletCmd = exec.CommandContext(ctx, filepath.Join(tc.binDir, "kubelet"),
-		"--name=test-node",
-		"--address=localhost:10250",
-		"--apiserver="+tc.apiServerURL)
-	tc.kubeletCmd.Stdout = os.Stdout
-	tc.kubeletCmd.Stderr = os.Stderr
 
-	if err := tc.kubeletCmd.Start(); err != nil {
-		tc.Stop()
-		return fmt.Errorf("failed to start kubelet: %w", err)
+	ctx, tc.cancel = context.WithCancel(ctx)
+	run := func(name string, fn func(ctx context.Context) error) {
+		tc.done.Add(1)
+		go func() {
+			defer tc.done.Done()
+			if err := fn(ctx); err != nil {
+				tc.t.Logf("%s stopped: %v", name, err)
+			}
+		}()
 	}
-	tc.t.Logf("Started kubelet (PID: %d)", tc.kubeletCmd.Process.Pid)
+
+	run("apiserver", func(ctx context.Context) error {
+		return apiserver.NewAPIServer(dataStore, podIPs).Serve(ctx, ln)
+	})
+	tc.t.Logf("Started API server at %s", tc.apiServerURL)
+	run("scheduler", func(ctx context.Context) error {
+		recorder := record.NewRecorder(client, api.EventSource{Component: "scheduler"})
+		scheduler.NewScheduler(client, recorder, syncInterval, tc.Clock).Run(ctx)
+		return nil
+	})
+	run("controller-manager", func(ctx context.Context) error {
+		controllermanager.Run(ctx, client, controllermanager.Options{SyncInterval: syncInterval, Workers: 1, Clock: tc.Clock})
+		return nil
+	})
+	k := kubelet.NewKubelet(client, "test-node", "localhost:10250", tc.t.TempDir(), syncInterval, kubelet.ImagePullOptions{BackOff: time.Second}, tc.Clock)
+	run("kubelet", k.Run)
 
 	// Wait for node to register
 	if err := tc.waitForNode(ctx, "test-node"); err != nil {
@@ -178,64 +129,25 @@ func (tc *TestCluster) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop stops all cluster components gracefully.
+// Stop stops all cluster components and waits for them to return.
 func (tc *TestCluster) Stop() {
 	tc.t.Helper()
-
-	stopProcess := func(name string, cmd *exec.Cmd) {
-		if cmd == nil || cmd.Process == nil {
-			return
-		}
-		tc.t.Logf("Stopping %s (PID: %d)", name, cmd.Process.Pid)
-
-		// Send SIGTERM for graceful shutdown
-		if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
-			tc.t.Logf("Failed to send SIGTERM to %s: %v", name, err)
-		}
-
-		// Wait with timeout
-		done := make(chan error, 1)
-		go func() {
-			done <- cmd.Wait()
-		}()
-
-		select {
-		case <-done:
-			tc.t.Logf("%s stopped", name)
-		case <-time.After(shutdownTimeout):
-			tc.t.Logf("%s did not stop gracefully, killing", name)
-			_ = cmd.Process.Kill()
-		}
-	}
-
-	stopProcess("kubelet", tc.kubeletCmd)
-	stopProcess("scheduler", tc.schedulerCmd)
-	stopProcess("apiserver", tc.apiServerCmd)
-}
-
-// waitForAPIServer waits for the API server to be ready.
-func (tc *TestCluster) waitForAPIServer(ctx context.Context) error {
-	deadline := time.Now().Add(startupTimeout)
-	client := &http.Client{Timeout: 1 * time.Second}
-
-	for time.Now().Before(deadline) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		resp, err := client.Get(tc.apiServerURL + "/api/v1/nodes")
-		if err == nil {
-			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
-				tc.t.Log("API server is ready")
-				return nil
-			}
-		}
-		time.Sleep(100 * time.Millisecond)
+	if tc.cancel == nil {
+		return
+	}
+	tc.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		tc.done.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		tc.t.Log("Cluster stopped")
+	case <-time.After(shutdownTimeout):
+		tc.t.Log("Cluster components did not stop in time")
 	}
-	return fmt.Errorf("timeout waiting for API server")
 }
 
 // waitForNode waits for a node to be registered and ready.
@@ -368,6 +280,21 @@ func (tc *TestCluster) DeletePod(namespace, name string) error {
 	return nil
 }
 
+// podPhaseOrder ranks the phases a pod moves through on its way to running.
+var podPhaseOrder = map[string]int{"Pending": 0, "Scheduled": 1, "Running": 2}
+
+// reachedPhase reports whether a pod in phase current has reached phase, either exactly
+// or by moving on past it. The components sync quickly, so a pod can go from Scheduled
+// to Running between two polls.
+func reachedPhase(current, phase string) bool {
+	if current == phase {
+		return true
+	}
+	c, ok1 := podPhaseOrder[current]
+	p, ok2 := podPhaseOrder[phase]
+	return ok1 && ok2 && c > p
+}
+
 // WaitForPodPhase waits for a pod to reach a specific phase.
 func (tc *TestCluster) WaitForPodPhase(namespace, name, phase string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
@@ -379,7 +306,7 @@ func (tc *TestCluster) WaitForPodPhase(namespace, name, phase string, timeout ti
 			continue
 		}
 
-		if pod.Phase == phase {
+		if reachedPhase(pod.Phase, phase) {
 			return nil
 		}
 		time.Sleep(100 * time.Millisecond)
@@ -530,6 +457,32 @@ func TestMultiplePods(t *testing.T) {
 	}
 }
 
+// TestPodStartsWithoutWaitingForSyncs checks that the scheduler and the kubelet act on
+// a new pod as soon as their watches report it: the cluster's clock never moves, so
+// their periodic passes and syncs never come round again after the first.
+func TestPodStartsWithoutWaitingForSyncs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	cluster := NewTestCluster(t)
+	cluster.Clock = clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := cluster.Start(ctx); err != nil {
+		t.Fatalf("Failed to start cluster: %v", err)
+	}
+	defer cluster.Stop()
+
+	if _, err := cluster.CreatePod("default", "watched", "nginx:1.25"); err != nil {
+		t.Fatalf("Failed to create pod: %v", err)
+	}
+	if err := cluster.WaitForPodPhase("default", "watched", "Running", 5*time.Second); err != nil {
+		t.Fatalf("Pod did not start from watch events alone: %v", err)
+	}
+}
+
 // TestDuplicatePodCreation tests that creating a duplicate pod fails.
 func TestDuplicatePodCreation(t *testing.T) {
 	if testing.Short() {